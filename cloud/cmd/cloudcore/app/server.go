@@ -51,6 +51,7 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/dynamiccontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/policycontroller"
+	policycontrollerconfig "github.com/kubeedge/kubeedge/cloud/pkg/policycontroller/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/router"
 	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
 	"github.com/kubeedge/kubeedge/cloud/pkg/taskmanager"
@@ -58,6 +59,7 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/features"
 	"github.com/kubeedge/kubeedge/pkg/util"
 	"github.com/kubeedge/kubeedge/pkg/util/flag"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
 	"github.com/kubeedge/kubeedge/pkg/version"
 )
 
@@ -94,6 +96,10 @@ kubernetes controller which manages devices so that the device metadata/status d
 			// start monitor server
 			go monitor.ServeMonitor(config.CommonConfig.MonitorServer)
 
+			if trace := config.CommonConfig.MessageTrace; trace != nil && trace.Enable {
+				msgtrace.SetSampleRate(trace.SampleRate)
+			}
+
 			// To help debugging, immediately log version
 			klog.Infof("Version: %+v", version.Get())
 			enableImpersonation := config.Modules.CloudHub.Authorization != nil &&
@@ -136,6 +142,8 @@ kubernetes controller which manages devices so that the device metadata/status d
 			core.StartModules()
 			gis.Start(ctx.Done())
 			core.GracefulShutdown()
+			// the edge nodes reconnect gradually instead of all at once
+			cloudhub.WaitForDrain()
 		},
 	}
 	fs := cmd.Flags()
@@ -175,6 +183,7 @@ func registerModules(c *v1alpha1.CloudCoreConfig) {
 	cloudstream.Register(c.Modules.CloudStream, c.CommonConfig)
 	router.Register(c.Modules.Router)
 	dynamiccontroller.Register(c.Modules.DynamicController, enableAuthorization)
+	policycontrollerconfig.InitConfigure(c.Modules.PolicyController)
 	policycontroller.Register(client.CrdConfig)
 }
 
@@ -19,15 +19,12 @@ package authorization
 import (
 	"context"
 	"crypto/tls"
-	stdx509 "crypto/x509"
-	"encoding/pem"
 	"fmt"
 	"net/http"
 
 	"k8s.io/apiserver/pkg/authentication/request/x509"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/endpoints/request"
-	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/cmd/kubeadm/app/constants"
 
@@ -116,8 +113,11 @@ func (r *cloudhubAuthorizer) authenticateConnection(connection conn.Connection)
 
 	options := x509.DefaultVerifyOptions()
 	// ca cloud be available util CloudHub starts
-	options.Roots = stdx509.NewCertPool()
-	options.Roots.AppendCertsFromPEM(pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: hubconfig.Config.Ca}))
+	roots, err := hubconfig.Config.CAPool()
+	if err != nil {
+		return fmt.Errorf("node %q: unable to load the CA certificates: %v", nodeID, err)
+	}
+	options.Roots = roots
 
 	authenticator := x509.New(options, x509.CommonNameUserConversion)
 	resp, ok, err := authenticator.AuthenticateRequest(&http.Request{TLS: &tls.ConnectionState{PeerCertificates: peerCerts}})
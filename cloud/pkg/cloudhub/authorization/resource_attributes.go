@@ -77,7 +77,7 @@ func getAuthorizerAttributes(router beehivemodel.MessageRoute, hubInfo cloudhubm
 func isKubeedgeResourceMessage(router beehivemodel.MessageRoute) bool {
 	switch router.Operation {
 	case beehivemodel.ResponseOperation, beehivemodel.ResponseErrorOperation, beehivemodel.UploadOperation,
-		taskutil.TaskPrePull, taskutil.TaskUpgrade, cloudhubmodel.OpKeepalive:
+		taskutil.TaskPrePull, taskutil.TaskUpgrade, cloudhubmodel.OpKeepalive, cloudhubmodel.OpReliableAck:
 		return true
 	}
 	switch router.Source {
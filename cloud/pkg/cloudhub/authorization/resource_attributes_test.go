@@ -114,6 +114,11 @@ func TestIsKubeedgeResourceMessage(t *testing.T) {
 			router: model.MessageRoute{Operation: cloudhubmodel.OpKeepalive},
 			result: true,
 		},
+		{
+			name:   "reliable ack message",
+			router: model.MessageRoute{Operation: cloudhubmodel.OpReliableAck},
+			result: true,
+		},
 		{
 			name:   "device twin message",
 			router: model.MessageRoute{Source: cloudhubmodel.ResTwin},
@@ -1,10 +1,14 @@
 package cloudhub
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"sync/atomic"
+	"time"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubeapiserver/authorizer/modes"
@@ -16,18 +20,37 @@ import (
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/dispatcher"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/handler"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/registry"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/certreload"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certificate"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certinfo"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/issuancelog"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/ocsp"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/udsserver"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/informers"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/features"
 )
 
 var DoneTLSTunnelCerts = make(chan bool, 1)
 var sessionMgr *session.Manager
 
+var (
+	// draining is set when the sessions are to be drained after cloudHub stops
+	draining atomic.Bool
+	// drained is closed when the sessions are drained
+	drained = make(chan struct{})
+)
+
+// drainGracePeriod is the time waited for the drain beyond its timeout
+const drainGracePeriod = 5 * time.Second
+
 type cloudHub struct {
 	enable               bool
 	informersSyncedFuncs []cache.InformerSynced
@@ -61,6 +84,7 @@ func newCloudHub(enable bool) *cloudHub {
 		sessionManager, client.GetCRDClient(),
 		messageDispatcher, authorizer)
 	sessionMgr = sessionManager
+	certificate.SetSessionStore(sessionManager)
 
 	ch := &cloudHub{
 		enable:         enable,
@@ -70,6 +94,19 @@ func newCloudHub(enable bool) *cloudHub {
 
 	ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, clusterObjectSyncInformer.Informer().HasSynced)
 	ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, objectSyncInformer.Informer().HasSynced)
+	if hubconfig.Config.IssuanceQuota != nil || len(hubconfig.Config.Issuers) > 0 {
+		// the tenants of the issuance quota and the issuers can be matched by the node group of the node
+		nodeInformer := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes()
+		certificate.SetNodeLister(nodeInformer.Lister())
+		ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, nodeInformer.Informer().HasSynced)
+	}
+	if a := hubconfig.Config.NodeAdmission; a != nil && a.Enable {
+		nodeInformer := informers.GetInformersManager().GetKubeInformerFactory().Core().V1().Nodes()
+		if err := sessionManager.EnableNodeAdmission(a, nodeInformer.Lister()); err != nil {
+			panic(fmt.Sprintf("unable to enable the node admission for CloudHub: %v", err))
+		}
+		ch.informersSyncedFuncs = append(ch.informersSyncedFuncs, nodeInformer.Informer().HasSynced)
+	}
 
 	return ch
 }
@@ -99,6 +136,20 @@ func (ch *cloudHub) Start() {
 	}
 	ctx := beehiveContext.GetContext()
 
+	if features.DefaultFeatureGate.Enabled(features.CloudHubSessionRegistry) {
+		// the registry must be ready before the downstream messages are dispatched
+		// and the edge nodes are connected
+		reg, err := registry.NewLeaseRegistry(client.GetKubeClient(), hubconfig.Config.SessionRegistry)
+		if err != nil {
+			klog.Exit(err)
+		}
+		if err := reg.Start(ctx); err != nil {
+			klog.Exit(err)
+		}
+		sessionMgr.SetRegistry(reg)
+		registry.SetDeliverFunc(ch.dispatcher.DeliverLocal)
+	}
+
 	// start dispatch message from the cloud to edge node
 	go ch.dispatcher.DispatchDownstream()
 
@@ -107,10 +158,59 @@ func (ch *cloudHub) Start() {
 	if err := httpserver.PrepareAllCerts(ctx); err != nil {
 		klog.Exit(err)
 	}
+	if err := certificate.InitIssuers(); err != nil {
+		klog.Exit(err)
+	}
+	if err := certreload.Init(ctx); err != nil {
+		klog.Exit(err)
+	}
+	if err := certificate.CheckSignaturePolicy(); err != nil {
+		klog.Exit(err)
+	}
+	if err := certificate.CheckCSRSubjectPolicy(); err != nil {
+		klog.Exit(err)
+	}
+	if err := certificate.CheckCertExtensions(); err != nil {
+		klog.Exit(err)
+	}
 	// TODO: Will improve in the future
 	DoneTLSTunnelCerts <- true
 	close(DoneTLSTunnelCerts)
 
+	if l := hubconfig.Config.IssuanceLog; l != nil && l.Enable {
+		if err := issuancelog.Init(ctx, l.Path); err != nil {
+			klog.Exit(err)
+		}
+		issuancelog.StartPruning(ctx, l.PruneInterval*time.Hour)
+	}
+	certificate.InitIssuanceQuota()
+	certificate.InitSigningRateLimit(client.GetKubeClient())
+	certificate.InitNodeApproval()
+	if err := certificate.InitSigningFreeze(ctx, client.GetKubeClient()); err != nil {
+		klog.Exit(err)
+	}
+	if err := certificate.InitSigningWebhook(); err != nil {
+		klog.Exit(err)
+	}
+	if r := hubconfig.Config.Revocation; r != nil {
+		if r.Persist {
+			if err := revocation.DefaultList.Persist(ctx, client.GetKubeClient(), constants.SystemNamespace, r.ConfigMapName); err != nil {
+				klog.Exit(err)
+			}
+		}
+		go wait.UntilWithContext(ctx, func(context.Context) {
+			sessionMgr.CloseRevokedSessions(revocation.DefaultList.IsRevoked)
+		}, time.Duration(r.SessionCheckInterval)*time.Second)
+	}
+	ocspIssuers, err := certificate.OCSPIssuers()
+	if err != nil {
+		klog.Exit(err)
+	}
+	if err := ocsp.Init(ocspIssuers...); err != nil {
+		klog.Exit(err)
+	}
+	certinfo.StartExpiryScan(ctx, client.GetKubeClient(), hubconfig.Config.CertExpiryScan)
+
 	// generate Token
 	if err := httpserver.GenerateAndRefreshToken(ctx); err != nil {
 		klog.Exit(err)
@@ -118,13 +218,22 @@ func (ch *cloudHub) Start() {
 
 	// HttpServer mainly used to issue certificates for the edge
 	go func() {
-		if err := httpserver.StartHTTPServer(); err != nil {
+		if err := httpserver.StartHTTPServer(ctx); err != nil {
 			klog.Exit(err)
 		}
 	}()
 
 	servers.StartCloudHub(ch.messageHandler)
 
+	if d := hubconfig.Config.SessionDrain; d != nil && d.Enable {
+		draining.Store(true)
+		go func() {
+			defer close(drained)
+			<-ctx.Done()
+			sessionMgr.Drain(time.Duration(d.Timeout)*time.Second, time.Duration(d.ReconnectWindow)*time.Second)
+		}()
+	}
+
 	if hubconfig.Config.UnixSocket.Enable {
 		// The uds server is only used to communicate with csi driver from kubeedge on cloud.
 		// It is not used to communicate between cloud and edge.
@@ -132,6 +241,19 @@ func (ch *cloudHub) Start() {
 	}
 }
 
+// WaitForDrain waits for the sessions of the edge nodes to be drained after cloudHub stops,
+// it returns at once if the drain is disabled or cloudHub is not started.
+func WaitForDrain() {
+	if !draining.Load() {
+		return
+	}
+	select {
+	case <-drained:
+	case <-time.After(time.Duration(hubconfig.Config.SessionDrain.Timeout)*time.Second + drainGracePeriod):
+		klog.Warning("timed out waiting for the sessions of the edge nodes to be drained")
+	}
+}
+
 func getAuthConfig() authorization.Config {
 	enabled := hubconfig.Config.Authorization != nil && hubconfig.Config.Authorization.Enable
 	debug := enabled && hubconfig.Config.Authorization.Debug
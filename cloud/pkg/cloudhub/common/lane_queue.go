@@ -0,0 +1,319 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+// Lane is the priority lane of the downstream messages to an edge node,
+// the messages in the lanes of higher priority are sent first
+type Lane int
+
+const (
+	// LaneCritical carries the deletes, the node changes such as the drain and the
+	// taints, and the certificate, token and hub control messages
+	LaneCritical Lane = iota
+	// LaneBulk carries the other messages, mostly the resource sync
+	LaneBulk
+
+	laneCount
+)
+
+func (l Lane) String() string {
+	if l == LaneCritical {
+		return "critical"
+	}
+	return "bulk"
+}
+
+// criticalResourceTypes are the resource types whose messages are sent in the critical lane
+var criticalResourceTypes = sets.NewString(
+	beehivemodel.ResourceTypeNode,
+	beehivemodel.ResourceTypeNodePatch,
+	beehivemodel.ResourceTypeCSR,
+	beehivemodel.ResourceTypeServiceAccountToken,
+)
+
+var (
+	laneOverrideLock sync.RWMutex
+	laneOverride     func(msg *beehivemodel.Message) (Lane, bool)
+)
+
+// SetLaneOverride sets the hook overriding the lanes of the downstream messages,
+// the messages for which it returns false are classified by their route.
+func SetLaneOverride(f func(msg *beehivemodel.Message) (Lane, bool)) {
+	laneOverrideLock.Lock()
+	defer laneOverrideLock.Unlock()
+	laneOverride = f
+}
+
+// MessageLane returns the lane of the downstream message
+func MessageLane(msg *beehivemodel.Message) Lane {
+	laneOverrideLock.RLock()
+	override := laneOverride
+	laneOverrideLock.RUnlock()
+	if override != nil {
+		if lane, ok := override(msg); ok {
+			return lane
+		}
+	}
+
+	if msg.GetGroup() == model.GpHub || msg.GetOperation() == beehivemodel.DeleteOperation {
+		return LaneCritical
+	}
+	if resourceType, err := messagelayer.GetResourceType(*msg); err == nil && criticalResourceTypes.Has(resourceType) {
+		return LaneCritical
+	}
+	if deletionTimestamp, err := GetMessageDeletionTimestamp(msg); err == nil && deletionTimestamp != nil {
+		return LaneCritical
+	}
+	return LaneBulk
+}
+
+type laneItem struct {
+	lane     Lane
+	resource string
+}
+
+// laneQueue is the queue of the message keys of an edge node which hands out the keys of the
+// critical messages before those of the bulk messages, a bulk key is handed out after criticalBurst
+// critical keys in a row so that the bulk messages still progress. As the workqueue, a key is
+// queued once however many times it is added, and a key added while it's being processed is
+// queued again when it's done.
+type laneQueue struct {
+	cond *sync.Cond
+	// lanes holds the queued keys of each lane in order
+	lanes [laneCount][]interface{}
+	// dirty records the lane and the resource of the keys to be processed
+	dirty map[interface{}]laneItem
+	// processing records the keys being processed
+	processing map[interface{}]struct{}
+	// burst counts the critical keys handed out in a row while bulk keys are waiting
+	burst         int
+	criticalBurst int
+	shuttingDown  bool
+	// classify returns the lane and the resource of the message of the key
+	classify    func(key interface{}) (Lane, string)
+	rateLimiter workqueue.RateLimiter
+	nodeID      string
+}
+
+var _ workqueue.RateLimitingInterface = &laneQueue{}
+
+func newLaneQueue(nodeID string, criticalBurst int, classify func(key interface{}) (Lane, string)) *laneQueue {
+	return &laneQueue{
+		cond:          sync.NewCond(&sync.Mutex{}),
+		dirty:         map[interface{}]laneItem{},
+		processing:    map[interface{}]struct{}{},
+		criticalBurst: criticalBurst,
+		classify:      classify,
+		rateLimiter:   workqueue.DefaultControllerRateLimiter(),
+		nodeID:        nodeID,
+	}
+}
+
+func (q *laneQueue) Add(key interface{}) {
+	lane, resource := q.classify(key)
+
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+
+	if item, ok := q.dirty[key]; ok {
+		// the key is promoted if its message becomes critical
+		if lane < item.lane {
+			q.dirty[key] = laneItem{lane: lane, resource: resource}
+			if _, processing := q.processing[key]; !processing {
+				q.remove(item.lane, key)
+				q.push(key, lane, resource)
+			}
+		}
+		return
+	}
+
+	q.dirty[key] = laneItem{lane: lane, resource: resource}
+	if _, processing := q.processing[key]; processing {
+		return
+	}
+	q.push(key, lane, resource)
+	q.cond.Signal()
+}
+
+// push queues the key in the lane, the keys of the same resource queued in the lanes of lower
+// priority are moved ahead of it so that the messages of a resource are sent in order
+func (q *laneQueue) push(key interface{}, lane Lane, resource string) {
+	if resource != "" {
+		for l := lane + 1; l < laneCount; l++ {
+			kept := q.lanes[l][:0]
+			for _, k := range q.lanes[l] {
+				if q.dirty[k].resource != resource {
+					kept = append(kept, k)
+					continue
+				}
+				q.dirty[k] = laneItem{lane: lane, resource: resource}
+				q.lanes[lane] = append(q.lanes[lane], k)
+				q.depth(l).Dec()
+				q.depth(lane).Inc()
+			}
+			clear(q.lanes[l][len(kept):])
+			q.lanes[l] = kept
+		}
+	}
+	q.lanes[lane] = append(q.lanes[lane], key)
+	q.depth(lane).Inc()
+}
+
+func (q *laneQueue) remove(lane Lane, key interface{}) {
+	for i, k := range q.lanes[lane] {
+		if k == key {
+			q.lanes[lane] = append(q.lanes[lane][:i], q.lanes[lane][i+1:]...)
+			q.depth(lane).Dec()
+			return
+		}
+	}
+}
+
+func (q *laneQueue) depth(lane Lane) prometheus.Gauge {
+	return monitor.NodeQueueDepth.WithLabelValues(monitor.NodeLabel(q.nodeID), lane.String())
+}
+
+func (q *laneQueue) len() int {
+	n := 0
+	for _, keys := range q.lanes {
+		n += len(keys)
+	}
+	return n
+}
+
+func (q *laneQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.len()
+}
+
+func (q *laneQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.len() == 0 {
+		return nil, true
+	}
+
+	lane := q.next()
+	key := q.lanes[lane][0]
+	q.lanes[lane][0] = nil
+	q.lanes[lane] = q.lanes[lane][1:]
+	q.depth(lane).Dec()
+
+	q.processing[key] = struct{}{}
+	delete(q.dirty, key)
+	return key, false
+}
+
+// next returns the lane to hand out a key from, the bulk lane is served
+// after criticalBurst critical keys in a row
+func (q *laneQueue) next() Lane {
+	switch {
+	case len(q.lanes[LaneCritical]) == 0:
+		q.burst = 0
+		return LaneBulk
+	case len(q.lanes[LaneBulk]) == 0:
+		q.burst = 0
+		return LaneCritical
+	case q.burst >= q.criticalBurst:
+		q.burst = 0
+		return LaneBulk
+	default:
+		q.burst++
+		return LaneCritical
+	}
+}
+
+func (q *laneQueue) Done(key interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.processing, key)
+	if item, ok := q.dirty[key]; ok && !q.shuttingDown {
+		q.push(key, item.lane, item.resource)
+		q.cond.Signal()
+	} else if len(q.processing) == 0 {
+		q.cond.Broadcast()
+	}
+}
+
+// ShutDown stops the queue, the queued keys are dropped
+func (q *laneQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.shuttingDown = true
+	for lane, keys := range q.lanes {
+		q.depth(Lane(lane)).Sub(float64(len(keys)))
+		q.lanes[lane] = nil
+	}
+	q.cond.Broadcast()
+}
+
+// ShutDownWithDrain stops the queue and waits for the keys being processed to be done
+func (q *laneQueue) ShutDownWithDrain() {
+	q.ShutDown()
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for len(q.processing) > 0 {
+		q.cond.Wait()
+	}
+}
+
+func (q *laneQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+func (q *laneQueue) AddAfter(key interface{}, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(key)
+		return
+	}
+	time.AfterFunc(duration, func() { q.Add(key) })
+}
+
+func (q *laneQueue) AddRateLimited(key interface{}) {
+	q.AddAfter(key, q.rateLimiter.When(key))
+}
+
+func (q *laneQueue) Forget(key interface{}) {
+	q.rateLimiter.Forget(key)
+}
+
+func (q *laneQueue) NumRequeues(key interface{}) int {
+	return q.rateLimiter.NumRequeues(key)
+}
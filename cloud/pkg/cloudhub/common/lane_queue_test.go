@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	edgecon "github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
+)
+
+func newLaneMessage(resourceType, name, operation string) *beehivemodel.Message {
+	return beehivemodel.NewMessage("").
+		BuildRouter("edgecontroller", edgecon.GroupResource, "node/test-node/default/"+resourceType+"/"+name, operation).
+		FillBody(&TestMessageObj{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID("uid-" + name)}})
+}
+
+func TestMessageLane(t *testing.T) {
+	deleting := newLaneMessage(beehivemodel.ResourceTypePod, "deleting", beehivemodel.UpdateOperation)
+	now := metav1.Now()
+	deleting.Content.(*TestMessageObj).DeletionTimestamp = &now
+
+	cases := []struct {
+		name string
+		msg  *beehivemodel.Message
+		lane Lane
+	}{
+		{"pod update", newLaneMessage(beehivemodel.ResourceTypePod, "p", beehivemodel.UpdateOperation), LaneBulk},
+		{"configmap insert", newLaneMessage(beehivemodel.ResourceTypeConfigmap, "c", beehivemodel.InsertOperation), LaneBulk},
+		{"pod delete", newLaneMessage(beehivemodel.ResourceTypePod, "p", beehivemodel.DeleteOperation), LaneCritical},
+		{"pod being deleted", deleting, LaneCritical},
+		{"node update", newLaneMessage(beehivemodel.ResourceTypeNode, "n", beehivemodel.UpdateOperation), LaneCritical},
+		{"token response", newLaneMessage(beehivemodel.ResourceTypeServiceAccountToken, "t", beehivemodel.ResponseOperation), LaneCritical},
+		{"hub control", beehivemodel.NewMessage("").BuildRouter("cloudhub", model.GpHub, "node/test-node", model.OpReconnect), LaneCritical},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.lane, MessageLane(c.msg), c.name)
+	}
+
+	SetLaneOverride(func(msg *beehivemodel.Message) (Lane, bool) {
+		if msg.GetOperation() == beehivemodel.DeleteOperation {
+			return LaneBulk, true
+		}
+		return LaneCritical, false
+	})
+	defer SetLaneOverride(nil)
+	assert.Equal(t, LaneBulk, MessageLane(cases[2].msg), "overridden pod delete")
+	assert.Equal(t, LaneCritical, MessageLane(cases[4].msg), "node update not overridden")
+}
+
+// newTestLaneQueue returns a lane queue whose keys are "<lane>/<resource>/<id>"
+func newTestLaneQueue(nodeID string, criticalBurst int) *laneQueue {
+	return newLaneQueue(nodeID, criticalBurst, func(key interface{}) (Lane, string) {
+		k := key.(string)
+		lane := LaneBulk
+		if k[0] == 'c' {
+			lane = LaneCritical
+		}
+		return lane, k[2:3]
+	})
+}
+
+func getKeys(q *laneQueue, n int) []string {
+	var keys []string
+	for i := 0; i < n; i++ {
+		key, _ := q.Get()
+		q.Done(key)
+		keys = append(keys, key.(string))
+	}
+	return keys
+}
+
+func TestLaneQueueStarvation(t *testing.T) {
+	q := newTestLaneQueue("lane-node", 2)
+	defer q.ShutDown()
+	for _, key := range []string{"b/a/1", "b/b/2", "b/c/3", "c/d/1", "c/e/2", "c/f/3", "c/g/4"} {
+		q.Add(key)
+	}
+
+	assert.Equal(t, 7, q.Len())
+	assert.Equal(t, []string{"c/d/1", "c/e/2", "b/a/1", "c/f/3", "c/g/4", "b/b/2", "b/c/3"}, getKeys(q, 7))
+}
+
+func TestLaneQueueResourceOrder(t *testing.T) {
+	q := newTestLaneQueue("lane-node", 10)
+	defer q.ShutDown()
+	// the update of resource x is queued before its delete
+	q.Add("b/y/1")
+	q.Add("b/x/2")
+	q.Add("b/z/3")
+	q.Add("c/x/4")
+
+	assert.Equal(t, []string{"b/x/2", "c/x/4", "b/y/1", "b/z/3"}, getKeys(q, 4))
+}
+
+func TestLaneQueueDedupAndRequeue(t *testing.T) {
+	lanes := map[string]Lane{"k1": LaneBulk, "k2": LaneBulk}
+	q := newLaneQueue("lane-node", 10, func(key interface{}) (Lane, string) {
+		return lanes[key.(string)], key.(string)
+	})
+	defer q.ShutDown()
+
+	q.Add("k1")
+	q.Add("k2")
+	q.Add("k2")
+	assert.Equal(t, 2, q.Len())
+
+	// the key becomes critical while queued
+	lanes["k2"] = LaneCritical
+	q.Add("k2")
+	key, _ := q.Get()
+	assert.Equal(t, "k2", key)
+
+	// the key added while being processed is queued again when it's done
+	q.Add("k2")
+	assert.Equal(t, 1, q.Len())
+	q.Done(key)
+	assert.Equal(t, []string{"k2", "k1"}, getKeys(q, 2))
+
+	q.ShutDown()
+	q.Add("k1")
+	_, shutdown := q.Get()
+	assert.True(t, shutdown)
+}
+
+func TestLaneQueueDepth(t *testing.T) {
+	q := newTestLaneQueue("lane-depth-node", 10)
+	critical := monitor.NodeQueueDepth.WithLabelValues("lane-depth-node", "critical")
+	bulk := monitor.NodeQueueDepth.WithLabelValues("lane-depth-node", "bulk")
+
+	q.Add("b/a/1")
+	q.Add("b/b/2")
+	q.Add("c/c/3")
+	assert.Equal(t, float64(1), testutil.ToFloat64(critical))
+	assert.Equal(t, float64(2), testutil.ToFloat64(bulk))
+
+	getKeys(q, 1)
+	assert.Equal(t, float64(0), testutil.ToFloat64(critical))
+
+	q.ShutDown()
+	assert.Equal(t, float64(0), testutil.ToFloat64(bulk))
+}
+
+func TestInitNodeMessagePoolLanes(t *testing.T) {
+	lanes := hubconfig.Config.MessageLanes
+	hubconfig.Config.MessageLanes = &v1alpha1.CloudHubMessageLanes{Enable: true, CriticalBurst: 10}
+	defer func() { hubconfig.Config.MessageLanes = lanes }()
+
+	pool := InitNodeMessagePool("lane-pool-node")
+	defer pool.ShutDown()
+	for _, msg := range []*beehivemodel.Message{
+		newLaneMessage(beehivemodel.ResourceTypePod, "bulk", beehivemodel.UpdateOperation),
+		newLaneMessage(beehivemodel.ResourceTypePod, "gone", beehivemodel.DeleteOperation),
+	} {
+		key, err := AckMessageKeyFunc(msg)
+		assert.NoError(t, err)
+		assert.NoError(t, pool.AckMessageStore.Add(msg))
+		pool.AckMessageQueue.Add(key)
+	}
+
+	key, _ := pool.AckMessageQueue.Get()
+	msg, err := pool.GetAckMessage(key.(string))
+	assert.NoError(t, err)
+	assert.Equal(t, beehivemodel.DeleteOperation, msg.GetOperation())
+}
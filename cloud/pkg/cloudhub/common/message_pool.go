@@ -23,6 +23,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 )
 
 // NodeMessagePool is a collection of all downstream messages sent to an
@@ -46,12 +47,33 @@ type NodeMessagePool struct {
 
 // InitNodeMessagePool init node message pool for node
 func InitNodeMessagePool(nodeID string) *NodeMessagePool {
-	return &NodeMessagePool{
+	nmp := &NodeMessagePool{
 		AckMessageStore:   cache.NewStore(AckMessageKeyFunc),
-		AckMessageQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), nodeID),
 		NoAckMessageStore: cache.NewStore(NoAckMessageKeyFunc),
-		NoAckMessageQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), nodeID),
 	}
+
+	if lanes := hubconfig.Config.MessageLanes; lanes != nil && lanes.Enable {
+		// the key of the messages that require ack identifies the resource
+		nmp.AckMessageQueue = newLaneQueue(nodeID, int(lanes.CriticalBurst), func(key interface{}) (Lane, string) {
+			msg, err := nmp.GetAckMessage(key.(string))
+			if err != nil {
+				return LaneBulk, ""
+			}
+			return MessageLane(msg), key.(string)
+		})
+		nmp.NoAckMessageQueue = newLaneQueue(nodeID, int(lanes.CriticalBurst), func(key interface{}) (Lane, string) {
+			msg, err := nmp.GetNoAckMessage(key.(string))
+			if err != nil {
+				return LaneBulk, ""
+			}
+			return MessageLane(msg), msg.GetResource()
+		})
+		return nmp
+	}
+
+	nmp.AckMessageQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), nodeID)
+	nmp.NoAckMessageQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), nodeID)
+	return nmp
 }
 
 // GetAckMessage get message that requires ack with the key
@@ -3,6 +3,7 @@ package model
 import (
 	_ "encoding/json" // Mapping value of json to struct member
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
@@ -33,11 +34,18 @@ const (
 	OpConnect    = "connected"
 	OpDisConnect = "disconnected"
 	OpKeepalive  = "keepalive"
+	// OpReliableAck is the operation of the acknowledgements of the reliable messages
+	OpReliableAck = "reliable_ack"
+	// OpReconnect is the operation of the control messages which ask the edge hubs
+	// to reconnect after the delay in the content
+	OpReconnect = "reconnect"
 )
 
 // GpResource constants for message group
 const (
 	GpResource = "resource"
+	// GpHub is the group of the control messages between CloudHub and the edge hubs
+	GpHub = "hub"
 )
 
 // constants for message source
@@ -49,6 +57,24 @@ const (
 const (
 	ProjectID = "project_id"
 	NodeID    = "node_id"
+	// ReliableAck is set to true by the edge hubs which acknowledge the reliable messages
+	ReliableAck = "reliable_ack"
+	// SchemaVersion is the message schema version advertised by the edge hubs
+	SchemaVersion = "schema_version"
+)
+
+// SchemaVersionAnnotation is the annotation of the node recording the message schema
+// version negotiated with its edge hub
+const SchemaVersionAnnotation = "kubeedge.io/edge-schema-version"
+
+// constants for the message schema versions of the edge hubs
+const (
+	// SchemaVersionLegacy is the version of the edge hubs not advertising their version
+	SchemaVersionLegacy = 1
+	// SchemaVersionReconnect is the first version whose edge hubs handle the reconnect control messages
+	SchemaVersionReconnect = 2
+	// SchemaVersionCurrent is the latest version supported by CloudHub
+	SchemaVersionCurrent = SchemaVersionReconnect
 )
 
 var cloudModuleArray = []string{
@@ -130,3 +156,17 @@ func IsToEdge(msg *model.Message) bool {
 	}
 	return true
 }
+
+// NegotiateSchemaVersion returns the message schema version used with the edge hub advertising
+// the version in the header value, which is the older one of the advertised and the current.
+// The missing or unknown versions are taken as the legacy version.
+func NegotiateSchemaVersion(value string) int {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < SchemaVersionLegacy {
+		return SchemaVersionLegacy
+	}
+	if version > SchemaVersionCurrent {
+		return SchemaVersionCurrent
+	}
+	return version
+}
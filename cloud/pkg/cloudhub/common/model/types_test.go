@@ -195,3 +195,25 @@ func TestIsToEdge(t *testing.T) {
 		})
 	}
 }
+
+func TestNegotiateSchemaVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{name: "missing", value: "", want: SchemaVersionLegacy},
+		{name: "unknown", value: "v2", want: SchemaVersionLegacy},
+		{name: "below legacy", value: "0", want: SchemaVersionLegacy},
+		{name: "legacy", value: "1", want: SchemaVersionLegacy},
+		{name: "current", value: "2", want: SchemaVersionCurrent},
+		{name: "newer than current", value: "9", want: SchemaVersionCurrent},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NegotiateSchemaVersion(test.value); got != test.want {
+				t.Errorf("NegotiateSchemaVersion(%q) = %d, want %d", test.value, got, test.want)
+			}
+		})
+	}
+}
@@ -1,24 +1,63 @@
 package config
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
 	"sync"
+	"sync/atomic"
 
+	certutil "k8s.io/client-go/util/cert"
+	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
 )
 
 var Config Configure
 var once sync.Once
 
+// caPool caches the pool of the CA bundle, it is rebuilt when the CA bundle changes
+var caPool atomic.Pointer[caPoolCache]
+
+type caPoolCache struct {
+	ca            []byte
+	rootCA        []byte
+	additionalCAs [][]byte
+	pool          *x509.CertPool
+}
+
 type Configure struct {
 	v1alpha1.CloudHub
 	KubeAPIConfig *v1alpha1.KubeAPIConfig
 	Ca            []byte
 	CaKey         []byte
+	// CaSigner is the signer of the CA key held by the CA key provider, CaKey is empty if it is set
+	CaSigner crypto.Signer
+	// TokenKey is the dedicated HMAC key of the tokens when the CA key is held by the CA key provider
+	TokenKey []byte
+	// EncryptedCaKey is the PEM encoded CA key encrypted at rest, CaKey is only decrypted from it
+	// in memory, and the tokens are signed by TokenKey instead
+	EncryptedCaKey []byte
+	// RootCA is the offline root CA in DER which Ca chains to, it never signs
+	RootCA []byte
+	// AdditionalCAs are the CAs in DER trusted besides Ca, which never sign
+	AdditionalCAs [][]byte
 	Cert          []byte
 	Key           []byte
+	// MinTLSVersion is the parsed TLSMinVersion of the servers
+	MinTLSVersion uint16
+	// CipherSuites are the parsed TLSCipherSuites of the servers, nil if they are not configured
+	CipherSuites []uint16
 }
 
 func InitConfigure(hub *v1alpha1.CloudHub) {
@@ -40,20 +79,57 @@ func InitConfigure(hub *v1alpha1.CloudHub) {
 			}
 		}
 		if hub.TLSCAKeyFile != "" {
-			if block, err := certs.ReadPEMFile(hub.TLSCAKeyFile); err == nil {
+			if block, err := certs.ReadPEMFile(hub.TLSCAKeyFile); err == nil && certs.IsEncryptedPrivateKey(block) {
+				caKey, err = Config.DecryptCAKey(block)
+				if err != nil {
+					klog.Exitf("failed to decrypt the CA key file %s, err: %v", hub.TLSCAKeyFile, err)
+				}
+				Config.EncryptedCaKey = pem.EncodeToMemory(block)
+				klog.Info("succeed in loading encrypted CA key from local directory")
+			} else if err == nil {
 				caKey = block.Bytes
 				klog.Info("succeed in loading CA key from local directory")
 			} else {
 				klog.Warningf("failed to load the CA key file %s, err: %v", hub.TLSCAKeyFile, err)
 			}
 		}
-		if ca != nil && caKey != nil {
+		if p := hub.CAKeyProvider; p != nil {
+			if ca == nil {
+				klog.Exit("ca should be specified with the CA key provider!")
+			}
+			signer, err := loadCASigner(ca, p)
+			if err != nil {
+				klog.Exitf("failed to load the CA key from the provider %s, err: %v", p.Name, err)
+			}
+			Config.Ca = ca
+			Config.CaSigner = signer
+			klog.Infof("succeed in loading CA key from the provider %s", p.Name)
+		} else if ca != nil && caKey != nil {
 			Config.Ca = ca
 			Config.CaKey = caKey
 		} else if !(ca == nil && caKey == nil) {
 			klog.Exit("Both of ca and caKey should be specified!")
 		}
 
+		if hub.TLSRootCAFile != "" {
+			block, err := certs.ReadPEMFile(hub.TLSRootCAFile)
+			if err != nil {
+				klog.Exitf("failed to load the root CA file %s, err: %v", hub.TLSRootCAFile, err)
+			}
+			Config.RootCA = block.Bytes
+			klog.Info("succeed in loading root CA certificate from local directory")
+		}
+		if hub.TLSAdditionalCAFile != "" {
+			cas, err := certutil.CertsFromFile(hub.TLSAdditionalCAFile)
+			if err != nil {
+				klog.Exitf("failed to load the additional CA file %s, err: %v", hub.TLSAdditionalCAFile, err)
+			}
+			for _, ca := range cas {
+				Config.AdditionalCAs = append(Config.AdditionalCAs, ca.Raw)
+			}
+			klog.Infof("succeed in loading %d additional CA certificates from local directory", len(cas))
+		}
+
 		if hub.TLSCertFile != "" {
 			if block, err := certs.ReadPEMFile(hub.TLSCertFile); err == nil {
 				cert = block.Bytes
@@ -76,9 +152,216 @@ func InitConfigure(hub *v1alpha1.CloudHub) {
 		} else if !(cert == nil && key == nil) {
 			klog.Exit("Both of cert and key should be specified!")
 		}
+
+		minVersion, err := cliflag.TLSVersion(hub.TLSMinVersion)
+		if err != nil {
+			klog.Exitf("invalid tlsMinVersion, err: %v", err)
+		}
+		Config.MinTLSVersion = minVersion
+		if len(hub.TLSCipherSuites) > 0 {
+			Config.CipherSuites, err = cliflag.TLSCipherSuites(hub.TLSCipherSuites)
+			if err != nil {
+				klog.Exitf("invalid tlsCipherSuites, err: %v", err)
+			}
+		}
 	})
 }
 
+// ApplyTLSOptions sets the minimum TLS version and the cipher suites of the servers to the config,
+// the cipher suites of the config are kept if they are not configured.
+func (c *Configure) ApplyTLSOptions(config *tls.Config) {
+	config.MinVersion = c.MinTLSVersion
+	if config.MinVersion == 0 {
+		config.MinVersion = cliflag.DefaultTLSVersion()
+	}
+	if len(c.CipherSuites) > 0 {
+		config.CipherSuites = c.CipherSuites
+	}
+}
+
+// loadCASigner loads the signer of the CA key from the key provider, and checks that it is the key of the CA.
+func loadCASigner(caDER []byte, p *v1alpha1.CloudHubKeyProvider) (crypto.Signer, error) {
+	provider, err := certs.GetKeyProvider(p.Name)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := provider.Signer(p.KeyURI)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+	if pub, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(ca.PublicKey) {
+		return nil, errors.New("the key does not match the CA certificate")
+	}
+	return signer, nil
+}
+
+// DecryptCAKey decrypts the CA key encrypted with the passphrase of CAKeyPassphraseFile, or of the
+// env CLOUDCORE_CA_KEY_PASSPHRASE, or wrapped by the KEK of CAKeyKEKFile.
+func (c *Configure) DecryptCAKey(block *pem.Block) ([]byte, error) {
+	switch block.Type {
+	case certs.EncryptedPrivateKeyBlockType:
+		passphrase, err := c.caKeyPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		if passphrase == nil {
+			return nil, fmt.Errorf("the CA key is encrypted, but neither CAKeyPassphraseFile nor the env %s is set",
+				certs.CAKeyPassphraseEnv)
+		}
+		key, err := certs.DecryptPrivateKey(block, passphrase)
+		if errors.Is(err, certs.ErrIncorrectPassphrase) {
+			return nil, fmt.Errorf("%w, check CAKeyPassphraseFile or the env %s", err, certs.CAKeyPassphraseEnv)
+		}
+		return key, err
+	case certs.WrappedPrivateKeyBlockType:
+		if c.CAKeyKEKFile == "" {
+			return nil, errors.New("the CA key is wrapped by a KEK, but CAKeyKEKFile is not set")
+		}
+		kek, err := c.caKeyKEK()
+		if err != nil {
+			return nil, err
+		}
+		key, err := certs.UnwrapPrivateKey(block, kek)
+		if errors.Is(err, certs.ErrIncorrectKEK) {
+			return nil, fmt.Errorf("%w, check CAKeyKEKFile %s", err, c.CAKeyKEKFile)
+		}
+		return key, err
+	default:
+		return nil, fmt.Errorf("the PEM block %q is not an encrypted private key", block.Type)
+	}
+}
+
+// EncryptCAKey encrypts the CA key in DER with the passphrase or the KEK configured, and returns
+// it in PEM. It returns nil if the CA key is not configured to be encrypted.
+func (c *Configure) EncryptCAKey(der []byte) ([]byte, error) {
+	var block *pem.Block
+	if c.CAKeyKEKFile != "" {
+		kek, err := c.caKeyKEK()
+		if err != nil {
+			return nil, err
+		}
+		if block, err = certs.WrapPrivateKey(der, kek); err != nil {
+			return nil, err
+		}
+	} else {
+		passphrase, err := c.caKeyPassphrase()
+		if err != nil || passphrase == nil {
+			return nil, err
+		}
+		if block, err = certs.EncryptPrivateKey(der, passphrase); err != nil {
+			return nil, err
+		}
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// caKeyPassphrase reads the passphrase of the CA key from CAKeyPassphraseFile, or from the env
+// CLOUDCORE_CA_KEY_PASSPHRASE if the file is not set. It returns nil if neither is set.
+func (c *Configure) caKeyPassphrase() ([]byte, error) {
+	if c.CAKeyPassphraseFile == "" {
+		if v := os.Getenv(certs.CAKeyPassphraseEnv); v != "" {
+			return []byte(v), nil
+		}
+		return nil, nil
+	}
+	data, err := os.ReadFile(c.CAKeyPassphraseFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the passphrase file %s, err: %v", c.CAKeyPassphraseFile, err)
+	}
+	passphrase := bytes.TrimRight(data, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("the passphrase file %s is empty", c.CAKeyPassphraseFile)
+	}
+	return passphrase, nil
+}
+
+func (c *Configure) caKeyKEK() ([]byte, error) {
+	data, err := os.ReadFile(c.CAKeyKEKFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the KEK file %s, err: %v", c.CAKeyKEKFile, err)
+	}
+	return certs.ParseKEK(data)
+}
+
+// CASigner returns the signer of the CA key, which is either held by the CA key provider
+// or parsed from CaKey.
+func (c *Configure) CASigner() (crypto.Signer, error) {
+	if c.CaSigner != nil {
+		return c.CaSigner, nil
+	}
+	if len(c.CaKey) == 0 {
+		return nil, errors.New("the CA key is not ready")
+	}
+	return certs.PrivateKeySigner(c.CaKey)
+}
+
+// TokenSigningKey returns the HMAC key of the tokens, which is TokenKey when the CA key is held
+// by the CA key provider or encrypted at rest, otherwise it is CaKey as before.
+func (c *Configure) TokenSigningKey() []byte {
+	if c.CaSigner != nil || c.EncryptedCaKey != nil {
+		return c.TokenKey
+	}
+	return c.CaKey
+}
+
+// CABundle returns the CAs trusted to verify the edge certificates in DER, which is the CA
+// followed by the offline root CA and the additional CAs. The order is stable and the duplicate
+// CAs are dropped, so that the bundle served to the edge nodes only changes with the CAs.
+func (c *Configure) CABundle() [][]byte {
+	bundle := make([][]byte, 0, 2+len(c.AdditionalCAs))
+	for _, ca := range append([][]byte{c.Ca, c.RootCA}, c.AdditionalCAs...) {
+		if ca != nil && !slices.ContainsFunc(bundle, func(b []byte) bool { return bytes.Equal(b, ca) }) {
+			bundle = append(bundle, ca)
+		}
+	}
+	return bundle
+}
+
+// NodeNameHeader returns the header carrying the node name of the certificate requests,
+// which is NodeName unless it is configured.
+func (c *Configure) NodeNameHeader() string {
+	if c.HTTPS != nil && c.HTTPS.NodeNameHeader != "" {
+		return c.HTTPS.NodeNameHeader
+	}
+	return types.HeaderNodeName
+}
+
+// MaxCSRSize returns the max bytes of the body of the edge certificate requests,
+// which is constants.MaxRespBodyLength unless it is configured.
+func (c *Configure) MaxCSRSize() int64 {
+	if c.HTTPS != nil && c.HTTPS.MaxCSRSize > 0 {
+		return c.HTTPS.MaxCSRSize
+	}
+	return constants.MaxRespBodyLength
+}
+
+// CAPool returns the pool of the CA bundle to verify the edge certificates. The pool is built
+// once and shared, it is rebuilt only when the CA bundle changes, e.g. the CA is rotated.
+func (c *Configure) CAPool() (*x509.CertPool, error) {
+	if cached := caPool.Load(); cached != nil && bytes.Equal(cached.ca, c.Ca) && bytes.Equal(cached.rootCA, c.RootCA) &&
+		slices.EqualFunc(cached.additionalCAs, c.AdditionalCAs, bytes.Equal) {
+		return cached.pool, nil
+	}
+	pool := x509.NewCertPool()
+	for _, der := range c.CABundle() {
+		ca, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the CA certificate, err: %v", err)
+		}
+		pool.AddCert(ca)
+	}
+	additionalCAs := make([][]byte, 0, len(c.AdditionalCAs))
+	for _, ca := range c.AdditionalCAs {
+		additionalCAs = append(additionalCAs, bytes.Clone(ca))
+	}
+	caPool.Store(&caPoolCache{ca: bytes.Clone(c.Ca), rootCA: bytes.Clone(c.RootCA), additionalCAs: additionalCAs, pool: pool})
+	return pool, nil
+}
+
 func (c *Configure) UpdateCA(ca, caKey []byte) {
 	if ca != nil {
 		c.Ca = ca
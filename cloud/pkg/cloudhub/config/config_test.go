@@ -1,13 +1,27 @@
 package config
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
 )
 
 // Helper function to create valid PEM files
@@ -48,6 +62,105 @@ func TestUpdateConfig(t *testing.T) {
 			t.Errorf("UpdateCerts(): got %v, want %v", Config.Key, []byte("key"))
 		}
 	})
+
+	t.Run("CA Bundle", func(t *testing.T) {
+		Config = originalConfig
+		if bundle := Config.CABundle(); len(bundle) != 0 {
+			t.Errorf("CABundle(): got %v, want empty", bundle)
+		}
+		Config.UpdateCA([]byte("ca"), nil)
+		Config.AdditionalCAs = [][]byte{[]byte("old")}
+		want := [][]byte{[]byte("ca"), []byte("old")}
+		if bundle := Config.CABundle(); !reflect.DeepEqual(bundle, want) {
+			t.Errorf("CABundle(): got %v, want %v", bundle, want)
+		}
+
+		// the duplicate CAs are dropped in the order
+		Config.AdditionalCAs = [][]byte{[]byte("old"), []byte("ca"), []byte("old")}
+		if bundle := Config.CABundle(); !reflect.DeepEqual(bundle, want) {
+			t.Errorf("CABundle(): got %v, want %v", bundle, want)
+		}
+	})
+}
+
+func TestCASigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "KubeEdge"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("CA key in DER", func(t *testing.T) {
+		c := Configure{}
+		if _, err := c.CASigner(); err == nil {
+			t.Error("CASigner(): want an error without the CA key")
+		}
+		c.UpdateCA(caDER, keyDER)
+		signer, err := c.CASigner()
+		if err != nil {
+			t.Fatalf("CASigner(): %v", err)
+		}
+		if !key.PublicKey.Equal(signer.Public()) {
+			t.Error("CASigner(): got the signer of another key")
+		}
+		if !reflect.DeepEqual(c.TokenSigningKey(), keyDER) {
+			t.Error("TokenSigningKey(): want the CA key")
+		}
+	})
+
+	t.Run("CA key provider", func(t *testing.T) {
+		keyFile := filepath.Join(t.TempDir(), "ca.key")
+		createKeyFile := func(der []byte) {
+			if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		createKeyFile(keyDER)
+		p := &v1alpha1.CloudHubKeyProvider{Name: "file", KeyURI: "file://" + keyFile}
+		signer, err := loadCASigner(caDER, p)
+		if err != nil {
+			t.Fatalf("loadCASigner(): %v", err)
+		}
+		c := Configure{Ca: caDER, CaSigner: signer, TokenKey: []byte("token")}
+		if got, err := c.CASigner(); err != nil || got != signer {
+			t.Errorf("CASigner(): got %v, %v, want the signer of the key provider", got, err)
+		}
+		if !reflect.DeepEqual(c.TokenSigningKey(), []byte("token")) {
+			t.Error("TokenSigningKey(): want the token key")
+		}
+
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		otherDER, err := x509.MarshalECPrivateKey(other)
+		if err != nil {
+			t.Fatal(err)
+		}
+		createKeyFile(otherDER)
+		if _, err := loadCASigner(caDER, p); err == nil {
+			t.Error("loadCASigner(): want an error for the key of another CA")
+		}
+		if _, err := loadCASigner(caDER, &v1alpha1.CloudHubKeyProvider{Name: "unknown"}); err == nil {
+			t.Error("loadCASigner(): want an error for an unknown key provider")
+		}
+	})
 }
 
 func TestInitConfigureBasic(t *testing.T) {
@@ -139,3 +252,267 @@ func TestConcurrentAccess(t *testing.T) {
 		t.Error("Concurrent initialization failed")
 	}
 }
+
+// newCACert creates a self-signed CA certificate in DER.
+func newCACert(tb testing.TB, name string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return caDER
+}
+
+func newTestPool(t *testing.T, cas ...[]byte) *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, der := range cas {
+		ca, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool.AddCert(ca)
+	}
+	return pool
+}
+
+func TestCAPool(t *testing.T) {
+	oldCA, newCA := newCACert(t, "old"), newCACert(t, "new")
+	c := Configure{Ca: oldCA}
+
+	pool, err := c.CAPool()
+	if err != nil {
+		t.Fatalf("CAPool(): %v", err)
+	}
+	if cached, _ := c.CAPool(); cached != pool {
+		t.Error("CAPool(): want the cached pool when the CA bundle is not changed")
+	}
+	if !pool.Equal(newTestPool(t, oldCA)) {
+		t.Error("CAPool(): want the pool of the old CA")
+	}
+
+	// the CA is rotated, and the previous one is trusted as an additional CA
+	c.UpdateCA(newCA, nil)
+	c.AdditionalCAs = [][]byte{oldCA}
+	rotated, err := c.CAPool()
+	if err != nil {
+		t.Fatalf("CAPool(): %v", err)
+	}
+	if rotated == pool {
+		t.Fatal("CAPool(): want the pool rebuilt after the CA is rotated")
+	}
+	if !rotated.Equal(newTestPool(t, newCA, oldCA)) {
+		t.Error("CAPool(): want the pool of the new CA and the old CA")
+	}
+
+	// the offline root CA is trusted along with the CA
+	root := newCACert(t, "root")
+	c.RootCA = root
+	withRoot, err := c.CAPool()
+	if err != nil {
+		t.Fatalf("CAPool(): %v", err)
+	}
+	if withRoot == rotated {
+		t.Fatal("CAPool(): want the pool rebuilt after the root CA is set")
+	}
+	if !withRoot.Equal(newTestPool(t, newCA, root, oldCA)) {
+		t.Error("CAPool(): want the pool of the new CA, the root CA and the old CA")
+	}
+	if bundle := c.CABundle(); !reflect.DeepEqual(bundle, [][]byte{newCA, root, oldCA}) {
+		t.Errorf("CABundle(): got %v, want the CA, the root CA and the additional CA", bundle)
+	}
+
+	c.UpdateCA([]byte("invalid"), nil)
+	if _, err := c.CAPool(); err == nil {
+		t.Error("CAPool(): want an error with an invalid CA")
+	}
+}
+
+func BenchmarkCAPool(b *testing.B) {
+	c := Configure{Ca: newCACert(b, "ca"), AdditionalCAs: [][]byte{newCACert(b, "old")}}
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.CAPool(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("rebuilt", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pool := x509.NewCertPool()
+			for _, ca := range c.CABundle() {
+				if !pool.AppendCertsFromPEM(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca})) {
+					b.Fatal("failed to parse the CA")
+				}
+			}
+		}
+	})
+}
+
+func TestDecryptCAKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	passphraseFile := filepath.Join(dir, "passphrase")
+	if err := os.WriteFile(passphraseFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	kekFile := filepath.Join(dir, "kek")
+	if err := os.WriteFile(kekFile, []byte(base64.StdEncoding.EncodeToString(make([]byte, 32))), 0600); err != nil {
+		t.Fatal(err)
+	}
+	decrypt := func(c *Configure, encrypted []byte) ([]byte, error) {
+		block, _ := pem.Decode(encrypted)
+		if block == nil {
+			t.Fatal("no PEM block in the encrypted CA key")
+		}
+		return c.DecryptCAKey(block)
+	}
+	matches := func(der []byte) bool {
+		signer, err := certs.PrivateKeySigner(der)
+		return err == nil && key.PublicKey.Equal(signer.Public())
+	}
+
+	t.Run("passphrase file", func(t *testing.T) {
+		c := &Configure{CloudHub: v1alpha1.CloudHub{CAKeyPassphraseFile: passphraseFile}}
+		encrypted, err := c.EncryptCAKey(keyDER)
+		if err != nil {
+			t.Fatalf("EncryptCAKey(): %v", err)
+		}
+		der, err := decrypt(c, encrypted)
+		if err != nil || !matches(der) {
+			t.Fatalf("DecryptCAKey(): got %v, want the CA key", err)
+		}
+
+		c.EncryptedCaKey, c.TokenKey = encrypted, []byte("token")
+		c.UpdateCA(nil, der)
+		if !reflect.DeepEqual(c.TokenSigningKey(), []byte("token")) {
+			t.Error("TokenSigningKey(): want the token key for the encrypted CA key")
+		}
+
+		wrong := &Configure{}
+		t.Setenv(certs.CAKeyPassphraseEnv, "wrong")
+		if _, err := decrypt(wrong, encrypted); !errors.Is(err, certs.ErrIncorrectPassphrase) {
+			t.Errorf("DecryptCAKey(): got %v, want an incorrect passphrase error", err)
+		}
+		t.Setenv(certs.CAKeyPassphraseEnv, "secret")
+		if der, err := decrypt(wrong, encrypted); err != nil || !matches(der) {
+			t.Errorf("DecryptCAKey(): got %v, want the CA key with the passphrase of the env", err)
+		}
+		t.Setenv(certs.CAKeyPassphraseEnv, "")
+		if _, err := decrypt(wrong, encrypted); err == nil {
+			t.Error("DecryptCAKey(): want an error without the passphrase")
+		}
+	})
+
+	t.Run("KEK file", func(t *testing.T) {
+		c := &Configure{CloudHub: v1alpha1.CloudHub{CAKeyKEKFile: kekFile}}
+		encrypted, err := c.EncryptCAKey(keyDER)
+		if err != nil {
+			t.Fatalf("EncryptCAKey(): %v", err)
+		}
+		if der, err := decrypt(c, encrypted); err != nil || !reflect.DeepEqual(der, keyDER) {
+			t.Fatalf("DecryptCAKey(): got %v, want the CA key", err)
+		}
+		if _, err := decrypt(&Configure{}, encrypted); err == nil {
+			t.Error("DecryptCAKey(): want an error without the KEK file")
+		}
+	})
+
+	t.Run("not encrypted", func(t *testing.T) {
+		c := &Configure{}
+		if encrypted, err := c.EncryptCAKey(keyDER); err != nil || encrypted != nil {
+			t.Errorf("EncryptCAKey(): got %v, %v, want nothing without the passphrase and the KEK", encrypted, err)
+		}
+	})
+}
+
+func TestApplyTLSOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		minVersion    uint16
+		cipherSuites  []uint16
+		clientVersion uint16
+		clientCiphers []uint16
+		wantErr       bool
+	}{
+		{
+			name:          "TLS 1.1 client rejected by default",
+			clientVersion: tls.VersionTLS11,
+			wantErr:       true,
+		},
+		{
+			name:          "TLS 1.1 client rejected when min version is 1.2",
+			minVersion:    tls.VersionTLS12,
+			clientVersion: tls.VersionTLS11,
+			wantErr:       true,
+		},
+		{
+			name:          "TLS 1.2 client accepted when min version is 1.2",
+			minVersion:    tls.VersionTLS12,
+			clientVersion: tls.VersionTLS12,
+		},
+		{
+			name:          "TLS 1.1 client accepted when min version is 1.1",
+			minVersion:    tls.VersionTLS11,
+			clientVersion: tls.VersionTLS11,
+		},
+		{
+			name:          "TLS 1.2 client rejected when no cipher suite matches",
+			minVersion:    tls.VersionTLS12,
+			cipherSuites:  []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+			clientVersion: tls.VersionTLS12,
+			clientCiphers: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+			wantErr:       true,
+		},
+		{
+			name:          "TLS 1.2 client accepted with the configured cipher suite",
+			minVersion:    tls.VersionTLS12,
+			cipherSuites:  []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+			clientVersion: tls.VersionTLS12,
+			clientCiphers: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Configure{MinTLSVersion: tt.minVersion, CipherSuites: tt.cipherSuites}
+			srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+			srv.TLS = &tls.Config{}
+			c.ApplyTLSOptions(srv.TLS)
+			srv.StartTLS()
+			defer srv.Close()
+
+			conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), &tls.Config{
+				InsecureSkipVerify: true, // #nosec G402 -- the handshake is tested only
+				MinVersion:         tt.clientVersion,
+				MaxVersion:         tt.clientVersion,
+				CipherSuites:       tt.clientCiphers,
+			})
+			if err == nil {
+				conn.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected the handshake error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
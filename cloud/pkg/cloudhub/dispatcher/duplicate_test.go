@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
+	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
+)
+
+// connectNode admits the connection of the node as the message handler does,
+// it returns the message pool of the new session, nil if the connection is rejected
+func connectNode(md *messageDispatcher, connection *mockcon.MockConnection, remoteAddr string) *common.NodeMessagePool {
+	if !md.SessionManager.AdmitSession(tf.TestNodeID, remoteAddr) {
+		return nil
+	}
+	nmp := common.InitNodeMessagePool(tf.TestNodeID)
+	md.AddNodeMessagePool(tf.TestNodeID, nmp)
+	md.SessionManager.AddSession(session.NewNodeSession(tf.TestNodeID, tf.TestProjectID, connection,
+		tf.KeepaliveInterval, nmp, md.reliableClient))
+	return nmp
+}
+
+func TestDuplicateSessionRouting(t *testing.T) {
+	cases := []struct {
+		name          string
+		policy        v1alpha1.DuplicateSessionPolicy
+		survivorIsNew bool
+	}{
+		{name: "takeover", policy: v1alpha1.DuplicateSessionTakeover, survivorIsNew: true},
+		{name: "reject", policy: v1alpha1.DuplicateSessionReject, survivorIsNew: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			kubeClient := kubefake.NewSimpleClientset()
+			patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+				return kubeClient
+			})
+			defer patches.Reset()
+			policy := hubconfig.Config.DuplicateSessionPolicy
+			hubconfig.Config.DuplicateSessionPolicy = c.policy
+			defer func() { hubconfig.Config.DuplicateSessionPolicy = policy }()
+
+			md := newTestInstanceDispatcher(nil)
+			mockController := gomock.NewController(t)
+			defer mockController.Finish()
+
+			// the stale session is left behind by a connection the node lost without closing it
+			staleConn := mockcon.NewMockConnection(mockController)
+			stalePool := connectNode(md, staleConn, "10.0.0.1:5000")
+			if c.survivorIsNew {
+				staleConn.EXPECT().Close().Return(nil)
+			}
+			newPool := connectNode(md, mockcon.NewMockConnection(mockController), "10.0.0.2:5000")
+			if (newPool != nil) != c.survivorIsNew {
+				t.Fatalf("expected the new connection admitted %v", c.survivorIsNew)
+			}
+
+			survivor, other := stalePool, newPool
+			if c.survivorIsNew {
+				survivor, other = newPool, stalePool
+			}
+			if pool := md.GetNodeMessagePool(tf.TestNodeID); pool != survivor {
+				t.Errorf("expected the messages routed to the pool of the surviving session")
+			}
+
+			pod := tf.NewTestPodResource(tf.TestPodName, tf.TestPodUID, "2")
+			msg := tf.NewPodMessage(pod, beehivemodel.UpdateOperation)
+			md.dispatchToNode(tf.TestNodeID, msg)
+			key, _ := common.AckMessageKeyFunc(msg)
+			if _, exists, _ := survivor.AckMessageStore.GetByKey(key); !exists {
+				t.Errorf("expected the message queued for the surviving session")
+			}
+			if other != nil {
+				if _, exists, _ := other.AckMessageStore.GetByKey(key); exists {
+					t.Errorf("expected no message queued for the closed session")
+				}
+			}
+
+			err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+				func(ctx context.Context) (bool, error) {
+					events, err := kubeClient.CoreV1().Events(metav1.NamespaceDefault).List(ctx, metav1.ListOptions{})
+					if err != nil || len(events.Items) == 0 {
+						return false, err
+					}
+					event := events.Items[0]
+					return event.Reason == session.ReasonDuplicateSession && event.InvolvedObject.Name == tf.TestNodeID, nil
+				})
+			if err != nil {
+				t.Errorf("expected the duplicate session event of the node recorded")
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang/mock/gomock"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeedge/api/client/clientset/versioned/fake"
+	synclisters "github.com/kubeedge/api/client/listers/reliablesyncs/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
+	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/registry"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
+	"github.com/kubeedge/kubeedge/common/constants"
+	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
+)
+
+// fakeRegistry is the registry shared by the instances in the test
+type fakeRegistry struct {
+	self   registry.Instance
+	owners map[string]registry.Instance
+}
+
+func (r *fakeRegistry) Self() registry.Instance { return r.self }
+
+func (r *fakeRegistry) Register(nodeID string) { r.owners[nodeID] = r.self }
+
+func (r *fakeRegistry) Unregister(nodeID string) { delete(r.owners, nodeID) }
+
+func (r *fakeRegistry) Owner(nodeID string) (registry.Instance, bool) {
+	owner, ok := r.owners[nodeID]
+	return owner, ok
+}
+
+func newTestInstanceDispatcher(reg registry.Registry) *messageDispatcher {
+	manager := session.NewSessionManager(10)
+	manager.SetRegistry(reg)
+	return &messageDispatcher{
+		reliableClient:          &fake.Clientset{},
+		SessionManager:          manager,
+		objectSyncLister:        synclisters.NewObjectSyncLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		clusterObjectSyncLister: synclisters.NewClusterObjectSyncLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+	}
+}
+
+func TestForwardToOwnerInstance(t *testing.T) {
+	ca, caKey := hubconfig.Config.Ca, hubconfig.Config.CaKey
+	t.Cleanup(func() { hubconfig.Config.Ca, hubconfig.Config.CaKey = ca, caKey })
+	hubconfig.Config.CaKey = []byte("token signing key")
+
+	// the edge node is connected to instance b
+	owners := map[string]registry.Instance{}
+	mdB := newTestInstanceDispatcher(&fakeRegistry{self: registry.Instance{Identity: "instance-b"}, owners: owners})
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	nmp := common.InitNodeMessagePool(tf.TestNodeID)
+	mdB.AddNodeMessagePool(tf.TestNodeID, nmp)
+	mdB.SessionManager.AddSession(session.NewNodeSession(tf.TestNodeID, tf.TestProjectID,
+		mockcon.NewMockConnection(mockController), tf.KeepaliveInterval, nmp, mdB.reliableClient))
+
+	registry.SetDeliverFunc(mdB.DeliverLocal)
+	t.Cleanup(func() { registry.SetDeliverFunc(nil) })
+	ws := new(restful.WebService)
+	ws.Route(ws.POST(constants.DefaultSessionForwardURL).To(registry.HandleForward))
+	container := restful.NewContainer()
+	container.Add(ws)
+	srv := httptest.NewTLSServer(container)
+	defer srv.Close()
+	hubconfig.Config.Ca = srv.Certificate().Raw
+	owners[tf.TestNodeID] = registry.Instance{Identity: "instance-b", Address: strings.TrimPrefix(srv.URL, "https://")}
+
+	// the pod update originates on instance a
+	mdA := newTestInstanceDispatcher(&fakeRegistry{self: registry.Instance{Identity: "instance-a"}, owners: owners})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	mdA.forwarder = registry.NewForwarder(stopCh, mdA.dispatchToNode)
+
+	pod := tf.NewTestPodResource(tf.TestPodName, tf.TestPodUID, "2")
+	msg := tf.NewPodMessage(pod, beehivemodel.UpdateOperation)
+	if !mdA.forward(tf.TestNodeID, msg) {
+		t.Fatalf("expected the message to be forwarded to instance b")
+	}
+
+	key, _ := common.AckMessageKeyFunc(msg)
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+		func(ctx context.Context) (bool, error) {
+			_, exists, _ := nmp.AckMessageStore.GetByKey(key)
+			return exists, nil
+		})
+	if err != nil {
+		t.Fatalf("the message is not queued for the node on instance b")
+	}
+	if _, ok := mdA.NodeMessagePools.Load(tf.TestNodeID); ok {
+		t.Errorf("expected no message queued on instance a")
+	}
+
+	// the messages of the nodes connected to instance a or to none are dispatched locally
+	owners[tf.TestNodeID] = registry.Instance{Identity: "instance-a"}
+	if mdA.forward(tf.TestNodeID, msg) {
+		t.Errorf("expected the message of the node connected to instance a to be dispatched locally")
+	}
+	delete(owners, tf.TestNodeID)
+	if mdA.forward(tf.TestNodeID, msg) {
+		t.Errorf("expected the message of the disconnected node to be dispatched locally")
+	}
+}
@@ -33,6 +33,8 @@ import (
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/registry"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
@@ -44,6 +46,7 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/metaserver"
 	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
 	taskmsg "github.com/kubeedge/kubeedge/pkg/nodetask/message"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
 )
 
 // There are two `AcknowledgeMode` for message that send to edge node
@@ -89,6 +92,10 @@ type MessageDispatcher interface {
 
 	// Publish sends the given message to module according to the message source
 	Publish(msg *beehivemodel.Message) error
+
+	// DeliverLocal dispatches the message forwarded from another cloudHub instance to
+	// the given node, it returns false if the node is not connected to this instance.
+	DeliverLocal(nodeID string, msg *beehivemodel.Message) bool
 }
 
 type messageDispatcher struct {
@@ -107,6 +114,19 @@ type messageDispatcher struct {
 
 	// clusterObjectSyncLister can list/get clusterObjectSync from the shared informer's store
 	clusterObjectSyncLister synclisters.ClusterObjectSyncLister
+
+	// upstreamLimiter limits the upstream messages of each node, nil if the limit is disabled
+	upstreamLimiter *upstreamLimiter
+
+	// reliable keeps the unacknowledged reliable messages, nil if the reliable delivery is disabled
+	reliable *reliableTracker
+
+	// offline keeps the messages of the disconnected nodes, nil if the offline store is disabled
+	offline *offlineStore
+
+	// forwarder forwards the messages of the nodes connected to other cloudHub instances,
+	// nil if the session registry is disabled
+	forwarder *registry.Forwarder
 }
 
 // NewMessageDispatcher initializes a new MessageDispatcher
@@ -115,15 +135,29 @@ func NewMessageDispatcher(
 	objectSyncLister synclisters.ObjectSyncLister,
 	clusterObjectSyncLister synclisters.ClusterObjectSyncLister,
 	reliableClient reliableclient.Interface) MessageDispatcher {
-	return &messageDispatcher{
+	md := &messageDispatcher{
 		objectSyncLister:        objectSyncLister,
 		clusterObjectSyncLister: clusterObjectSyncLister,
 		reliableClient:          reliableClient,
 		SessionManager:          sessionManager,
+		upstreamLimiter:         newUpstreamLimiter(hubconfig.Config.UpstreamRateLimit),
 	}
+	md.reliable = newReliableTracker(hubconfig.Config.ReliableDelivery, md)
+	md.offline = newOfflineStore(hubconfig.Config.OfflineStore)
+	return md
 }
 
 func (md *messageDispatcher) DispatchDownstream() {
+	if md.reliable != nil {
+		go md.reliable.run(beehivecontext.Done())
+	}
+	if md.offline != nil {
+		go md.offline.run(beehivecontext.Done())
+	}
+	if md.SessionManager.Registry() != nil {
+		md.forwarder = registry.NewForwarder(beehivecontext.Done(), md.dispatchToNode)
+	}
+
 	for {
 		select {
 		case <-beehivecontext.Done():
@@ -137,7 +171,7 @@ func (md *messageDispatcher) DispatchDownstream() {
 				continue
 			}
 
-			klog.V(4).Infof("[DispatchDownstream] dispatch Message to edge: %+v", msg)
+			msgtrace.Infof(4, &msg, "[DispatchDownstream] dispatch Message to edge: %+v", msg)
 
 			nodeID, err := GetNodeID(&msg)
 			if nodeID == "" || err != nil {
@@ -150,17 +184,70 @@ func (md *messageDispatcher) DispatchDownstream() {
 				continue
 			}
 
-			switch {
-			case noAckRequired(&msg):
-				md.enqueueNoAckMessage(nodeID, &msg)
-			default:
-				md.enqueueAckMessage(nodeID, &msg)
+			if md.forward(nodeID, &msg) {
+				continue
 			}
+
+			md.dispatchToNode(nodeID, &msg)
 		}
 	}
 }
 
+// dispatchToNode dispatches the message to the message queue of the node, the message is
+// stored instead if the node is disconnected and the offline store is enabled
+func (md *messageDispatcher) dispatchToNode(nodeID string, msg *beehivemodel.Message) {
+	switch {
+	case md.reliable != nil && md.reliable.isReliable(msg):
+		md.reliable.add(nodeID, msg)
+	case md.offline != nil && md.offline.add(nodeID, msg):
+		klog.V(4).Infof("node %s is disconnected, store message %s", nodeID, msg.GetID())
+	default:
+		md.enqueue(nodeID, msg)
+	}
+}
+
+// enqueue enqueues the message to the message pool of the node
+func (md *messageDispatcher) enqueue(nodeID string, msg *beehivemodel.Message) {
+	if noAckRequired(msg) {
+		md.enqueueNoAckMessage(nodeID, msg)
+		return
+	}
+	md.enqueueAckMessage(nodeID, msg)
+}
+
+// forward forwards the message to the cloudHub instance which the node is connected to,
+// it returns false if the message is dispatched by this instance, which is the case
+// when the node is connected to this instance or to none of the instances.
+func (md *messageDispatcher) forward(nodeID string, msg *beehivemodel.Message) bool {
+	reg := md.SessionManager.Registry()
+	if reg == nil || md.forwarder == nil {
+		return false
+	}
+	if _, ok := md.SessionManager.GetSession(nodeID); ok {
+		return false
+	}
+	owner, ok := reg.Owner(nodeID)
+	if !ok || owner.Identity == reg.Self().Identity {
+		return false
+	}
+	klog.V(4).Infof("forward message %s of node %s to cloudHub instance %s", msg.GetID(), nodeID, owner.Identity)
+	md.forwarder.Forward(owner, nodeID, msg)
+	return true
+}
+
+func (md *messageDispatcher) DeliverLocal(nodeID string, msg *beehivemodel.Message) bool {
+	if _, ok := md.SessionManager.GetSession(nodeID); !ok {
+		return false
+	}
+	md.dispatchToNode(nodeID, msg)
+	return true
+}
+
 func (md *messageDispatcher) DispatchUpstream(message *beehivemodel.Message, info *model.HubInfo) {
+	if !md.admitUpstream(message, info.NodeID) {
+		return
+	}
+
 	switch {
 	case message.GetOperation() == model.OpKeepalive:
 		klog.V(4).Infof("Keepalive message received from node: %s", info.NodeID)
@@ -179,6 +266,11 @@ func (md *messageDispatcher) DispatchUpstream(message *beehivemodel.Message, inf
 			klog.Errorf("node %s receive message ack err: %v", info.NodeID, err)
 		}
 
+	case message.GetOperation() == model.OpReliableAck:
+		if md.reliable != nil {
+			md.reliable.ack(info.NodeID, message.GetParentID())
+		}
+
 	case message.GetOperation() == beehivemodel.ResponseErrorOperation:
 		klog.Errorf("node %s receive message %s error response: %v", info.NodeID, message.GetID(), message.GetContent())
 
@@ -506,9 +598,8 @@ func (md *messageDispatcher) GetNodeMessagePool(nodeID string) *common.NodeMessa
 	nsp, exist := md.NodeMessagePools.Load(nodeID)
 	if !exist {
 		klog.Warningf("message pool for edge node %s not found and created now", nodeID)
-		nodeMessagePool := common.InitNodeMessagePool(nodeID)
-		md.NodeMessagePools.Store(nodeID, nodeMessagePool)
-		return nodeMessagePool
+		// the forwarded messages are dispatched concurrently with the downstream loop
+		nsp, _ = md.NodeMessagePools.LoadOrStore(nodeID, common.InitNodeMessagePool(nodeID))
 	}
 
 	return nsp.(*common.NodeMessagePool)
@@ -516,6 +607,10 @@ func (md *messageDispatcher) GetNodeMessagePool(nodeID string) *common.NodeMessa
 
 func (md *messageDispatcher) AddNodeMessagePool(nodeID string, pool *common.NodeMessagePool) {
 	md.NodeMessagePools.Store(nodeID, pool)
+	if md.offline != nil {
+		// the stored messages are enqueued before the ones that come after
+		md.offline.connect(nodeID, md.enqueue)
+	}
 }
 
 func (md *messageDispatcher) DeleteNodeMessagePool(nodeID string, pool *common.NodeMessagePool) {
@@ -532,6 +627,9 @@ func (md *messageDispatcher) DeleteNodeMessagePool(nodeID string, pool *common.N
 	}
 
 	md.NodeMessagePools.Delete(nodeID)
+	if md.offline != nil {
+		md.offline.disconnect(nodeID)
+	}
 }
 
 func (md *messageDispatcher) Publish(msg *beehivemodel.Message) error {
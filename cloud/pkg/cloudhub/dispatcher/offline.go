@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	edgecon "github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
+)
+
+// offlineSweepPeriod is the period of dropping the expired messages of the disconnected nodes
+const offlineSweepPeriod = 10 * time.Second
+
+// offlineStore keeps the downstream messages of the disconnected nodes within the bounds of each
+// node, and sends them in order when the node connects. The messages of an object are coalesced
+// into the most recent one, and the oldest messages are evicted when the store of a node is full.
+type offlineStore struct {
+	maxMessages int
+	maxBytes    int64
+	ttl         time.Duration
+	now         func() time.Time
+
+	mu sync.Mutex
+	// online records the nodes whose message pools are added to the dispatcher
+	online map[string]bool
+	nodes  map[string]*offlineQueue
+}
+
+type offlineQueue struct {
+	// entries are the stored messages in the order they come
+	entries []*offlineEntry
+	bytes   int64
+}
+
+type offlineEntry struct {
+	// key identifies the object of the message, empty if the message is never coalesced
+	key      string
+	msg      *beehivemodel.Message
+	size     int64
+	deadline time.Time
+}
+
+// newOfflineStore returns nil if the offline store is disabled.
+func newOfflineStore(c *v1alpha1.CloudHubOfflineStore) *offlineStore {
+	if c == nil || !c.Enable {
+		return nil
+	}
+	return &offlineStore{
+		maxMessages: int(c.MaxMessages),
+		maxBytes:    c.MaxBytes,
+		ttl:         time.Duration(c.TTL) * time.Second,
+		now:         time.Now,
+		online:      make(map[string]bool),
+		nodes:       make(map[string]*offlineQueue),
+	}
+}
+
+// add stores the message of the node if the node is disconnected, it returns false if
+// the node is connected and the message should be enqueued to its message pool.
+func (s *offlineStore) add(nodeID string, msg *beehivemodel.Message) bool {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.online[nodeID] {
+		return false
+	}
+
+	if model.IsNodeStopped(msg) {
+		if q, ok := s.nodes[nodeID]; ok {
+			klog.Warningf("node %s is deleted, drop its %d stored messages", nodeID, len(q.entries))
+			s.evict(nodeID, q, len(q.entries), "deleted")
+			delete(s.nodes, nodeID)
+		}
+		return true
+	}
+
+	q, ok := s.nodes[nodeID]
+	if !ok {
+		q = &offlineQueue{}
+		s.nodes[nodeID] = q
+	}
+	s.expire(nodeID, q, now)
+
+	e := &offlineEntry{
+		key:      objectKey(msg),
+		msg:      msg,
+		size:     messageSize(msg),
+		deadline: now.Add(s.ttl),
+	}
+	if e.key != "" {
+		for i, old := range q.entries {
+			if old.key == e.key {
+				q.remove(nodeID, i)
+				monitor.OfflineEvictionsTotal.WithLabelValues(monitor.NodeLabel(nodeID), "coalesced").Inc()
+				break
+			}
+		}
+	}
+	q.entries = append(q.entries, e)
+	q.bytes += e.size
+	monitor.OfflineMessages.WithLabelValues(monitor.NodeLabel(nodeID)).Inc()
+	monitor.OfflineBytes.WithLabelValues(monitor.NodeLabel(nodeID)).Add(float64(e.size))
+
+	var overflow int
+	for bytes := q.bytes; len(q.entries)-overflow > s.maxMessages || bytes > s.maxBytes; overflow++ {
+		bytes -= q.entries[overflow].size
+	}
+	if overflow > 0 {
+		klog.Warningf("the offline store of node %s is full, evict the oldest %d messages", nodeID, overflow)
+		s.evict(nodeID, q, overflow, "overflow")
+	}
+	if len(q.entries) == 0 {
+		delete(s.nodes, nodeID)
+	}
+	return true
+}
+
+// connect sends the stored messages of the node in order, the messages of the
+// node are enqueued to its message pool by the dispatcher after it returns.
+func (s *offlineStore) connect(nodeID string, send func(nodeID string, msg *beehivemodel.Message)) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.online[nodeID] = true
+	q, ok := s.nodes[nodeID]
+	if !ok {
+		return
+	}
+	delete(s.nodes, nodeID)
+	s.expire(nodeID, q, now)
+
+	klog.Infof("send %d stored messages to node %s", len(q.entries), nodeID)
+	for _, e := range q.entries {
+		send(nodeID, e.msg)
+	}
+	monitor.OfflineMessages.WithLabelValues(monitor.NodeLabel(nodeID)).Sub(float64(len(q.entries)))
+	monitor.OfflineBytes.WithLabelValues(monitor.NodeLabel(nodeID)).Sub(float64(q.bytes))
+}
+
+// disconnect stores the messages of the node from now on.
+func (s *offlineStore) disconnect(nodeID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.online, nodeID)
+}
+
+// run drops the expired messages until stopCh is closed.
+func (s *offlineStore) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(offlineSweepPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.sweep()
+		}
+	}
+}
+
+func (s *offlineStore) sweep() {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for nodeID, q := range s.nodes {
+		s.expire(nodeID, q, now)
+		if len(q.entries) == 0 {
+			delete(s.nodes, nodeID)
+		}
+	}
+}
+
+// expire drops the expired messages of the node, which are the oldest ones as the
+// messages are kept in the order they come.
+func (s *offlineStore) expire(nodeID string, q *offlineQueue, now time.Time) {
+	var n int
+	for n < len(q.entries) && !now.Before(q.entries[n].deadline) {
+		n++
+	}
+	if n > 0 {
+		klog.Warningf("%d stored messages of node %s expired", n, nodeID)
+		s.evict(nodeID, q, n, "expired")
+	}
+}
+
+// evict drops the oldest n messages of the node for the reason.
+func (s *offlineStore) evict(nodeID string, q *offlineQueue, n int, reason string) {
+	size := q.size(n)
+	q.entries = q.entries[n:]
+	q.bytes -= size
+	node := monitor.NodeLabel(nodeID)
+	monitor.OfflineMessages.WithLabelValues(node).Sub(float64(n))
+	monitor.OfflineBytes.WithLabelValues(node).Sub(float64(size))
+	monitor.OfflineEvictionsTotal.WithLabelValues(node, reason).Add(float64(n))
+}
+
+// size returns the total size of the oldest n messages.
+func (q *offlineQueue) size(n int) int64 {
+	var size int64
+	for _, e := range q.entries[:n] {
+		size += e.size
+	}
+	return size
+}
+
+// remove drops the i-th message which is superseded by a more recent one of the object.
+func (q *offlineQueue) remove(nodeID string, i int) {
+	e := q.entries[i]
+	q.entries = append(q.entries[:i], q.entries[i+1:]...)
+	q.bytes -= e.size
+	monitor.OfflineMessages.WithLabelValues(monitor.NodeLabel(nodeID)).Dec()
+	monitor.OfflineBytes.WithLabelValues(monitor.NodeLabel(nodeID)).Sub(float64(e.size))
+}
+
+// objectKey returns the UID of the object in the resource message, empty
+// if the message carries no object, such as a list.
+func objectKey(msg *beehivemodel.Message) string {
+	if msg.GetGroup() != edgecon.GroupResource {
+		return ""
+	}
+	uid, err := common.GetMessageUID(*msg)
+	if err != nil {
+		return ""
+	}
+	return uid
+}
+
+func messageSize(msg *beehivemodel.Message) int64 {
+	data, err := msg.GetContentData()
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
+	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+func newTestOfflineStore(maxMessages int32, now *time.Time) *offlineStore {
+	s := newOfflineStore(&v1alpha1.CloudHubOfflineStore{
+		Enable:      true,
+		MaxMessages: maxMessages,
+		MaxBytes:    1 << 20,
+		TTL:         60,
+	})
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func storedIDs(s *offlineStore, nodeID string) []string {
+	var ids []string
+	s.connect(nodeID, func(_ string, msg *beehivemodel.Message) {
+		ids = append(ids, msg.GetID())
+	})
+	return ids
+}
+
+func TestOfflineStoreCoalescing(t *testing.T) {
+	const nodeID = "offline-coalescing-node"
+	now := time.Now()
+	s := newTestOfflineStore(10, &now)
+
+	podV1 := tf.NewPodMessage(tf.NewTestPodResource("pod", "pod-uid", "1"), beehivemodel.UpdateOperation)
+	configMap := tf.NewConfigMapMessage(tf.NewTestConfigMapResource("cm", "cm-uid", "1"), beehivemodel.UpdateOperation)
+	podV2 := tf.NewPodMessage(tf.NewTestPodResource("pod", "pod-uid", "2"), beehivemodel.UpdateOperation)
+
+	coalesced := monitor.OfflineEvictionsTotal.WithLabelValues(nodeID, "coalesced")
+	before := testutil.ToFloat64(coalesced)
+	for _, msg := range []*beehivemodel.Message{podV1, configMap, podV2} {
+		if !s.add(nodeID, msg) {
+			t.Fatalf("expected the message of the disconnected node stored")
+		}
+	}
+
+	if got := testutil.ToFloat64(monitor.OfflineMessages.WithLabelValues(nodeID)); got != 2 {
+		t.Errorf("expected 2 stored messages, got %v", got)
+	}
+	if got := testutil.ToFloat64(coalesced) - before; got != 1 {
+		t.Errorf("expected 1 coalesced message, got %v", got)
+	}
+	// the most recent version of the pod is sent after the configmap
+	ids := storedIDs(s, nodeID)
+	if len(ids) != 2 || ids[0] != configMap.GetID() || ids[1] != podV2.GetID() {
+		t.Errorf("expected the configmap and the pod of version 2 sent in order, got %v", ids)
+	}
+	if got := testutil.ToFloat64(monitor.OfflineMessages.WithLabelValues(nodeID)); got != 0 {
+		t.Errorf("expected no stored messages after the node connects, got %v", got)
+	}
+	if s.add(nodeID, podV1) {
+		t.Errorf("expected the message of the connected node not stored")
+	}
+}
+
+func TestOfflineStoreTTL(t *testing.T) {
+	const nodeID = "offline-ttl-node"
+	now := time.Now()
+	s := newTestOfflineStore(10, &now)
+
+	old := tf.NewPodMessage(tf.NewTestPodResource("old", "old-uid", "1"), beehivemodel.UpdateOperation)
+	s.add(nodeID, old)
+	now = now.Add(30 * time.Second)
+	recent := tf.NewPodMessage(tf.NewTestPodResource("recent", "recent-uid", "1"), beehivemodel.UpdateOperation)
+	s.add(nodeID, recent)
+
+	expired := monitor.OfflineEvictionsTotal.WithLabelValues(nodeID, "expired")
+	before := testutil.ToFloat64(expired)
+	now = now.Add(45 * time.Second)
+	s.sweep()
+	if got := testutil.ToFloat64(expired) - before; got != 1 {
+		t.Errorf("expected 1 expired message, got %v", got)
+	}
+	if ids := storedIDs(s, nodeID); len(ids) != 1 || ids[0] != recent.GetID() {
+		t.Errorf("expected only the recent message sent, got %v", ids)
+	}
+
+	s.disconnect(nodeID)
+	s.add(nodeID, old)
+	now = now.Add(2 * time.Minute)
+	if ids := storedIDs(s, nodeID); len(ids) != 0 {
+		t.Errorf("expected the expired messages not sent, got %v", ids)
+	}
+}
+
+func TestOfflineStoreBounds(t *testing.T) {
+	const nodeID = "offline-bounds-node"
+	now := time.Now()
+	s := newTestOfflineStore(2, &now)
+
+	var msgs []*beehivemodel.Message
+	for _, uid := range []string{"a", "b", "c"} {
+		msg := tf.NewPodMessage(tf.NewTestPodResource(uid, uid, "1"), beehivemodel.UpdateOperation)
+		msgs = append(msgs, msg)
+		s.add(nodeID, msg)
+	}
+	if ids := storedIDs(s, nodeID); len(ids) != 2 || ids[0] != msgs[1].GetID() || ids[1] != msgs[2].GetID() {
+		t.Errorf("expected the oldest message evicted, got %v", ids)
+	}
+
+	s.disconnect(nodeID)
+	s.maxBytes = messageSize(msgs[0]) + 1
+	s.add(nodeID, msgs[0])
+	s.add(nodeID, msgs[1])
+	if got := testutil.ToFloat64(monitor.OfflineBytes.WithLabelValues(nodeID)); got != float64(messageSize(msgs[1])) {
+		t.Errorf("expected %d stored bytes, got %v", messageSize(msgs[1]), got)
+	}
+	if ids := storedIDs(s, nodeID); len(ids) != 1 || ids[0] != msgs[1].GetID() {
+		t.Errorf("expected the messages over the max bytes evicted, got %v", ids)
+	}
+}
+
+func TestOfflineStoreReplay(t *testing.T) {
+	md := newTestInstanceDispatcher(nil)
+	now := time.Now()
+	md.offline = newTestOfflineStore(10, &now)
+
+	stored := []*beehivemodel.Message{
+		tf.NewPodMessage(tf.NewTestPodResource("p1", "uid-1", "1"), beehivemodel.UpdateOperation),
+		tf.NewPodMessage(tf.NewTestPodResource("p2", "uid-2", "1"), beehivemodel.UpdateOperation),
+	}
+	for _, msg := range stored {
+		md.dispatchToNode(tf.TestNodeID, msg)
+	}
+	if _, ok := md.NodeMessagePools.Load(tf.TestNodeID); ok {
+		t.Fatalf("expected no message pool for the disconnected node")
+	}
+
+	// the stored messages are enqueued when the node connects, before the live ones
+	nmp := common.InitNodeMessagePool(tf.TestNodeID)
+	md.AddNodeMessagePool(tf.TestNodeID, nmp)
+	live := tf.NewPodMessage(tf.NewTestPodResource("p3", "uid-3", "1"), beehivemodel.UpdateOperation)
+	md.dispatchToNode(tf.TestNodeID, live)
+
+	for _, msg := range append(stored, live) {
+		key, _ := nmp.AckMessageQueue.Get()
+		if want, _ := common.AckMessageKeyFunc(msg); key != want {
+			t.Errorf("expected message %s, got %v", want, key)
+		}
+		nmp.AckMessageQueue.Done(key)
+	}
+
+	md.DeleteNodeMessagePool(tf.TestNodeID, nmp)
+	md.dispatchToNode(tf.TestNodeID, live)
+	if nmp.AckMessageQueue.Len() != 0 {
+		t.Errorf("expected the message of the disconnected node stored")
+	}
+}
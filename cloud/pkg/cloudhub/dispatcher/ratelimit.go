@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+// upstreamLimiter limits the upstream messages of each node by a token bucket. The buckets
+// are kept by the node names rather than the sessions, so that a node reconnecting starts
+// with the bucket it left, and the violations it has made.
+type upstreamLimiter struct {
+	limit           rate.Limit
+	burst           int
+	disconnect      bool
+	maxViolations   int
+	warningInterval time.Duration
+	now             func() time.Time
+
+	mu        sync.Mutex
+	nodes     map[string]*nodeLimiter
+	lastSweep time.Time
+}
+
+type nodeLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+	// violations is the number of the messages dropped since the bucket was last full
+	violations int
+	// dropped is the number of the messages dropped since the last warning
+	dropped     int
+	lastWarning time.Time
+}
+
+// newUpstreamLimiter returns nil if the upstream rate limit is disabled.
+func newUpstreamLimiter(c *v1alpha1.CloudHubUpstreamRateLimit) *upstreamLimiter {
+	if c == nil || !c.Enable {
+		return nil
+	}
+	return &upstreamLimiter{
+		limit:           rate.Limit(c.QPS),
+		burst:           int(c.Burst),
+		disconnect:      c.OverflowPolicy == v1alpha1.UpstreamOverflowDisconnect,
+		maxViolations:   int(c.MaxViolations),
+		warningInterval: time.Duration(c.WarningInterval) * time.Second,
+		now:             time.Now,
+		nodes:           make(map[string]*nodeLimiter),
+	}
+}
+
+// admit takes a token from the bucket of the node, it reports whether the message is
+// dispatched, and whether the session of the node should be closed.
+func (l *upstreamLimiter) admit(nodeID string) (bool, bool) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	nl, ok := l.nodes[nodeID]
+	if !ok {
+		nl = &nodeLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.nodes[nodeID] = nl
+	}
+	nl.lastSeen = now
+	if nl.limiter.TokensAt(now) >= float64(l.burst) {
+		nl.violations = 0
+	}
+	if nl.limiter.AllowN(now, 1) {
+		return true, false
+	}
+
+	nl.violations++
+	nl.dropped++
+	monitor.NodeMessagesDroppedTotal.WithLabelValues(monitor.NodeLabel(nodeID)).Inc()
+	if nl.lastWarning.IsZero() || now.Sub(nl.lastWarning) >= l.warningInterval {
+		klog.Warningf("node %s exceeds the upstream rate limit, %d messages are dropped", nodeID, nl.dropped)
+		nl.dropped = 0
+		nl.lastWarning = now
+	}
+	if !l.disconnect || nl.violations < l.maxViolations {
+		return false, false
+	}
+	nl.violations = 0
+	return false, true
+}
+
+// sweep drops the buckets of the nodes which have been idle long enough for the buckets
+// to be refilled and the warnings to be due again, they are the same as new ones.
+func (l *upstreamLimiter) sweep(now time.Time) {
+	idle := time.Duration(float64(l.burst) / float64(l.limit) * float64(time.Second))
+	if l.warningInterval > idle {
+		idle = l.warningInterval
+	}
+	if now.Sub(l.lastSweep) < idle {
+		return
+	}
+	l.lastSweep = now
+	for nodeID, nl := range l.nodes {
+		if now.Sub(nl.lastSeen) >= idle {
+			delete(l.nodes, nodeID)
+		}
+	}
+}
+
+// admitUpstream reports whether the upstream message of the node is dispatched. The keepalive
+// messages and the acks are always dispatched, so that a throttled node keeps its session and
+// does not get the messages it has received again.
+func (md *messageDispatcher) admitUpstream(message *beehivemodel.Message, nodeID string) bool {
+	if md.upstreamLimiter == nil {
+		return true
+	}
+	switch message.GetOperation() {
+	case model.OpKeepalive, model.OpReliableAck, beehivemodel.ResponseOperation:
+		return true
+	}
+	ok, disconnect := md.upstreamLimiter.admit(nodeID)
+	if disconnect {
+		klog.Warningf("close the session of node %s, it exceeds the upstream rate limit %d times", nodeID, md.upstreamLimiter.maxViolations)
+		md.SessionManager.CloseSession(nodeID)
+	}
+	return ok
+}
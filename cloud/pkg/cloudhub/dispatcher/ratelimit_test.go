@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+func newTestUpstreamLimiter(policy v1alpha1.UpstreamOverflowPolicy, now *time.Time) *upstreamLimiter {
+	l := newUpstreamLimiter(&v1alpha1.CloudHubUpstreamRateLimit{
+		Enable:          true,
+		QPS:             1,
+		Burst:           2,
+		OverflowPolicy:  policy,
+		MaxViolations:   3,
+		WarningInterval: 60,
+	})
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestNewUpstreamLimiterDisabled(t *testing.T) {
+	if l := newUpstreamLimiter(nil); l != nil {
+		t.Errorf("expected no limiter without config, got %v", l)
+	}
+	if l := newUpstreamLimiter(&v1alpha1.CloudHubUpstreamRateLimit{QPS: 1, Burst: 1}); l != nil {
+		t.Errorf("expected no limiter when disabled, got %v", l)
+	}
+}
+
+func TestUpstreamLimiterDrop(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := newTestUpstreamLimiter(v1alpha1.UpstreamOverflowDrop, &now)
+	const nodeID = "ratelimit-drop-node"
+
+	before := testutil.ToFloat64(monitor.NodeMessagesDroppedTotal.WithLabelValues(nodeID))
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.admit(nodeID); !ok {
+			t.Fatalf("message %d within the burst is dropped", i)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		ok, disconnect := l.admit(nodeID)
+		if ok || disconnect {
+			t.Fatalf("message %d over the limit: admitted %v, disconnect %v", i, ok, disconnect)
+		}
+	}
+	if got := testutil.ToFloat64(monitor.NodeMessagesDroppedTotal.WithLabelValues(nodeID)) - before; got != 10 {
+		t.Errorf("expected 10 dropped messages, got %v", got)
+	}
+	if ok, _ := l.admit("ratelimit-other-node"); !ok {
+		t.Errorf("the message of another node is dropped")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.admit(nodeID); !ok {
+		t.Errorf("the message is dropped after the bucket is refilled")
+	}
+}
+
+func TestUpstreamLimiterDisconnect(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := newTestUpstreamLimiter(v1alpha1.UpstreamOverflowDisconnect, &now)
+	const nodeID = "ratelimit-disconnect-node"
+
+	l.admit(nodeID)
+	l.admit(nodeID)
+	for i := 1; i <= 3; i++ {
+		ok, disconnect := l.admit(nodeID)
+		if ok {
+			t.Fatalf("violation %d is admitted", i)
+		}
+		if disconnect != (i == 3) {
+			t.Fatalf("violation %d: expected disconnect %v, got %v", i, i == 3, disconnect)
+		}
+	}
+
+	// the violations are reset once the bucket is full again
+	now = now.Add(time.Second)
+	if ok, _ := l.admit(nodeID); !ok {
+		t.Fatalf("the message is dropped after a token is refilled")
+	}
+	if ok, disconnect := l.admit(nodeID); ok || disconnect {
+		t.Fatalf("expected the message dropped without disconnect, admitted %v, disconnect %v", ok, disconnect)
+	}
+	now = now.Add(2 * time.Second)
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.admit(nodeID); !ok {
+			t.Fatalf("message %d within the refilled burst is dropped", i)
+		}
+	}
+	for i := 1; i <= 3; i++ {
+		if _, disconnect := l.admit(nodeID); disconnect != (i == 3) {
+			t.Fatalf("violation %d after refill: expected disconnect %v, got %v", i, i == 3, disconnect)
+		}
+	}
+}
+
+func TestUpstreamLimiterSweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := newTestUpstreamLimiter(v1alpha1.UpstreamOverflowDrop, &now)
+
+	l.admit("ratelimit-sweep-node")
+	now = now.Add(time.Minute)
+	l.admit("ratelimit-sweep-other-node")
+	if _, ok := l.nodes["ratelimit-sweep-node"]; ok {
+		t.Errorf("expected the idle node to be swept")
+	}
+	if len(l.nodes) != 1 {
+		t.Errorf("expected 1 node kept, got %d", len(l.nodes))
+	}
+}
+
+func TestAdmitUpstream(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	const nodeID = "ratelimit-admit-node"
+	md := &messageDispatcher{
+		SessionManager:  session.NewSessionManager(10),
+		upstreamLimiter: newTestUpstreamLimiter(v1alpha1.UpstreamOverflowDisconnect, &now),
+	}
+
+	update := beehivemodel.NewMessage("").SetResourceOperation("node/"+nodeID+"/default/pod/foo", beehivemodel.UpdateOperation)
+	keepalive := beehivemodel.NewMessage("").SetResourceOperation("node/"+nodeID, model.OpKeepalive)
+	ack := beehivemodel.NewMessage("").SetResourceOperation("node/"+nodeID, beehivemodel.ResponseOperation)
+
+	var admitted int
+	for i := 0; i < 10; i++ {
+		if md.admitUpstream(update, nodeID) {
+			admitted++
+		}
+		if !md.admitUpstream(keepalive, nodeID) {
+			t.Fatalf("the keepalive message is dropped")
+		}
+		if !md.admitUpstream(ack, nodeID) {
+			t.Fatalf("the ack is dropped")
+		}
+	}
+	if admitted != 2 {
+		t.Errorf("expected 2 messages admitted, got %d", admitted)
+	}
+
+	md.upstreamLimiter = nil
+	if !md.admitUpstream(update, nodeID) {
+		t.Errorf("the message is dropped with the limit disabled")
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/nodes"
+)
+
+const (
+	// reliableRetryPeriod is the period of sending the reliable messages again and expiring them
+	reliableRetryPeriod = time.Second
+
+	// ReasonReliableMessagesExpired is the reason of the Warning Event of the node whose
+	// reliable messages expire unacknowledged
+	ReasonReliableMessagesExpired = "ReliableMessagesExpired"
+)
+
+// reliableTracker keeps the unacknowledged reliable messages of each node, and sends them again
+// with exponential backoff until the node acknowledges them or they expire. The messages are kept
+// by the node names rather than the sessions, so that they are sent again after the node reconnects.
+type reliableTracker struct {
+	resourceTypes  map[string]bool
+	queueDepth     int
+	ttl            time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	now            func() time.Time
+
+	// send enqueues the message to the session of the node
+	send func(nodeID string, msg *beehivemodel.Message)
+	// acks reports whether the node has an active session, and whether the node
+	// acknowledges the reliable messages
+	acks func(nodeID string) (bool, bool)
+	// recordEvent records the Warning Event of the node whose reliable messages expire
+	recordEvent func(nodeID, message string)
+
+	mu    sync.Mutex
+	nodes map[string]*reliableQueue
+}
+
+type reliableQueue struct {
+	// order is the IDs of the messages in the order they come
+	order   []string
+	entries map[string]*reliableEntry
+}
+
+type reliableEntry struct {
+	msg      *beehivemodel.Message
+	deadline time.Time
+	// nextSend is the time to send the message again, zero means the message is sent at once
+	nextSend time.Time
+	backoff  time.Duration
+}
+
+// newReliableTracker returns nil if the reliable delivery is disabled.
+func newReliableTracker(c *v1alpha1.CloudHubReliableDelivery, md *messageDispatcher) *reliableTracker {
+	if c == nil || !c.Enable {
+		return nil
+	}
+	resourceTypes := make(map[string]bool, len(c.ResourceTypes))
+	for _, resourceType := range c.ResourceTypes {
+		resourceTypes[resourceType] = true
+	}
+	return &reliableTracker{
+		resourceTypes:  resourceTypes,
+		queueDepth:     int(c.QueueDepth),
+		ttl:            time.Duration(c.TTL) * time.Second,
+		initialBackoff: time.Duration(c.InitialBackoff) * time.Second,
+		maxBackoff:     time.Duration(c.MaxBackoff) * time.Second,
+		now:            time.Now,
+		send:           md.enqueueNoAckMessage,
+		acks:           md.SessionManager.AcksReliableMessages,
+		recordEvent:    recordReliableEvent,
+		nodes:          make(map[string]*reliableQueue),
+	}
+}
+
+// isReliable reports whether the message is flagged as reliable by its sender, or it is of
+// the reliable resource types and not synced by the ObjectSyncs.
+func (t *reliableTracker) isReliable(msg *beehivemodel.Message) bool {
+	if msg.Header.Reliable {
+		return true
+	}
+	if len(t.resourceTypes) == 0 || !noAckRequired(msg) {
+		return false
+	}
+	for _, token := range strings.Split(msg.GetResource(), "/") {
+		if t.resourceTypes[token] {
+			return true
+		}
+	}
+	return false
+}
+
+// add keeps the reliable message of the node and sends it if the node is connected, the oldest
+// message of the node is dropped if its queue is full.
+func (t *reliableTracker) add(nodeID string, msg *beehivemodel.Message) {
+	if msg.GetID() == "" {
+		msg.UpdateID()
+	}
+	msg.Header.Reliable = true
+
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	q, ok := t.nodes[nodeID]
+	if !ok {
+		q = &reliableQueue{entries: make(map[string]*reliableEntry)}
+		t.nodes[nodeID] = q
+	}
+	if _, exists := q.entries[msg.GetID()]; exists {
+		return
+	}
+	if len(q.order) >= t.queueDepth {
+		oldest := q.order[0]
+		klog.Warningf("the reliable message queue of node %s is full, drop the oldest message %s", nodeID, oldest)
+		delete(q.entries, oldest)
+		q.order = q.order[1:]
+		monitor.ReliableMessagesDroppedTotal.WithLabelValues(monitor.NodeLabel(nodeID), "overflow").Inc()
+	}
+	q.entries[msg.GetID()] = &reliableEntry{
+		msg:      msg,
+		deadline: now.Add(t.ttl),
+		backoff:  t.initialBackoff,
+	}
+	q.order = append(q.order, msg.GetID())
+	t.sendDue(nodeID, q, now)
+}
+
+// ack drops the message of the node acknowledged by the edge hub.
+func (t *reliableTracker) ack(nodeID, msgID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	q, ok := t.nodes[nodeID]
+	if !ok {
+		return
+	}
+	if _, exists := q.entries[msgID]; !exists {
+		return
+	}
+	delete(q.entries, msgID)
+	for i, id := range q.order {
+		if id == msgID {
+			q.order = append(q.order[:i], q.order[i+1:]...)
+			break
+		}
+	}
+	if len(q.order) == 0 {
+		delete(t.nodes, nodeID)
+	}
+}
+
+// run sends the reliable messages again and expires them until stopCh is closed.
+func (t *reliableTracker) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(reliableRetryPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			t.retry()
+		}
+	}
+}
+
+// retry sends the messages due again, and records the Events of the nodes whose messages expire.
+func (t *reliableTracker) retry() {
+	now := t.now()
+	expired := make(map[string]int)
+	t.mu.Lock()
+	for nodeID, q := range t.nodes {
+		if n := t.sendDue(nodeID, q, now); n > 0 {
+			expired[nodeID] = n
+		}
+		if len(q.order) == 0 {
+			delete(t.nodes, nodeID)
+		}
+	}
+	t.mu.Unlock()
+
+	for nodeID, n := range expired {
+		t.recordEvent(nodeID, fmt.Sprintf("%d reliable messages to node %s expired unacknowledged after %v",
+			n, nodeID, t.ttl))
+	}
+}
+
+// sendDue sends the messages of the node which are due, and drops the expired ones, it returns
+// the number of the expired messages. The messages are sent only once if the edge hub of the
+// node does not acknowledge them, and they wait for the node to connect if it is offline.
+func (t *reliableTracker) sendDue(nodeID string, q *reliableQueue, now time.Time) int {
+	connected, acks := t.acks(nodeID)
+	var expired int
+	order := q.order[:0]
+	for _, id := range q.order {
+		e := q.entries[id]
+		switch {
+		case !now.Before(e.deadline):
+			delete(q.entries, id)
+			expired++
+			continue
+		case !connected:
+			e.nextSend = time.Time{}
+		case !acks:
+			klog.V(4).Infof("node %s does not acknowledge the reliable messages, send message %s once", nodeID, id)
+			t.send(nodeID, common.DeepCopy(e.msg))
+			delete(q.entries, id)
+			continue
+		case !now.Before(e.nextSend):
+			t.send(nodeID, common.DeepCopy(e.msg))
+			e.nextSend = now.Add(e.backoff)
+			e.backoff = min(2*e.backoff, t.maxBackoff)
+		}
+		order = append(order, id)
+	}
+	q.order = order
+	if expired > 0 {
+		klog.Warningf("%d reliable messages to node %s expired unacknowledged", expired, nodeID)
+		monitor.ReliableMessagesDroppedTotal.WithLabelValues(monitor.NodeLabel(nodeID), "expired").Add(float64(expired))
+	}
+	return expired
+}
+
+// recordReliableEvent records a warning event of the node whose reliable messages expire.
+func recordReliableEvent(nodeName, message string) {
+	nodes.RecordEvent(context.Background(), client.GetKubeClient(), nodeName, corev1.EventTypeWarning,
+		ReasonReliableMessagesExpired, message)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+type fakeReliableNode struct {
+	connected, acks bool
+	sent            []string
+	events          []string
+}
+
+func newTestReliableTracker(node *fakeReliableNode, now *time.Time) *reliableTracker {
+	md := &messageDispatcher{SessionManager: session.NewSessionManager(10)}
+	t := newReliableTracker(&v1alpha1.CloudHubReliableDelivery{
+		Enable:         true,
+		ResourceTypes:  []string{"membership"},
+		QueueDepth:     2,
+		TTL:            60,
+		InitialBackoff: 5,
+		MaxBackoff:     10,
+	}, md)
+	t.now = func() time.Time { return *now }
+	t.send = func(_ string, msg *beehivemodel.Message) {
+		node.sent = append(node.sent, msg.GetID())
+	}
+	t.acks = func(string) (bool, bool) { return node.connected, node.acks }
+	t.recordEvent = func(_, message string) { node.events = append(node.events, message) }
+	return t
+}
+
+func newReliableMessage(nodeID string) *beehivemodel.Message {
+	msg := beehivemodel.NewMessage("").SetResourceOperation("node/"+nodeID+"/default/pod/foo", beehivemodel.DeleteOperation)
+	msg.Header.Reliable = true
+	return msg
+}
+
+func TestReliableTrackerIsReliable(t *testing.T) {
+	now := time.Now()
+	tracker := newTestReliableTracker(&fakeReliableNode{}, &now)
+
+	tests := []struct {
+		name    string
+		message *beehivemodel.Message
+		want    bool
+	}{
+		{
+			name:    "flagged message",
+			message: newReliableMessage("edge-node"),
+			want:    true,
+		},
+		{
+			name:    "message of reliable resource type",
+			message: beehivemodel.NewMessage("").SetResourceOperation("node/edge-node/membership/detail", "response"),
+			want:    true,
+		},
+		{
+			name:    "message of other resource type",
+			message: beehivemodel.NewMessage("").SetResourceOperation("node/edge-node/default/podlist", "response"),
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tracker.isReliable(tt.message); got != tt.want {
+				t.Errorf("isReliable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReliableTrackerRetryAndAck(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	node := &fakeReliableNode{connected: true, acks: true}
+	tracker := newTestReliableTracker(node, &now)
+	const nodeID = "reliable-retry-node"
+
+	msg := newReliableMessage(nodeID)
+	tracker.add(nodeID, msg)
+	tracker.add(nodeID, msg)
+	if len(node.sent) != 1 {
+		t.Fatalf("expected the message sent once, got %d", len(node.sent))
+	}
+
+	// sent again after 5s, 10s and then every 10s
+	for _, step := range []struct {
+		after time.Duration
+		sent  int
+	}{
+		{4 * time.Second, 1},
+		{time.Second, 2},
+		{9 * time.Second, 2},
+		{time.Second, 3},
+		{10 * time.Second, 4},
+	} {
+		now = now.Add(step.after)
+		tracker.retry()
+		if len(node.sent) != step.sent {
+			t.Fatalf("at %v: expected %d sends, got %d", now, step.sent, len(node.sent))
+		}
+	}
+
+	tracker.ack(nodeID, msg.GetID())
+	now = now.Add(time.Minute)
+	tracker.retry()
+	if len(node.sent) != 4 {
+		t.Errorf("expected the acknowledged message not sent again, got %d sends", len(node.sent))
+	}
+	if len(tracker.nodes) != 0 {
+		t.Errorf("expected no queue kept, got %d", len(tracker.nodes))
+	}
+}
+
+func TestReliableTrackerOfflineAndExpiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	node := &fakeReliableNode{}
+	tracker := newTestReliableTracker(node, &now)
+	const nodeID = "reliable-offline-node"
+	before := testutil.ToFloat64(monitor.ReliableMessagesDroppedTotal.WithLabelValues(nodeID, "expired"))
+
+	msg := newReliableMessage(nodeID)
+	tracker.add(nodeID, msg)
+	if len(node.sent) != 0 {
+		t.Fatalf("expected no message sent to the offline node")
+	}
+
+	// the message is sent once the node reconnects
+	now = now.Add(30 * time.Second)
+	node.connected, node.acks = true, true
+	tracker.retry()
+	if len(node.sent) != 1 {
+		t.Fatalf("expected the message sent after the node reconnects, got %d", len(node.sent))
+	}
+
+	now = now.Add(30 * time.Second)
+	tracker.retry()
+	if len(tracker.nodes) != 0 {
+		t.Errorf("expected the expired message dropped")
+	}
+	if got := testutil.ToFloat64(monitor.ReliableMessagesDroppedTotal.WithLabelValues(nodeID, "expired")) - before; got != 1 {
+		t.Errorf("expected 1 expired message, got %v", got)
+	}
+	if len(node.events) != 1 {
+		t.Errorf("expected 1 event, got %v", node.events)
+	}
+}
+
+func TestReliableTrackerOverflow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	node := &fakeReliableNode{}
+	tracker := newTestReliableTracker(node, &now)
+	const nodeID = "reliable-overflow-node"
+	before := testutil.ToFloat64(monitor.ReliableMessagesDroppedTotal.WithLabelValues(nodeID, "overflow"))
+
+	first := newReliableMessage(nodeID)
+	tracker.add(nodeID, first)
+	tracker.add(nodeID, newReliableMessage(nodeID))
+	tracker.add(nodeID, newReliableMessage(nodeID))
+
+	q := tracker.nodes[nodeID]
+	if len(q.order) != 2 || len(q.entries) != 2 {
+		t.Fatalf("expected 2 messages kept, got %d", len(q.order))
+	}
+	if _, ok := q.entries[first.GetID()]; ok {
+		t.Errorf("expected the oldest message dropped")
+	}
+	if got := testutil.ToFloat64(monitor.ReliableMessagesDroppedTotal.WithLabelValues(nodeID, "overflow")) - before; got != 1 {
+		t.Errorf("expected 1 overflow, got %v", got)
+	}
+}
+
+func TestReliableTrackerWithoutAcks(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	node := &fakeReliableNode{connected: true}
+	tracker := newTestReliableTracker(node, &now)
+	const nodeID = "reliable-legacy-node"
+
+	tracker.add(nodeID, newReliableMessage(nodeID))
+	now = now.Add(time.Minute)
+	tracker.retry()
+	if len(node.sent) != 1 {
+		t.Errorf("expected the message sent once to the node not acknowledging, got %d", len(node.sent))
+	}
+	if len(tracker.nodes) != 0 || len(node.events) != 0 {
+		t.Errorf("expected the message dropped without expiry")
+	}
+}
@@ -18,6 +18,8 @@ package handler
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/avast/retry-go"
@@ -28,8 +30,11 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/dispatcher"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certinfo"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/session"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/controller"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/mux"
 )
@@ -103,7 +108,8 @@ func (mh *messageHandler) HandleMessage(container *mux.MessageContainer, _ mux.R
 		return
 	}
 
-	klog.V(4).Infof("[messageHandler]get msg from node(%s): %+v", nodeID, container.Message)
+	msgtrace.Infof(4, container.Message, "[messageHandler]get msg from node(%s): %+v", nodeID, container.Message)
+	monitor.ObserveMessageReceived(nodeID, container.Message)
 
 	hubInfo := model.HubInfo{ProjectID: projectID, NodeID: nodeID}
 
@@ -126,11 +132,38 @@ func (mh *messageHandler) HandleConnection(connection conn.Connection) {
 		return
 	}
 
+	if mh.SessionManager.Draining() {
+		klog.Warningf("Fail to serve node %s, cloudhub is stopping", nodeID)
+		return
+	}
+
 	if mh.SessionManager.ReachLimit() {
 		klog.Errorf("Fail to serve node %s, reach node limit", nodeID)
 		return
 	}
 
+	if reason, ok := mh.SessionManager.AdmitNode(nodeID); !ok {
+		if err := conn.CloseWithCode(connection, conn.CloseCodeNotAdmitted, reason); err != nil {
+			klog.Warningf("failed to close the connection of node %s, err: %v", nodeID, err)
+		}
+		return
+	}
+
+	if !mh.SessionManager.AdmitSession(nodeID, connection.RemoteAddr().String()) {
+		if err := conn.CloseWithReason(connection, fmt.Sprintf("node %s already has an active session", nodeID)); err != nil {
+			klog.Warningf("failed to close the duplicate connection of node %s, err: %v", nodeID, err)
+		}
+		return
+	}
+
+	schemaVersion := model.NegotiateSchemaVersion(connection.ConnectionState().Headers.Get(model.SchemaVersion))
+	if reason, ok := mh.SessionManager.AdmitSchemaVersion(nodeID, schemaVersion); !ok {
+		if err := conn.CloseWithReason(connection, reason); err != nil {
+			klog.Warningf("failed to close the connection of node %s, err: %v", nodeID, err)
+		}
+		return
+	}
+
 	nodeInfo := &model.HubInfo{ProjectID: projectID, NodeID: nodeID}
 
 	if err := mh.OnEdgeNodeConnect(nodeInfo, connection); err != nil {
@@ -150,12 +183,18 @@ func (mh *messageHandler) HandleConnection(connection conn.Connection) {
 		// create a node session for each edge node
 		nodeSession := session.NewNodeSession(nodeID, projectID, connection,
 			keepaliveInterval, nodeMessagePool, mh.reliableClient)
+		nodeSession.SetSchemaVersion(schemaVersion)
 		// add node session to the session manager
 		mh.SessionManager.AddSession(nodeSession)
+		if certs := connection.ConnectionState().PeerCertificates; len(certs) > 0 {
+			certinfo.DefaultStore.Record(nodeID, certs[0])
+		}
 		go func() {
 			err := retry.Do(
 				func() error {
-					return controller.UpdateAnnotation(context.TODO(), nodeID)
+					return controller.UpdateAnnotation(context.TODO(), nodeID, map[string]string{
+						model.SchemaVersionAnnotation: strconv.Itoa(schemaVersion),
+					})
 				},
 				retry.Delay(1*time.Second),
 				retry.Attempts(3),
@@ -205,5 +244,6 @@ func (mh *messageHandler) OnReadTransportErr(nodeID, projectID string) {
 		return
 	}
 
+	nodeSession.SetTerminateErr(session.TransportErr)
 	nodeSession.Terminating()
 }
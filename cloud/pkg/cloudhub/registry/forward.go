@@ -0,0 +1,282 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"golang.org/x/crypto/hkdf"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+const (
+	// SignatureHeader carries the HMAC-SHA256 of the timestamp, the node name and the forwarded
+	// message, keyed with the forward key derived from the token signing key shared by the instances
+	SignatureHeader = "X-KubeEdge-Forward-Signature"
+	// TimestampHeader carries the Unix time when the message is forwarded, the forwarded messages
+	// are rejected if it is not within maxForwardSkew of the receiver, so they can't be replayed later
+	TimestampHeader = "X-KubeEdge-Forward-Timestamp"
+
+	// forwardQueueSize is the number of the pending messages forwarded to each instance
+	forwardQueueSize = 1024
+	// maxForwardBodySize is the maximum size of the forwarded message
+	maxForwardBodySize = 16 << 20
+	forwardTimeout     = 10 * time.Second
+	maxForwardSkew     = 30 * time.Second
+
+	// forwardKeyInfo labels the key derived for signing the forwarded messages, so that the key
+	// differs from the token signing key which signs the bootstrap tokens
+	forwardKeyInfo = "kubeedge session-forward"
+)
+
+// DeliverFunc delivers the message to the node connected to the instance itself,
+// it returns false if the node is not connected.
+type DeliverFunc func(nodeID string, msg *model.Message) bool
+
+var deliver DeliverFunc
+
+// SetDeliverFunc sets the function delivering the messages forwarded from the other instances.
+func SetDeliverFunc(f DeliverFunc) {
+	deliver = f
+}
+
+type forwardItem struct {
+	nodeID string
+	msg    *model.Message
+}
+
+// Forwarder forwards the downstream messages to the instances which the nodes are connected to.
+// The messages to each instance are sent in order by a worker of the instance, and the messages
+// failed to forward are passed to the fallback, which dispatches them locally.
+type Forwarder struct {
+	stopCh   <-chan struct{}
+	fallback func(nodeID string, msg *model.Message)
+	rootCAs  func() (*x509.CertPool, error)
+
+	lock   sync.Mutex
+	queues map[string]chan forwardItem
+	pool   *x509.CertPool
+	client *http.Client
+}
+
+// NewForwarder creates the forwarder whose workers stop when the stopCh is closed.
+func NewForwarder(stopCh <-chan struct{}, fallback func(nodeID string, msg *model.Message)) *Forwarder {
+	return &Forwarder{
+		stopCh:   stopCh,
+		fallback: fallback,
+		rootCAs:  hubconfig.Config.CAPool,
+		queues:   make(map[string]chan forwardItem),
+	}
+}
+
+// Forward queues the message to be forwarded to the owner of the node.
+func (f *Forwarder) Forward(owner Instance, nodeID string, msg *model.Message) {
+	f.lock.Lock()
+	queue, ok := f.queues[owner.Address]
+	if !ok {
+		queue = make(chan forwardItem, forwardQueueSize)
+		f.queues[owner.Address] = queue
+		go f.run(owner.Address, queue)
+	}
+	f.lock.Unlock()
+
+	select {
+	case queue <- forwardItem{nodeID: nodeID, msg: msg}:
+	default:
+		klog.Warningf("the forward queue of instance %s is full, dispatch the message %s of node %s locally",
+			owner.Address, msg.GetID(), nodeID)
+		monitor.ForwardedMessagesTotal.WithLabelValues("failed").Inc()
+		f.fallback(nodeID, msg)
+	}
+}
+
+func (f *Forwarder) run(address string, queue chan forwardItem) {
+	for {
+		select {
+		case <-f.stopCh:
+			return
+		case item := <-queue:
+			if err := f.send(address, item.nodeID, item.msg); err != nil {
+				klog.Warningf("failed to forward the message %s of node %s to instance %s, dispatch it locally, err: %v",
+					item.msg.GetID(), item.nodeID, address, err)
+				monitor.ForwardedMessagesTotal.WithLabelValues("failed").Inc()
+				f.fallback(item.nodeID, item.msg)
+				continue
+			}
+			monitor.ForwardedMessagesTotal.WithLabelValues("forwarded").Inc()
+		}
+	}
+}
+
+func (f *Forwarder) send(address, nodeID string, msg *model.Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the message, err: %v", err)
+	}
+	client, err := f.httpClient()
+	if err != nil {
+		return err
+	}
+	url := "https://" + address + strings.Replace(constants.DefaultSessionForwardURL, "{nodename}", nodeID, 1)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	key, err := forwardKey()
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, sign(key, timestamp, nodeID, body))
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// httpClient returns the client verifying the instances with the CA of CloudHub,
+// it is rebuilt when the CA is rotated.
+func (f *Forwarder) httpClient() (*http.Client, error) {
+	pool, err := f.rootCAs()
+	if err != nil {
+		return nil, err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.client == nil || f.pool != pool {
+		f.pool = pool
+		// the instances are served with the same TLS options
+		tlsConfig := &tls.Config{RootCAs: pool}
+		hubconfig.Config.ApplyTLSOptions(tlsConfig)
+		f.client = &http.Client{
+			Timeout: forwardTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+		}
+	}
+	return f.client, nil
+}
+
+// forwardKey derives the key signing the forwarded messages from the token signing key by HKDF.
+func forwardKey() ([]byte, error) {
+	key := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, hubconfig.Config.TokenSigningKey(), nil, []byte(forwardKeyInfo)), key); err != nil {
+		return nil, fmt.Errorf("failed to derive the forward key, err: %v", err)
+	}
+	return key, nil
+}
+
+func sign(key []byte, timestamp, nodeID string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(nodeID))
+	mac.Write([]byte{'\n'})
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// HandleForward delivers the message forwarded from another instance to the node connected
+// to the instance itself. The message must be signed within maxForwardSkew. It responds 409 if
+// the node is not connected, so that the sender dispatches the message by itself.
+func HandleForward(req *restful.Request, resp *restful.Response) {
+	nodeID := req.PathParameter("nodename")
+	if nodeID == "" {
+		resps.ErrorMessage(resp, http.StatusBadRequest, "nodename parameter is required")
+		return
+	}
+	if deliver == nil {
+		resps.ErrorMessage(resp, http.StatusServiceUnavailable, "the session registry is not ready")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Request.Body, maxForwardBodySize+1))
+	if err != nil {
+		resps.ErrorMessage(resp, http.StatusBadRequest, fmt.Sprintf("failed to read the message, err: %v", err))
+		return
+	}
+	if len(body) > maxForwardBodySize {
+		resps.ErrorMessage(resp, http.StatusRequestEntityTooLarge, "the message is too large")
+		return
+	}
+	timestamp := req.HeaderParameter(TimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		resps.ErrorMessage(resp, http.StatusUnauthorized, "invalid forward timestamp")
+		return
+	}
+	if skew := time.Since(time.Unix(unix, 0)); skew > maxForwardSkew || skew < -maxForwardSkew {
+		resps.ErrorMessage(resp, http.StatusUnauthorized, "the forward timestamp is out of the allowed window")
+		return
+	}
+	key, err := forwardKey()
+	if err != nil {
+		resps.ErrorMessage(resp, http.StatusInternalServerError, err.Error())
+		return
+	}
+	signature := req.HeaderParameter(SignatureHeader)
+	expected := sign(key, timestamp, nodeID, body)
+	if signature == "" || !hmac.Equal([]byte(signature), []byte(expected)) {
+		resps.ErrorMessage(resp, http.StatusUnauthorized, "invalid forward signature")
+		return
+	}
+	var msg model.Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		resps.ErrorMessage(resp, http.StatusBadRequest, fmt.Sprintf("failed to decode the message, err: %v", err))
+		return
+	}
+	// the objects are decoded as unstructured, the dispatcher reads their metadata
+	if obj, ok := msg.Content.(map[string]interface{}); ok {
+		if _, ok := obj["metadata"]; ok {
+			msg.Content = &unstructured.Unstructured{Object: obj}
+		}
+	}
+	if !deliver(nodeID, &msg) {
+		resps.ErrorMessage(resp, http.StatusConflict, fmt.Sprintf("node %s is not connected", nodeID))
+		return
+	}
+	resp.WriteHeader(http.StatusAccepted)
+}
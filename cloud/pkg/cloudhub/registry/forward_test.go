@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+// newForwardServer starts the HTTPS server of an instance which delivers the messages to the
+// connected nodes, and trusts its certificate as the CA of CloudHub.
+func newForwardServer(t *testing.T, connected map[string]bool, delivered chan<- *model.Message) *httptest.Server {
+	ca, caKey := hubconfig.Config.Ca, hubconfig.Config.CaKey
+	t.Cleanup(func() { hubconfig.Config.Ca, hubconfig.Config.CaKey = ca, caKey })
+	hubconfig.Config.CaKey = []byte("token signing key")
+
+	SetDeliverFunc(func(nodeID string, msg *model.Message) bool {
+		if !connected[nodeID] {
+			return false
+		}
+		delivered <- msg
+		return true
+	})
+	t.Cleanup(func() { SetDeliverFunc(nil) })
+
+	ws := new(restful.WebService)
+	ws.Route(ws.POST(constants.DefaultSessionForwardURL).To(HandleForward))
+	container := restful.NewContainer()
+	container.Add(ws)
+	srv := httptest.NewTLSServer(container)
+	t.Cleanup(srv.Close)
+	hubconfig.Config.Ca = srv.Certificate().Raw
+	return srv
+}
+
+func TestHandleForward(t *testing.T) {
+	srv := newForwardServer(t, map[string]bool{"edge-node": true}, make(chan *model.Message, 1))
+	body := `{"header":{"msg_id":"1"},"route":{"resource":"node/edge-node/default/pod/foo","operation":"update"},"content":{"metadata":{"name":"foo"}}}`
+
+	key, err := forwardKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	expired := strconv.FormatInt(time.Now().Add(-2*maxForwardSkew).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		nodeID    string
+		timestamp string
+		signature string
+		want      int
+	}{
+		{
+			name:      "delivered",
+			nodeID:    "edge-node",
+			timestamp: now,
+			signature: sign(key, now, "edge-node", []byte(body)),
+			want:      http.StatusAccepted,
+		},
+		{
+			name:      "missing signature",
+			nodeID:    "edge-node",
+			timestamp: now,
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "signature of another node",
+			nodeID:    "edge-node",
+			timestamp: now,
+			signature: sign(key, now, "other-node", []byte(body)),
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "signed with the token signing key",
+			nodeID:    "edge-node",
+			timestamp: now,
+			signature: sign(hubconfig.Config.TokenSigningKey(), now, "edge-node", []byte(body)),
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "missing timestamp",
+			nodeID:    "edge-node",
+			signature: sign(key, "", "edge-node", []byte(body)),
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "replayed after the window",
+			nodeID:    "edge-node",
+			timestamp: expired,
+			signature: sign(key, expired, "edge-node", []byte(body)),
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "signature of another timestamp",
+			nodeID:    "edge-node",
+			timestamp: now,
+			signature: sign(key, expired, "edge-node", []byte(body)),
+			want:      http.StatusUnauthorized,
+		},
+		{
+			name:      "node not connected",
+			nodeID:    "other-node",
+			timestamp: now,
+			signature: sign(key, now, "other-node", []byte(body)),
+			want:      http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := srv.URL + strings.Replace(constants.DefaultSessionForwardURL, "{nodename}", tt.nodeID, 1)
+			req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.timestamp != "" {
+				req.Header.Set(TimestampHeader, tt.timestamp)
+			}
+			if tt.signature != "" {
+				req.Header.Set(SignatureHeader, tt.signature)
+			}
+			resp, err := srv.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestForwarder(t *testing.T) {
+	delivered := make(chan *model.Message, 1)
+	srv := newForwardServer(t, map[string]bool{"edge-node": true}, delivered)
+	owner := Instance{Identity: "instance-b", Address: strings.TrimPrefix(srv.URL, "https://")}
+
+	fallback := make(chan string, 1)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	f := NewForwarder(stopCh, func(nodeID string, _ *model.Message) { fallback <- nodeID })
+
+	msg := model.NewMessage("").SetResourceOperation("node/edge-node/default/pod/foo", model.UpdateOperation)
+	f.Forward(owner, "edge-node", msg)
+	select {
+	case got := <-delivered:
+		if got.GetID() != msg.GetID() || got.GetResource() != msg.GetResource() {
+			t.Errorf("unexpected message delivered: %+v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the message is not delivered")
+	}
+
+	// the node disconnected from the owner, the message is dispatched locally
+	f.Forward(owner, "other-node", model.NewMessage("").SetResourceOperation("node/other-node/default/pod/foo", model.UpdateOperation))
+	select {
+	case nodeID := <-fallback:
+		if nodeID != "other-node" {
+			t.Errorf("unexpected node %s dispatched locally", nodeID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the message is not dispatched locally")
+	}
+}
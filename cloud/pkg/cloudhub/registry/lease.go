@@ -0,0 +1,280 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	coordinationlisters "k8s.io/client-go/listers/coordination/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+)
+
+const (
+	// LeaseNamePrefix is the prefix of the names of the session leases, followed by the node name
+	LeaseNamePrefix = "cloudhub-session-"
+	// SessionLabel labels the session leases, the instances only watch the leases with it
+	SessionLabel = "cloudhub.kubeedge.io/session"
+	// AddressAnnotation is the annotation of the session lease which publishes the advertised
+	// HTTPS address of the holder
+	AddressAnnotation = "cloudhub.kubeedge.io/session-address"
+)
+
+// LeaseRegistry is the Registry backed by a lease per edge node. The instance which the node
+// is connected to holds the lease and renews it, the lease expires if the instance stops.
+type LeaseRegistry struct {
+	self          Instance
+	client        kubernetes.Interface
+	namespace     string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+	factory       informers.SharedInformerFactory
+	lister        coordinationlisters.LeaseNamespaceLister
+	now           func() time.Time
+
+	lock sync.Mutex
+	// nodes are the nodes connected to the instance itself
+	nodes map[string]struct{}
+	// queue is the nodes whose leases are to be acquired or released
+	queue workqueue.RateLimitingInterface
+}
+
+var _ Registry = (*LeaseRegistry)(nil)
+
+// NewLeaseRegistry creates the registry of the instance itself, whose identity is the hostname
+// with a random suffix and whose address is the first advertised address with the HTTPS port.
+func NewLeaseRegistry(client kubernetes.Interface, c *v1alpha1.CloudHubSessionRegistry) (*LeaseRegistry, error) {
+	if c == nil {
+		return nil, fmt.Errorf("the config of the session registry is required")
+	}
+	if len(hubconfig.Config.AdvertiseAddress) == 0 {
+		return nil, fmt.Errorf("advertiseAddress is required by the session registry")
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the hostname, err: %v", err)
+	}
+	self := Instance{
+		Identity: hostname + "_" + string(uuid.NewUUID()),
+		Address:  net.JoinHostPort(hubconfig.Config.AdvertiseAddress[0], strconv.Itoa(int(hubconfig.Config.HTTPS.Port))),
+	}
+	return newLeaseRegistry(client, c, self), nil
+}
+
+func newLeaseRegistry(client kubernetes.Interface, c *v1alpha1.CloudHubSessionRegistry, self Instance) *LeaseRegistry {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(c.LeaseNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = labels.Set{SessionLabel: "true"}.String()
+		}))
+	return &LeaseRegistry{
+		self:          self,
+		client:        client,
+		namespace:     c.LeaseNamespace,
+		leaseDuration: time.Duration(c.LeaseDuration) * time.Second,
+		renewInterval: time.Duration(c.RenewInterval) * time.Second,
+		factory:       factory,
+		lister:        factory.Coordination().V1().Leases().Lister().Leases(c.LeaseNamespace),
+		now:           time.Now,
+		nodes:         make(map[string]struct{}),
+		queue:         workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+}
+
+// Start watches the session leases, and keeps the leases of the nodes connected
+// to the instance itself until the context is done.
+func (r *LeaseRegistry) Start(ctx context.Context) error {
+	informer := r.factory.Coordination().V1().Leases().Informer()
+	r.factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync the session leases")
+	}
+	go func() {
+		<-ctx.Done()
+		r.queue.ShutDown()
+	}()
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		for r.processNext(ctx) {
+		}
+	}, time.Second)
+	go wait.UntilWithContext(ctx, func(context.Context) { r.renew() }, r.renewInterval)
+	return nil
+}
+
+func (r *LeaseRegistry) Self() Instance {
+	return r.self
+}
+
+func (r *LeaseRegistry) Register(nodeID string) {
+	r.lock.Lock()
+	r.nodes[nodeID] = struct{}{}
+	r.lock.Unlock()
+	r.queue.Add(nodeID)
+}
+
+func (r *LeaseRegistry) Unregister(nodeID string) {
+	r.lock.Lock()
+	delete(r.nodes, nodeID)
+	r.lock.Unlock()
+	r.queue.Add(nodeID)
+}
+
+func (r *LeaseRegistry) Owner(nodeID string) (Instance, bool) {
+	lease, err := r.lister.Get(LeaseNamePrefix + nodeID)
+	if err != nil {
+		return Instance{}, false
+	}
+	if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return Instance{}, false
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	if !r.now().Before(expiry) {
+		return Instance{}, false
+	}
+	address := lease.Annotations[AddressAnnotation]
+	if address == "" {
+		return Instance{}, false
+	}
+	return Instance{Identity: *lease.Spec.HolderIdentity, Address: address}, true
+}
+
+// renew enqueues the nodes connected to the instance itself to renew their leases.
+func (r *LeaseRegistry) renew() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for nodeID := range r.nodes {
+		r.queue.Add(nodeID)
+	}
+}
+
+func (r *LeaseRegistry) connected(nodeID string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, ok := r.nodes[nodeID]
+	return ok
+}
+
+func (r *LeaseRegistry) processNext(ctx context.Context) bool {
+	key, quit := r.queue.Get()
+	if quit {
+		return false
+	}
+	defer r.queue.Done(key)
+
+	nodeID := key.(string)
+	var err error
+	if r.connected(nodeID) {
+		err = r.acquire(ctx, nodeID)
+	} else {
+		err = r.release(ctx, nodeID)
+	}
+	if err != nil {
+		klog.Errorf("failed to sync the session lease of node %s, err: %v", nodeID, err)
+		r.queue.AddRateLimited(key)
+		return true
+	}
+	r.queue.Forget(key)
+	return true
+}
+
+// acquire creates or renews the lease of the node. The lease is taken over from the other
+// instance, since the node connects to the instance itself later than to that one.
+func (r *LeaseRegistry) acquire(ctx context.Context, nodeID string) error {
+	now := metav1.NewMicroTime(r.now())
+	leases := r.client.CoordinationV1().Leases(r.namespace)
+	lease, err := leases.Get(ctx, LeaseNamePrefix+nodeID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        LeaseNamePrefix + nodeID,
+				Namespace:   r.namespace,
+				Labels:      map[string]string{SessionLabel: "true"},
+				Annotations: map[string]string{AddressAnnotation: r.self.Address},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(r.self.Identity),
+				LeaseDurationSeconds: ptr.To(int32(r.leaseDuration / time.Second)),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != r.self.Identity {
+		lease.Spec.HolderIdentity = ptr.To(r.self.Identity)
+		lease.Spec.AcquireTime = &now
+	}
+	if lease.Labels == nil {
+		lease.Labels = map[string]string{}
+	}
+	lease.Labels[SessionLabel] = "true"
+	if lease.Annotations == nil {
+		lease.Annotations = map[string]string{}
+	}
+	lease.Annotations[AddressAnnotation] = r.self.Address
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(r.leaseDuration / time.Second))
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// release deletes the lease of the node if it is still held by the instance itself,
+// the lease taken over by another instance is kept.
+func (r *LeaseRegistry) release(ctx context.Context, nodeID string) error {
+	leases := r.client.CoordinationV1().Leases(r.namespace)
+	lease, err := leases.Get(ctx, LeaseNamePrefix+nodeID, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != r.self.Identity {
+		return nil
+	}
+	err = leases.Delete(ctx, lease.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+	})
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+)
+
+func newTestLeaseRegistry(t *testing.T, ctx context.Context, client *fake.Clientset, identity string) *LeaseRegistry {
+	r := newLeaseRegistry(client, &v1alpha1.CloudHubSessionRegistry{
+		LeaseNamespace: "kubeedge",
+		LeaseDuration:  40,
+		RenewInterval:  10,
+	}, Instance{Identity: identity, Address: identity + ":10002"})
+	if err := r.Start(ctx); err != nil {
+		t.Fatalf("failed to start the registry: %v", err)
+	}
+	return r
+}
+
+func waitForOwner(t *testing.T, r *LeaseRegistry, nodeID, want string) {
+	t.Helper()
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+		func(context.Context) (bool, error) {
+			owner, ok := r.Owner(nodeID)
+			if want == "" {
+				return !ok, nil
+			}
+			return ok && owner.Identity == want && owner.Address == want+":10002", nil
+		})
+	if err != nil {
+		t.Fatalf("the owner of node %s is not %q", nodeID, want)
+	}
+}
+
+func TestLeaseRegistry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := fake.NewSimpleClientset()
+	a := newTestLeaseRegistry(t, ctx, client, "instance-a")
+	b := newTestLeaseRegistry(t, ctx, client, "instance-b")
+
+	a.Register("edge-node")
+	waitForOwner(t, b, "edge-node", "instance-a")
+	waitForOwner(t, a, "edge-node", "instance-a")
+
+	// the node reconnects to instance b, the lease is taken over
+	b.Register("edge-node")
+	waitForOwner(t, a, "edge-node", "instance-b")
+
+	// instance a must not release the lease taken over by instance b
+	a.Unregister("edge-node")
+	time.Sleep(100 * time.Millisecond)
+	waitForOwner(t, a, "edge-node", "instance-b")
+
+	b.Unregister("edge-node")
+	waitForOwner(t, a, "edge-node", "")
+	if _, err := client.CoordinationV1().Leases("kubeedge").Get(ctx, LeaseNamePrefix+"edge-node", metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the lease to be deleted")
+	}
+}
+
+func TestLeaseRegistryExpiredOwner(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := fake.NewSimpleClientset()
+	a := newTestLeaseRegistry(t, ctx, client, "instance-a")
+
+	a.Register("edge-node")
+	waitForOwner(t, a, "edge-node", "instance-a")
+
+	// the instance stopped renewing the lease
+	a.now = func() time.Time { return time.Now().Add(time.Minute) }
+	if _, ok := a.Owner("edge-node"); ok {
+		t.Errorf("expected no owner of the expired lease")
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+// Instance is a CloudHub instance serving the sessions of the edge nodes.
+type Instance struct {
+	// Identity is the unique identity of the instance
+	Identity string
+	// Address is the advertised HTTPS address of the instance, which the downstream
+	// messages of the nodes connected to the instance are forwarded to
+	Address string
+}
+
+// Registry records the CloudHub instance which each edge node is connected to, so that
+// multiple instances can run behind a load balancer.
+type Registry interface {
+	// Self returns the instance itself
+	Self() Instance
+
+	// Register records that the node is connected to the instance itself
+	Register(nodeID string)
+
+	// Unregister records that the node is disconnected from the instance itself
+	Unregister(nodeID string)
+
+	// Owner returns the instance which the node is connected to,
+	// it returns false if the node is not connected to any instance
+	Owner(nodeID string) (Instance, bool)
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certreload reloads the server certificate and the client CAs of the CloudHub servers
+// when their files change, so they are renewed without restarting CloudCore.
+package certreload
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+const (
+	kindServerCert = "server-cert"
+	kindClientCA   = "client-ca"
+)
+
+var (
+	// ServerCert is the server certificate of the CloudHub servers, set by Init
+	ServerCert *KeyPair
+	// ClientCAs are the CAs verifying the client certificates of the CloudHub servers, set by Init
+	ClientCAs *CAPool
+)
+
+// Init loads the server certificate and the client CAs of CloudHub, and reloads them when the
+// files they are loaded from change until the ctx is done. The ones not loaded from files, e.g.
+// from the Secrets, are never reloaded.
+func Init(ctx context.Context) error {
+	cfg := &hubconfig.Config
+	cert, err := tls.X509KeyPair(
+		pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: cfg.Cert}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: cfg.Key}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create a x509 tls certificate, err: %v", err)
+	}
+	var certFile, keyFile string
+	if fileExists(cfg.TLSCertFile) && fileExists(cfg.TLSPrivateKeyFile) {
+		certFile, keyFile = cfg.TLSCertFile, cfg.TLSPrivateKeyFile
+	}
+	ServerCert = NewKeyPair(cert, certFile, keyFile)
+
+	var static [][]byte
+	var caFiles []string
+	if fileExists(cfg.TLSCAFile) {
+		caFiles = append(caFiles, cfg.TLSCAFile)
+	} else if cfg.Ca != nil {
+		static = append(static, cfg.Ca)
+	}
+	// the offline root CA verifies the client certificates chaining through the intermediate CAs
+	if cfg.TLSRootCAFile != "" {
+		caFiles = append(caFiles, cfg.TLSRootCAFile)
+	}
+	if cfg.TLSAdditionalCAFile != "" {
+		caFiles = append(caFiles, cfg.TLSAdditionalCAFile)
+	} else {
+		static = append(static, cfg.AdditionalCAs...)
+	}
+	if ClientCAs, err = NewCAPool(static, caFiles...); err != nil {
+		return err
+	}
+
+	if certFile != "" {
+		Watch(ctx, pollInterval, logError(ServerCert.Reload), certFile, keyFile)
+	}
+	if len(caFiles) > 0 {
+		Watch(ctx, pollInterval, logError(ClientCAs.Reload), caFiles...)
+	}
+	return nil
+}
+
+// KeyPair holds a server certificate, which is reloaded from the certificate and key files when
+// they change. The new handshakes are served with the reloaded certificate, the established
+// connections keep their sessions.
+type KeyPair struct {
+	certFile, keyFile string
+	cert              atomic.Pointer[tls.Certificate]
+
+	// mu serializes the reloads, certPEM and keyPEM are the contents of the files loaded last
+	mu      sync.Mutex
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// NewKeyPair returns a KeyPair serving the cert, which is reloaded from the files if they are set.
+func NewKeyPair(cert tls.Certificate, certFile, keyFile string) *KeyPair {
+	k := &KeyPair{certFile: certFile, keyFile: keyFile}
+	k.cert.Store(&cert)
+	if certFile != "" && keyFile != "" {
+		k.certPEM, _ = os.ReadFile(certFile)
+		k.keyPEM, _ = os.ReadFile(keyFile)
+	}
+	return k
+}
+
+// GetCertificate returns the current certificate, it is used as tls.Config.GetCertificate.
+func (k *KeyPair) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return k.cert.Load(), nil
+}
+
+// Reload reloads the certificate if the files changed. The previous certificate is still served
+// if the files fail to parse, e.g. the certificate is replaced but the key is not yet.
+func (k *KeyPair) Reload() error {
+	if k.certFile == "" || k.keyFile == "" {
+		return nil
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	certPEM, err := os.ReadFile(k.certFile)
+	if err != nil {
+		return recordReload(kindServerCert, fmt.Errorf("failed to read the certificate file %s, err: %v", k.certFile, err))
+	}
+	keyPEM, err := os.ReadFile(k.keyFile)
+	if err != nil {
+		return recordReload(kindServerCert, fmt.Errorf("failed to read the private key file %s, err: %v", k.keyFile, err))
+	}
+	if bytes.Equal(certPEM, k.certPEM) && bytes.Equal(keyPEM, k.keyPEM) {
+		return nil
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return recordReload(kindServerCert, fmt.Errorf("failed to parse the certificate file %s and the private key file %s, err: %v",
+			k.certFile, k.keyFile, err))
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return recordReload(kindServerCert, fmt.Errorf("failed to parse the certificate file %s, err: %v", k.certFile, err))
+	}
+	cert.Leaf = leaf
+	k.certPEM, k.keyPEM = certPEM, keyPEM
+	k.cert.Store(&cert)
+	klog.Infof("reloaded the server certificate %s, it expires at %v", leaf.SerialNumber, leaf.NotAfter)
+	return recordReload(kindServerCert, nil)
+}
+
+// CAPool holds a pool of CAs, which are reloaded from the CA files when they change. The CAs
+// not loaded from files, e.g. from the Secrets, are kept in the pool.
+type CAPool struct {
+	static [][]byte
+	files  []string
+	pool   atomic.Pointer[x509.CertPool]
+
+	// mu serializes the reloads, contents are the contents of the files loaded last
+	mu       sync.Mutex
+	contents [][]byte
+}
+
+// NewCAPool returns a CAPool of the CAs in DER and the CAs in the files.
+func NewCAPool(static [][]byte, files ...string) (*CAPool, error) {
+	p := &CAPool{static: static, files: files}
+	contents, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+	pool, err := p.build(contents)
+	if err != nil {
+		return nil, err
+	}
+	p.contents = contents
+	p.pool.Store(pool)
+	return p, nil
+}
+
+// Pool returns the current pool.
+func (p *CAPool) Pool() *x509.CertPool {
+	return p.pool.Load()
+}
+
+// Reload reloads the CAs if the files changed. The previous pool is still used if the files
+// fail to parse.
+func (p *CAPool) Reload() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	contents, err := p.read()
+	if err != nil {
+		return recordReload(kindClientCA, err)
+	}
+	if slices.EqualFunc(contents, p.contents, bytes.Equal) {
+		return nil
+	}
+	pool, err := p.build(contents)
+	if err != nil {
+		return recordReload(kindClientCA, err)
+	}
+	p.contents = contents
+	p.pool.Store(pool)
+	klog.Infof("reloaded the client CAs from the files %v", p.files)
+	return recordReload(kindClientCA, nil)
+}
+
+// read reads the contents of the CA files.
+func (p *CAPool) read() ([][]byte, error) {
+	contents := make([][]byte, 0, len(p.files))
+	for _, file := range p.files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA file %s, err: %v", file, err)
+		}
+		contents = append(contents, content)
+	}
+	return contents, nil
+}
+
+// build builds the pool of the static CAs and the CAs in the contents of the files.
+func (p *CAPool) build(contents [][]byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, der := range p.static {
+		ca, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the CA certificate, err: %v", err)
+		}
+		pool.AddCert(ca)
+	}
+	for i, content := range contents {
+		cas, err := certutil.ParseCertsPEM(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the CA file %s, err: %v", p.files[i], err)
+		}
+		for _, ca := range cas {
+			pool.AddCert(ca)
+		}
+	}
+	return pool, nil
+}
+
+// recordReload counts the reload of the kind by the result, and returns the error.
+func recordReload(kind string, err error) error {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	monitor.CertReloadsTotal.WithLabelValues(kind, result).Inc()
+	return err
+}
+
+// logError returns a function calling reload, which logs the error of it.
+func logError(reload func() error) func() {
+	return func() {
+		if err := reload(); err != nil {
+			klog.Errorf("failed to reload, the previous one is still used, err: %v", err)
+		}
+	}
+}
+
+// fileExists returns whether the file is set and exists.
+func fileExists(file string) bool {
+	if file == "" {
+		return false
+	}
+	_, err := os.Stat(file)
+	return !errors.Is(err, os.ErrNotExist)
+}
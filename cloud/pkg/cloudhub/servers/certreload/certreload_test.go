@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certreload
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	certutil "k8s.io/client-go/util/cert"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// caPEM returns the certificate of the CA in PEM
+func caPEM(ca *testutil.CA) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: ca.Cert.Raw})
+}
+
+// issue signs a server certificate for 127.0.0.1 by the CA, and returns it with its key in PEM
+func issue(t *testing.T, ca *testutil.CA) (certPEM, keyPEM []byte) {
+	key, err := certs.GenPrivateKey(certs.KeyTypeECDSAP256)
+	require.NoError(t, err)
+	signer, err := key.Signer()
+	require.NoError(t, err)
+	block, err := certs.GetHandler(certs.HandlerTypeX509).SignCerts(context.TODO(), certs.SignCertsOptionsWithCA(certutil.Config{
+		CommonName: "kubeedge",
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		AltNames:   certutil.AltNames{IPs: []net.IP{net.ParseIP("127.0.0.1")}},
+	}, ca.Cert.Raw, ca.Key.DER(), signer.Public(), time.Hour))
+	require.NoError(t, err)
+	return pem.EncodeToMemory(block), key.PEM()
+}
+
+func writeFile(t *testing.T, file string, content []byte) {
+	require.NoError(t, os.WriteFile(file, content, 0600))
+}
+
+func reloads(kind, result string) float64 {
+	return promtestutil.ToFloat64(monitor.CertReloadsTotal.WithLabelValues(kind, result))
+}
+
+func TestKeyPairReload(t *testing.T) {
+	ca := testutil.NewCA(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	certPEM, keyPEM := issue(t, ca)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	k := NewKeyPair(cert, certFile, keyFile)
+	current := func() []byte {
+		c, err := k.GetCertificate(nil)
+		require.NoError(t, err)
+		return c.Certificate[0]
+	}
+	successes, failures := reloads(kindServerCert, "success"), reloads(kindServerCert, "failure")
+
+	// the unchanged files are not reloaded
+	require.NoError(t, k.Reload())
+	require.Equal(t, cert.Certificate[0], current())
+	require.Equal(t, successes, reloads(kindServerCert, "success"))
+
+	newCertPEM, newKeyPEM := issue(t, ca)
+	// the certificate replaced without its key fails to parse, the previous one is still served
+	writeFile(t, certFile, newCertPEM)
+	require.ErrorContains(t, k.Reload(), "failed to parse the certificate file")
+	require.Equal(t, cert.Certificate[0], current())
+	require.Equal(t, failures+1, reloads(kindServerCert, "failure"))
+
+	writeFile(t, keyFile, newKeyPEM)
+	require.NoError(t, k.Reload())
+	newCert, err := tls.X509KeyPair(newCertPEM, newKeyPEM)
+	require.NoError(t, err)
+	require.Equal(t, newCert.Certificate[0], current())
+	require.Equal(t, successes+1, reloads(kindServerCert, "success"))
+
+	// the certificate not loaded from files is never reloaded
+	static := NewKeyPair(cert, "", "")
+	require.NoError(t, static.Reload())
+	c, err := static.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, cert.Certificate[0], c.Certificate[0])
+}
+
+func TestCAPoolReload(t *testing.T) {
+	ca, other, additional := testutil.NewCA(t), testutil.NewCA(t), testutil.NewCA(t)
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	writeFile(t, caFile, caPEM(ca))
+	p, err := NewCAPool([][]byte{additional.Cert.Raw}, caFile)
+	require.NoError(t, err)
+	trusts := func(c *testutil.CA) bool {
+		_, err := c.Cert.Verify(x509.VerifyOptions{Roots: p.Pool()})
+		return err == nil
+	}
+	require.True(t, trusts(ca))
+	require.True(t, trusts(additional))
+	require.False(t, trusts(other))
+	failures := reloads(kindClientCA, "failure")
+
+	writeFile(t, caFile, []byte("invalid"))
+	require.ErrorContains(t, p.Reload(), "failed to parse the CA file")
+	require.True(t, trusts(ca))
+	require.Equal(t, failures+1, reloads(kindClientCA, "failure"))
+
+	writeFile(t, caFile, append(caPEM(ca), caPEM(other)...))
+	require.NoError(t, p.Reload())
+	require.True(t, trusts(ca))
+	require.True(t, trusts(other))
+	require.True(t, trusts(additional))
+
+	_, err = NewCAPool(nil, filepath.Join(dir, "missing.crt"))
+	require.ErrorContains(t, err, "failed to read the CA file")
+}
+
+func TestReloadDuringHandshakes(t *testing.T) {
+	ca := testutil.NewCA(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	certPEM, keyPEM := issue(t, ca)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	k := NewKeyPair(cert, certFile, keyFile)
+
+	// httptest.Server serves its own certificate instead of calling GetCertificate
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &http.Server{Handler: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})}
+	go func() { _ = srv.Serve(tls.NewListener(ln, &tls.Config{GetCertificate: k.GetCertificate})) }()
+	defer srv.Close()
+	url := "https://" + ln.Addr().String()
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	// served returns the certificate served to the client, which keeps its connection alive
+	served := func(client *http.Client) []byte {
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.TLS.PeerCertificates[0].Raw
+	}
+	newClient := func() *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+	}
+
+	established := newClient()
+	require.Equal(t, cert.Certificate[0], served(established))
+
+	newCertPEM, newKeyPEM := issue(t, ca)
+	writeFile(t, certFile, newCertPEM)
+	writeFile(t, keyFile, newKeyPEM)
+	require.NoError(t, k.Reload())
+	newCert, err := tls.X509KeyPair(newCertPEM, newKeyPEM)
+	require.NoError(t, err)
+
+	// the new handshakes pick up the new certificate, the established connection keeps its session
+	require.Equal(t, newCert.Certificate[0], served(newClient()))
+	require.Equal(t, cert.Certificate[0], served(established))
+}
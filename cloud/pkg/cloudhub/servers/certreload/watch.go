@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certreload
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// pollInterval is the interval of checking the files if fsnotify is not available
+	pollInterval = time.Minute
+	// reloadDelay collects the events of replacing several files, e.g. a certificate and its key,
+	// into a single reload
+	reloadDelay = 500 * time.Millisecond
+)
+
+// Watch calls reload when the files change until the ctx is done. The directories of the files are
+// watched rather than the files, so the files replaced by renaming are noticed too, e.g. the files
+// of a Secret volume, which are updated by swapping the symbolic link of the directory. The files
+// are polled every interval instead if fsnotify is not available.
+func Watch(ctx context.Context, interval time.Duration, reload func(), files ...string) {
+	watcher, err := newWatcher(files)
+	if err != nil {
+		klog.Warningf("failed to watch the files %v, poll them every %v instead, err: %v", files, interval, err)
+		go poll(ctx, interval, reload)
+		return
+	}
+	go func() {
+		defer watcher.Close()
+		timer := time.NewTimer(reloadDelay)
+		timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				klog.V(4).Infof("file event %s, reload in %v", event, reloadDelay)
+				timer.Reset(reloadDelay)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				klog.Warningf("failed to watch the files %v, err: %v", files, err)
+			case <-timer.C:
+				reload()
+			}
+		}
+	}()
+}
+
+// newWatcher returns a fsnotify watcher of the directories of the files.
+func newWatcher(files []string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	watched := make(map[string]bool)
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if watched[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+		watched[dir] = true
+	}
+	return watcher, nil
+}
+
+// poll calls reload every interval until the ctx is done.
+func poll(ctx context.Context, interval time.Duration, reload func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reload()
+		}
+	}
+}
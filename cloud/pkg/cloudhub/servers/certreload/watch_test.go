@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certreload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	newReload := func() (func(), chan struct{}) {
+		reloaded := make(chan struct{}, 10)
+		return func() { reloaded <- struct{}{} }, reloaded
+	}
+
+	t.Run("fsnotify", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		dir := t.TempDir()
+		file := filepath.Join(dir, "server.crt")
+		reload, reloaded := newReload()
+		Watch(ctx, time.Hour, reload, file)
+
+		// the file is replaced by renaming, and the events are collected into a single reload
+		tmp := filepath.Join(dir, "server.crt.tmp")
+		require.NoError(t, os.WriteFile(tmp, []byte("new"), 0600))
+		require.NoError(t, os.Rename(tmp, file))
+		select {
+		case <-reloaded:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the file is not reloaded")
+		}
+		select {
+		case <-reloaded:
+			t.Fatal("the file is reloaded more than once")
+		case <-time.After(2 * reloadDelay):
+		}
+	})
+
+	t.Run("polling", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		reload, reloaded := newReload()
+		// the directory does not exist, so it can not be watched
+		Watch(ctx, 10*time.Millisecond, reload, filepath.Join(t.TempDir(), "missing", "server.crt"))
+		for i := 0; i < 2; i++ {
+			select {
+			case <-reloaded:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the file is not polled")
+			}
+		}
+	})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// Filter authenticates the bearer token of admin requests via TokenReview, and authorizes
+// the request via SubjectAccessReview on the non-resource URL of the request.
+func Filter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	r := req.Request
+	if code, err := authorize(r.Context(), r); err != nil {
+		klog.Errorf("admin request %s %s is denied, err: %v", r.Method, r.URL.Path, err)
+		resps.Error(resp, code, err)
+		return
+	}
+	chain.ProcessFilter(req, resp)
+}
+
+func authorize(ctx context.Context, r *http.Request) (int, error) {
+	bearerToken := strings.Split(r.Header.Get(types.HeaderAuthorization), " ")
+	if len(bearerToken) != 2 || bearerToken[1] == "" {
+		return http.StatusUnauthorized, fmt.Errorf("bearer token is required")
+	}
+	kubeClient := client.GetKubeClient()
+	review, err := kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: bearerToken[1]},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to review the token, err: %v", err)
+	}
+	if !review.Status.Authenticated {
+		return http.StatusUnauthorized, fmt.Errorf("the token is not authenticated, err: %s", review.Status.Error)
+	}
+
+	u := review.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(u.Extra))
+	for k, v := range u.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar, err := kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   u.Username,
+			Groups: u.Groups,
+			UID:    u.UID,
+			Extra:  extra,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: r.URL.Path,
+				Verb: verb(r.Method),
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to review the access, err: %v", err)
+	}
+	if !sar.Status.Allowed {
+		return http.StatusForbidden, fmt.Errorf("user %s is not allowed to %s %s", u.Username,
+			verb(r.Method), r.URL.Path)
+	}
+	return http.StatusOK, nil
+}
+
+func verb(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return "get"
+	}
+}
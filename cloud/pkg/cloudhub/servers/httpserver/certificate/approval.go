@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/preregistration"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// ReasonNodeNotApproved is the reason code of the response and the event when a certificate
+// is requested with a token for a node name that is not approved.
+const ReasonNodeNotApproved = types.ReasonNodeNotApproved
+
+var errNodeNotApproved = errors.New(ReasonNodeNotApproved)
+
+// nodeApprover checks that the node names are approved before their certificates are signed,
+// the results of the Nodes are cached for ttl.
+type nodeApprover struct {
+	key   string
+	value string
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]approval
+}
+
+type approval struct {
+	approved bool
+	expires  time.Time
+}
+
+// defaultNodeApprover is nil if the approval of the node names is disabled.
+var defaultNodeApprover *nodeApprover
+
+// InitNodeApproval initializes the approval of the node names from the config of CloudHub.
+func InitNodeApproval() {
+	a := hubconfig.Config.NodeApproval
+	if a == nil || !a.Enable {
+		return
+	}
+	defaultNodeApprover = &nodeApprover{
+		key:   a.Key,
+		value: a.Value,
+		ttl:   time.Duration(a.CacheTTL) * time.Second,
+		now:   time.Now,
+		cache: make(map[string]approval),
+	}
+}
+
+// check returns an error if the node is not approved. A node is approved if it is pre-registered,
+// or if its Node carries the label or the annotation of the key with the value. The rejection is
+// recorded as an event of the node.
+func (a *nodeApprover) check(ctx context.Context, nodeName, clientIP string) (int, error) {
+	if a == nil {
+		return http.StatusOK, nil
+	}
+	registered, err := preregistration.DefaultStore.Has(ctx, nodeName)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to check the pre-registration of edgenode %s, err: %v", nodeName, err)
+	}
+	if registered {
+		return http.StatusOK, nil
+	}
+	approved, err := a.approved(ctx, nodeName)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to check the approval of edgenode %s, err: %v", nodeName, err)
+	}
+	if approved {
+		return http.StatusOK, nil
+	}
+	klog.InfoS("Audit unapproved enrollment", "node", nodeName, "clientIP", clientIP)
+	recordEnrollmentEvent(ctx, nodeName, ReasonNodeNotApproved, fmt.Sprintf(
+		"certificate requested from %s for the node which is not approved, the Node must carry the label or the annotation %s=%s",
+		clientIP, a.key, a.value))
+	return http.StatusForbidden, fmt.Errorf("%w: edgenode %s is not approved, the Node must exist with the label or the annotation %s=%s",
+		errNodeNotApproved, nodeName, a.key, a.value)
+}
+
+// approved reports whether the Node of the name carries the label or the annotation, the errors
+// of the apiserver are not cached.
+func (a *nodeApprover) approved(ctx context.Context, nodeName string) (bool, error) {
+	now := a.now()
+	a.mu.Lock()
+	if c, ok := a.cache[nodeName]; ok && now.Before(c.expires) {
+		a.mu.Unlock()
+		return c.approved, nil
+	}
+	a.mu.Unlock()
+
+	var approved bool
+	node, err := client.GetKubeClient().CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return false, err
+	default:
+		approved = hasValue(node.Labels, a.key, a.value) || hasValue(node.Annotations, a.key, a.value)
+	}
+	if a.ttl > 0 {
+		a.mu.Lock()
+		// the expired entries are dropped so that the names of the rejected requests do not pile up
+		for name, c := range a.cache {
+			if !now.Before(c.expires) {
+				delete(a.cache, name)
+			}
+		}
+		a.cache[nodeName] = approval{approved: approved, expires: now.Add(a.ttl)}
+		a.mu.Unlock()
+	}
+	return approved, nil
+}
+
+func hasValue(m map[string]string, key, value string) bool {
+	v, ok := m[key]
+	return ok && v == value
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/preregistration"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+)
+
+func TestEdgeCoreClientCertNodeApproval(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+		return kubeClient
+	})
+	defer patches.Reset()
+	origin := preregistration.DefaultStore
+	preregistration.DefaultStore = preregistration.NewStore(kubeClient)
+	defer func() { preregistration.DefaultStore = origin }()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	defaultNodeApprover = &nodeApprover{
+		key:   "node.kubeedge.io/approved",
+		value: "true",
+		ttl:   30 * time.Second,
+		now:   func() time.Time { return now },
+		cache: make(map[string]approval),
+	}
+	t.Cleanup(func() { defaultNodeApprover = nil })
+
+	ca := testutil.NewCA(t)
+	sign := func(node *testutil.Node) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	// the node does not exist
+	node := ca.NewNode(t, "testnode")
+	resp := sign(node)
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+	require.True(t, strings.HasPrefix(resp.Body.String(), ReasonNodeNotApproved+":"), resp.Body.String())
+	require.Contains(t, resp.Body.String(), "edgenode testnode is not approved")
+	events, err := kubeClient.CoreV1().Events(metav1.NamespaceDefault).List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1)
+	require.Equal(t, ReasonNodeNotApproved, events.Items[0].Reason)
+	require.Equal(t, "testnode", events.Items[0].InvolvedObject.Name)
+
+	// the rejection is cached until the ttl expires
+	_, err = kubeClient.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "testnode",
+			Annotations: map[string]string{"node.kubeedge.io/approved": "true"},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	resp = sign(node)
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+	now = now.Add(30 * time.Second)
+	resp = sign(node)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// the node without the label or the annotation is not approved
+	unlabeled := ca.NewNode(t, "unlabeled")
+	_, err = kubeClient.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "unlabeled",
+			Labels: map[string]string{"node.kubeedge.io/approved": "false"},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	resp = sign(unlabeled)
+	require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	// the pre-registered node is approved
+	registered := ca.NewNode(t, "registered")
+	signer, err := registered.Key.Signer()
+	require.NoError(t, err)
+	fp, err := preregistration.PublicKeyFingerprint(signer.Public())
+	require.NoError(t, err)
+	_, err = preregistration.DefaultStore.Add(context.TODO(), preregistration.Registration{NodeName: "registered", Fingerprint: fp})
+	require.NoError(t, err)
+	resp = sign(registered)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// the renewals are not checked
+	renewal := httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(unlabeled.RenewalRequest(ca.Issue(t, unlabeled))),
+		restful.NewResponse(renewal))
+	require.Equal(t, http.StatusOK, renewal.Code, renewal.Body.String())
+}
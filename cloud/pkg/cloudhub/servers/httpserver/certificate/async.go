@@ -0,0 +1,180 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// signingJobRetryAfterSeconds is the value of Retry-After header when the signing job is not done.
+const signingJobRetryAfterSeconds = "2"
+
+// signingJob is an edge certificate request signed asynchronously.
+type signingJob struct {
+	nodeName  string
+	format    certFormat
+	iss       *issuer
+	warnings  *warningRecorder
+	expiresAt time.Time
+
+	// the result is set when done is true
+	done    bool
+	certDER []byte
+	code    int
+	err     error
+}
+
+// signingJobs keeps the signing jobs until some time after they expire, so that polling an
+// expired job is responded with 410 rather than 404.
+type signingJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*signingJob
+	now  func() time.Time
+}
+
+var defaultSigningJobs = newSigningJobs()
+
+func newSigningJobs() *signingJobs {
+	return &signingJobs{
+		jobs: make(map[string]*signingJob),
+		now:  time.Now,
+	}
+}
+
+// asyncSigningRequested reports whether the edge certificate request is signed asynchronously,
+// which requires both the config enabled and the client preferring respond-async.
+func asyncSigningRequested(r *http.Request) bool {
+	if c := hubconfig.Config.AsyncSigning; c == nil || !c.Enable {
+		return false
+	}
+	for _, prefer := range r.Header.Values(types.HeaderPrefer) {
+		for _, pref := range strings.Split(prefer, ",") {
+			if strings.EqualFold(strings.TrimSpace(pref), types.PreferRespondAsync) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jobTTL returns how long the result of a signing job can be polled.
+func jobTTL() time.Duration {
+	return time.Duration(hubconfig.Config.AsyncSigning.JobTTL) * time.Second
+}
+
+// submit runs the issue func of the node in the background with a new signing deadline,
+// and returns the job to be polled.
+func (s *signingJobs) submit(nodeName string, format certFormat, iss *issuer, warnings *warningRecorder,
+	issue func(ctx context.Context) (*pem.Block, int, error)) types.SigningJob {
+	id := uuid.New().String()
+	ttl := jobTTL()
+	s.mu.Lock()
+	now := s.now()
+	for k, job := range s.jobs {
+		if now.After(job.expiresAt.Add(ttl)) {
+			delete(s.jobs, k)
+		}
+	}
+	job := &signingJob{
+		nodeName:  nodeName,
+		format:    format,
+		iss:       iss,
+		warnings:  warnings,
+		expiresAt: now.Add(ttl),
+	}
+	s.jobs[id] = job
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := signingContext(context.Background())
+		defer cancel()
+		certBlock, code, err := issue(ctx)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		job.done, job.code, job.err = true, code, err
+		if certBlock != nil {
+			job.certDER = certBlock.Bytes
+		}
+	}()
+	return types.SigningJob{ID: id, ExpiresAt: metav1.NewTime(job.expiresAt)}
+}
+
+// get returns a copy of the job of the node, ok is false if the job is unknown
+// and expired is true if the job can no longer be polled.
+func (s *signingJobs) get(id, nodeName string) (job signingJob, ok, expired bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok || j.nodeName != nodeName {
+		return signingJob{}, false, false
+	}
+	return *j, true, !s.now().Before(j.expiresAt)
+}
+
+// respondSigningJob responds 202 with the job and the URL to poll its result.
+func respondSigningJob(response *restful.Response, job types.SigningJob) {
+	body, err := json.Marshal(job)
+	if err != nil {
+		klog.Errorf("failed to marshal the signing job, err: %v", err)
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	response.Header().Set("Location", strings.Replace(constants.DefaultCertResultURL, "{id}", job.ID, 1))
+	response.Header().Set("Retry-After", signingJobRetryAfterSeconds)
+	response.Header().Set("Content-Type", restful.MIME_JSON)
+	response.WriteHeader(http.StatusAccepted)
+	if _, err := response.Write(body); err != nil {
+		klog.Errorf("failed to write the signing job to the response, err: %v", err)
+	}
+}
+
+// GetCertResult returns the result of an asynchronous signing job of the node. It responds 202
+// if the job is not done, 404 if the job is unknown and 410 if the job has expired, otherwise
+// the certificate or the error of the job is responded as the edge certificate request would be.
+func GetCertResult(request *restful.Request, response *restful.Response) {
+	id := request.PathParameter("id")
+	nodeName := request.Request.Header.Get(hubconfig.Config.NodeNameHeader())
+	job, ok, expired := defaultSigningJobs.get(id, nodeName)
+	switch {
+	case !ok:
+		resps.ErrorMessage(response, http.StatusNotFound, "the signing job "+id+" is not found")
+	case expired:
+		resps.ErrorMessage(response, http.StatusGone, "the signing job "+id+" has expired")
+	case !job.done:
+		respondSigningJob(response, types.SigningJob{ID: id, ExpiresAt: metav1.NewTime(job.expiresAt)})
+	case job.err != nil:
+		respondSigningError(response, nodeName, job.code, job.err)
+	default:
+		respondCert(response, job.certDER, job.format, job.iss, job.warnings.list())
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestAsyncSigning(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+	hubconfig.Config.CloudHub.AsyncSigning = &v1alpha1.CloudHubAsyncSigning{Enable: true, JobTTL: 60}
+	jobs := newSigningJobs()
+	defaultSigningJobs = jobs
+	t.Cleanup(func() {
+		hubconfig.Config.CloudHub.AsyncSigning = nil
+		defaultSigningJobs = newSigningJobs()
+	})
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:testnode",
+	}, pk, nil)
+	require.NoError(t, err)
+
+	ws := new(restful.WebService)
+	ws.Route(ws.GET(constants.DefaultCertResultURL).To(GetCertResult))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	submit := func(csrDER []byte, prefer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, constants.DefaultCertURL, bytes.NewReader(csrDER))
+		req.TLS = &tls.ConnectionState{}
+		req.Header.Set(types.HeaderNodeName, "testnode")
+		req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+		if prefer != "" {
+			req.Header.Set(types.HeaderPrefer, prefer)
+		}
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+	poll := func(location, nodeName string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, location, nil)
+		req.Header.Set(types.HeaderNodeName, nodeName)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+		return recorder
+	}
+	accepted := func(resp *httptest.ResponseRecorder) (string, types.SigningJob) {
+		require.Equal(t, http.StatusAccepted, resp.Code, resp.Body.String())
+		var job types.SigningJob
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &job))
+		location := resp.Header().Get("Location")
+		require.Equal(t, strings.Replace(constants.DefaultCertResultURL, "{id}", job.ID, 1), location)
+		return location, job
+	}
+	pollDone := func(location string) *httptest.ResponseRecorder {
+		var resp *httptest.ResponseRecorder
+		require.Eventually(t, func() bool {
+			resp = poll(location, "testnode")
+			return resp.Code != http.StatusAccepted
+		}, 10*time.Second, 10*time.Millisecond)
+		return resp
+	}
+
+	t.Run("sign synchronously without the preference", func(t *testing.T) {
+		resp := submit(csr.Bytes, "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	})
+
+	t.Run("poll until the certificate is issued", func(t *testing.T) {
+		location, job := accepted(submit(csr.Bytes, "wait=10, respond-async"))
+		require.True(t, job.ExpiresAt.After(time.Now()))
+
+		resp := pollDone(location)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		cert, err := x509.ParseCertificate(resp.Body.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, "system:node:testnode", cert.Subject.CommonName)
+		require.Equal(t, cert.SerialNumber.String(), resp.Header().Get(types.HeaderCertSerial))
+
+		// the job of a node is not visible to the other nodes
+		resp = poll(location, "othernode")
+		require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	})
+
+	t.Run("poll until the job fails", func(t *testing.T) {
+		location, _ := accepted(submit([]byte("invalid csr"), types.PreferRespondAsync))
+		resp := pollDone(location)
+		require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+		require.Contains(t, resp.Body.String(), "failed to sign certs for edgenode testnode")
+	})
+
+	t.Run("expired and unknown jobs", func(t *testing.T) {
+		location, _ := accepted(submit(csr.Bytes, types.PreferRespondAsync))
+		require.Equal(t, http.StatusOK, pollDone(location).Code)
+
+		now := time.Now()
+		jobs.now = func() time.Time { return now.Add(time.Minute) }
+		t.Cleanup(func() { jobs.now = time.Now })
+		resp := poll(location, "testnode")
+		require.Equal(t, http.StatusGone, resp.Code, resp.Body.String())
+
+		// the expired jobs are pruned after another TTL
+		jobs.now = func() time.Time { return now.Add(3 * time.Minute) }
+		accepted(submit(csr.Bytes, types.PreferRespondAsync))
+		resp = poll(location, "testnode")
+		require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+		resp = poll(strings.Replace(constants.DefaultCertResultURL, "{id}", "unknown", 1), "testnode")
+		require.Equal(t, http.StatusNotFound, resp.Code, resp.Body.String())
+	})
+}
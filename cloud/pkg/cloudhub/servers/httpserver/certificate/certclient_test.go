@@ -0,0 +1,275 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/preregistration"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certclient"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// newEnrollmentServer serves the certificate enrollment API by the handlers over TLS like
+// CloudHub, it returns the server and its CA to pin.
+func newEnrollmentServer(t *testing.T) (*httptest.Server, []byte) {
+	ws := new(restful.WebService)
+	ws.Path("/")
+	ws.Produces("*/*")
+	ws.Route(ws.GET(constants.DefaultCertURL).To(EdgeCoreClientCert))
+	ws.Route(ws.GET(constants.DefaultCAURL).To(GetCA))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	srv := httptest.NewUnstartedServer(container)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+}
+
+func TestCertClientContract(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	srv, serverCA := newEnrollmentServer(t)
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	newCSR := func(nodeName string) (certs.PrivateKeyWrap, []byte) {
+		pk, err := certshandler.GenPrivateKey()
+		require.NoError(t, err)
+		csr, err := certshandler.CreateCSR(pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:" + nodeName,
+		}, pk, nil)
+		require.NoError(t, err)
+		return pk, csr.Bytes
+	}
+	requireNodeCert := func(t *testing.T, issued *certclient.IssuedCert, nodeName string) *x509.Certificate {
+		cert, err := x509.ParseCertificate(issued.Certificate)
+		require.NoError(t, err)
+		require.Equal(t, "system:node:"+nodeName, cert.Subject.CommonName)
+		return cert
+	}
+
+	t.Run("get CA without pinning", func(t *testing.T) {
+		cli, err := certclient.New(srv.URL, "")
+		require.NoError(t, err)
+		ca, err := cli.GetCA(context.TODO())
+		require.NoError(t, err)
+		require.Equal(t, caPem.Bytes, ca)
+	})
+
+	t.Run("get CA bundle", func(t *testing.T) {
+		previousKey, err := cahandler.GenPrivateKey()
+		require.NoError(t, err)
+		previous, err := cahandler.NewSelfSigned(previousKey)
+		require.NoError(t, err)
+		additional := hubconfig.Config.AdditionalCAs
+		hubconfig.Config.AdditionalCAs = [][]byte{previous.Bytes}
+		t.Cleanup(func() { hubconfig.Config.AdditionalCAs = additional })
+
+		cli, err := certclient.New(srv.URL, "")
+		require.NoError(t, err)
+		// the old clients still get the CA only, which the token hash is computed from
+		ca, err := cli.GetCA(context.TODO())
+		require.NoError(t, err)
+		require.Equal(t, caPem.Bytes, ca)
+		bundle, err := cli.GetCABundle(context.TODO())
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{caPem.Bytes, previous.Bytes}, bundle)
+	})
+
+	t.Run("bootstrap token", func(t *testing.T) {
+		tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}).SignedString(caKey.DER())
+		require.NoError(t, err)
+		cli, err := certclient.New(srv.URL, "testnode", certclient.WithCA(serverCA),
+			certclient.WithToken(tokenStr))
+		require.NoError(t, err)
+
+		// the node requests ServerAuth with the token
+		policy := hubconfig.Config.ExtKeyUsagePolicy
+		hubconfig.Config.ExtKeyUsagePolicy = &v1alpha1.CloudHubExtKeyUsagePolicy{AllowServerAuthWithToken: true}
+		t.Cleanup(func() { hubconfig.Config.ExtKeyUsagePolicy = policy })
+		_, csr := newCSR("testnode")
+		issued, err := cli.SignCert(context.TODO(), certclient.CSRRequest{
+			CSR:    csr,
+			Usages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		})
+		require.NoError(t, err)
+		cert := requireNodeCert(t, issued, "testnode")
+		require.ElementsMatch(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+			cert.ExtKeyUsage)
+		require.Nil(t, issued.Manifest)
+
+		// the token is rejected by CloudHub
+		cli, err = certclient.New(srv.URL, "testnode", certclient.WithCA(serverCA),
+			certclient.WithToken("invalid"))
+		require.NoError(t, err)
+		_, err = cli.SignCert(context.TODO(), certclient.CSRRequest{CSR: csr})
+		var respErr *certclient.Error
+		require.ErrorAs(t, err, &respErr)
+		require.Equal(t, http.StatusUnauthorized, respErr.StatusCode)
+		require.False(t, respErr.Temporary())
+	})
+
+	t.Run("ServiceAccount token", func(t *testing.T) {
+		patches := gomonkey.ApplyFunc(client.GetKubeClient, newFakeTokenReviewClient)
+		defer patches.Reset()
+		hubconfig.Config.DelegatedSigning = &v1alpha1.CloudHubDelegatedSigning{Enable: true}
+		defer func() { hubconfig.Config.DelegatedSigning = nil }()
+
+		cli, err := certclient.New(srv.URL, "node2", certclient.WithCA(serverCA),
+			certclient.WithToken(validSAToken))
+		require.NoError(t, err)
+		_, csr := newCSR("node2")
+		issued, err := cli.SignCert(context.TODO(), certclient.CSRRequest{CSR: csr})
+		require.NoError(t, err)
+		requireNodeCert(t, issued, "node2")
+	})
+
+	t.Run("pre-registration", func(t *testing.T) {
+		origin := preregistration.DefaultStore
+		preregistration.DefaultStore = preregistration.NewStore(fake.NewSimpleClientset())
+		defer func() { preregistration.DefaultStore = origin }()
+
+		pk, csr := newCSR("prenode")
+		signer, err := pk.Signer()
+		require.NoError(t, err)
+		fp, err := preregistration.PublicKeyFingerprint(signer.Public())
+		require.NoError(t, err)
+		_, err = preregistration.DefaultStore.Add(context.TODO(), preregistration.Registration{
+			NodeName:    "prenode",
+			Fingerprint: fp,
+		})
+		require.NoError(t, err)
+
+		cli, err := certclient.New(srv.URL, "prenode", certclient.WithCA(serverCA))
+		require.NoError(t, err)
+		issued, err := cli.SignCert(context.TODO(), certclient.CSRRequest{CSR: csr})
+		require.NoError(t, err)
+		requireNodeCert(t, issued, "prenode")
+	})
+
+	t.Run("client certificate renewal", func(t *testing.T) {
+		tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}).SignedString(caKey.DER())
+		require.NoError(t, err)
+		cli, err := certclient.New(srv.URL, "renewnode", certclient.WithCA(serverCA),
+			certclient.WithToken(tokenStr))
+		require.NoError(t, err)
+		pk, csr := newCSR("renewnode")
+		issued, err := cli.SignCert(context.TODO(), certclient.CSRRequest{CSR: csr})
+		require.NoError(t, err)
+		previous := requireNodeCert(t, issued, "renewnode")
+
+		_, err = cli.Renew(context.TODO(), certclient.CSRRequest{CSR: csr})
+		require.ErrorContains(t, err, "the client certificate is required")
+
+		signer, err := pk.Signer()
+		require.NoError(t, err)
+		cli, err = certclient.New(srv.URL, "renewnode", certclient.WithCA(serverCA),
+			certclient.WithClientCert(tls.Certificate{Certificate: [][]byte{issued.Certificate}, PrivateKey: signer}))
+		require.NoError(t, err)
+		pk, csr = newCSR("renewnode")
+		issued, err = cli.Renew(context.TODO(), certclient.CSRRequest{CSR: csr})
+		require.NoError(t, err)
+		cert := requireNodeCert(t, issued, "renewnode")
+		require.NotEqual(t, previous.SerialNumber, cert.SerialNumber)
+
+		// the certificate of another node is not accepted
+		signer, err = pk.Signer()
+		require.NoError(t, err)
+		cli, err = certclient.New(srv.URL, "othernode", certclient.WithCA(serverCA),
+			certclient.WithClientCert(tls.Certificate{Certificate: [][]byte{issued.Certificate}, PrivateKey: signer}))
+		require.NoError(t, err)
+		_, csr = newCSR("othernode")
+		_, err = cli.Renew(context.TODO(), certclient.CSRRequest{CSR: csr})
+		var respErr *certclient.Error
+		require.ErrorAs(t, err, &respErr)
+		require.Equal(t, http.StatusUnauthorized, respErr.StatusCode)
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}).SignedString(caKey.DER())
+		require.NoError(t, err)
+		cli, err := certclient.New(srv.URL, "testnode", certclient.WithCA(serverCA),
+			certclient.WithToken(tokenStr), certclient.WithManifest())
+		require.NoError(t, err)
+		_, csr := newCSR("testnode")
+		issued, err := cli.SignCert(context.TODO(), certclient.CSRRequest{CSR: csr})
+		require.NoError(t, err)
+		requireNodeCert(t, issued, "testnode")
+		require.NotNil(t, issued.Manifest)
+		digest := sha256.Sum256(caPem.Bytes)
+		require.Equal(t, hex.EncodeToString(digest[:]), issued.Manifest.CAFingerprint)
+	})
+
+	t.Run("signing timeout", func(t *testing.T) {
+		hubconfig.Config.SigningTimeout = 1
+		defer func() { hubconfig.Config.SigningTimeout = 0 }()
+		handler := &slowHandler{Handler: certshandler, started: make(chan struct{})}
+		patches := gomonkey.ApplyFunc(certs.GetHandler, func(certs.HanndlerType) certs.Handler { return handler })
+		defer patches.Reset()
+
+		tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}).SignedString(caKey.DER())
+		require.NoError(t, err)
+		cli, err := certclient.New(srv.URL, "testnode", certclient.WithCA(serverCA),
+			certclient.WithToken(tokenStr))
+		require.NoError(t, err)
+		_, csr := newCSR("testnode")
+		_, err = cli.SignCert(context.TODO(), certclient.CSRRequest{CSR: csr})
+		require.True(t, certclient.IsSigningTimeout(err), err)
+		var respErr *certclient.Error
+		require.ErrorAs(t, err, &respErr)
+		require.Equal(t, http.StatusGatewayTimeout, respErr.StatusCode)
+		require.Equal(t, 5*time.Second, respErr.RetryAfter)
+		require.True(t, respErr.Temporary())
+	})
+}
@@ -16,147 +16,797 @@ limitations under the License.
 package certificate
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"crypto/x509"
-	"encoding/json"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
 	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certinfo"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/clientip"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/issuancelog"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/preregistration"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
-	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/features"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
 	"github.com/kubeedge/kubeedge/pkg/security/token"
 )
 
-// GetCA returns the caCertDER
-func GetCA(_ *restful.Request, response *restful.Response) {
-	resps.OK(response, hubconfig.Config.Ca)
+// GetCA returns the caCertDER, or the CA bundle in PEM if the client accepts types.MIMEPEMFile,
+// or the caCertDER with its media type if the client accepts types.MIMEPKIXCert. It responds
+// 304 Not Modified if the ETag in the If-None-Match header matches the current CA. The bundle is
+// not the default, as the hash of the bootstrap tokens is of the caCertDER. A client requesting
+// the API version v2 without a media type is responded with types.CAResponse in JSON.
+func GetCA(request *restful.Request, response *restful.Response) {
+	body, contentType := hubconfig.Config.Ca, ""
+	switch {
+	case acceptsMediaType(request.Request, types.MIMEPEMFile):
+		body, contentType = caBundlePEM(), types.MIMEPEMFile
+	case acceptsMediaType(request.Request, types.MIMEPKIXCert):
+		contentType = types.MIMEPKIXCert
+	default:
+		var err error
+		if body, contentType, err = resps.VersionedBody(response, body, caResponse()); err != nil {
+			resps.Error(response, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	etag := caETag(body)
+	response.Header().Set("ETag", etag)
+	if matchETag(request.Request.Header.Get("If-None-Match"), etag) {
+		response.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if contentType != "" {
+		response.Header().Set("Content-Type", contentType)
+	}
+	resps.OK(response, body)
+}
+
+// caBundlePEM returns the CA followed by the additional trusted CAs in PEM.
+func caBundlePEM() []byte {
+	var bundle []byte
+	for _, ca := range hubconfig.Config.CABundle() {
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: ca})...)
+	}
+	return bundle
+}
+
+// caETag computes a strong ETag from the CA content, so it changes when the CA rotates.
+func caETag(ca []byte) string {
+	digest := sha256.Sum256(ca)
+	return `"` + hex.EncodeToString(digest[:]) + `"`
+}
+
+// matchETag reports whether the If-None-Match header value matches the etag.
+func matchETag(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
 }
 
-// EdgeCoreClientCert will verify the certificate of EdgeCore or token then create EdgeCoreCert and return it
+// EdgeCoreClientCert will verify the certificate of EdgeCore or token then create EdgeCoreCert and return it,
+// the node is identified by the NodeName header, or by the CommonName of the CSR if the header is empty,
+// the certificate is returned along with its provisioning manifest if the client accepts types.MIMECertManifest,
+// or along with the intermediate CAs in PEM if the client accepts types.MIMEPEMCertChain, or as
+// types.EdgeCertResponse in JSON if the client requests the API version v2 without a media type.
+// If asynchronous signing is enabled and the client prefers respond-async, it responds 202 with a signing job
+// whose result is polled by GetCertResult. If the signing webhook is configured, the authenticated request
+// is signed only if the webhook allows it.
 func EdgeCoreClientCert(request *restful.Request, response *restful.Response) {
 	r := request.Request
-	nodeName := r.Header.Get(types.HeaderNodeName)
+	nodeName := r.Header.Get(hubconfig.Config.NodeNameHeader())
+	clientIP := clientip.FromRequest(r)
+	if err := signingFrozenError(); err != nil {
+		klog.Warningf("reject the certificate request of edgenode %s, client IP: %s, err: %v", nodeName, clientIP, err)
+		recordSignFailure(http.StatusServiceUnavailable, err)
+		respondSigningFrozen(response, err)
+		return
+	}
 
+	maxCSRSize := hubconfig.Config.MaxCSRSize()
+	if r.ContentLength > maxCSRSize {
+		// reject the oversized upload before buffering it
+		err := csrTooLargeError(nodeName, maxCSRSize)
+		klog.Errorf("%v, client IP: %s", err, clientIP)
+		recordSignFailure(http.StatusRequestEntityTooLarge, err)
+		resps.Error(response, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	payload, err := io.ReadAll(http.MaxBytesReader(response, r.Body, maxCSRSize))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err := csrTooLargeError(nodeName, maxBytesErr.Limit)
+		klog.Errorf("%v, client IP: %s", err, clientIP)
+		recordSignFailure(http.StatusRequestEntityTooLarge, err)
+		resps.Error(response, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	if err != nil {
+		message := fmt.Sprintf("failed to read the CSR of edgenode %s, err: %v", nodeName, err)
+		klog.Errorf("%s, client IP: %s", message, clientIP)
+		recordSignFailure(http.StatusBadRequest, err)
+		resps.ErrorMessage(response, http.StatusBadRequest, message)
+		return
+	}
+	if nodeName == "" {
+		// some minimal clients only carry the node name in the CSR
+		if nodeName, err = nodeNameFromCSR(payload); err != nil {
+			klog.Errorf("%v, client IP: %s", err, clientIP)
+			recordSignFailure(http.StatusBadRequest, err)
+			resps.Error(response, http.StatusBadRequest, err)
+			return
+		}
+	}
+	ctx, cancel := signingContext(r.Context())
+	defer cancel()
+	warnings := &warningRecorder{}
+	ctx = withWarnings(ctx, warnings)
+	iss, err := selectIssuer(r, nodeName)
+	if err != nil {
+		klog.Errorf("%v, edgenode: %s, client IP: %s", err, nodeName, clientIP)
+		recordSignFailure(http.StatusBadRequest, err)
+		resps.Error(response, http.StatusBadRequest, err)
+		return
+	}
+	duration, err := certDuration(ctx, r)
+	if err != nil {
+		klog.Errorf("%v, edgenode: %s, client IP: %s", err, nodeName, clientIP)
+		recordSignFailure(http.StatusBadRequest, err)
+		resps.Error(response, http.StatusBadRequest, err)
+		return
+	}
+	var preReg *preregistration.Registration
+	// nodeToken is the per-node token consumed by this request
+	var nodeToken *token.NodeClaims
+	// release returns the single-use credentials consumed by this request if the certificate
+	// is not issued, so that the node can retry with them
+	release := func() {
+		if preReg != nil {
+			preregistration.DefaultStore.Restore(*preReg)
+		}
+		if nodeToken != nil {
+			releaseNodeToken(nodeToken)
+		}
+	}
+	// previous is the certificate of the node which is renewed by this request
+	var previous *x509.Certificate
+	// authentication is how the request is authenticated, which is recorded in the issuance log
+	var authentication string
 	if cert := r.TLS.PeerCertificates; len(cert) > 0 {
-		if err := verifyCert(cert[0], nodeName); err != nil {
-			message := fmt.Sprintf("failed to verify the certificate for edgenode: %s, err: %v", nodeName, err)
-			klog.Error(message)
-			resps.ErrorMessage(response, http.StatusUnauthorized, message)
+		authentication = certs.IssuanceAuthCertificate
+		previous, err = verifyPeerCertificates(cert, nodeName, iss)
+		if err != nil {
+			err = wrapError(err, "failed to verify the certificate for edgenode: %s", nodeName)
+			klog.Errorf("%v, client IP: %s", err, clientIP)
+			recordSignFailure(http.StatusUnauthorized, err)
+			resps.Error(response, http.StatusUnauthorized, err)
+			return
+		}
+		if isLegacyCertSubject(previous) {
+			addWarning(ctx, "the certificate %s with the legacy subject is accepted, which is deprecated", previous.SerialNumber)
+		}
+		if err := checkFreshCSRKey(payload, previous); err != nil {
+			klog.Errorf("%v, edgenode: %s, client IP: %s", err, nodeName, clientIP)
+			recordSignFailure(http.StatusBadRequest, err)
+			resps.Error(response, http.StatusBadRequest, err)
+			return
+		}
+	} else if authorization := r.Header.Get(types.HeaderAuthorization); authorization != "" {
+		authentication = certs.IssuanceAuthToken
+		allowedNodes, consumed, code, err := verifyAuthorization(ctx, authorization, nodeName)
+		if err != nil {
+			klog.Errorf("%v, edgenode: %s, client IP: %s", err, nodeName, clientIP)
+			if terr := signingTimeoutError(ctx); terr != nil {
+				recordSignFailure(http.StatusGatewayTimeout, terr)
+				respondSigningTimeout(response, terr)
+				return
+			}
+			if code == http.StatusServiceUnavailable {
+				response.Header().Set("Retry-After", tokenReviewRetryAfterSeconds)
+			}
+			recordSignFailure(code, err)
+			resps.Error(response, code, err)
+			return
+		}
+		nodeToken = consumed
+		if allowedNodes != nil && !slices.Contains(allowedNodes, nodeName) {
+			err := newReasonError(types.ReasonSubjectMismatch, "the token is not permitted to provision edgenode: %s", nodeName)
+			klog.Errorf("%v, client IP: %s", err, clientIP)
+			release()
+			recordSignFailure(http.StatusForbidden, err)
+			resps.Error(response, http.StatusForbidden, err)
+			return
+		}
+		if code, err := defaultNodeApprover.check(ctx, nodeName, clientIP); err != nil {
+			klog.Errorf("%v, client IP: %s", err, clientIP)
+			release()
+			recordSignFailure(code, err)
+			resps.Error(response, code, err)
 			return
 		}
 	} else {
-		authorization := r.Header.Get(types.HeaderAuthorization)
-		if code, err := verifyAuthorization(authorization); err != nil {
-			klog.Error(err)
+		// neither certificate nor token is presented, try the pre-registration of the node
+		reg, code, err := verifyPreRegistration(r.Context(), payload, nodeName)
+		if err != nil {
+			klog.Errorf("%v, edgenode: %s, client IP: %s", err, nodeName, clientIP)
+			recordSignFailure(code, err)
 			resps.Error(response, code, err)
 			return
 		}
+		preReg = &reg
+		authentication = certs.IssuanceAuthPreRegistration
 	}
 
 	usagesStr := r.Header.Get(types.HeaderExtKeyUsages)
-	reader := http.MaxBytesReader(response, r.Body, constants.MaxRespBodyLength)
-	certBlock, err := signEdgeCert(reader, usagesStr)
+	if code, err := checkExtKeyUsagePolicy(usagesStr, authentication); err != nil {
+		klog.Errorf("%v, edgenode: %s, client IP: %s", err, nodeName, clientIP)
+		release()
+		recordSignFailure(code, err)
+		resps.Error(response, code, err)
+		return
+	}
+
+	// an identical CSR posted again in the dedup window, e.g. by an edge node in a restart loop,
+	// is responded with the certificate signed for it before
+	dedupKey, dedupDigest, dedup := csrDedupKey(payload, nodeName, usagesStr, duration, iss)
+	if dedup {
+		if certDER, ok := defaultCSRDedup.lookup(dedupKey, dedupDigest); ok {
+			klog.V(2).Infof("respond the certificate signed before for the repeated CSR of edgenode %s, client IP: %s",
+				nodeName, clientIP)
+			monitor.CSRDedupHitsTotal.Inc()
+			respondCert(response, certDER, acceptedCertFormat(r), iss, warnings.list())
+			return
+		}
+	}
+
+	if retryAfter, err := defaultSigningRateLimiter.allow(ctx, nodeName); err != nil {
+		klog.Errorf("%v, client IP: %s", err, clientIP)
+		release()
+		recordSignFailure(http.StatusTooManyRequests, err)
+		respondRateLimited(response, retryAfter, err)
+		return
+	}
+
+	if code, err := defaultSigningWebhook.review(ctx, nodeName, payload, authentication, clientIP); err != nil {
+		klog.Errorf("%v, client IP: %s", err, clientIP)
+		release()
+		if terr := signingTimeoutError(ctx); terr != nil {
+			recordSignFailure(http.StatusGatewayTimeout, terr)
+			respondSigningTimeout(response, terr)
+			return
+		}
+		recordSignFailure(code, err)
+		resps.Error(response, code, err)
+		return
+	}
+
+	fenced, code, err := checkDuplicateEnrollment(ctx, nodeName, clientIP, payload, previous)
+	if err != nil {
+		release()
+		if terr := signingTimeoutError(ctx); terr != nil {
+			recordSignFailure(http.StatusGatewayTimeout, terr)
+			respondSigningTimeout(response, terr)
+			return
+		}
+		recordSignFailure(code, err)
+		resps.Error(response, code, err)
+		return
+	}
+
+	tenant := quotaTenant(nodeName)
+	if tenant != nil {
+		// the request authenticated by the certificate of the node is a renewal
+		if err := defaultQuota.reserve(tenant, nodeName, len(r.TLS.PeerCertificates) > 0); err != nil {
+			klog.Errorf("%v, edgenode: %s, client IP: %s", err, nodeName, clientIP)
+			release()
+			recordSignFailure(http.StatusTooManyRequests, err)
+			resps.Error(response, http.StatusTooManyRequests, err)
+			return
+		}
+	}
+
+	// issue signs the certificate and settles the state of the request, which is shared by
+	// the synchronous and the asynchronous requests
+	issue := func(ctx context.Context) (*pem.Block, int, error) {
+		// the asynchronous job runs with a new ctx, which collects the warnings of the request too
+		ctx = withWarnings(ctx, warnings)
+		certBlock, code, err := signEdgeCert(ctx, io.NopCloser(bytes.NewReader(payload)), nodeName, usagesStr, duration, iss)
+		if err != nil {
+			klog.Errorf("failed to sign certs for edgenode %s, err: %v", nodeName, err)
+			if tenant != nil {
+				defaultQuota.cancel(nodeName)
+			}
+			release()
+			return nil, code, err
+		}
+		if tenant != nil {
+			// the new certificate is committed before fencing, so revoking the
+			// active certificate does not release the slot of the node
+			defaultQuota.commit(nodeName, certBlock.Bytes)
+		}
+		if previous != nil {
+			revokeRenewed(nodeName, previous)
+		}
+		if fenced != nil {
+			fence(nodeName, fenced)
+		}
+		if dedup {
+			defaultCSRDedup.store(dedupKey, dedupDigest, certBlock.Bytes, csrDedupWindow())
+		}
+		issuancelog.Record(nodeName, clientIP, authentication, certBlock.Bytes)
+		if cert, err := x509.ParseCertificate(certBlock.Bytes); err == nil {
+			certinfo.DefaultStore.Record(nodeName, cert)
+		}
+		return certBlock, http.StatusOK, nil
+	}
+	if asyncSigningRequested(r) {
+		respondSigningJob(response, defaultSigningJobs.submit(nodeName, acceptedCertFormat(r), iss, warnings, issue))
+		return
+	}
+	certBlock, code, err := issue(ctx)
+	if err != nil {
+		respondSigningError(response, nodeName, code, err)
+		return
+	}
+	respondCert(response, certBlock.Bytes, acceptedCertFormat(r), iss, warnings.list())
+}
+
+// respondSigningError responds the error of signing the certificate of the node with the code.
+func respondSigningError(response *restful.Response, nodeName string, code int, err error) {
+	if errors.Is(err, errSigningFrozen) {
+		respondSigningFrozen(response, err)
+		return
+	}
+	if code == http.StatusGatewayTimeout {
+		respondSigningTimeout(response, err)
+		return
+	}
+	if errors.Is(err, errCSRDenied) || errors.Is(err, errCSRFailed) {
+		resps.Error(response, code, err)
+		return
+	}
+	if code == http.StatusServiceUnavailable {
+		response.Header().Set("Retry-After", signingRetryAfterSeconds)
+	}
+	resps.Error(response, code, wrapError(err, "failed to sign certs for edgenode %s", nodeName))
+}
+
+// respondCert responds the issued certificate in the format with the warnings of the request.
+// The whole body is built before any header is set, and then written at once with its
+// Content-Length, so that a failed request never responds a partial certificate.
+func respondCert(response *restful.Response, certDER []byte, format certFormat, iss *issuer, warnings []string) {
+	body, contentType, err := certBody(response, certDER, format, iss, warnings)
+	if err != nil {
+		klog.Errorf("failed to build the certificate response, err: %v", err)
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	setCertHeaders(response, certDER)
+	setWarningHeaders(response, warnings)
+	if contentType != "" {
+		response.Header().Set("Content-Type", contentType)
+	}
+	resps.OK(response, body)
+}
+
+// certBody returns the body of the issued certificate in the format and its content type, the
+// content type is empty if the default one applies.
+func certBody(response *restful.Response, certDER []byte, format certFormat, iss *issuer,
+	warnings []string) ([]byte, string, error) {
+	switch format {
+	case certFormatManifest:
+		body, err := certManifestBody(certDER, iss)
+		return body, types.MIMECertManifest, err
+	case certFormatPEMChain:
+		body, err := certChainBody(certDER, iss)
+		return body, types.MIMEPEMCertChain, err
+	case certFormatPEM:
+		return pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: certDER}), types.MIMEPEMFile, nil
+	case certFormatPKIXCert:
+		return certDER, types.MIMEPKIXCert, nil
+	default:
+		return resps.VersionedBody(response, certDER, edgeCertResponse(certDER, warnings))
+	}
+}
+
+// respondSigningTimeout responds 504 with the reason code of the signing timeout, which can be retried.
+func respondSigningTimeout(response *restful.Response, err error) {
+	response.Header().Set("Retry-After", signingRetryAfterSeconds)
+	resps.Error(response, http.StatusGatewayTimeout, err)
+}
+
+// setCertHeaders sets the serial number and expiration of the issued certificate to the response headers,
+// the serial number is in the same decimal format as the issuance log.
+func setCertHeaders(response *restful.Response, certDER []byte) {
+	cert, err := x509.ParseCertificate(certDER)
 	if err != nil {
-		message := fmt.Sprintf("failed to sign certs for edgenode %s, err: %v", nodeName, err)
-		klog.Error(message)
-		resps.ErrorMessage(response, http.StatusInternalServerError, message)
+		klog.Warningf("failed to parse the issued certificate, err: %v", err)
 		return
 	}
-	resps.OK(response, certBlock.Bytes)
+	response.Header().Set(types.HeaderCertSerial, cert.SerialNumber.String())
+	response.Header().Set(types.HeaderCertNotAfter, cert.NotAfter.UTC().Format(time.RFC3339))
+	response.Header().Set(types.HeaderCertSignatureAlgorithm, cert.SignatureAlgorithm.String())
+}
+
+// verifyPeerCertificates identifies the leaf certificate among the peer certificates, which may
+// be presented in any order, and verifies it with the others as intermediates. It returns the
+// verified leaf certificate.
+func verifyPeerCertificates(peerCerts []*x509.Certificate, nodeName string, iss *issuer) (*x509.Certificate, error) {
+	var leaf *x509.Certificate
+	var intermediates []*x509.Certificate
+	for _, cert := range peerCerts {
+		if cert.IsCA {
+			intermediates = append(intermediates, cert)
+			continue
+		}
+		if leaf != nil {
+			return nil, newReasonError(types.ReasonCertInvalid, "more than one end-entity certificate is presented")
+		}
+		leaf = cert
+	}
+	if leaf == nil {
+		return nil, newReasonError(types.ReasonCertInvalid, "no end-entity certificate is presented")
+	}
+	if err := verifyCertChain(leaf, intermediates, nodeName, iss); err != nil {
+		return nil, err
+	}
+	return leaf, nil
 }
 
-// verifyCert verifies the edge certificate by CA certificate when edge certificates rotate.
-func verifyCert(cert *x509.Certificate, nodeName string) error {
-	roots := x509.NewCertPool()
-	ok := roots.AppendCertsFromPEM(pem.EncodeToMemory(&pem.Block{
-		Type:  certutil.CertificateBlockType,
-		Bytes: hubconfig.Config.Ca,
-	}))
-	if !ok {
-		return fmt.Errorf("failed to parse root certificate")
+// VerifyForwardedCerts verifies the client certificate forwarded by the trusted proxies, which comes
+// first, with the others as intermediates. It is verified by the CAs of CloudHub and of the issuers
+// with the same options as verifyCert, and the verified chains are returned.
+func VerifyForwardedCerts(forwarded []*x509.Certificate) ([][]*x509.Certificate, error) {
+	if len(forwarded) == 0 {
+		return nil, fmt.Errorf("no certificate is forwarded")
+	}
+	candidates := []*issuer{nil}
+	for _, iss := range issuers {
+		candidates = append(candidates, iss)
+	}
+	var verifyErr error
+	for _, iss := range candidates {
+		roots, err := iss.rootPool()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse root certificate, err: %v", err)
+		}
+		chains, err := forwarded[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: iss.intermediatePool(forwarded[1:]...),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		if err == nil {
+			return chains, nil
+		}
+		if verifyErr == nil {
+			// the error of the global CA is reported
+			verifyErr = err
+		}
+	}
+	return nil, verifyErr
+}
+
+// verifyCert verifies the edge certificate by the CA certificate of the issuer when edge certificates rotate.
+func verifyCert(cert *x509.Certificate, nodeName string, iss *issuer) error {
+	return verifyCertChain(cert, nil, nodeName, iss)
+}
+
+// verifyCertChain verifies the edge certificate with the intermediate CAs presented along with it and
+// the intermediate CAs of the issuer, so that it chains to the offline root CA too.
+func verifyCertChain(cert *x509.Certificate, intermediates []*x509.Certificate, nodeName string, iss *issuer) error {
+	roots, err := iss.rootPool()
+	if err != nil {
+		return newReasonError(types.ReasonSignerUnavailable, "failed to parse root certificate, err: %v", err)
 	}
 	opts := x509.VerifyOptions{
-		Roots:     roots,
-		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		Roots:         roots,
+		Intermediates: iss.intermediatePool(intermediates...),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 	}
 	if _, err := cert.Verify(opts); err != nil {
-		return fmt.Errorf("failed to verify edge certificate: %v", err)
+		at, ok := skewedVerifyTime(cert, err)
+		if !ok {
+			return newReasonError(types.ReasonCertInvalid, "failed to verify edge certificate: %v", err)
+		}
+		opts.CurrentTime = at
+		if _, err := cert.Verify(opts); err != nil {
+			return newReasonError(types.ReasonCertInvalid, "failed to verify edge certificate: %v", err)
+		}
+		klog.V(2).Infof("accept the edge certificate %s of edgenode %s within the clock skew tolerance %v",
+			cert.SerialNumber, nodeName, clockSkewTolerance())
+	}
+	if revocation.DefaultList.IsRevoked(cert) {
+		return newReasonError(types.ReasonCertInvalid, "the edge certificate %s is revoked", cert.SerialNumber.String())
 	}
 	return verifyCertSubject(cert, nodeName)
 }
 
-// verifyCertSubject ...
+// verifyCertSubject verifies that the certificate subject belongs to the node. Organization is
+// a multi-valued attribute, so the certificate is accepted if any of its organizations matches.
+// The legacy subject of the old versions is accepted for any node if AcceptLegacyCertSubject is set.
+// If SPIFFE is enabled, the certificate carrying the SPIFFE ID of the node is accepted as well.
 func verifyCertSubject(cert *x509.Certificate, nodeName string) error {
-	if cert.Subject.Organization[0] == "KubeEdge" && cert.Subject.CommonName == "kubeedge.io" {
-		// In order to maintain compatibility with older versions of certificates
-		// this condition will be removed in KubeEdge v1.18.
+	if cert == nil {
+		return newReasonError(types.ReasonCertInvalid, "no certificate of edgenode %s to verify", nodeName)
+	}
+	orgs := cert.Subject.Organization
+	if isLegacyCertSubject(cert) {
+		if !hubconfig.Config.AcceptLegacyCertSubject {
+			return newReasonError(types.ReasonSubjectMismatch, "the certificate %s with the legacy subject O=KubeEdge, CN=kubeedge.io is not accepted "+
+				"since acceptLegacyCertSubject is disabled, please rotate the edge certificate of edgenode %s "+
+				"by enrolling it again with a token", cert.SerialNumber, nodeName)
+		}
+		klog.Warningf("DEPRECATED: accept the certificate %s with the legacy subject for edgenode %s, "+
+			"please rotate it before acceptLegacyCertSubject is disabled", cert.SerialNumber, nodeName)
 		return nil
 	}
 	commonName := fmt.Sprintf("system:node:%s", nodeName)
-	if cert.Subject.Organization[0] == "system:nodes" && cert.Subject.CommonName == commonName {
+	if slices.Contains(orgs, "system:nodes") && cert.Subject.CommonName == commonName {
+		return nil
+	}
+	if hasSPIFFEID(cert, nodeName) {
 		return nil
 	}
-	return fmt.Errorf("request node name is not match with the certificate")
+	if len(orgs) == 0 {
+		return newReasonError(types.ReasonSubjectMismatch, "the subject of the certificate %s is not acceptable for edgenode %s, "+
+			"it has no Organization", cert.SerialNumber, nodeName)
+	}
+	return newReasonError(types.ReasonSubjectMismatch, "the subject of the certificate %s is not acceptable for edgenode %s, "+
+		"it must be O=system:nodes, CN=%s, got O=%q, CN=%q", cert.SerialNumber, nodeName, commonName, orgs, cert.Subject.CommonName)
+}
+
+// isLegacyCertSubject reports whether the certificate has the legacy subject issued by the old versions.
+func isLegacyCertSubject(cert *x509.Certificate) bool {
+	return cert != nil && slices.Contains(cert.Subject.Organization, "KubeEdge") && cert.Subject.CommonName == "kubeedge.io"
 }
 
-// verifyAuthorization verifies the token from EdgeCore CSR
-func verifyAuthorization(authorization string) (int, error) {
+// verifyAuthorization verifies the token from EdgeCore CSR. A per-node token is consumed, its claims
+// are returned so that the request can release it if the certificate is not issued. The node name
+// claim of a token is verified by verifyTokenNodeName, and the token which is not recorded is accepted
+// regardless of the feature gate LegacyBootstrapToken, which the global token is accepted only behind.
+// If the delegated signing is enabled, a ServiceAccount token is accepted too, and the node names that
+// the ServiceAccount may provision are returned. The returned node names are nil if the request is not
+// restricted to any node. If the TokenReview still fails transiently after the retries, the code is 503
+// rather than 401, as the token may be valid.
+func verifyAuthorization(ctx context.Context, authorization, nodeName string,
+) (nodes []string, consumed *token.NodeClaims, code int, err error) {
 	klog.V(4).Info("authorization token is: ", authorization)
+	defer func() {
+		if err != nil {
+			monitor.TokenVerifyFailuresTotal.Inc()
+		}
+	}()
 	if authorization == "" {
-		return http.StatusUnauthorized, errors.New("token validation failure, token is empty")
+		return nil, nil, http.StatusUnauthorized, newReasonError(types.ReasonTokenMalformed, "token validation failure, token is empty")
 	}
 	bearerToken := strings.Split(authorization, " ")
 	if len(bearerToken) != 2 {
-		return http.StatusUnauthorized, errors.New("token validation failure, token cannot be splited")
+		return nil, nil, http.StatusUnauthorized, newReasonError(types.ReasonTokenMalformed, "token validation failure, token cannot be splited")
 	}
-	valid, err := token.Verify(bearerToken[1], hubconfig.Config.CaKey)
-	if err != nil {
-		return http.StatusUnauthorized, fmt.Errorf("token validation failure, err: %v", err)
+	claims, err := token.ParseNodeToken(bearerToken[1], hubconfig.Config.TokenSigningKey())
+	if err == nil {
+		if claims.NodeName != "" && claims.ID != "" {
+			return verifyNodeToken(ctx, claims, nodeName)
+		}
+		if claims.NodeName != "" {
+			code, err := verifyTokenNodeName(claims, nodeName)
+			return nil, nil, code, err
+		}
+		if features.DefaultFeatureGate.Enabled(features.LegacyBootstrapToken) {
+			return nil, nil, http.StatusOK, nil
+		}
+		err = fmt.Errorf("the global token is disabled by the feature gate %s, use a token of the node", features.LegacyBootstrapToken)
+	}
+	if delegatedSigningEnabled() {
+		nodes, saErr := verifyServiceAccountTokenWithRetry(ctx, bearerToken[1])
+		if saErr == nil {
+			return nodes, nil, http.StatusOK, nil
+		}
+		// the token may be valid, the request can retry once the API server recovers
+		var te *transientError
+		if errors.As(saErr, &te) {
+			return nil, nil, http.StatusServiceUnavailable, fmt.Errorf("failed to review the ServiceAccount token, err: %v", saErr)
+		}
+		klog.V(4).Infof("ServiceAccount token validation failure, err: %v", saErr)
+	}
+	if reason := tokenFailureReason(err); reason != "" {
+		return nil, nil, http.StatusUnauthorized, newReasonError(reason, "token validation failure, err: %v", err)
+	}
+	return nil, nil, http.StatusUnauthorized, fmt.Errorf("token validation failure, err: %v", err)
+}
+
+// tokenFailureReason returns the reason code of the error parsing the token, so that EdgeCore
+// can tell an expired token, which may be replaced by a fresh one, from a broken one.
+func tokenFailureReason(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return types.ReasonTokenExpired
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return types.ReasonTokenInvalidSignature
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return types.ReasonTokenMalformed
+	}
+	return ""
+}
+
+// configuredCertDuration returns the validity period of the edge certificates configured by
+// EdgeCertSigningDuration.
+func configuredCertDuration() time.Duration {
+	return hubconfig.Config.EdgeCertSigningDuration * time.Hour * 24
+}
+
+// certDuration resolves the validity period of the edge certificate of the request, which is the
+// duration in the X-KubeEdge-Cert-Duration header clamped to MaxEdgeCertSigningDuration, or the
+// configured duration if the header is absent or the requested durations are not honored.
+func certDuration(ctx context.Context, r *http.Request) (time.Duration, error) {
+	value := r.Header.Get(types.HeaderCertDuration)
+	if value == "" {
+		return configuredCertDuration(), nil
+	}
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours <= 0 {
+		return 0, fmt.Errorf("invalid %s header %q, it must be a positive number of hours", types.HeaderCertDuration, value)
+	}
+	maxDuration := hubconfig.Config.MaxEdgeCertSigningDuration * time.Hour * 24
+	if maxDuration == 0 {
+		addWarning(ctx, "the requested validity period of %d hours is ignored, the certificate is valid for %v",
+			hours, configuredCertDuration())
+		return configuredCertDuration(), nil
 	}
-	if !valid {
-		return http.StatusUnauthorized, errors.New("token validation failure, valid is false")
+	// the hours are compared before the conversion, which may overflow
+	if hours > int(maxDuration/time.Hour) {
+		addWarning(ctx, "the requested validity period of %d hours is clamped to %v", hours, maxDuration)
+		return maxDuration, nil
 	}
-	return http.StatusOK, nil
+	return time.Duration(hours) * time.Hour, nil
 }
 
-// signEdgeCert signs the CSR from EdgeCore
-func signEdgeCert(r io.ReadCloser, usagesStr string) (*pem.Block, error) {
+// signEdgeCert signs the CSR from EdgeCore, the CSR can be either PEM or DER encoded.
+// The CSR is validated by the same rules as the offline signing, see certs.ValidateEdgeCSR.
+// The SANs of the CSR are copied into the certificate after they are checked, see checkCSRSANs.
+// The SPIFFE ID of the node is added as a URI SAN if SPIFFE is enabled.
+// The certificate is valid for the duration, which is clamped to certs.MaxEdgeCertDuration.
+// The certificate is signed by the CA of the issuer, or the global CA if the issuer is nil,
+// unless the csr-api signer delegates the signing to the Kubernetes CSR API.
+// It returns the status code that should be responded when an error occurs, which is 504 if the
+// signing deadline of the ctx passes.
+func signEdgeCert(ctx context.Context, r io.ReadCloser, nodeName, usagesStr string, duration time.Duration,
+	iss *issuer) (certBlock *pem.Block, code int, err error) {
 	klog.V(4).Infof("receive sign crt request, ExtKeyUsages: %s", usagesStr)
-	var usages []x509.ExtKeyUsage
-	if usagesStr == "" {
-		usages = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
-	} else {
-		err := json.Unmarshal([]byte(usagesStr), &usages)
+	start := time.Now()
+	defer func() {
 		if err != nil {
-			return nil, fmt.Errorf("unmarshal http header ExtKeyUsages fail, err: %v", err)
+			recordSignFailure(code, err)
+			return
 		}
+		monitor.CertsSignedTotal.Inc()
+		monitor.SigningSeconds.Observe(time.Since(start).Seconds())
+	}()
+	// the signing may be frozen after the request is accepted, e.g. a queued asynchronous job
+	if err := signingFrozenError(); err != nil {
+		return nil, http.StatusServiceUnavailable, err
+	}
+	validationStart := time.Now()
+	usages, err := certs.ParseEdgeCertUsages(usagesStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
 	}
 	payload, err := io.ReadAll(r)
 	if err != nil {
-		return nil, fmt.Errorf("fail to read file when signing the cert, err: %v", err)
+		return nil, http.StatusInternalServerError,
+			fmt.Errorf("fail to read file when signing the cert, err: %v", err)
+	}
+	csrDER, err := certs.DecodeCSR(payload)
+	if err != nil {
+		return nil, http.StatusBadRequest, newReasonError(types.ReasonCSRInvalid, "invalid CSR, err: %v", err)
 	}
-	edgeCertSigningDuration := hubconfig.Config.CloudHub.EdgeCertSigningDuration * time.Hour * 24
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, http.StatusBadRequest, newReasonError(types.ReasonCSRInvalid, "invalid CSR, err: %v", err)
+	}
+	csrNodeName, err := certs.ValidateEdgeCSR(csr, "", hubconfig.Config.CSRSignatureAlgorithms, int(hubconfig.Config.CSRMinRSAKeySize))
+	if err != nil {
+		return nil, http.StatusBadRequest, &Error{Reason: types.ReasonCSRInvalid, Err: err}
+	}
+	if csrNodeName != nodeName {
+		return nil, http.StatusBadRequest, newReasonError(types.ReasonSubjectMismatch,
+			"the CommonName of the CSR is for node %q, not %q", csrNodeName, nodeName)
+	}
+	policy := signaturePolicy()
+	if err := policy.CheckCSR(csr); err != nil {
+		return nil, http.StatusBadRequest, &Error{Reason: types.ReasonCSRInvalid, Err: err}
+	}
+	extraNames, err := checkCSRSubject(ctx, csr)
+	if err != nil {
+		return nil, http.StatusBadRequest, &Error{Reason: types.ReasonCSRInvalid, Err: err}
+	}
+	if code, err := checkCSRSANs(ctx, csr, nodeName); err != nil {
+		return nil, code, err
+	}
+	var keyUsage x509.KeyUsage
+	if names := hubconfig.Config.EdgeCertKeyUsages; len(names) > 0 {
+		if keyUsage, err = certs.ParseKeyUsages(names); err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("invalid edgeCertKeyUsages config, err: %v", err)
+		}
+		if err := certs.CheckEdgeCertKeyUsage(keyUsage, usages, csr.PublicKey); err != nil {
+			return nil, http.StatusBadRequest, newReasonError(types.ReasonCSRInvalid,
+				"the configured KeyUsage does not fit the request, err: %v", err)
+		}
+	}
+	var uris []*url.URL
+	if id, ok := spiffeID(nodeName); ok {
+		uris = append(uris, id)
+	}
+	exts, err := certExtensions(nodeName)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	edgeCertSigningDuration := certs.ClampEdgeCertDuration(duration)
+	if duration > edgeCertSigningDuration {
+		addWarning(ctx, "the validity period %v of the certificate is clamped to %v", duration, edgeCertSigningDuration)
+	}
+	monitor.SigningValidationSeconds.Observe(time.Since(validationStart).Seconds())
+	if csrAPISignerEnabled() {
+		if len(uris) > 0 {
+			addWarning(ctx, "the SPIFFE ID %s is not added to the certificate signed by the Kubernetes CSR API", uris[0])
+		}
+		if len(exts) > 0 {
+			addWarning(ctx, "the custom extensions are not added to the certificate signed by the Kubernetes CSR API")
+		}
+		return signWithCSRAPI(ctx, csr, nodeName, usages, keyUsage, edgeCertSigningDuration)
+	}
+	caSigner, err := iss.caSigner()
+	if err != nil {
+		return nil, http.StatusInternalServerError, &Error{Reason: types.ReasonSignerUnavailable, Err: err}
+	}
+	backdate := certBackdate(ctx, edgeCertSigningDuration)
 	h := certs.GetHandler(certs.HandlerTypeX509)
-	certBlock, err := h.SignCerts(certs.SignCertsOptionsWithCSR(
-		payload,
-		hubconfig.Config.Ca,
-		hubconfig.Config.CaKey,
-		usages,
-		edgeCertSigningDuration,
-	))
+	certBlock, err = getSigningQueue().run(ctx, func(ctx context.Context) (*pem.Block, error) {
+		signStart := time.Now()
+		defer func() {
+			monitor.SigningKeyOperationSeconds.Observe(time.Since(signStart).Seconds())
+		}()
+		return h.SignCerts(ctx, certs.SignCertsOptionsWithCSR(
+			csrDER,
+			iss.caDER(),
+			nil,
+			usages,
+			edgeCertSigningDuration,
+		).WithCASigner(caSigner).WithKeyUsage(keyUsage).WithSignaturePolicy(policy).WithExtraSubjectNames(extraNames).
+			WithBackdate(backdate).WithURIs(uris...).WithOCSPServers(iss.ocspServers()...).WithExtraExtensions(exts...))
+	})
+	if errors.Is(err, errSigningQueueFull) {
+		return nil, http.StatusServiceUnavailable, err
+	}
+	if terr := signingTimeoutError(ctx); terr != nil {
+		return nil, http.StatusGatewayTimeout, terr
+	}
 	if err != nil {
-		return nil, fmt.Errorf("fail to signCerts, err: %v", err)
+		return nil, http.StatusInternalServerError, newReasonError(types.ReasonSignerUnavailable, "fail to signCerts, err: %v", err)
 	}
-	return certBlock, nil
+	return certBlock, http.StatusOK, nil
 }
@@ -1,17 +1,54 @@
 package certificate
 
 import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/issuancelog"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
 )
 
@@ -33,7 +70,7 @@ func TestVerifyCert(t *testing.T) {
 	}, pk, nil)
 	require.NoError(t, err)
 
-	certPrm, err := certshandler.SignCerts(certs.SignCertsOptionsWithCSR(
+	certPrm, err := certshandler.SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
 		csrPem.Bytes,
 		caPem.Bytes,
 		pk.DER(),
@@ -46,7 +83,7 @@ func TestVerifyCert(t *testing.T) {
 	certs, err := x509.ParseCertificate(certPrm.Bytes)
 	require.NoError(t, err)
 
-	err = verifyCert(certs, "testnode")
+	err = verifyCert(certs, "testnode", nil)
 	require.NoError(t, err)
 }
 
@@ -67,37 +104,51 @@ func TestVerifyAuthorization(t *testing.T) {
 	})
 	passedToken, err := token.SignedString(cakeyDer)
 	require.NoError(t, err)
+	forgedToken, err := token.SignedString([]byte("another key"))
+	require.NoError(t, err)
 
 	cases := []struct {
 		name          string
 		token         string
 		wantCode      int
+		wantReason    string
 		containsError string
 	}{
 		{
 			name:          "token empty",
 			token:         "",
 			wantCode:      http.StatusUnauthorized,
+			wantReason:    types.ReasonTokenMalformed,
 			containsError: "token validation failure, token is empty",
 		},
 		{
 			name:          "not splited token",
 			token:         "xxxx",
 			wantCode:      http.StatusUnauthorized,
+			wantReason:    types.ReasonTokenMalformed,
 			containsError: "token validation failure, token cannot be splited",
 		},
 		{
 			name:          "invalid token",
 			token:         "Bearer xxxx",
 			wantCode:      http.StatusUnauthorized,
+			wantReason:    types.ReasonTokenMalformed,
 			containsError: "token validation failure, err:",
 		},
 		{
 			name:          "expired token",
 			token:         "Bearer " + expiredToken,
 			wantCode:      http.StatusUnauthorized,
+			wantReason:    types.ReasonTokenExpired,
 			containsError: "token validation failure, err: token has invalid claims: token is expire",
 		},
+		{
+			name:          "invalid signature",
+			token:         "Bearer " + forgedToken,
+			wantCode:      http.StatusUnauthorized,
+			wantReason:    types.ReasonTokenInvalidSignature,
+			containsError: "token validation failure, err: token signature is invalid",
+		},
 		{
 			name:     "passed token",
 			token:    "Bearer " + passedToken,
@@ -107,9 +158,480 @@ func TestVerifyAuthorization(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			code, err := verifyAuthorization(c.token)
+			nodes, consumed, code, err := verifyAuthorization(context.TODO(), c.token, "testnode")
+			require.Equal(t, c.wantCode, code)
+			require.Nil(t, nodes)
+			require.Nil(t, consumed)
+			if c.containsError != "" {
+				require.Error(t, err)
+				require.ErrorContains(t, err, c.containsError)
+				require.Equal(t, c.wantReason, signFailureReason(c.wantCode, err))
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSignEdgeCert(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csrBlock, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:testnode",
+	}, pk, nil)
+	require.NoError(t, err)
+	csrPEM := pem.EncodeToMemory(csrBlock)
+
+	cases := []struct {
+		name          string
+		body          []byte
+		wantCode      int
+		containsError string
+	}{
+		{
+			name:     "DER body",
+			body:     csrBlock.Bytes,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "DER body with trailing newline",
+			body:     append(append([]byte{}, csrBlock.Bytes...), '\n'),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "PEM body",
+			body:     csrPEM,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:          "PEM body with garbage prefix",
+			body:          append([]byte("garbage"), csrPEM...),
+			wantCode:      http.StatusBadRequest,
+			containsError: "unexpected data before the PEM block",
+		},
+		{
+			name:          "PEM body with wrong block type",
+			body:          pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caPem.Bytes}),
+			wantCode:      http.StatusBadRequest,
+			containsError: `unexpected PEM block type "CERTIFICATE"`,
+		},
+		{
+			name:          "PEM body with multiple CSRs",
+			body:          bytes.Join([][]byte{csrPEM, csrPEM}, nil),
+			wantCode:      http.StatusBadRequest,
+			containsError: "only one CSR is allowed",
+		},
+		{
+			name:          "DER body with multiple CSRs",
+			body:          bytes.Join([][]byte{csrBlock.Bytes, csrBlock.Bytes}, nil),
+			wantCode:      http.StatusBadRequest,
+			containsError: "only one CSR is allowed",
+		},
+		{
+			name:          "empty body",
+			body:          []byte("\n"),
+			wantCode:      http.StatusBadRequest,
+			containsError: "the CSR is empty",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			certBlock, code, err := signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader(c.body)), "testnode", "", configuredCertDuration(), nil)
+			require.Equal(t, c.wantCode, code)
+			if c.containsError != "" {
+				require.ErrorContains(t, err, c.containsError)
+				return
+			}
+			require.NoError(t, err)
+			cert, err := x509.ParseCertificate(certBlock.Bytes)
+			require.NoError(t, err)
+			require.Equal(t, "system:node:testnode", cert.Subject.CommonName)
+		})
+	}
+}
+
+func TestSignEdgeCertSignatureAlgorithm(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newCSR := func(algo x509.SignatureAlgorithm) []byte {
+		csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+			Subject: pkix.Name{
+				Organization: []string{"system:nodes"},
+				CommonName:   "system:node:testnode",
+			},
+			SignatureAlgorithm: algo,
+		}, rsaKey)
+		require.NoError(t, err)
+		return csr
+	}
+	brokenCSR := newCSR(x509.SHA256WithRSA)
+	brokenCSR[len(brokenCSR)-1] ^= 0xff
+	// stolenKeyCSR carries the public key of rsaKey but is signed by another key
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	stolenKeyCSR := replaceCSRSignature(t, newCSR(x509.SHA256WithRSA), otherKey)
+
+	cases := []struct {
+		name          string
+		allowed       []string
+		body          []byte
+		wantCode      int
+		containsError string
+	}{
+		{
+			name:     "allowed algorithm",
+			body:     newCSR(x509.SHA256WithRSA),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:          "disallowed algorithm",
+			body:          newCSR(x509.SHA1WithRSA),
+			wantCode:      http.StatusBadRequest,
+			containsError: "signature algorithm SHA1-RSA of the CSR is not allowed",
+		},
+		{
+			name:          "algorithm not in the configured list",
+			allowed:       []string{"ECDSA-SHA256"},
+			body:          newCSR(x509.SHA256WithRSA),
+			wantCode:      http.StatusBadRequest,
+			containsError: "signature algorithm SHA256-RSA of the CSR is not allowed",
+		},
+		{
+			name:          "broken self-signature",
+			body:          brokenCSR,
+			wantCode:      http.StatusBadRequest,
+			containsError: "invalid signature of the CSR",
+		},
+		{
+			name:          "signature not matching the public key",
+			body:          stolenKeyCSR,
+			wantCode:      http.StatusBadRequest,
+			containsError: "fails the proof-of-possession of the private key",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubconfig.Config.CSRSignatureAlgorithms = c.allowed
+			defer func() { hubconfig.Config.CSRSignatureAlgorithms = nil }()
+			_, code, err := signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader(c.body)), "testnode", "", configuredCertDuration(), nil)
+			require.Equal(t, c.wantCode, code)
+			if c.containsError != "" {
+				require.ErrorContains(t, err, c.containsError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSignEdgeCertKeyUsage(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+	defer func() { hubconfig.Config.EdgeCertKeyUsages = nil }()
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:testnode",
+	}, pk, nil)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name          string
+		keyUsages     []string
+		usages        string
+		want          x509.KeyUsage
+		wantCode      int
+		containsError string
+	}{
+		{
+			name:     "default KeyUsage of ECDSA keys",
+			want:     x509.KeyUsageDigitalSignature,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "configured KeyUsage",
+			keyUsages: []string{"DigitalSignature", "KeyAgreement"},
+			usages:    "[1,2]",
+			want:      x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement,
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:          "KeyUsage not applicable to the key",
+			keyUsages:     []string{"DigitalSignature", "KeyEncipherment"},
+			wantCode:      http.StatusBadRequest,
+			containsError: "not applicable to ECDSA keys",
+		},
+		{
+			name:          "KeyUsage not fit for the ExtKeyUsages",
+			keyUsages:     []string{"KeyAgreement"},
+			wantCode:      http.StatusBadRequest,
+			containsError: "DigitalSignature is required",
+		},
+		{
+			name:          "unknown KeyUsage",
+			keyUsages:     []string{"Signing"},
+			wantCode:      http.StatusInternalServerError,
+			containsError: "invalid edgeCertKeyUsages config",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubconfig.Config.EdgeCertKeyUsages = c.keyUsages
+			certBlock, code, err := signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader(csr.Bytes)), "testnode", c.usages, configuredCertDuration(), nil)
 			require.Equal(t, c.wantCode, code)
 			if c.containsError != "" {
+				require.ErrorContains(t, err, c.containsError)
+				return
+			}
+			require.NoError(t, err)
+			cert, err := x509.ParseCertificate(certBlock.Bytes)
+			require.NoError(t, err)
+			require.Equal(t, c.want, cert.KeyUsage)
+		})
+	}
+}
+
+func TestSignEdgeCertTimingMetrics(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:testnode",
+	}, pk, nil)
+	require.NoError(t, err)
+
+	sampleCount := func(h prometheus.Histogram) uint64 {
+		m := &dto.Metric{}
+		require.NoError(t, h.Write(m))
+		return m.GetHistogram().GetSampleCount()
+	}
+	validations := sampleCount(monitor.SigningValidationSeconds)
+	keyOperations := sampleCount(monitor.SigningKeyOperationSeconds)
+
+	_, code, err := signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader(csr.Bytes)), "testnode", "", configuredCertDuration(), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, validations+1, sampleCount(monitor.SigningValidationSeconds))
+	require.Equal(t, keyOperations+1, sampleCount(monitor.SigningKeyOperationSeconds))
+
+	// the CSR rejected by the validation never reaches the CA key
+	_, code, _ = signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader([]byte("invalid"))), "testnode", "", configuredCertDuration(), nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, keyOperations+1, sampleCount(monitor.SigningKeyOperationSeconds))
+}
+
+func TestGetCA(t *testing.T) {
+	getCA := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ca.crt", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		recorder := httptest.NewRecorder()
+		GetCA(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	hubconfig.Config.Ca = []byte("ca-v1")
+	resp := getCA("")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, []byte("ca-v1"), resp.Body.Bytes())
+	etag := resp.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	resp = getCA(etag)
+	require.Equal(t, http.StatusNotModified, resp.Code)
+	require.Empty(t, resp.Body.Bytes())
+	require.Equal(t, etag, resp.Header().Get("ETag"))
+
+	resp = getCA(`"other", ` + etag)
+	require.Equal(t, http.StatusNotModified, resp.Code)
+
+	// rotate the CA
+	hubconfig.Config.Ca = []byte("ca-v2")
+	resp = getCA(etag)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, []byte("ca-v2"), resp.Body.Bytes())
+	newETag := resp.Header().Get("ETag")
+	require.NotEmpty(t, newETag)
+	require.NotEqual(t, etag, newETag)
+}
+
+func TestVerifyCertSubject(t *testing.T) {
+	cases := []struct {
+		name          string
+		subject       pkix.Name
+		nodeName      string
+		acceptLegacy  bool
+		wantErr       bool
+		containsError string
+	}{
+		{
+			name: "valid organization first",
+			subject: pkix.Name{
+				Organization: []string{"system:nodes", "example"},
+				CommonName:   "system:node:testnode",
+			},
+		},
+		{
+			name: "valid organization second",
+			subject: pkix.Name{
+				Organization: []string{"example", "system:nodes"},
+				CommonName:   "system:node:testnode",
+			},
+		},
+		{
+			name: "legacy subject accepted",
+			subject: pkix.Name{
+				Organization: []string{"example", "KubeEdge"},
+				CommonName:   "kubeedge.io",
+			},
+			acceptLegacy: true,
+		},
+		{
+			name: "legacy subject rejected",
+			subject: pkix.Name{
+				Organization: []string{"example", "KubeEdge"},
+				CommonName:   "kubeedge.io",
+			},
+			wantErr:       true,
+			containsError: "please rotate the edge certificate of edgenode testnode",
+		},
+		{
+			name: "legacy common name without organization",
+			subject: pkix.Name{
+				CommonName: "kubeedge.io",
+			},
+			acceptLegacy: true,
+			wantErr:      true,
+		},
+		{
+			name: "no valid organization",
+			subject: pkix.Name{
+				Organization: []string{"example", "system:masters"},
+				CommonName:   "system:node:testnode",
+			},
+			wantErr: true,
+		},
+		{
+			name: "no organization",
+			subject: pkix.Name{
+				CommonName: "system:node:testnode",
+			},
+			wantErr:       true,
+			containsError: "it has no Organization",
+		},
+		{
+			name: "empty organization",
+			subject: pkix.Name{
+				Organization: []string{""},
+				CommonName:   "system:node:testnode",
+			},
+			wantErr:       true,
+			containsError: "is not acceptable for edgenode testnode",
+		},
+		{
+			name:          "empty subject",
+			wantErr:       true,
+			containsError: "it has no Organization",
+		},
+		{
+			name: "node name mismatch",
+			subject: pkix.Name{
+				Organization: []string{"system:nodes"},
+				CommonName:   "system:node:othernode",
+			},
+			wantErr:       true,
+			containsError: `CN="system:node:othernode"`,
+		},
+		{
+			name: "unicode common name",
+			subject: pkix.Name{
+				Organization: []string{"system:nodes"},
+				CommonName:   "system:node:tеstnode", // the e is Cyrillic
+			},
+			wantErr:       true,
+			containsError: "is not acceptable for edgenode testnode",
+		},
+		{
+			name: "unicode node name",
+			subject: pkix.Name{
+				Organization: []string{"system:nodes"},
+				CommonName:   "system:node:节点-1",
+			},
+			nodeName: "节点-1",
+		},
+		{
+			name: "long node name",
+			subject: pkix.Name{
+				Organization: []string{"system:nodes"},
+				CommonName:   "system:node:" + strings.Repeat("n", 64*1024),
+			},
+			nodeName: strings.Repeat("n", 64*1024),
+		},
+		{
+			name: "long node name mismatch",
+			subject: pkix.Name{
+				Organization: []string{"system:nodes"},
+				CommonName:   "system:node:" + strings.Repeat("n", 64*1024),
+			},
+			nodeName:      strings.Repeat("n", 64*1024-1),
+			wantErr:       true,
+			containsError: "is not acceptable",
+		},
+	}
+
+	defer func() { hubconfig.Config.AcceptLegacyCertSubject = false }()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubconfig.Config.AcceptLegacyCertSubject = c.acceptLegacy
+			nodeName := c.nodeName
+			if nodeName == "" {
+				nodeName = "testnode"
+			}
+			err := verifyCertSubject(&x509.Certificate{Subject: c.subject, SerialNumber: big.NewInt(1)}, nodeName)
+			if c.wantErr {
 				require.Error(t, err)
 				require.ErrorContains(t, err, c.containsError)
 			} else {
@@ -118,3 +640,806 @@ func TestVerifyAuthorization(t *testing.T) {
 		})
 	}
 }
+
+func FuzzVerifyCertSubject(f *testing.F) {
+	f.Add("system:nodes", "", "system:node:testnode", "testnode")
+	f.Add("", "system:nodes", "system:node:testnode", "testnode")
+	f.Add("", "", "system:node:testnode", "testnode")
+	f.Add("KubeEdge", "example", "kubeedge.io", "testnode")
+	f.Add("system:nodes", "system:masters", "system:node:节点", "节点")
+	f.Add("system:nodes", "", "system:node:", "")
+	f.Fuzz(func(t *testing.T, org1, org2, commonName, nodeName string) {
+		var orgs []string
+		for _, org := range []string{org1, org2} {
+			if org != "" {
+				orgs = append(orgs, org)
+			}
+		}
+		cert := &x509.Certificate{
+			Subject:      pkix.Name{Organization: orgs, CommonName: commonName},
+			SerialNumber: big.NewInt(1),
+		}
+		err := verifyCertSubject(cert, nodeName)
+		want := slices.Contains(orgs, "system:nodes") && commonName == "system:node:"+nodeName
+		if want != (err == nil) {
+			t.Fatalf("verifyCertSubject(O=%q, CN=%q, %q) = %v, want accepted %v", orgs, commonName, nodeName, err, want)
+		}
+		if err != nil && ReasonOf(err) != types.ReasonSubjectMismatch {
+			t.Fatalf("the reason of %v is %q, want %q", err, ReasonOf(err), types.ReasonSubjectMismatch)
+		}
+	})
+}
+
+func TestEdgeCoreClientCertMaxCSRSize(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	https := hubconfig.Config.HTTPS
+	t.Cleanup(func() { hubconfig.Config.HTTPS = https })
+
+	cases := []struct {
+		name string
+		// limit is the MaxCSRSize relative to the size of the CSR
+		limit int64
+		// unknownLength hides the Content-Length of the body, so it is only limited while being read
+		unknownLength bool
+		wantCode      int
+	}{
+		{name: "exactly at the limit", limit: 0, wantCode: http.StatusOK},
+		{name: "oversized", limit: -1, wantCode: http.StatusRequestEntityTooLarge},
+		{name: "exactly at the limit without Content-Length", limit: 0, unknownLength: true, wantCode: http.StatusOK},
+		{name: "oversized without Content-Length", limit: -1, unknownLength: true, wantCode: http.StatusRequestEntityTooLarge},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			maxCSRSize := int64(len(node.CSR)) + c.limit
+			hubconfig.Config.HTTPS = &v1alpha1.CloudHubHTTPS{MaxCSRSize: maxCSRSize}
+			req := node.Request()
+			if c.unknownLength {
+				req.ContentLength = -1
+				req.Body = io.NopCloser(req.Body)
+			}
+			recorder := httptest.NewRecorder()
+			EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+			require.Equal(t, c.wantCode, recorder.Code, recorder.Body.String())
+			if c.wantCode == http.StatusRequestEntityTooLarge {
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf("larger than the limit of %d bytes", maxCSRSize))
+				require.True(t, strings.HasPrefix(recorder.Body.String(), types.ReasonCSRInvalid+": "), recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestEdgeCoreClientCertHeaders(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+
+	recorder := httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, cert.SerialNumber.String(), recorder.Header().Get(types.HeaderCertSerial))
+	notAfter, err := time.Parse(time.RFC3339, recorder.Header().Get(types.HeaderCertNotAfter))
+	require.NoError(t, err)
+	require.True(t, cert.NotAfter.Equal(notAfter))
+	require.Equal(t, cert.SignatureAlgorithm.String(), recorder.Header().Get(types.HeaderCertSignatureAlgorithm))
+}
+
+func TestEdgeCoreClientCertKeyTypes(t *testing.T) {
+	caKey, err := certs.GenPrivateKey(certs.KeyTypeRSA)
+	require.NoError(t, err)
+	ca := testutil.NewCAWithKey(t, caKey)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+
+	cases := []struct {
+		keyType  string
+		keyUsage x509.KeyUsage
+	}{
+		{keyType: certs.KeyTypeECDSAP256, keyUsage: x509.KeyUsageDigitalSignature},
+		{keyType: certs.KeyTypeECDSAP384, keyUsage: x509.KeyUsageDigitalSignature},
+		{keyType: certs.KeyTypeEd25519, keyUsage: x509.KeyUsageDigitalSignature},
+		{keyType: certs.KeyTypeRSA, keyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment},
+	}
+	for _, c := range cases {
+		t.Run(c.keyType, func(t *testing.T) {
+			key, err := certs.GenPrivateKey(c.keyType)
+			require.NoError(t, err)
+			node := ca.NewNodeWithKey(t, "testnode", key)
+
+			recorder := httptest.NewRecorder()
+			EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+			require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+			cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+			require.NoError(t, err)
+			// the certificate is issued for the key of the CSR, signed by the RSA CA
+			keyType, err := certs.KeyType(cert.PublicKey)
+			require.NoError(t, err)
+			require.Equal(t, c.keyType, keyType)
+			signer, err := key.Signer()
+			require.NoError(t, err)
+			require.True(t, signer.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(cert.PublicKey))
+			require.Equal(t, c.keyUsage, cert.KeyUsage)
+			_, err = cert.Verify(x509.VerifyOptions{
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			})
+			require.NoError(t, err)
+
+			// edgehub pairs the certificate with its key
+			_, err = tls.X509KeyPair(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), key.PEM())
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEdgeCoreClientCertEd25519Handshake(t *testing.T) {
+	ca := testutil.NewCA(t)
+	key, err := certs.GenPrivateKey(certs.KeyTypeEd25519)
+	require.NoError(t, err)
+	node := ca.NewNodeWithKey(t, "testnode", key)
+
+	recorder := httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	clientCert, err := tls.X509KeyPair(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: recorder.Body.Bytes()}), key.PEM())
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: roots}
+	srv.StartTLS()
+	defer srv.Close()
+
+	client := srv.Client()
+	client.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{clientCert}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, certs.EdgeNodeCommonNamePrefix+"testnode", string(body))
+}
+
+func TestEdgeCoreClientCertMinRSAKeySize(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	node.CSR, err = x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{
+		Organization: []string{certs.EdgeNodeOrganization},
+		CommonName:   certs.EdgeNodeCommonNamePrefix + node.Name,
+	}}, key)
+	require.NoError(t, err)
+	minRSAKeySize := hubconfig.Config.CSRMinRSAKeySize
+	t.Cleanup(func() { hubconfig.Config.CSRMinRSAKeySize = minRSAKeySize })
+	sign := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	hubconfig.Config.CSRMinRSAKeySize = 0
+	resp := sign()
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "the RSA key size 1024 of the CSR is less than 2048")
+
+	// the labs with constrained hardware lower the minimum deliberately
+	hubconfig.Config.CSRMinRSAKeySize = 1024
+	resp = sign()
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
+
+func TestCertDuration(t *testing.T) {
+	config := &hubconfig.Config.CloudHub
+	duration, maxDuration := config.EdgeCertSigningDuration, config.MaxEdgeCertSigningDuration
+	t.Cleanup(func() {
+		config.EdgeCertSigningDuration, config.MaxEdgeCertSigningDuration = duration, maxDuration
+	})
+	config.EdgeCertSigningDuration = 365
+
+	cases := []struct {
+		name        string
+		header      string
+		maxDuration time.Duration
+		want        time.Duration
+		wantWarning string
+		wantErr     string
+	}{
+		{
+			name:        "no header",
+			maxDuration: 3650,
+			want:        365 * 24 * time.Hour,
+		},
+		{
+			name:        "requested",
+			header:      "720",
+			maxDuration: 3650,
+			want:        720 * time.Hour,
+		},
+		{
+			name:        "longer than the cap",
+			header:      "87601",
+			maxDuration: 3650,
+			want:        3650 * 24 * time.Hour,
+			wantWarning: "the requested validity period of 87601 hours is clamped to 87600h0m0s",
+		},
+		{
+			name:        "overflow",
+			header:      "9223372036854775807",
+			maxDuration: 30,
+			want:        30 * 24 * time.Hour,
+			wantWarning: "is clamped to 720h0m0s",
+		},
+		{
+			name:        "not honored",
+			header:      "720",
+			want:        365 * 24 * time.Hour,
+			wantWarning: "the requested validity period of 720 hours is ignored",
+		},
+		{
+			name:        "not positive",
+			header:      "0",
+			maxDuration: 3650,
+			wantErr:     `invalid X-KubeEdge-Cert-Duration header "0"`,
+		},
+		{
+			name:        "not a number",
+			header:      "30d",
+			maxDuration: 3650,
+			wantErr:     `invalid X-KubeEdge-Cert-Duration header "30d"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config.MaxEdgeCertSigningDuration = c.maxDuration
+			req := httptest.NewRequest(http.MethodGet, constants.DefaultCertURL, nil)
+			if c.header != "" {
+				req.Header.Set(types.HeaderCertDuration, c.header)
+			}
+			warnings := &warningRecorder{}
+			got, err := certDuration(withWarnings(context.TODO(), warnings), req)
+			if c.wantErr != "" {
+				require.ErrorContains(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+			if c.wantWarning == "" {
+				require.Empty(t, warnings.list())
+				return
+			}
+			require.Len(t, warnings.list(), 1)
+			require.Contains(t, warnings.list()[0], c.wantWarning)
+		})
+	}
+}
+
+func TestEdgeCoreClientCertDuration(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	maxDuration := hubconfig.Config.MaxEdgeCertSigningDuration
+	t.Cleanup(func() { hubconfig.Config.MaxEdgeCertSigningDuration = maxDuration })
+	hubconfig.Config.MaxEdgeCertSigningDuration = 30
+
+	req := node.Request()
+	req.Header.Set(types.HeaderCertDuration, "48")
+	req.Header.Set("Accept", types.MIMECertManifest)
+	recorder := httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	var resp types.CertResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	cert, err := x509.ParseCertificate(resp.Certificate)
+	require.NoError(t, err)
+	require.InDelta(t, (48 * time.Hour).Seconds(), cert.NotAfter.Sub(cert.NotBefore).Seconds(), 1)
+	// the rotation follows the lifetime of the certificate rather than the configured one
+	require.Equal(t, 48*time.Hour*8/10, resp.Manifest.RotationInterval.Duration)
+
+	req = node.Request()
+	req.Header.Set(types.HeaderCertDuration, "invalid")
+	recorder = httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusBadRequest, recorder.Code, recorder.Body.String())
+}
+
+func TestEdgeCoreClientCertNodeName(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, issuancelog.Init(ctx, filepath.Join(t.TempDir(), "issuance.log")))
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	newCSR := func(commonName string) []byte {
+		csr, err := certshandler.CreateCSR(pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   commonName,
+		}, pk, nil)
+		require.NoError(t, err)
+		return csr.Bytes
+	}
+
+	cases := []struct {
+		name          string
+		header        string
+		csr           []byte
+		wantCode      int
+		wantNode      string
+		containsError string
+	}{
+		{
+			name:     "header present",
+			header:   "testnode",
+			csr:      newCSR("system:node:testnode"),
+			wantCode: http.StatusOK,
+			wantNode: "testnode",
+		},
+		{
+			name:     "header absent but CSR CommonName present",
+			csr:      newCSR("system:node:cnnode"),
+			wantCode: http.StatusOK,
+			wantNode: "cnnode",
+		},
+		{
+			name:          "both absent",
+			csr:           newCSR("edge"),
+			wantCode:      http.StatusBadRequest,
+			containsError: `the NodeName header is empty and the CommonName "edge" of the CSR is not system:node:<nodeName>`,
+		},
+		{
+			name:          "header absent and CSR invalid",
+			csr:           []byte("invalid"),
+			wantCode:      http.StatusBadRequest,
+			containsError: "the NodeName header is empty and the CSR is invalid",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(c.csr))
+			req.TLS = &tls.ConnectionState{}
+			if c.header != "" {
+				req.Header.Set(types.HeaderNodeName, c.header)
+			}
+			req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+			recorder := httptest.NewRecorder()
+			EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+			require.Equal(t, c.wantCode, recorder.Code, recorder.Body.String())
+			if c.containsError != "" {
+				require.Contains(t, recorder.Body.String(), c.containsError)
+				return
+			}
+
+			cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, "system:node:"+c.wantNode, cert.Subject.CommonName)
+			var recorded certs.IssuanceLogEntry
+			issuancelog.ForEach(func(e certs.IssuanceLogEntry) {
+				if e.Serial == cert.SerialNumber.String() {
+					recorded = e
+				}
+			})
+			require.Equal(t, c.wantNode, recorded.NodeName)
+			require.Equal(t, certs.IssuanceAuthToken, recorded.Authentication)
+			require.Equal(t, []string{"ClientAuth"}, recorded.ExtKeyUsages)
+		})
+	}
+}
+
+func TestEdgeCoreClientCertNodeNameHeader(t *testing.T) {
+	ca := testutil.NewCA(t)
+	https := hubconfig.Config.HTTPS
+	hubconfig.Config.HTTPS = &v1alpha1.CloudHubHTTPS{NodeNameHeader: "X-Edge-Node"}
+	t.Cleanup(func() { hubconfig.Config.HTTPS = https })
+
+	node := ca.NewNode(t, "testnode")
+	req := node.Request()
+	req.Header.Del(types.HeaderNodeName)
+	req.Header.Set("X-Edge-Node", node.Name)
+	recorder := httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "system:node:testnode", cert.Subject.CommonName)
+
+	// the NodeName header is not read, the node name is derived from the CSR
+	req = ca.NewNode(t, "other").Request()
+	req.Header.Set(types.HeaderNodeName, "testnode")
+	recorder = httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	cert, err = x509.ParseCertificate(recorder.Body.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "system:node:other", cert.Subject.CommonName)
+}
+
+func TestVerifyForwardedCerts(t *testing.T) {
+	tenant := testutil.NewCA(t)
+	unknown := testutil.NewCA(t)
+	ca := testutil.NewCA(t)
+	valid := ca.Issue(t, ca.NewNode(t, "testnode"))
+
+	signer, err := ca.Key.Signer()
+	require.NoError(t, err)
+	nodeKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{Organization: []string{"system:nodes"}, CommonName: "system:node:testnode"},
+		NotBefore:    time.Now().Add(-2 * time.Hour),
+		NotAfter:     time.Now().Add(-time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca.Cert, nodeKey.Public(), signer)
+	require.NoError(t, err)
+	expired, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	issuers = map[string]*issuer{"tenant": {name: "tenant", ca: tenant.Cert.Raw}}
+	t.Cleanup(func() {
+		issuers = nil
+	})
+
+	chains, err := VerifyForwardedCerts([]*x509.Certificate{valid})
+	require.NoError(t, err)
+	require.Equal(t, [][]*x509.Certificate{{valid, ca.Cert}}, chains)
+
+	_, err = VerifyForwardedCerts([]*x509.Certificate{expired})
+	require.ErrorContains(t, err, "expired")
+
+	_, err = VerifyForwardedCerts([]*x509.Certificate{unknown.Issue(t, unknown.NewNode(t, "testnode"))})
+	require.ErrorContains(t, err, "unknown authority")
+
+	// the certificates of the issuers are verified too
+	_, err = VerifyForwardedCerts([]*x509.Certificate{tenant.Issue(t, tenant.NewNode(t, "testnode"))})
+	require.NoError(t, err)
+}
+
+func TestVerifyPeerCertificates(t *testing.T) {
+	newCert := func(template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		return cert, key
+	}
+	now := time.Now()
+	root, rootKey := newCert(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "root"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, nil)
+	intermediate, intermediateKey := newCert(&x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "intermediate"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, root, rootKey)
+	leaf, _ := newCert(&x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject: pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:testnode",
+		},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(time.Hour),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, intermediate, intermediateKey)
+	hubconfig.Config.Ca = root.Raw
+
+	cases := []struct {
+		name    string
+		certs   []*x509.Certificate
+		wantErr bool
+	}{
+		{
+			name:  "leaf first",
+			certs: []*x509.Certificate{leaf, intermediate},
+		},
+		{
+			name:  "leaf last",
+			certs: []*x509.Certificate{intermediate, leaf},
+		},
+		{
+			name:    "missing intermediate",
+			certs:   []*x509.Certificate{leaf},
+			wantErr: true,
+		},
+		{
+			name:    "no leaf",
+			certs:   []*x509.Certificate{intermediate},
+			wantErr: true,
+		},
+		{
+			name:    "more than one leaf",
+			certs:   []*x509.Certificate{leaf, intermediate, leaf},
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := verifyPeerCertificates(c.certs, "testnode", nil)
+			if c.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// replaceCSRSignature re-signs the CSR with the key, keeping the public key of the CSR.
+func replaceCSRSignature(t *testing.T, csrDER []byte, key *rsa.PrivateKey) []byte {
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	require.NoError(t, err)
+	digest := sha256.Sum256(csr.RawTBSCertificateRequest)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	var raw struct {
+		TBS       asn1.RawValue
+		Algorithm pkix.AlgorithmIdentifier
+		Signature asn1.BitString
+	}
+	_, err = asn1.Unmarshal(csrDER, &raw)
+	require.NoError(t, err)
+	raw.Signature = asn1.BitString{Bytes: signature, BitLength: len(signature) * 8}
+	der, err := asn1.Marshal(raw)
+	require.NoError(t, err)
+	return der
+}
+
+func TestSignEdgeCertSubjectPolicy(t *testing.T) {
+	testutil.NewCA(t)
+	defer func() { hubconfig.Config.CSRSubjectPolicy = nil }()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	custom := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 55555, 1}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:testnode",
+			Locality:     []string{"Berlin"},
+			ExtraNames: []pkix.AttributeTypeAndValue{
+				{Type: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}, Value: "ops@example.com"},
+				{Type: custom, Value: "custom"},
+			},
+		},
+	}, key)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name          string
+		policy        *v1alpha1.CloudHubCSRSubjectPolicy
+		wantCode      int
+		wantLocality  []string
+		wantNames     int
+		containsError string
+	}{
+		{
+			name:      "strip all by default",
+			wantCode:  http.StatusOK,
+			wantNames: 2,
+		},
+		{
+			name: "strip the attributes not allowed",
+			policy: &v1alpha1.CloudHubCSRSubjectPolicy{
+				Action:            v1alpha1.CSRSubjectStrip,
+				AllowedAttributes: []string{"L"},
+			},
+			wantCode:     http.StatusOK,
+			wantLocality: []string{"Berlin"},
+			wantNames:    3,
+		},
+		{
+			name: "reject the attributes not allowed",
+			policy: &v1alpha1.CloudHubCSRSubjectPolicy{
+				Action:            v1alpha1.CSRSubjectReject,
+				AllowedAttributes: []string{"L", "emailAddress"},
+			},
+			wantCode:      http.StatusBadRequest,
+			containsError: "the Subject attributes [1.3.6.1.4.1.55555.1] of the CSR are not allowed",
+		},
+		{
+			name: "reject nothing if all are allowed",
+			policy: &v1alpha1.CloudHubCSRSubjectPolicy{
+				Action:            v1alpha1.CSRSubjectReject,
+				AllowedAttributes: []string{"L", "emailAddress", "1.3.6.1.4.1.55555.1"},
+			},
+			wantCode:     http.StatusOK,
+			wantLocality: []string{"Berlin"},
+			wantNames:    5,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubconfig.Config.CSRSubjectPolicy = c.policy
+			certBlock, code, err := signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader(csr)), "testnode", "", configuredCertDuration(), nil)
+			require.Equal(t, c.wantCode, code)
+			if c.containsError != "" {
+				require.ErrorContains(t, err, c.containsError)
+				return
+			}
+			require.NoError(t, err)
+			cert, err := x509.ParseCertificate(certBlock.Bytes)
+			require.NoError(t, err)
+			require.Equal(t, "system:node:testnode", cert.Subject.CommonName)
+			require.Equal(t, []string{"system:nodes"}, cert.Subject.Organization)
+			require.Equal(t, c.wantLocality, cert.Subject.Locality)
+			require.Len(t, cert.Subject.Names, c.wantNames)
+		})
+	}
+}
+
+func TestSignEdgeCertSANs(t *testing.T) {
+	testutil.NewCA(t)
+	kubeClient := fake.NewSimpleClientset()
+	patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+		return kubeClient
+	})
+	defer patches.Reset()
+	defer func() { hubconfig.Config.CSRSANPolicy = nil }()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newCSR := func(dnsNames []string, ips ...string) []byte {
+		tmpl := &x509.CertificateRequest{
+			Subject: pkix.Name{
+				Organization: []string{"system:nodes"},
+				CommonName:   "system:node:testnode",
+			},
+			DNSNames: dnsNames,
+		}
+		for _, ip := range ips {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, net.ParseIP(ip))
+		}
+		csr, err := x509.CreateCertificateRequest(rand.Reader, tmpl, key)
+		require.NoError(t, err)
+		return csr
+	}
+	usages, err := json.Marshal([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+	require.NoError(t, err)
+	sign := func(csr []byte) (*x509.Certificate, int, error) {
+		certBlock, code, err := signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader(csr)), "testnode",
+			string(usages), configuredCertDuration(), nil)
+		if err != nil {
+			return nil, code, err
+		}
+		cert, err := x509.ParseCertificate(certBlock.Bytes)
+		require.NoError(t, err)
+		return cert, code, nil
+	}
+
+	// only the loopback SANs are allowed before the node exists
+	cert, code, err := sign(newCSR([]string{"localhost"}, "127.0.0.1", "::1"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"localhost"}, cert.DNSNames)
+	require.Len(t, cert.IPAddresses, 2)
+	_, code, err = sign(newCSR(nil, "192.168.1.10"))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.ErrorContains(t, err, "the IP SAN 192.168.1.10 of the CSR is not an address of the node")
+
+	// the loopback SANs are rejected by the policy
+	hubconfig.Config.CSRSANPolicy = &v1alpha1.CloudHubCSRSANPolicy{RejectLoopback: true}
+	_, code, err = sign(newCSR([]string{"localhost"}))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.ErrorContains(t, err, `the DNS SAN "localhost" of the CSR is not an address of the node`)
+	_, code, err = sign(newCSR(nil, "::1"))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.ErrorContains(t, err, "the IP SAN ::1 of the CSR is not an address of the node")
+	hubconfig.Config.CSRSANPolicy = nil
+
+	// the Node missed by the node lister is got from the apiserver
+	SetNodeLister(corev1listers.NewNodeLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})))
+	defer func() { nodeLister = nil }()
+	_, err = kubeClient.CoreV1().Nodes().Create(context.TODO(), &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "testnode"},
+		Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+			{Type: corev1.NodeHostName, Address: "edge-01"},
+			{Type: corev1.NodeInternalIP, Address: "192.168.1.10"},
+			{Type: corev1.NodeInternalIP, Address: "2001:db8::10"},
+		}},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	cert, code, err = sign(newCSR([]string{"EDGE-01"}, "192.168.1.10", "2001:db8:0:0::10"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"EDGE-01"}, cert.DNSNames)
+	require.True(t, cert.IPAddresses[1].Equal(net.ParseIP("2001:db8::10")))
+
+	_, code, err = sign(newCSR([]string{"edge-01.edge.example.com"}))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.ErrorContains(t, err, `the DNS SAN "edge-01.edge.example.com" of the CSR is not an address of the node`)
+	hubconfig.Config.CSRSANPolicy = &v1alpha1.CloudHubCSRSANPolicy{AllowedDNSSuffixes: []string{".edge.example.com"}}
+	_, code, err = sign(newCSR([]string{"edge-01.edge.example.com"}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	_, code, err = sign(newCSR(nil, "2001:db8::11"))
+	require.Equal(t, http.StatusBadRequest, code)
+	require.ErrorContains(t, err, "the IP SAN 2001:db8::11 of the CSR is not an address of the node")
+}
+
+func TestSignEdgeCertOCSPServer(t *testing.T) {
+	ca := testutil.NewCA(t)
+	defer func() { hubconfig.Config.OCSP = nil }()
+
+	sign := func() *x509.Certificate {
+		n := ca.NewNode(t, "testnode")
+		certBlock, _, err := signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader(n.CSR)), n.Name,
+			"", configuredCertDuration(), nil)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(certBlock.Bytes)
+		require.NoError(t, err)
+		return cert
+	}
+
+	require.Empty(t, sign().OCSPServer)
+	hubconfig.Config.OCSP = &v1alpha1.CloudHubOCSP{Enable: true, URL: "https://10.0.0.1:10002/ocsp"}
+	require.Equal(t, []string{"https://10.0.0.1:10002/ocsp"}, sign().OCSPServer)
+}
+
+// countingWriter counts the writes of the body.
+type countingWriter struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return w.ResponseRecorder.Write(b)
+}
+
+func TestRespondCertWritesOnce(t *testing.T) {
+	ca := testutil.NewCA(t)
+	cert := ca.Issue(t, ca.NewNode(t, "testnode"))
+
+	for _, format := range []certFormat{certFormatDER, certFormatPEM, certFormatPKIXCert, certFormatPEMChain, certFormatManifest} {
+		writer := &countingWriter{ResponseRecorder: httptest.NewRecorder()}
+		respondCert(restful.NewResponse(writer), cert.Raw, format, nil, []string{"a warning"})
+
+		require.Equal(t, http.StatusOK, writer.Code)
+		require.Equal(t, 1, writer.writes, "format %v", format)
+		require.Equal(t, fmt.Sprint(writer.Body.Len()), writer.Header().Get("Content-Length"))
+		require.Equal(t, cert.SerialNumber.String(), writer.Header().Get(types.HeaderCertSerial))
+		require.NotEmpty(t, writer.Header().Get(types.HeaderWarning))
+	}
+}
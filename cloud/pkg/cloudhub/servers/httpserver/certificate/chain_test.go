@@ -0,0 +1,248 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestEdgeCoreClientCertChain(t *testing.T) {
+	ca, root := testutil.NewIntermediateCA(t)
+	chainFile := filepath.Join(t.TempDir(), "intermediate.crt")
+	require.NoError(t, os.WriteFile(chainFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Cert.Raw}), 0600))
+	file := hubconfig.Config.TLSIntermediateCAFile
+	hubconfig.Config.TLSIntermediateCAFile = chainFile
+	t.Cleanup(func() {
+		hubconfig.Config.TLSIntermediateCAFile = file
+		globalChain, globalIntermediates = nil, nil
+	})
+	require.NoError(t, InitIssuers())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.Cert)
+	node := ca.NewNode(t, "testnode")
+	sign := func(accept string) *httptest.ResponseRecorder {
+		req := node.Request()
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	resp := sign(types.MIMEPEMCertChain)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, types.MIMEPEMCertChain, resp.Header().Get("Content-Type"))
+	keyPair, err := tls.X509KeyPair(resp.Body.Bytes(), node.Key.PEM())
+	require.NoError(t, err)
+	require.Len(t, keyPair.Certificate, 2)
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	require.NoError(t, err)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(ca.Cert)
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	require.NoError(t, err)
+
+	// the old clients get the certificate only in DER
+	resp = sign("")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	cert, err := x509.ParseCertificate(resp.Body.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "system:node:testnode", cert.Subject.CommonName)
+}
+
+func TestOfflineRootCA(t *testing.T) {
+	// the root CA is kept offline, the intermediate CA signs the edge certificates
+	ca, root := testutil.NewIntermediateCA(t)
+	chainFile := filepath.Join(t.TempDir(), "intermediate.crt")
+	require.NoError(t, os.WriteFile(chainFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Cert.Raw}), 0600))
+	file, rootCA := hubconfig.Config.TLSIntermediateCAFile, hubconfig.Config.RootCA
+	hubconfig.Config.TLSIntermediateCAFile, hubconfig.Config.RootCA = chainFile, root.Cert.Raw
+	t.Cleanup(func() {
+		hubconfig.Config.TLSIntermediateCAFile, hubconfig.Config.RootCA = file, rootCA
+		globalChain, globalIntermediates = nil, nil
+	})
+	require.NoError(t, InitIssuers())
+	rootOnly := x509.NewCertPool()
+	rootOnly.AddCert(root.Cert)
+
+	node := ca.NewNode(t, "testnode")
+	req := node.Request()
+	req.Header.Set("Accept", types.MIMEPEMCertChain)
+	recorder := httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	keyPair, err := tls.X509KeyPair(recorder.Body.Bytes(), node.Key.PEM())
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	require.NoError(t, err)
+
+	t.Run("CA bundle", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ca.crt", nil)
+		req.Header.Set("Accept", types.MIMEPEMFile)
+		recorder := httptest.NewRecorder()
+		GetCA(restful.NewRequest(req), restful.NewResponse(recorder))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var bundle [][]byte
+		for rest := recorder.Body.Bytes(); len(rest) > 0; {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			require.NotNil(t, block)
+			bundle = append(bundle, block.Bytes)
+		}
+		require.Equal(t, [][]byte{ca.Cert.Raw, root.Cert.Raw}, bundle)
+	})
+
+	t.Run("renewal", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.RenewalRequest(leaf)), restful.NewResponse(recorder))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	})
+
+	t.Run("verified to the root", func(t *testing.T) {
+		// the issuer trusting only the root chains the certificate presented alone by its intermediate CAs
+		iss := &issuer{name: "offline", ca: ca.Cert.Raw, roots: rootOnly, intermediates: []*x509.Certificate{ca.Cert}}
+		require.NoError(t, verifyCert(leaf, "testnode", iss))
+		iss.intermediates = nil
+		require.ErrorContains(t, verifyCert(leaf, "testnode", iss), "certificate signed by unknown authority")
+	})
+
+	t.Run("handshake", func(t *testing.T) {
+		pool, err := hubconfig.Config.CAPool()
+		require.NoError(t, err)
+		for name, clientCAs := range map[string]*x509.CertPool{"CA bundle": pool, "root only": rootOnly} {
+			t.Run(name, func(t *testing.T) {
+				srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+				}))
+				srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+				srv.StartTLS()
+				defer srv.Close()
+				client := srv.Client()
+				client.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{keyPair}
+				resp, err := client.Get(srv.URL)
+				require.NoError(t, err)
+				defer resp.Body.Close()
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+			})
+		}
+	})
+}
+
+func TestEdgeCoreClientCertFormats(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	sign := func(accept string) *httptest.ResponseRecorder {
+		req := node.Request()
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	cases := []struct {
+		name        string
+		accept      string
+		contentType string
+		decode      func(t *testing.T, body []byte) []byte
+	}{
+		{
+			name:   "default",
+			decode: func(_ *testing.T, body []byte) []byte { return body },
+		},
+		{
+			name:        "PEM",
+			accept:      types.MIMEPEMFile,
+			contentType: types.MIMEPEMFile,
+			decode: func(t *testing.T, body []byte) []byte {
+				block, rest := pem.Decode(body)
+				require.NotNil(t, block)
+				require.Equal(t, "CERTIFICATE", block.Type)
+				require.Empty(t, rest)
+				return block.Bytes
+			},
+		},
+		{
+			name:        "DER",
+			accept:      "text/plain, " + types.MIMEPKIXCert,
+			contentType: types.MIMEPKIXCert,
+			decode:      func(_ *testing.T, body []byte) []byte { return body },
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resp := sign(c.accept)
+			require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+			if c.contentType != "" {
+				require.Equal(t, c.contentType, resp.Header().Get("Content-Type"))
+			}
+			cert, err := x509.ParseCertificate(c.decode(t, resp.Body.Bytes()))
+			require.NoError(t, err)
+			require.Equal(t, "system:node:testnode", cert.Subject.CommonName)
+		})
+	}
+}
+
+func TestLoadCAChain(t *testing.T) {
+	ca, root := testutil.NewIntermediateCA(t)
+	dir := t.TempDir()
+	write := func(name string, certs ...*x509.Certificate) string {
+		var data []byte
+		for _, cert := range certs {
+			data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+		}
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(file, data, 0600))
+		return file
+	}
+
+	chain, err := loadCAChain("", ca.Cert.Raw)
+	require.NoError(t, err)
+	require.Nil(t, chain)
+
+	chain, err = loadCAChain(write("chain.crt", ca.Cert, root.Cert), ca.Cert.Raw)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{ca.Cert.Raw, root.Cert.Raw}, chain)
+
+	_, err = loadCAChain(write("root.crt", root.Cert), ca.Cert.Raw)
+	require.ErrorContains(t, err, "is not the CA")
+
+	other := testutil.NewCA(t)
+	_, err = loadCAChain(write("broken.crt", other.Cert, root.Cert), other.Cert.Raw)
+	require.ErrorContains(t, err, "is not signed by the next one")
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"time"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+)
+
+// certBackdate returns how long NotBefore of the edge certificate valid for the duration is set
+// before the signing, so the nodes with their clocks behind accept it. The backdate is limited to
+// half of the duration, e.g. for a node requesting a validity period shorter than EdgeCertBackdate.
+func certBackdate(ctx context.Context, duration time.Duration) time.Duration {
+	backdate := hubconfig.Config.EdgeCertBackdate * time.Minute
+	if backdate > duration/2 {
+		addWarning(ctx, "the backdate %v of the certificate is limited to %v of its validity period %v",
+			backdate, duration/2, duration)
+		backdate = duration / 2
+	}
+	return backdate
+}
+
+// clockSkewTolerance returns the clock skew tolerated when verifying the certificates of edge nodes.
+func clockSkewTolerance() time.Duration {
+	return hubconfig.Config.CertClockSkewTolerance * time.Minute
+}
+
+// skewedVerifyTime returns the time to verify the certificate at again, if it failed the
+// verification only because it is not yet valid, or has expired, within the clock skew tolerance.
+func skewedVerifyTime(cert *x509.Certificate, err error) (time.Time, bool) {
+	var invalid x509.CertificateInvalidError
+	if !errors.As(err, &invalid) || invalid.Reason != x509.Expired {
+		return time.Time{}, false
+	}
+	tolerance := clockSkewTolerance()
+	now := time.Now()
+	switch {
+	case now.Before(cert.NotBefore) && cert.NotBefore.Sub(now) <= tolerance:
+		return cert.NotBefore, true
+	case now.After(cert.NotAfter) && now.Sub(cert.NotAfter) <= tolerance:
+		return cert.NotAfter, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestEdgeCoreClientCertBackdate(t *testing.T) {
+	ca := testutil.NewCA(t)
+	t.Cleanup(func() { hubconfig.Config.EdgeCertBackdate = 0 })
+	hubconfig.Config.EdgeCertBackdate = 5
+
+	start := time.Now().Truncate(time.Second)
+	recorder := httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(ca.NewNode(t, "testnode").Request()), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+	require.NoError(t, err)
+
+	// the certificate is valid on a node whose clock is 4 minutes behind, for the configured day
+	require.False(t, cert.NotBefore.After(start.Add(-4*time.Minute)))
+	require.Equal(t, 24*time.Hour, cert.NotAfter.Sub(cert.NotBefore))
+	require.Empty(t, recorder.Header().Values(types.HeaderWarning))
+}
+
+func TestCertBackdate(t *testing.T) {
+	t.Cleanup(func() { hubconfig.Config.EdgeCertBackdate = 0 })
+	hubconfig.Config.EdgeCertBackdate = 120
+
+	warnings := &warningRecorder{}
+	ctx := withWarnings(context.TODO(), warnings)
+	require.Equal(t, 2*time.Hour, certBackdate(ctx, 24*time.Hour))
+	require.Empty(t, warnings.list())
+	// the backdate is limited for a short validity period
+	require.Equal(t, 30*time.Minute, certBackdate(ctx, time.Hour))
+	require.Equal(t, []string{"the backdate 2h0m0s of the certificate is limited to 30m0s of its validity period 1h0m0s"},
+		warnings.list())
+}
+
+func TestVerifyCertClockSkew(t *testing.T) {
+	ca := testutil.NewCA(t)
+	cert := ca.Issue(t, ca.NewNode(t, "testnode"))
+	t.Cleanup(func() { hubconfig.Config.CertClockSkewTolerance = 0 })
+	// at sets the clock of CloudHub to the time
+	at := func(now time.Time) *gomonkey.Patches {
+		return gomonkey.ApplyFunc(time.Now, func() time.Time { return now })
+	}
+
+	cases := []struct {
+		name      string
+		now       time.Time
+		tolerance time.Duration
+		expectErr bool
+	}{
+		{name: "not yet valid without tolerance", now: cert.NotBefore.Add(-30 * time.Second), expectErr: true},
+		{name: "not yet valid within tolerance", now: cert.NotBefore.Add(-30 * time.Second), tolerance: 1},
+		{name: "not yet valid beyond tolerance", now: cert.NotBefore.Add(-2 * time.Minute), tolerance: 1, expectErr: true},
+		{name: "expired without tolerance", now: cert.NotAfter.Add(30 * time.Second), expectErr: true},
+		{name: "expired within tolerance", now: cert.NotAfter.Add(30 * time.Second), tolerance: 1},
+		{name: "expired beyond tolerance", now: cert.NotAfter.Add(2 * time.Minute), tolerance: 1, expectErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubconfig.Config.CertClockSkewTolerance = c.tolerance
+			patches := at(c.now)
+			defer patches.Reset()
+			err := verifyCert(cert, "testnode", nil)
+			if c.expectErr {
+				require.ErrorContains(t, err, "failed to verify edge certificate")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// parseCSR decodes and parses the CSR payload.
+func parseCSR(payload []byte) (*x509.CertificateRequest, error) {
+	csrDER, err := certs.DecodeCSR(payload)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificateRequest(csrDER)
+}
+
+// nodeNameFromCSR derives the node name from the system:node:<name> CommonName of the CSR,
+// which identifies the node of the request that has no NodeName header.
+func nodeNameFromCSR(payload []byte) (string, error) {
+	csr, err := parseCSR(payload)
+	if err != nil {
+		return "", fmt.Errorf("the %s header is empty and the CSR is invalid, err: %v", hubconfig.Config.NodeNameHeader(), err)
+	}
+	name, ok := strings.CutPrefix(csr.Subject.CommonName, certs.EdgeNodeCommonNamePrefix)
+	if !ok || name == "" {
+		return "", fmt.Errorf("the %s header is empty and the CommonName %q of the CSR is not %s<nodeName>",
+			hubconfig.Config.NodeNameHeader(), csr.Subject.CommonName, certs.EdgeNodeCommonNamePrefix)
+	}
+	return name, nil
+}
+
+// csrTooLargeError returns the error of the CSR of the node exceeding the limit in bytes,
+// which is responded with 413.
+func csrTooLargeError(nodeName string, limit int64) error {
+	return newReasonError(types.ReasonCSRInvalid, "the CSR of edgenode %s is larger than the limit of %d bytes, "+
+		"which is configured by modules.cloudHub.https.maxCSRSize", nodeName, limit)
+}
+
+// checkFreshCSRKey rejects the CSR renewing the previous certificate with the same key if
+// RequireFreshCSRKey is set, so that a renewal proves the possession of a new key.
+func checkFreshCSRKey(payload []byte, previous *x509.Certificate) error {
+	if !hubconfig.Config.RequireFreshCSRKey || previous == nil {
+		return nil
+	}
+	csr, err := parseCSR(payload)
+	if err != nil {
+		// the invalid CSR is rejected by signEdgeCert
+		return nil
+	}
+	if samePublicKey(previous, csr) {
+		return errors.New("the CSR must be generated with a new key to renew the certificate")
+	}
+	return nil
+}
+
+// CheckCSRSubjectPolicy checks that the allowed Subject attributes of the CSR subject policy
+// are valid, it is checked when CloudHub starts.
+func CheckCSRSubjectPolicy() error {
+	if p := hubconfig.Config.CSRSubjectPolicy; p != nil {
+		if _, err := certs.ParseSubjectAttributes(p.AllowedAttributes); err != nil {
+			return fmt.Errorf("invalid CSR subject policy, err: %v", err)
+		}
+	}
+	return nil
+}
+
+// checkCSRSubject applies the CSR subject policy to the Subject attributes of the CSR besides the
+// node identity, and returns the allowed attributes to keep in the certificate. The other attributes
+// are stripped with a warning, or the CSR is rejected if the action of the policy is reject.
+func checkCSRSubject(ctx context.Context, csr *x509.CertificateRequest) ([]pkix.AttributeTypeAndValue, error) {
+	var allowed []string
+	action := cloudcorev1alpha1.CSRSubjectStrip
+	if p := hubconfig.Config.CSRSubjectPolicy; p != nil {
+		allowed = p.AllowedAttributes
+		if p.Action != "" {
+			action = p.Action
+		}
+	}
+	oids, err := certs.ParseSubjectAttributes(allowed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSR subject policy, err: %v", err)
+	}
+	kept, others := certs.FilterCSRSubject(csr, oids)
+	if len(others) == 0 {
+		return kept, nil
+	}
+	if action == cloudcorev1alpha1.CSRSubjectReject {
+		return nil, fmt.Errorf("the Subject attributes %v of the CSR are not allowed", others)
+	}
+	addWarning(ctx, "the Subject attributes %v of the CSR are not allowed and stripped", others)
+	return kept, nil
+}
+
+// checkCSRSANs checks the SANs of the CSR against the addresses in the status of the Node and
+// the CSR SAN policy, see certs.CheckEdgeCSRSANs. Only the loopback addresses, unless the policy
+// rejects them, are allowed if the Node doesn't exist yet. It returns the status code that should
+// be responded when an error occurs.
+func checkCSRSANs(ctx context.Context, csr *x509.CertificateRequest, nodeName string) (int, error) {
+	if len(csr.DNSNames)+len(csr.IPAddresses)+len(csr.EmailAddresses)+len(csr.URIs) == 0 {
+		return http.StatusOK, nil
+	}
+	var addresses, suffixes []string
+	policy := hubconfig.Config.CSRSANPolicy
+	node, err := getNode(ctx, nodeName)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return http.StatusInternalServerError, fmt.Errorf("failed to get the node %s to check the SANs of the CSR, err: %v", nodeName, err)
+	default:
+		for _, address := range node.Status.Addresses {
+			addresses = append(addresses, address.Address)
+		}
+		if policy != nil {
+			suffixes = policy.AllowedDNSSuffixes
+		}
+	}
+	allowLoopback := policy == nil || !policy.RejectLoopback
+	if err := certs.CheckEdgeCSRSANs(csr, addresses, suffixes, allowLoopback); err != nil {
+		return http.StatusBadRequest, &Error{Reason: types.ReasonSubjectMismatch, Err: err}
+	}
+	return http.StatusOK, nil
+}
+
+// getNode gets the Node from the node lister if it is set, and from the apiserver if the lister
+// is not set or misses the Node, e.g. the Node is created just before the request.
+func getNode(ctx context.Context, nodeName string) (*corev1.Node, error) {
+	if nodeLister != nil {
+		node, err := nodeLister.Get(nodeName)
+		if !apierrors.IsNotFound(err) {
+			return node, err
+		}
+	}
+	return client.GetKubeClient().CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// ReasonCSRPending is the reason code of the response when the CertificateSigningRequest of the
+// edge certificate is not issued within the wait timeout of the csr-api signer.
+const ReasonCSRPending = types.ReasonCSRPending
+
+// ReasonCSRDenied is the reason code of the response when the CertificateSigningRequest of the
+// edge certificate is denied or failed by the external approver or signer.
+const ReasonCSRDenied = types.ReasonCSRDenied
+
+// csrAPIPollInterval is the interval of checking whether the CertificateSigningRequest is issued.
+var csrAPIPollInterval = time.Second
+
+// csrAPISignerEnabled returns whether the edge certificates are signed by the Kubernetes CSR API.
+func csrAPISignerEnabled() bool {
+	return hubconfig.Config.EdgeCertSigner == cloudcorev1alpha1.EdgeCertSignerCSRAPI
+}
+
+// extKeyUsagesToK8s maps the extended key usages of edge certificates to the usages of
+// CertificateSigningRequests.
+var extKeyUsagesToK8s = map[x509.ExtKeyUsage]certificatesv1.KeyUsage{
+	x509.ExtKeyUsageClientAuth: certificatesv1.UsageClientAuth,
+	x509.ExtKeyUsageServerAuth: certificatesv1.UsageServerAuth,
+}
+
+// keyUsagesToK8s maps the KeyUsage bits to the usages of CertificateSigningRequests.
+var keyUsagesToK8s = map[x509.KeyUsage]certificatesv1.KeyUsage{
+	x509.KeyUsageDigitalSignature:  certificatesv1.UsageDigitalSignature,
+	x509.KeyUsageContentCommitment: certificatesv1.UsageContentCommitment,
+	x509.KeyUsageKeyEncipherment:   certificatesv1.UsageKeyEncipherment,
+	x509.KeyUsageDataEncipherment:  certificatesv1.UsageDataEncipherment,
+	x509.KeyUsageKeyAgreement:      certificatesv1.UsageKeyAgreement,
+	x509.KeyUsageEncipherOnly:      certificatesv1.UsageEncipherOnly,
+	x509.KeyUsageDecipherOnly:      certificatesv1.UsageDecipherOnly,
+}
+
+// csrAPIUsages returns the usages of the CertificateSigningRequest, the KeyUsage bits default to
+// DigitalSignature, and KeyEncipherment for RSA keys, which are the same as the local signer.
+func csrAPIUsages(keyUsage x509.KeyUsage, usages []x509.ExtKeyUsage, csr *x509.CertificateRequest) []certificatesv1.KeyUsage {
+	if keyUsage == 0 {
+		keyUsage = x509.KeyUsageDigitalSignature
+		if csr.PublicKeyAlgorithm == x509.RSA {
+			keyUsage |= x509.KeyUsageKeyEncipherment
+		}
+	}
+	var k8sUsages []certificatesv1.KeyUsage
+	for bit := x509.KeyUsageDigitalSignature; bit <= x509.KeyUsageDecipherOnly; bit <<= 1 {
+		if u, ok := keyUsagesToK8s[bit]; ok && keyUsage&bit != 0 {
+			k8sUsages = append(k8sUsages, u)
+		}
+	}
+	for _, usage := range usages {
+		if u, ok := extKeyUsagesToK8s[usage]; ok {
+			k8sUsages = append(k8sUsages, u)
+		}
+	}
+	return k8sUsages
+}
+
+var (
+	errCSRDenied = errors.New("denied")
+	errCSRFailed = errors.New("failed")
+)
+
+// signWithCSRAPI creates a CertificateSigningRequest of the edge CSR with the configured signerName
+// and waits for the external approver and signer to issue it. It returns 403 if the request is
+// denied or failed, and 504 if it is still pending after the wait timeout or the signing deadline
+// of the ctx passes.
+func signWithCSRAPI(ctx context.Context, csr *x509.CertificateRequest, nodeName string, usages []x509.ExtKeyUsage,
+	keyUsage x509.KeyUsage, duration time.Duration) (*pem.Block, int, error) {
+	cfg := hubconfig.Config.CSRAPISigner
+	if cfg == nil {
+		return nil, http.StatusInternalServerError, errors.New("the csr-api signer is not configured")
+	}
+	expirationSeconds := int32(min(duration.Seconds(), math.MaxInt32))
+	csrs := client.GetKubeClient().CertificatesV1().CertificateSigningRequests()
+	created, err := csrs.Create(ctx, &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "edge-" + nodeName + "-",
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateRequestBlockType, Bytes: csr.Raw}),
+			SignerName:        cfg.SignerName,
+			ExpirationSeconds: &expirationSeconds,
+			Usages:            csrAPIUsages(keyUsage, usages, csr),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		if terr := signingTimeoutError(ctx); terr != nil {
+			return nil, http.StatusGatewayTimeout, terr
+		}
+		return nil, http.StatusInternalServerError,
+			fmt.Errorf("failed to create the CertificateSigningRequest, err: %v", err)
+	}
+	name := created.Name
+	klog.V(4).Infof("created the CertificateSigningRequest %s of edgenode %s with signer %s", name, nodeName, cfg.SignerName)
+
+	var certPEM []byte
+	waitCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.WaitTimeout)*time.Second)
+	defer cancel()
+	err = wait.PollUntilContextCancel(waitCtx, csrAPIPollInterval, true, func(ctx context.Context) (bool, error) {
+		got, err := csrs.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			// the transient errors are retried until the wait timeout
+			klog.Warningf("failed to get the CertificateSigningRequest %s, err: %v", name, err)
+			return false, nil
+		}
+		for _, c := range got.Status.Conditions {
+			switch c.Type {
+			case certificatesv1.CertificateDenied:
+				return false, fmt.Errorf("%w: %s", errCSRDenied, c.Message)
+			case certificatesv1.CertificateFailed:
+				return false, fmt.Errorf("%w: %s", errCSRFailed, c.Message)
+			}
+		}
+		certPEM = got.Status.Certificate
+		return len(certPEM) > 0, nil
+	})
+	switch {
+	case errors.Is(err, errCSRDenied), errors.Is(err, errCSRFailed):
+		return nil, http.StatusForbidden,
+			fmt.Errorf("%s: the CertificateSigningRequest %s is %w", ReasonCSRDenied, name, err)
+	case err != nil:
+		if terr := signingTimeoutError(ctx); terr != nil {
+			return nil, http.StatusGatewayTimeout, terr
+		}
+		return nil, http.StatusGatewayTimeout,
+			fmt.Errorf("%s: the CertificateSigningRequest %s is not issued within %ds, please retry later",
+				ReasonCSRPending, name, cfg.WaitTimeout)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != certutil.CertificateBlockType {
+		return nil, http.StatusInternalServerError,
+			fmt.Errorf("the CertificateSigningRequest %s is issued with an invalid certificate", name)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, http.StatusInternalServerError,
+			fmt.Errorf("failed to parse the certificate of the CertificateSigningRequest %s, err: %v", name, err)
+	}
+	if !samePublicKey(cert, csr) {
+		return nil, http.StatusInternalServerError,
+			fmt.Errorf("the certificate of the CertificateSigningRequest %s does not match the CSR", name)
+	}
+	return block, http.StatusOK, nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestEdgeCoreClientCertCSRAPISigner(t *testing.T) {
+	// the external signer is not the CA of CloudHub
+	external := testutil.NewCA(t)
+	ca := testutil.NewCA(t)
+
+	config := &hubconfig.Config
+	signer, signerConfig := config.EdgeCertSigner, config.CSRAPISigner
+	t.Cleanup(func() { config.EdgeCertSigner, config.CSRAPISigner = signer, signerConfig })
+	config.EdgeCertSigner = cloudcorev1alpha1.EdgeCertSignerCSRAPI
+	config.CSRAPISigner = &cloudcorev1alpha1.CloudHubCSRAPISigner{
+		SignerName:  "example.com/edge",
+		WaitTimeout: 1,
+	}
+	interval := csrAPIPollInterval
+	csrAPIPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { csrAPIPollInterval = interval })
+
+	issue := func(t *testing.T, csr *certificatesv1.CertificateSigningRequest) {
+		block, _ := pem.Decode(csr.Spec.Request)
+		require.NotNil(t, block)
+		cert, err := certs.GetHandler(certs.HandlerTypeX509).SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+			block.Bytes, external.Cert.Raw, external.Key.DER(), []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour))
+		require.NoError(t, err)
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+			Type: certificatesv1.CertificateApproved,
+		})
+		csr.Status.Certificate = pem.EncodeToMemory(cert)
+	}
+
+	cases := []struct {
+		name string
+		// sign settles the CertificateSigningRequest on creation as the external approver and signer
+		sign       func(t *testing.T, csr *certificatesv1.CertificateSigningRequest)
+		wantCode   int
+		wantReason string
+	}{
+		{
+			name:     "issued",
+			sign:     issue,
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "denied",
+			sign: func(_ *testing.T, csr *certificatesv1.CertificateSigningRequest) {
+				csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+					Type:    certificatesv1.CertificateDenied,
+					Message: "not allowed",
+				})
+			},
+			wantCode:   http.StatusForbidden,
+			wantReason: ReasonCSRDenied,
+		},
+		{
+			name: "failed",
+			sign: func(_ *testing.T, csr *certificatesv1.CertificateSigningRequest) {
+				csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+					Type: certificatesv1.CertificateApproved,
+				}, certificatesv1.CertificateSigningRequestCondition{
+					Type:    certificatesv1.CertificateFailed,
+					Message: "signer error",
+				})
+			},
+			wantCode:   http.StatusForbidden,
+			wantReason: ReasonCSRDenied,
+		},
+		{
+			name:       "pending",
+			sign:       func(*testing.T, *certificatesv1.CertificateSigningRequest) {},
+			wantCode:   http.StatusGatewayTimeout,
+			wantReason: ReasonCSRPending,
+		},
+		{
+			name: "mismatched certificate",
+			sign: func(t *testing.T, csr *certificatesv1.CertificateSigningRequest) {
+				other := external.Issue(t, external.NewNode(t, "other"))
+				csr.Status.Certificate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: other.Raw})
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var created *certificatesv1.CertificateSigningRequest
+			kubeClient := fake.NewSimpleClientset()
+			kubeClient.PrependReactor("create", "certificatesigningrequests",
+				func(action k8stesting.Action) (bool, runtime.Object, error) {
+					csr := action.(k8stesting.CreateAction).GetObject().(*certificatesv1.CertificateSigningRequest)
+					csr.Name = csr.GenerateName + "abcde"
+					created = csr.DeepCopy()
+					c.sign(t, csr)
+					// the object is created by the tracker
+					return false, nil, nil
+				})
+			patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+				return kubeClient
+			})
+			defer patches.Reset()
+
+			node := ca.NewNode(t, "testnode")
+			recorder := httptest.NewRecorder()
+			EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+			require.Equal(t, c.wantCode, recorder.Code, recorder.Body.String())
+			if c.wantReason != "" {
+				require.True(t, strings.HasPrefix(recorder.Body.String(), c.wantReason+":"), recorder.Body.String())
+			}
+
+			require.NotNil(t, created)
+			require.Equal(t, "edge-testnode-abcde", created.Name)
+			require.Equal(t, "example.com/edge", created.Spec.SignerName)
+			require.Equal(t, []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageClientAuth},
+				created.Spec.Usages)
+			require.Equal(t, int32(24*time.Hour/time.Second), *created.Spec.ExpirationSeconds)
+			if c.wantCode != http.StatusOK {
+				return
+			}
+			cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+			require.NoError(t, err)
+			require.NoError(t, cert.CheckSignatureFrom(external.Cert))
+			require.Equal(t, fmt.Sprintf("system:node:%s", node.Name), cert.Subject.CommonName)
+		})
+	}
+}
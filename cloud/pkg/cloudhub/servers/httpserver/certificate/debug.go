@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/clientip"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// DebugCertInfo is the response body of the certinfo debug endpoint, it describes how the client
+// certificate of the request is seen by CloudHub to diagnose the mTLS setup, e.g. behind a gateway.
+type DebugCertInfo struct {
+	// PeerCertificate indicates whether a client certificate is presented, directly or forwarded.
+	PeerCertificate bool `json:"peerCertificate"`
+	// Certificate is the end-entity certificate presented, it is nil if no certificate is presented.
+	Certificate *DebugCertificate `json:"certificate,omitempty"`
+	// Verified indicates whether the certificate is verified by the CA bundle of CloudHub.
+	Verified bool `json:"verified"`
+	// VerifyError is the reason why the certificate is not verified.
+	VerifyError string `json:"verifyError,omitempty"`
+	// ForwardedCert is how the forwarded client certificate header is handled.
+	ForwardedCert clientip.ForwardedCertStatus `json:"forwardedCert"`
+	// AuthPath is how a certificate request would be authenticated, one of certificate, token
+	// and preregistration.
+	AuthPath string `json:"authPath"`
+	// ClientIP is the client IP resolved from the request.
+	ClientIP string `json:"clientIP"`
+}
+
+// DebugCertificate is the summary of a client certificate.
+type DebugCertificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	Serial    string    `json:"serial"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+}
+
+// GetDebugCertInfo echoes the client certificate seen by CloudHub and which authentication path
+// a certificate request would take. Nothing is signed, and the token is never verified nor echoed.
+func GetDebugCertInfo(request *restful.Request, response *restful.Response) {
+	r := request.Request
+	info := DebugCertInfo{
+		ForwardedCert: clientip.ForwardedCertFromRequest(r),
+		ClientIP:      clientip.FromRequest(r),
+	}
+	var peerCerts []*x509.Certificate
+	if r.TLS != nil {
+		peerCerts = r.TLS.PeerCertificates
+	}
+	switch {
+	case len(peerCerts) > 0:
+		info.PeerCertificate = true
+		info.AuthPath = certs.IssuanceAuthCertificate
+		describePeerCertificates(r, peerCerts, &info)
+	case r.Header.Get(types.HeaderAuthorization) != "":
+		info.AuthPath = certs.IssuanceAuthToken
+	default:
+		info.AuthPath = certs.IssuanceAuthPreRegistration
+	}
+
+	bff, err := json.Marshal(info)
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	response.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	resps.OK(response, bff)
+}
+
+// describePeerCertificates fills the summary of the end-entity certificate in peerCerts and
+// the result of verifying it as EdgeCoreClientCert does.
+func describePeerCertificates(r *http.Request, peerCerts []*x509.Certificate, info *DebugCertInfo) {
+	leaf := peerCerts[0]
+	for _, cert := range peerCerts {
+		if !cert.IsCA {
+			leaf = cert
+			break
+		}
+	}
+	info.Certificate = &DebugCertificate{
+		Subject:   leaf.Subject.String(),
+		Issuer:    leaf.Issuer.String(),
+		Serial:    leaf.SerialNumber.String(),
+		NotBefore: leaf.NotBefore.UTC(),
+		NotAfter:  leaf.NotAfter.UTC(),
+	}
+
+	nodeName := r.Header.Get(hubconfig.Config.NodeNameHeader())
+	if nodeName == "" {
+		nodeName = strings.TrimPrefix(leaf.Subject.CommonName, certs.EdgeNodeCommonNamePrefix)
+	}
+	iss, err := selectIssuer(r, nodeName)
+	if err == nil {
+		_, err = verifyPeerCertificates(peerCerts, nodeName, iss)
+	}
+	if err != nil {
+		info.VerifyError = err.Error()
+		return
+	}
+	info.Verified = true
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/clientip"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestGetDebugCertInfo(t *testing.T) {
+	unknown := testutil.NewCA(t)
+	untrusted := unknown.Issue(t, unknown.NewNode(t, "testnode"))
+	ca := testutil.NewCA(t)
+	valid := ca.Issue(t, ca.NewNode(t, "testnode"))
+
+	trusted, err := clientip.ParseTrustedProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+	cf := clientip.NewCertFilter(trusted, "", false, clientip.CertFormatAuto, VerifyForwardedCerts)
+
+	forwarded := func(cert *x509.Certificate) string {
+		return url.PathEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})))
+	}
+	cases := []struct {
+		name          string
+		remoteAddr    string
+		direct        *x509.Certificate
+		forwarded     string
+		authorization string
+		want          DebugCertInfo
+		wantSerial    string
+		wantVerifyErr string
+	}{
+		{
+			name:       "direct mTLS",
+			remoteAddr: "192.0.2.10:34567",
+			direct:     valid,
+			want: DebugCertInfo{
+				PeerCertificate: true,
+				Verified:        true,
+				ForwardedCert:   clientip.ForwardedCertNone,
+				AuthPath:        certs.IssuanceAuthCertificate,
+				ClientIP:        "192.0.2.10",
+			},
+			wantSerial: valid.SerialNumber.String(),
+		},
+		{
+			name:       "direct mTLS with an unknown CA",
+			remoteAddr: "192.0.2.10:34567",
+			direct:     untrusted,
+			want: DebugCertInfo{
+				PeerCertificate: true,
+				ForwardedCert:   clientip.ForwardedCertNone,
+				AuthPath:        certs.IssuanceAuthCertificate,
+				ClientIP:        "192.0.2.10",
+			},
+			wantSerial:    untrusted.SerialNumber.String(),
+			wantVerifyErr: "unknown authority",
+		},
+		{
+			name:       "forwarded by a trusted gateway",
+			remoteAddr: "10.0.0.1:34567",
+			forwarded:  forwarded(valid),
+			want: DebugCertInfo{
+				PeerCertificate: true,
+				Verified:        true,
+				ForwardedCert:   clientip.ForwardedCertApplied,
+				AuthPath:        certs.IssuanceAuthCertificate,
+				ClientIP:        "10.0.0.1",
+			},
+			wantSerial: valid.SerialNumber.String(),
+		},
+		{
+			name:       "forwarded by an untrusted peer",
+			remoteAddr: "192.0.2.10:34567",
+			forwarded:  forwarded(valid),
+			want: DebugCertInfo{
+				ForwardedCert: clientip.ForwardedCertStripped,
+				AuthPath:      certs.IssuanceAuthPreRegistration,
+				ClientIP:      "192.0.2.10",
+			},
+		},
+		{
+			name:          "token",
+			remoteAddr:    "192.0.2.10:34567",
+			authorization: "Bearer " + ca.Token(t, time.Minute),
+			want: DebugCertInfo{
+				ForwardedCert: clientip.ForwardedCertNone,
+				AuthPath:      certs.IssuanceAuthToken,
+				ClientIP:      "192.0.2.10",
+			},
+		},
+		{
+			name:       "anonymous",
+			remoteAddr: "192.0.2.10:34567",
+			want: DebugCertInfo{
+				ForwardedCert: clientip.ForwardedCertNone,
+				AuthPath:      certs.IssuanceAuthPreRegistration,
+				ClientIP:      "192.0.2.10",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ws := new(restful.WebService)
+			ws.Path("/")
+			ws.Filter(clientip.NewFilter(trusted))
+			ws.Filter(cf.FilterCert)
+			ws.Route(ws.GET(constants.DefaultDebugCertInfoURL).To(GetDebugCertInfo))
+			container := restful.NewContainer()
+			container.Add(ws)
+
+			req := httptest.NewRequest(http.MethodGet, constants.DefaultDebugCertInfoURL, nil)
+			req.RemoteAddr = c.remoteAddr
+			req.TLS = &tls.ConnectionState{}
+			if c.direct != nil {
+				req.TLS.PeerCertificates = []*x509.Certificate{c.direct}
+			}
+			if c.forwarded != "" {
+				req.Header.Set(clientip.HeaderXForwardedClientCert, c.forwarded)
+			}
+			if c.authorization != "" {
+				req.Header.Set(types.HeaderAuthorization, c.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			container.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+			require.Equal(t, restful.MIME_JSON, recorder.Header().Get(restful.HEADER_ContentType))
+			// the token is never echoed
+			if c.authorization != "" {
+				require.NotContains(t, recorder.Body.String(), c.authorization)
+			}
+
+			var got DebugCertInfo
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			if c.wantSerial == "" {
+				require.Nil(t, got.Certificate)
+			} else {
+				require.NotNil(t, got.Certificate)
+				require.Equal(t, c.wantSerial, got.Certificate.Serial)
+				require.Equal(t, "CN=system:node:testnode,O=system:nodes", got.Certificate.Subject)
+			}
+			require.Contains(t, got.VerifyError, c.wantVerifyErr)
+			got.Certificate, got.VerifyError = nil, ""
+			require.Equal(t, c.want, got)
+		})
+	}
+}
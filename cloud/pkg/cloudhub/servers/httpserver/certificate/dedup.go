@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+)
+
+// maxCSRDedupEntries bounds the number of the certificates kept for the repeated CSRs.
+const maxCSRDedupEntries = 1024
+
+// csrDedupEntry is the certificate signed for a CSR, digest identifies the request it is signed for.
+type csrDedupEntry struct {
+	digest    []byte
+	certDER   []byte
+	expiresAt time.Time
+}
+
+// csrDedup keeps the certificates signed in the dedup window by the node name and the public key
+// of the CSR, so an edge node in a restart loop posting the same CSR again gets the same
+// certificate rather than a new serial.
+type csrDedup struct {
+	mu      sync.Mutex
+	entries map[string]csrDedupEntry
+	max     int
+	now     func() time.Time
+}
+
+var defaultCSRDedup = newCSRDedup(maxCSRDedupEntries)
+
+func newCSRDedup(max int) *csrDedup {
+	return &csrDedup{
+		entries: make(map[string]csrDedupEntry),
+		max:     max,
+		now:     time.Now,
+	}
+}
+
+// csrDedupWindow returns the window in which an identical CSR is deduplicated, 0 if disabled.
+func csrDedupWindow() time.Duration {
+	return time.Duration(hubconfig.Config.CSRDedupWindow) * time.Second
+}
+
+// csrDedupKey returns the key of the CSR of the node, which is the node name and the fingerprint
+// of the public key, and the digest of the request, which covers the CSR and everything else
+// that goes into the certificate. ok is false if the deduplication is disabled or the CSR is invalid.
+func csrDedupKey(payload []byte, nodeName, usagesStr string, duration time.Duration,
+	iss *issuer) (key string, digest []byte, ok bool) {
+	if csrDedupWindow() <= 0 {
+		return "", nil, false
+	}
+	csr, err := parseCSR(payload)
+	if err != nil {
+		return "", nil, false
+	}
+	fingerprint := sha256.Sum256(csr.RawSubjectPublicKeyInfo)
+	h := sha256.New()
+	h.Write(csr.Raw)
+	h.Write([]byte(usagesStr))
+	h.Write([]byte(strconv.FormatInt(int64(duration), 10)))
+	h.Write(iss.caDER())
+	return nodeName + "/" + hex.EncodeToString(fingerprint[:]), h.Sum(nil), true
+}
+
+// lookup returns the certificate signed in the window for the request of the key and digest,
+// a revoked certificate is dropped and not returned.
+func (d *csrDedup) lookup(key string, digest []byte) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.entries[key]
+	if !ok || !d.now().Before(e.expiresAt) || !bytes.Equal(e.digest, digest) {
+		return nil, false
+	}
+	if cert, err := x509.ParseCertificate(e.certDER); err != nil || revocation.DefaultList.IsRevoked(cert) {
+		delete(d.entries, key)
+		return nil, false
+	}
+	return e.certDER, true
+}
+
+// store keeps the certificate signed for the request of the key and digest for the window.
+// When the cache is full, the entry expiring first is evicted.
+func (d *csrDedup) store(key string, digest, certDER []byte, window time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for k, e := range d.entries {
+		if !now.Before(e.expiresAt) {
+			delete(d.entries, k)
+		}
+	}
+	if _, ok := d.entries[key]; !ok && len(d.entries) >= d.max {
+		var oldest string
+		for k, e := range d.entries {
+			if oldest == "" || e.expiresAt.Before(d.entries[oldest].expiresAt) {
+				oldest = k
+			}
+		}
+		delete(d.entries, oldest)
+	}
+	d.entries[key] = csrDedupEntry{digest: digest, certDER: certDER, expiresAt: now.Add(window)}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+func TestCSRDedup(t *testing.T) {
+	ca := testutil.NewCA(t)
+	cert := ca.Issue(t, ca.NewNode(t, "node1"))
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := newCSRDedup(2)
+	d.now = func() time.Time { return now }
+
+	d.store("node1/a", []byte("digest"), cert.Raw, time.Minute)
+	certDER, ok := d.lookup("node1/a", []byte("digest"))
+	require.True(t, ok)
+	require.Equal(t, cert.Raw, certDER)
+	// a different request with the same key is not deduplicated
+	_, ok = d.lookup("node1/a", []byte("other"))
+	require.False(t, ok)
+	_, ok = d.lookup("node1/b", []byte("digest"))
+	require.False(t, ok)
+
+	// the entry expiring first is evicted when the cache is full
+	now = now.Add(time.Second)
+	d.store("node2/a", []byte("digest"), cert.Raw, time.Minute)
+	d.store("node3/a", []byte("digest"), cert.Raw, time.Minute)
+	require.Len(t, d.entries, 2)
+	_, ok = d.lookup("node1/a", []byte("digest"))
+	require.False(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = d.lookup("node2/a", []byte("digest"))
+	require.False(t, ok)
+
+	revocation.DefaultList = revocation.NewList()
+	t.Cleanup(func() { revocation.DefaultList = revocation.NewList() })
+	d.store("node1/a", []byte("digest"), cert.Raw, time.Minute)
+	revocation.DefaultList.Revoke(cert)
+	_, ok = d.lookup("node1/a", []byte("digest"))
+	require.False(t, ok)
+	require.NotContains(t, d.entries, "node1/a")
+}
+
+func TestCSRDedupConcurrent(t *testing.T) {
+	ca := testutil.NewCA(t)
+	cert := ca.Issue(t, ca.NewNode(t, "node1"))
+	d := newCSRDedup(8)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("node%d/a", i)
+			d.store(key, []byte("digest"), cert.Raw, time.Minute)
+			d.lookup(key, []byte("digest"))
+		}(i)
+	}
+	wg.Wait()
+	require.Len(t, d.entries, 8)
+}
+
+func TestEdgeCoreClientCertDedup(t *testing.T) {
+	ca := testutil.NewCA(t)
+	hubconfig.Config.CSRDedupWindow = 60
+	defaultCSRDedup = newCSRDedup(maxCSRDedupEntries)
+	t.Cleanup(func() {
+		hubconfig.Config.CSRDedupWindow = 0
+		defaultCSRDedup = newCSRDedup(maxCSRDedupEntries)
+	})
+
+	sign := func(node *testutil.Node) *x509.Certificate {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+		require.NoError(t, err)
+		return cert
+	}
+	node := ca.NewNode(t, "testnode")
+	signed := promtestutil.ToFloat64(monitor.CertsSignedTotal)
+	hits := promtestutil.ToFloat64(monitor.CSRDedupHitsTotal)
+	cert := sign(node)
+	require.Equal(t, cert.Raw, sign(node).Raw)
+	require.Equal(t, signed+1, promtestutil.ToFloat64(monitor.CertsSignedTotal))
+	require.Equal(t, hits+1, promtestutil.ToFloat64(monitor.CSRDedupHitsTotal))
+
+	// a new key of the same node bypasses the cache
+	renewed := sign(ca.NewNode(t, "testnode"))
+	require.NotEqual(t, cert.SerialNumber, renewed.SerialNumber)
+	require.Equal(t, signed+2, promtestutil.ToFloat64(monitor.CertsSignedTotal))
+
+	// every CSR is signed when the deduplication is disabled
+	hubconfig.Config.CSRDedupWindow = 0
+	require.NotEqual(t, cert.SerialNumber, sign(node).SerialNumber)
+}
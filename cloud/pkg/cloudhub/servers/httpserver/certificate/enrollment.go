@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/nodes"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// ReasonDuplicateEnrollment is the reason code of the response and the event when a certificate
+// is requested for a node name that already has an active session with a different key.
+const ReasonDuplicateEnrollment = types.ReasonDuplicateEnrollment
+
+// SessionStore looks up the sessions of the edge nodes connected to cloudhub.
+type SessionStore interface {
+	// PeerCertificate returns the client certificate of the active session of the node.
+	PeerCertificate(nodeName string) (*x509.Certificate, bool)
+	// CloseSession terminates the active session of the node.
+	CloseSession(nodeName string)
+}
+
+var sessionStore SessionStore
+
+// SetSessionStore sets the session store used to detect duplicate enrollments.
+func SetSessionStore(store SessionStore) {
+	sessionStore = store
+}
+
+func duplicateEnrollmentPolicy() cloudcorev1alpha1.DuplicateEnrollmentPolicy {
+	if c := hubconfig.Config.DuplicateEnrollment; c != nil && c.Policy != "" {
+		return c.Policy
+	}
+	return cloudcorev1alpha1.DuplicateEnrollmentAllow
+}
+
+// checkDuplicateEnrollment checks whether the node already has an active session with a different
+// key, and applies the duplicate enrollment policy. It returns the certificate of the active session
+// if the session should be fenced after the new certificate is signed. The renewed is the certificate
+// presented by the request, which is nil if the request is not a renewal.
+func checkDuplicateEnrollment(ctx context.Context, nodeName, clientIP string, payload []byte,
+	renewed *x509.Certificate) (*x509.Certificate, int, error) {
+	if sessionStore == nil || nodeName == "" {
+		return nil, http.StatusOK, nil
+	}
+	active, ok := sessionStore.PeerCertificate(nodeName)
+	if !ok || active == nil || renewsActive(renewed, active) {
+		return nil, http.StatusOK, nil
+	}
+	csr, err := parseCSR(payload)
+	if err != nil {
+		// the invalid CSR is rejected by signEdgeCert
+		return nil, http.StatusOK, nil
+	}
+	if samePublicKey(active, csr) {
+		// the renewal of the active session is always allowed
+		return nil, http.StatusOK, nil
+	}
+
+	policy := duplicateEnrollmentPolicy()
+	var decision string
+	switch policy {
+	case cloudcorev1alpha1.DuplicateEnrollmentReject:
+		decision = "rejected"
+	case cloudcorev1alpha1.DuplicateEnrollmentFence:
+		decision = "signed and fenced the active session"
+	default:
+		decision = "allowed"
+	}
+	klog.InfoS("Audit duplicate enrollment", "node", nodeName, "clientIP", clientIP,
+		"policy", policy, "decision", decision, "activeSerial", active.SerialNumber.String())
+	recordEnrollmentEvent(ctx, nodeName, ReasonDuplicateEnrollment, fmt.Sprintf(
+		"certificate requested from %s while the node has an active session with a different key (serial %s), policy %s: %s",
+		clientIP, active.SerialNumber.String(), policy, decision))
+
+	switch policy {
+	case cloudcorev1alpha1.DuplicateEnrollmentReject:
+		return nil, http.StatusConflict, fmt.Errorf("%s: edgenode %s already has an active session with a different key",
+			ReasonDuplicateEnrollment, nodeName)
+	case cloudcorev1alpha1.DuplicateEnrollmentFence:
+		return active, http.StatusOK, nil
+	default:
+		return nil, http.StatusOK, nil
+	}
+}
+
+// fence revokes the certificate of the active session and drops the session.
+func fence(nodeName string, active *x509.Certificate) {
+	revocation.DefaultList.Revoke(active)
+	sessionStore.CloseSession(nodeName)
+	klog.InfoS("Audit fenced the active session", "node", nodeName, "revokedSerial", active.SerialNumber.String())
+}
+
+// renewsActive returns whether the renewed certificate is the one of the active session, i.e.
+// the node rotates the key of its own session, which is a renewal rather than a duplicate enrollment.
+func renewsActive(renewed, active *x509.Certificate) bool {
+	return renewed != nil && renewed.SerialNumber.Cmp(active.SerialNumber) == 0
+}
+
+func samePublicKey(cert *x509.Certificate, csr *x509.CertificateRequest) bool {
+	certKey, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return false
+	}
+	csrKey, err := x509.MarshalPKIXPublicKey(csr.PublicKey)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(certKey, csrKey)
+}
+
+// recordEnrollmentEvent records a warning event of the node with the reason.
+func recordEnrollmentEvent(ctx context.Context, nodeName, reason, message string) {
+	nodes.RecordEvent(ctx, client.GetKubeClient(), nodeName, corev1.EventTypeWarning, reason, message)
+}
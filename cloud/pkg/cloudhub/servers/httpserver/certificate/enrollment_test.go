@@ -0,0 +1,206 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+type fakeSessionStore struct {
+	certs  map[string]*x509.Certificate
+	closed []string
+}
+
+func (f *fakeSessionStore) PeerCertificate(nodeName string) (*x509.Certificate, bool) {
+	cert, ok := f.certs[nodeName]
+	return cert, ok
+}
+
+func (f *fakeSessionStore) CloseSession(nodeName string) {
+	f.closed = append(f.closed, nodeName)
+	delete(f.certs, nodeName)
+}
+
+func TestEdgeCoreClientCertDuplicateEnrollment(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+		return kubeClient
+	})
+	defer patches.Reset()
+
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+	defer func() { hubconfig.Config.DuplicateEnrollment = nil }()
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	newCSR := func() []byte {
+		pk, err := certshandler.GenPrivateKey()
+		require.NoError(t, err)
+		csr, err := certshandler.CreateCSR(pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:testnode",
+		}, pk, nil)
+		require.NoError(t, err)
+		return csr.Bytes
+	}
+	// request requests the certificate with the token, or with the certificate peer if it is set
+	request := func(csr []byte, peer *x509.Certificate) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr))
+		req.TLS = &tls.ConnectionState{}
+		req.Header.Set(types.HeaderNodeName, "testnode")
+		if peer != nil {
+			req.TLS.PeerCertificates = []*x509.Certificate{peer}
+		} else {
+			req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+		}
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	// the certificate of the active session
+	activeCSR := newCSR()
+	resp := request(activeCSR, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	activeCert, err := x509.ParseCertificate(resp.Body.Bytes())
+	require.NoError(t, err)
+
+	origin := sessionStore
+	defer func() { sessionStore = origin }()
+
+	cases := []struct {
+		name       string
+		policy     v1alpha1.DuplicateEnrollmentPolicy
+		csr        []byte
+		peer       *x509.Certificate
+		wantCode   int
+		wantEvent  bool
+		wantFenced bool
+	}{
+		{
+			name:     "same key renewal is allowed with reject policy",
+			policy:   v1alpha1.DuplicateEnrollmentReject,
+			csr:      activeCSR,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "key rotation of the active session is allowed with reject policy",
+			policy:   v1alpha1.DuplicateEnrollmentReject,
+			csr:      newCSR(),
+			peer:     activeCert,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "key rotation of the active session is not fenced",
+			policy:   v1alpha1.DuplicateEnrollmentFence,
+			csr:      newCSR(),
+			peer:     activeCert,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "reject",
+			policy:    v1alpha1.DuplicateEnrollmentReject,
+			csr:       newCSR(),
+			wantCode:  http.StatusConflict,
+			wantEvent: true,
+		},
+		{
+			name:      "allow",
+			policy:    v1alpha1.DuplicateEnrollmentAllow,
+			csr:       newCSR(),
+			wantCode:  http.StatusOK,
+			wantEvent: true,
+		},
+		{
+			name:       "fence",
+			policy:     v1alpha1.DuplicateEnrollmentFence,
+			csr:        newCSR(),
+			wantCode:   http.StatusOK,
+			wantEvent:  true,
+			wantFenced: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store := &fakeSessionStore{certs: map[string]*x509.Certificate{"testnode": activeCert}}
+			sessionStore = store
+			revocation.DefaultList = revocation.NewList()
+			hubconfig.Config.DuplicateEnrollment = &v1alpha1.CloudHubDuplicateEnrollment{Policy: c.policy}
+			kubeClient = fake.NewSimpleClientset()
+
+			resp := request(c.csr, c.peer)
+			require.Equal(t, c.wantCode, resp.Code, resp.Body.String())
+			if c.wantCode == http.StatusConflict {
+				require.True(t, strings.HasPrefix(resp.Body.String(), ReasonDuplicateEnrollment))
+			}
+
+			events, err := kubeClient.CoreV1().Events(metav1.NamespaceDefault).List(context.Background(), metav1.ListOptions{})
+			require.NoError(t, err)
+			if c.wantEvent {
+				require.Len(t, events.Items, 1)
+				require.Equal(t, ReasonDuplicateEnrollment, events.Items[0].Reason)
+				require.Equal(t, "testnode", events.Items[0].InvolvedObject.Name)
+				require.Contains(t, events.Items[0].Message, string(c.policy))
+			} else {
+				require.Empty(t, events.Items)
+			}
+
+			require.Equal(t, c.wantFenced, revocation.DefaultList.IsRevoked(activeCert))
+			if c.wantFenced {
+				require.Equal(t, []string{"testnode"}, store.closed)
+				require.Error(t, verifyCert(activeCert, "testnode", nil))
+			} else {
+				require.Empty(t, store.closed)
+			}
+		})
+	}
+}
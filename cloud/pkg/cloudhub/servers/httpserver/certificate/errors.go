@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error is an error of the edge certificate requests with its reason code, so that the clients
+// can tell the errors apart, e.g. whether the request may succeed if it is retried. The message
+// is prefixed with the reason code like the other errors of the requests, see failureReasons.
+type Error struct {
+	// Reason is the reason code of the error, such as types.ReasonCSRInvalid.
+	Reason string
+	Err    error
+}
+
+// newReasonError returns an *Error of the reason with the formatted message.
+func newReasonError(reason, format string, args ...any) *Error {
+	return &Error{Reason: reason, Err: fmt.Errorf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return e.Reason + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ReasonOf returns the reason code of the err, which is the reason of an *Error, or the reason
+// code that the message is prefixed with. It returns "" if the err has no reason code.
+func ReasonOf(err error) string {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Reason
+	}
+	for _, reason := range failureReasons {
+		if strings.HasPrefix(err.Error(), reason+":") {
+			return reason
+		}
+	}
+	return ""
+}
+
+// wrapError adds the formatted context to the err, the reason code of an *Error stays in front
+// of the message.
+func wrapError(err error, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	var e *Error
+	if errors.As(err, &e) {
+		return &Error{Reason: e.Reason, Err: fmt.Errorf("%s, err: %w", msg, e.Err)}
+	}
+	return fmt.Errorf("%s, err: %w", msg, err)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestReasonOf(t *testing.T) {
+	cause := errors.New("asn1: syntax error")
+	err := wrapError(&Error{Reason: types.ReasonCSRInvalid, Err: cause}, "failed to sign certs for edgenode %s", "testnode")
+	require.Equal(t, types.ReasonCSRInvalid, ReasonOf(err))
+	require.EqualError(t, err, "CSRInvalid: failed to sign certs for edgenode testnode, err: asn1: syntax error")
+	require.ErrorIs(t, err, cause)
+	require.Equal(t, types.ReasonCSRInvalid, ReasonOf(fmt.Errorf("wrapped: %w", err)))
+
+	err = wrapError(errors.New("the signing queue is full"), "failed to sign certs for edgenode %s", "testnode")
+	require.Empty(t, ReasonOf(err))
+	require.EqualError(t, err, "failed to sign certs for edgenode testnode, err: the signing queue is full")
+
+	require.Equal(t, types.ReasonRateLimited, ReasonOf(fmt.Errorf("%w: edgenode test", errRateLimited)))
+	require.Empty(t, ReasonOf(nil))
+}
+
+func TestEdgeCoreClientCertReasons(t *testing.T) {
+	ca := testutil.NewCA(t)
+	origin := revocation.DefaultList
+	revocation.DefaultList = revocation.NewList()
+	t.Cleanup(func() { revocation.DefaultList = origin })
+
+	node := ca.NewNode(t, "testnode")
+	revoked := ca.Issue(t, node)
+	revocation.DefaultList.Revoke(revoked)
+
+	cases := []struct {
+		name       string
+		req        func() *http.Request
+		wantCode   int
+		wantReason string
+	}{
+		{
+			name: "invalid CSR",
+			req: func() *http.Request {
+				req := node.Request()
+				req.Body = http.NoBody
+				return req
+			},
+			wantCode:   http.StatusBadRequest,
+			wantReason: types.ReasonCSRInvalid,
+		},
+		{
+			name: "CSR of another node",
+			req: func() *http.Request {
+				req := ca.NewNode(t, "other").Request()
+				req.Header.Set(types.HeaderNodeName, "testnode")
+				return req
+			},
+			wantCode:   http.StatusBadRequest,
+			wantReason: types.ReasonSubjectMismatch,
+		},
+		{
+			name: "malformed token",
+			req: func() *http.Request {
+				req := node.Request()
+				req.Header.Set(types.HeaderAuthorization, "Bearer")
+				return req
+			},
+			wantCode:   http.StatusUnauthorized,
+			wantReason: types.ReasonTokenMalformed,
+		},
+		{
+			name: "revoked certificate",
+			req: func() *http.Request {
+				return node.RenewalRequest(revoked)
+			},
+			wantCode:   http.StatusUnauthorized,
+			wantReason: types.ReasonCertInvalid,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			EdgeCoreClientCert(restful.NewRequest(c.req()), restful.NewResponse(recorder))
+			require.Equal(t, c.wantCode, recorder.Code, recorder.Body.String())
+			require.True(t, strings.HasPrefix(recorder.Body.String(), c.wantReason+": "), recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509/pkix"
+	"fmt"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// CheckCertExtensions checks that the OIDs of the custom extensions of the edge certificates
+// are valid, it is checked when CloudHub starts.
+func CheckCertExtensions() error {
+	for _, ext := range hubconfig.Config.CertExtensions {
+		if _, err := certs.ParseExtensionOID(ext.OID); err != nil {
+			return fmt.Errorf("invalid certExtensions config, err: %v", err)
+		}
+	}
+	return nil
+}
+
+// certExtensions returns the custom extensions of the certificate of the node, whose values are
+// the labels or the annotations of the node in the cache of the node lister. The extensions with
+// empty values are omitted, and so are all the extensions if the node doesn't exist yet.
+func certExtensions(nodeName string) ([]pkix.Extension, error) {
+	if len(hubconfig.Config.CertExtensions) == 0 || nodeLister == nil {
+		return nil, nil
+	}
+	node, err := nodeLister.Get(nodeName)
+	if err != nil {
+		// the node does not exist yet, it is signed without the extensions
+		return nil, nil
+	}
+	var exts []pkix.Extension
+	for _, c := range hubconfig.Config.CertExtensions {
+		value := node.Labels[c.Label]
+		if c.Annotation != "" {
+			value = node.Annotations[c.Annotation]
+		}
+		if value == "" {
+			continue
+		}
+		oid, err := certs.ParseExtensionOID(c.OID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certExtensions config, err: %v", err)
+		}
+		ext, err := certs.NewUTF8StringExtension(oid, value)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
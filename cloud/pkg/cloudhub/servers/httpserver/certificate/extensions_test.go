@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+)
+
+func TestEdgeCoreClientCertExtensions(t *testing.T) {
+	ca := testutil.NewCA(t)
+	hubconfig.Config.CertExtensions = []cloudcorev1alpha1.CloudHubCertExtension{
+		{OID: "1.3.6.1.4.1.55555.2.1", Label: "example.com/asset-tag"},
+		{OID: "1.3.6.1.4.1.55555.2.2", Annotation: "example.com/site-code"},
+	}
+	t.Cleanup(func() {
+		hubconfig.Config.CertExtensions = nil
+		nodeLister = nil
+	})
+	require.NoError(t, CheckCertExtensions())
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "testnode",
+		Labels:      map[string]string{"example.com/asset-tag": "asset-0042"},
+		Annotations: map[string]string{"example.com/site-code": "sha-01"},
+	}}))
+	require.NoError(t, indexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "untagged",
+		Labels: map[string]string{"example.com/asset-tag": "asset-0043"},
+	}}))
+	SetNodeLister(corev1listers.NewNodeLister(indexer))
+
+	extensionValues := func(t *testing.T, nodeName string) map[string]string {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(ca.NewNode(t, nodeName).Request()), restful.NewResponse(recorder))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+		require.NoError(t, err)
+		values := make(map[string]string)
+		for _, ext := range cert.Extensions {
+			if len(ext.Id) > 7 && ext.Id[:7].Equal(asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 55555}) {
+				require.False(t, ext.Critical)
+				var value string
+				_, err := asn1.UnmarshalWithParams(ext.Value, &value, "utf8")
+				require.NoError(t, err)
+				values[ext.Id.String()] = value
+			}
+		}
+		return values
+	}
+
+	require.Equal(t, map[string]string{
+		"1.3.6.1.4.1.55555.2.1": "asset-0042",
+		"1.3.6.1.4.1.55555.2.2": "sha-01",
+	}, extensionValues(t, "testnode"))
+	// the extension without a value is omitted
+	require.Equal(t, map[string]string{
+		"1.3.6.1.4.1.55555.2.1": "asset-0043",
+	}, extensionValues(t, "untagged"))
+	// the node does not exist yet
+	require.Empty(t, extensionValues(t, "newnode"))
+
+	t.Run("invalid OID", func(t *testing.T) {
+		hubconfig.Config.CertExtensions = []cloudcorev1alpha1.CloudHubCertExtension{
+			{OID: "2.5.29.17", Label: "example.com/asset-tag"},
+		}
+		require.ErrorContains(t, CheckCertExtensions(), "overrides a standard extension")
+	})
+}
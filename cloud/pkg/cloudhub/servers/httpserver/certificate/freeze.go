@@ -0,0 +1,272 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// ReasonSigningFrozen is the reason code of the response when the signing of edge certificates
+// is frozen by the administrator.
+const ReasonSigningFrozen = types.ReasonSigningFrozen
+
+// signingFreezeRetryAfterSeconds is the value of Retry-After header when the signing is frozen.
+const signingFreezeRetryAfterSeconds = "60"
+
+// SigningFreezeConfigMapName is the name of the ConfigMap in the system namespace that shares
+// the signing freeze between the replicas of CloudCore.
+const SigningFreezeConfigMapName = "cloudcore-signing-freeze"
+
+// signingFreezeDataKey is the key of the JSON encoded SigningFreezeStatus in the data of the ConfigMap.
+const signingFreezeDataKey = "status.json"
+
+var errSigningFrozen = errors.New(ReasonSigningFrozen)
+
+// SigningFreezeStatus is the response body of the signing freeze endpoints.
+type SigningFreezeStatus struct {
+	Frozen bool `json:"frozen"`
+	// Since is the time when the signing was frozen, it is nil if the signing is not frozen.
+	Since *time.Time `json:"since,omitempty"`
+	// Reason is the reason given by the administrator who froze the signing.
+	Reason string `json:"reason,omitempty"`
+}
+
+// signingFreeze is the emergency switch that stops issuing edge certificates, e.g. during a
+// suspected compromise of the CA key. The CA and the verification of the certificates are
+// still served while the signing is frozen. The switch is kept in a ConfigMap watched by
+// every replica, so that the freeze applies to the whole cluster whichever replica receives it.
+type signingFreeze struct {
+	mu     sync.Mutex
+	status SigningFreezeStatus
+	// client is nil until the freeze is started by InitSigningFreeze.
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+var defaultSigningFreeze = &signingFreeze{}
+
+// InitSigningFreeze restores the signing freeze from the ConfigMap and watches the ConfigMap
+// until the ctx is done, so that a freeze set through any replica applies to this one too.
+func InitSigningFreeze(ctx context.Context, kubeClient kubernetes.Interface) error {
+	return defaultSigningFreeze.start(ctx, kubeClient, constants.SystemNamespace, SigningFreezeConfigMapName)
+}
+
+func (f *signingFreeze) start(ctx context.Context, kubeClient kubernetes.Interface, namespace, name string) error {
+	f.mu.Lock()
+	f.client, f.namespace, f.name = kubeClient, namespace, name
+	f.mu.Unlock()
+
+	factory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { f.observe(obj.(*corev1.ConfigMap)) },
+		UpdateFunc: func(_, obj interface{}) { f.observe(obj.(*corev1.ConfigMap)) },
+		DeleteFunc: func(interface{}) { f.observe(nil) },
+	}); err != nil {
+		return fmt.Errorf("failed to watch the ConfigMap %s/%s of the signing freeze, err: %v", namespace, name, err)
+	}
+	factory.Start(ctx.Done())
+	// the certificates must not be signed before the freeze set by the other replicas is known
+	for typ, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync the informer of %v for the signing freeze", typ)
+		}
+	}
+	return nil
+}
+
+// observe applies the status in the ConfigMap to this replica, a deleted ConfigMap
+// means that the signing is not frozen.
+func (f *signingFreeze) observe(cm *corev1.ConfigMap) {
+	status, err := decodeSigningFreeze(cm)
+	if err != nil {
+		// keep the current status rather than resuming the signing on a corrupted ConfigMap
+		klog.Errorf("failed to decode the signing freeze in the ConfigMap, err: %v", err)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if status.Frozen != f.status.Frozen {
+		klog.InfoS("Observed the signing freeze of edge certificates", "frozen", status.Frozen, "reason", status.Reason)
+	}
+	f.status = status
+}
+
+func decodeSigningFreeze(cm *corev1.ConfigMap) (SigningFreezeStatus, error) {
+	var status SigningFreezeStatus
+	if cm == nil || cm.Data[signingFreezeDataKey] == "" {
+		return status, nil
+	}
+	err := json.Unmarshal([]byte(cm.Data[signingFreezeDataKey]), &status)
+	return status, err
+}
+
+func (f *signingFreeze) get() SigningFreezeStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status
+}
+
+// set writes the freeze to the ConfigMap, and applies it to this replica without waiting for
+// the informer. The time of the freeze is kept if the signing is already frozen.
+func (f *signingFreeze) set(ctx context.Context, frozen bool, reason string) (SigningFreezeStatus, error) {
+	f.mu.Lock()
+	kubeClient, namespace, name := f.client, f.namespace, f.name
+	f.mu.Unlock()
+	if kubeClient == nil {
+		return SigningFreezeStatus{}, errors.New("the signing freeze is not initialized")
+	}
+
+	var status SigningFreezeStatus
+	configMaps := kubeClient.CoreV1().ConfigMaps(namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		notFound := err != nil
+		current, err := decodeSigningFreeze(cm)
+		if notFound || err != nil {
+			current = SigningFreezeStatus{}
+		}
+		status = nextSigningFreeze(current, frozen, reason)
+		data, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		if notFound {
+			_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Data:       map[string]string{signingFreezeDataKey: string(data)},
+			}, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// created by another replica in the meantime, retry on the latest one
+				return apierrors.NewConflict(corev1.Resource("configmaps"), name, err)
+			}
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[signingFreezeDataKey] = string(data)
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return SigningFreezeStatus{}, fmt.Errorf("failed to save the signing freeze to the ConfigMap %s/%s, err: %v", namespace, name, err)
+	}
+	f.mu.Lock()
+	f.status = status
+	f.mu.Unlock()
+	return status, nil
+}
+
+func nextSigningFreeze(current SigningFreezeStatus, frozen bool, reason string) SigningFreezeStatus {
+	if !frozen {
+		return SigningFreezeStatus{}
+	}
+	if !current.Frozen {
+		now := time.Now()
+		current.Frozen, current.Since = true, &now
+	}
+	current.Reason = reason
+	return current
+}
+
+// signingFrozenError returns the error responded with 503 if the signing is frozen,
+// otherwise it returns nil.
+func signingFrozenError() error {
+	status := defaultSigningFreeze.get()
+	if !status.Frozen {
+		return nil
+	}
+	return fmt.Errorf("%w: the signing of edge certificates is frozen since %s, reason: %q",
+		errSigningFrozen, status.Since.UTC().Format(time.RFC3339), status.Reason)
+}
+
+// respondSigningFrozen responds 503 with the reason code of the signing freeze.
+func respondSigningFrozen(response *restful.Response, err error) {
+	response.Header().Set("Retry-After", signingFreezeRetryAfterSeconds)
+	resps.Error(response, http.StatusServiceUnavailable, err)
+}
+
+// FreezeSigning freezes the signing of edge certificates on all the replicas, the reason
+// is taken from the reason query parameter.
+func FreezeSigning(request *restful.Request, response *restful.Response) {
+	status, err := defaultSigningFreeze.set(request.Request.Context(), true, request.QueryParameter("reason"))
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	klog.InfoS("Froze the signing of edge certificates", "reason", status.Reason)
+	respondSigningFreezeStatus(response, status)
+}
+
+// UnfreezeSigning resumes the signing of edge certificates on all the replicas.
+func UnfreezeSigning(request *restful.Request, response *restful.Response) {
+	previous := defaultSigningFreeze.get()
+	status, err := defaultSigningFreeze.set(request.Request.Context(), false, "")
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	if previous.Frozen {
+		klog.InfoS("Unfroze the signing of edge certificates", "frozenSince", previous.Since, "reason", previous.Reason)
+	}
+	respondSigningFreezeStatus(response, status)
+}
+
+// GetSigningFreeze returns whether the signing of edge certificates is frozen.
+func GetSigningFreeze(_ *restful.Request, response *restful.Response) {
+	respondSigningFreezeStatus(response, defaultSigningFreeze.get())
+}
+
+func respondSigningFreezeStatus(response *restful.Response, status SigningFreezeStatus) {
+	bff, err := json.Marshal(status)
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	response.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	resps.OK(response, bff)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+func TestSigningFreeze(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	defaultSigningFreeze = &signingFreeze{}
+	t.Cleanup(func() { defaultSigningFreeze = &signingFreeze{} })
+	require.NoError(t, defaultSigningFreeze.start(ctx, fake.NewSimpleClientset(), constants.SystemNamespace, SigningFreezeConfigMapName))
+
+	sign := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+		return recorder
+	}
+	admin := func(handler restful.RouteFunction, method, url string) SigningFreezeStatus {
+		recorder := httptest.NewRecorder()
+		handler(restful.NewRequest(httptest.NewRequest(method, url, nil)), restful.NewResponse(recorder))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		var status SigningFreezeStatus
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+		return status
+	}
+
+	resp := sign()
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, SigningFreezeStatus{}, admin(GetSigningFreeze, http.MethodGet, constants.DefaultCertFreezeURL))
+
+	status := admin(FreezeSigning, http.MethodPost, constants.DefaultCertFreezeURL+"?reason=key+compromise")
+	require.True(t, status.Frozen)
+	require.NotNil(t, status.Since)
+	require.Equal(t, "key compromise", status.Reason)
+	require.Equal(t, status, admin(GetSigningFreeze, http.MethodGet, constants.DefaultCertFreezeURL))
+
+	resp = sign()
+	require.Equal(t, http.StatusServiceUnavailable, resp.Code, resp.Body.String())
+	require.Equal(t, signingFreezeRetryAfterSeconds, resp.Header().Get("Retry-After"))
+	require.True(t, strings.HasPrefix(resp.Body.String(), ReasonSigningFrozen+":"), resp.Body.String())
+	require.Contains(t, resp.Body.String(), `reason: "key compromise"`)
+
+	// the request accepted before the freeze is not signed either
+	_, code, err := signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader(node.CSR)), node.Name, "", configuredCertDuration(), nil)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.ErrorIs(t, err, errSigningFrozen)
+
+	// the CA is still served
+	recorder := httptest.NewRecorder()
+	GetCA(restful.NewRequest(httptest.NewRequest(http.MethodGet, constants.DefaultCAURL, nil)), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	require.Equal(t, SigningFreezeStatus{}, admin(UnfreezeSigning, http.MethodPost, constants.DefaultCertUnfreezeURL))
+	resp = sign()
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
+
+func TestSigningFreezeSharedByReplicas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient, waitForWatches := testutil.NewClientset(t)
+	first, second := &signingFreeze{}, &signingFreeze{}
+	require.NoError(t, first.start(ctx, kubeClient, constants.SystemNamespace, SigningFreezeConfigMapName))
+	require.NoError(t, second.start(ctx, kubeClient, constants.SystemNamespace, SigningFreezeConfigMapName))
+	waitForWatches(2)
+
+	frozen, err := first.set(ctx, true, "key compromise")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return second.get().Frozen }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, "key compromise", second.get().Reason)
+
+	// freezing again through the other replica keeps the time of the freeze
+	again, err := second.set(ctx, true, "still investigating")
+	require.NoError(t, err)
+	require.True(t, frozen.Since.Equal(*again.Since))
+	require.Eventually(t, func() bool { return first.get().Reason == "still investigating" }, 5*time.Second, 10*time.Millisecond)
+
+	// a replica started later restores the freeze before serving
+	third := &signingFreeze{}
+	require.NoError(t, third.start(ctx, kubeClient, constants.SystemNamespace, SigningFreezeConfigMapName))
+	require.True(t, third.get().Frozen)
+	waitForWatches(1)
+
+	_, err = second.set(ctx, false, "")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return !first.get().Frozen && !third.get().Frozen }, 5*time.Second, 10*time.Millisecond)
+
+	// deleting the ConfigMap resumes the signing
+	_, err = first.set(ctx, true, "")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return second.get().Frozen }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, kubeClient.CoreV1().ConfigMaps(constants.SystemNamespace).Delete(ctx, SigningFreezeConfigMapName, metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool { return !first.get().Frozen && !second.get().Frozen }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSigningFreezeNotInitialized(t *testing.T) {
+	_, err := (&signingFreeze{}).set(context.TODO(), true, "")
+	require.Error(t, err)
+}
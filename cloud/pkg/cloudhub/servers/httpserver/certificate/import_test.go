@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/issuancelog"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestVerifyCertImportedRevoked(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logFile := filepath.Join(t.TempDir(), "issuance.log")
+	require.NoError(t, issuancelog.Init(ctx, logFile))
+
+	// the certificate is signed offline, which is unknown to CloudHub until it is imported
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:airgapped",
+	}, pk, nil)
+	require.NoError(t, err)
+	certBlock, err := certshandler.SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(csr.Bytes, caPem.Bytes, caKey.DER(),
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour))
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(cert.Raw)
+	body, err := json.Marshal(issuancelog.ImportRequest{Records: []issuancelog.ImportRecord{{
+		NodeName:    "airgapped",
+		Serial:      cert.SerialNumber.String(),
+		Fingerprint: hex.EncodeToString(digest[:]),
+		NotAfter:    cert.NotAfter,
+		PEM:         string(pem.EncodeToMemory(certBlock)),
+	}}})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/admin/certs/import", bytes.NewReader(body))
+	recorder := httptest.NewRecorder()
+	issuancelog.ImportCertificates(restful.NewRequest(req), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	require.Contains(t, recorder.Body.String(), issuancelog.ImportStatusImported)
+	// wait for the entry to be persisted, so the file is not written after the test
+	require.Eventually(t, func() bool {
+		bff, err := os.ReadFile(logFile)
+		return err == nil && bytes.Contains(bff, []byte(cert.SerialNumber.String()))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, verifyCert(cert, "airgapped", nil))
+	require.NoError(t, issuancelog.Revoke(cert.SerialNumber.String()))
+	require.ErrorContains(t, verifyCert(cert, "airgapped", nil), "is revoked")
+}
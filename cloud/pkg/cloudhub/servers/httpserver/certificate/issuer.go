@@ -0,0 +1,282 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	certutil "k8s.io/client-go/util/cert"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/ocsp"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// issuer is a named CA which signs the edge certificates of a tenant,
+// a nil issuer is the global CA of CloudHub.
+type issuer struct {
+	name  string
+	ca    []byte
+	caKey []byte
+	// chain is the intermediate CA certificates of the issuer in DER
+	chain [][]byte
+	// roots is the pool of the CA, it is built once when the issuer is loaded
+	roots *x509.CertPool
+	// intermediates are the parsed chain, which chain the edge certificates to the roots
+	// that do not include the CA, e.g. the offline root CA
+	intermediates []*x509.Certificate
+}
+
+var (
+	issuers            map[string]*issuer
+	issuersByNodeGroup map[string]*issuer
+	// globalChain is the intermediate CA certificates of the global CA in DER
+	globalChain [][]byte
+	// globalIntermediates are the parsed globalChain
+	globalIntermediates []*x509.Certificate
+)
+
+// InitIssuers loads the CAs, the keys and the intermediate CAs of the configured issuers,
+// and the intermediate CAs of the global CA.
+func InitIssuers() error {
+	chain, err := loadCAChain(hubconfig.Config.TLSIntermediateCAFile, hubconfig.Config.Ca)
+	if err != nil {
+		return fmt.Errorf("failed to load the intermediate CA file %s, err: %v", hubconfig.Config.TLSIntermediateCAFile, err)
+	}
+	byName := make(map[string]*issuer)
+	byNodeGroup := make(map[string]*issuer)
+	for _, c := range hubconfig.Config.Issuers {
+		caBlock, err := readPEMFile(c.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to load the CA file %s of issuer %s, err: %v", c.CAFile, c.Name, err)
+		}
+		ca, err := x509.ParseCertificate(caBlock.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse the CA of issuer %s, err: %v", c.Name, err)
+		}
+		caKeyBlock, err := readPEMFile(c.CAKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load the CA key file %s of issuer %s, err: %v", c.CAKeyFile, c.Name, err)
+		}
+		// the key is parsed here, so that an invalid key fails the start rather than the signings
+		caKey, err := certs.PrivateKeySigner(caKeyBlock.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse the CA key of issuer %s, err: %v", c.Name, err)
+		}
+		if pub, ok := caKey.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(ca.PublicKey) {
+			return fmt.Errorf("the CA key of issuer %s does not match its CA", c.Name)
+		}
+		issChain, err := loadCAChain(c.IntermediateCAFile, caBlock.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to load the intermediate CA file %s of issuer %s, err: %v", c.IntermediateCAFile, c.Name, err)
+		}
+		roots := x509.NewCertPool()
+		roots.AddCert(ca)
+		iss := &issuer{name: c.Name, ca: caBlock.Bytes, caKey: caKeyBlock.Bytes, chain: issChain, roots: roots,
+			intermediates: parseChain(issChain)}
+		byName[c.Name] = iss
+		for _, group := range c.NodeGroups {
+			byNodeGroup[group] = iss
+		}
+	}
+	issuers, issuersByNodeGroup, globalChain = byName, byNodeGroup, chain
+	globalIntermediates = parseChain(chain)
+	return nil
+}
+
+// readPEMFile reads the first PEM block of the file, it fails if the file has no PEM data.
+func readPEMFile(file string) (*pem.Block, error) {
+	block, err := certs.ReadPEMFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("no PEM data is found")
+	}
+	return block, nil
+}
+
+// parseChain parses the intermediate CA certificates loaded by loadCAChain.
+func parseChain(chain [][]byte) []*x509.Certificate {
+	parsed := make([]*x509.Certificate, 0, len(chain))
+	for _, der := range chain {
+		// the chain is parsed by loadCAChain already
+		cert, _ := x509.ParseCertificate(der)
+		parsed = append(parsed, cert)
+	}
+	return parsed
+}
+
+// loadCAChain loads the intermediate CA certificates from the PEM file, the first one must be
+// the CA, and each one must be signed by the next one. It returns nil if the file is empty.
+func loadCAChain(file string, caDER []byte) ([][]byte, error) {
+	if file == "" {
+		return nil, nil
+	}
+	chain, err := certutil.CertsFromFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(chain[0].Raw, caDER) {
+		return nil, fmt.Errorf("the first certificate %q is not the CA", chain[0].Subject)
+	}
+	chainDER := make([][]byte, 0, len(chain))
+	for i, cert := range chain {
+		if i+1 < len(chain) {
+			if err := cert.CheckSignatureFrom(chain[i+1]); err != nil {
+				return nil, fmt.Errorf("the certificate %q is not signed by the next one, err: %v", cert.Subject, err)
+			}
+		}
+		chainDER = append(chainDER, cert.Raw)
+	}
+	return chainDER, nil
+}
+
+// selectIssuer selects the issuer of the request by the issuer header, or by the node group
+// of the node if the header is not set. The header must not conflict with the issuer of the
+// node group, so a node can't pick the CA of another node group. It returns nil if no issuer
+// is selected.
+func selectIssuer(r *http.Request, nodeName string) (*issuer, error) {
+	mapped := nodeGroupIssuer(nodeName)
+	name := r.Header.Get(types.HeaderCertIssuer)
+	if name == "" {
+		return mapped, nil
+	}
+	iss, ok := issuers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown issuer %q", name)
+	}
+	if mapped != nil && mapped != iss {
+		return nil, fmt.Errorf("issuer %q conflicts with the issuer %q of the node group of edgenode %s",
+			name, mapped.name, nodeName)
+	}
+	return iss, nil
+}
+
+// nodeGroupIssuer returns the issuer of the node group of the node, or nil if there is none.
+func nodeGroupIssuer(nodeName string) *issuer {
+	if len(issuersByNodeGroup) == 0 || nodeLister == nil {
+		return nil
+	}
+	node, err := nodeLister.Get(nodeName)
+	if err != nil {
+		// the node does not exist yet, it is signed by the global CA
+		return nil
+	}
+	return issuersByNodeGroup[node.Labels[nodeGroupLabel]]
+}
+
+// caDER returns the CA certificate of the issuer in DER.
+func (i *issuer) caDER() []byte {
+	if i == nil {
+		return hubconfig.Config.Ca
+	}
+	return i.ca
+}
+
+// rootPool returns the pool of the CAs which verify the edge certificates issued by the issuer,
+// which are the CA and the additional CAs of CloudHub for the global CA. The pool of the global
+// CA is shared with the TLS listeners.
+func (i *issuer) rootPool() (*x509.CertPool, error) {
+	if i == nil {
+		return hubconfig.Config.CAPool()
+	}
+	if i.roots != nil {
+		return i.roots, nil
+	}
+	ca, err := x509.ParseCertificate(i.ca)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	return roots, nil
+}
+
+// chainDER returns the intermediate CA certificates of the issuer in DER, starting with its CA.
+// It returns nil if the CA is trusted as a root.
+func (i *issuer) chainDER() [][]byte {
+	if i == nil {
+		return globalChain
+	}
+	return i.chain
+}
+
+// intermediatePool returns the pool of the intermediate CA certificates of the issuer and the
+// intermediate CA certificates presented along with an edge certificate.
+func (i *issuer) intermediatePool(presented ...*x509.Certificate) *x509.CertPool {
+	chain := globalIntermediates
+	if i != nil {
+		chain = i.intermediates
+	}
+	pool := x509.NewCertPool()
+	for _, cert := range chain {
+		pool.AddCert(cert)
+	}
+	for _, cert := range presented {
+		pool.AddCert(cert)
+	}
+	return pool
+}
+
+// ocspServers returns the URLs of the OCSP responder of the edge certificates issued by the issuer.
+func (i *issuer) ocspServers() []string {
+	if u := ocsp.ResponderURL(); u != "" {
+		return []string{u}
+	}
+	return nil
+}
+
+// OCSPIssuers returns the named issuers, whose edge certificates are answered by the OCSP responder
+// along with the ones of the global CA.
+func OCSPIssuers() ([]ocsp.Issuer, error) {
+	names := make([]string, 0, len(issuers))
+	for name := range issuers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]ocsp.Issuer, 0, len(names))
+	for _, name := range names {
+		iss := issuers[name]
+		ca, err := x509.ParseCertificate(iss.ca)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the CA of issuer %s, err: %v", name, err)
+		}
+		signer, err := iss.caSigner()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the CA key of issuer %s, err: %v", name, err)
+		}
+		result = append(result, ocsp.Issuer{CA: ca, Signer: signer})
+	}
+	return result, nil
+}
+
+// caSigner returns the signer of the CA key of the issuer.
+func (i *issuer) caSigner() (crypto.Signer, error) {
+	if i == nil {
+		return hubconfig.Config.CASigner()
+	}
+	return certs.PrivateKeySigner(i.caKey)
+}
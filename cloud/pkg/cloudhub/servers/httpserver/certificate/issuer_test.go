@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	certutil "k8s.io/client-go/util/cert"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// newTestIssuer writes a self-signed CA and its key to the dir and returns the issuer config.
+func newTestIssuer(t *testing.T, dir, name string, nodeGroups ...string) cloudcorev1alpha1.CloudHubIssuer {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	c := cloudcorev1alpha1.CloudHubIssuer{
+		Name:       name,
+		CAFile:     filepath.Join(dir, name, "ca.crt"),
+		CAKeyFile:  filepath.Join(dir, name, "ca.key"),
+		NodeGroups: nodeGroups,
+	}
+	_, err = certs.WriteDERToPEMFile(c.CAFile, certutil.CertificateBlockType, caPem.Bytes)
+	require.NoError(t, err)
+	_, err = certs.WriteDERToPEMFile(c.CAKeyFile, "EC PRIVATE KEY", caKey.DER())
+	require.NoError(t, err)
+	return c
+}
+
+func TestSignEdgeCertWithIssuers(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	dir := t.TempDir()
+	hubconfig.Config.Issuers = []cloudcorev1alpha1.CloudHubIssuer{
+		newTestIssuer(t, dir, "fleet-a"),
+		newTestIssuer(t, dir, "fleet-b", "group-b"),
+	}
+	t.Cleanup(func() {
+		hubconfig.Config.Issuers = nil
+		issuers, issuersByNodeGroup = nil, nil
+		nodeLister = nil
+	})
+	require.NoError(t, InitIssuers())
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:testnode",
+	}, pk, nil)
+	require.NoError(t, err)
+
+	sign := func(issuerName string) (*x509.Certificate, *issuer) {
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", nil)
+		if issuerName != "" {
+			req.Header.Set(types.HeaderCertIssuer, issuerName)
+		}
+		iss, err := selectIssuer(req, "testnode")
+		require.NoError(t, err)
+		certBlock, code, err := signEdgeCert(context.TODO(), io.NopCloser(bytes.NewReader(csr.Bytes)), "testnode", "", configuredCertDuration(), iss)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+		cert, err := x509.ParseCertificate(certBlock.Bytes)
+		require.NoError(t, err)
+		return cert, iss
+	}
+
+	// the node is not in a node group yet, so the header selects any issuer
+	certA, issA := sign("fleet-a")
+	require.Equal(t, "fleet-a", issA.name)
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "testnode",
+		Labels: map[string]string{nodeGroupLabel: "group-b"},
+	}}))
+	SetNodeLister(corev1listers.NewNodeLister(indexer))
+
+	// the node group of the node selects fleet-b if the header is not set
+	certB, issB := sign("")
+	require.Equal(t, "fleet-b", issB.name)
+
+	require.NoError(t, verifyCert(certA, "testnode", issA))
+	require.NoError(t, verifyCert(certB, "testnode", issB))
+	require.ErrorContains(t, verifyCert(certA, "testnode", issB), "failed to verify edge certificate")
+	require.ErrorContains(t, verifyCert(certB, "testnode", issA), "failed to verify edge certificate")
+	require.ErrorContains(t, verifyCert(certA, "testnode", nil), "failed to verify edge certificate")
+
+	t.Run("the node out of the node groups is signed by the global CA", func(t *testing.T) {
+		iss, err := selectIssuer(httptest.NewRequest(http.MethodGet, "/edge.crt", nil), "othernode")
+		require.NoError(t, err)
+		require.Nil(t, iss)
+	})
+
+	t.Run("the issuer header must not conflict with the node group", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", nil)
+		req.Header.Set(types.HeaderCertIssuer, "fleet-b")
+		iss, err := selectIssuer(req, "testnode")
+		require.NoError(t, err)
+		require.Equal(t, issB, iss)
+
+		req.Header.Set(types.HeaderCertIssuer, "fleet-a")
+		_, err = selectIssuer(req, "testnode")
+		require.ErrorContains(t, err, `issuer "fleet-a" conflicts with the issuer "fleet-b"`)
+	})
+
+	t.Run("the named issuers are answered by the OCSP responder", func(t *testing.T) {
+		ocspIssuers, err := OCSPIssuers()
+		require.NoError(t, err)
+		require.Len(t, ocspIssuers, 2)
+		for i, iss := range []*issuer{issA, issB} {
+			require.Equal(t, iss.ca, ocspIssuers[i].CA.Raw)
+			require.True(t, ocspIssuers[i].CA.PublicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(ocspIssuers[i].Signer.Public()))
+		}
+	})
+
+	t.Run("unknown issuer", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+		req.TLS = &tls.ConnectionState{}
+		req.Header.Set(types.HeaderNodeName, "testnode")
+		req.Header.Set(types.HeaderCertIssuer, "fleet-c")
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Contains(t, recorder.Body.String(), `unknown issuer "fleet-c"`)
+	})
+}
+
+func TestInitIssuersInvalidFiles(t *testing.T) {
+	t.Cleanup(func() {
+		hubconfig.Config.Issuers = nil
+		issuers, issuersByNodeGroup = nil, nil
+	})
+	dir := t.TempDir()
+
+	notPEM := newTestIssuer(t, dir, "not-pem")
+	require.NoError(t, os.WriteFile(notPEM.CAKeyFile, []byte("not a PEM file"), 0600))
+	hubconfig.Config.Issuers = []cloudcorev1alpha1.CloudHubIssuer{notPEM}
+	require.ErrorContains(t, InitIssuers(), "no PEM data is found")
+
+	mismatched := newTestIssuer(t, dir, "mismatched")
+	mismatched.CAKeyFile = newTestIssuer(t, dir, "other").CAKeyFile
+	hubconfig.Config.Issuers = []cloudcorev1alpha1.CloudHubIssuer{mismatched}
+	require.ErrorContains(t, InitIssuers(), "the CA key of issuer mismatched does not match its CA")
+}
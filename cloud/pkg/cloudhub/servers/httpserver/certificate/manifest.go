@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// rotationFraction is the fraction of the certificate lifetime recommended to renew after,
+// it is the middle of the 70-90% rotation deadline of edgehub.
+const rotationFraction = 0.8
+
+// certFormat is the format of the issued certificate in the response.
+type certFormat int
+
+const (
+	// certFormatDER is the certificate only in DER, which is the default for the old clients.
+	certFormatDER certFormat = iota
+	// certFormatManifest is the certificate with its provisioning manifest, see types.MIMECertManifest.
+	certFormatManifest
+	// certFormatPEMChain is the certificate followed by the intermediate CAs in PEM, see types.MIMEPEMCertChain.
+	certFormatPEMChain
+	// certFormatPEM is the certificate only in PEM, see types.MIMEPEMFile.
+	certFormatPEM
+	// certFormatPKIXCert is the certificate only in DER with its media type, see types.MIMEPKIXCert.
+	certFormatPKIXCert
+)
+
+// acceptedCertFormat returns the format of the certificate accepted by the client, the manifest
+// is preferred if the client accepts several, followed by the PEM chain, the PEM and the DER.
+func acceptedCertFormat(r *http.Request) certFormat {
+	switch {
+	case acceptsMediaType(r, types.MIMECertManifest):
+		return certFormatManifest
+	case acceptsMediaType(r, types.MIMEPEMCertChain):
+		return certFormatPEMChain
+	case acceptsMediaType(r, types.MIMEPEMFile):
+		return certFormatPEM
+	case acceptsMediaType(r, types.MIMEPKIXCert):
+		return certFormatPKIXCert
+	default:
+		return certFormatDER
+	}
+}
+
+// acceptsMediaType reports whether the Accept header of the request includes the media type.
+func acceptsMediaType(r *http.Request, mediaType string) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			if t, _, err := mime.ParseMediaType(mediaRange); err == nil && t == mediaType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// certManifest assembles the provisioning manifest of the certificate issued by the issuer from
+// the config of CloudHub. The endpoints are left empty if no address is advertised.
+func certManifest(certDER []byte, iss *issuer) types.CertManifest {
+	config := &hubconfig.Config.CloudHub
+	digest := sha256.Sum256(iss.caDER())
+	lifetime := certs.ClampEdgeCertDuration(configuredCertDuration())
+	// the nodes may request their own validity periods, the validity of the certificate is
+	// in seconds, so the lifetime is rounded to the requested hours or the configured days
+	if cert, err := x509.ParseCertificate(certDER); err == nil {
+		lifetime = cert.NotAfter.Sub(cert.NotBefore).Round(time.Minute)
+	}
+	manifest := types.CertManifest{
+		CAFingerprint:    hex.EncodeToString(digest[:]),
+		RotationInterval: metav1.Duration{Duration: time.Duration(float64(lifetime) * rotationFraction)},
+	}
+	if len(config.AdvertiseAddress) == 0 {
+		return manifest
+	}
+	host := config.AdvertiseAddress[0]
+	if config.WebSocket != nil && config.WebSocket.Enable {
+		manifest.WebSocketServer = net.JoinHostPort(host, strconv.Itoa(int(config.WebSocket.Port)))
+	}
+	if config.Quic != nil && config.Quic.Enable {
+		manifest.QuicServer = net.JoinHostPort(host, strconv.Itoa(int(config.Quic.Port)))
+	}
+	if config.HTTPS != nil {
+		manifest.HTTPServer = "https://" + net.JoinHostPort(host, strconv.Itoa(int(config.HTTPS.Port)))
+	}
+	return manifest
+}
+
+// certManifestBody returns the certificate along with its provisioning manifest.
+func certManifestBody(certDER []byte, iss *issuer) ([]byte, error) {
+	body, err := json.Marshal(types.CertResponse{
+		Certificate: certDER,
+		Chain:       iss.chainDER(),
+		Manifest:    certManifest(certDER, iss),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the provisioning manifest, err: %v", err)
+	}
+	return body, nil
+}
+
+// certChainBody returns the certificate followed by the intermediate CAs of the issuer in PEM.
+func certChainBody(certDER []byte, iss *issuer) ([]byte, error) {
+	var body bytes.Buffer
+	for _, der := range append([][]byte{certDER}, iss.chainDER()...) {
+		if err := pem.Encode(&body, &pem.Block{Type: certutil.CertificateBlockType, Bytes: der}); err != nil {
+			return nil, err
+		}
+	}
+	return body.Bytes(), nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestEdgeCoreClientCertManifest(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+	hubconfig.Config.CloudHub.AdvertiseAddress = []string{"10.0.0.1"}
+	hubconfig.Config.CloudHub.WebSocket = &v1alpha1.CloudHubWebSocket{Enable: true, Port: 10000}
+	hubconfig.Config.CloudHub.Quic = &v1alpha1.CloudHubQUIC{Enable: false, Port: 10001}
+	hubconfig.Config.CloudHub.HTTPS = &v1alpha1.CloudHubHTTPS{Enable: true, Port: 10002}
+	t.Cleanup(func() {
+		hubconfig.Config.CloudHub.AdvertiseAddress = nil
+		hubconfig.Config.CloudHub.WebSocket = nil
+		hubconfig.Config.CloudHub.Quic = nil
+		hubconfig.Config.CloudHub.HTTPS = nil
+	})
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:testnode",
+	}, pk, nil)
+	require.NoError(t, err)
+
+	request := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+		req.TLS = &tls.ConnectionState{}
+		req.Header.Set(types.HeaderNodeName, "testnode")
+		req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	// the certificate only is responded by default
+	resp := request("")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	_, err = x509.ParseCertificate(resp.Body.Bytes())
+	require.NoError(t, err)
+
+	resp = request("application/problem+json, " + types.MIMECertManifest)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, types.MIMECertManifest, resp.Header().Get("Content-Type"))
+	var certResp types.CertResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &certResp))
+	cert, err := x509.ParseCertificate(certResp.Certificate)
+	require.NoError(t, err)
+	require.Equal(t, cert.SerialNumber.String(), resp.Header().Get(types.HeaderCertSerial))
+
+	digest := sha256.Sum256(caPem.Bytes)
+	require.Equal(t, types.CertManifest{
+		WebSocketServer: "10.0.0.1:10000",
+		HTTPServer:      "https://10.0.0.1:10002",
+		CAFingerprint:   hex.EncodeToString(digest[:]),
+		// 80% of the lifetime of 1 day
+		RotationInterval: metav1.Duration{Duration: 24 * time.Hour * 8 / 10},
+	}, certResp.Manifest)
+}
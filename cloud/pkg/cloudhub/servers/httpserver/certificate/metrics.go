@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// failureReasons are the reason codes that the error messages of the edge certificate requests
+// are prefixed with, see types.ReasonQuotaExceeded.
+var failureReasons = []string{
+	types.ReasonQuotaExceeded,
+	types.ReasonDuplicateEnrollment,
+	types.ReasonSigningTimeout,
+	types.ReasonSigningFrozen,
+	types.ReasonRateLimited,
+	types.ReasonNodeNotApproved,
+	types.ReasonCSRPending,
+	types.ReasonCSRDenied,
+	types.ReasonSigningDenied,
+	types.ReasonTokenExpired,
+	types.ReasonTokenMalformed,
+	types.ReasonTokenInvalidSignature,
+	types.ReasonTokenConsumed,
+	types.ReasonCSRInvalid,
+	types.ReasonSubjectMismatch,
+	types.ReasonCertInvalid,
+	types.ReasonSignerUnavailable,
+}
+
+// signFailureReason returns the reason of the failed edge certificate request for the metrics,
+// which is the reason code of the error, see ReasonOf, or the status text of the code without spaces,
+// e.g. Unauthorized, so that the label values are bounded.
+func signFailureReason(code int, err error) string {
+	if reason := ReasonOf(err); reason != "" {
+		return reason
+	}
+	if code == 0 {
+		code = http.StatusInternalServerError
+	}
+	return strings.ReplaceAll(http.StatusText(code), " ", "")
+}
+
+// recordSignFailure counts the edge certificate request which failed with the code and the error.
+func recordSignFailure(code int, err error) {
+	monitor.CertSignFailuresTotal.WithLabelValues(signFailureReason(code, err)).Inc()
+}
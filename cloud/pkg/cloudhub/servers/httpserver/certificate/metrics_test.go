@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestSignFailureReason(t *testing.T) {
+	cases := []struct {
+		name string
+		code int
+		err  error
+		want string
+	}{
+		{
+			name: "reason code",
+			code: http.StatusTooManyRequests,
+			err:  fmt.Errorf("%w: edgenode test", errRateLimited),
+			want: types.ReasonRateLimited,
+		},
+		{
+			name: "typed error",
+			code: http.StatusBadRequest,
+			err:  wrapError(newReasonError(types.ReasonCSRInvalid, "invalid CSR"), "failed to sign certs for edgenode test"),
+			want: types.ReasonCSRInvalid,
+		},
+		{
+			name: "status text",
+			code: http.StatusUnauthorized,
+			err:  errors.New("token validation failure"),
+			want: "Unauthorized",
+		},
+		{
+			name: "no error",
+			code: http.StatusForbidden,
+			want: "Forbidden",
+		},
+		{
+			name: "reason in the message",
+			code: http.StatusBadRequest,
+			err:  errors.New("RateLimited is not a prefix"),
+			want: "BadRequest",
+		},
+		{
+			name: "no code",
+			err:  errors.New("unknown"),
+			want: "InternalServerError",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.want, signFailureReason(c.code, c.err))
+		})
+	}
+}
+
+func TestEdgeCoreClientCertMetrics(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	sign := func(req *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	signed := promtestutil.ToFloat64(monitor.CertsSignedTotal)
+	resp := sign(node.Request())
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, signed+1, promtestutil.ToFloat64(monitor.CertsSignedTotal))
+
+	tokenFailures := promtestutil.ToFloat64(monitor.TokenVerifyFailuresTotal)
+	malformed := monitor.CertSignFailuresTotal.WithLabelValues(types.ReasonTokenMalformed)
+	failures := promtestutil.ToFloat64(malformed)
+	req := node.Request()
+	req.Header.Set(types.HeaderAuthorization, "Bearer invalid")
+	resp = sign(req)
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+	require.Equal(t, tokenFailures+1, promtestutil.ToFloat64(monitor.TokenVerifyFailuresTotal))
+	require.Equal(t, failures+1, promtestutil.ToFloat64(malformed))
+	require.Equal(t, signed+1, promtestutil.ToFloat64(monitor.CertsSignedTotal))
+}
+
+func TestCARemainingValiditySeconds(t *testing.T) {
+	ca := testutil.NewCA(t)
+	remaining := promtestutil.ToFloat64(monitor.CARemainingValiditySeconds)
+	require.InDelta(t, time.Until(ca.Cert.NotAfter).Seconds(), remaining, 60)
+
+	hubconfig.Config.Ca = nil
+	require.True(t, math.IsNaN(promtestutil.ToFloat64(monitor.CARemainingValiditySeconds)))
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+const (
+	// defaultNodeTokenTTL is the time to live of the minted token if the request doesn't specify it.
+	defaultNodeTokenTTL = 24 * time.Hour
+	// nodeTokenReleaseTimeout bounds releasing the token consumed by a failed request.
+	nodeTokenReleaseTimeout = 10 * time.Second
+)
+
+// MintNodeToken mints a token which can enroll the edge node of the path parameter nodename
+// once, e.g. to replace the expired token of a node imaged long before it boots. The time to
+// live of the token is the duration of the query parameter ttl, which defaults to 24h.
+func MintNodeToken(request *restful.Request, response *restful.Response) {
+	nodeName := request.PathParameter("nodename")
+	ttl := defaultNodeTokenTTL
+	if s := request.QueryParameter("ttl"); s != "" {
+		var err error
+		if ttl, err = time.ParseDuration(s); err != nil || ttl <= 0 {
+			resps.ErrorMessage(response, http.StatusBadRequest,
+				fmt.Sprintf("invalid query parameter ttl: %s, it must be a positive duration", s))
+			return
+		}
+	}
+	nodeToken, claims, err := token.CreateNodeToken(hubconfig.Config.Ca, hubconfig.Config.TokenSigningKey(), nodeName, ttl)
+	if err != nil {
+		resps.Error(response, http.StatusBadRequest, err)
+		return
+	}
+	ctx := request.Request.Context()
+	if _, err := client.GetKubeClient().CoreV1().Secrets(constants.SystemNamespace).Create(ctx,
+		token.NodeTokenSecret(claims, constants.SystemNamespace), metav1.CreateOptions{}); err != nil {
+		resps.Error(response, http.StatusInternalServerError, fmt.Errorf("failed to record the token, err: %v", err))
+		return
+	}
+	klog.InfoS("Audit minted the bootstrap token", "node", nodeName, "tokenID", claims.ID, "expiresAt", claims.ExpiresAt.Time)
+	resps.OK(response, []byte(nodeToken))
+}
+
+// verifyNodeToken verifies the node name claim of the per-node token by verifyTokenNodeName and
+// consumes the token, so the token can enroll a node only once. The consumed claims are returned,
+// the request must release them by releaseNodeToken if the certificate is not issued afterwards.
+func verifyNodeToken(ctx context.Context, claims *token.NodeClaims, nodeName string) ([]string, *token.NodeClaims, int, error) {
+	if code, err := verifyTokenNodeName(claims, nodeName); err != nil {
+		return nil, nil, code, err
+	}
+	secrets := client.GetKubeClient().CoreV1().Secrets(constants.SystemNamespace)
+	secret, err := secrets.Get(ctx, token.NodeTokenSecretName(claims.ID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil, http.StatusUnauthorized, fmt.Errorf("token validation failure, the token %s of edgenode %s is unknown or revoked",
+			claims.ID, nodeName)
+	}
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, fmt.Errorf("failed to get the record of the token %s, err: %v", claims.ID, err)
+	}
+	if string(secret.Data[token.NodeTokenDataNodeName]) != claims.NodeName {
+		return nil, nil, http.StatusUnauthorized, fmt.Errorf("token validation failure, the token %s does not match its record", claims.ID)
+	}
+	consumedAt, consumed := secret.Annotations[token.NodeTokenConsumedAnnotation]
+	if consumed {
+		return nil, nil, http.StatusUnauthorized, newReasonError(types.ReasonTokenConsumed,
+			"token validation failure, the token %s of edgenode %s was consumed at %s", claims.ID, nodeName, consumedAt)
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[token.NodeTokenConsumedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	// the update fails with a conflict if another request consumes the token meanwhile
+	if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, nil, http.StatusUnauthorized, newReasonError(types.ReasonTokenConsumed,
+				"token validation failure, the token %s of edgenode %s is consumed", claims.ID, nodeName)
+		}
+		return nil, nil, http.StatusInternalServerError, fmt.Errorf("failed to consume the token %s, err: %v", claims.ID, err)
+	}
+	klog.InfoS("Audit consumed the bootstrap token", "node", nodeName, "tokenID", claims.ID)
+	return []string{nodeName}, claims, http.StatusOK, nil
+}
+
+// verifyTokenNodeName verifies the node name claim of the token. The token of another node is
+// rejected if StrictTokenNodeName is set, otherwise the mismatch is logged as a warning and the
+// token enrolls the node of the request.
+func verifyTokenNodeName(claims *token.NodeClaims, nodeName string) (int, error) {
+	if claims.NodeName == nodeName {
+		return http.StatusOK, nil
+	}
+	if hubconfig.Config.StrictTokenNodeName {
+		return http.StatusUnauthorized, newReasonError(types.ReasonSubjectMismatch,
+			"token validation failure, the token of edgenode %s is not permitted to provision edgenode: %s",
+			claims.NodeName, nodeName)
+	}
+	klog.Warningf("the token of edgenode %s provisions edgenode %s, set strictTokenNodeName to reject it",
+		claims.NodeName, nodeName)
+	return http.StatusOK, nil
+}
+
+// releaseNodeToken clears the consumption of the per-node token by a request which failed to
+// issue the certificate, e.g. it is rate limited or exceeds the quota, so that the node can
+// retry with the same token. It runs with its own deadline, as the context of the request may
+// have expired.
+func releaseNodeToken(claims *token.NodeClaims) {
+	ctx, cancel := context.WithTimeout(context.Background(), nodeTokenReleaseTimeout)
+	defer cancel()
+	secrets := client.GetKubeClient().CoreV1().Secrets(constants.SystemNamespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := secrets.Get(ctx, token.NodeTokenSecretName(claims.ID), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		delete(secret.Annotations, token.NodeTokenConsumedAnnotation)
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		klog.Errorf("failed to release the token %s of edgenode %s, a new token must be minted, err: %v",
+			claims.ID, claims.NodeName, err)
+		return
+	}
+	klog.InfoS("Audit released the bootstrap token", "node", claims.NodeName, "tokenID", claims.ID)
+}
@@ -0,0 +1,345 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/component-base/featuregate"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/features"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
+)
+
+func TestEdgeCoreClientCertNodeToken(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+		return kubeClient
+	})
+	defer patches.Reset()
+
+	ca := testutil.NewCA(t)
+	mintToken := func(nodeName string, record bool) string {
+		realToken, _ := mintNodeToken(t, kubeClient, ca, nodeName, record)
+		return realToken
+	}
+	sign := func(node *testutil.Node) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	node := ca.NewNode(t, "testnode")
+	node.Token = mintToken("testnode", true)
+	resp := sign(node)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// the token is consumed
+	resp = sign(node)
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+	require.True(t, strings.HasPrefix(resp.Body.String(), types.ReasonTokenConsumed+":"), resp.Body.String())
+	require.Contains(t, resp.Body.String(), "was consumed at")
+
+	// the token of another node is rejected in the strict mode and not consumed
+	strict := hubconfig.Config.StrictTokenNodeName
+	defer func() { hubconfig.Config.StrictTokenNodeName = strict }()
+	hubconfig.Config.StrictTokenNodeName = true
+	other := ca.NewNode(t, "othernode")
+	var secretName string
+	other.Token, secretName = mintNodeToken(t, kubeClient, ca, "testnode", true)
+	resp = sign(other)
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+	require.True(t, strings.HasPrefix(resp.Body.String(), types.ReasonSubjectMismatch+":"), resp.Body.String())
+	require.Contains(t, resp.Body.String(), "not permitted to provision edgenode: othernode")
+	require.False(t, consumedNodeToken(t, kubeClient, secretName))
+
+	// the token of another node enrolls the node once in the lenient mode
+	hubconfig.Config.StrictTokenNodeName = false
+	resp = sign(other)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.True(t, consumedNodeToken(t, kubeClient, secretName))
+	resp = sign(other)
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "was consumed at")
+
+	// the token without the record is revoked
+	node.Token = mintToken("testnode", false)
+	resp = sign(node)
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "is unknown or revoked")
+
+	// the global token is accepted only behind the feature gate
+	global := ca.NewNode(t, "globalnode")
+	resp = sign(global)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	setFeatureGate(t, features.LegacyBootstrapToken, false)
+	resp = sign(global)
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+	require.True(t, strings.Contains(resp.Body.String(), "the global token is disabled"), resp.Body.String())
+}
+
+// mintNodeToken mints a token of the node like keadm gettoken --node-name, the token is recorded
+// in a Secret of the kubeClient if record is true. It returns the token without the CA hash and the
+// name of its Secret.
+func mintNodeToken(t *testing.T, kubeClient kubernetes.Interface, ca *testutil.CA, nodeName string, record bool) (string, string) {
+	tokenWithHash, claims, err := token.CreateNodeToken(ca.Cert.Raw, ca.Key.DER(), nodeName, time.Minute)
+	require.NoError(t, err)
+	if record {
+		_, err = kubeClient.CoreV1().Secrets(constants.SystemNamespace).Create(context.TODO(),
+			token.NodeTokenSecret(claims, constants.SystemNamespace), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	realToken, err := token.VerifyCAAndGetRealToken(tokenWithHash, ca.Cert.Raw)
+	require.NoError(t, err)
+	return realToken, token.NodeTokenSecretName(claims.ID)
+}
+
+func TestEdgeCoreClientCertTokenNodeName(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+		return kubeClient
+	})
+	defer patches.Reset()
+	strict := hubconfig.Config.StrictTokenNodeName
+	defer func() { hubconfig.Config.StrictTokenNodeName = strict }()
+
+	ca := testutil.NewCA(t)
+	sign := func(node *testutil.Node) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	cases := []struct {
+		name      string
+		strict    bool
+		tokenNode string
+		wantCode  int
+	}{
+		{
+			name:      "strict mode with the claim of the node",
+			strict:    true,
+			tokenNode: "testnode",
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "strict mode with the claim of another node",
+			strict:    true,
+			tokenNode: "othernode",
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "strict mode without the claim",
+			strict:    true,
+			tokenNode: "",
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "lenient mode with the claim of the node",
+			tokenNode: "testnode",
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "lenient mode with the claim of another node",
+			tokenNode: "othernode",
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "lenient mode without the claim",
+			tokenNode: "",
+			wantCode:  http.StatusOK,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubconfig.Config.StrictTokenNodeName = c.strict
+			// the node without the token claim uses the global token
+			node := ca.NewNode(t, "testnode")
+			if c.tokenNode != "" {
+				tokenWithHash, err := token.CreateWithNodeName(ca.Cert.Raw, ca.Key.DER(), c.tokenNode, time.Minute)
+				require.NoError(t, err)
+				node.Token, err = token.VerifyCAAndGetRealToken(tokenWithHash, ca.Cert.Raw)
+				require.NoError(t, err)
+			}
+			resp := sign(node)
+			require.Equal(t, c.wantCode, resp.Code, resp.Body.String())
+			if c.wantCode != http.StatusOK {
+				require.True(t, strings.HasPrefix(resp.Body.String(), types.ReasonSubjectMismatch+":"), resp.Body.String())
+				require.Contains(t, resp.Body.String(), "not permitted to provision edgenode: testnode")
+			}
+		})
+	}
+
+	t.Run("legacy bootstrap token disabled", func(t *testing.T) {
+		hubconfig.Config.StrictTokenNodeName = true
+		setFeatureGate(t, features.LegacyBootstrapToken, false)
+		tokenWithHash, err := token.CreateWithNodeName(ca.Cert.Raw, ca.Key.DER(), "testnode", time.Minute)
+		require.NoError(t, err)
+		realToken, err := token.VerifyCAAndGetRealToken(tokenWithHash, ca.Cert.Raw)
+		require.NoError(t, err)
+
+		// the token with the claim is not subject to the feature gate
+		node := ca.NewNode(t, "testnode")
+		node.Token = realToken
+		resp := sign(node)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		other := ca.NewNode(t, "othernode")
+		other.Token = realToken
+		resp = sign(other)
+		require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+		require.Contains(t, resp.Body.String(), "not permitted to provision edgenode: othernode")
+
+		// the global token is still disabled
+		resp = sign(ca.NewNode(t, "globalnode"))
+		require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+		require.Contains(t, resp.Body.String(), "the global token is disabled")
+	})
+}
+
+// consumedNodeToken reports whether the token recorded in the Secret secretName is consumed.
+func consumedNodeToken(t *testing.T, kubeClient kubernetes.Interface, secretName string) bool {
+	secret, err := kubeClient.CoreV1().Secrets(constants.SystemNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	_, ok := secret.Annotations[token.NodeTokenConsumedAnnotation]
+	return ok
+}
+
+func TestEdgeCoreClientCertNodeTokenReleased(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+		return kubeClient
+	})
+	defer patches.Reset()
+
+	ca := testutil.NewCA(t)
+	sign := func(node *testutil.Node) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	t.Run("rate limited", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC)
+		defaultSigningRateLimiter = newTestRateLimiter(newMemoryCounter(), &now)
+		t.Cleanup(func() { defaultSigningRateLimiter = nil })
+
+		node := ca.NewNode(t, "testnode")
+		// the global token uses up the limit of the node
+		for i := 0; i < 2; i++ {
+			resp := sign(node)
+			require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		}
+		var secretName string
+		node.Token, secretName = mintNodeToken(t, kubeClient, ca, "testnode", true)
+		resp := sign(node)
+		require.Equal(t, http.StatusTooManyRequests, resp.Code, resp.Body.String())
+		require.False(t, consumedNodeToken(t, kubeClient, secretName))
+
+		// the node retries with the same token in the next window
+		now = now.Add(time.Minute)
+		resp = sign(node)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.True(t, consumedNodeToken(t, kubeClient, secretName))
+	})
+
+	t.Run("denied by the signing webhook", func(t *testing.T) {
+		var allowed atomic.Bool
+		f := newFakeSigningWebhook(t, func(w http.ResponseWriter, _ int32) {
+			respondReview(w, allowed.Load(), "the node is not in the inventory")
+		})
+		f.install(t, cloudcorev1alpha1.SigningWebhookDeny)
+
+		node := ca.NewNode(t, "webhooknode")
+		var secretName string
+		node.Token, secretName = mintNodeToken(t, kubeClient, ca, "webhooknode", true)
+		resp := sign(node)
+		require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+		require.False(t, consumedNodeToken(t, kubeClient, secretName))
+
+		// the node retries with the same token once the webhook allows it
+		allowed.Store(true)
+		resp = sign(node)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.True(t, consumedNodeToken(t, kubeClient, secretName))
+	})
+}
+
+func setFeatureGate(t *testing.T, feature featuregate.Feature, enabled bool) {
+	original := features.DefaultFeatureGate.Enabled(feature)
+	require.NoError(t, features.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(feature): enabled}))
+	t.Cleanup(func() {
+		require.NoError(t, features.DefaultMutableFeatureGate.SetFromMap(map[string]bool{string(feature): original}))
+	})
+}
+
+func TestMintNodeToken(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+		return kubeClient
+	})
+	defer patches.Reset()
+
+	ca := testutil.NewCA(t)
+	ws := new(restful.WebService)
+	ws.Route(ws.POST(constants.DefaultNodeTokenURL).To(MintNodeToken))
+	container := restful.NewContainer()
+	container.Add(ws)
+	mint := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/nodes/testnode/token"+query, nil)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	resp := mint("?ttl=-1h")
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = mint("?ttl=1h")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	realToken, err := token.VerifyCAAndGetRealToken(resp.Body.String(), ca.Cert.Raw)
+	require.NoError(t, err)
+	claims, err := token.ParseNodeToken(realToken, ca.Key.DER())
+	require.NoError(t, err)
+	require.Equal(t, "testnode", claims.NodeName)
+	require.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt.Time, time.Minute)
+
+	// the minted token enrolls the node
+	node := ca.NewNode(t, "testnode")
+	node.Token = realToken
+	recorder := httptest.NewRecorder()
+	EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+}
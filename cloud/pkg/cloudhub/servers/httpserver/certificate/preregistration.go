@@ -0,0 +1,51 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/preregistration"
+)
+
+// verifyPreRegistration verifies that the public key of the CSR matches the pre-registration
+// of the node, and consumes the registration.
+func verifyPreRegistration(ctx context.Context, payload []byte, nodeName string) (preregistration.Registration, int, error) {
+	var reg preregistration.Registration
+	if nodeName == "" {
+		return reg, http.StatusUnauthorized,
+			fmt.Errorf("neither certificate nor token is presented, and the node name is empty")
+	}
+	csr, err := parseCSR(payload)
+	if err != nil {
+		return reg, http.StatusBadRequest, fmt.Errorf("invalid CSR, err: %v", err)
+	}
+	if csr.Subject.CommonName != fmt.Sprintf("system:node:%s", nodeName) {
+		return reg, http.StatusUnauthorized,
+			fmt.Errorf("pre-registration validation failure, the CSR subject does not match the node name")
+	}
+	reg, err = preregistration.DefaultStore.Consume(ctx, nodeName, csr.PublicKey)
+	if errors.Is(err, preregistration.ErrNotFound) || errors.Is(err, preregistration.ErrMismatch) {
+		return reg, http.StatusUnauthorized, fmt.Errorf("pre-registration validation failure, err: %v", err)
+	}
+	if err != nil {
+		return reg, http.StatusInternalServerError, err
+	}
+	return reg, http.StatusOK, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/preregistration"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestEdgeCoreClientCertPreRegistration(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	newCSR := func() ([]byte, string) {
+		pk, err := certshandler.GenPrivateKey()
+		require.NoError(t, err)
+		csr, err := certshandler.CreateCSR(pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:testnode",
+		}, pk, nil)
+		require.NoError(t, err)
+		parsed, err := x509.ParseCertificateRequest(csr.Bytes)
+		require.NoError(t, err)
+		fp, err := preregistration.PublicKeyFingerprint(parsed.PublicKey)
+		require.NoError(t, err)
+		return csr.Bytes, fp
+	}
+	request := func(csr []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr))
+		req.TLS = &tls.ConnectionState{}
+		req.Header.Set(types.HeaderNodeName, "testnode")
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	origin := preregistration.DefaultStore
+	defer func() { preregistration.DefaultStore = origin }()
+
+	t.Run("matched and consumed", func(t *testing.T) {
+		preregistration.DefaultStore = preregistration.NewStore(fake.NewSimpleClientset())
+		csr, fp := newCSR()
+		_, err := preregistration.DefaultStore.Add(context.TODO(), preregistration.Registration{
+			NodeName:    "testnode",
+			Fingerprint: fp,
+		})
+		require.NoError(t, err)
+
+		resp := request(csr)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		regs, err := preregistration.DefaultStore.List(context.TODO())
+		require.NoError(t, err)
+		require.Empty(t, regs)
+
+		// replay of the consumed registration
+		resp = request(csr)
+		require.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+
+	t.Run("mismatched", func(t *testing.T) {
+		preregistration.DefaultStore = preregistration.NewStore(fake.NewSimpleClientset())
+		_, fp := newCSR()
+		_, err := preregistration.DefaultStore.Add(context.TODO(), preregistration.Registration{
+			NodeName:    "testnode",
+			Fingerprint: fp,
+		})
+		require.NoError(t, err)
+
+		other, _ := newCSR()
+		resp := request(other)
+		require.Equal(t, http.StatusUnauthorized, resp.Code)
+		// the registration is kept for the real node
+		regs, err := preregistration.DefaultStore.List(context.TODO())
+		require.NoError(t, err)
+		require.Len(t, regs, 1)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		preregistration.DefaultStore = preregistration.NewStore(fake.NewSimpleClientset())
+		csr, fp := newCSR()
+		expiresAt := time.Now().Add(time.Millisecond)
+		_, err := preregistration.DefaultStore.Add(context.TODO(), preregistration.Registration{
+			NodeName:    "testnode",
+			Fingerprint: fp,
+			ExpiresAt:   &expiresAt,
+		})
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+
+		resp := request(csr)
+		require.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+}
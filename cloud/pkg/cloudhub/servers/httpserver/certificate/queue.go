@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"sync"
+	"time"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+const (
+	defaultSigningWorkers    = 4
+	defaultSigningQueueDepth = 100
+
+	// signingRetryAfterSeconds is the value of Retry-After header when the signing queue is full.
+	signingRetryAfterSeconds = "5"
+)
+
+var errSigningQueueFull = errors.New("the signing queue is full, please retry later")
+
+type signingTask struct {
+	ctx      context.Context
+	fn       func(ctx context.Context) (*pem.Block, error)
+	enqueued time.Time
+	done     chan struct{}
+
+	// result is set before done is closed
+	certBlock *pem.Block
+	err       error
+}
+
+// signingQueue is a bounded queue of signing tasks, which are run by a fixed number of workers.
+type signingQueue struct {
+	tasks chan *signingTask
+}
+
+var (
+	signQueue     *signingQueue
+	signQueueOnce sync.Once
+)
+
+// getSigningQueue returns the signing queue, which is created from the CloudHub config on first use.
+func getSigningQueue() *signingQueue {
+	signQueueOnce.Do(func() {
+		workers, depth := defaultSigningWorkers, defaultSigningQueueDepth
+		if c := hubconfig.Config.SigningQueue; c != nil {
+			if c.Workers > 0 {
+				workers = int(c.Workers)
+			}
+			if c.QueueDepth > 0 {
+				depth = int(c.QueueDepth)
+			}
+		}
+		if signQueue == nil {
+			signQueue = newSigningQueue(workers, depth)
+		}
+	})
+	return signQueue
+}
+
+func newSigningQueue(workers, depth int) *signingQueue {
+	q := &signingQueue{
+		tasks: make(chan *signingTask, depth),
+	}
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+func (q *signingQueue) work() {
+	for task := range q.tasks {
+		monitor.SigningQueueDepth.Dec()
+		monitor.SigningQueueWaitSeconds.Observe(time.Since(task.enqueued).Seconds())
+		// the task is skipped if the request has given up while waiting in the queue
+		if task.err = task.ctx.Err(); task.err == nil {
+			task.certBlock, task.err = task.fn(task.ctx)
+		}
+		close(task.done)
+	}
+}
+
+// run enqueues the fn and waits for its result. It returns errSigningQueueFull immediately
+// if the queue is full, and the error of the ctx as soon as the ctx is done, then the fn is
+// skipped if it is still waiting in the queue.
+func (q *signingQueue) run(ctx context.Context, fn func(ctx context.Context) (*pem.Block, error)) (*pem.Block, error) {
+	task := &signingTask{
+		ctx:      ctx,
+		fn:       fn,
+		enqueued: time.Now(),
+		done:     make(chan struct{}),
+	}
+	monitor.SigningQueueDepth.Inc()
+	select {
+	case q.tasks <- task:
+	default:
+		monitor.SigningQueueDepth.Dec()
+		return nil, errSigningQueueFull
+	}
+	select {
+	case <-task.done:
+		return task.certBlock, task.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
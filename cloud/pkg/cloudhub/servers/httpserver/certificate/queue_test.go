@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// runFunc runs the fn in the queue and returns the error of the queue.
+func runFunc(q *signingQueue, fn func()) error {
+	_, err := q.run(context.TODO(), func(context.Context) (*pem.Block, error) {
+		fn()
+		return nil, nil
+	})
+	return err
+}
+
+func TestSigningQueueLightLoad(t *testing.T) {
+	q := newSigningQueue(2, 2)
+	var mu sync.Mutex
+	var count int
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				err := runFunc(q, func() {
+					mu.Lock()
+					count++
+					mu.Unlock()
+				})
+				if err == nil {
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 10, count)
+
+	// sequential requests never hit the limit
+	for i := 0; i < 10; i++ {
+		require.NoError(t, runFunc(q, func() {}))
+	}
+}
+
+func TestSigningQueueSaturated(t *testing.T) {
+	q := newSigningQueue(1, 1)
+	block := make(chan struct{})
+	started := make(chan struct{})
+
+	// occupies the only worker
+	go func() {
+		_ = runFunc(q, func() {
+			close(started)
+			<-block
+		})
+	}()
+	<-started
+	// fills the queue
+	queued := make(chan error)
+	go func() {
+		queued <- runFunc(q, func() {})
+	}()
+	require.Eventually(t, func() bool { return len(q.tasks) == 1 }, time.Second, time.Millisecond)
+
+	require.ErrorIs(t, runFunc(q, func() {}), errSigningQueueFull)
+
+	close(block)
+	require.NoError(t, <-queued)
+	require.NoError(t, runFunc(q, func() {}))
+}
+
+func TestEdgeCoreClientCertQueueFull(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:testnode",
+	}, pk, nil)
+	require.NoError(t, err)
+
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+		req.TLS = &tls.ConnectionState{}
+		req.Header.Set(types.HeaderNodeName, "testnode")
+		req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	origin := getSigningQueue()
+	defer func() { signQueue = origin }()
+	signQueue = newSigningQueue(1, 1)
+
+	resp := request()
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = runFunc(signQueue, func() {
+			close(started)
+			<-block
+		})
+	}()
+	<-started
+	go func() { _ = runFunc(signQueue, func() {}) }()
+	require.Eventually(t, func() bool { return len(signQueue.tasks) == 1 }, time.Second, time.Millisecond)
+
+	resp = request()
+	require.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	require.Equal(t, signingRetryAfterSeconds, resp.Header().Get("Retry-After"))
+	close(block)
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"container/heap"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/issuancelog"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// ReasonQuotaExceeded is the reason code of the response when the issuance quota of the tenant is exceeded.
+const ReasonQuotaExceeded = types.ReasonQuotaExceeded
+
+// nodeGroupLabel is the label of the node group that the node belongs to,
+// which is the same as nodegroup.LabelBelongingTo.
+const nodeGroupLabel = "apps.kubeedge.io/belonging-to"
+
+var nodeLister corev1listers.NodeLister
+
+// SetNodeLister sets the node lister used to match the tenants by the node group.
+func SetNodeLister(lister corev1listers.NodeLister) {
+	nodeLister = lister
+}
+
+// quotaTenant returns the first tenant matching the node, it returns nil if no tenant matches.
+func quotaTenant(nodeName string) *cloudcorev1alpha1.IssuanceQuotaTenant {
+	q := hubconfig.Config.IssuanceQuota
+	if q == nil {
+		return nil
+	}
+	var group string
+	for i := range q.Tenants {
+		t := &q.Tenants[i]
+		if t.NodeNamePrefix != "" && strings.HasPrefix(nodeName, t.NodeNamePrefix) {
+			return t
+		}
+		if t.NodeGroup == "" || nodeLister == nil {
+			continue
+		}
+		if group == "" {
+			node, err := nodeLister.Get(nodeName)
+			if err != nil {
+				// the node does not exist yet, only the prefixes can match
+				group = "-"
+				continue
+			}
+			group = node.Labels[nodeGroupLabel]
+		}
+		if group == t.NodeGroup {
+			return t
+		}
+	}
+	return nil
+}
+
+type quotaRecord struct {
+	tenant   string
+	serial   string
+	notAfter time.Time
+	// pending indicates that the certificate is being signed
+	pending bool
+}
+
+type quotaExpiry struct {
+	nodeName string
+	notAfter time.Time
+}
+
+type quotaExpiryHeap []quotaExpiry
+
+func (h quotaExpiryHeap) Len() int           { return len(h) }
+func (h quotaExpiryHeap) Less(i, j int) bool { return h[i].notAfter.Before(h[j].notAfter) }
+func (h quotaExpiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *quotaExpiryHeap) Push(x any)        { *h = append(*h, x.(quotaExpiry)) }
+func (h *quotaExpiryHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// issuanceQuota counts the nodes holding issued and unexpired certificates per tenant.
+// The counts are maintained incrementally, they are decreased when the certificates
+// expire or are revoked.
+type issuanceQuota struct {
+	mu       sync.Mutex
+	records  map[string]quotaRecord
+	bySerial map[string]string
+	counts   map[string]int
+	expiries quotaExpiryHeap
+	now      func() time.Time
+}
+
+var defaultQuota = newIssuanceQuota()
+
+func newIssuanceQuota() *issuanceQuota {
+	return &issuanceQuota{
+		records:  make(map[string]quotaRecord),
+		bySerial: make(map[string]string),
+		counts:   make(map[string]int),
+		now:      time.Now,
+	}
+}
+
+// InitIssuanceQuota loads the certificates recorded in the issuance log into the quota counts,
+// and releases the slots of the nodes whose certificates are revoked. The expiration of the
+// certificates signed by CloudHub is estimated by the signing duration.
+func InitIssuanceQuota() {
+	if hubconfig.Config.IssuanceQuota == nil {
+		return
+	}
+	revocation.DefaultList.OnRevoke(defaultQuota.revoked)
+	duration := certs.ClampEdgeCertDuration(hubconfig.Config.EdgeCertSigningDuration * time.Hour * 24)
+	issuancelog.ForEach(func(e certs.IssuanceLogEntry) {
+		t := quotaTenant(e.NodeName)
+		if t == nil {
+			return
+		}
+		notAfter := e.Timestamp.Add(duration)
+		if e.NotAfter != nil {
+			notAfter = *e.NotAfter
+		}
+		defaultQuota.record(t.Name, e.NodeName, e.Serial, notAfter)
+	})
+}
+
+// reserve reserves a slot of the tenant for the node. A node that holds an unexpired certificate
+// is a renewal and takes no new slot, so is a request authenticated by the certificate of the node.
+func (q *issuanceQuota) reserve(t *cloudcorev1alpha1.IssuanceQuotaTenant, nodeName string, renewal bool) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pruneLocked()
+	if _, ok := q.records[nodeName]; ok {
+		return nil
+	}
+	if !renewal && q.counts[t.Name] >= int(t.MaxCertificates) {
+		return fmt.Errorf("%s: tenant %s has reached the quota of %d edge certificates",
+			ReasonQuotaExceeded, t.Name, t.MaxCertificates)
+	}
+	q.counts[t.Name]++
+	q.records[nodeName] = quotaRecord{tenant: t.Name, pending: true}
+	return nil
+}
+
+// cancel releases the slot reserved for the node if the signing fails.
+func (q *issuanceQuota) cancel(nodeName string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if r, ok := q.records[nodeName]; ok && r.pending {
+		q.removeLocked(nodeName)
+	}
+}
+
+// commit records the certificate issued to the node which has reserved a slot.
+func (q *issuanceQuota) commit(nodeName string, certDER []byte) {
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		klog.Warningf("failed to parse the certificate of edge node %s, err: %v", nodeName, err)
+		q.cancel(nodeName)
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	r, ok := q.records[nodeName]
+	if !ok {
+		return
+	}
+	q.setLocked(r.tenant, nodeName, cert.SerialNumber.String(), cert.NotAfter)
+}
+
+// record adds the certificate of the node to the counts of the tenant.
+func (q *issuanceQuota) record(tenant, nodeName, serial string, notAfter time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.now().Before(notAfter) {
+		return
+	}
+	if _, ok := q.records[nodeName]; !ok {
+		q.counts[tenant]++
+	}
+	q.setLocked(tenant, nodeName, serial, notAfter)
+}
+
+func (q *issuanceQuota) setLocked(tenant, nodeName, serial string, notAfter time.Time) {
+	if old, ok := q.records[nodeName]; ok && old.serial != "" {
+		delete(q.bySerial, old.serial)
+		// keep the latest expiration of the renewed node
+		if old.notAfter.After(notAfter) {
+			notAfter = old.notAfter
+		}
+	}
+	q.records[nodeName] = quotaRecord{tenant: tenant, serial: serial, notAfter: notAfter}
+	q.bySerial[serial] = nodeName
+	heap.Push(&q.expiries, quotaExpiry{nodeName: nodeName, notAfter: notAfter})
+}
+
+// revoked releases the slot of the node if its latest certificate is revoked.
+func (q *issuanceQuota) revoked(serial string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if nodeName, ok := q.bySerial[serial]; ok {
+		q.removeLocked(nodeName)
+	}
+}
+
+// pruneLocked releases the slots of the nodes whose certificates have expired.
+func (q *issuanceQuota) pruneLocked() {
+	now := q.now()
+	for q.expiries.Len() > 0 && !now.Before(q.expiries[0].notAfter) {
+		e := heap.Pop(&q.expiries).(quotaExpiry)
+		// the expiry is stale if the certificate has been renewed or revoked
+		if r, ok := q.records[e.nodeName]; ok && !r.pending && r.notAfter.Equal(e.notAfter) {
+			q.removeLocked(e.nodeName)
+		}
+	}
+}
+
+func (q *issuanceQuota) removeLocked(nodeName string) {
+	r, ok := q.records[nodeName]
+	if !ok {
+		return
+	}
+	delete(q.records, nodeName)
+	delete(q.bySerial, r.serial)
+	if q.counts[r.tenant]--; q.counts[r.tenant] <= 0 {
+		delete(q.counts, r.tenant)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestIssuanceQuota(t *testing.T) {
+	now := time.Now()
+	q := newIssuanceQuota()
+	q.now = func() time.Time { return now }
+	tenant := &v1alpha1.IssuanceQuotaTenant{Name: "tenant-a", MaxCertificates: 2}
+
+	issue := func(nodeName, serial string, renewal bool, ttl time.Duration) error {
+		if err := q.reserve(tenant, nodeName, renewal); err != nil {
+			return err
+		}
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.setLocked(tenant.Name, nodeName, serial, now.Add(ttl))
+		return nil
+	}
+
+	require.NoError(t, issue("node1", "1", false, time.Hour))
+	require.NoError(t, issue("node2", "2", false, 2*time.Hour))
+	// the quota is reached
+	err := issue("node3", "3", false, time.Hour)
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), ReasonQuotaExceeded+":"), err.Error())
+	require.Equal(t, 2, q.counts[tenant.Name])
+
+	// renewals of the tracked nodes are exempt and take no new slot
+	require.NoError(t, issue("node1", "4", false, 90*time.Minute))
+	require.NoError(t, issue("node1", "5", true, 90*time.Minute))
+	require.Equal(t, 2, q.counts[tenant.Name])
+	// renewals authenticated by the node certificates are exempt even if the nodes are untracked
+	require.NoError(t, issue("node3", "3", true, time.Hour))
+	require.Equal(t, 3, q.counts[tenant.Name])
+
+	// revoking a replaced certificate does not release the slot
+	q.revoked("1")
+	require.Equal(t, 3, q.counts[tenant.Name])
+	q.revoked("3")
+	require.Equal(t, 2, q.counts[tenant.Name])
+	err = issue("node4", "6", false, time.Hour)
+	require.Error(t, err)
+
+	// the renewed node1 expires later than its first certificate
+	now = now.Add(time.Hour)
+	q.mu.Lock()
+	q.pruneLocked()
+	q.mu.Unlock()
+	require.Equal(t, 2, q.counts[tenant.Name])
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, issue("node4", "6", false, 2*time.Hour))
+	require.Equal(t, 2, q.counts[tenant.Name])
+
+	// the slot is released if the signing fails
+	now = now.Add(time.Hour)
+	require.NoError(t, q.reserve(tenant, "node5", false))
+	require.Error(t, q.reserve(tenant, "node6", false))
+	q.cancel("node5")
+	require.NoError(t, q.reserve(tenant, "node6", false))
+}
+
+func TestQuotaTenant(t *testing.T) {
+	hubconfig.Config.IssuanceQuota = &v1alpha1.CloudHubIssuanceQuota{
+		Tenants: []v1alpha1.IssuanceQuotaTenant{
+			{Name: "a", NodeNamePrefix: "a-", MaxCertificates: 1},
+			{Name: "ab", NodeNamePrefix: "a-b-", MaxCertificates: 1},
+		},
+	}
+	defer func() { hubconfig.Config.IssuanceQuota = nil }()
+
+	require.Equal(t, "a", quotaTenant("a-b-1").Name)
+	require.Nil(t, quotaTenant("b-1"))
+}
+
+func TestEdgeCoreClientCertQuota(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+	hubconfig.Config.IssuanceQuota = &v1alpha1.CloudHubIssuanceQuota{
+		Tenants: []v1alpha1.IssuanceQuotaTenant{
+			{Name: "tenant-a", NodeNamePrefix: "tenant-a-", MaxCertificates: 1},
+		},
+	}
+	origin, originList := defaultQuota, revocation.DefaultList
+	defaultQuota, revocation.DefaultList = newIssuanceQuota(), revocation.NewList()
+	defer func() {
+		hubconfig.Config.IssuanceQuota = nil
+		defaultQuota, revocation.DefaultList = origin, originList
+	}()
+	InitIssuanceQuota()
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	request := func(nodeName string) *httptest.ResponseRecorder {
+		pk, err := certshandler.GenPrivateKey()
+		require.NoError(t, err)
+		csr, err := certshandler.CreateCSR(pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:" + nodeName,
+		}, pk, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+		req.TLS = &tls.ConnectionState{}
+		req.Header.Set(types.HeaderNodeName, nodeName)
+		req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	resp := request("tenant-a-1")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	cert, err := x509.ParseCertificate(resp.Body.Bytes())
+	require.NoError(t, err)
+
+	// the token holder of the node renews its certificate
+	resp = request("tenant-a-1")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	renewed, err := x509.ParseCertificate(resp.Body.Bytes())
+	require.NoError(t, err)
+
+	resp = request("tenant-a-2")
+	require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	require.Contains(t, resp.Body.String(), ReasonQuotaExceeded+":")
+	// nodes of no tenant are not limited
+	resp = request("other-1")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// revoking the replaced certificate keeps the slot, revoking the latest one releases it
+	revocation.DefaultList.RevokeSerial(cert.SerialNumber.String(), cert.NotAfter)
+	resp = request("tenant-a-2")
+	require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	revocation.DefaultList.RevokeSerial(renewed.SerialNumber.String(), renewed.NotAfter)
+	resp = request("tenant-a-2")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
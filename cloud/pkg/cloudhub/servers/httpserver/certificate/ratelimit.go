@@ -0,0 +1,207 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// ReasonRateLimited is the reason code of the response when the node exceeds the signing rate limit.
+const ReasonRateLimited = types.ReasonRateLimited
+
+const (
+	// signingLeasePrefix is the name prefix of the Leases keeping the counts of the lease backend.
+	signingLeasePrefix = "cloudhub-signing-"
+	// signingWindowAnnotation records the start of the window the count belongs to.
+	signingWindowAnnotation = "cloudhub.kubeedge.io/signing-window"
+	// signingCountAnnotation records the count of the requests of the node in the window.
+	signingCountAnnotation = "cloudhub.kubeedge.io/signing-count"
+)
+
+var errRateLimited = errors.New(ReasonRateLimited)
+
+// signingCounter counts the edge certificate requests of the nodes in fixed windows.
+type signingCounter interface {
+	// increment counts a request of the node in the window starting at start,
+	// and returns the count of the window including the request.
+	increment(ctx context.Context, nodeName string, start time.Time) (int, error)
+}
+
+// memoryCounter keeps the counts in the memory of the replica.
+type memoryCounter struct {
+	mu     sync.Mutex
+	counts map[string]windowCount
+}
+
+type windowCount struct {
+	start time.Time
+	count int
+}
+
+func newMemoryCounter() *memoryCounter {
+	return &memoryCounter{counts: make(map[string]windowCount)}
+}
+
+func (c *memoryCounter) increment(_ context.Context, nodeName string, start time.Time) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// the counts of the past windows are useless, drop them so that the map does not grow
+	for name, wc := range c.counts {
+		if wc.start.Before(start) {
+			delete(c.counts, name)
+		}
+	}
+	wc := c.counts[nodeName]
+	wc.start = start
+	wc.count++
+	c.counts[nodeName] = wc
+	return wc.count, nil
+}
+
+// leaseCounter keeps the counts in a Lease per node, so that the limit holds across the
+// replicas of CloudHub. The updates are serialized by the resourceVersion of the Lease.
+type leaseCounter struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+func (c *leaseCounter) increment(ctx context.Context, nodeName string, start time.Time) (int, error) {
+	window := strconv.FormatInt(start.Unix(), 10)
+	name := signingLeasePrefix + nodeName
+	leases := c.client.CoordinationV1().Leases(c.namespace)
+	var count int
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lease, err := leases.Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			count = 1
+			lease = &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: c.namespace,
+					Annotations: map[string]string{
+						signingWindowAnnotation: window,
+						signingCountAnnotation:  strconv.Itoa(count),
+					},
+				},
+			}
+			_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// another replica created it first, count on top of it
+				return apierrors.NewConflict(schema.GroupResource{Group: coordinationv1.GroupName, Resource: "leases"}, name, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		count = 1
+		if lease.Annotations[signingWindowAnnotation] == window {
+			if n, err := strconv.Atoi(lease.Annotations[signingCountAnnotation]); err == nil {
+				count = n + 1
+			}
+		}
+		if lease.Annotations == nil {
+			lease.Annotations = make(map[string]string)
+		}
+		lease.Annotations[signingWindowAnnotation] = window
+		lease.Annotations[signingCountAnnotation] = strconv.Itoa(count)
+		_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+		return err
+	})
+	return count, err
+}
+
+// signingRateLimiter limits the edge certificate requests of each node in fixed windows.
+type signingRateLimiter struct {
+	counter signingCounter
+	limit   int
+	window  time.Duration
+	now     func() time.Time
+}
+
+// defaultSigningRateLimiter is nil if the signing rate limit is disabled.
+var defaultSigningRateLimiter *signingRateLimiter
+
+// InitSigningRateLimit initializes the signing rate limit from the config of CloudHub,
+// the kubeClient is used by the lease backend.
+func InitSigningRateLimit(kubeClient kubernetes.Interface) {
+	l := hubconfig.Config.SigningRateLimit
+	if l == nil || !l.Enable {
+		return
+	}
+	var counter signingCounter = newMemoryCounter()
+	if l.Backend == cloudcorev1alpha1.RateLimitBackendLease {
+		counter = &leaseCounter{client: kubeClient, namespace: l.LeaseNamespace}
+	}
+	defaultSigningRateLimiter = &signingRateLimiter{
+		counter: counter,
+		limit:   int(l.Requests),
+		window:  time.Duration(l.Window) * time.Second,
+		now:     time.Now,
+	}
+}
+
+// allow counts the request of the node, it returns the time left in the window if the node
+// exceeds the limit. The request is allowed if the counts are unavailable, so that an outage
+// of the backend does not stop issuing the certificates.
+func (l *signingRateLimiter) allow(ctx context.Context, nodeName string) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+	now := l.now()
+	start := now.Truncate(l.window)
+	count, err := l.counter.increment(ctx, nodeName, start)
+	if err != nil {
+		klog.Warningf("failed to count the certificate request of edgenode %s, the request is allowed, err: %v", nodeName, err)
+		return 0, nil
+	}
+	if count <= l.limit {
+		return 0, nil
+	}
+	return start.Add(l.window).Sub(now), fmt.Errorf("%w: edgenode %s exceeds the limit of %d certificate requests per %s",
+		errRateLimited, nodeName, l.limit, l.window)
+}
+
+// respondRateLimited responds 429 with the seconds left in the window as Retry-After.
+func respondRateLimited(response *restful.Response, retryAfter time.Duration, err error) {
+	seconds := int((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	response.Header().Set("Retry-After", strconv.Itoa(seconds))
+	resps.Error(response, http.StatusTooManyRequests, err)
+}
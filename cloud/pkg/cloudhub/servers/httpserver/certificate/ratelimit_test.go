@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+)
+
+func newTestRateLimiter(counter signingCounter, now *time.Time) *signingRateLimiter {
+	return &signingRateLimiter{
+		counter: counter,
+		limit:   2,
+		window:  time.Minute,
+		now:     func() time.Time { return *now },
+	}
+}
+
+func TestSigningRateLimitAcrossReplicas(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC)
+	client := fake.NewSimpleClientset()
+	replica1 := newTestRateLimiter(&leaseCounter{client: client, namespace: "kubeedge"}, &now)
+	replica2 := newTestRateLimiter(&leaseCounter{client: client, namespace: "kubeedge"}, &now)
+
+	ctx := context.TODO()
+	_, err := replica1.allow(ctx, "node1")
+	require.NoError(t, err)
+	_, err = replica2.allow(ctx, "node1")
+	require.NoError(t, err)
+	// the third request within the window is rejected whichever replica serves it
+	retryAfter, err := replica1.allow(ctx, "node1")
+	require.ErrorIs(t, err, errRateLimited)
+	require.Equal(t, 50*time.Second, retryAfter)
+	_, err = replica2.allow(ctx, "node1")
+	require.ErrorIs(t, err, errRateLimited)
+	// the other nodes are counted separately
+	_, err = replica2.allow(ctx, "node2")
+	require.NoError(t, err)
+
+	// the count is reset in the next window
+	now = now.Add(time.Minute)
+	_, err = replica2.allow(ctx, "node1")
+	require.NoError(t, err)
+}
+
+func TestSigningRateLimitMemory(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC)
+	replica1 := newTestRateLimiter(newMemoryCounter(), &now)
+	replica2 := newTestRateLimiter(newMemoryCounter(), &now)
+
+	ctx := context.TODO()
+	for i := 0; i < 2; i++ {
+		_, err := replica1.allow(ctx, "node1")
+		require.NoError(t, err)
+	}
+	_, err := replica1.allow(ctx, "node1")
+	require.ErrorIs(t, err, errRateLimited)
+	// the counts in memory are kept per replica
+	_, err = replica2.allow(ctx, "node1")
+	require.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = replica1.allow(ctx, "node1")
+	require.NoError(t, err)
+
+	var limiter *signingRateLimiter
+	_, err = limiter.allow(ctx, "node1")
+	require.NoError(t, err)
+}
+
+func TestEdgeCoreClientCertRateLimited(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	now := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC)
+	defaultSigningRateLimiter = newTestRateLimiter(newMemoryCounter(), &now)
+	t.Cleanup(func() { defaultSigningRateLimiter = nil })
+
+	sign := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+		return recorder
+	}
+	for i := 0; i < 2; i++ {
+		resp := sign()
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	}
+	resp := sign()
+	require.Equal(t, http.StatusTooManyRequests, resp.Code, resp.Body.String())
+	require.Equal(t, "50", resp.Header().Get("Retry-After"))
+	require.True(t, strings.HasPrefix(resp.Body.String(), ReasonRateLimited+":"), resp.Body.String())
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+)
+
+// renewalOverlap returns the window during which the previous certificate of a node
+// is still accepted after the node renews it.
+func renewalOverlap() time.Duration {
+	return hubconfig.Config.CertRenewalOverlap * time.Minute
+}
+
+// revokeRenewed revokes the renewed certificate of the node after the overlap window, so the
+// connections still presenting it right after the renewal, e.g. an in-flight reconnection,
+// are accepted in the window. The renewed certificate is not revoked if the window is 0.
+func revokeRenewed(nodeName string, previous *x509.Certificate) {
+	overlap := renewalOverlap()
+	if overlap <= 0 {
+		return
+	}
+	serial := previous.SerialNumber.String()
+	revocation.DefaultList.RevokeSerialAfter(serial, previous.NotAfter, overlap)
+	klog.InfoS("Audit scheduled the revocation of the renewed certificate", "node", nodeName,
+		"serial", serial, "overlap", overlap)
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestEdgeCoreClientCertRenewalOverlap(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	originList, originStore := revocation.DefaultList, sessionStore
+	defer func() {
+		hubconfig.Config.CertRenewalOverlap = 0
+		hubconfig.Config.DuplicateEnrollment = nil
+		revocation.DefaultList, sessionStore = originList, originStore
+	}()
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	// request signs a certificate with a new key, it is a renewal if peer is not nil
+	request := func(peer *x509.Certificate) *x509.Certificate {
+		pk, err := certshandler.GenPrivateKey()
+		require.NoError(t, err)
+		csr, err := certshandler.CreateCSR(pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:testnode",
+		}, pk, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+		req.TLS = &tls.ConnectionState{}
+		if peer != nil {
+			req.TLS.PeerCertificates = []*x509.Certificate{peer}
+		} else {
+			req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+		}
+		req.Header.Set(types.HeaderNodeName, "testnode")
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+		require.NoError(t, err)
+		return cert
+	}
+	// after moves the clock by d
+	after := func(d time.Duration) *gomonkey.Patches {
+		now := time.Now().Add(d)
+		return gomonkey.ApplyFunc(time.Now, func() time.Time { return now })
+	}
+
+	t.Run("the previous certificate is accepted in the window", func(t *testing.T) {
+		revocation.DefaultList = revocation.NewList()
+		hubconfig.Config.CertRenewalOverlap = 10
+
+		previous := request(nil)
+		renewed := request(previous)
+		require.NoError(t, verifyCert(previous, "testnode", nil))
+		require.NoError(t, verifyCert(renewed, "testnode", nil))
+
+		patches := after(9 * time.Minute)
+		require.NoError(t, verifyCert(previous, "testnode", nil))
+		patches.Reset()
+
+		patches = after(11 * time.Minute)
+		defer patches.Reset()
+		require.ErrorContains(t, verifyCert(previous, "testnode", nil), "is revoked")
+		require.NoError(t, verifyCert(renewed, "testnode", nil))
+	})
+
+	t.Run("the previous certificate is not revoked if the window is 0", func(t *testing.T) {
+		revocation.DefaultList = revocation.NewList()
+		hubconfig.Config.CertRenewalOverlap = 0
+
+		previous := request(nil)
+		request(previous)
+
+		patches := after(11 * time.Minute)
+		defer patches.Reset()
+		require.NoError(t, verifyCert(previous, "testnode", nil))
+	})
+
+	t.Run("rotating the key of the active session is not fenced", func(t *testing.T) {
+		revocation.DefaultList = revocation.NewList()
+		hubconfig.Config.CertRenewalOverlap = 10
+		hubconfig.Config.DuplicateEnrollment = &v1alpha1.CloudHubDuplicateEnrollment{
+			Policy: v1alpha1.DuplicateEnrollmentFence,
+		}
+
+		previous := request(nil)
+		store := &fakeSessionStore{certs: map[string]*x509.Certificate{"testnode": previous}}
+		sessionStore = store
+		request(previous)
+		require.Empty(t, store.closed)
+		require.NoError(t, verifyCert(previous, "testnode", nil))
+	})
+}
+
+func TestEdgeCoreClientCertFreshKey(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+	defer func() { hubconfig.Config.RequireFreshCSRKey = false }()
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	// request signs a certificate with the key, it is a renewal if peer is not nil
+	request := func(pk certs.PrivateKeyWrap, peer *x509.Certificate) *httptest.ResponseRecorder {
+		csr, err := certshandler.CreateCSR(pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:testnode",
+		}, pk, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+		req.TLS = &tls.ConnectionState{}
+		if peer != nil {
+			req.TLS.PeerCertificates = []*x509.Certificate{peer}
+		} else {
+			req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+		}
+		req.Header.Set(types.HeaderNodeName, "testnode")
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	resp := request(pk, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	previous, err := x509.ParseCertificate(resp.Body.Bytes())
+	require.NoError(t, err)
+
+	// the renewal with the same key is allowed by default
+	resp = request(pk, previous)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	hubconfig.Config.RequireFreshCSRKey = true
+	resp = request(pk, previous)
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "must be generated with a new key")
+
+	newKey, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	resp = request(newKey, previous)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// the first enrollment is not a renewal, so the key is not checked
+	resp = request(pk, nil)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
+
+func TestEdgeCoreClientCertRevoked(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	cert := ca.Issue(t, node)
+	originList := revocation.DefaultList
+	revocation.DefaultList = revocation.NewList()
+	t.Cleanup(func() { revocation.DefaultList = originList })
+
+	renew := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.RenewalRequest(cert)), restful.NewResponse(recorder))
+		return recorder
+	}
+	resp := renew()
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	revocation.DefaultList.Revoke(cert)
+	resp = renew()
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), "is revoked")
+
+	require.True(t, revocation.DefaultList.Unrevoke(cert.SerialNumber.String()))
+	resp = renew()
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+}
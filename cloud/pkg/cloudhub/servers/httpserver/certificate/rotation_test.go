@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certinfo"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// trustAdditionalCAs sets the additional CAs of CloudHub until the test finishes.
+func trustAdditionalCAs(t *testing.T, cas ...*testutil.CA) {
+	additional := hubconfig.Config.AdditionalCAs
+	t.Cleanup(func() { hubconfig.Config.AdditionalCAs = additional })
+	hubconfig.Config.AdditionalCAs = nil
+	for _, ca := range cas {
+		hubconfig.Config.AdditionalCAs = append(hubconfig.Config.AdditionalCAs, ca.Cert.Raw)
+	}
+}
+
+func TestEdgeCoreClientCertCARotation(t *testing.T) {
+	previous := testutil.NewCA(t)
+	node := previous.NewNode(t, "testnode")
+	cert := previous.Issue(t, node)
+	ca := testutil.NewCA(t)
+
+	renew := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.RenewalRequest(cert)), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	// the certificate signed by the previous CA is not trusted once the CA is replaced
+	trustAdditionalCAs(t)
+	resp := renew()
+	require.Equal(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+
+	trustAdditionalCAs(t, previous)
+	resp = renew()
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	renewed, err := x509.ParseCertificate(resp.Body.Bytes())
+	require.NoError(t, err)
+	// the new certificate is always signed by the CA
+	require.NoError(t, renewed.CheckSignatureFrom(ca.Cert))
+	require.Error(t, renewed.CheckSignatureFrom(previous.Cert))
+	// the rotated certificate becomes the current certificate of the node
+	info, ok := certinfo.DefaultStore.Get("testnode")
+	require.True(t, ok)
+	require.Equal(t, renewed.SerialNumber.String(), info.Serial)
+	require.True(t, renewed.NotAfter.Equal(info.NotAfter))
+}
+
+func TestGetCABundle(t *testing.T) {
+	previous := testutil.NewCA(t)
+	ca := testutil.NewCA(t)
+	trustAdditionalCAs(t, previous)
+
+	getCA := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ca.crt", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		recorder := httptest.NewRecorder()
+		GetCA(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	// the old clients get the CA only in DER, which the token hash is computed from
+	resp := getCA("")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, ca.Cert.Raw, resp.Body.Bytes())
+	etag := resp.Header().Get("ETag")
+
+	resp = getCA(types.MIMEPEMFile)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, types.MIMEPEMFile, resp.Header().Get("Content-Type"))
+	require.NotEqual(t, etag, resp.Header().Get("ETag"))
+	var bundle [][]byte
+	for rest := resp.Body.Bytes(); len(rest) > 0; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		require.NotNil(t, block)
+		bundle = append(bundle, block.Bytes)
+	}
+	require.Equal(t, [][]byte{ca.Cert.Raw, previous.Cert.Raw}, bundle)
+
+	resp = getCA(types.MIMEPKIXCert)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, types.MIMEPKIXCert, resp.Header().Get("Content-Type"))
+	require.Equal(t, ca.Cert.Raw, resp.Body.Bytes())
+	require.Equal(t, etag, resp.Header().Get("ETag"))
+
+	// the PEM bundle is not modified
+	req := httptest.NewRequest(http.MethodGet, "/ca.crt", nil)
+	req.Header.Set("Accept", types.MIMEPEMFile)
+	req.Header.Set("If-None-Match", caETag(caBundlePEM()))
+	recorder := httptest.NewRecorder()
+	GetCA(restful.NewRequest(req), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusNotModified, recorder.Code)
+	require.Empty(t, recorder.Body.Bytes())
+}
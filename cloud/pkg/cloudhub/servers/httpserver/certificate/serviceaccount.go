@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+)
+
+// ProvisionableNodesAnnotation is the annotation of a ServiceAccount which lists the
+// comma-separated node names that the ServiceAccount may request certificates for.
+const ProvisionableNodesAnnotation = "cloudhub.kubeedge.io/provisionable-nodes"
+
+const (
+	defaultTokenReviewSteps           = 4
+	defaultTokenReviewInitialInterval = 100 * time.Millisecond
+	defaultTokenReviewTimeout         = 2 * time.Second
+	// tokenReviewRetryAfterSeconds is the value of Retry-After header when the TokenReview
+	// keeps failing transiently.
+	tokenReviewRetryAfterSeconds = "5"
+)
+
+func delegatedSigningEnabled() bool {
+	return hubconfig.Config.DelegatedSigning != nil && hubconfig.Config.DelegatedSigning.Enable
+}
+
+// transientError is an error of the API server which may succeed on retry.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// classifyError marks the err as transient if the API server is unreachable or temporarily
+// unable to serve, the other errors are definitive.
+func classifyError(err error) error {
+	if apierrors.IsServiceUnavailable(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsInternalError(err) || apierrors.IsUnexpectedServerError(err) ||
+		utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err) || utilnet.IsTimeout(err) {
+		return &transientError{err: err}
+	}
+	return err
+}
+
+// tokenReviewRetry returns the backoff and the time bound of retrying the TokenReview.
+func tokenReviewRetry() (wait.Backoff, time.Duration) {
+	backoff := wait.Backoff{
+		Steps:    defaultTokenReviewSteps,
+		Duration: defaultTokenReviewInitialInterval,
+		Factor:   2,
+	}
+	timeout := defaultTokenReviewTimeout
+	if d := hubconfig.Config.DelegatedSigning; d != nil && d.Retry != nil {
+		if d.Retry.Steps > 0 {
+			backoff.Steps = int(d.Retry.Steps)
+		}
+		if d.Retry.InitialInterval > 0 {
+			backoff.Duration = time.Duration(d.Retry.InitialInterval) * time.Millisecond
+		}
+		if d.Retry.Timeout > 0 {
+			timeout = time.Duration(d.Retry.Timeout) * time.Millisecond
+		}
+	}
+	return backoff, timeout
+}
+
+// verifyServiceAccountTokenWithRetry validates the ServiceAccount token like verifyServiceAccountToken,
+// the transient errors of the API server are retried with exponential backoff within the time bound.
+func verifyServiceAccountTokenWithRetry(ctx context.Context, token string) ([]string, error) {
+	backoff, timeout := tokenReviewRetry()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var nodes []string
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
+		nodes, lastErr = verifyServiceAccountToken(ctx, token)
+		var te *transientError
+		if errors.As(lastErr, &te) {
+			klog.V(4).Infof("transient failure of the TokenReview, will retry, err: %v", lastErr)
+			return false, nil
+		}
+		return true, lastErr
+	})
+	if err != nil {
+		if lastErr != nil {
+			// the retries are exhausted or timed out, report the last failure
+			return nil, lastErr
+		}
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// verifyServiceAccountToken validates the ServiceAccount token via TokenReview,
+// and returns the node names that the ServiceAccount may provision. The errors
+// of the API server which may succeed on retry are returned as transientError.
+func verifyServiceAccountToken(ctx context.Context, token string) ([]string, error) {
+	kubeClient := client.GetKubeClient()
+	review, err := kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, classifyError(fmt.Errorf("failed to review the token, err: %w", err))
+	}
+	if !review.Status.Authenticated {
+		return nil, fmt.Errorf("the token is not authenticated, err: %s", review.Status.Error)
+	}
+	namespace, name, err := serviceaccount.SplitUsername(review.Status.User.Username)
+	if err != nil {
+		return nil, fmt.Errorf("the token does not belong to a ServiceAccount, err: %v", err)
+	}
+	sa, err := kubeClient.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, classifyError(fmt.Errorf("failed to get ServiceAccount %s/%s, err: %w", namespace, name, err))
+	}
+	var nodes []string
+	for _, node := range strings.Split(sa.Annotations[ProvisionableNodesAnnotation], ",") {
+		if node = strings.TrimSpace(node); node != "" {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("ServiceAccount %s/%s is not permitted to provision any node", namespace, name)
+	}
+	return nodes, nil
+}
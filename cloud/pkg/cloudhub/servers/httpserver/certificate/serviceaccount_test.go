@@ -0,0 +1,235 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+const validSAToken = "valid-sa-token"
+
+func newFakeTokenReviewClient() kubernetes.Interface {
+	cli := fake.NewSimpleClientset(&corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "provisioner",
+			Namespace: "gitops",
+			Annotations: map[string]string{
+				ProvisionableNodesAnnotation: "node1, node2",
+			},
+		},
+	})
+	cli.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview).DeepCopy()
+		if review.Spec.Token == validSAToken {
+			review.Status.Authenticated = true
+			review.Status.User.Username = "system:serviceaccount:gitops:provisioner"
+		} else {
+			review.Status.Error = "invalid bearer token"
+		}
+		return true, review, nil
+	})
+	return cli
+}
+
+func TestEdgeCoreClientCertWithServiceAccountToken(t *testing.T) {
+	patches := gomonkey.ApplyFunc(client.GetKubeClient, newFakeTokenReviewClient)
+	defer patches.Reset()
+
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+	hubconfig.Config.DelegatedSigning = &v1alpha1.CloudHubDelegatedSigning{Enable: true}
+	defer func() { hubconfig.Config.DelegatedSigning = nil }()
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		nodeName string
+		token    string
+		wantCode int
+	}{
+		{
+			name:     "token permitted for the node",
+			nodeName: "node2",
+			token:    validSAToken,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "token not permitted for the node",
+			nodeName: "node3",
+			token:    validSAToken,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "invalid token",
+			nodeName: "node1",
+			token:    "invalid-sa-token",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			csr, err := certshandler.CreateCSR(pkix.Name{
+				Organization: []string{"system:nodes"},
+				CommonName:   "system:node:" + c.nodeName,
+			}, pk, nil)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+			req.TLS = &tls.ConnectionState{}
+			req.Header.Set(types.HeaderNodeName, c.nodeName)
+			req.Header.Set(types.HeaderAuthorization, "Bearer "+c.token)
+			recorder := httptest.NewRecorder()
+			EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+			require.Equal(t, c.wantCode, recorder.Code, recorder.Body.String())
+		})
+	}
+
+	t.Run("the API server keeps failing", func(t *testing.T) {
+		hubconfig.Config.DelegatedSigning.Retry = &v1alpha1.TokenReviewRetry{Steps: 2, InitialInterval: 1, Timeout: 2000}
+		patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+			cli := newFakeTokenReviewClient().(*fake.Clientset)
+			cli.PrependReactor("create", "tokenreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewServiceUnavailable("etcd is unavailable")
+			})
+			return cli
+		})
+		defer patches.Reset()
+
+		csr, err := certshandler.CreateCSR(pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:node2",
+		}, pk, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+		req.TLS = &tls.ConnectionState{}
+		req.Header.Set(types.HeaderNodeName, "node2")
+		req.Header.Set(types.HeaderAuthorization, "Bearer "+validSAToken)
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		require.Equal(t, http.StatusServiceUnavailable, recorder.Code, recorder.Body.String())
+		require.Equal(t, tokenReviewRetryAfterSeconds, recorder.Header().Get("Retry-After"))
+	})
+}
+
+func TestVerifyServiceAccountTokenWithRetry(t *testing.T) {
+	hubconfig.Config.DelegatedSigning = &v1alpha1.CloudHubDelegatedSigning{
+		Enable: true,
+		Retry:  &v1alpha1.TokenReviewRetry{Steps: 4, InitialInterval: 1, Timeout: 2000},
+	}
+	defer func() { hubconfig.Config.DelegatedSigning = nil }()
+
+	tokenReviews := schema.GroupResource{Group: "authentication.k8s.io", Resource: "tokenreviews"}
+	cases := []struct {
+		name          string
+		token         string
+		failures      int
+		failure       error
+		wantCalls     int
+		wantNodes     []string
+		containsError string
+	}{
+		{
+			name:      "transient errors eventually succeed",
+			token:     validSAToken,
+			failures:  2,
+			failure:   apierrors.NewServiceUnavailable("etcd is unavailable"),
+			wantCalls: 3,
+			wantNodes: []string{"node1", "node2"},
+		},
+		{
+			name:          "invalid token fails immediately",
+			token:         "invalid-token",
+			wantCalls:     1,
+			containsError: "the token is not authenticated",
+		},
+		{
+			name:          "definitive API error fails immediately",
+			token:         validSAToken,
+			failures:      4,
+			failure:       apierrors.NewForbidden(tokenReviews, "", nil),
+			wantCalls:     1,
+			containsError: "forbidden",
+		},
+		{
+			name:          "retries are bounded",
+			token:         validSAToken,
+			failures:      10,
+			failure:       apierrors.NewTooManyRequests("slow down", 0),
+			wantCalls:     4,
+			containsError: "slow down",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var calls int
+			patches := gomonkey.ApplyFunc(client.GetKubeClient, func() kubernetes.Interface {
+				cli := newFakeTokenReviewClient().(*fake.Clientset)
+				cli.PrependReactor("create", "tokenreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+					calls++
+					if calls <= c.failures {
+						return true, nil, c.failure
+					}
+					return false, nil, nil
+				})
+				return cli
+			})
+			defer patches.Reset()
+
+			nodes, err := verifyServiceAccountTokenWithRetry(context.TODO(), c.token)
+			require.Equal(t, c.wantCalls, calls)
+			if c.containsError != "" {
+				require.ErrorContains(t, err, c.containsError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.wantNodes, nodes)
+		})
+	}
+}
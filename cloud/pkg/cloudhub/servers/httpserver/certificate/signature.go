@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// signaturePolicy returns the signature policy of the edge certificates,
+// a nil policy allows all the algorithms except the weak ones.
+func signaturePolicy() *certs.SignaturePolicy {
+	p := hubconfig.Config.SignaturePolicy
+	if p == nil {
+		return nil
+	}
+	return &certs.SignaturePolicy{
+		Allowed: p.AllowedAlgorithms,
+		Digests: p.Digests,
+	}
+}
+
+// CheckSignaturePolicy checks that the signature policy is valid and a signature algorithm
+// allowed by the policy can be selected for the CA keys of CloudHub and the issuers, it is
+// checked when CloudHub starts.
+func CheckSignaturePolicy() error {
+	policy := signaturePolicy()
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid signature policy, err: %v", err)
+	}
+	if err := checkSignaturePolicyForCA(policy, hubconfig.Config.Ca); err != nil {
+		return fmt.Errorf("invalid signature policy for the CA, err: %v", err)
+	}
+	for name, iss := range issuers {
+		if err := checkSignaturePolicyForCA(policy, iss.ca); err != nil {
+			return fmt.Errorf("invalid signature policy for the CA of issuer %s, err: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func checkSignaturePolicyForCA(policy *certs.SignaturePolicy, caDER []byte) error {
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return err
+	}
+	_, err = policy.SelectSignatureAlgorithm(ca.PublicKey)
+	return err
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"net/url"
+	"slices"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// spiffeID returns the SPIFFE ID of the node, ok is false if SPIFFE is not enabled.
+func spiffeID(nodeName string) (id *url.URL, ok bool) {
+	p := hubconfig.Config.SPIFFE
+	if p == nil || !p.Enable {
+		return nil, false
+	}
+	return certs.EdgeNodeSPIFFEID(p.TrustDomain, nodeName), true
+}
+
+// hasSPIFFEID reports whether the certificate carries the SPIFFE ID of the node as a URI SAN,
+// it is always false if SPIFFE is not enabled.
+func hasSPIFFEID(cert *x509.Certificate, nodeName string) bool {
+	id, ok := spiffeID(nodeName)
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(cert.URIs, func(uri *url.URL) bool {
+		return uri.String() == id.String()
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// enableSPIFFE enables SPIFFE with the trust domain until the test finishes.
+func enableSPIFFE(t *testing.T, trustDomain string) {
+	spiffe := hubconfig.Config.SPIFFE
+	t.Cleanup(func() { hubconfig.Config.SPIFFE = spiffe })
+	hubconfig.Config.SPIFFE = &cloudcorev1alpha1.CloudHubSPIFFE{Enable: true, TrustDomain: trustDomain}
+}
+
+func TestEdgeCoreClientCertSPIFFE(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	sign := func(req *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	t.Run("issued", func(t *testing.T) {
+		enableSPIFFE(t, "example.org")
+		resp := sign(node.Request())
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		cert, err := x509.ParseCertificate(resp.Body.Bytes())
+		require.NoError(t, err)
+		require.Len(t, cert.URIs, 1)
+		require.Equal(t, "spiffe://example.org/node/testnode", cert.URIs[0].String())
+		require.Equal(t, "system:node:testnode", cert.Subject.CommonName)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		resp := sign(node.Request())
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		cert, err := x509.ParseCertificate(resp.Body.Bytes())
+		require.NoError(t, err)
+		require.Empty(t, cert.URIs)
+	})
+
+	// the certificate presented to renew is identified only by the SPIFFE ID
+	csr, err := certs.GetHandler(certs.HandlerTypeX509).CreateCSR(pkix.Name{CommonName: "workload"}, node.Key, nil)
+	require.NoError(t, err)
+	block, err := certs.GetHandler(certs.HandlerTypeX509).SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+		csr.Bytes, ca.Cert.Raw, ca.Key.DER(), []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour).
+		WithURIs(certs.EdgeNodeSPIFFEID("example.org", "testnode")))
+	require.NoError(t, err)
+	spiffeOnly, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		trustDomain string
+		nodeName    string
+		wantCode    int
+	}{
+		{name: "rotation", trustDomain: "example.org", nodeName: "testnode", wantCode: http.StatusOK},
+		{name: "another node", trustDomain: "example.org", nodeName: "othernode", wantCode: http.StatusUnauthorized},
+		{name: "another trust domain", trustDomain: "example.com", nodeName: "testnode", wantCode: http.StatusUnauthorized},
+		{name: "rotation without SPIFFE", nodeName: "testnode", wantCode: http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.trustDomain != "" {
+				enableSPIFFE(t, c.trustDomain)
+			}
+			renewing := ca.NewNode(t, c.nodeName)
+			resp := sign(renewing.RenewalRequest(spiffeOnly))
+			require.Equal(t, c.wantCode, resp.Code, resp.Body.String())
+		})
+	}
+}
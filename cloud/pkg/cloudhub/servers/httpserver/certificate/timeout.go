@@ -0,0 +1,49 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// ReasonSigningTimeout is the reason code of the response when the edge certificate request
+// is not done within the signing timeout, the request can be retried.
+const ReasonSigningTimeout = types.ReasonSigningTimeout
+
+// signingContext returns the ctx of the edge certificate request with the signing deadline.
+func signingContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if t := hubconfig.Config.SigningTimeout; t > 0 {
+		return context.WithTimeout(parent, time.Duration(t)*time.Second)
+	}
+	return context.WithCancel(parent)
+}
+
+// signingTimeoutError returns the error responded with 504 if the signing deadline of the ctx
+// has passed, otherwise it returns nil.
+func signingTimeoutError(ctx context.Context) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil
+	}
+	return fmt.Errorf("%s: the request is not done within the signing timeout of %ds, please retry later",
+		ReasonSigningTimeout, hubconfig.Config.SigningTimeout)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// slowHandler is a signer which hangs until the ctx is done, e.g. an unresponsive remote signer.
+type slowHandler struct {
+	certs.Handler
+	started chan struct{}
+}
+
+func (h *slowHandler) SignCerts(ctx context.Context, _ certs.SignCertsOptions) (*pem.Block, error) {
+	close(h.started)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(time.Minute):
+		return nil, nil
+	}
+}
+
+func TestEdgeCoreClientCertSigningTimeout(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+	hubconfig.Config.SigningTimeout = 1
+	hubconfig.Config.IssuanceQuota = &v1alpha1.CloudHubIssuanceQuota{
+		Tenants: []v1alpha1.IssuanceQuotaTenant{
+			{Name: "tenant-a", NodeNamePrefix: "test", MaxCertificates: 1},
+		},
+	}
+	origin := defaultQuota
+	defaultQuota = newIssuanceQuota()
+	defer func() {
+		hubconfig.Config.SigningTimeout = 0
+		hubconfig.Config.IssuanceQuota = nil
+		defaultQuota = origin
+	}()
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:testnode",
+	}, pk, nil)
+	require.NoError(t, err)
+
+	handler := &slowHandler{Handler: certshandler, started: make(chan struct{})}
+	patches := gomonkey.ApplyFunc(certs.GetHandler, func(certs.HanndlerType) certs.Handler { return handler })
+	defer patches.Reset()
+
+	req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+	req.TLS = &tls.ConnectionState{}
+	req.Header.Set(types.HeaderNodeName, "testnode")
+	req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+	recorder := httptest.NewRecorder()
+	start := time.Now()
+	EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+
+	require.Less(t, time.Since(start), 5*time.Second)
+	<-handler.started
+	require.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	require.True(t, strings.HasPrefix(recorder.Body.String(), ReasonSigningTimeout+":"), recorder.Body.String())
+	require.Equal(t, signingRetryAfterSeconds, recorder.Header().Get("Retry-After"))
+
+	// the slot of the tenant reserved for the request is released
+	defaultQuota.mu.Lock()
+	require.Zero(t, defaultQuota.counts["tenant-a"])
+	defaultQuota.mu.Unlock()
+
+	// the signer returns on the cancellation, no goroutine is left behind in it
+	require.Eventually(t, func() bool {
+		buf := make([]byte, 1<<20)
+		stacks := string(buf[:runtime.Stack(buf, true)])
+		return !strings.Contains(stacks, "(*slowHandler).SignCerts")
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSigningQueueSkipsCanceledTask(t *testing.T) {
+	q := newSigningQueue(1, 1)
+	block := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_ = runFunc(q, func() {
+			close(started)
+			<-block
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var called bool
+	_, err := q.run(ctx, func(context.Context) (*pem.Block, error) {
+		called = true
+		return nil, nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the canceled task leaves the queue without running
+	close(block)
+	require.Eventually(t, func() bool { return len(q.tasks) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, runFunc(q, func() {}))
+	require.False(t, called)
+}
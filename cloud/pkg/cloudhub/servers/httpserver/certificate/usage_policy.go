@@ -0,0 +1,58 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"slices"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// defaultAllowedUsages are the ExtKeyUsages that the edge nodes may request if the ExtKeyUsagePolicy
+// does not specify any.
+var defaultAllowedUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth}
+
+// checkExtKeyUsagePolicy checks the ExtKeyUsages requested in the usagesStr header against the
+// ExtKeyUsagePolicy of CloudHub. ServerAuth is only allowed for the requests authenticated by the
+// certificates of the nodes, unless AllowServerAuthWithToken is set. It returns the status code that
+// should be responded when the usages are not allowed.
+func checkExtKeyUsagePolicy(usagesStr, authentication string) (int, error) {
+	usages, err := certs.ParseEdgeCertUsages(usagesStr)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	p := hubconfig.Config.ExtKeyUsagePolicy
+	allowed := defaultAllowedUsages
+	if p != nil && len(p.AllowedUsages) > 0 {
+		if allowed, err = certs.ParseExtKeyUsages(p.AllowedUsages); err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("invalid extKeyUsagePolicy config, err: %v", err)
+		}
+	}
+	if err := certs.CheckEdgeCertUsagesAllowed(usages, allowed); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if authentication != certs.IssuanceAuthCertificate && slices.Contains(usages, x509.ExtKeyUsageServerAuth) &&
+		(p == nil || !p.AllowServerAuthWithToken) {
+		return http.StatusBadRequest, fmt.Errorf("the ExtKeyUsage ServerAuth(%d) is only issued to the edge nodes "+
+			"authenticated by their certificates", x509.ExtKeyUsageServerAuth)
+	}
+	return http.StatusOK, nil
+}
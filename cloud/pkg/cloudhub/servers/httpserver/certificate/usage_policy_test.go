@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestEdgeCoreClientCertExtKeyUsagePolicy(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+	policy := hubconfig.Config.ExtKeyUsagePolicy
+	t.Cleanup(func() { hubconfig.Config.ExtKeyUsagePolicy = policy })
+
+	cases := []struct {
+		name          string
+		policy        *v1alpha1.CloudHubExtKeyUsagePolicy
+		renewal       bool
+		usages        string
+		wantCode      int
+		wantUsages    []x509.ExtKeyUsage
+		containsError string
+	}{
+		{
+			name:       "client auth with a token",
+			usages:     "[2]",
+			wantCode:   http.StatusOK,
+			wantUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		},
+		{
+			name:          "code signing is refused",
+			usages:        "[2,3]",
+			wantCode:      http.StatusBadRequest,
+			containsError: "the ExtKeyUsage CodeSigning(3) is not allowed",
+		},
+		{
+			name:          "OCSP signing is refused with a certificate",
+			renewal:       true,
+			usages:        "[9]",
+			wantCode:      http.StatusBadRequest,
+			containsError: "the ExtKeyUsage OCSPSigning(9) is not allowed",
+		},
+		{
+			name:          "all the disallowed usages are echoed",
+			usages:        "[3,9]",
+			wantCode:      http.StatusBadRequest,
+			containsError: "the ExtKeyUsages CodeSigning(3), OCSPSigning(9) are not allowed",
+		},
+		{
+			name:          "server auth with a token",
+			usages:        "[1,2]",
+			wantCode:      http.StatusBadRequest,
+			containsError: "only issued to the edge nodes authenticated by their certificates",
+		},
+		{
+			name:       "server auth with a certificate",
+			renewal:    true,
+			usages:     "[1,2]",
+			wantCode:   http.StatusOK,
+			wantUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		},
+		{
+			name:       "server auth with a token is allowed explicitly",
+			policy:     &v1alpha1.CloudHubExtKeyUsagePolicy{AllowServerAuthWithToken: true},
+			usages:     "[1,2]",
+			wantCode:   http.StatusOK,
+			wantUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		},
+		{
+			name:          "server auth is not in the allowed usages",
+			policy:        &v1alpha1.CloudHubExtKeyUsagePolicy{AllowedUsages: []string{"ClientAuth"}},
+			renewal:       true,
+			usages:        "[1,2]",
+			wantCode:      http.StatusBadRequest,
+			containsError: "the ExtKeyUsage ServerAuth(1) is not allowed",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubconfig.Config.ExtKeyUsagePolicy = c.policy
+			req := node.Request()
+			if c.renewal {
+				req = node.RenewalRequest(ca.Issue(t, node))
+			}
+			req.Header.Set(types.HeaderExtKeyUsages, c.usages)
+			recorder := httptest.NewRecorder()
+			EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+			require.Equal(t, c.wantCode, recorder.Code, recorder.Body.String())
+			if c.containsError != "" {
+				require.Contains(t, recorder.Body.String(), c.containsError)
+				return
+			}
+			cert, err := x509.ParseCertificate(recorder.Body.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, c.wantUsages, cert.ExtKeyUsage)
+		})
+	}
+}
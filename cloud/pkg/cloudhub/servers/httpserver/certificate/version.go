@@ -0,0 +1,48 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"crypto/x509"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// caResponse returns the CA in the representation of the API version v2.
+func caResponse() types.CAResponse {
+	return types.CAResponse{Certificate: hubconfig.Config.Ca, Bundle: hubconfig.Config.CABundle()}
+}
+
+// edgeCertResponse returns the issued certificate with its metadata and the warnings of the request
+// in the representation of the API version v2.
+func edgeCertResponse(certDER []byte, warnings []string) types.EdgeCertResponse {
+	resp := types.EdgeCertResponse{Certificate: certDER, Warnings: warnings}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		klog.Warningf("failed to parse the issued certificate, err: %v", err)
+		return resp
+	}
+	notAfter := metav1.NewTime(cert.NotAfter.UTC())
+	resp.SerialNumber = cert.SerialNumber.String()
+	resp.NotAfter = &notAfter
+	resp.SignatureAlgorithm = cert.SignatureAlgorithm.String()
+	return resp
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// serveVersioned serves the request by the handler behind the version filter of the certificate routes
+func serveVersioned(handler restful.RouteFunction, req *http.Request, version string) *httptest.ResponseRecorder {
+	ws := new(restful.WebService)
+	ws.Produces("*/*")
+	ws.Route(ws.GET(req.URL.Path).Filter(resps.VersionFilter(resps.APIVersionV1, resps.APIVersionV2)).To(handler))
+	container := restful.NewContainer()
+	container.Add(ws)
+	if version != "" {
+		req.Header.Set(types.HeaderAPIVersion, version)
+	}
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestGetCAVersions(t *testing.T) {
+	caDER := []byte("ca-der")
+	hubconfig.Config.Ca = caDER
+
+	// the raw responses of the old edgecores are pinned byte for byte
+	var v1ETag string
+	for _, version := range []string{"", resps.APIVersionV1} {
+		resp := serveVersioned(GetCA, httptest.NewRequest(http.MethodGet, "/ca.crt", nil), version)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Equal(t, caDER, resp.Body.Bytes())
+		require.Empty(t, resp.Header().Get("Content-Type"))
+		require.Equal(t, version, resp.Header().Get(types.HeaderAPIVersion))
+		v1ETag = resp.Header().Get("ETag")
+	}
+
+	resp := serveVersioned(GetCA, httptest.NewRequest(http.MethodGet, "/ca.crt", nil), resps.APIVersionV2)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, restful.MIME_JSON, resp.Header().Get("Content-Type"))
+	require.NotEqual(t, v1ETag, resp.Header().Get("ETag"))
+	var caResp types.CAResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &caResp))
+	require.Equal(t, caDER, caResp.Certificate)
+	require.Equal(t, [][]byte{caDER}, caResp.Bundle)
+
+	resp = serveVersioned(GetCA, httptest.NewRequest(http.MethodGet, "/ca.crt", nil), "v3")
+	require.Equal(t, http.StatusNotAcceptable, resp.Code)
+	require.Equal(t, "the API version v3 is not supported, supported versions: v1, v2", resp.Body.String())
+}
+
+func TestEdgeCoreClientCertVersions(t *testing.T) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	hubconfig.Config.Ca = caPem.Bytes
+	hubconfig.Config.CaKey = caKey.DER()
+	hubconfig.Config.CloudHub.EdgeCertSigningDuration = 1
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(caKey.DER())
+	require.NoError(t, err)
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := certshandler.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := certshandler.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:testnode",
+	}, pk, nil)
+	require.NoError(t, err)
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/edge.crt", bytes.NewReader(csr.Bytes))
+		req.TLS = &tls.ConnectionState{}
+		req.Header.Set(types.HeaderNodeName, "testnode")
+		req.Header.Set(types.HeaderAuthorization, "Bearer "+tokenStr)
+		return req
+	}
+
+	for _, version := range []string{"", resps.APIVersionV1} {
+		resp := serveVersioned(EdgeCoreClientCert, newRequest(), version)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.Empty(t, resp.Header().Get("Content-Type"))
+		// the body is exactly the certificate in DER
+		cert, err := x509.ParseCertificate(resp.Body.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, cert.Raw, resp.Body.Bytes())
+		require.Equal(t, strconv.Itoa(len(cert.Raw)), resp.Header().Get("Content-Length"))
+		require.Equal(t, cert.SerialNumber.String(), resp.Header().Get(types.HeaderCertSerial))
+	}
+
+	resp := serveVersioned(EdgeCoreClientCert, newRequest(), resps.APIVersionV2)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, restful.MIME_JSON, resp.Header().Get("Content-Type"))
+	var certResp types.EdgeCertResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &certResp))
+	cert, err := x509.ParseCertificate(certResp.Certificate)
+	require.NoError(t, err)
+	require.Equal(t, cert.SerialNumber.String(), certResp.SerialNumber)
+	require.Equal(t, cert.SerialNumber.String(), resp.Header().Get(types.HeaderCertSerial))
+	require.NotNil(t, certResp.NotAfter)
+	require.True(t, cert.NotAfter.Equal(certResp.NotAfter.Time))
+	require.Equal(t, cert.SignatureAlgorithm.String(), certResp.SignatureAlgorithm)
+}
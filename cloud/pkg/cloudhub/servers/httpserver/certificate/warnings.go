@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+type warningsKey struct{}
+
+// warningRecorder collects the non-fatal warnings of an edge certificate request, which are
+// responded in the Warning headers of the successful response like the Kubernetes API warnings.
+type warningRecorder struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+// withWarnings returns a ctx that the warnings added to it are collected by w.
+func withWarnings(ctx context.Context, w *warningRecorder) context.Context {
+	return context.WithValue(ctx, warningsKey{}, w)
+}
+
+// addWarning adds a warning to the request of the ctx, the duplicate warnings are dropped.
+// It does nothing if the ctx does not collect warnings.
+func addWarning(ctx context.Context, format string, args ...any) {
+	w, ok := ctx.Value(warningsKey{}).(*warningRecorder)
+	if !ok || w == nil {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !slices.Contains(w.warnings, msg) {
+		w.warnings = append(w.warnings, msg)
+	}
+}
+
+// list returns the warnings collected, it tolerates a nil receiver.
+func (w *warningRecorder) list() []string {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return slices.Clone(w.warnings)
+}
+
+// setWarningHeaders adds the warnings to the Warning headers of the response.
+func setWarningHeaders(response *restful.Response, warnings []string) {
+	for _, warning := range warnings {
+		response.Header().Add(types.HeaderWarning, types.FormatWarning(warning))
+	}
+}
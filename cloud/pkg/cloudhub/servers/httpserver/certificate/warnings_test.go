@@ -0,0 +1,63 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestEdgeCoreClientCertWarnings(t *testing.T) {
+	ca := testutil.NewCA(t)
+	node := ca.NewNode(t, "testnode")
+
+	cases := []struct {
+		name         string
+		durationDays int
+		want         []string
+	}{
+		{
+			name:         "no warning",
+			durationDays: 1,
+		},
+		{
+			name:         "the duration is clamped",
+			durationDays: 10*365 + 1,
+			want:         []string{`299 - "the validity period 87624h0m0s of the certificate is clamped to 87600h0m0s"`},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hubconfig.Config.CloudHub.EdgeCertSigningDuration = time.Duration(c.durationDays)
+			req := node.Request()
+			recorder := httptest.NewRecorder()
+			EdgeCoreClientCert(restful.NewRequest(req), restful.NewResponse(recorder))
+			require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+			require.Equal(t, c.want, recorder.Header().Values(types.HeaderWarning))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// ReasonSigningDenied is the reason code of the response when the signing webhook denies the
+// edge certificate request, or fails with the deny failure policy.
+const ReasonSigningDenied = types.ReasonSigningDenied
+
+var errSigningDenied = errors.New(ReasonSigningDenied)
+
+const (
+	defaultSigningWebhookSteps           = 3
+	defaultSigningWebhookInitialInterval = 200 * time.Millisecond
+	defaultSigningWebhookTimeout         = 5 * time.Second
+)
+
+// The results of the reviews of the signing webhook in the metrics.
+const (
+	signingWebhookAllow   = "allow"
+	signingWebhookDeny    = "deny"
+	signingWebhookTimeout = "timeout"
+	signingWebhookError   = "error"
+)
+
+// signingWebhook approves the edge certificate requests before they are signed.
+type signingWebhook struct {
+	url           string
+	client        *http.Client
+	failurePolicy cloudcorev1alpha1.SigningWebhookFailurePolicy
+	backoff       wait.Backoff
+	timeout       time.Duration
+}
+
+// defaultSigningWebhook is nil if the signing webhook is not configured.
+var defaultSigningWebhook *signingWebhook
+
+// InitSigningWebhook initializes the signing webhook from the config of CloudHub.
+func InitSigningWebhook() error {
+	w := hubconfig.Config.SigningWebhook
+	if w == nil || w.URL == "" {
+		return nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if w.CABundleFile != "" {
+		bundle, err := os.ReadFile(w.CABundleFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the CA bundle of the signing webhook, err: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("no CA certificate is found in the CA bundle %s of the signing webhook", w.CABundleFile)
+		}
+	}
+	webhook := &signingWebhook{
+		url: w.URL,
+		client: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+		failurePolicy: w.FailurePolicy,
+		backoff: wait.Backoff{
+			Steps:    defaultSigningWebhookSteps,
+			Duration: defaultSigningWebhookInitialInterval,
+			Factor:   2,
+		},
+		timeout: defaultSigningWebhookTimeout,
+	}
+	if r := w.Retry; r != nil {
+		if r.Steps > 0 {
+			webhook.backoff.Steps = int(r.Steps)
+		}
+		if r.InitialInterval > 0 {
+			webhook.backoff.Duration = time.Duration(r.InitialInterval) * time.Millisecond
+		}
+		if r.Timeout > 0 {
+			webhook.timeout = time.Duration(r.Timeout) * time.Millisecond
+		}
+	}
+	defaultSigningWebhook = webhook
+	return nil
+}
+
+// review asks the webhook whether the certificate of the node is signed, it returns the status
+// code that should be responded and the error if the request is denied. If the webhook fails
+// or times out, the request is denied unless the failure policy is allow.
+func (w *signingWebhook) review(ctx context.Context, nodeName string, payload []byte, authentication,
+	clientIP string) (int, error) {
+	if w == nil {
+		return http.StatusOK, nil
+	}
+	csrDER, err := certs.DecodeCSR(payload)
+	if err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid CSR, err: %v", err)
+	}
+	resp, err := w.callWithRetry(ctx, &types.SigningReviewRequest{
+		NodeName:   nodeName,
+		CSR:        string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})),
+		AuthMethod: authentication,
+		SourceIP:   clientIP,
+	})
+	if err != nil {
+		result := signingWebhookError
+		if errors.Is(err, context.DeadlineExceeded) || utilnet.IsTimeout(err) {
+			result = signingWebhookTimeout
+		}
+		monitor.SigningWebhookRequestsTotal.WithLabelValues(result).Inc()
+		if w.failurePolicy == cloudcorev1alpha1.SigningWebhookAllow {
+			klog.Warningf("the signing webhook failed, allow the certificate request of edgenode %s "+
+				"by the failure policy, err: %v", nodeName, err)
+			return http.StatusOK, nil
+		}
+		return http.StatusForbidden, fmt.Errorf("%w: the certificate request of edgenode %s is denied "+
+			"since the signing webhook failed, err: %v", errSigningDenied, nodeName, err)
+	}
+	if !resp.Allowed {
+		monitor.SigningWebhookRequestsTotal.WithLabelValues(signingWebhookDeny).Inc()
+		klog.InfoS("Audit signing denied by the webhook", "node", nodeName, "clientIP", clientIP, "message", resp.Message)
+		return http.StatusForbidden, fmt.Errorf("%w: the certificate request of edgenode %s is denied by the signing webhook: %s",
+			errSigningDenied, nodeName, resp.Message)
+	}
+	monitor.SigningWebhookRequestsTotal.WithLabelValues(signingWebhookAllow).Inc()
+	return http.StatusOK, nil
+}
+
+// callWithRetry calls the webhook like call, the transient failures are retried with
+// exponential backoff within the time bound.
+func (w *signingWebhook) callWithRetry(ctx context.Context, req *types.SigningReviewRequest) (*types.SigningReviewResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	var resp *types.SigningReviewResponse
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, w.backoff, func(ctx context.Context) (bool, error) {
+		resp, lastErr = w.call(ctx, req)
+		var te *transientError
+		if errors.As(lastErr, &te) {
+			klog.V(4).Infof("transient failure of the signing webhook, will retry, err: %v", lastErr)
+			return false, nil
+		}
+		return true, lastErr
+	})
+	if err != nil {
+		if lastErr != nil {
+			// the retries are exhausted or timed out, report the last failure
+			return nil, lastErr
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// call POSTs the SigningReview of the request to the webhook and returns its response.
+// The network errors and the 429 and 5xx responses are returned as transientError.
+func (w *signingWebhook) call(ctx context.Context, req *types.SigningReviewRequest) (*types.SigningReviewResponse, error) {
+	body, err := json.Marshal(types.SigningReview{Request: req})
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := w.client.Do(httpReq)
+	if err != nil {
+		return nil, &transientError{err: fmt.Errorf("failed to call the signing webhook, err: %w", err)}
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, constants.MaxRespBodyLength))
+	if err != nil {
+		return nil, &transientError{err: fmt.Errorf("failed to read the response of the signing webhook, err: %w", err)}
+	}
+	if code := httpResp.StatusCode; code != http.StatusOK {
+		err := fmt.Errorf("the signing webhook responded %d: %s", code, bytes.TrimSpace(data))
+		if code == http.StatusTooManyRequests || code >= http.StatusInternalServerError {
+			return nil, &transientError{err: err}
+		}
+		return nil, err
+	}
+	var review types.SigningReview
+	if err := json.Unmarshal(data, &review); err != nil {
+		return nil, fmt.Errorf("invalid response of the signing webhook, err: %v", err)
+	}
+	if review.Response == nil {
+		return nil, errors.New("invalid response of the signing webhook, the response is missing")
+	}
+	return review.Response, nil
+}
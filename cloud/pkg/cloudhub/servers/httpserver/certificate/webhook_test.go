@@ -0,0 +1,220 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	cloudcorev1alpha1 "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// fakeSigningWebhook is a signing webhook which responds the reviews by respond,
+// the reviews it receives are recorded.
+type fakeSigningWebhook struct {
+	*httptest.Server
+	calls   atomic.Int32
+	reviews chan *types.SigningReviewRequest
+}
+
+func newFakeSigningWebhook(t *testing.T, respond func(w http.ResponseWriter, calls int32)) *fakeSigningWebhook {
+	f := &fakeSigningWebhook{reviews: make(chan *types.SigningReviewRequest, 10)}
+	f.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var review types.SigningReview
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(w, "invalid review", http.StatusBadRequest)
+			return
+		}
+		f.reviews <- review.Request
+		respond(w, f.calls.Add(1))
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// install configures the fake as the signing webhook of CloudHub until the test finishes.
+func (f *fakeSigningWebhook) install(t *testing.T, policy cloudcorev1alpha1.SigningWebhookFailurePolicy) {
+	bundle := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: f.Certificate().Raw}), 0600))
+	webhook := hubconfig.Config.SigningWebhook
+	t.Cleanup(func() {
+		hubconfig.Config.SigningWebhook = webhook
+		defaultSigningWebhook = nil
+	})
+	hubconfig.Config.SigningWebhook = &cloudcorev1alpha1.CloudHubSigningWebhook{
+		URL:           f.URL,
+		CABundleFile:  bundle,
+		FailurePolicy: policy,
+		Retry: &cloudcorev1alpha1.SigningWebhookRetry{
+			Steps:           3,
+			InitialInterval: 10,
+			Timeout:         500,
+		},
+	}
+	require.NoError(t, InitSigningWebhook())
+}
+
+func respondReview(w http.ResponseWriter, allowed bool, message string) {
+	_ = json.NewEncoder(w).Encode(types.SigningReview{
+		Response: &types.SigningReviewResponse{Allowed: allowed, Message: message},
+	})
+}
+
+func TestEdgeCoreClientCertSigningWebhook(t *testing.T) {
+	ca := testutil.NewCA(t)
+	sign := func(node *testutil.Node) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+		return recorder
+	}
+	results := func() map[string]float64 {
+		m := make(map[string]float64)
+		for _, result := range []string{signingWebhookAllow, signingWebhookDeny, signingWebhookTimeout, signingWebhookError} {
+			m[result] = promtestutil.ToFloat64(monitor.SigningWebhookRequestsTotal.WithLabelValues(result))
+		}
+		return m
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		f := newFakeSigningWebhook(t, func(w http.ResponseWriter, _ int32) {
+			respondReview(w, true, "")
+		})
+		f.install(t, cloudcorev1alpha1.SigningWebhookDeny)
+		before := results()
+		node := ca.NewNode(t, "testnode")
+		resp := sign(node)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.Equal(t, before[signingWebhookAllow]+1, results()[signingWebhookAllow])
+
+		review := <-f.reviews
+		require.Equal(t, "testnode", review.NodeName)
+		require.Equal(t, certs.IssuanceAuthToken, review.AuthMethod)
+		require.NotEmpty(t, review.SourceIP)
+		block, _ := pem.Decode([]byte(review.CSR))
+		require.NotNil(t, block)
+		require.Equal(t, node.CSR, block.Bytes)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		f := newFakeSigningWebhook(t, func(w http.ResponseWriter, _ int32) {
+			respondReview(w, false, "the node is not in the inventory")
+		})
+		f.install(t, cloudcorev1alpha1.SigningWebhookAllow)
+		before := results()
+		resp := sign(ca.NewNode(t, "testnode"))
+		require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+		require.True(t, strings.HasPrefix(resp.Body.String(), ReasonSigningDenied+":"), resp.Body.String())
+		require.Contains(t, resp.Body.String(), "the node is not in the inventory")
+		require.Equal(t, before[signingWebhookDeny]+1, results()[signingWebhookDeny])
+	})
+
+	t.Run("retried", func(t *testing.T) {
+		f := newFakeSigningWebhook(t, func(w http.ResponseWriter, calls int32) {
+			if calls < 3 {
+				http.Error(w, "overloaded", http.StatusServiceUnavailable)
+				return
+			}
+			respondReview(w, true, "")
+		})
+		f.install(t, cloudcorev1alpha1.SigningWebhookDeny)
+		resp := sign(ca.NewNode(t, "testnode"))
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.Equal(t, int32(3), f.calls.Load())
+	})
+
+	t.Run("not retried on client errors", func(t *testing.T) {
+		f := newFakeSigningWebhook(t, func(w http.ResponseWriter, _ int32) {
+			http.Error(w, "bad review", http.StatusBadRequest)
+		})
+		f.install(t, cloudcorev1alpha1.SigningWebhookDeny)
+		before := results()
+		resp := sign(ca.NewNode(t, "testnode"))
+		require.Equal(t, http.StatusForbidden, resp.Code, resp.Body.String())
+		require.Equal(t, int32(1), f.calls.Load())
+		require.Equal(t, before[signingWebhookError]+1, results()[signingWebhookError])
+	})
+
+	timeoutCases := []struct {
+		policy   cloudcorev1alpha1.SigningWebhookFailurePolicy
+		wantCode int
+	}{
+		{policy: cloudcorev1alpha1.SigningWebhookDeny, wantCode: http.StatusForbidden},
+		{policy: cloudcorev1alpha1.SigningWebhookAllow, wantCode: http.StatusOK},
+	}
+	for _, c := range timeoutCases {
+		t.Run("timeout with failure policy "+string(c.policy), func(t *testing.T) {
+			release := make(chan struct{})
+			f := newFakeSigningWebhook(t, func(w http.ResponseWriter, _ int32) {
+				select {
+				case <-release:
+				case <-time.After(5 * time.Second):
+				}
+				respondReview(w, true, "")
+			})
+			// the handler is released before the server is closed
+			t.Cleanup(func() { close(release) })
+			f.install(t, c.policy)
+			before := results()
+			resp := sign(ca.NewNode(t, "testnode"))
+			require.Equal(t, c.wantCode, resp.Code, resp.Body.String())
+			require.Equal(t, before[signingWebhookTimeout]+1, results()[signingWebhookTimeout])
+		})
+	}
+}
+
+func TestInitSigningWebhook(t *testing.T) {
+	webhook := hubconfig.Config.SigningWebhook
+	t.Cleanup(func() {
+		hubconfig.Config.SigningWebhook = webhook
+		defaultSigningWebhook = nil
+	})
+
+	hubconfig.Config.SigningWebhook = &cloudcorev1alpha1.CloudHubSigningWebhook{}
+	require.NoError(t, InitSigningWebhook())
+	require.Nil(t, defaultSigningWebhook)
+
+	bundle := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(bundle, []byte("not a certificate"), 0600))
+	hubconfig.Config.SigningWebhook = &cloudcorev1alpha1.CloudHubSigningWebhook{
+		URL:          "https://approver.example.com/review",
+		CABundleFile: bundle,
+	}
+	require.Error(t, InitSigningWebhook())
+
+	hubconfig.Config.SigningWebhook.CABundleFile = ""
+	require.NoError(t, InitSigningWebhook())
+	require.Equal(t, defaultSigningWebhookSteps, defaultSigningWebhook.backoff.Steps)
+	require.Equal(t, defaultSigningWebhookTimeout, defaultSigningWebhook.timeout)
+}
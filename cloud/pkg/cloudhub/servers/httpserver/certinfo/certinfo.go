@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinfo
+
+import (
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+// Info is the certificate that an edge node presented to CloudHub most recently.
+type Info struct {
+	NodeName string `json:"nodeName"`
+	// Serial is the decimal serial number of the certificate.
+	Serial   string    `json:"serial"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"expiry"`
+	// LastSeen is the time that the certificate was presented or issued.
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// Store stores the current certificate of each edge node in memory, which is refreshed when
+// the node connects to CloudHub and when its certificate is rotated.
+type Store struct {
+	mu    sync.RWMutex
+	items map[string]Info
+	now   func() time.Time
+}
+
+// DefaultStore is the certificate store updated by the cloudhub servers and the https server.
+var DefaultStore = NewStore()
+
+func NewStore() *Store {
+	return &Store{
+		items: make(map[string]Info),
+		now:   time.Now,
+	}
+}
+
+// Record records the certificate as the current certificate of the node, and exports its
+// expiration in the metrics.
+func (s *Store) Record(nodeName string, cert *x509.Certificate) {
+	info := Info{
+		NodeName: nodeName,
+		Serial:   cert.SerialNumber.String(),
+		Issuer:   cert.Issuer.String(),
+		NotAfter: cert.NotAfter.UTC(),
+		LastSeen: s.now().UTC(),
+	}
+	s.mu.Lock()
+	s.items[nodeName] = info
+	s.mu.Unlock()
+	monitor.NodeCertExpirySeconds.WithLabelValues(nodeName).Set(float64(cert.NotAfter.Unix()))
+}
+
+// Get returns the current certificate of the node.
+func (s *Store) Get(nodeName string) (Info, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	info, ok := s.items[nodeName]
+	return info, ok
+}
+
+// List returns the current certificates of all the nodes.
+func (s *Store) List() []Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	infos := make([]Info, 0, len(s.items))
+	for _, info := range s.items {
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Prune forgets the certificates which expired more than the grace period before now, which are
+// of the nodes that never reconnect, and returns the names of the nodes.
+func (s *Store) Prune(now time.Time, grace time.Duration) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pruned []string
+	for nodeName, info := range s.items {
+		if now.After(info.NotAfter.Add(grace)) {
+			delete(s.items, nodeName)
+			monitor.NodeCertExpirySeconds.DeleteLabelValues(nodeName)
+			pruned = append(pruned, nodeName)
+		}
+	}
+	return pruned
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinfo
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+func TestGetNodeCertInfo(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	DefaultStore = NewStore()
+	DefaultStore.now = func() time.Time { return now }
+	defer func() { DefaultStore = NewStore() }()
+
+	ws := new(restful.WebService)
+	ws.Route(ws.GET(constants.DefaultNodeCertInfoURL).To(GetNodeCertInfo))
+	container := restful.NewContainer()
+	container.Add(ws)
+	get := func(nodeName string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/nodes/"+nodeName+"/certinfo", nil)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	require.Equal(t, http.StatusNotFound, get("node0").Code)
+
+	notAfter := now.Add(24 * time.Hour)
+	cert := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Issuer:       pkix.Name{CommonName: "KubeEdge"},
+		NotAfter:     notAfter,
+	}
+	DefaultStore.Record("node0", cert)
+	resp := get("node0")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var info Info
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+	require.Equal(t, Info{
+		NodeName: "node0",
+		Serial:   "42",
+		Issuer:   "CN=KubeEdge",
+		NotAfter: notAfter,
+		LastSeen: now,
+	}, info)
+	require.Equal(t, float64(notAfter.Unix()),
+		testutil.ToFloat64(monitor.NodeCertExpirySeconds.WithLabelValues("node0")))
+
+	// the certificate presented on reconnect replaces the previous one
+	now = now.Add(time.Hour)
+	cert.SerialNumber = big.NewInt(43)
+	cert.NotAfter = notAfter.Add(time.Hour)
+	DefaultStore.Record("node0", cert)
+	info, ok := DefaultStore.Get("node0")
+	require.True(t, ok)
+	require.Equal(t, "43", info.Serial)
+	require.Equal(t, now, info.LastSeen)
+	require.Equal(t, float64(cert.NotAfter.Unix()),
+		testutil.ToFloat64(monitor.NodeCertExpirySeconds.WithLabelValues("node0")))
+}
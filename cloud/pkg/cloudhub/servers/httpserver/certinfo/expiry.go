@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinfo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/nodes"
+)
+
+const (
+	// CertExpiryAnnotation is the annotation of the Node recording the expiration of the
+	// certificate that the edge node presented most recently, in RFC 3339.
+	CertExpiryAnnotation = "kubeedge.io/cert-expiry"
+	// ReasonCertExpiring is the reason of the Warning Event of the node whose certificate
+	// expires within the threshold.
+	ReasonCertExpiring = "CertificateExpiring"
+)
+
+// ExpiryScanner scans the certificates of the Store periodically, it annotates the nodes
+// with the expiration of their certificates and warns of the certificates expiring soon.
+type ExpiryScanner struct {
+	store     *Store
+	client    kubernetes.Interface
+	threshold time.Duration
+	grace     time.Duration
+	now       func() time.Time
+
+	// annotated is the expiration annotated on each node, the nodes are only patched when it changes
+	annotated map[string]string
+	// warned is the serial of the certificate of each node whose Warning Event is recorded,
+	// so that the event is recorded once per certificate
+	warned map[string]string
+}
+
+// NewExpiryScanner creates an ExpiryScanner of the store, which warns of the certificates
+// expiring within the threshold, and forgets the certificates of the nodes that never reconnect
+// after they have expired for the grace period.
+func NewExpiryScanner(store *Store, client kubernetes.Interface, threshold, grace time.Duration) *ExpiryScanner {
+	return &ExpiryScanner{
+		store:     store,
+		client:    client,
+		threshold: threshold,
+		grace:     grace,
+		now:       time.Now,
+		annotated: make(map[string]string),
+		warned:    make(map[string]string),
+	}
+}
+
+// StartExpiryScan scans the certificates of DefaultStore in the background every interval
+// of the config until the context is done.
+func StartExpiryScan(ctx context.Context, client kubernetes.Interface, c *v1alpha1.CloudHubCertExpiryScan) {
+	if c == nil || !c.Enable {
+		return
+	}
+	s := NewExpiryScanner(DefaultStore, client, time.Duration(c.Threshold)*24*time.Hour,
+		time.Duration(c.GracePeriod)*time.Hour)
+	go wait.UntilWithContext(ctx, func(ctx context.Context) { s.Scan(ctx) }, time.Duration(c.Interval)*time.Second)
+}
+
+// Scan prunes the stale certificates, annotates the nodes and records the Warning Events of
+// the certificates expiring within the threshold. It returns the number of the expiring certificates.
+func (s *ExpiryScanner) Scan(ctx context.Context) int {
+	now := s.now()
+	for _, nodeName := range s.store.Prune(now, s.grace) {
+		delete(s.annotated, nodeName)
+		delete(s.warned, nodeName)
+		klog.V(2).InfoS("Forgot the expired certificate of the node", "node", nodeName)
+	}
+
+	var expiring int
+	for _, info := range s.store.List() {
+		expiry := info.NotAfter.Format(time.RFC3339)
+		if s.annotated[info.NodeName] != expiry {
+			if err := s.annotate(ctx, info.NodeName, expiry); err != nil {
+				klog.Warningf("failed to annotate the certificate expiry of node %s, err: %v", info.NodeName, err)
+			} else {
+				s.annotated[info.NodeName] = expiry
+			}
+		}
+		if info.NotAfter.Sub(now) > s.threshold {
+			delete(s.warned, info.NodeName)
+			continue
+		}
+		expiring++
+		if s.warned[info.NodeName] != info.Serial {
+			s.recordEvent(ctx, info, now)
+			s.warned[info.NodeName] = info.Serial
+		}
+	}
+	monitor.NodesCertExpiringTotal.Set(float64(expiring))
+	return expiring
+}
+
+func (s *ExpiryScanner) annotate(ctx context.Context, nodeName, expiry string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{CertExpiryAnnotation: expiry},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.client.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		// the node is deleted, its certificate is forgotten after it expires
+		return nil
+	}
+	return err
+}
+
+func (s *ExpiryScanner) recordEvent(ctx context.Context, info Info, now time.Time) {
+	message := fmt.Sprintf("the certificate %s of node %s expires at %s", info.Serial, info.NodeName,
+		info.NotAfter.Format(time.RFC3339))
+	if !info.NotAfter.After(now) {
+		message = fmt.Sprintf("the certificate %s of node %s has expired at %s", info.Serial, info.NodeName,
+			info.NotAfter.Format(time.RFC3339))
+	}
+	nodes.RecordEvent(ctx, s.client, info.NodeName, corev1.EventTypeWarning, ReasonCertExpiring, message)
+}
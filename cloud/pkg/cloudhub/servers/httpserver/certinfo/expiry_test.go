@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinfo
+
+import (
+	"context"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+func TestExpiryScanner(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewStore()
+	store.now = func() time.Time { return now }
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "expiring"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "healthy"}},
+	)
+	s := NewExpiryScanner(store, client, 7*24*time.Hour, 24*time.Hour)
+	s.now = func() time.Time { return now }
+
+	record := func(nodeName string, serial int64, notAfter time.Time) {
+		store.Record(nodeName, &x509.Certificate{SerialNumber: big.NewInt(serial), NotAfter: notAfter})
+	}
+	annotation := func(nodeName string) string {
+		node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+		require.NoError(t, err)
+		return node.Annotations[CertExpiryAnnotation]
+	}
+	warnings := func() []string {
+		events, err := client.CoreV1().Events(metav1.NamespaceDefault).List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		var nodes []string
+		for _, e := range events.Items {
+			require.Equal(t, ReasonCertExpiring, e.Reason)
+			require.Equal(t, corev1.EventTypeWarning, e.Type)
+			nodes = append(nodes, e.InvolvedObject.Name)
+		}
+		return nodes
+	}
+
+	expiringAt := now.Add(3 * 24 * time.Hour)
+	record("expiring", 1, expiringAt)
+	record("healthy", 2, now.Add(30*24*time.Hour))
+	// the node is deleted, its certificate is still tracked until it expires
+	record("deleted", 3, now.Add(time.Hour))
+
+	require.Equal(t, 2, s.Scan(context.TODO()))
+	require.Equal(t, float64(2), testutil.ToFloat64(monitor.NodesCertExpiringTotal))
+	require.Equal(t, expiringAt.Format(time.RFC3339), annotation("expiring"))
+	require.Equal(t, now.Add(30*24*time.Hour).Format(time.RFC3339), annotation("healthy"))
+	require.ElementsMatch(t, []string{"expiring", "deleted"}, warnings())
+
+	// the event is recorded once per certificate
+	now = now.Add(time.Hour)
+	require.Equal(t, 2, s.Scan(context.TODO()))
+	require.Len(t, warnings(), 2)
+
+	// the node rotates its certificate
+	record("expiring", 4, now.Add(30*24*time.Hour))
+	require.Equal(t, 1, s.Scan(context.TODO()))
+	require.Equal(t, now.Add(30*24*time.Hour).Format(time.RFC3339), annotation("expiring"))
+	require.Len(t, warnings(), 2)
+
+	// the certificate of the node that never reconnects is forgotten after the grace period
+	now = now.Add(24*time.Hour + time.Second)
+	require.Equal(t, 0, s.Scan(context.TODO()))
+	require.Equal(t, float64(0), testutil.ToFloat64(monitor.NodesCertExpiringTotal))
+	_, ok := store.Get("deleted")
+	require.False(t, ok)
+	require.Len(t, store.List(), 2)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+)
+
+// GetNodeCertInfo returns the current certificate of the edge node of the path parameter
+// nodename, it responds 404 if the node has not presented a certificate since CloudCore started.
+func GetNodeCertInfo(request *restful.Request, response *restful.Response) {
+	nodeName := request.PathParameter("nodename")
+	info, ok := DefaultStore.Get(nodeName)
+	if !ok {
+		resps.ErrorMessage(response, http.StatusNotFound, fmt.Sprintf("no certificate is seen for node %s", nodeName))
+		return
+	}
+	bff, err := json.Marshal(info)
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	response.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	resps.OK(response, bff)
+}
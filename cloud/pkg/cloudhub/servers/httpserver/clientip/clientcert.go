@@ -0,0 +1,350 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientip
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+)
+
+// HeaderXForwardedClientCert is the header of the client certificate forwarded by a proxy,
+// in the format of Envoy, or the URL encoded PEM of the certificate like nginx.
+const HeaderXForwardedClientCert = "X-Forwarded-Client-Cert"
+
+// CertFormat is the format of the X-Forwarded-Client-Cert header.
+type CertFormat string
+
+const (
+	// CertFormatAuto detects the format of the header, which is one of the others.
+	CertFormatAuto CertFormat = "auto"
+	// CertFormatXFCC is the key/value format of Envoy, where the Cert element is the URL encoded PEM.
+	CertFormatXFCC CertFormat = "xfcc"
+	// CertFormatCaddy is the certificate encoded in base64 DER or base64 PEM like Caddy,
+	// or the URL encoded PEM like nginx.
+	CertFormatCaddy CertFormat = "caddy"
+)
+
+// ForwardedCertStatus is how FilterCert handled the X-Forwarded-Client-Cert header of a request.
+type ForwardedCertStatus string
+
+const (
+	// ForwardedCertNone means no certificate is forwarded.
+	ForwardedCertNone ForwardedCertStatus = "none"
+	// ForwardedCertStripped means the header is stripped, as the forwarded certificates are not
+	// accepted or the request does not come from a trusted proxy.
+	ForwardedCertStripped ForwardedCertStatus = "stripped"
+	// ForwardedCertIgnored means the TLS peer certificate is used instead of the forwarded one.
+	ForwardedCertIgnored ForwardedCertStatus = "ignored"
+	// ForwardedCertApplied means the forwarded certificates are applied as the peer certificates.
+	ForwardedCertApplied ForwardedCertStatus = "applied"
+	// ForwardedCertRejected means the forwarded certificates fail the verification and are dropped.
+	ForwardedCertRejected ForwardedCertStatus = "rejected"
+)
+
+type forwardedCertKey struct{}
+
+// ForwardedCertFromRequest returns how FilterCert handled the forwarded client certificate of the request.
+func ForwardedCertFromRequest(r *http.Request) ForwardedCertStatus {
+	if status, ok := r.Context().Value(forwardedCertKey{}).(ForwardedCertStatus); ok {
+		return status
+	}
+	return ForwardedCertNone
+}
+
+// processWithStatus stores the status of the forwarded client certificate into the request
+// context, and processes the rest of the chain.
+func processWithStatus(req *restful.Request, resp *restful.Response, chain *restful.FilterChain, status ForwardedCertStatus) {
+	req.Request = req.Request.WithContext(context.WithValue(req.Request.Context(), forwardedCertKey{}, status))
+	chain.ProcessFilter(req, resp)
+}
+
+// CertVerifier verifies the forwarded client certificates, the certificate of the client comes
+// first and the others are the intermediates. It returns the verified chains.
+type CertVerifier func(certs []*x509.Certificate) ([][]*x509.Certificate, error)
+
+// CertFilter applies the client certificates forwarded by the trusted proxies to the requests,
+// so that they are verified in the same way as the TLS peer certificates.
+type CertFilter struct {
+	trusted         TrustedProxies
+	header          string
+	preferForwarded bool
+	format          CertFormat
+	verify          CertVerifier
+}
+
+// NewCertFilter returns a CertFilter of the trusted proxies. header is the header forwarding the
+// certificates, which is X-Forwarded-Client-Cert if it is empty. preferForwarded decides the certificate
+// used when a request presents both a TLS peer certificate and a different forwarded one, format
+// is the format of the header forwarded by the proxies, and verify verifies the forwarded certificates
+// before they are applied. The forwarded certificates are applied without verification if verify
+// is nil, which is only for debugging.
+func NewCertFilter(trusted TrustedProxies, header string, preferForwarded bool, format CertFormat, verify CertVerifier) *CertFilter {
+	if header == "" {
+		header = HeaderXForwardedClientCert
+	}
+	return &CertFilter{trusted: trusted, header: header, preferForwarded: preferForwarded, format: format, verify: verify}
+}
+
+// headerName returns the header forwarding the certificates, the CertFilter may be nil.
+func (f *CertFilter) headerName() string {
+	if f == nil {
+		return HeaderXForwardedClientCert
+	}
+	return f.header
+}
+
+// FilterCert replaces the TLS peer certificates of the request with the forwarded ones.
+// The forwarded certificates are stripped from the request if the CertFilter is nil or the
+// request does not come from a trusted proxy, so that the handlers never see a forged header.
+// A request presenting a TLS peer certificate keeps it and the forwarded header is ignored
+// without being parsed, unless the forwarded one is preferred.
+// The forwarded certificates are verified before they replace the TLS peer certificates along
+// with the verified chains, the request goes on without any peer certificate if the verification
+// fails, e.g. the intermediates are missing or the client certificate does not come first.
+func (f *CertFilter) FilterCert(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	r := req.Request
+	header := f.headerName()
+	value := r.Header.Get(header)
+	if value == "" {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	if f == nil || !f.trusted.IsTrustedRequest(r) {
+		if f != nil {
+			klog.Warningf("strip the forwarded client certificate of the request from the untrusted peer %s", r.RemoteAddr)
+		}
+		r.Header.Del(header)
+		processWithStatus(req, resp, chain, ForwardedCertStripped)
+		return
+	}
+
+	var state tls.ConnectionState
+	if r.TLS != nil {
+		state = *r.TLS
+	}
+	direct := state.PeerCertificates
+	if len(direct) > 0 && !f.preferForwarded {
+		r.Header.Del(header)
+		processWithStatus(req, resp, chain, ForwardedCertIgnored)
+		return
+	}
+	forwarded, err := ParseForwardedClientCert(value, f.format)
+	if err != nil {
+		resps.ErrorMessage(resp, http.StatusBadRequest,
+			fmt.Sprintf("invalid %s header, err: %v", header, err))
+		return
+	}
+	if len(direct) > 0 {
+		if direct[0].Equal(forwarded[0]) {
+			processWithStatus(req, resp, chain, ForwardedCertApplied)
+			return
+		}
+		klog.Warningf("the direct client certificate %q and the forwarded client certificate %q of the request "+
+			"from %s disagree, the forwarded one is used", direct[0].Subject, forwarded[0].Subject, r.RemoteAddr)
+	}
+	state.PeerCertificates = forwarded
+	// the verified chains belong to the direct certificate
+	state.VerifiedChains = nil
+	status := ForwardedCertApplied
+	if f.verify != nil {
+		if chains, err := f.verifyForwarded(forwarded); err != nil {
+			klog.V(2).Infof("drop the forwarded client certificate %q of the request from %s, err: %v",
+				forwarded[0].Subject, r.RemoteAddr, err)
+			state.PeerCertificates = nil
+			status = ForwardedCertRejected
+		} else {
+			state.VerifiedChains = chains
+		}
+	}
+	r.TLS = &state
+	processWithStatus(req, resp, chain, status)
+}
+
+// verifyForwarded verifies the forwarded certificates, the first of which must be the certificate
+// of the client, so that an intermediate CA forwarded out of order is never taken as the client.
+func (f *CertFilter) verifyForwarded(forwarded []*x509.Certificate) ([][]*x509.Certificate, error) {
+	if forwarded[0].IsCA {
+		return nil, fmt.Errorf("the first forwarded certificate %q is a CA certificate", forwarded[0].Subject)
+	}
+	return f.verify(forwarded)
+}
+
+// ParseForwardedClientCert parses the certificates of the X-Forwarded-Client-Cert header in the
+// format, the certificate of the client comes first. The format is detected if it is empty or
+// CertFormatAuto. If there are multiple elements in the Envoy format, the first one is used,
+// which is added by the proxy facing the client.
+func ParseForwardedClientCert(value string, format CertFormat) ([]*x509.Certificate, error) {
+	value = strings.TrimSpace(value)
+	switch format {
+	case "", CertFormatAuto:
+		if isCaddyCert(value) {
+			return parseCaddyCert(value)
+		}
+		return parseXFCC(value)
+	case CertFormatXFCC:
+		return parseXFCC(value)
+	case CertFormatCaddy:
+		return parseCaddyCert(value)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// isCaddyCert reports whether the value is a PEM or a base64 encoded certificate, the Envoy
+// format is never valid base64 as it contains the separators and the quotes.
+func isCaddyCert(value string) bool {
+	if strings.HasPrefix(value, "-----BEGIN") {
+		return true
+	}
+	_, err := base64.StdEncoding.DecodeString(value)
+	return err == nil
+}
+
+// parseCaddyCert parses the certificates encoded in base64 DER, base64 PEM or URL encoded PEM.
+func parseCaddyCert(value string) ([]*x509.Certificate, error) {
+	if strings.HasPrefix(value, "-----BEGIN") {
+		pemData, err := url.PathUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		return parseCertsPEM(nil, pemData)
+	}
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 certificate, err: %v", err)
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN")) {
+		return parseCertsPEM(nil, string(data))
+	}
+	certs, err := x509.ParseCertificates(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate is forwarded")
+	}
+	return certs, nil
+}
+
+// parseXFCC parses the certificates of the first element in the Envoy format.
+func parseXFCC(value string) ([]*x509.Certificate, error) {
+	element, _ := splitQuoted(value, ',')
+	var certPEM, chainPEM string
+	for rest := element; rest != ""; {
+		var pair string
+		pair, rest = splitQuoted(rest, ';')
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid element %q", pair)
+		}
+		if unquoted, err := strconv.Unquote(val); err == nil {
+			val = unquoted
+		}
+		switch strings.ToLower(key) {
+		case "cert":
+			certPEM = val
+		case "chain":
+			chainPEM = val
+		}
+	}
+	if certPEM == "" {
+		return nil, errors.New("no certificate is forwarded")
+	}
+	var certs []*x509.Certificate
+	for _, encoded := range []string{certPEM, chainPEM} {
+		pemData, err := url.PathUnescape(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if certs, err = parseCertsPEM(certs, pemData); err != nil {
+			return nil, err
+		}
+	}
+	return certs, nil
+}
+
+// parseCertsPEM appends the certificates in the PEM data to the certs, the certificates
+// already in the certs are skipped, e.g. the Envoy chain includes the client certificate.
+func parseCertsPEM(certs []*x509.Certificate, pemData string) ([]*x509.Certificate, error) {
+	rest := []byte(pemData)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if !containsCert(certs, cert) {
+			certs = append(certs, cert)
+		}
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, errors.New("invalid PEM data")
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate is forwarded")
+	}
+	return certs, nil
+}
+
+func containsCert(certs []*x509.Certificate, cert *x509.Certificate) bool {
+	for _, c := range certs {
+		if c.Equal(cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitQuoted splits s at the first sep which is not quoted.
+func splitQuoted(s string, sep byte) (string, string) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				return s[:i], s[i+1:]
+			}
+		}
+	}
+	return s, ""
+}
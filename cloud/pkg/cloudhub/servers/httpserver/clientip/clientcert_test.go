@@ -0,0 +1,594 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientip
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// newNodeCerts returns a CA and the certificates of the nodes signed by it.
+func newNodeCerts(t *testing.T, nodeNames ...string) (*x509.Certificate, []*x509.Certificate) {
+	cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cahandler.GenPrivateKey()
+	require.NoError(t, err)
+	caPem, err := cahandler.NewSelfSigned(caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caPem.Bytes)
+	require.NoError(t, err)
+
+	certshandler := certs.GetHandler(certs.HandlerTypeX509)
+	var nodeCerts []*x509.Certificate
+	for _, nodeName := range nodeNames {
+		pk, err := certshandler.GenPrivateKey()
+		require.NoError(t, err)
+		csr, err := certshandler.CreateCSR(pkix.Name{
+			Organization: []string{"system:nodes"},
+			CommonName:   "system:node:" + nodeName,
+		}, pk, nil)
+		require.NoError(t, err)
+		certBlock, err := certshandler.SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(csr.Bytes,
+			caPem.Bytes, caKey.DER(), []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour))
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(certBlock.Bytes)
+		require.NoError(t, err)
+		nodeCerts = append(nodeCerts, cert)
+	}
+	return ca, nodeCerts
+}
+
+func escapedPEM(certs ...*x509.Certificate) string {
+	var b strings.Builder
+	for _, cert := range certs {
+		b.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+	return url.PathEscape(b.String())
+}
+
+func TestParseForwardedClientCert(t *testing.T) {
+	ca, nodeCerts := newNodeCerts(t, "node1", "node2")
+	node1, node2 := nodeCerts[0], nodeCerts[1]
+
+	cases := []struct {
+		name          string
+		value         string
+		format        CertFormat
+		want          []*x509.Certificate
+		containsError string
+	}{
+		{
+			name:  "nginx escaped PEM",
+			value: escapedPEM(node1),
+			want:  []*x509.Certificate{node1},
+		},
+		{
+			name: "Envoy with the chain including the client certificate",
+			value: `By=spiffe://cluster.local/ns/kubeedge/sa/cloudcore;Hash=abc;Subject="CN=system:node:node1,O=system:nodes";` +
+				`Cert="` + escapedPEM(node1) + `";Chain="` + escapedPEM(node1, ca) + `"`,
+			want: []*x509.Certificate{node1, ca},
+		},
+		{
+			name:  "Envoy with multiple elements",
+			value: `Cert="` + escapedPEM(node1) + `",Cert="` + escapedPEM(node2) + `"`,
+			want:  []*x509.Certificate{node1},
+		},
+		{
+			name:          "Envoy without certificate",
+			value:         `By=spiffe://cluster.local/ns/kubeedge/sa/cloudcore;Hash=abc`,
+			containsError: "no certificate is forwarded",
+		},
+		{
+			name:          "invalid PEM",
+			value:         url.PathEscape("-----BEGIN CERTIFICATE-----\ninvalid\n-----END CERTIFICATE-----\n"),
+			containsError: "invalid PEM data",
+		},
+		{
+			name:          "junk after the certificates",
+			value:         escapedPEM(node1, ca) + url.PathEscape("junk"),
+			containsError: "invalid PEM data",
+		},
+		{
+			name:  "Caddy base64 DER",
+			value: base64.StdEncoding.EncodeToString(node1.Raw),
+			want:  []*x509.Certificate{node1},
+		},
+		{
+			name:   "Caddy base64 PEM",
+			value:  base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: node1.Raw})),
+			format: CertFormatCaddy,
+			want:   []*x509.Certificate{node1},
+		},
+		{
+			name:   "Envoy with explicit format",
+			value:  `Cert="` + escapedPEM(node1) + `"`,
+			format: CertFormatXFCC,
+			want:   []*x509.Certificate{node1},
+		},
+		{
+			name:          "base64 with Envoy format",
+			value:         base64.StdEncoding.EncodeToString([]byte{0, 1, 2}),
+			format:        CertFormatXFCC,
+			containsError: `invalid element "AAEC"`,
+		},
+		{
+			name:          "Envoy with Caddy format",
+			value:         `Cert="` + escapedPEM(node1) + `"`,
+			format:        CertFormatCaddy,
+			containsError: "invalid base64 certificate",
+		},
+		{
+			name:          "invalid DER",
+			value:         base64.StdEncoding.EncodeToString([]byte("invalid")),
+			containsError: "x509",
+		},
+		{
+			name:          "malformed Envoy element",
+			value:         `Cert="` + escapedPEM(node1) + `";Subject`,
+			containsError: "invalid element",
+		},
+		{
+			name:          "unknown format",
+			value:         escapedPEM(node1),
+			format:        "haproxy",
+			containsError: `unknown format "haproxy"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseForwardedClientCert(c.value, c.format)
+			if c.containsError != "" {
+				require.ErrorContains(t, err, c.containsError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestFilterCert(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+	_, nodeCerts := newNodeCerts(t, "direct", "forwarded")
+	direct, forwarded := nodeCerts[0], nodeCerts[1]
+
+	cases := []struct {
+		name            string
+		filter          *CertFilter
+		remoteAddr      string
+		direct          *x509.Certificate
+		forwardedHeader string
+		wantCode        int
+		want            *x509.Certificate
+		// wantHeader indicates whether the forwarded header reaches the handler
+		wantHeader bool
+		wantStatus ForwardedCertStatus
+	}{
+		{
+			name:            "prefer direct with conflicting certificates",
+			filter:          NewCertFilter(trusted, "", false, CertFormatAuto, nil),
+			remoteAddr:      "10.0.0.1:34567",
+			direct:          direct,
+			forwardedHeader: escapedPEM(forwarded),
+			wantCode:        http.StatusOK,
+			want:            direct,
+			wantStatus:      ForwardedCertIgnored,
+		},
+		{
+			name:            "prefer forwarded with conflicting certificates",
+			filter:          NewCertFilter(trusted, "", true, CertFormatAuto, nil),
+			remoteAddr:      "10.0.0.1:34567",
+			direct:          direct,
+			forwardedHeader: escapedPEM(forwarded),
+			wantCode:        http.StatusOK,
+			want:            forwarded,
+			wantHeader:      true,
+			wantStatus:      ForwardedCertApplied,
+		},
+		{
+			name:            "trusted gateway without direct certificate",
+			filter:          NewCertFilter(trusted, "", false, CertFormatAuto, nil),
+			remoteAddr:      "10.0.0.1:34567",
+			forwardedHeader: escapedPEM(forwarded),
+			wantCode:        http.StatusOK,
+			want:            forwarded,
+			wantHeader:      true,
+			wantStatus:      ForwardedCertApplied,
+		},
+		{
+			name:            "prefer direct with the same certificate",
+			filter:          NewCertFilter(trusted, "", false, CertFormatAuto, nil),
+			remoteAddr:      "10.0.0.1:34567",
+			direct:          forwarded,
+			forwardedHeader: escapedPEM(forwarded),
+			wantCode:        http.StatusOK,
+			want:            forwarded,
+			wantStatus:      ForwardedCertIgnored,
+		},
+		{
+			name:            "prefer direct ignores invalid header",
+			filter:          NewCertFilter(trusted, "", false, CertFormatAuto, nil),
+			remoteAddr:      "10.0.0.1:34567",
+			direct:          direct,
+			forwardedHeader: "By=spiffe://cluster.local",
+			wantCode:        http.StatusOK,
+			want:            direct,
+			wantStatus:      ForwardedCertIgnored,
+		},
+		{
+			name:            "spoofed header from untrusted peer",
+			filter:          NewCertFilter(trusted, "", false, CertFormatAuto, nil),
+			remoteAddr:      "1.2.3.4:34567",
+			forwardedHeader: escapedPEM(forwarded),
+			wantCode:        http.StatusOK,
+			wantStatus:      ForwardedCertStripped,
+		},
+		{
+			name:            "untrusted peer",
+			filter:          NewCertFilter(trusted, "", true, CertFormatAuto, nil),
+			remoteAddr:      "1.2.3.4:34567",
+			direct:          direct,
+			forwardedHeader: escapedPEM(forwarded),
+			wantCode:        http.StatusOK,
+			want:            direct,
+			wantStatus:      ForwardedCertStripped,
+		},
+		{
+			name:            "disabled",
+			remoteAddr:      "10.0.0.1:34567",
+			forwardedHeader: escapedPEM(forwarded),
+			wantCode:        http.StatusOK,
+			wantStatus:      ForwardedCertStripped,
+		},
+		{
+			name:            "invalid header",
+			filter:          NewCertFilter(trusted, "", true, CertFormatAuto, nil),
+			remoteAddr:      "10.0.0.1:34567",
+			direct:          direct,
+			forwardedHeader: "By=spiffe://cluster.local",
+			wantCode:        http.StatusBadRequest,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got []*x509.Certificate
+			var gotHeader string
+			var gotStatus ForwardedCertStatus
+			ws := new(restful.WebService)
+			ws.Path("/")
+			ws.Filter(c.filter.FilterCert)
+			ws.Route(ws.GET("/edge.crt").To(func(req *restful.Request, _ *restful.Response) {
+				got = req.Request.TLS.PeerCertificates
+				gotHeader = req.Request.Header.Get(HeaderXForwardedClientCert)
+				gotStatus = ForwardedCertFromRequest(req.Request)
+			}))
+			container := restful.NewContainer()
+			container.Add(ws)
+
+			req := httptest.NewRequest(http.MethodGet, "/edge.crt", nil)
+			req.RemoteAddr = c.remoteAddr
+			req.TLS = &tls.ConnectionState{}
+			if c.direct != nil {
+				req.TLS.PeerCertificates = []*x509.Certificate{c.direct}
+			}
+			req.Header.Set(HeaderXForwardedClientCert, c.forwardedHeader)
+			recorder := httptest.NewRecorder()
+			container.ServeHTTP(recorder, req)
+			require.Equal(t, c.wantCode, recorder.Code, recorder.Body.String())
+			require.Equal(t, c.wantHeader, gotHeader != "")
+			require.Equal(t, c.wantStatus, gotStatus)
+			if c.want == nil {
+				require.Empty(t, got)
+				return
+			}
+			require.Equal(t, []*x509.Certificate{c.want}, got)
+		})
+	}
+}
+
+// newClientCert creates a client certificate of the node signed by the CA, the CA is self-signed
+// if it is nil. It returns the certificate and its key.
+func newClientCert(t *testing.T, name string, ca *x509.Certificate, caKey crypto.Signer,
+	notAfter time.Time) (*x509.Certificate, crypto.Signer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notAfter.Add(-2 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if ca == nil {
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+		tmpl.BasicConstraintsValid, tmpl.IsCA = true, true
+		ca, caKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, key.Public(), caKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestFilterCertCustomHeader(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+	_, nodeCerts := newNodeCerts(t, "forwarded")
+	forwarded := nodeCerts[0]
+	filter := NewCertFilter(trusted, "SSL-Client-Cert", false, CertFormatAuto, nil)
+
+	cases := []struct {
+		name       string
+		header     string
+		want       *x509.Certificate
+		wantStatus ForwardedCertStatus
+	}{
+		{
+			name:       "custom header",
+			header:     "SSL-Client-Cert",
+			want:       forwarded,
+			wantStatus: ForwardedCertApplied,
+		},
+		{
+			name:       "default header is not honored",
+			header:     HeaderXForwardedClientCert,
+			wantStatus: ForwardedCertNone,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var state *tls.ConnectionState
+			var status ForwardedCertStatus
+			handler := func(req *restful.Request, _ *restful.Response) {
+				state = req.Request.TLS
+				status = ForwardedCertFromRequest(req.Request)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/edge.crt", nil)
+			req.RemoteAddr = "10.0.0.1:34567"
+			req.TLS = &tls.ConnectionState{}
+			req.Header.Set(c.header, escapedPEM(forwarded))
+			chain := &restful.FilterChain{Target: handler}
+			filter.FilterCert(restful.NewRequest(req), restful.NewResponse(httptest.NewRecorder()), chain)
+			require.NotNil(t, state)
+			require.Equal(t, c.wantStatus, status)
+			if c.want == nil {
+				require.Empty(t, state.PeerCertificates)
+				return
+			}
+			require.Len(t, state.PeerCertificates, 1)
+			require.True(t, c.want.Equal(state.PeerCertificates[0]))
+		})
+	}
+}
+
+func TestFilterCertVerify(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+	ca, caKey := newClientCert(t, "ca", nil, nil, time.Now().Add(time.Hour))
+	unknownCA, unknownCAKey := newClientCert(t, "unknown", nil, nil, time.Now().Add(time.Hour))
+	valid, _ := newClientCert(t, "system:node:valid", ca, caKey, time.Now().Add(time.Hour))
+	expired, _ := newClientCert(t, "system:node:expired", ca, caKey, time.Now().Add(-time.Hour))
+	unknown, _ := newClientCert(t, "system:node:unknown", unknownCA, unknownCAKey, time.Now().Add(time.Hour))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	verify := func(certs []*x509.Certificate) ([][]*x509.Certificate, error) {
+		return certs[0].Verify(x509.VerifyOptions{
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+	}
+
+	cases := []struct {
+		name       string
+		verify     CertVerifier
+		forwarded  *x509.Certificate
+		want       *x509.Certificate
+		wantStatus ForwardedCertStatus
+	}{
+		{
+			name:       "valid",
+			verify:     verify,
+			forwarded:  valid,
+			want:       valid,
+			wantStatus: ForwardedCertApplied,
+		},
+		{
+			name:       "expired",
+			verify:     verify,
+			forwarded:  expired,
+			wantStatus: ForwardedCertRejected,
+		},
+		{
+			name:       "unknown CA",
+			verify:     verify,
+			forwarded:  unknown,
+			wantStatus: ForwardedCertRejected,
+		},
+		{
+			name:       "unknown CA without verification",
+			forwarded:  unknown,
+			want:       unknown,
+			wantStatus: ForwardedCertApplied,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var state *tls.ConnectionState
+			var status ForwardedCertStatus
+			handler := func(req *restful.Request, _ *restful.Response) {
+				state = req.Request.TLS
+				status = ForwardedCertFromRequest(req.Request)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/edge.crt", nil)
+			req.RemoteAddr = "10.0.0.1:34567"
+			req.TLS = &tls.ConnectionState{}
+			req.Header.Set(HeaderXForwardedClientCert, escapedPEM(c.forwarded))
+			recorder := httptest.NewRecorder()
+			chain := &restful.FilterChain{Target: handler}
+			NewCertFilter(trusted, "", false, CertFormatAuto, c.verify).FilterCert(
+				restful.NewRequest(req), restful.NewResponse(recorder), chain)
+			require.NotNil(t, state)
+			require.Equal(t, c.wantStatus, status)
+			if c.want == nil {
+				require.Empty(t, state.PeerCertificates)
+				require.Empty(t, state.VerifiedChains)
+				return
+			}
+			require.Equal(t, []*x509.Certificate{c.want}, state.PeerCertificates)
+			if c.verify != nil {
+				require.Equal(t, [][]*x509.Certificate{{valid, ca}}, state.VerifiedChains)
+			} else {
+				require.Empty(t, state.VerifiedChains)
+			}
+		})
+	}
+}
+
+// newIntermediateCA returns an intermediate CA signed by the CA, which may sign client certificates too.
+func newIntermediateCA(t *testing.T, ca *x509.Certificate, caKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "intermediate"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, ca, key.Public(), caKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestFilterCertChain(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+	root, rootKey := newClientCert(t, "root", nil, nil, time.Now().Add(time.Hour))
+	intermediate, intermediateKey := newIntermediateCA(t, root, rootKey)
+	leaf, _ := newClientCert(t, "system:node:node1", intermediate, intermediateKey, time.Now().Add(time.Hour))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	verify := func(certs []*x509.Certificate) ([][]*x509.Certificate, error) {
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		return certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+	}
+
+	cases := []struct {
+		name       string
+		header     string
+		wantCode   int
+		wantCerts  []*x509.Certificate
+		wantChains [][]*x509.Certificate
+		wantStatus ForwardedCertStatus
+	}{
+		{
+			name:       "leaf and intermediate",
+			header:     escapedPEM(leaf, intermediate),
+			wantCode:   http.StatusOK,
+			wantCerts:  []*x509.Certificate{leaf, intermediate},
+			wantChains: [][]*x509.Certificate{{leaf, intermediate, root}},
+			wantStatus: ForwardedCertApplied,
+		},
+		{
+			name:       "Envoy chain",
+			header:     `Cert="` + escapedPEM(leaf) + `";Chain="` + escapedPEM(leaf, intermediate) + `"`,
+			wantCode:   http.StatusOK,
+			wantCerts:  []*x509.Certificate{leaf, intermediate},
+			wantChains: [][]*x509.Certificate{{leaf, intermediate, root}},
+			wantStatus: ForwardedCertApplied,
+		},
+		{
+			name:       "out of order",
+			header:     escapedPEM(intermediate, leaf),
+			wantCode:   http.StatusOK,
+			wantStatus: ForwardedCertRejected,
+		},
+		{
+			name:       "missing intermediate",
+			header:     escapedPEM(leaf),
+			wantCode:   http.StatusOK,
+			wantStatus: ForwardedCertRejected,
+		},
+		{
+			name:     "junk trailing data",
+			header:   escapedPEM(leaf, intermediate) + url.PathEscape("\njunk"),
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var state *tls.ConnectionState
+			var status ForwardedCertStatus
+			handler := func(req *restful.Request, _ *restful.Response) {
+				state = req.Request.TLS
+				status = ForwardedCertFromRequest(req.Request)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/edge.crt", nil)
+			req.RemoteAddr = "10.0.0.1:34567"
+			req.TLS = &tls.ConnectionState{}
+			req.Header.Set(HeaderXForwardedClientCert, c.header)
+			recorder := httptest.NewRecorder()
+			chain := &restful.FilterChain{Target: handler}
+			NewCertFilter(trusted, "", false, CertFormatAuto, verify).FilterCert(
+				restful.NewRequest(req), restful.NewResponse(recorder), chain)
+			require.Equal(t, c.wantCode, recorder.Code)
+			if c.wantCode != http.StatusOK {
+				require.Nil(t, state)
+				return
+			}
+			require.NotNil(t, state)
+			require.Equal(t, c.wantStatus, status)
+			require.Equal(t, c.wantCerts, state.PeerCertificates)
+			require.Equal(t, c.wantChains, state.VerifiedChains)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+)
+
+const (
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderXRealIP       = "X-Real-IP"
+)
+
+type contextKey struct{}
+
+type unixSocketKey struct{}
+
+// TrustedProxies is a list of networks of the trusted proxies.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses the IPs or CIDRs of the trusted proxies.
+func ParseTrustedProxies(proxies []string) (TrustedProxies, error) {
+	trusted := make(TrustedProxies, 0, len(proxies))
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", p)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipnet, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, err: %v", p, err)
+		}
+		trusted = append(trusted, ipnet)
+	}
+	return trusted, nil
+}
+
+// Contains returns whether the ip belongs to a trusted proxy.
+func (t TrustedProxies) Contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipnet := range t {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTrustedRequest returns whether the immediate peer of the request is a trusted proxy.
+// The requests arriving via the unix domain socket are always trusted.
+func (t TrustedProxies) IsTrustedRequest(r *http.Request) bool {
+	return IsUnixSocketRequest(r) || t.Contains(parseIP(r.RemoteAddr))
+}
+
+// Resolve returns the effective client IP of the request. The forwarded headers are
+// only honored when the immediate peer is a trusted proxy, otherwise RemoteAddr is used.
+func (t TrustedProxies) Resolve(r *http.Request) string {
+	remote := parseIP(r.RemoteAddr)
+	if !IsUnixSocketRequest(r) {
+		if remote == nil {
+			return r.RemoteAddr
+		}
+		if !t.Contains(remote) {
+			return remote.String()
+		}
+	}
+
+	if xff := r.Header.Values(HeaderXForwardedFor); len(xff) > 0 {
+		var hops []net.IP
+		for _, v := range xff {
+			for _, hop := range strings.Split(v, ",") {
+				hops = append(hops, parseIP(hop))
+			}
+		}
+		// walk the hops from right to left, the first untrusted hop is the client
+		for i := len(hops) - 1; i >= 0; i-- {
+			if hops[i] == nil {
+				// stop at an invalid hop, the hops on the left can not be trusted
+				break
+			}
+			if !t.Contains(hops[i]) || i == 0 {
+				return hops[i].String()
+			}
+		}
+	}
+	if ip := parseIP(r.Header.Get(HeaderXRealIP)); ip != nil {
+		return ip.String()
+	}
+	if remote == nil {
+		return r.RemoteAddr
+	}
+	return remote.String()
+}
+
+// parseIP parses an IP address which may be with a port, like "[::1]:80" or "10.0.0.1:80".
+func parseIP(s string) net.IP {
+	s = strings.TrimSpace(s)
+	if ip := net.ParseIP(s); ip != nil {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		return net.ParseIP(host)
+	}
+	return net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"))
+}
+
+// NewFilter returns a filter that resolves the effective client IP and stores it into the
+// request context.
+func NewFilter(trusted TrustedProxies) restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		ip := trusted.Resolve(req.Request)
+		req.Request = req.Request.WithContext(WithClientIP(req.Request.Context(), ip))
+		chain.ProcessFilter(req, resp)
+	}
+}
+
+// WithUnixSocket returns a copy of ctx marking the request arriving via the unix domain socket,
+// which is only reachable by the processes on the same host.
+func WithUnixSocket(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unixSocketKey{}, true)
+}
+
+// IsUnixSocketRequest returns whether the request arrives via the unix domain socket.
+func IsUnixSocketRequest(r *http.Request) bool {
+	v, _ := r.Context().Value(unixSocketKey{}).(bool)
+	return v
+}
+
+// WithClientIP returns a copy of ctx with the client IP.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ip)
+}
+
+// FromRequest returns the client IP stored in the request context,
+// and falls back to the RemoteAddr of the request.
+func FromRequest(r *http.Request) string {
+	if ip, ok := r.Context().Value(contextKey{}).(string); ok && ip != "" {
+		return ip
+	}
+	if ip := parseIP(r.RemoteAddr); ip != nil {
+		return ip.String()
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1", "fd00::/8", "2001:db8::1"})
+	require.NoError(t, err)
+	require.Len(t, trusted, 4)
+
+	_, err = ParseTrustedProxies([]string{"invalid"})
+	require.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+}
+
+func TestResolve(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "fd00::/8"})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name       string
+		remoteAddr string
+		xff        []string
+		xRealIP    string
+		unixSocket bool
+		want       string
+	}{
+		{
+			name:       "no forwarded headers",
+			remoteAddr: "192.168.1.10:34567",
+			want:       "192.168.1.10",
+		},
+		{
+			name:       "spoofed headers from untrusted peer",
+			remoteAddr: "192.168.1.10:34567",
+			xff:        []string{"1.2.3.4"},
+			xRealIP:    "5.6.7.8",
+			want:       "192.168.1.10",
+		},
+		{
+			name:       "single hop from trusted proxy",
+			remoteAddr: "10.0.0.1:34567",
+			xff:        []string{"1.2.3.4"},
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "multi-hop list skips trusted proxies",
+			remoteAddr: "10.0.0.1:34567",
+			xff:        []string{"6.6.6.6, 1.2.3.4, 10.0.0.2"},
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "multi-hop list in multiple headers",
+			remoteAddr: "10.0.0.1:34567",
+			xff:        []string{"6.6.6.6", "1.2.3.4, 10.0.0.2"},
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "all hops trusted",
+			remoteAddr: "10.0.0.1:34567",
+			xff:        []string{"10.0.0.3, 10.0.0.2"},
+			want:       "10.0.0.3",
+		},
+		{
+			name:       "X-Real-IP from trusted proxy",
+			remoteAddr: "10.0.0.1:34567",
+			xRealIP:    "1.2.3.4",
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "IPv6 remote address with port",
+			remoteAddr: "[2001:db8::10]:34567",
+			xff:        []string{"1.2.3.4"},
+			want:       "2001:db8::10",
+		},
+		{
+			name:       "IPv6 trusted proxy and IPv6 hops with ports",
+			remoteAddr: "[fd00::1]:34567",
+			xff:        []string{"[2001:db8::20]:4711, [fd00::2]:80"},
+			want:       "2001:db8::20",
+		},
+		{
+			name:       "invalid hop stops the walk",
+			remoteAddr: "10.0.0.1:34567",
+			xff:        []string{"1.2.3.4, unknown"},
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "forwarded headers via unix socket",
+			xff:        []string{"1.2.3.4, 10.0.0.2"},
+			unixSocket: true,
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "no forwarded headers via unix socket",
+			unixSocket: true,
+			want:       "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/edge.crt", nil)
+			req.RemoteAddr = c.remoteAddr
+			for _, v := range c.xff {
+				req.Header.Add(HeaderXForwardedFor, v)
+			}
+			if c.xRealIP != "" {
+				req.Header.Set(HeaderXRealIP, c.xRealIP)
+			}
+			if c.unixSocket {
+				req = req.WithContext(WithUnixSocket(req.Context()))
+			}
+			require.Equal(t, c.want, trusted.Resolve(req))
+			require.Equal(t, c.unixSocket || trusted.Contains(parseIP(c.remoteAddr)), trusted.IsTrustedRequest(req))
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.1"})
+	require.NoError(t, err)
+
+	var got string
+	ws := new(restful.WebService)
+	ws.Path("/")
+	ws.Filter(NewFilter(trusted))
+	ws.Route(ws.GET("/edge.crt").To(func(req *restful.Request, _ *restful.Response) {
+		got = FromRequest(req.Request)
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	req := httptest.NewRequest(http.MethodGet, "/edge.crt", nil)
+	req.RemoteAddr = "10.0.0.1:34567"
+	req.Header.Set(HeaderXForwardedFor, "1.2.3.4")
+	container.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, "1.2.3.4", got)
+
+	// falls back to RemoteAddr without the filter
+	req = httptest.NewRequest(http.MethodGet, "/edge.crt", nil)
+	req.RemoteAddr = "[2001:db8::1]:34567"
+	require.Equal(t, "2001:db8::1", FromRequest(req))
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+)
+
+const (
+	defaultDrainTimeout = 30 * time.Second
+
+	// drainRetryAfterSeconds is the value of Retry-After header for requests arriving during drain.
+	drainRetryAfterSeconds = "10"
+)
+
+// drainer rejects the requests arriving after the server starts to shut down.
+type drainer struct {
+	draining atomic.Bool
+}
+
+func (d *drainer) filter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if d.draining.Load() {
+		resp.Header().Set("Retry-After", drainRetryAfterSeconds)
+		resp.Header().Set("Connection", "close")
+		resps.ErrorMessage(resp, http.StatusServiceUnavailable, "the server is shutting down, please retry later")
+		return
+	}
+	chain.ProcessFilter(req, resp)
+}
+
+// serve runs the server by serveFn until the ctx is done, and then shuts down the server
+// gracefully. The in-flight requests are waited for up to the drainTimeout.
+func serve(ctx context.Context, server *http.Server, d *drainer, drainTimeout time.Duration, serveFn func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- serveFn()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	klog.Infof("shutting down the https server, waiting up to %v for in-flight requests", drainTimeout)
+	d.draining.Store(true)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		klog.Warningf("failed to drain the https server in %v, err: %v", drainTimeout, err)
+		if err := server.Close(); err != nil {
+			klog.Errorf("failed to close the https server, err: %v", err)
+		}
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
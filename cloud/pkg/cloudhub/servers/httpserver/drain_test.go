@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+)
+
+func TestServeDrainsInFlightSigning(t *testing.T) {
+	certBytes := []byte("-----BEGIN CERTIFICATE-----\nfake\n-----END CERTIFICATE-----\n")
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	d := &drainer{}
+	ws := new(restful.WebService)
+	ws.Path("/")
+	ws.Filter(d.filter)
+	ws.Route(ws.GET("/edge.crt").To(func(_ *restful.Request, resp *restful.Response) {
+		// simulates a slow signing
+		close(started)
+		<-release
+		resps.OK(resp, certBytes)
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &http.Server{Handler: container}
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- serve(ctx, server, d, 10*time.Second, func() error {
+			return server.Serve(ln)
+		})
+	}()
+
+	type result struct {
+		code int
+		body []byte
+		err  error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/edge.crt")
+		if err != nil {
+			resCh <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		resCh <- result{code: resp.StatusCode, body: body, err: err}
+	}()
+	<-started
+
+	cancel()
+	require.Eventually(t, d.draining.Load, time.Second, time.Millisecond)
+	close(release)
+
+	res := <-resCh
+	require.NoError(t, res.err)
+	require.Equal(t, http.StatusOK, res.code)
+	require.Equal(t, certBytes, res.body)
+	require.NoError(t, <-serveErr)
+}
+
+func TestDrainerFilter(t *testing.T) {
+	d := &drainer{}
+	ws := new(restful.WebService)
+	ws.Path("/")
+	ws.Filter(d.filter)
+	ws.Route(ws.GET("/ca.crt").To(func(_ *restful.Request, resp *restful.Response) {
+		resps.OK(resp, []byte("ok"))
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ca.crt", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	d.draining.Store(true)
+	recorder = httptest.NewRecorder()
+	container.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ca.crt", nil))
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.Equal(t, drainRetryAfterSeconds, recorder.Header().Get("Retry-After"))
+}
+
+func TestRoutesDrainingProblem(t *testing.T) {
+	d := &drainer{}
+	d.draining.Store(true)
+	container := restful.NewContainer()
+	container.Add(routes(nil, d, nil, nil, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/ca.crt", nil)
+	req.Header.Set("Accept", resps.MIMEProblemJSON)
+	recorder := httptest.NewRecorder()
+	container.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.Equal(t, resps.MIMEProblemJSON, recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), `"status":503`)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/emicklei/go-restful"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+)
+
+// GetIssuanceLog exports the issuance log entries since the index of query parameter 'since',
+// together with the head signed by the CA key.
+func GetIssuanceLog(request *restful.Request, response *restful.Response) {
+	if defaultLog == nil {
+		resps.ErrorMessage(response, http.StatusNotFound, "the issuance log is not enabled")
+		return
+	}
+	var since uint64
+	if s := request.QueryParameter("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			resps.ErrorMessage(response, http.StatusBadRequest,
+				fmt.Sprintf("invalid query parameter since: %s", s))
+			return
+		}
+	}
+	caKey, err := hubconfig.Config.CASigner()
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	seg, err := defaultLog.Export(since, caKey)
+	if err != nil {
+		resps.Error(response, http.StatusBadRequest, err)
+		return
+	}
+	bff, err := json.Marshal(seg)
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	response.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	resps.OK(response, bff)
+}
+
+// ListNodeCertificates lists the records of the certificates issued for the edge node of the
+// path parameter nodename in the issuance log, the pruned records are not listed.
+func ListNodeCertificates(request *restful.Request, response *restful.Response) {
+	if defaultLog == nil {
+		resps.ErrorMessage(response, http.StatusNotFound, "the issuance log is not enabled")
+		return
+	}
+	writeJSON(response, defaultLog.NodeEntries(request.PathParameter("nodename")))
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancelog
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/preregistration"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+const (
+	ImportStatusImported = "imported"
+	ImportStatusExists   = "exists"
+	ImportStatusFailed   = "failed"
+)
+
+// ImportRecord is the record of a pre-issued certificate to import.
+type ImportRecord struct {
+	NodeName string `json:"nodeName"`
+	// Serial is the decimal serial number of the certificate.
+	Serial string `json:"serial"`
+	// Fingerprint is the hex encoded SHA-256 digest of the certificate DER.
+	Fingerprint string    `json:"fingerprint"`
+	NotAfter    time.Time `json:"notAfter"`
+	// PEM is the PEM encoded certificate, it is verified against the record
+	// and the CA if it is set.
+	PEM string `json:"pem,omitempty"`
+}
+
+// ImportRequest is the request body of importing certificates.
+type ImportRequest struct {
+	Records []ImportRecord `json:"records"`
+}
+
+// ImportResult is the result of importing a record.
+type ImportResult struct {
+	NodeName string `json:"nodeName"`
+	Serial   string `json:"serial"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ImportResponse is the response body of importing certificates.
+type ImportResponse struct {
+	Results []ImportResult `json:"results"`
+}
+
+// ImportCertificates imports the records of the certificates which are not signed by CloudHub,
+// so they are tracked in the issuance log like the locally issued ones. A record that fails
+// does not abort the others, and importing a record again is idempotent.
+func ImportCertificates(request *restful.Request, response *restful.Response) {
+	if defaultLog == nil {
+		resps.ErrorMessage(response, http.StatusNotFound, "the issuance log is not enabled")
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(response, request.Request.Body, constants.MaxRespBodyLength))
+	if err != nil {
+		resps.Error(response, http.StatusBadRequest, err)
+		return
+	}
+	var req ImportRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		resps.ErrorMessage(response, http.StatusBadRequest, fmt.Sprintf("invalid import request, err: %v", err))
+		return
+	}
+	if len(req.Records) == 0 {
+		resps.ErrorMessage(response, http.StatusBadRequest, "no records to import")
+		return
+	}
+
+	resp := ImportResponse{Results: make([]ImportResult, 0, len(req.Records))}
+	for _, record := range req.Records {
+		result := ImportResult{NodeName: record.NodeName, Serial: record.Serial}
+		imported, err := importRecord(defaultLog, record, hubconfig.Config.Ca)
+		switch {
+		case err != nil:
+			klog.Warningf("failed to import the certificate %s of edge node %s, err: %v",
+				record.Serial, record.NodeName, err)
+			result.Status, result.Error = ImportStatusFailed, err.Error()
+		case imported:
+			klog.InfoS("Imported an external certificate", "node", record.NodeName, "serial", record.Serial)
+			result.Status = ImportStatusImported
+		default:
+			result.Status = ImportStatusExists
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	bff, err := json.Marshal(resp)
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	response.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	resps.OK(response, bff)
+}
+
+// importRecord validates the record and imports it to the log, it returns false if the
+// record has been imported before.
+func importRecord(l *Log, record ImportRecord, caDER []byte) (bool, error) {
+	if record.NodeName == "" {
+		return false, errors.New("nodeName is required")
+	}
+	serial, ok := new(big.Int).SetString(record.Serial, 10)
+	if !ok {
+		return false, fmt.Errorf("invalid serial %q, it must be a decimal number", record.Serial)
+	}
+	fingerprint, err := preregistration.NormalizeFingerprint(record.Fingerprint)
+	if err != nil {
+		return false, err
+	}
+	if record.NotAfter.IsZero() {
+		return false, errors.New("notAfter is required")
+	}
+	if record.PEM != "" {
+		if err := verifyRecordCert(record, serial, fingerprint, caDER); err != nil {
+			return false, err
+		}
+	} else if time.Now().After(record.NotAfter) {
+		return false, errors.New("the certificate has expired")
+	}
+	_, imported, err := l.Import(record.NodeName, serial.String(), fingerprint, record.NotAfter)
+	return imported, err
+}
+
+// verifyRecordCert checks that the certificate matches the record and chains to the CA.
+func verifyRecordCert(record ImportRecord, serial *big.Int, fingerprint string, caDER []byte) error {
+	block, _ := pem.Decode([]byte(record.PEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return errors.New("failed to decode the PEM block of the certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse the certificate, err: %v", err)
+	}
+	digest := sha256.Sum256(block.Bytes)
+	if hex.EncodeToString(digest[:]) != fingerprint {
+		return errors.New("the fingerprint does not match the certificate")
+	}
+	if cert.SerialNumber.Cmp(serial) != 0 {
+		return errors.New("the serial does not match the certificate")
+	}
+	if !cert.NotAfter.Equal(record.NotAfter.Truncate(time.Second)) {
+		return errors.New("notAfter does not match the certificate")
+	}
+	if cn := certs.EdgeNodeCommonNamePrefix + record.NodeName; cert.Subject.CommonName != cn {
+		return fmt.Errorf("the CommonName %q of the certificate is not %q", cert.Subject.CommonName, cn)
+	}
+
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return fmt.Errorf("failed to parse the CA, err: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return fmt.Errorf("the certificate does not chain to the CA, err: %v", err)
+	}
+	return nil
+}
+
+// Revoke revokes the certificate with the serial number recorded in the issuance log,
+// no matter it is issued by CloudHub or imported.
+func Revoke(serial string) error {
+	if defaultLog == nil {
+		return errors.New("the issuance log is not enabled")
+	}
+	e, ok := defaultLog.Lookup(serial)
+	if !ok {
+		return fmt.Errorf("the certificate %s is not found in the issuance log", serial)
+	}
+	revocation.DefaultList.RevokeSerial(serial, expiration(e))
+	return nil
+}
+
+// expiration returns the expiration of the certificate of the entry. The expiration is not
+// recorded in the entries written before, those certificates can not live longer than the
+// max duration of edge certificates.
+func expiration(e certs.IssuanceLogEntry) time.Time {
+	if e.NotAfter != nil {
+		return *e.NotAfter
+	}
+	return e.Timestamp.Add(certs.MaxEdgeCertDuration)
+}
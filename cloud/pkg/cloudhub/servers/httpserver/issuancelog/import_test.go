@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancelog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func newImportRecord(t *testing.T, nodeName string, certDER []byte, withPEM bool) ImportRecord {
+	cert, err := x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+	digest := sha256.Sum256(certDER)
+	record := ImportRecord{
+		NodeName:    nodeName,
+		Serial:      cert.SerialNumber.String(),
+		Fingerprint: hex.EncodeToString(digest[:]),
+		NotAfter:    cert.NotAfter,
+	}
+	if withPEM {
+		record.PEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+	}
+	return record
+}
+
+func TestImportCertificates(t *testing.T) {
+	caDER, caKeyDER := newTestCA(t)
+	otherCADER, otherCAKeyDER := newTestCA(t)
+	// the other CA is installed last, install the CA again
+	hubconfig.Config.Ca = caDER
+	hubconfig.Config.CaKey = caKeyDER
+
+	post := func(body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/certs/import", bytes.NewReader(body))
+		recorder := httptest.NewRecorder()
+		ImportCertificates(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+	importRecords := func(records ...ImportRecord) []ImportResult {
+		body, err := json.Marshal(ImportRequest{Records: records})
+		require.NoError(t, err)
+		resp := post(body)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var result ImportResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		return result.Results
+	}
+
+	defaultLog = nil
+	require.Equal(t, http.StatusNotFound, post([]byte(`{}`)).Code)
+
+	l, err := NewLog(filepath.Join(t.TempDir(), "issuance.log"))
+	require.NoError(t, err)
+	defaultLog = l
+	defer func() { defaultLog = nil }()
+	require.Equal(t, http.StatusBadRequest, post([]byte(`invalid`)).Code)
+	require.Equal(t, http.StatusBadRequest, post([]byte(`{"records":[]}`)).Code)
+
+	withPEM := newImportRecord(t, "node1", newTestCert(t, caDER, caKeyDER, "node1"), true)
+	withoutPEM := newImportRecord(t, "node2", newTestCert(t, caDER, caKeyDER, "node2"), false)
+	untrusted := newImportRecord(t, "node3", newTestCert(t, otherCADER, otherCAKeyDER, "node3"), true)
+	wrongNode := newImportRecord(t, "node4", newTestCert(t, caDER, caKeyDER, "node1"), true)
+	wrongFingerprint := newImportRecord(t, "node5", newTestCert(t, caDER, caKeyDER, "node5"), true)
+	wrongFingerprint.Fingerprint = withPEM.Fingerprint
+	expired := withoutPEM
+	expired.Serial, expired.NotAfter = "12345", time.Now().Add(-time.Hour)
+
+	results := importRecords(withPEM, withoutPEM, untrusted, wrongNode, wrongFingerprint, expired)
+	require.Len(t, results, 6)
+	require.Equal(t, ImportStatusImported, results[0].Status)
+	require.Equal(t, ImportStatusImported, results[1].Status)
+	require.Contains(t, results[2].Error, "does not chain to the CA")
+	require.Contains(t, results[3].Error, `is not "system:node:node4"`)
+	require.Contains(t, results[4].Error, "the fingerprint does not match")
+	require.Contains(t, results[5].Error, "the certificate has expired")
+
+	// importing again is idempotent
+	results = importRecords(withPEM, withoutPEM)
+	require.Equal(t, ImportStatusExists, results[0].Status)
+	require.Equal(t, ImportStatusExists, results[1].Status)
+	conflict := withoutPEM
+	conflict.Fingerprint = withPEM.Fingerprint
+	results = importRecords(conflict)
+	require.Equal(t, ImportStatusFailed, results[0].Status)
+	require.Contains(t, results[0].Error, ErrConflict.Error())
+
+	seg, err := l.Export(0, testSigner(t, caKeyDER))
+	require.NoError(t, err)
+	require.Len(t, seg.Entries, 2)
+	require.True(t, seg.Entries[0].External)
+	require.Equal(t, withPEM.Fingerprint, seg.Entries[0].CertHash)
+	require.True(t, seg.Entries[1].NotAfter.Equal(withoutPEM.NotAfter))
+	require.NoError(t, certs.VerifyIssuanceLog(seg.PrevHash, seg.Entries, seg.Head, caDER))
+
+	// imported entries are loaded after restarting
+	require.NoError(t, l.flush())
+	reloaded, err := NewLog(l.file)
+	require.NoError(t, err)
+	e, ok := reloaded.Lookup(withoutPEM.Serial)
+	require.True(t, ok)
+	require.True(t, e.External)
+}
+
+func TestRevoke(t *testing.T) {
+	caDER, caKeyDER := newTestCA(t)
+	defaultLog = nil
+	require.Error(t, Revoke("1"))
+
+	l, err := NewLog(filepath.Join(t.TempDir(), "issuance.log"))
+	require.NoError(t, err)
+	defaultLog = l
+	defer func() { defaultLog = nil }()
+
+	localDER := newTestCert(t, caDER, caKeyDER, "node0")
+	Record("node0", "10.0.0.1", certs.IssuanceAuthToken, localDER)
+	record := newImportRecord(t, "node1", newTestCert(t, caDER, caKeyDER, "node1"), false)
+	_, err = importRecord(l, record, caDER)
+	require.NoError(t, err)
+
+	local, err := x509.ParseCertificate(localDER)
+	require.NoError(t, err)
+	require.NoError(t, Revoke(local.SerialNumber.String()))
+	require.True(t, revocation.DefaultList.IsRevoked(local))
+	require.NoError(t, Revoke(record.Serial))
+	require.ErrorContains(t, Revoke("1"), "not found")
+}
@@ -0,0 +1,368 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancelog
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// retryInterval is the interval to retry persisting entries after a failure.
+const retryInterval = 5 * time.Second
+
+// Log is an append-only log of the issued edge certificates. Entries are appended in memory
+// synchronously and persisted to the file asynchronously, failed writes are retried so every
+// entry is persisted at least once.
+type Log struct {
+	file string
+
+	mu      sync.RWMutex
+	entries []certs.IssuanceLogEntry
+	// bySerial indexes the entries by the serial number
+	bySerial map[string]int
+
+	pendingMu sync.Mutex
+	pending   []certs.IssuanceLogEntry
+	notify    chan struct{}
+
+	// fileMu serializes appending to the file and rewriting it
+	fileMu sync.Mutex
+}
+
+var defaultLog *Log
+
+// Init loads the issuance log from the file and starts persisting the new entries.
+func Init(ctx context.Context, file string) error {
+	l, err := NewLog(file)
+	if err != nil {
+		return err
+	}
+	go l.Run(ctx)
+	defaultLog = l
+	return nil
+}
+
+// Enabled returns whether the issuance log is initialized.
+func Enabled() bool {
+	return defaultLog != nil
+}
+
+// Record appends the issued certificate to the issuance log if it is enabled, the authentication
+// is how the request of the certificate was authenticated.
+func Record(nodeName, clientIP, authentication string, certDER []byte) {
+	if defaultLog == nil {
+		return
+	}
+	if err := defaultLog.Append(nodeName, clientIP, authentication, certDER); err != nil {
+		klog.Errorf("failed to record the certificate of edge node %s to the issuance log, err: %v", nodeName, err)
+	}
+}
+
+// ForEach calls fn with every entry of the issuance log in order if it is enabled,
+// the pruned entries are skipped.
+func ForEach(fn func(e certs.IssuanceLogEntry)) {
+	if defaultLog == nil {
+		return
+	}
+	defaultLog.mu.RLock()
+	entries := defaultLog.entries
+	defaultLog.mu.RUnlock()
+	for _, e := range entries {
+		if !e.Pruned {
+			fn(e)
+		}
+	}
+}
+
+// Lookup returns the entry of the certificate with the serial number in the issuance log,
+// ok is false if the issuance log is not enabled or the certificate is not recorded.
+func Lookup(serial string) (e certs.IssuanceLogEntry, ok bool) {
+	if defaultLog == nil {
+		return certs.IssuanceLogEntry{}, false
+	}
+	return defaultLog.Lookup(serial)
+}
+
+// NewLog creates a Log and loads the existing entries from the file.
+func NewLog(file string) (*Log, error) {
+	l := &Log{
+		file:     file,
+		bySerial: make(map[string]int),
+		notify:   make(chan struct{}, 1),
+	}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) load() error {
+	f, err := os.Open(l.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open the issuance log file %s, err: %v", l.file, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e certs.IssuanceLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// the last line may be partially written when cloudcore exits unexpectedly
+			klog.Warningf("skip the invalid line in the issuance log file %s, err: %v", l.file, err)
+			continue
+		}
+		// entries may be persisted more than once
+		if e.Index < uint64(len(l.entries)) {
+			continue
+		}
+		if e.Index != uint64(len(l.entries)) {
+			return fmt.Errorf("the issuance log file %s is not continuous at entry %d", l.file, e.Index)
+		}
+		if !e.Pruned {
+			l.bySerial[e.Serial] = len(l.entries)
+		}
+		l.entries = append(l.entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read the issuance log file %s, err: %v", l.file, err)
+	}
+	return nil
+}
+
+// Append appends an entry of the issued certificate to the log, the clientIP is the
+// effective IP of the client which requested the certificate.
+func (l *Log) Append(nodeName, clientIP, authentication string, certDER []byte) error {
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return fmt.Errorf("failed to parse the certificate, err: %v", err)
+	}
+
+	l.mu.Lock()
+	l.appendLocked(func(index uint64, prevHash string) certs.IssuanceLogEntry {
+		return certs.NewIssuedCertLogEntry(index, time.Now(), nodeName, clientIP, authentication, cert, prevHash)
+	})
+	l.mu.Unlock()
+	return nil
+}
+
+// ErrConflict indicates that a different certificate with the same serial number is in the log.
+var ErrConflict = errors.New("a different certificate with the same serial number is in the issuance log")
+
+// Import appends an entry of an externally issued certificate to the log. Importing the
+// same certificate again is a no-op, which returns the existing entry and false.
+func (l *Log) Import(nodeName, serial, certHash string, notAfter time.Time) (certs.IssuanceLogEntry, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if i, ok := l.bySerial[serial]; ok {
+		e := l.entries[i]
+		if e.CertHash != certHash || e.NodeName != nodeName {
+			return e, false, ErrConflict
+		}
+		return e, false, nil
+	}
+	e := l.appendLocked(func(index uint64, prevHash string) certs.IssuanceLogEntry {
+		return certs.NewImportedIssuanceLogEntry(index, time.Now(), nodeName, serial, certHash, notAfter, prevHash)
+	})
+	return e, true, nil
+}
+
+// Lookup returns the entry of the certificate with the serial number.
+func (l *Log) Lookup(serial string) (certs.IssuanceLogEntry, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	i, ok := l.bySerial[serial]
+	if !ok {
+		return certs.IssuanceLogEntry{}, false
+	}
+	return l.entries[i], true
+}
+
+// NodeEntries returns the entries of the certificates issued for the node in order,
+// the pruned entries are skipped.
+func (l *Log) NodeEntries(nodeName string) []certs.IssuanceLogEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	entries := []certs.IssuanceLogEntry{}
+	for _, e := range l.entries {
+		if !e.Pruned && e.NodeName == nodeName {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// appendLocked appends the entry created by newEntry and notifies it to be persisted,
+// l.mu must be held.
+func (l *Log) appendLocked(newEntry func(index uint64, prevHash string) certs.IssuanceLogEntry) certs.IssuanceLogEntry {
+	var prevHash string
+	if n := len(l.entries); n > 0 {
+		prevHash = l.entries[n-1].Hash
+	}
+	e := newEntry(uint64(len(l.entries)), prevHash)
+	l.bySerial[e.Serial] = len(l.entries)
+	l.entries = append(l.entries, e)
+	// keep the pending entries in order with the log
+	l.pendingMu.Lock()
+	l.pending = append(l.pending, e)
+	l.pendingMu.Unlock()
+
+	select {
+	case l.notify <- struct{}{}:
+	default:
+	}
+	return e
+}
+
+// Run persists the pending entries until the context is done.
+func (l *Log) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			if err := l.flush(); err != nil {
+				klog.Errorf("failed to persist the issuance log, err: %v", err)
+			}
+			return
+		case <-l.notify:
+		}
+		for {
+			err := l.flush()
+			if err == nil {
+				break
+			}
+			klog.Errorf("failed to persist the issuance log, will retry in %v, err: %v", retryInterval, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+		}
+	}
+}
+
+// flush writes the pending entries to the file, the entries are kept pending if it fails.
+func (l *Log) flush() error {
+	l.pendingMu.Lock()
+	pending := l.pending
+	l.pending = nil
+	l.pendingMu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := l.write(pending); err != nil {
+		l.pendingMu.Lock()
+		l.pending = append(pending, l.pending...)
+		l.pendingMu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (l *Log) write(entries []certs.IssuanceLogEntry) error {
+	l.fileMu.Lock()
+	defer l.fileMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.file), 0750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	return writeEntries(f, entries)
+}
+
+// writeEntries writes the entries to f line by line and closes it.
+func writeEntries(f *os.File, entries []certs.IssuanceLogEntry) error {
+	w := bufio.NewWriter(f)
+	for _, e := range entries {
+		bff, err := json.Marshal(e)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		bff = append(bff, '\n')
+		if _, err := w.Write(bff); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Segment is an exported segment of the issuance log.
+type Segment struct {
+	// PrevHash is the hash of the entry before the segment, it is empty if the
+	// segment starts from the first entry.
+	PrevHash string                   `json:"prevHash"`
+	Entries  []certs.IssuanceLogEntry `json:"entries"`
+	Head     *certs.IssuanceLogHead   `json:"head"`
+}
+
+// Export returns the entries since the index and the head signed by the signer of the CA key.
+func (l *Log) Export(since uint64, caKey crypto.Signer) (*Segment, error) {
+	l.mu.RLock()
+	size := uint64(len(l.entries))
+	if since > size {
+		l.mu.RUnlock()
+		return nil, fmt.Errorf("index %d is out of range, the size of issuance log is %d", since, size)
+	}
+	seg := &Segment{
+		Entries: append([]certs.IssuanceLogEntry{}, l.entries[since:]...),
+	}
+	if since > 0 {
+		seg.PrevHash = l.entries[since-1].Hash
+	}
+	var hash string
+	if size > 0 {
+		hash = l.entries[size-1].Hash
+	}
+	l.mu.RUnlock()
+
+	if caKey == nil {
+		return nil, errors.New("the CA key is not ready")
+	}
+	head, err := certs.SignIssuanceLogHead(size, hash, caKey)
+	if err != nil {
+		return nil, err
+	}
+	seg.Head = head
+	return seg, nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancelog
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// newTestCA installs a CA by testutil.NewCA as the CA of CloudHub, and returns the CA and its key in DER.
+func newTestCA(t *testing.T) (caDER, caKeyDER []byte) {
+	ca := testutil.NewCA(t)
+	return ca.Cert.Raw, ca.Key.DER()
+}
+
+func testSigner(t *testing.T, keyDER []byte) crypto.Signer {
+	signer, err := certs.PrivateKeySigner(keyDER)
+	require.NoError(t, err)
+	return signer
+}
+
+func newTestCert(t *testing.T, caDER, caKeyDER []byte, nodeName string) []byte {
+	h := certs.GetHandler(certs.HandlerTypeX509)
+	key, err := h.GenPrivateKey()
+	require.NoError(t, err)
+	csr, err := h.CreateCSR(pkix.Name{
+		Organization: []string{"system:nodes"},
+		CommonName:   "system:node:" + nodeName,
+	}, key, nil)
+	require.NoError(t, err)
+	cert, err := h.SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(csr.Bytes, caDER, caKeyDER,
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour))
+	require.NoError(t, err)
+	return cert.Bytes
+}
+
+func waitPersisted(t *testing.T, file string, lines int) {
+	require.Eventually(t, func() bool {
+		bff, err := os.ReadFile(file)
+		return err == nil && strings.Count(string(bff), "\n") >= lines
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLog(t *testing.T) {
+	caDER, caKeyDER := newTestCA(t)
+	file := filepath.Join(t.TempDir(), "issuance.log")
+
+	l, err := NewLog(file)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go l.Run(ctx)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, l.Append(fmt.Sprintf("node%d", i), "10.0.0.1", certs.IssuanceAuthToken, newTestCert(t, caDER, caKeyDER, fmt.Sprintf("node%d", i))))
+	}
+	require.Error(t, l.Append("node", "10.0.0.1", certs.IssuanceAuthToken, []byte("invalid")))
+	waitPersisted(t, file, 3)
+
+	seg, err := l.Export(0, testSigner(t, caKeyDER))
+	require.NoError(t, err)
+	require.Len(t, seg.Entries, 3)
+	require.Equal(t, uint64(3), seg.Head.Size)
+	require.NoError(t, certs.VerifyIssuanceLog(seg.PrevHash, seg.Entries, seg.Head, caDER))
+
+	seg, err = l.Export(2, testSigner(t, caKeyDER))
+	require.NoError(t, err)
+	require.Len(t, seg.Entries, 1)
+	require.Equal(t, "node2", seg.Entries[0].NodeName)
+	require.NoError(t, certs.VerifyIssuanceLog(seg.PrevHash, seg.Entries, seg.Head, caDER))
+
+	_, err = l.Export(4, testSigner(t, caKeyDER))
+	require.Error(t, err)
+
+	// persisted entries are loaded after restarting, duplicated entries are ignored
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	dup, err := json.Marshal(seg.Entries[0])
+	require.NoError(t, err)
+	_, err = f.Write(append(dup, '\n'))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reloaded, err := NewLog(file)
+	require.NoError(t, err)
+	reloadedSeg, err := reloaded.Export(0, testSigner(t, caKeyDER))
+	require.NoError(t, err)
+	require.Len(t, reloadedSeg.Entries, 3)
+	require.NoError(t, certs.VerifyIssuanceLog("", reloadedSeg.Entries, reloadedSeg.Head, caDER))
+}
+
+func TestLogTampered(t *testing.T) {
+	caDER, caKeyDER := newTestCA(t)
+	file := filepath.Join(t.TempDir(), "issuance.log")
+
+	l, err := NewLog(file)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, l.Append(fmt.Sprintf("node%d", i), "10.0.0.1", certs.IssuanceAuthToken, newTestCert(t, caDER, caKeyDER, fmt.Sprintf("node%d", i))))
+	}
+	require.NoError(t, l.flush())
+
+	bff, err := os.ReadFile(file)
+	require.NoError(t, err)
+	tampered := strings.Replace(string(bff), `"nodeName":"node1"`, `"nodeName":"evil"`, 1)
+	require.NotEqual(t, string(bff), tampered)
+	require.NoError(t, os.WriteFile(file, []byte(tampered), 0600))
+
+	reloaded, err := NewLog(file)
+	require.NoError(t, err)
+	seg, err := reloaded.Export(0, testSigner(t, caKeyDER))
+	require.NoError(t, err)
+	require.ErrorContains(t, certs.VerifyIssuanceLog(seg.PrevHash, seg.Entries, seg.Head, caDER),
+		"entry 1 has been tampered")
+}
+
+func TestGetIssuanceLog(t *testing.T) {
+	caDER, caKeyDER := newTestCA(t)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/issuance-log"+query, nil)
+		recorder := httptest.NewRecorder()
+		GetIssuanceLog(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	defaultLog = nil
+	require.Equal(t, http.StatusNotFound, get("").Code)
+
+	l, err := NewLog(filepath.Join(t.TempDir(), "issuance.log"))
+	require.NoError(t, err)
+	defaultLog = l
+	defer func() { defaultLog = nil }()
+	Record("node0", "10.0.0.1", certs.IssuanceAuthToken, newTestCert(t, caDER, caKeyDER, "node0"))
+	Record("node1", "2001:db8::1", certs.IssuanceAuthCertificate, newTestCert(t, caDER, caKeyDER, "node1"))
+
+	resp := get("?since=1")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var seg Segment
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &seg))
+	require.Len(t, seg.Entries, 1)
+	require.Equal(t, "2001:db8::1", seg.Entries[0].ClientIP)
+	require.NoError(t, certs.VerifyIssuanceLog(seg.PrevHash, seg.Entries, seg.Head, caDER))
+
+	require.Equal(t, http.StatusBadRequest, get("?since=abc").Code)
+	require.Equal(t, http.StatusBadRequest, get("?since=3").Code)
+}
+
+func TestListNodeCertificates(t *testing.T) {
+	caDER, caKeyDER := newTestCA(t)
+	ws := new(restful.WebService)
+	ws.Route(ws.GET(constants.DefaultIssuanceLogNodeURL).To(ListNodeCertificates))
+	container := restful.NewContainer()
+	container.Add(ws)
+	list := func(nodeName string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/issuance-log/nodes/"+nodeName, nil)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	defaultLog = nil
+	require.Equal(t, http.StatusNotFound, list("node0").Code)
+
+	l, err := NewLog(filepath.Join(t.TempDir(), "issuance.log"))
+	require.NoError(t, err)
+	defaultLog = l
+	defer func() { defaultLog = nil }()
+	node0Cert := newTestCert(t, caDER, caKeyDER, "node0")
+	Record("node0", "10.0.0.1", certs.IssuanceAuthToken, node0Cert)
+	Record("node1", "10.0.0.2", certs.IssuanceAuthToken, newTestCert(t, caDER, caKeyDER, "node1"))
+	Record("node0", "10.0.0.3", certs.IssuanceAuthCertificate, newTestCert(t, caDER, caKeyDER, "node0"))
+
+	resp := list("node0")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var entries []certs.IssuanceLogEntry
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &entries))
+	require.Len(t, entries, 2)
+	require.Equal(t, []string{certs.IssuanceAuthToken, certs.IssuanceAuthCertificate},
+		[]string{entries[0].Authentication, entries[1].Authentication})
+	require.Equal(t, "10.0.0.3", entries[1].ClientIP)
+
+	cert, err := x509.ParseCertificate(node0Cert)
+	require.NoError(t, err)
+	require.Equal(t, cert.SerialNumber.String(), entries[0].Serial)
+	require.NotNil(t, entries[0].NotBefore)
+	require.True(t, cert.NotBefore.Equal(*entries[0].NotBefore))
+	require.True(t, cert.NotAfter.Equal(*entries[0].NotAfter))
+	require.Equal(t, []string{"ClientAuth"}, entries[0].ExtKeyUsages)
+	require.NotEmpty(t, entries[0].KeyUsages)
+
+	resp = list("unknown")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.JSONEq(t, "[]", resp.Body.String())
+}
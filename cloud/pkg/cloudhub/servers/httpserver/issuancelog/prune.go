@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancelog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// PruneResponse is the response body of pruning the issuance log.
+type PruneResponse struct {
+	// Pruned is the number of the entries pruned.
+	Pruned int `json:"pruned"`
+}
+
+// Prune prunes the entries whose certificates expired more than the retention before now,
+// and the entries of a node older than its latest maxPerNode entries if maxPerNode is positive.
+// The entries of the revoked certificates are kept until they leave the revocation list.
+// Pruned entries only keep the Index and the Hash, so the log can still be verified, and
+// the file is rewritten with the pruned entries.
+func (l *Log) Prune(now time.Time, retention time.Duration, maxPerNode int, isRevoked func(serial string) bool) (int, error) {
+	l.fileMu.Lock()
+	defer l.fileMu.Unlock()
+
+	l.mu.Lock()
+	exceeded := l.exceededLocked(maxPerNode)
+	var entries []certs.IssuanceLogEntry
+	var pruned int
+	for i, e := range l.entries {
+		if e.Pruned || !certs.CanPruneIssuanceLogEntry(e) || (now.Before(expiration(e).Add(retention)) && !exceeded[i]) || isRevoked(e.Serial) {
+			continue
+		}
+		if entries == nil {
+			// copy on write, the entries may be read by ForEach without the lock
+			entries = append([]certs.IssuanceLogEntry{}, l.entries...)
+		}
+		entries[i] = certs.PruneIssuanceLogEntry(e)
+		delete(l.bySerial, e.Serial)
+		pruned++
+	}
+	if pruned == 0 {
+		l.mu.Unlock()
+		return 0, nil
+	}
+	l.entries = entries
+	// the pending entries are written by the rewrite, and they are skipped
+	// on loading if a running flush persists them again
+	l.pendingMu.Lock()
+	l.pending = nil
+	l.pendingMu.Unlock()
+	l.mu.Unlock()
+
+	if err := l.rewrite(entries); err != nil {
+		return pruned, fmt.Errorf("failed to rewrite the issuance log file %s, err: %v", l.file, err)
+	}
+	return pruned, nil
+}
+
+// exceededLocked returns the indexes of the entries of each node older than its latest
+// maxPerNode entries, l.mu must be held.
+func (l *Log) exceededLocked(maxPerNode int) map[int]bool {
+	if maxPerNode <= 0 {
+		return nil
+	}
+	exceeded := make(map[int]bool)
+	counts := make(map[string]int)
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		e := l.entries[i]
+		if e.Pruned {
+			continue
+		}
+		counts[e.NodeName]++
+		if counts[e.NodeName] > maxPerNode {
+			exceeded[i] = true
+		}
+	}
+	return exceeded
+}
+
+// rewrite replaces the file with the entries, l.fileMu must be held.
+func (l *Log) rewrite(entries []certs.IssuanceLogEntry) error {
+	tmp := l.file + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err := writeEntries(f, entries); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, l.file)
+}
+
+func pruneRetention() time.Duration {
+	if l := hubconfig.Config.IssuanceLog; l != nil {
+		return l.PruneRetention * 24 * time.Hour
+	}
+	return 0
+}
+
+func maxRecordsPerNode() int {
+	if l := hubconfig.Config.IssuanceLog; l != nil {
+		return int(l.MaxRecordsPerNode)
+	}
+	return 0
+}
+
+func prune() (int, error) {
+	return defaultLog.Prune(time.Now(), pruneRetention(), maxRecordsPerNode(), revocation.DefaultList.IsRevokedSerial)
+}
+
+// StartPruning prunes the issuance log in the background every interval until the context is done.
+func StartPruning(ctx context.Context, interval time.Duration) {
+	if defaultLog == nil || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			n, err := prune()
+			if err != nil {
+				klog.Errorf("failed to prune the issuance log, err: %v", err)
+				continue
+			}
+			if n > 0 {
+				klog.InfoS("Pruned the issuance log", "pruned", n)
+			}
+		}
+	}()
+}
+
+// PruneCertificates prunes the records of the expired certificates in the issuance log
+// on demand, the records are kept for the configured retention after the expiration.
+// The records of a node beyond the configured max records per node are pruned too.
+func PruneCertificates(_ *restful.Request, response *restful.Response) {
+	if defaultLog == nil {
+		resps.ErrorMessage(response, http.StatusNotFound, "the issuance log is not enabled")
+		return
+	}
+	n, err := prune()
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	klog.InfoS("Pruned the issuance log", "pruned", n)
+	bff, err := json.Marshal(PruneResponse{Pruned: n})
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	response.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	resps.OK(response, bff)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancelog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestLogPrune(t *testing.T) {
+	caDER, caKeyDER := newTestCA(t)
+	file := filepath.Join(t.TempDir(), "issuance.log")
+	l, err := NewLog(file)
+	require.NoError(t, err)
+
+	now := time.Now()
+	retention := 24 * time.Hour
+	seed := []struct {
+		node     string
+		serial   string
+		issuedAt time.Time
+		// notAfter is zero for the entries written before the expiration is recorded
+		notAfter time.Time
+	}{
+		{node: "valid", serial: "1", issuedAt: now.Add(-time.Hour), notAfter: now.Add(time.Hour)},
+		{node: "expired-recently", serial: "2", issuedAt: now.Add(-3 * time.Hour), notAfter: now.Add(-time.Hour)},
+		{node: "expired", serial: "3", issuedAt: now.Add(-72 * time.Hour), notAfter: now.Add(-48 * time.Hour)},
+		{node: "revoked", serial: "4", issuedAt: now.Add(-72 * time.Hour), notAfter: now.Add(-48 * time.Hour)},
+		{node: "legacy", serial: "5", issuedAt: now.Add(-certs.MaxEdgeCertDuration - 48*time.Hour)},
+		{node: "legacy-valid", serial: "6", issuedAt: now.Add(-48 * time.Hour)},
+	}
+	l.mu.Lock()
+	for _, s := range seed {
+		l.appendLocked(func(index uint64, prevHash string) certs.IssuanceLogEntry {
+			return certs.NewIssuanceLogEntry(index, s.issuedAt, s.node, "10.0.0.1", s.serial,
+				[]byte(s.node), s.notAfter, "", prevHash)
+		})
+	}
+	l.mu.Unlock()
+	require.NoError(t, l.flush())
+
+	isRevoked := func(serial string) bool { return serial == "4" }
+	n, err := l.Prune(now, retention, 0, isRevoked)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	check := func(l *Log) {
+		for i, e := range l.entries {
+			wantPruned := seed[i].node == "expired" || seed[i].node == "legacy"
+			require.Equal(t, wantPruned, e.Pruned, seed[i].node)
+			_, found := l.Lookup(seed[i].serial)
+			require.Equal(t, !wantPruned, found, seed[i].node)
+		}
+		seg, err := l.Export(0, testSigner(t, caKeyDER))
+		require.NoError(t, err)
+		require.Len(t, seg.Entries, len(seed))
+		require.NoError(t, certs.VerifyIssuanceLog(seg.PrevHash, seg.Entries, seg.Head, caDER))
+	}
+	check(l)
+
+	// the file is rewritten without the records of the pruned entries
+	bff, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.NotContains(t, string(bff), `"nodeName":"expired"`)
+	require.Equal(t, len(seed), strings.Count(string(bff), "\n"))
+	reloaded, err := NewLog(file)
+	require.NoError(t, err)
+	check(reloaded)
+
+	// pruning again is a no-op
+	n, err = l.Prune(now, retention, 0, isRevoked)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	// the entries appended after pruning are persisted after the pruned ones
+	l.mu.Lock()
+	l.appendLocked(func(index uint64, prevHash string) certs.IssuanceLogEntry {
+		return certs.NewIssuanceLogEntry(index, now, "new", "10.0.0.1", "7", []byte("new"), now.Add(time.Hour), "", prevHash)
+	})
+	l.mu.Unlock()
+	require.NoError(t, l.flush())
+	reloaded, err = NewLog(file)
+	require.NoError(t, err)
+	require.Len(t, reloaded.entries, len(seed)+1)
+	seg, err := reloaded.Export(0, testSigner(t, caKeyDER))
+	require.NoError(t, err)
+	require.NoError(t, certs.VerifyIssuanceLog(seg.PrevHash, seg.Entries, seg.Head, caDER))
+}
+
+func TestLogPruneMaxPerNode(t *testing.T) {
+	caDER, caKeyDER := newTestCA(t)
+	l, err := NewLog(filepath.Join(t.TempDir(), "issuance.log"))
+	require.NoError(t, err)
+
+	now := time.Now()
+	seed := []struct {
+		node   string
+		serial string
+	}{
+		{node: "node1", serial: "1"},
+		{node: "node1", serial: "2"},
+		{node: "node2", serial: "3"},
+		{node: "node1", serial: "4"},
+		{node: "node1", serial: "5"},
+	}
+	l.mu.Lock()
+	for _, s := range seed {
+		l.appendLocked(func(index uint64, prevHash string) certs.IssuanceLogEntry {
+			return certs.NewIssuanceLogEntry(index, now, s.node, "10.0.0.1", s.serial,
+				[]byte(s.serial), now.Add(time.Hour), "", prevHash)
+		})
+	}
+	l.mu.Unlock()
+
+	// the revoked entries are kept even if they are beyond the max
+	isRevoked := func(serial string) bool { return serial == "2" }
+	n, err := l.Prune(now, time.Hour, 2, isRevoked)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	var serials []string
+	for _, e := range l.NodeEntries("node1") {
+		serials = append(serials, e.Serial)
+	}
+	require.Equal(t, []string{"2", "4", "5"}, serials)
+	require.Len(t, l.NodeEntries("node2"), 1)
+
+	seg, err := l.Export(0, testSigner(t, caKeyDER))
+	require.NoError(t, err)
+	require.NoError(t, certs.VerifyIssuanceLog(seg.PrevHash, seg.Entries, seg.Head, caDER))
+
+	// the records are not limited by the max of 0
+	n, err = l.Prune(now, time.Hour, 0, func(string) bool { return false })
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestPruneCertificates(t *testing.T) {
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/certificate/prune", nil)
+		recorder := httptest.NewRecorder()
+		PruneCertificates(restful.NewRequest(req), restful.NewResponse(recorder))
+		return recorder
+	}
+
+	defaultLog = nil
+	require.Equal(t, http.StatusNotFound, post().Code)
+
+	hubconfig.Config.IssuanceLog = &v1alpha1.CloudHubIssuanceLog{Enable: true, PruneRetention: 1}
+	originList := revocation.DefaultList
+	revocation.DefaultList = revocation.NewList()
+	defer func() {
+		hubconfig.Config.IssuanceLog = nil
+		revocation.DefaultList = originList
+	}()
+
+	l, err := NewLog(filepath.Join(t.TempDir(), "issuance.log"))
+	require.NoError(t, err)
+	defaultLog = l
+	defer func() { defaultLog = nil }()
+	now := time.Now()
+	l.mu.Lock()
+	for i, notAfter := range []time.Time{
+		now.Add(-48 * time.Hour), // expired more than the retention ago
+		now.Add(-48 * time.Hour), // revoked
+		now.Add(-time.Hour),      // in the retention
+		now.Add(time.Hour),       // valid
+	} {
+		serial := string(rune('1' + i))
+		l.appendLocked(func(index uint64, prevHash string) certs.IssuanceLogEntry {
+			return certs.NewImportedIssuanceLogEntry(index, now.Add(-72*time.Hour), "node"+serial, serial,
+				"hash"+serial, notAfter, prevHash)
+		})
+	}
+	l.mu.Unlock()
+	revocation.DefaultList.RevokeSerial("2", now.Add(time.Hour))
+
+	resp := post()
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var pruneResp PruneResponse
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &pruneResp))
+	require.Equal(t, 1, pruneResp.Pruned)
+	require.True(t, l.entries[0].Pruned)
+
+	var nodes []string
+	ForEach(func(e certs.IssuanceLogEntry) { nodes = append(nodes, e.NodeName) })
+	require.Equal(t, []string{"node2", "node3", "node4"}, nodes)
+}
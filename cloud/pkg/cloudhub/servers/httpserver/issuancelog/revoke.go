@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// RevokeRequest is the request body of revoking an edge certificate.
+type RevokeRequest struct {
+	// Serial is the decimal serial number of the certificate.
+	Serial string `json:"serial"`
+	// NotAfter is the expiration of the certificate, it is looked up in the issuance log if it
+	// is not given, and the max validity of the edge certificates is assumed if it is unknown.
+	NotAfter *time.Time `json:"notAfter,omitempty"`
+}
+
+// ListRevocations lists the revoked and unexpired edge certificates.
+func ListRevocations(_ *restful.Request, response *restful.Response) {
+	writeJSON(response, revocation.DefaultList.Entries())
+}
+
+// RevokeCertificate revokes the edge certificate, which is rejected by the certificate rotation
+// and the cloudhub servers from then on. The session of the node holding it is closed by the
+// next session check of the revocations.
+func RevokeCertificate(request *restful.Request, response *restful.Response) {
+	body, err := io.ReadAll(http.MaxBytesReader(response, request.Request.Body, constants.MaxRespBodyLength))
+	if err != nil {
+		resps.ErrorMessage(response, http.StatusBadRequest, fmt.Sprintf("failed to read the body, err: %v", err))
+		return
+	}
+	var req RevokeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		resps.ErrorMessage(response, http.StatusBadRequest, fmt.Sprintf("failed to unmarshal the body, err: %v", err))
+		return
+	}
+	if _, ok := new(big.Int).SetString(req.Serial, 10); !ok {
+		resps.ErrorMessage(response, http.StatusBadRequest, fmt.Sprintf("invalid serial number %q, it must be decimal", req.Serial))
+		return
+	}
+	entry := revocation.Entry{Serial: req.Serial, NotAfter: time.Now().Add(certs.MaxEdgeCertDuration)}
+	if req.NotAfter != nil {
+		entry.NotAfter = *req.NotAfter
+	} else if defaultLog != nil {
+		if e, ok := defaultLog.Lookup(req.Serial); ok {
+			entry.NotAfter = expiration(e)
+		}
+	}
+	revocation.DefaultList.RevokeSerial(entry.Serial, entry.NotAfter)
+	klog.InfoS("Audit revoked the edge certificate", "serial", entry.Serial, "notAfter", entry.NotAfter)
+	writeJSON(response, entry)
+}
+
+// UnrevokeCertificate removes the edge certificate of the path parameter serial from the
+// revocation list, it responds 404 if the certificate is not revoked.
+func UnrevokeCertificate(request *restful.Request, response *restful.Response) {
+	serial := request.PathParameter("serial")
+	if !revocation.DefaultList.Unrevoke(serial) {
+		resps.ErrorMessage(response, http.StatusNotFound, fmt.Sprintf("the edge certificate %s is not revoked", serial))
+		return
+	}
+	klog.InfoS("Audit unrevoked the edge certificate", "serial", serial)
+	response.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(response *restful.Response, obj any) {
+	bff, err := json.Marshal(obj)
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	response.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	resps.OK(response, bff)
+}
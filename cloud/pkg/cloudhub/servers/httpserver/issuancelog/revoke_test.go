@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancelog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestRevocationHandlers(t *testing.T) {
+	originList := revocation.DefaultList
+	revocation.DefaultList = revocation.NewList()
+	defer func() { revocation.DefaultList = originList }()
+
+	l, err := NewLog(filepath.Join(t.TempDir(), "issuance.log"))
+	require.NoError(t, err)
+	defaultLog = l
+	defer func() { defaultLog = nil }()
+	logged := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	_, _, err = l.Import("node1", "1", "hash1", logged)
+	require.NoError(t, err)
+
+	ws := new(restful.WebService)
+	ws.Route(ws.GET(constants.DefaultRevocationURL).To(ListRevocations))
+	ws.Route(ws.POST(constants.DefaultRevocationURL).To(RevokeCertificate))
+	ws.Route(ws.DELETE(constants.DefaultRevocationItemURL).To(UnrevokeCertificate))
+	container := restful.NewContainer()
+	container.Add(ws)
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set(restful.HEADER_ContentType, restful.MIME_JSON)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+		return recorder
+	}
+	list := func() []revocation.Entry {
+		resp := do(http.MethodGet, constants.DefaultRevocationURL, "")
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var entries []revocation.Entry
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &entries))
+		return entries
+	}
+
+	require.Empty(t, list())
+
+	// the expiration is looked up in the issuance log
+	resp := do(http.MethodPost, constants.DefaultRevocationURL, `{"serial":"1"}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.True(t, revocation.DefaultList.IsRevokedSerial("1"))
+
+	given := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	body, err := json.Marshal(RevokeRequest{Serial: "2", NotAfter: &given})
+	require.NoError(t, err)
+	resp = do(http.MethodPost, constants.DefaultRevocationURL, string(body))
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	// the max validity is assumed for the unknown certificate
+	resp = do(http.MethodPost, constants.DefaultRevocationURL, `{"serial":"3"}`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var entry revocation.Entry
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &entry))
+	require.WithinDuration(t, time.Now().Add(certs.MaxEdgeCertDuration), entry.NotAfter, time.Minute)
+
+	entries := list()
+	require.Len(t, entries, 3)
+	require.Equal(t, revocation.Entry{Serial: "1", NotAfter: logged, RevokedAt: entries[0].RevokedAt}, entries[0])
+	require.Equal(t, revocation.Entry{Serial: "2", NotAfter: given, RevokedAt: entries[1].RevokedAt}, entries[1])
+	require.WithinDuration(t, time.Now(), entries[0].RevokedAt, time.Minute)
+
+	resp = do(http.MethodPost, constants.DefaultRevocationURL, `{"serial":"0x1f"}`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = do(http.MethodDelete, "/admin/revocations/1", "")
+	require.Equal(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	require.False(t, revocation.DefaultList.IsRevokedSerial("1"))
+	resp = do(http.MethodDelete, "/admin/revocations/1", "")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+	require.Len(t, list(), 2)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocsp
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	xocsp "golang.org/x/crypto/ocsp"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+const mimeOCSPResponse = "application/ocsp-response"
+
+// HandleOCSP answers the OCSP requests sent by GET with the base64 encoded request in the path,
+// or by POST with the DER encoded request in the body, as described in the appendix A of RFC 6960.
+// The unsuccessful OCSP statuses are responded with 200 as well.
+func HandleOCSP(request *restful.Request, response *restful.Response) {
+	r := defaultResponder
+	if r == nil {
+		http.Error(response, "OCSP is not enabled", http.StatusNotFound)
+		return
+	}
+	var der []byte
+	var err error
+	switch request.Request.Method {
+	case http.MethodGet:
+		der, err = base64.StdEncoding.DecodeString(request.PathParameter("request"))
+	case http.MethodPost:
+		der, err = io.ReadAll(http.MaxBytesReader(response, request.Request.Body, constants.MaxRespBodyLength))
+	}
+	resp := xocsp.MalformedRequestErrorResponse
+	if err != nil {
+		klog.V(4).Infof("failed to read the OCSP request, err: %v", err)
+	} else {
+		resp = r.Respond(der)
+	}
+	response.Header().Set("Content-Type", mimeOCSPResponse)
+	response.WriteHeader(http.StatusOK)
+	if _, err := response.Write(resp); err != nil {
+		klog.Errorf("failed to write the OCSP response, err: %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocsp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	xocsp "golang.org/x/crypto/ocsp"
+	"k8s.io/klog/v2"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/issuancelog"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// Issuer is a CA whose edge certificates are answered by the responder besides the CA of CloudHub,
+// e.g. a named issuer. Its responses are signed by the CA key.
+type Issuer struct {
+	CA     *x509.Certificate
+	Signer crypto.Signer
+}
+
+// Responder answers the OCSP requests of the edge certificates signed by the CA of CloudHub
+// and the named issuers.
+type Responder struct {
+	// signerCert and signer are the delegated OCSP signing certificate and its key,
+	// the responses are signed by the CA if they are nil
+	signerCert *x509.Certificate
+	signer     crypto.Signer
+	issuers    []Issuer
+	validity   time.Duration
+	now        func() time.Time
+}
+
+var defaultResponder *Responder
+
+// Init loads the delegated OCSP signing certificate if it is configured, and enables the
+// responder of the CA of CloudHub and the issuers if OCSP is enabled.
+func Init(issuers ...Issuer) error {
+	o := hubconfig.Config.OCSP
+	if o == nil || !o.Enable {
+		defaultResponder = nil
+		return nil
+	}
+	r := &Responder{issuers: issuers, validity: time.Duration(o.ValidityPeriod) * time.Second, now: time.Now}
+	if o.SignerCertFile != "" {
+		cert, signer, err := loadSigner(o.SignerCertFile, o.SignerKeyFile)
+		if err != nil {
+			return err
+		}
+		r.signerCert, r.signer = cert, signer
+	}
+	defaultResponder = r
+	return nil
+}
+
+// loadSigner loads the delegated OCSP signing certificate and its key, the certificate must be
+// issued by the CA for OCSP signing.
+func loadSigner(certFile, keyFile string) (*x509.Certificate, crypto.Signer, error) {
+	certBlock, err := certs.ReadPEMFile(certFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load the OCSP signing certificate %s, err: %v", certFile, err)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the OCSP signing certificate %s, err: %v", certFile, err)
+	}
+	ca, err := x509.ParseCertificate(hubconfig.Config.Ca)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the CA, err: %v", err)
+	}
+	if err := cert.CheckSignatureFrom(ca); err != nil {
+		return nil, nil, fmt.Errorf("the OCSP signing certificate %s is not issued by the CA, err: %v", certFile, err)
+	}
+	if !slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageOCSPSigning) {
+		return nil, nil, fmt.Errorf("the OCSP signing certificate %s does not have the OCSPSigning ExtKeyUsage", certFile)
+	}
+	keyBlock, err := certs.ReadPEMFile(keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load the OCSP signing key %s, err: %v", keyFile, err)
+	}
+	signer, err := certs.PrivateKeySigner(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the OCSP signing key %s, err: %v", keyFile, err)
+	}
+	if !publicKeyEqual(signer.Public(), cert.PublicKey) {
+		return nil, nil, fmt.Errorf("the OCSP signing key %s does not match the certificate %s", keyFile, certFile)
+	}
+	return cert, signer, nil
+}
+
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	k, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && k.Equal(b)
+}
+
+// ResponderURL returns the URL of the OCSP responder added to the edge certificates, it is empty
+// if OCSP is not enabled.
+func ResponderURL() string {
+	o := hubconfig.Config.OCSP
+	if o == nil || !o.Enable {
+		return ""
+	}
+	if o.URL != "" {
+		return o.URL
+	}
+	if len(hubconfig.Config.AdvertiseAddress) == 0 || hubconfig.Config.HTTPS == nil {
+		return ""
+	}
+	host := net.JoinHostPort(hubconfig.Config.AdvertiseAddress[0], strconv.Itoa(int(hubconfig.Config.HTTPS.Port)))
+	return (&url.URL{Scheme: "https", Host: host, Path: constants.DefaultOCSPURL}).String()
+}
+
+// Respond returns the DER encoded OCSP response of the DER encoded OCSP request. The response
+// is signed for the issuer of the first certificate in the request. The certificates of other
+// issuers and the certificates not in the enabled issuance log are answered unknown, and the
+// nonce of the request is echoed in the response.
+func (r *Responder) Respond(der []byte) []byte {
+	var req ocspRequest
+	if rest, err := asn1.Unmarshal(der, &req); err != nil || len(rest) > 0 || len(req.TBSRequest.RequestList) == 0 {
+		klog.V(4).Infof("malformed OCSP request, err: %v", err)
+		return xocsp.MalformedRequestErrorResponse
+	}
+	var extensions []pkix.Extension
+	for _, ext := range req.TBSRequest.Extensions {
+		if ext.Id.Equal(oidOCSPNonce) {
+			if len(ext.Value) > maxNonceLength {
+				return xocsp.MalformedRequestErrorResponse
+			}
+			extensions = append(extensions, pkix.Extension{Id: oidOCSPNonce, Value: ext.Value})
+		}
+	}
+	ids := make([]certID, 0, len(req.TBSRequest.RequestList))
+	for _, single := range req.TBSRequest.RequestList {
+		var id certID
+		if rest, err := asn1.Unmarshal(single.CertID.FullBytes, &id); err != nil || len(rest) > 0 || id.SerialNumber == nil {
+			return xocsp.MalformedRequestErrorResponse
+		}
+		ids = append(ids, id)
+	}
+
+	ca, signerCert, signer, err := r.issuerOf(ids[0])
+	if err != nil {
+		klog.Errorf("failed to get the signer to respond the OCSP request, err: %v", err)
+		return xocsp.InternalErrorErrorResponse
+	}
+
+	now := r.now().UTC().Truncate(time.Second)
+	responses := make([]singleResponse, 0, len(ids))
+	for i, id := range ids {
+		status, err := r.certStatus(id, ca)
+		if err != nil {
+			klog.Errorf("failed to marshal the OCSP status of the certificate %s, err: %v", id.SerialNumber, err)
+			return xocsp.InternalErrorErrorResponse
+		}
+		responses = append(responses, singleResponse{
+			CertID:     req.TBSRequest.RequestList[i].CertID,
+			CertStatus: status,
+			ThisUpdate: now,
+			NextUpdate: now.Add(r.validity),
+		})
+	}
+
+	tbs := responseData{
+		ResponderID: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 1, IsCompound: true, Bytes: signerCert.RawSubject},
+		ProducedAt:  now,
+		Responses:   responses,
+		Extensions:  extensions,
+	}
+	resp, err := signResponse(tbs, signerCert, signer, signerCert != ca)
+	if err != nil {
+		klog.Errorf("failed to sign the OCSP response, err: %v", err)
+		return xocsp.InternalErrorErrorResponse
+	}
+	return resp
+}
+
+// issuerOf returns the issuer of the certificate in the CertID, and the certificate and the key
+// which sign the responses of the issuer. It returns the CA of CloudHub if the certificate is
+// not issued by any issuer, whose certificates are answered unknown then.
+func (r *Responder) issuerOf(id certID) (ca, signerCert *x509.Certificate, signer crypto.Signer, err error) {
+	for _, iss := range r.issuers {
+		if issuedBy(id, iss.CA) {
+			return iss.CA, iss.CA, iss.Signer, nil
+		}
+	}
+	// the CA of CloudHub is read for every request, since it may be reloaded
+	if ca, err = x509.ParseCertificate(hubconfig.Config.Ca); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse the CA, err: %v", err)
+	}
+	if r.signer != nil {
+		return ca, r.signerCert, r.signer, nil
+	}
+	if signer, err = hubconfig.Config.CASigner(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get the signer of the CA, err: %v", err)
+	}
+	return ca, ca, signer, nil
+}
+
+// certStatus returns the CertStatus of the certificate in the CertID. The certificate is
+// revoked if it is in the revocation list, unknown if it is not issued by the CA or not in
+// the enabled issuance log, otherwise it is good.
+func (r *Responder) certStatus(id certID, ca *x509.Certificate) (asn1.RawValue, error) {
+	serial := id.SerialNumber.String()
+	if !issuedBy(id, ca) {
+		return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: xocsp.Unknown}, nil
+	}
+	if revokedAt, ok := revocation.DefaultList.RevokedAt(serial); ok {
+		if revokedAt.IsZero() {
+			// the revocation time of the entries restored from an old snapshot is unknown
+			revokedAt = r.now()
+		}
+		revokedInfo, err := asn1.MarshalWithParams(revokedAt.UTC().Truncate(time.Second), "generalized")
+		if err != nil {
+			return asn1.RawValue{}, err
+		}
+		return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: xocsp.Revoked, IsCompound: true,
+			Bytes: revokedInfo}, nil
+	}
+	if issuancelog.Enabled() {
+		if _, ok := issuancelog.Lookup(serial); !ok {
+			return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: xocsp.Unknown}, nil
+		}
+	}
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: xocsp.Good}, nil
+}
+
+// issuedBy reports whether the issuer hashes of the CertID are the hashes of the CA.
+func issuedBy(id certID, ca *x509.Certificate) bool {
+	hash, ok := hashFromOID(id.HashAlgorithm.Algorithm)
+	if !ok {
+		return false
+	}
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(ca.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return false
+	}
+	h := hash.New()
+	h.Write(ca.RawSubject)
+	if !bytes.Equal(h.Sum(nil), id.IssuerNameHash) {
+		return false
+	}
+	h.Reset()
+	h.Write(spki.PublicKey.RightAlign())
+	return bytes.Equal(h.Sum(nil), id.IssuerKeyHash)
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocsp
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xocsp "golang.org/x/crypto/ocsp"
+	certutil "k8s.io/client-go/util/cert"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/issuancelog"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// enableOCSP enables the responder with the config, the previous config is restored when
+// the test finishes.
+func enableOCSP(t *testing.T, o *v1alpha1.CloudHubOCSP, issuers ...Issuer) {
+	origin, originList := hubconfig.Config.OCSP, revocation.DefaultList
+	t.Cleanup(func() {
+		hubconfig.Config.OCSP, revocation.DefaultList, defaultResponder = origin, originList, nil
+	})
+	o.Enable = true
+	hubconfig.Config.OCSP, revocation.DefaultList = o, revocation.NewList()
+	require.NoError(t, Init(issuers...))
+}
+
+// TestMain enables the issuance log for the tests, so that the certificates not recorded in it
+// are answered unknown.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "ocsp")
+	if err != nil {
+		panic(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := issuancelog.Init(ctx, filepath.Join(dir, "issuance.log")); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	cancel()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// issue issues a certificate of the node and records it in the issuance log.
+func issue(t *testing.T, ca *testutil.CA, nodeName string) *x509.Certificate {
+	cert := ca.Issue(t, ca.NewNode(t, nodeName))
+	issuancelog.Record(nodeName, "", "token", cert.Raw)
+	return cert
+}
+
+func newRequest(t *testing.T, cert, issuer *x509.Certificate, hash crypto.Hash) []byte {
+	req, err := xocsp.CreateRequest(cert, issuer, &xocsp.RequestOptions{Hash: hash})
+	require.NoError(t, err)
+	return req
+}
+
+func TestRespond(t *testing.T) {
+	other := testutil.NewCA(t)
+	otherCert := other.Issue(t, other.NewNode(t, "node-other"))
+	ca := testutil.NewCA(t)
+	enableOCSP(t, &v1alpha1.CloudHubOCSP{ValidityPeriod: 3600})
+
+	good := issue(t, ca, "node-good")
+	revoked := issue(t, ca, "node-revoked")
+	unlogged := ca.Issue(t, ca.NewNode(t, "node-unlogged"))
+	revocation.DefaultList.Revoke(revoked)
+
+	cases := []struct {
+		name       string
+		cert       *x509.Certificate
+		issuer     *x509.Certificate
+		hash       crypto.Hash
+		wantStatus int
+	}{
+		{name: "good", cert: good, issuer: ca.Cert, wantStatus: xocsp.Good},
+		{name: "good with SHA-256", cert: good, issuer: ca.Cert, hash: crypto.SHA256, wantStatus: xocsp.Good},
+		{name: "revoked", cert: revoked, issuer: ca.Cert, wantStatus: xocsp.Revoked},
+		{name: "not in the issuance log", cert: unlogged, issuer: ca.Cert, wantStatus: xocsp.Unknown},
+		{name: "another issuer", cert: otherCert, issuer: other.Cert, wantStatus: xocsp.Unknown},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			der := defaultResponder.Respond(newRequest(t, c.cert, c.issuer, c.hash))
+			resp, err := xocsp.ParseResponseForCert(der, c.cert, ca.Cert)
+			require.NoError(t, err)
+			assert.Equal(t, c.wantStatus, resp.Status)
+			assert.Equal(t, c.cert.SerialNumber, resp.SerialNumber)
+			assert.Equal(t, time.Hour, resp.NextUpdate.Sub(resp.ThisUpdate))
+			if c.wantStatus == xocsp.Revoked {
+				assert.WithinDuration(t, time.Now(), resp.RevokedAt, time.Minute)
+			}
+		})
+	}
+}
+
+func TestRespondNamedIssuer(t *testing.T) {
+	named := testutil.NewCA(t)
+	// the global CA is the latest one set up
+	ca := testutil.NewCA(t)
+	namedSigner, err := named.Key.Signer()
+	require.NoError(t, err)
+	enableOCSP(t, &v1alpha1.CloudHubOCSP{ValidityPeriod: 3600}, Issuer{CA: named.Cert, Signer: namedSigner})
+
+	good := issue(t, named, "node-good")
+	revoked := issue(t, named, "node-revoked")
+	revocation.DefaultList.Revoke(revoked)
+	cases := []struct {
+		name       string
+		cert       *x509.Certificate
+		issuer     *x509.Certificate
+		wantStatus int
+	}{
+		{name: "named issuer", cert: good, issuer: named.Cert, wantStatus: xocsp.Good},
+		{name: "revoked by named issuer", cert: revoked, issuer: named.Cert, wantStatus: xocsp.Revoked},
+		{name: "global CA", cert: issue(t, ca, "node-global"), issuer: ca.Cert, wantStatus: xocsp.Good},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			der := defaultResponder.Respond(newRequest(t, c.cert, c.issuer, crypto.SHA256))
+			// the response is signed by the issuer of the certificate
+			resp, err := xocsp.ParseResponseForCert(der, c.cert, c.issuer)
+			require.NoError(t, err)
+			assert.Equal(t, c.wantStatus, resp.Status)
+		})
+	}
+}
+
+func TestRespondNonce(t *testing.T) {
+	ca := testutil.NewCA(t)
+	enableOCSP(t, &v1alpha1.CloudHubOCSP{ValidityPeriod: 3600})
+	cert := issue(t, ca, "node1")
+
+	var req ocspRequest
+	_, err := asn1.Unmarshal(newRequest(t, cert, ca.Cert, crypto.SHA1), &req)
+	require.NoError(t, err)
+	nonce := make([]byte, 16)
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+	nonceValue, err := asn1.Marshal(nonce)
+	require.NoError(t, err)
+	req.TBSRequest.Extensions = []pkix.Extension{{Id: oidOCSPNonce, Value: nonceValue}}
+	der, err := asn1.Marshal(req)
+	require.NoError(t, err)
+
+	respDER := defaultResponder.Respond(der)
+	resp, err := xocsp.ParseResponseForCert(respDER, cert, ca.Cert)
+	require.NoError(t, err)
+	assert.Equal(t, xocsp.Good, resp.Status)
+
+	var respASN1 responseASN1
+	_, err = asn1.Unmarshal(respDER, &respASN1)
+	require.NoError(t, err)
+	var basic basicResponse
+	_, err = asn1.Unmarshal(respASN1.Response.Response, &basic)
+	require.NoError(t, err)
+	require.Len(t, basic.TBSResponseData.Extensions, 1)
+	assert.True(t, basic.TBSResponseData.Extensions[0].Id.Equal(oidOCSPNonce))
+	assert.Equal(t, nonceValue, basic.TBSResponseData.Extensions[0].Value)
+
+	req.TBSRequest.Extensions[0].Value = make([]byte, maxNonceLength+1)
+	der, err = asn1.Marshal(req)
+	require.NoError(t, err)
+	_, err = xocsp.ParseResponse(defaultResponder.Respond(der), nil)
+	assert.Equal(t, xocsp.ResponseError{Status: xocsp.Malformed}, err)
+}
+
+func TestRespondDelegatedSigner(t *testing.T) {
+	ca := testutil.NewCA(t)
+	caSigner, err := ca.Key.Signer()
+	require.NoError(t, err)
+	newSigner := func(usages ...x509.ExtKeyUsage) (string, string) {
+		key, err := certs.GetHandler(certs.HandlerTypeX509).GenPrivateKey()
+		require.NoError(t, err)
+		signer, err := key.Signer()
+		require.NoError(t, err)
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: "KubeEdge OCSP"},
+			NotBefore:    time.Now().Add(-time.Minute),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  usages,
+		}, ca.Cert, signer.Public(), caSigner)
+		require.NoError(t, err)
+		dir := t.TempDir()
+		certFile, keyFile := filepath.Join(dir, "ocsp.crt"), filepath.Join(dir, "ocsp.key")
+		_, err = certs.WriteDERToPEMFile(certFile, certutil.CertificateBlockType, der)
+		require.NoError(t, err)
+		_, err = certs.WriteDERToPEMFile(keyFile, certs.PrivateKeyBlockType(key.DER()), key.DER())
+		require.NoError(t, err)
+		return certFile, keyFile
+	}
+
+	certFile, keyFile := newSigner(x509.ExtKeyUsageOCSPSigning)
+	enableOCSP(t, &v1alpha1.CloudHubOCSP{SignerCertFile: certFile, SignerKeyFile: keyFile, ValidityPeriod: 60})
+	cert := issue(t, ca, "node1")
+	resp, err := xocsp.ParseResponseForCert(defaultResponder.Respond(newRequest(t, cert, ca.Cert, crypto.SHA1)), cert, ca.Cert)
+	require.NoError(t, err)
+	assert.Equal(t, xocsp.Good, resp.Status)
+	require.NotNil(t, resp.Certificate)
+	assert.Equal(t, "KubeEdge OCSP", resp.Certificate.Subject.CommonName)
+
+	certFile, keyFile = newSigner(x509.ExtKeyUsageClientAuth)
+	hubconfig.Config.OCSP.SignerCertFile, hubconfig.Config.OCSP.SignerKeyFile = certFile, keyFile
+	assert.ErrorContains(t, Init(), "OCSPSigning")
+}
+
+func TestHandleOCSP(t *testing.T) {
+	ca := testutil.NewCA(t)
+	enableOCSP(t, &v1alpha1.CloudHubOCSP{ValidityPeriod: 3600})
+	cert := issue(t, ca, "node1")
+	reqDER := newRequest(t, cert, ca.Cert, crypto.SHA1)
+
+	ws := new(restful.WebService)
+	ws.Route(ws.POST(constants.DefaultOCSPURL).To(HandleOCSP))
+	ws.Route(ws.GET(constants.DefaultOCSPRequestURL).To(HandleOCSP))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	cases := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{
+			name:       "POST",
+			req:        httptest.NewRequest(http.MethodPost, constants.DefaultOCSPURL, bytes.NewReader(reqDER)),
+			wantStatus: xocsp.Good,
+		},
+		{
+			name:       "GET",
+			req:        httptest.NewRequest(http.MethodGet, constants.DefaultOCSPURL+"/"+base64.StdEncoding.EncodeToString(reqDER), nil),
+			wantStatus: xocsp.Good,
+		},
+		{
+			name:       "malformed",
+			req:        httptest.NewRequest(http.MethodPost, constants.DefaultOCSPURL, bytes.NewReader([]byte("invalid"))),
+			wantStatus: -1,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			container.ServeHTTP(w, c.req)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, mimeOCSPResponse, w.Header().Get("Content-Type"))
+			resp, err := xocsp.ParseResponseForCert(w.Body.Bytes(), cert, ca.Cert)
+			if c.wantStatus < 0 {
+				assert.Equal(t, xocsp.ResponseError{Status: xocsp.Malformed}, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.wantStatus, resp.Status)
+		})
+	}
+}
+
+func TestResponderURL(t *testing.T) {
+	origin, originAddrs, originHTTPS := hubconfig.Config.OCSP, hubconfig.Config.AdvertiseAddress, hubconfig.Config.HTTPS
+	defer func() {
+		hubconfig.Config.OCSP, hubconfig.Config.AdvertiseAddress, hubconfig.Config.HTTPS = origin, originAddrs, originHTTPS
+	}()
+	hubconfig.Config.AdvertiseAddress = []string{"10.0.0.1"}
+	hubconfig.Config.HTTPS = &v1alpha1.CloudHubHTTPS{Port: 10002}
+
+	hubconfig.Config.OCSP = &v1alpha1.CloudHubOCSP{}
+	assert.Empty(t, ResponderURL())
+	hubconfig.Config.OCSP.Enable = true
+	assert.Equal(t, "https://10.0.0.1:10002/ocsp", ResponderURL())
+	hubconfig.Config.OCSP.URL = "http://ocsp.example.com/ocsp"
+	assert.Equal(t, "http://ocsp.example.com/ocsp", ResponderURL())
+}
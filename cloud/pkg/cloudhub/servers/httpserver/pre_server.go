@@ -17,7 +17,9 @@ package httpserver
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"time"
@@ -42,6 +44,7 @@ const (
 	CloudCoreSecretName  string = "cloudcoresecret"
 	CaDataName           string = "cadata"
 	CaKeyDataName        string = "cakeydata"
+	TokenKeyDataName     string = "tokenkeydata"
 	CloudCoreCertName    string = "cloudcoredata"
 	CloudCoreKeyDataName string = "cloudcorekeydata"
 )
@@ -56,6 +59,9 @@ func PrepareAllCerts(ctx context.Context) error {
 }
 
 func createCAToSecret(ctx context.Context) error {
+	if hubconfig.Config.CaSigner != nil {
+		return createTokenKeyToSecret(ctx)
+	}
 	var caDER, keyDER []byte
 	// Check whether the ca exists in the local directory
 	if hubconfig.Config.Ca == nil && hubconfig.Config.CaKey == nil {
@@ -81,9 +87,20 @@ func createCAToSecret(ctx context.Context) error {
 			}
 			caDER = caPem.Bytes
 			keyDER = pk.DER()
+			encrypted, err := hubconfig.Config.EncryptCAKey(keyDER)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt the CA key, err: %v", err)
+			}
+			hubconfig.Config.EncryptedCaKey = encrypted
 		} else {
 			caDER = caSecret.Data[CaDataName]
 			keyDER = caSecret.Data[CaKeyDataName]
+			if block, _ := pem.Decode(keyDER); block != nil && certs.IsEncryptedPrivateKey(block) {
+				if keyDER, err = hubconfig.Config.DecryptCAKey(block); err != nil {
+					return fmt.Errorf("failed to decrypt the CA key of the secret %s, err: %v", CaSecretName, err)
+				}
+				hubconfig.Config.EncryptedCaKey = caSecret.Data[CaKeyDataName]
+			}
 		}
 
 		hubconfig.Config.UpdateCA(caDER, keyDER)
@@ -93,6 +110,10 @@ func createCAToSecret(ctx context.Context) error {
 		keyDER = hubconfig.Config.CaKey
 	}
 
+	// The CA key encrypted at rest is never saved in plaintext, so it can't sign the tokens either
+	if hubconfig.Config.EncryptedCaKey != nil {
+		return createTokenKeyToSecret(ctx)
+	}
 	if err := client.SaveSecret(ctx, createCaSecret(caDER, keyDER), constants.SystemNamespace); err != nil {
 		return fmt.Errorf("failed to create ca to secrets, error: %v", err)
 	}
@@ -100,6 +121,41 @@ func createCAToSecret(ctx context.Context) error {
 	return nil
 }
 
+// createTokenKeyToSecret reads the dedicated token key from the secret, or generates it if it
+// doesn't exist, since the CA key held by the CA key provider can not sign the tokens. The CA
+// and the token key are saved to the secret without the CA key, or with the encrypted CA key
+// if it is encrypted at rest.
+func createTokenKeyToSecret(ctx context.Context) error {
+	var tokenKey []byte
+	caSecret, err := client.GetSecret(ctx, CaSecretName, constants.SystemNamespace)
+	if err != nil {
+		if !apierror.IsNotFound(err) {
+			return fmt.Errorf("get secret: %s error: %v", CaSecretName, err)
+		}
+	} else {
+		tokenKey = caSecret.Data[TokenKeyDataName]
+	}
+	if len(tokenKey) == 0 {
+		klog.Info("Token key doesn't exist in the secret, and will be created by CloudCore")
+		tokenKey = make([]byte, 32)
+		if _, err := rand.Read(tokenKey); err != nil {
+			return fmt.Errorf("failed to generate the token key, err: %v", err)
+		}
+	}
+	hubconfig.Config.TokenKey = tokenKey
+
+	secret := createCaSecret(hubconfig.Config.Ca, nil)
+	delete(secret.Data, CaKeyDataName)
+	if key := hubconfig.Config.EncryptedCaKey; key != nil {
+		secret.Data[CaKeyDataName] = key
+	}
+	secret.Data[TokenKeyDataName] = tokenKey
+	if err := client.SaveSecret(ctx, secret, constants.SystemNamespace); err != nil {
+		return fmt.Errorf("failed to create ca to secrets, error: %v", err)
+	}
+	return nil
+}
+
 func createCertsToSecret(ctx context.Context) error {
 	const year100 = time.Hour * 24 * 364 * 100
 	var certDER, keyDER []byte
@@ -123,7 +179,7 @@ func createCertsToSecret(ctx context.Context) error {
 			}
 			h := certs.GetHandler(certs.HandlerTypeX509)
 
-			keywrap, err := h.GenPrivateKey()
+			keywrap, err := certs.GenPrivateKey(hubconfig.Config.ServerKeyAlgorithm)
 			if err != nil {
 				return fmt.Errorf("failed to generate the private key, err: %v", err)
 			}
@@ -140,8 +196,12 @@ func createCertsToSecret(ctx context.Context) error {
 					DNSNames: hubconfig.Config.DNSNames,
 					IPs:      ips,
 				},
-			}, hubconfig.Config.Ca, hubconfig.Config.CaKey, key.Public(), year100)
-			certPEM, err := h.SignCerts(opts)
+			}, hubconfig.Config.Ca, nil, key.Public(), year100)
+			caSigner, err := hubconfig.Config.CASigner()
+			if err != nil {
+				return err
+			}
+			certPEM, err := h.SignCerts(ctx, opts.WithCASigner(caSigner))
 			if err != nil {
 				return fmt.Errorf("failed to sign the certificate, err: %v", err)
 			}
@@ -191,7 +251,7 @@ func GenerateAndRefreshToken(ctx context.Context) error {
 }
 
 func createNewToken(ctx context.Context) error {
-	caHashToken, err := token.Create(hubconfig.Config.Ca, hubconfig.Config.CaKey,
+	caHashToken, err := token.Create(hubconfig.Config.Ca, hubconfig.Config.TokenSigningKey(),
 		hubconfig.Config.CloudHub.TokenRefreshDuration)
 	if err != nil {
 		return fmt.Errorf("failed to generate the token for edgecore register, err: %v", err)
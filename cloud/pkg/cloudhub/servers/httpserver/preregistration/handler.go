@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preregistration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+// CreateRegistration pre-registers an edge node with the fingerprint of its public key.
+func CreateRegistration(request *restful.Request, response *restful.Response) {
+	body, err := io.ReadAll(http.MaxBytesReader(response, request.Request.Body, constants.MaxRespBodyLength))
+	if err != nil {
+		resps.ErrorMessage(response, http.StatusBadRequest, fmt.Sprintf("failed to read the body, err: %v", err))
+		return
+	}
+	var reg Registration
+	if err := json.Unmarshal(body, &reg); err != nil {
+		resps.ErrorMessage(response, http.StatusBadRequest, fmt.Sprintf("failed to unmarshal the body, err: %v", err))
+		return
+	}
+	reg, err = DefaultStore.Add(request.Request.Context(), reg)
+	if err != nil {
+		resps.Error(response, http.StatusBadRequest, err)
+		return
+	}
+	klog.Infof("edge node %s is pre-registered with fingerprint %s", reg.NodeName, reg.Fingerprint)
+	writeJSON(response, reg)
+}
+
+// ListRegistrations lists the unconsumed and unexpired pre-registrations.
+func ListRegistrations(request *restful.Request, response *restful.Response) {
+	regs, err := DefaultStore.List(request.Request.Context())
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(response, regs)
+}
+
+func writeJSON(response *restful.Response, obj any) {
+	bff, err := json.Marshal(obj)
+	if err != nil {
+		resps.Error(response, http.StatusInternalServerError, err)
+		return
+	}
+	response.Header().Set(restful.HEADER_ContentType, restful.MIME_JSON)
+	resps.OK(response, bff)
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preregistration
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+const (
+	// SecretPrefix is the name prefix of the Secrets which record the pre-registrations.
+	SecretPrefix = "edge-preregistration-"
+	// SecretLabel labels the Secrets which record the pre-registrations.
+	SecretLabel = "kubeedge.io/edge-preregistration"
+
+	dataNodeName    = "nodeName"
+	dataFingerprint = "fingerprint"
+	dataExpiresAt   = "expiresAt"
+	dataCreatedAt   = "createdAt"
+
+	// restoreTimeout bounds restoring the registration consumed by a failed request.
+	restoreTimeout = 10 * time.Second
+)
+
+// Registration is a pre-registered edge node, which is allowed to enroll without a token
+// if the public key of its CSR matches the fingerprint.
+type Registration struct {
+	NodeName string `json:"nodeName"`
+	// Fingerprint is the hex encoded SHA-256 digest of the DER encoded SubjectPublicKeyInfo.
+	Fingerprint string `json:"fingerprint"`
+	// ExpiresAt is the time that the registration expires, it never expires if it is nil.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func (r *Registration) expired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
+}
+
+var (
+	// ErrNotFound indicates that there is no valid registration for the node.
+	ErrNotFound = errors.New("no pre-registration found for the node")
+	// ErrMismatch indicates that the public key does not match the registration.
+	ErrMismatch = errors.New("the public key does not match the pre-registration")
+)
+
+// Store stores the pre-registrations in the Secrets "edge-preregistration-<node>" of the
+// kubeedge namespace, so that they are shared by all the cloudcore replicas and survive
+// restarts. A registration is consumed once it is used, by deleting its Secret.
+type Store struct {
+	client func() kubernetes.Interface
+	now    func() time.Time
+}
+
+// DefaultStore is the pre-registration store used by the https server.
+var DefaultStore = &Store{
+	client: client.GetKubeClient,
+	now:    time.Now,
+}
+
+// NewStore returns a store which records the pre-registrations by the kubeClient.
+func NewStore(kubeClient kubernetes.Interface) *Store {
+	return &Store{
+		client: func() kubernetes.Interface { return kubeClient },
+		now:    time.Now,
+	}
+}
+
+// SecretName returns the name of the Secret which records the pre-registration of the node.
+func SecretName(nodeName string) string {
+	return SecretPrefix + nodeName
+}
+
+// Add adds a registration, the registration of the same node is replaced.
+func (s *Store) Add(ctx context.Context, reg Registration) (Registration, error) {
+	if reg.NodeName == "" {
+		return reg, errors.New("nodeName is required")
+	}
+	fp, err := NormalizeFingerprint(reg.Fingerprint)
+	if err != nil {
+		return reg, err
+	}
+	reg.Fingerprint = fp
+
+	now := s.now()
+	if reg.expired(now) {
+		return reg, errors.New("expiresAt must be in the future")
+	}
+	reg.CreatedAt = now.UTC()
+	secrets := s.secrets()
+	secret := newSecret(reg)
+	_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		var existing *corev1.Secret
+		existing, err = secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+		if err == nil {
+			existing.Labels = secret.Labels
+			existing.Data = secret.Data
+			_, err = secrets.Update(ctx, existing, metav1.UpdateOptions{})
+		}
+	}
+	if err != nil {
+		return reg, fmt.Errorf("failed to record the pre-registration of edgenode %s, err: %v", reg.NodeName, err)
+	}
+	return reg, nil
+}
+
+// List returns the unconsumed and unexpired registrations sorted by node name.
+func (s *Store) List(ctx context.Context) ([]Registration, error) {
+	list, err := s.secrets().List(ctx, metav1.ListOptions{LabelSelector: SecretLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the pre-registrations, err: %v", err)
+	}
+	now := s.now()
+	regs := make([]Registration, 0, len(list.Items))
+	for i := range list.Items {
+		reg, err := fromSecret(&list.Items[i])
+		if err != nil {
+			klog.Warningf("skip the invalid pre-registration %s, err: %v", list.Items[i].Name, err)
+			continue
+		}
+		if reg.expired(now) {
+			s.prune(ctx, &list.Items[i])
+			continue
+		}
+		regs = append(regs, reg)
+	}
+	sort.Slice(regs, func(i, j int) bool {
+		return regs[i].NodeName < regs[j].NodeName
+	})
+	return regs, nil
+}
+
+// Has reports whether the node has an unconsumed and unexpired registration.
+func (s *Store) Has(ctx context.Context, nodeName string) (bool, error) {
+	_, _, err := s.get(ctx, nodeName)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Consume checks the public key against the registration of the node,
+// and removes the registration if it matches. The registration is deleted with the
+// preconditions of the read Secret, so that it can be consumed by only one request
+// across the replicas.
+func (s *Store) Consume(ctx context.Context, nodeName string, publicKey any) (Registration, error) {
+	fp, err := PublicKeyFingerprint(publicKey)
+	if err != nil {
+		return Registration{}, err
+	}
+	reg, secret, err := s.get(ctx, nodeName)
+	if err != nil {
+		return Registration{}, err
+	}
+	if reg.Fingerprint != fp {
+		return Registration{}, ErrMismatch
+	}
+	err = s.secrets().Delete(ctx, secret.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &secret.UID, ResourceVersion: &secret.ResourceVersion},
+	})
+	// the registration is consumed or replaced by another request meanwhile
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return Registration{}, ErrNotFound
+	}
+	if err != nil {
+		return Registration{}, fmt.Errorf("failed to consume the pre-registration of edgenode %s, err: %v", nodeName, err)
+	}
+	return reg, nil
+}
+
+// Restore adds back a consumed registration, which is used when the signing fails. It runs
+// with its own deadline, as the context of the request may have expired.
+func (s *Store) Restore(reg Registration) {
+	ctx, cancel := context.WithTimeout(context.Background(), restoreTimeout)
+	defer cancel()
+	_, err := s.secrets().Create(ctx, newSecret(reg), metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.Errorf("failed to restore the pre-registration of edgenode %s, it must be registered again, err: %v",
+			reg.NodeName, err)
+	}
+}
+
+// get returns the unexpired registration of the node and its Secret.
+func (s *Store) get(ctx context.Context, nodeName string) (Registration, *corev1.Secret, error) {
+	secret, err := s.secrets().Get(ctx, SecretName(nodeName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return Registration{}, nil, ErrNotFound
+	}
+	if err != nil {
+		return Registration{}, nil, fmt.Errorf("failed to get the pre-registration of edgenode %s, err: %v", nodeName, err)
+	}
+	reg, err := fromSecret(secret)
+	if err != nil {
+		return Registration{}, nil, fmt.Errorf("invalid pre-registration of edgenode %s, err: %v", nodeName, err)
+	}
+	if reg.NodeName != nodeName {
+		return Registration{}, nil, ErrNotFound
+	}
+	if reg.expired(s.now()) {
+		s.prune(ctx, secret)
+		return Registration{}, nil, ErrNotFound
+	}
+	return reg, secret, nil
+}
+
+// prune deletes the Secret of the expired registration, the failure is only logged as
+// the expired registration is ignored anyway.
+func (s *Store) prune(ctx context.Context, secret *corev1.Secret) {
+	err := s.secrets().Delete(ctx, secret.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &secret.UID, ResourceVersion: &secret.ResourceVersion},
+	})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		klog.Warningf("failed to prune the expired pre-registration %s, err: %v", secret.Name, err)
+	}
+}
+
+func (s *Store) secrets() corev1client.SecretInterface {
+	return s.client().CoreV1().Secrets(constants.SystemNamespace)
+}
+
+func newSecret(reg Registration) *corev1.Secret {
+	data := map[string][]byte{
+		dataNodeName:    []byte(reg.NodeName),
+		dataFingerprint: []byte(reg.Fingerprint),
+		dataCreatedAt:   []byte(reg.CreatedAt.UTC().Format(time.RFC3339Nano)),
+	}
+	if reg.ExpiresAt != nil {
+		data[dataExpiresAt] = []byte(reg.ExpiresAt.UTC().Format(time.RFC3339Nano))
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SecretName(reg.NodeName),
+			Namespace: constants.SystemNamespace,
+			Labels:    map[string]string{SecretLabel: ""},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+func fromSecret(secret *corev1.Secret) (Registration, error) {
+	reg := Registration{
+		NodeName:    string(secret.Data[dataNodeName]),
+		Fingerprint: string(secret.Data[dataFingerprint]),
+	}
+	if reg.NodeName == "" || reg.Fingerprint == "" {
+		return reg, errors.New("nodeName and fingerprint are required")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, string(secret.Data[dataCreatedAt]))
+	if err != nil {
+		return reg, fmt.Errorf("invalid createdAt, err: %v", err)
+	}
+	reg.CreatedAt = createdAt
+	if v, ok := secret.Data[dataExpiresAt]; ok {
+		expiresAt, err := time.Parse(time.RFC3339Nano, string(v))
+		if err != nil {
+			return reg, fmt.Errorf("invalid expiresAt, err: %v", err)
+		}
+		reg.ExpiresAt = &expiresAt
+	}
+	return reg, nil
+}
+
+// PublicKeyFingerprint returns the hex encoded SHA-256 digest of the SubjectPublicKeyInfo.
+func PublicKeyFingerprint(publicKey any) (string, error) {
+	spki, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the public key, err: %v", err)
+	}
+	digest := sha256.Sum256(spki)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// NormalizeFingerprint normalizes the SHA-256 fingerprint, which may be prefixed with
+// "sha256:" and may be separated by colons.
+func NormalizeFingerprint(fp string) (string, error) {
+	fp = strings.ToLower(strings.TrimSpace(fp))
+	fp = strings.TrimPrefix(fp, "sha256:")
+	fp = strings.ReplaceAll(fp, ":", "")
+	if b, err := hex.DecodeString(fp); err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 fingerprint %q", fp)
+	}
+	return fp, nil
+}
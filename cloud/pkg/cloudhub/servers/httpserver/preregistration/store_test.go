@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preregistration
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+func TestNormalizeFingerprint(t *testing.T) {
+	want := strings.Repeat("ab", 32)
+	fp, err := NormalizeFingerprint("SHA256:" + strings.ToUpper(strings.Repeat("ab:", 31)) + "AB")
+	require.NoError(t, err)
+	require.Equal(t, want, fp)
+
+	_, err = NormalizeFingerprint("abcd")
+	require.Error(t, err)
+	_, err = NormalizeFingerprint(strings.Repeat("zz", 32))
+	require.Error(t, err)
+}
+
+func TestStore(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	fp, err := PublicKeyFingerprint(key.Public())
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	now := time.Now()
+	s := NewStore(fake.NewSimpleClientset())
+	ctx := context.TODO()
+	s.now = func() time.Time { return now }
+
+	_, err = s.Add(ctx, Registration{Fingerprint: fp})
+	require.Error(t, err)
+	past := now.Add(-time.Minute)
+	_, err = s.Add(ctx, Registration{NodeName: "node1", Fingerprint: fp, ExpiresAt: &past})
+	require.Error(t, err)
+
+	expiresAt := now.Add(time.Minute)
+	_, err = s.Add(ctx, Registration{NodeName: "node2", Fingerprint: fp, ExpiresAt: &expiresAt})
+	require.NoError(t, err)
+	reg, err := s.Add(ctx, Registration{NodeName: "node1", Fingerprint: fp})
+	require.NoError(t, err)
+	require.Equal(t, now.UTC(), reg.CreatedAt)
+
+	regs, err := s.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, regs, 2)
+	require.Equal(t, "node1", regs[0].NodeName)
+	require.Equal(t, "node2", regs[1].NodeName)
+	require.Equal(t, expiresAt.UTC(), regs[1].ExpiresAt.UTC())
+	has, err := s.Has(ctx, "node1")
+	require.NoError(t, err)
+	require.True(t, has)
+	has, err = s.Has(ctx, "node3")
+	require.NoError(t, err)
+	require.False(t, has)
+
+	_, err = s.Consume(ctx, "node3", key.Public())
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Consume(ctx, "node1", other.Public())
+	require.ErrorIs(t, err, ErrMismatch)
+	reg, err = s.Consume(ctx, "node1", key.Public())
+	require.NoError(t, err)
+	_, err = s.Consume(ctx, "node1", key.Public())
+	require.ErrorIs(t, err, ErrNotFound)
+
+	s.Restore(reg)
+	_, err = s.Consume(ctx, "node1", key.Public())
+	require.NoError(t, err)
+
+	// node2 expires
+	now = now.Add(2 * time.Minute)
+	regs, err = s.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, regs)
+	has, err = s.Has(ctx, "node2")
+	require.NoError(t, err)
+	require.False(t, has)
+	_, err = s.Consume(ctx, "node2", key.Public())
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStoreSharedByReplicas(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	fp, err := PublicKeyFingerprint(key.Public())
+	require.NoError(t, err)
+
+	kubeClient := fake.NewSimpleClientset()
+	ctx := context.TODO()
+	replica1, replica2 := NewStore(kubeClient), NewStore(kubeClient)
+
+	_, err = replica1.Add(ctx, Registration{NodeName: "node1", Fingerprint: fp})
+	require.NoError(t, err)
+	has, err := replica2.Has(ctx, "node1")
+	require.NoError(t, err)
+	require.True(t, has)
+
+	// the registration consumed by a replica can't be consumed by another one
+	_, err = replica2.Consume(ctx, "node1", key.Public())
+	require.NoError(t, err)
+	_, err = replica1.Consume(ctx, "node1", key.Public())
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = kubeClient.CoreV1().Secrets(constants.SystemNamespace).Get(ctx, SecretName("node1"), metav1.GetOptions{})
+	require.True(t, apierrors.IsNotFound(err))
+}
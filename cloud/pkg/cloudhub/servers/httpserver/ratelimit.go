@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/clientip"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// requestLimiter limits the requests of each source by a token bucket, the source is the node
+// name in the NodeName header, or the client IP if the header is absent. The requests are limited
+// before they are authenticated, so that the token brute force and the reconnect loops of the
+// nodes do not reach the signing.
+type requestLimiter struct {
+	limit rate.Limit
+	burst int
+	now   func() time.Time
+
+	mu        sync.Mutex
+	limiters  map[string]*sourceLimiter
+	lastSweep time.Time
+}
+
+type sourceLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newRequestLimiter returns nil if the request rate limit is disabled.
+func newRequestLimiter(c *v1alpha1.CloudHubRequestRateLimit) *requestLimiter {
+	if c == nil || !c.Enable {
+		return nil
+	}
+	return &requestLimiter{
+		limit:    rate.Limit(c.QPS),
+		burst:    int(c.Burst),
+		now:      time.Now,
+		limiters: make(map[string]*sourceLimiter),
+	}
+}
+
+// sourceKey returns the key of the bucket of the request, the node names and the IPs are
+// kept apart so that a node name never shares the bucket of an IP.
+func sourceKey(req *http.Request) string {
+	if nodeName := req.Header.Get(hubconfig.Config.NodeNameHeader()); nodeName != "" {
+		return "node/" + nodeName
+	}
+	return "ip/" + clientip.FromRequest(req)
+}
+
+// reserve takes a token from the bucket of the key, it returns the delay until a token is
+// available if the bucket is empty.
+func (l *requestLimiter) reserve(key string) time.Duration {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	sl, ok := l.limiters[key]
+	if !ok {
+		sl = &sourceLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = sl
+	}
+	sl.lastSeen = now
+	r := sl.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		// the rejected request does not consume the token
+		r.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// sweep drops the buckets which have been refilled since the sources were last seen,
+// they are the same as new ones, so that the buckets do not grow with the sources.
+func (l *requestLimiter) sweep(now time.Time) {
+	refill := time.Duration(float64(l.burst) / float64(l.limit) * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+	l.lastSweep = now
+	for key, sl := range l.limiters {
+		if now.Sub(sl.lastSeen) >= refill {
+			delete(l.limiters, key)
+		}
+	}
+}
+
+// filter rejects the request with 429 if its source exceeds the limit,
+// all the requests are served if the request rate limit is disabled.
+func (l *requestLimiter) filter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if l == nil {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	key := sourceKey(req.Request)
+	delay := l.reserve(key)
+	if delay == 0 {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	klog.Warningf("reject the request %s of %s, client IP: %s, exceeds the rate limit", req.Request.URL.Path, key, clientip.FromRequest(req.Request))
+	resp.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+	resps.ErrorMessage(resp, http.StatusTooManyRequests, fmt.Sprintf("%s: too many requests from %s, please retry later", types.ReasonRateLimited, key))
+}
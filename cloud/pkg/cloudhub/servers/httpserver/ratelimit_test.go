@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestRequestLimiterRoutes(t *testing.T) {
+	ca := hubconfig.Config.Ca
+	hubconfig.Config.Ca = []byte("ca")
+	t.Cleanup(func() { hubconfig.Config.Ca = ca })
+
+	now := time.Unix(1700000000, 0)
+	rl := newRequestLimiter(&v1alpha1.CloudHubRequestRateLimit{Enable: true, QPS: 0.5, Burst: 2})
+	rl.now = func() time.Time { return now }
+	container := restful.NewContainer()
+	container.Add(routes(nil, &drainer{}, nil, nil, rl))
+	getCA := func(remoteAddr, nodeName string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ca.crt", nil)
+		req.RemoteAddr = remoteAddr
+		if nodeName != "" {
+			req.Header.Set(types.HeaderNodeName, nodeName)
+		}
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusOK, getCA("10.0.0.1:1234", "").Code)
+	}
+	resp := getCA("10.0.0.1:1234", "")
+	require.Equal(t, http.StatusTooManyRequests, resp.Code)
+	require.Equal(t, "2", resp.Header().Get("Retry-After"))
+	require.True(t, strings.HasPrefix(resp.Body.String(), types.ReasonRateLimited+":"), resp.Body.String())
+	// the rejected requests do not consume the tokens
+	require.Equal(t, http.StatusTooManyRequests, getCA("10.0.0.1:1234", "").Code)
+
+	// the other sources have their own buckets
+	require.Equal(t, http.StatusOK, getCA("10.0.0.2:1234", "").Code)
+	// the nodes behind the same NAT are told apart by the node name
+	require.Equal(t, http.StatusOK, getCA("10.0.0.1:1234", "node-a").Code)
+	require.Equal(t, http.StatusOK, getCA("10.0.0.1:1234", "node-b").Code)
+
+	now = now.Add(2 * time.Second)
+	require.Equal(t, http.StatusOK, getCA("10.0.0.1:1234", "").Code)
+	require.Equal(t, http.StatusTooManyRequests, getCA("10.0.0.1:1234", "").Code)
+}
+
+func TestRequestLimiterSweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	rl := newRequestLimiter(&v1alpha1.CloudHubRequestRateLimit{Enable: true, QPS: 1, Burst: 2})
+	rl.now = func() time.Time { return now }
+
+	require.Zero(t, rl.reserve("ip/10.0.0.1"))
+	require.Zero(t, rl.reserve("ip/10.0.0.2"))
+	require.Len(t, rl.limiters, 2)
+
+	now = now.Add(time.Second)
+	require.Zero(t, rl.reserve("ip/10.0.0.2"))
+	now = now.Add(time.Second)
+	// the bucket of 10.0.0.1 is refilled, while 10.0.0.2 was seen a second ago
+	require.Zero(t, rl.reserve("ip/10.0.0.3"))
+	require.Len(t, rl.limiters, 2)
+	require.NotContains(t, rl.limiters, "ip/10.0.0.1")
+}
+
+func TestRequestLimiterDisabled(t *testing.T) {
+	require.Nil(t, newRequestLimiter(nil))
+	require.Nil(t, newRequestLimiter(&v1alpha1.CloudHubRequestRateLimit{QPS: 1, Burst: 1}))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resps
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/emicklei/go-restful"
+	"github.com/google/uuid"
+)
+
+const (
+	// MIMEProblemJSON is the media type of the problem details defined by RFC 7807.
+	MIMEProblemJSON = "application/problem+json"
+	// HeaderRequestID is the header of the request ID, which is used as the instance of problems.
+	HeaderRequestID = "X-Request-Id"
+)
+
+// Problem is the problem details of an error response, see RFC 7807.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is the machine-readable reason code that the message is prefixed with, e.g. TokenExpired,
+	// it is empty if the message has no reason code.
+	Code string `json:"code,omitempty"`
+}
+
+// problemWriter marks the response of a client that accepts problem details.
+type problemWriter struct {
+	http.ResponseWriter
+	requestID string
+}
+
+// ProblemFilter makes the error responses problem details if the client accepts
+// application/problem+json. The request ID is taken from the X-Request-Id header,
+// or generated if it is absent.
+func ProblemFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if acceptsProblem(req.Request.Header.Values("Accept")) {
+		requestID := req.Request.Header.Get(HeaderRequestID)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		resp.Header().Set(HeaderRequestID, requestID)
+		resp.ResponseWriter = &problemWriter{ResponseWriter: resp.ResponseWriter, requestID: requestID}
+	}
+	chain.ProcessFilter(req, resp)
+}
+
+func acceptsProblem(accepts []string) bool {
+	for _, accept := range accepts {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(mediaRange)
+			if err == nil && mediaType == MIMEProblemJSON {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (w *problemWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// problemWriterOf returns the problemWriter underlying w if the client accepts problem details,
+// w may be wrapped by the other filters such as VersionFilter.
+func problemWriterOf(w http.ResponseWriter) (*problemWriter, bool) {
+	for w != nil {
+		if pw, ok := w.(*problemWriter); ok {
+			return pw, true
+		}
+		w = unwrapWriter(w)
+	}
+	return nil, false
+}
+
+func newProblem(code int, msg, requestID string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Detail:   msg,
+		Instance: requestID,
+		Code:     reasonCode(msg),
+	}
+}
+
+// reasonCode returns the reason code prefixing the message, which is a CamelCase word followed
+// by a colon, such as "TokenExpired: token validation failure".
+func reasonCode(msg string) string {
+	code, _, ok := strings.Cut(msg, ":")
+	if !ok || code == "" || code[0] < 'A' || code[0] > 'Z' {
+		return ""
+	}
+	for _, c := range code {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return ""
+		}
+	}
+	return code
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resps
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+)
+
+func newProblemContainer() *restful.Container {
+	ws := new(restful.WebService)
+	ws.Path("/")
+	ws.Produces("*/*")
+	ws.Filter(ProblemFilter)
+	ws.Route(ws.GET("/unauthorized").To(func(_ *restful.Request, resp *restful.Response) {
+		ErrorMessage(resp, http.StatusUnauthorized, "token validation failure")
+	}))
+	ws.Route(ws.GET("/expired").To(func(_ *restful.Request, resp *restful.Response) {
+		ErrorMessage(resp, http.StatusUnauthorized, "TokenExpired: token validation failure, err: token is expired")
+	}))
+	ws.Route(ws.GET("/internal").To(func(_ *restful.Request, resp *restful.Response) {
+		Error(resp, 0, errors.New("fail to signCerts"))
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+	return container
+}
+
+func TestErrorMessageProblem(t *testing.T) {
+	container := newProblemContainer()
+	cases := []struct {
+		name       string
+		path       string
+		accept     string
+		requestID  string
+		wantStatus int
+		wantDetail string
+		wantCode   string
+	}{
+		{
+			name:       "unauthorized",
+			path:       "/unauthorized",
+			accept:     "application/problem+json",
+			requestID:  "req-1",
+			wantStatus: http.StatusUnauthorized,
+			wantDetail: "token validation failure",
+		},
+		{
+			name:       "internal server error with generated request ID",
+			path:       "/internal",
+			accept:     "application/json;q=0.9, application/problem+json",
+			wantStatus: http.StatusInternalServerError,
+			wantDetail: "fail to signCerts",
+		},
+		{
+			name:       "reason code",
+			path:       "/expired",
+			accept:     "application/problem+json",
+			wantStatus: http.StatusUnauthorized,
+			wantDetail: "TokenExpired: token validation failure, err: token is expired",
+			wantCode:   "TokenExpired",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, c.path, nil)
+			req.Header.Set("Accept", c.accept)
+			if c.requestID != "" {
+				req.Header.Set(HeaderRequestID, c.requestID)
+			}
+			recorder := httptest.NewRecorder()
+			container.ServeHTTP(recorder, req)
+
+			if recorder.Code != c.wantStatus {
+				t.Fatalf("want status code is %d, actual is %d", c.wantStatus, recorder.Code)
+			}
+			if ct := recorder.Header().Get("Content-Type"); ct != MIMEProblemJSON {
+				t.Fatalf("want content type is %s, actual is %s", MIMEProblemJSON, ct)
+			}
+			var problem map[string]any
+			if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("failed to unmarshal the problem, err: %v", err)
+			}
+			requestID := recorder.Header().Get(HeaderRequestID)
+			if c.requestID != "" && requestID != c.requestID {
+				t.Fatalf("want request ID is %s, actual is %s", c.requestID, requestID)
+			}
+			want := map[string]any{
+				"type":     "about:blank",
+				"title":    http.StatusText(c.wantStatus),
+				"status":   float64(c.wantStatus),
+				"detail":   c.wantDetail,
+				"instance": requestID,
+			}
+			if c.wantCode != "" {
+				want["code"] = c.wantCode
+			}
+			if len(problem) != len(want) {
+				t.Fatalf("want problem is %v, actual is %v", want, problem)
+			}
+			for k, v := range want {
+				if problem[k] != v {
+					t.Fatalf("want %s of the problem is %v, actual is %v", k, v, problem[k])
+				}
+			}
+		})
+	}
+}
+
+func TestErrorMessageWithoutProblem(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/unauthorized", nil)
+	req.Header.Set("Accept", "application/json")
+	recorder := httptest.NewRecorder()
+	newProblemContainer().ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("want status code is %d, actual is %d", http.StatusUnauthorized, recorder.Code)
+	}
+	if body := recorder.Body.String(); body != "token validation failure" {
+		t.Fatalf("want error message is %s, actual is %s", "token validation failure", body)
+	}
+	if recorder.Header().Get(HeaderRequestID) != "" {
+		t.Fatal("want no request ID without problem details")
+	}
+}
+
+func TestReasonCode(t *testing.T) {
+	cases := map[string]string{
+		"QuotaExceeded: the quota of tenant a is exhausted": "QuotaExceeded",
+		"CSRInvalid: invalid CSR, err: asn1":                "CSRInvalid",
+		"failed to sign certs for edgenode a, err: x":       "",
+		"the token of edgenode a: expired":                  "",
+		"no reason code":                                    "",
+		"Token Expired: invalid":                            "",
+	}
+	for msg, want := range cases {
+		if code := reasonCode(msg); code != want {
+			t.Errorf("want reason code of %q is %q, actual is %q", msg, want, code)
+		}
+	}
+}
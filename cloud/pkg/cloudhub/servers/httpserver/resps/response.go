@@ -1,7 +1,9 @@
 package resps
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"k8s.io/klog/v2"
 )
@@ -10,17 +12,31 @@ func Error(w http.ResponseWriter, code int, err error) {
 	ErrorMessage(w, code, err.Error())
 }
 
+// ErrorMessage writes the error message as plain text, or as a problem details document
+// if the client accepts application/problem+json, see ProblemFilter. The reason code that
+// the message is prefixed with is the code member of the problem details.
 func ErrorMessage(w http.ResponseWriter, code int, msg string) {
 	if code == 0 {
 		code = http.StatusInternalServerError
 	}
+	body := []byte(msg)
+	if pw, ok := problemWriterOf(w); ok {
+		if bff, err := json.Marshal(newProblem(code, msg, pw.requestID)); err != nil {
+			klog.Errorf("failed to marshal the problem details, err: %v", err)
+		} else {
+			w.Header().Set("Content-Type", MIMEProblemJSON)
+			body = bff
+		}
+	}
 	w.WriteHeader(code)
-	if _, err := w.Write([]byte(msg)); err != nil {
+	if _, err := w.Write(body); err != nil {
 		klog.Errorf("failed to write a error messge to the response, err: %v", err)
 	}
 }
 
+// OK writes the whole body with the Content-Length, so that a client can detect the truncated body.
 func OK(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(body); err != nil {
 		klog.Errorf("failed to write a payload to the response, err: %v", err)
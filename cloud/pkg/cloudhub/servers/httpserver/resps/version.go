@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resps
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/emicklei/go-restful"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+const (
+	// MediaTypeVersionParam is the parameter of the media ranges of the Accept header which requests
+	// the version of the response body if the types.HeaderAPIVersion is absent, e.g. application/json; version=v2
+	MediaTypeVersionParam = "version"
+
+	// APIVersionV1 is the raw response body, which is the default if no version is requested.
+	APIVersionV1 = "v1"
+	// APIVersionV2 is the response body in JSON with its metadata.
+	APIVersionV2 = "v2"
+)
+
+// versionWriter marks the response of a client that requests a supported version of the response body.
+type versionWriter struct {
+	http.ResponseWriter
+	version string
+}
+
+func (w *versionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// VersionFilter negotiates the version of the response body of the route which supports the versions.
+// The request of an unsupported version is responded 406 Not Acceptable listing the supported versions,
+// and the request without a version is served as today, see OKVersioned.
+func VersionFilter(versions ...string) restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		resp.Header().Add("Vary", types.HeaderAPIVersion)
+		version := RequestedVersion(req.Request)
+		if version == "" {
+			chain.ProcessFilter(req, resp)
+			return
+		}
+		if !slices.Contains(versions, version) {
+			ErrorMessage(resp, http.StatusNotAcceptable, fmt.Sprintf("the API version %s is not supported, supported versions: %s",
+				version, strings.Join(versions, ", ")))
+			return
+		}
+		resp.Header().Set(types.HeaderAPIVersion, version)
+		resp.ResponseWriter = &versionWriter{ResponseWriter: resp.ResponseWriter, version: version}
+		chain.ProcessFilter(req, resp)
+	}
+}
+
+// RequestedVersion returns the version of the response body requested by the types.HeaderAPIVersion, or by
+// the MediaTypeVersionParam of the Accept header. It is empty if no version is requested.
+func RequestedVersion(r *http.Request) string {
+	if version := strings.TrimSpace(r.Header.Get(types.HeaderAPIVersion)); version != "" {
+		return version
+	}
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			_, params, err := mime.ParseMediaType(mediaRange)
+			if err == nil && params[MediaTypeVersionParam] != "" {
+				return params[MediaTypeVersionParam]
+			}
+		}
+	}
+	return ""
+}
+
+// Version returns the version of the response body negotiated by VersionFilter, it is APIVersionV1
+// if the client requests no version.
+func Version(w http.ResponseWriter) string {
+	if vw, ok := versionWriterOf(w); ok {
+		return vw.version
+	}
+	return APIVersionV1
+}
+
+// VersionedBody returns the body of the negotiated version and its content type, which is the v1 body
+// as is, or the v2 representation marshaled in JSON. The content type of the v1 body is empty, so
+// that the one set by the handler is kept.
+func VersionedBody(w http.ResponseWriter, v1 []byte, v2 interface{}) ([]byte, string, error) {
+	if Version(w) != APIVersionV2 {
+		return v1, "", nil
+	}
+	body, err := json.Marshal(v2)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal the %s response, err: %v", APIVersionV2, err)
+	}
+	return body, restful.MIME_JSON, nil
+}
+
+// OKVersioned writes the v1 body as OK does, or the v2 representation in JSON if the client requests
+// APIVersionV2, see VersionFilter.
+func OKVersioned(w http.ResponseWriter, v1 []byte, v2 interface{}) {
+	body, contentType, err := VersionedBody(w, v1, v2)
+	if err != nil {
+		klog.Errorf("failed to write a versioned payload to the response, err: %v", err)
+		Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	OK(w, body)
+}
+
+// versionWriterOf returns the versionWriter underlying w if the client requests a supported version.
+func versionWriterOf(w http.ResponseWriter) (*versionWriter, bool) {
+	for w != nil {
+		if vw, ok := w.(*versionWriter); ok {
+			return vw, true
+		}
+		w = unwrapWriter(w)
+	}
+	return nil, false
+}
+
+// unwrapWriter returns the ResponseWriter wrapped by w, or nil if w wraps none.
+func unwrapWriter(w http.ResponseWriter) http.ResponseWriter {
+	switch w := w.(type) {
+	case *restful.Response:
+		return w.ResponseWriter
+	case interface{ Unwrap() http.ResponseWriter }:
+		return w.Unwrap()
+	}
+	return nil
+}
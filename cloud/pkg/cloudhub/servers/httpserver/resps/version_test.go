@@ -0,0 +1,178 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resps
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful"
+
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func newVersionContainer() *restful.Container {
+	ws := new(restful.WebService)
+	ws.Path("/")
+	ws.Produces("*/*")
+	ws.Filter(ProblemFilter)
+	versioned := VersionFilter(APIVersionV1, APIVersionV2)
+	ws.Route(ws.GET("/data").Filter(versioned).To(func(_ *restful.Request, resp *restful.Response) {
+		resp.Header().Set("Content-Type", "application/octet-stream")
+		OKVersioned(resp, []byte("raw data"), map[string]string{"data": "raw data"})
+	}))
+	ws.Route(ws.GET("/fail").Filter(versioned).To(func(_ *restful.Request, resp *restful.Response) {
+		ErrorMessage(resp, http.StatusBadRequest, "bad request")
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+	return container
+}
+
+func TestOKVersioned(t *testing.T) {
+	container := newVersionContainer()
+	cases := []struct {
+		name            string
+		version         string
+		accept          string
+		wantStatus      int
+		wantVersion     string
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "no version",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/octet-stream",
+			wantBody:        "raw data",
+		},
+		{
+			name:            "v1",
+			version:         APIVersionV1,
+			wantStatus:      http.StatusOK,
+			wantVersion:     APIVersionV1,
+			wantContentType: "application/octet-stream",
+			wantBody:        "raw data",
+		},
+		{
+			name:            "v2",
+			version:         APIVersionV2,
+			wantStatus:      http.StatusOK,
+			wantVersion:     APIVersionV2,
+			wantContentType: restful.MIME_JSON,
+			wantBody:        `{"data":"raw data"}`,
+		},
+		{
+			name:            "v2 by the media type parameter",
+			accept:          "application/json; version=v2",
+			wantStatus:      http.StatusOK,
+			wantVersion:     APIVersionV2,
+			wantContentType: restful.MIME_JSON,
+			wantBody:        `{"data":"raw data"}`,
+		},
+		{
+			name:            "header takes precedence over the media type parameter",
+			version:         APIVersionV1,
+			accept:          "application/json; version=v2",
+			wantStatus:      http.StatusOK,
+			wantVersion:     APIVersionV1,
+			wantContentType: "application/octet-stream",
+			wantBody:        "raw data",
+		},
+		{
+			name:       "unknown version",
+			version:    "v3",
+			wantStatus: http.StatusNotAcceptable,
+			wantBody:   "the API version v3 is not supported, supported versions: v1, v2",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/data", nil)
+			if c.version != "" {
+				req.Header.Set(types.HeaderAPIVersion, c.version)
+			}
+			if c.accept != "" {
+				req.Header.Set("Accept", c.accept)
+			}
+			recorder := httptest.NewRecorder()
+			container.ServeHTTP(recorder, req)
+
+			if recorder.Code != c.wantStatus {
+				t.Fatalf("want status code is %d, actual is %d", c.wantStatus, recorder.Code)
+			}
+			if body := recorder.Body.String(); body != c.wantBody {
+				t.Fatalf("want body is %s, actual is %s", c.wantBody, body)
+			}
+			if version := recorder.Header().Get(types.HeaderAPIVersion); version != c.wantVersion {
+				t.Fatalf("want API version is %q, actual is %q", c.wantVersion, version)
+			}
+			if c.wantContentType != "" && recorder.Header().Get("Content-Type") != c.wantContentType {
+				t.Fatalf("want content type is %s, actual is %s", c.wantContentType, recorder.Header().Get("Content-Type"))
+			}
+			if vary := recorder.Header().Get("Vary"); vary != types.HeaderAPIVersion {
+				t.Fatalf("want Vary is %s, actual is %s", types.HeaderAPIVersion, vary)
+			}
+		})
+	}
+}
+
+func TestVersionFilterProblem(t *testing.T) {
+	container := newVersionContainer()
+	for path, wantStatus := range map[string]int{
+		"/data": http.StatusNotAcceptable,
+		"/fail": http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", MIMEProblemJSON)
+		version := APIVersionV2
+		if wantStatus == http.StatusNotAcceptable {
+			version = "v3"
+		}
+		req.Header.Set(types.HeaderAPIVersion, version)
+		recorder := httptest.NewRecorder()
+		container.ServeHTTP(recorder, req)
+
+		if recorder.Code != wantStatus {
+			t.Fatalf("%s: want status code is %d, actual is %d", path, wantStatus, recorder.Code)
+		}
+		if ct := recorder.Header().Get("Content-Type"); ct != MIMEProblemJSON {
+			t.Fatalf("%s: want content type is %s, actual is %s", path, MIMEProblemJSON, ct)
+		}
+		var problem Problem
+		if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("%s: failed to unmarshal the problem, err: %v", path, err)
+		}
+		if problem.Status != wantStatus {
+			t.Fatalf("%s: want status of the problem is %d, actual is %d", path, wantStatus, problem.Status)
+		}
+	}
+}
+
+func TestVersionWithoutFilter(t *testing.T) {
+	w := new(FakeResponseWriter)
+	if version := Version(w); version != APIVersionV1 {
+		t.Fatalf("want version is %s, actual is %s", APIVersionV1, version)
+	}
+	OKVersioned(w, []byte("raw data"), map[string]string{"data": "raw data"})
+	if string(w.payload) != "raw data" {
+		t.Fatalf("want payload is raw data, actual is %s", string(w.payload))
+	}
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// DataKeyPrefix is the prefix of the keys of the revoked certificates in the data of the ConfigMap,
+// which is followed by the decimal serial number. Every value is a JSON encoded record, so that the
+// replicas of CloudCore update the revocations one by one without overwriting the others.
+const DataKeyPrefix = "serial."
+
+// saveRetryInterval is the interval to retry saving the list after a failure.
+const saveRetryInterval = 10 * time.Second
+
+// record is the latest change of a certificate in the ConfigMap.
+type record struct {
+	Entry
+	// Unrevoked marks the certificate as unrevoked. The record is kept until the certificate
+	// expires, so that a replica with a stale view does not restore the revocation.
+	Unrevoked bool `json:"unrevoked,omitempty"`
+	// UpdatedAt is the time of the change, the latest change of a certificate wins.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// persister keeps the revocation list and the ConfigMap in sync. The changes made on this
+// replica are saved to the ConfigMap, and the changes of the ConfigMap made by the other
+// replicas are applied to the list.
+type persister struct {
+	list       *List
+	configMaps typedcorev1.ConfigMapInterface
+	name       string
+
+	mu sync.Mutex
+	// updated are the times of the latest changes known to this replica, keyed by the serial number
+	updated map[string]time.Time
+	// dirty are the serial numbers changed on this replica and not saved yet
+	dirty map[string]bool
+	// applying are the serial numbers whose changes are being applied from the ConfigMap,
+	// which are not saved back
+	applying map[string]bool
+
+	changed chan struct{}
+}
+
+// Persist restores the revocation list from the ConfigMap, saves every change of the list to the
+// ConfigMap and applies the changes of the ConfigMap made by the other replicas until the ctx is
+// done, so that the revocations survive the restarts and are shared by all the replicas.
+func (l *List) Persist(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	p := &persister{
+		list:       l,
+		configMaps: client.CoreV1().ConfigMaps(namespace),
+		name:       name,
+		updated:    make(map[string]time.Time),
+		dirty:      make(map[string]bool),
+		applying:   make(map[string]bool),
+		changed:    make(chan struct{}, 1),
+	}
+	cm, err := p.configMaps.Get(ctx, name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the ConfigMap %s/%s of the revoked certificates, err: %v", namespace, name, err)
+	}
+	if err == nil {
+		records, err := decodeRecords(cm)
+		if err != nil {
+			return fmt.Errorf("failed to decode the revoked certificates in the ConfigMap %s/%s, err: %v", namespace, name, err)
+		}
+		var entries []Entry
+		for serial, r := range records {
+			p.updated[serial] = r.UpdatedAt
+			if !r.Unrevoked {
+				entries = append(entries, r.Entry)
+			}
+		}
+		l.Load(entries)
+		klog.Infof("restored %d revoked certificates from the ConfigMap %s/%s", len(entries), namespace, name)
+	}
+	// the revocations made before, e.g. by the import, are saved too
+	for _, e := range l.Entries() {
+		p.markDirty(e.Serial)
+	}
+	l.OnRevoke(p.markDirty)
+	l.OnUnrevoke(p.markDirty)
+
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { p.observe(obj.(*corev1.ConfigMap)) },
+		UpdateFunc: func(_, obj interface{}) { p.observe(obj.(*corev1.ConfigMap)) },
+		DeleteFunc: func(interface{}) { p.observeDeletion() },
+	}); err != nil {
+		return fmt.Errorf("failed to watch the ConfigMap %s/%s of the revoked certificates, err: %v", namespace, name, err)
+	}
+	factory.Start(ctx.Done())
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.changed:
+				if err := p.save(ctx); err != nil {
+					klog.Errorf("failed to save the revoked certificates to the ConfigMap %s/%s, err: %v", namespace, name, err)
+					time.AfterFunc(saveRetryInterval, p.notify)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (p *persister) notify() {
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+}
+
+// markDirty records the change of the certificate made on this replica to be saved.
+func (p *persister) markDirty(serial string) {
+	p.mu.Lock()
+	if p.applying[serial] {
+		p.mu.Unlock()
+		return
+	}
+	now := p.list.now()
+	// the change is newer than any change known to this replica
+	if prev := p.updated[serial]; !now.After(prev) {
+		now = prev.Add(time.Nanosecond)
+	}
+	p.updated[serial] = now
+	p.dirty[serial] = true
+	p.mu.Unlock()
+	p.notify()
+}
+
+// observe applies the records in the ConfigMap which are newer than the changes known to this
+// replica, so that an event delivered late does not undo a later change.
+func (p *persister) observe(cm *corev1.ConfigMap) {
+	records, err := decodeRecords(cm)
+	if err != nil {
+		klog.Errorf("failed to decode the revoked certificates in the ConfigMap %s/%s, err: %v", cm.Namespace, cm.Name, err)
+		return
+	}
+	for serial, r := range records {
+		p.mu.Lock()
+		if !r.UpdatedAt.After(p.updated[serial]) {
+			p.mu.Unlock()
+			continue
+		}
+		p.updated[serial] = r.UpdatedAt
+		p.applying[serial] = true
+		p.mu.Unlock()
+
+		_, revoked := p.list.entry(serial)
+		switch {
+		case r.Unrevoked && revoked:
+			p.list.Unrevoke(serial)
+		case !r.Unrevoked && !revoked:
+			p.list.RevokeEntry(r.Entry)
+		}
+
+		p.mu.Lock()
+		delete(p.applying, serial)
+		p.mu.Unlock()
+	}
+}
+
+// observeDeletion writes the whole list back if the ConfigMap is deleted, the revocations are
+// only removed by unrevoking them.
+func (p *persister) observeDeletion() {
+	p.mu.Lock()
+	for _, e := range p.list.Entries() {
+		p.dirty[e.Serial] = true
+	}
+	p.mu.Unlock()
+	p.notify()
+}
+
+// save writes the changed certificates to the ConfigMap unless the ConfigMap holds later changes
+// of them. The other keys are kept as they are except the expired records, which are pruned.
+func (p *persister) save(ctx context.Context) error {
+	p.mu.Lock()
+	dirty := p.dirty
+	p.dirty = make(map[string]bool)
+	p.mu.Unlock()
+	if len(dirty) == 0 {
+		return nil
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := p.configMaps.Get(ctx, p.name, metav1.GetOptions{})
+		notFound := apierrors.IsNotFound(err)
+		if err != nil && !notFound {
+			return err
+		}
+		if notFound {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: p.name}}
+		}
+		records, err := decodeRecords(cm)
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		for serial := range dirty {
+			r, ok := p.localRecord(serial, records[serial])
+			if !ok {
+				continue
+			}
+			data, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			cm.Data[DataKeyPrefix+serial] = string(data)
+		}
+		now := p.list.now()
+		for serial, r := range records {
+			if now.After(r.NotAfter) {
+				delete(cm.Data, DataKeyPrefix+serial)
+				p.mu.Lock()
+				delete(p.updated, serial)
+				p.mu.Unlock()
+			}
+		}
+		if notFound {
+			_, err = p.configMaps.Create(ctx, cm, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// created by another replica in the meantime, merge into the latest one
+				return apierrors.NewConflict(corev1.Resource("configmaps"), p.name, err)
+			}
+			return err
+		}
+		_, err = p.configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		p.mu.Lock()
+		// saved again at the retry
+		for serial := range dirty {
+			p.dirty[serial] = true
+		}
+		p.mu.Unlock()
+	}
+	return err
+}
+
+// localRecord returns the record of the certificate on this replica to be saved over the saved
+// record, ok is false if the saved one is up to date or later.
+func (p *persister) localRecord(serial string, saved record) (r record, ok bool) {
+	p.mu.Lock()
+	updated := p.updated[serial]
+	p.mu.Unlock()
+	if !updated.After(saved.UpdatedAt) {
+		return record{}, false
+	}
+	if e, revoked := p.list.entry(serial); revoked {
+		return record{Entry: e, UpdatedAt: updated}, true
+	}
+	// nothing to unrevoke if the revocation is not saved
+	if saved.Serial == "" || saved.Unrevoked {
+		return record{}, false
+	}
+	return record{Entry: Entry{Serial: serial, NotAfter: saved.NotAfter}, Unrevoked: true, UpdatedAt: updated}, true
+}
+
+// decodeRecords returns the records in the ConfigMap keyed by the serial number.
+func decodeRecords(cm *corev1.ConfigMap) (map[string]record, error) {
+	records := make(map[string]record)
+	for key, value := range cm.Data {
+		serial, ok := strings.CutPrefix(key, DataKeyPrefix)
+		if !ok {
+			continue
+		}
+		var r record
+		if err := json.Unmarshal([]byte(value), &r); err != nil {
+			return nil, fmt.Errorf("invalid record %s, err: %v", key, err)
+		}
+		r.Serial = serial
+		records[serial] = r
+	}
+	return records, nil
+}
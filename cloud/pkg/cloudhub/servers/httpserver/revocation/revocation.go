@@ -0,0 +1,245 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"crypto/x509"
+	"sort"
+	"sync"
+	"time"
+)
+
+// List is an in-memory list of the revoked edge certificates, keyed by the serial number.
+// An entry is kept until the certificate expires.
+type List struct {
+	mu      sync.RWMutex
+	serials map[string]revokedCert
+	now     func() time.Time
+	// scheduled are the certificates to be revoked at the time, keyed by the serial number
+	scheduled map[string]scheduledRevocation
+
+	handlers []func(serial string)
+	// unrevokeHandlers are called with the serial number of every certificate removed from the list
+	unrevokeHandlers []func(serial string)
+}
+
+// Entry is a revoked certificate in the list.
+type Entry struct {
+	// Serial is the decimal serial number of the certificate.
+	Serial string `json:"serial"`
+	// NotAfter is the expiration of the certificate, the entry is pruned after it.
+	NotAfter time.Time `json:"notAfter"`
+	// RevokedAt is when the certificate was revoked, it is zero for the entries persisted
+	// before it was recorded.
+	RevokedAt time.Time `json:"revokedAt"`
+}
+
+type revokedCert struct {
+	notAfter  time.Time
+	revokedAt time.Time
+}
+
+type scheduledRevocation struct {
+	at       time.Time
+	notAfter time.Time
+}
+
+// DefaultList is the revocation list checked by the https server and the cloudhub servers.
+var DefaultList = NewList()
+
+func NewList() *List {
+	return &List{
+		serials:   make(map[string]revokedCert),
+		now:       time.Now,
+		scheduled: make(map[string]scheduledRevocation),
+	}
+}
+
+// Revoke adds the certificate to the revocation list.
+func (l *List) Revoke(cert *x509.Certificate) {
+	l.RevokeSerial(cert.SerialNumber.String(), cert.NotAfter)
+}
+
+// RevokeSerial adds the certificate with the decimal serial number to the revocation list,
+// which is used when only the record of the certificate is known, e.g. an imported certificate.
+func (l *List) RevokeSerial(serial string, notAfter time.Time) {
+	l.mu.Lock()
+	revokedAt := l.now()
+	// the scheduled revocation takes effect at the time it is scheduled at
+	if r, ok := l.scheduled[serial]; ok && r.at.Before(revokedAt) {
+		revokedAt = r.at
+	}
+	handlers := l.addLocked(serial, notAfter, revokedAt)
+	l.mu.Unlock()
+
+	for _, h := range handlers {
+		h(serial)
+	}
+}
+
+// RevokeEntry adds the entry to the revocation list and calls the handlers, which is used
+// to apply the revocations made by the other replicas. The expired entry is ignored.
+func (l *List) RevokeEntry(e Entry) {
+	l.mu.Lock()
+	if l.now().After(e.NotAfter) {
+		l.mu.Unlock()
+		return
+	}
+	handlers := l.addLocked(e.Serial, e.NotAfter, e.RevokedAt)
+	l.mu.Unlock()
+
+	for _, h := range handlers {
+		h(e.Serial)
+	}
+}
+
+// addLocked adds the certificate to the list and prunes the expired ones, it returns the
+// handlers to be called after the lock is released.
+func (l *List) addLocked(serial string, notAfter, revokedAt time.Time) []func(serial string) {
+	now := l.now()
+	for s, r := range l.serials {
+		if now.After(r.notAfter) {
+			delete(l.serials, s)
+		}
+	}
+	l.serials[serial] = revokedCert{notAfter: notAfter, revokedAt: revokedAt}
+	delete(l.scheduled, serial)
+	return l.handlers
+}
+
+// OnRevoke registers a handler which is called with the serial number of every revoked certificate.
+func (l *List) OnRevoke(h func(serial string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers = append(l.handlers, h)
+}
+
+// IsRevoked returns whether the certificate is revoked.
+func (l *List) IsRevoked(cert *x509.Certificate) bool {
+	return l.IsRevokedSerial(cert.SerialNumber.String())
+}
+
+// IsRevokedSerial returns whether the certificate with the decimal serial number is revoked.
+func (l *List) IsRevokedSerial(serial string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if _, ok := l.serials[serial]; ok {
+		return true
+	}
+	// the scheduled revocation takes effect at the time even if it is not run yet
+	r, ok := l.scheduled[serial]
+	return ok && !l.now().Before(r.at)
+}
+
+// RevokedAt returns when the certificate with the decimal serial number was revoked, ok is false
+// if it is not revoked. The time is zero if it is unknown, e.g. restored from an old snapshot.
+func (l *List) RevokedAt(serial string) (revokedAt time.Time, ok bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if r, ok := l.serials[serial]; ok {
+		return r.revokedAt, true
+	}
+	if r, ok := l.scheduled[serial]; ok && !l.now().Before(r.at) {
+		return r.at, true
+	}
+	return time.Time{}, false
+}
+
+// RevokeSerialAfter revokes the certificate with the decimal serial number after the delay,
+// the certificate is accepted until then.
+func (l *List) RevokeSerialAfter(serial string, notAfter time.Time, delay time.Duration) {
+	l.mu.Lock()
+	l.scheduled[serial] = scheduledRevocation{at: l.now().Add(delay), notAfter: notAfter}
+	l.mu.Unlock()
+
+	time.AfterFunc(delay, func() {
+		l.mu.RLock()
+		_, ok := l.scheduled[serial]
+		l.mu.RUnlock()
+		if ok {
+			l.RevokeSerial(serial, notAfter)
+		}
+	})
+}
+
+// Unrevoke removes the certificate with the decimal serial number from the revocation list,
+// along with its scheduled revocation. It returns false if the certificate is not revoked.
+func (l *List) Unrevoke(serial string) bool {
+	l.mu.Lock()
+	_, revoked := l.serials[serial]
+	_, scheduled := l.scheduled[serial]
+	delete(l.serials, serial)
+	delete(l.scheduled, serial)
+	handlers := l.unrevokeHandlers
+	l.mu.Unlock()
+	if !revoked && !scheduled {
+		return false
+	}
+	for _, h := range handlers {
+		h(serial)
+	}
+	return true
+}
+
+// OnUnrevoke registers a handler which is called with the serial number of every certificate
+// removed from the revocation list.
+func (l *List) OnUnrevoke(h func(serial string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.unrevokeHandlers = append(l.unrevokeHandlers, h)
+}
+
+// Entries returns the revoked and unexpired certificates sorted by the serial numbers,
+// the scheduled revocations are not included until they take effect.
+func (l *List) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	now := l.now()
+	entries := make([]Entry, 0, len(l.serials))
+	for serial, r := range l.serials {
+		if !now.After(r.notAfter) {
+			entries = append(entries, Entry{Serial: serial, NotAfter: r.notAfter, RevokedAt: r.revokedAt})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Serial < entries[j].Serial })
+	return entries
+}
+
+// entry returns the entry of the revoked certificate with the decimal serial number,
+// ok is false if it is not revoked or expired.
+func (l *List) entry(serial string) (e Entry, ok bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	r, ok := l.serials[serial]
+	if !ok || l.now().After(r.notAfter) {
+		return Entry{}, false
+	}
+	return Entry{Serial: serial, NotAfter: r.notAfter, RevokedAt: r.revokedAt}, true
+}
+
+// Load adds the entries to the revocation list without calling the handlers, which is used
+// to restore the persisted list.
+func (l *List) Load(entries []Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	for _, e := range entries {
+		if !now.After(e.NotAfter) {
+			l.serials[e.Serial] = revokedCert{notAfter: e.NotAfter, revokedAt: e.RevokedAt}
+		}
+	}
+}
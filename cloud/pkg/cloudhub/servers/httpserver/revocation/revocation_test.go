@@ -0,0 +1,262 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+)
+
+func TestList(t *testing.T) {
+	now := time.Now()
+	l := NewList()
+	l.now = func() time.Time { return now }
+
+	cert1 := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: now.Add(time.Hour)}
+	cert2 := &x509.Certificate{SerialNumber: big.NewInt(2), NotAfter: now.Add(2 * time.Hour)}
+	require.False(t, l.IsRevoked(cert1))
+
+	l.Revoke(cert1)
+	require.True(t, l.IsRevoked(cert1))
+	require.False(t, l.IsRevoked(cert2))
+
+	// the expired cert1 is pruned
+	now = now.Add(90 * time.Minute)
+	l.Revoke(cert2)
+	require.False(t, l.IsRevoked(cert1))
+	require.True(t, l.IsRevoked(cert2))
+}
+
+func TestOnRevoke(t *testing.T) {
+	l := NewList()
+	var revoked []string
+	l.OnRevoke(func(serial string) { revoked = append(revoked, serial) })
+
+	l.Revoke(&x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour)})
+	l.RevokeSerial("2", time.Now().Add(time.Hour))
+	require.Equal(t, []string{"1", "2"}, revoked)
+}
+
+func TestRevokeSerialAfter(t *testing.T) {
+	now := time.Now()
+	l := NewList()
+	l.now = func() time.Time { return now }
+	revoked := make(chan string, 1)
+	l.OnRevoke(func(serial string) { revoked <- serial })
+
+	l.RevokeSerialAfter("1", now.Add(time.Hour), 50*time.Millisecond)
+	require.False(t, l.IsRevokedSerial("1"))
+	// the revocation takes effect at the time before it is run
+	now = now.Add(50 * time.Millisecond)
+	require.True(t, l.IsRevokedSerial("1"))
+
+	select {
+	case serial := <-revoked:
+		require.Equal(t, "1", serial)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the scheduled revocation is not run")
+	}
+	require.True(t, l.IsRevokedSerial("1"))
+}
+
+func TestUnrevoke(t *testing.T) {
+	now := time.Now()
+	l := NewList()
+	l.now = func() time.Time { return now }
+	var unrevoked []string
+	l.OnUnrevoke(func(serial string) { unrevoked = append(unrevoked, serial) })
+
+	l.RevokeSerial("1", now.Add(time.Hour))
+	l.RevokeSerialAfter("2", now.Add(time.Hour), time.Hour)
+	require.True(t, l.Unrevoke("1"))
+	require.True(t, l.Unrevoke("2"))
+	require.False(t, l.Unrevoke("3"))
+	require.False(t, l.IsRevokedSerial("1"))
+	// the scheduled revocation is cancelled
+	now = now.Add(time.Hour)
+	require.False(t, l.IsRevokedSerial("2"))
+	require.Equal(t, []string{"1", "2"}, unrevoked)
+}
+
+func TestEntriesAndLoad(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewList()
+	l.now = func() time.Time { return now }
+	l.RevokeSerial("2", now.Add(2*time.Hour))
+	l.RevokeSerial("1", now.Add(time.Hour))
+	l.RevokeSerialAfter("3", now.Add(time.Hour), time.Hour)
+	entries := l.Entries()
+	require.Equal(t, []Entry{
+		{Serial: "1", NotAfter: now.Add(time.Hour), RevokedAt: now},
+		{Serial: "2", NotAfter: now.Add(2 * time.Hour), RevokedAt: now},
+	}, entries)
+
+	restored := NewList()
+	restored.now = l.now
+	var revoked []string
+	restored.OnRevoke(func(serial string) { revoked = append(revoked, serial) })
+	restored.Load(append(entries, Entry{Serial: "4", NotAfter: now.Add(-time.Hour)}))
+	require.Equal(t, entries, restored.Entries())
+	// the expired entry is not loaded, and the handlers are not called
+	require.False(t, restored.IsRevokedSerial("4"))
+	require.Empty(t, revoked)
+}
+
+func TestRevokedAt(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewList()
+	l.now = func() time.Time { return now }
+	l.RevokeSerial("1", now.Add(time.Hour))
+	l.RevokeSerialAfter("2", now.Add(time.Hour), time.Hour)
+
+	revokedAt, ok := l.RevokedAt("1")
+	require.True(t, ok)
+	require.Equal(t, now, revokedAt)
+	_, ok = l.RevokedAt("2")
+	require.False(t, ok)
+	_, ok = l.RevokedAt("3")
+	require.False(t, ok)
+
+	// the scheduled revocation is revoked at the time it is scheduled at
+	scheduledAt := now.Add(time.Hour)
+	now = now.Add(90 * time.Minute)
+	revokedAt, ok = l.RevokedAt("2")
+	require.True(t, ok)
+	require.Equal(t, scheduledAt, revokedAt)
+	l.RevokeSerial("2", now.Add(time.Hour))
+	revokedAt, ok = l.RevokedAt("2")
+	require.True(t, ok)
+	require.Equal(t, scheduledAt, revokedAt)
+}
+
+func TestPersist(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notAfter := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	client := fake.NewSimpleClientset()
+	saved := func() []Entry {
+		cm, err := client.CoreV1().ConfigMaps("kubeedge").Get(ctx, "revoked", metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		records, err := decodeRecords(cm)
+		require.NoError(t, err)
+		saved := make([]Entry, 0, len(records))
+		for _, r := range records {
+			if !r.Unrevoked {
+				saved = append(saved, r.Entry)
+			}
+		}
+		sort.Slice(saved, func(i, j int) bool { return saved[i].Serial < saved[j].Serial })
+		return saved
+	}
+
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+	l := NewList()
+	l.now = func() time.Time { return revokedAt }
+	require.NoError(t, l.Persist(ctx, client, "kubeedge", "revoked"))
+	l.RevokeSerial("1", notAfter)
+	l.RevokeSerial("2", notAfter)
+	want := []Entry{{Serial: "1", NotAfter: notAfter, RevokedAt: revokedAt}, {Serial: "2", NotAfter: notAfter, RevokedAt: revokedAt}}
+	require.Eventually(t, func() bool { return reflect.DeepEqual(want, saved()) }, 5*time.Second, 10*time.Millisecond)
+	l.Unrevoke("1")
+	want = want[1:]
+	require.Eventually(t, func() bool { return reflect.DeepEqual(want, saved()) }, 5*time.Second, 10*time.Millisecond)
+
+	// the list is restored after a restart
+	restarted := NewList()
+	require.NoError(t, restarted.Persist(ctx, client, "kubeedge", "revoked"))
+	require.False(t, restarted.IsRevokedSerial("1"))
+	require.True(t, restarted.IsRevokedSerial("2"))
+}
+
+func TestPersistSharedByReplicas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notAfter := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	client, waitForWatches := testutil.NewClientset(t)
+	first, second := NewList(), NewList()
+	require.NoError(t, first.Persist(ctx, client, "kubeedge", "revoked"))
+	require.NoError(t, second.Persist(ctx, client, "kubeedge", "revoked"))
+	waitForWatches(2)
+	var mu sync.Mutex
+	var revokedOnSecond []string
+	second.OnRevoke(func(serial string) {
+		mu.Lock()
+		defer mu.Unlock()
+		revokedOnSecond = append(revokedOnSecond, serial)
+	})
+
+	// the revocations made on both replicas are kept
+	first.RevokeSerial("1", notAfter)
+	second.RevokeSerial("2", notAfter)
+	require.Eventually(t, func() bool {
+		return first.IsRevokedSerial("2") && second.IsRevokedSerial("1")
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		cm, err := client.CoreV1().ConfigMaps("kubeedge").Get(ctx, "revoked", metav1.GetOptions{})
+		return err == nil && len(cm.Data) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	require.ElementsMatch(t, []string{"1", "2"}, revokedOnSecond)
+	mu.Unlock()
+
+	// the time of the revocation is shared, e.g. for the OCSP responses
+	want, _ := first.RevokedAt("1")
+	got, ok := second.RevokedAt("1")
+	require.True(t, ok)
+	require.True(t, want.Equal(got))
+
+	configMaps := client.CoreV1().ConfigMaps("kubeedge")
+	stale, err := configMaps.Get(ctx, "revoked", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.True(t, second.Unrevoke("1"))
+	require.Eventually(t, func() bool { return !first.IsRevokedSerial("1") }, 5*time.Second, 10*time.Millisecond)
+	require.True(t, first.IsRevokedSerial("2"))
+
+	// a stale view of the ConfigMap does not restore the revocation
+	cm, err := configMaps.Get(ctx, "revoked", metav1.GetOptions{})
+	require.NoError(t, err)
+	cm.Data = stale.Data
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	second.RevokeSerial("3", notAfter)
+	require.Eventually(t, func() bool { return first.IsRevokedSerial("3") }, 5*time.Second, 10*time.Millisecond)
+	require.False(t, first.IsRevokedSerial("1"))
+	require.False(t, second.IsRevokedSerial("1"))
+
+	// the list is written back if the ConfigMap is deleted
+	require.NoError(t, configMaps.Delete(ctx, "revoked", metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool {
+		cm, err := configMaps.Get(ctx, "revoked", metav1.GetOptions{})
+		return err == nil && cm.Data[DataKeyPrefix+"2"] != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	require.True(t, first.IsRevokedSerial("2"))
+	require.True(t, second.IsRevokedSerial("2"))
+}
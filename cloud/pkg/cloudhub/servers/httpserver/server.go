@@ -16,52 +16,138 @@ limitations under the License.
 package httpserver
 
 import (
+	"context"
 	"crypto/tls"
-	"encoding/pem"
 	"fmt"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/emicklei/go-restful"
-	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/registry"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/certreload"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/admin"
 	certshandler "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certificate"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certinfo"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/clientip"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/issuancelog"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/node"
 	nodetaskhandler "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/nodetask"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/ocsp"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/preregistration"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/pkg/features"
 )
 
-// StartHTTPServer starts the http service
-func StartHTTPServer() error {
+// StartHTTPServer starts the http service, and shuts it down gracefully when the ctx is done
+func StartHTTPServer(ctx context.Context) error {
+	trusted, err := clientip.ParseTrustedProxies(hubconfig.Config.HTTPS.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("failed to parse the trusted proxies, err: %v", err)
+	}
+	var cf *clientip.CertFilter
+	if f := hubconfig.Config.HTTPS.ForwardedClientCert; f != nil && f.Enable {
+		verify := certshandler.VerifyForwardedCerts
+		if f.InsecureSkipVerify {
+			klog.Warning("the forwarded client certificates are accepted without verification")
+			verify = nil
+		}
+		cf = clientip.NewCertFilter(trusted, hubconfig.Config.HTTPS.ForwardedCertHeader,
+			f.Precedence == v1alpha1.ClientCertPreferForwarded, clientip.CertFormat(f.Format), verify)
+	}
+	d := &drainer{}
+	sb, err := startStandby(ctx, client.GetKubeClient())
+	if err != nil {
+		return fmt.Errorf("failed to start the standby mode, err: %v", err)
+	}
 	serverContainer := restful.NewContainer()
-	serverContainer.Add(routes())
+	rl := newRequestLimiter(hubconfig.Config.HTTPS.RequestRateLimit)
+	serverContainer.Add(routes(trusted, d, sb, cf, rl))
 	addr := fmt.Sprintf("%s:%d", hubconfig.Config.HTTPS.Address, hubconfig.Config.HTTPS.Port)
-	cert, err := tls.X509KeyPair(
-		pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: hubconfig.Config.Cert}),
-		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: hubconfig.Config.Key}),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create a x509 tls certificate")
+	// the server certificate is reloaded by the new handshakes after its files change
+	server := &http.Server{
+		Addr:      addr,
+		Handler:   serverContainer,
+		TLSConfig: newTLSConfig(),
 	}
+	drainTimeout := defaultDrainTimeout
+	if t := hubconfig.Config.HTTPS.DrainTimeout; t > 0 {
+		drainTimeout = time.Duration(t) * time.Second
+	}
+	if u := hubconfig.Config.HTTPS.UnixSocket; u != nil && u.Enable {
+		listener, err := listenUnixSocket(u.Path, os.FileMode(u.Mode))
+		if err != nil {
+			return err
+		}
+		go serveUnixSocket(ctx, listener, u.Path, serverContainer, d, drainTimeout)
+	}
+	return serve(ctx, server, d, drainTimeout, func() error {
+		return server.ListenAndServeTLS("", "")
+	})
+}
 
-	server := &http.Server{
-		Addr:    addr,
-		Handler: serverContainer,
-		TLSConfig: &tls.Config{
-			Certificates: []tls.Certificate{cert},
-			ClientAuth:   tls.RequestClientCert,
-		},
+// newTLSConfig returns the TLS config of the https server, the client certificates
+// are requested but not required.
+func newTLSConfig() *tls.Config {
+	config := &tls.Config{
+		GetCertificate: certreload.ServerCert.GetCertificate,
+		ClientAuth:     tls.RequestClientCert,
 	}
-	return server.ListenAndServeTLS("", "")
+	hubconfig.Config.ApplyTLSOptions(config)
+	return config
 }
 
-func routes() *restful.WebService {
+// routes returns the web service of the https server, the signing requests are redirected
+// to the leader if sb is a standby replica. The forwarded client certificates are applied
+// by cf if it is not nil, and the certificate requests of each source are limited by rl if
+// it is not nil.
+func routes(trusted clientip.TrustedProxies, d *drainer, sb *standby, cf *clientip.CertFilter, rl *requestLimiter) *restful.WebService {
 	ws := new(restful.WebService)
 	ws.Path("/")
-	ws.Route(ws.GET(constants.DefaultCertURL).To(certshandler.EdgeCoreClientCert))
-	ws.Route(ws.GET(constants.DefaultCAURL).To(certshandler.GetCA))
+	// the handlers write raw bodies, the routes must not be rejected by the Accept header,
+	// e.g. application/problem+json
+	ws.Produces("*/*")
+	ws.Filter(resps.ProblemFilter)
+	ws.Filter(d.filter)
+	ws.Filter(clientip.NewFilter(trusted))
+	ws.Filter(cf.FilterCert)
+	// the certificate routes respond the raw bodies unless the client requests the API version v2
+	versioned := resps.VersionFilter(resps.APIVersionV1, resps.APIVersionV2)
+	ws.Route(ws.GET(constants.DefaultCertURL).Filter(versioned).Filter(rl.filter).Filter(sb.filter).To(certshandler.EdgeCoreClientCert))
+	ws.Route(ws.GET(constants.DefaultCertResultURL).Filter(versioned).Filter(sb.filter).To(certshandler.GetCertResult))
+	ws.Route(ws.GET(constants.DefaultCAURL).Filter(versioned).Filter(rl.filter).To(certshandler.GetCA))
 	ws.Route(ws.GET(constants.DefaultCheckNodeURL).To(node.CheckNode))
+	ws.Route(ws.GET(constants.DefaultNodeCertInfoURL).Filter(admin.Filter).To(certinfo.GetNodeCertInfo))
 	ws.Route(ws.POST(constants.DefaultNodeUpgradeURL).To(nodetaskhandler.UpgradeEdge))
 	ws.Route(ws.POST(constants.DefaultTaskStateReportURL).To(nodetaskhandler.ReportStatus))
+	ws.Route(ws.GET(constants.DefaultIssuanceLogURL).Filter(admin.Filter).To(issuancelog.GetIssuanceLog))
+	ws.Route(ws.GET(constants.DefaultIssuanceLogNodeURL).Filter(admin.Filter).To(issuancelog.ListNodeCertificates))
+	ws.Route(ws.POST(constants.DefaultCertImportURL).Filter(admin.Filter).To(issuancelog.ImportCertificates))
+	ws.Route(ws.POST(constants.DefaultCertPruneURL).Filter(admin.Filter).To(issuancelog.PruneCertificates))
+	ws.Route(ws.GET(constants.DefaultCertFreezeURL).Filter(admin.Filter).To(certshandler.GetSigningFreeze))
+	ws.Route(ws.POST(constants.DefaultCertFreezeURL).Filter(admin.Filter).To(certshandler.FreezeSigning))
+	ws.Route(ws.POST(constants.DefaultCertUnfreezeURL).Filter(admin.Filter).To(certshandler.UnfreezeSigning))
+	ws.Route(ws.GET(constants.DefaultRevocationURL).Filter(admin.Filter).To(issuancelog.ListRevocations))
+	ws.Route(ws.POST(constants.DefaultRevocationURL).Filter(admin.Filter).To(issuancelog.RevokeCertificate))
+	ws.Route(ws.DELETE(constants.DefaultRevocationItemURL).Filter(admin.Filter).To(issuancelog.UnrevokeCertificate))
+	ws.Route(ws.POST(constants.DefaultNodeTokenURL).Filter(admin.Filter).To(certshandler.MintNodeToken))
+	ws.Route(ws.GET(constants.DefaultPreRegistrationURL).Filter(admin.Filter).To(preregistration.ListRegistrations))
+	ws.Route(ws.POST(constants.DefaultPreRegistrationURL).Filter(admin.Filter).To(preregistration.CreateRegistration))
+	if o := hubconfig.Config.OCSP; o != nil && o.Enable {
+		ws.Route(ws.POST(constants.DefaultOCSPURL).To(ocsp.HandleOCSP))
+		ws.Route(ws.GET(constants.DefaultOCSPRequestURL).To(ocsp.HandleOCSP))
+	}
+	if features.DefaultFeatureGate.Enabled(features.CloudHubSessionRegistry) {
+		ws.Route(ws.POST(constants.DefaultSessionForwardURL).To(registry.HandleForward))
+	}
+	if h := hubconfig.Config.HTTPS; h != nil && h.DebugCertInfo {
+		ws.Route(ws.GET(constants.DefaultDebugCertInfoURL).Filter(rl.filter).To(certshandler.GetDebugCertInfo))
+	}
 	return ws
 }
@@ -0,0 +1,43 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"crypto/tls"
+	"reflect"
+	"testing"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	minVersion, cipherSuites := hubconfig.Config.MinTLSVersion, hubconfig.Config.CipherSuites
+	t.Cleanup(func() { hubconfig.Config.MinTLSVersion, hubconfig.Config.CipherSuites = minVersion, cipherSuites })
+
+	hubconfig.Config.MinTLSVersion = tls.VersionTLS13
+	hubconfig.Config.CipherSuites = []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}
+	config := newTLSConfig()
+	if config.MinVersion != tls.VersionTLS13 {
+		t.Errorf("expected the min version TLS 1.3, got %x", config.MinVersion)
+	}
+	if !reflect.DeepEqual(config.CipherSuites, hubconfig.Config.CipherSuites) {
+		t.Errorf("expected the configured cipher suites, got %v", config.CipherSuites)
+	}
+	if config.ClientAuth != tls.RequestClientCert {
+		t.Errorf("expected the client certificates requested, got %v", config.ClientAuth)
+	}
+}
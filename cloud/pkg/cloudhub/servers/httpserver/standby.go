@@ -0,0 +1,223 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/resps"
+)
+
+const (
+	// LeaderIdentityAnnotation and LeaderAddressAnnotation are the annotations of the signing lease
+	// which publish the identity and the advertised HTTPS address of the leader. The address is
+	// only trusted when the identity matches the holder of the lease.
+	LeaderIdentityAnnotation = "cloudhub.kubeedge.io/leader-identity"
+	LeaderAddressAnnotation  = "cloudhub.kubeedge.io/leader-address"
+
+	// standbyRetryAfterSeconds is the value of Retry-After header when the leader is unknown.
+	standbyRetryAfterSeconds = "5"
+)
+
+// standby tracks the holder of the signing lease. Only the leader signs the edge certificates,
+// the other replicas redirect the signing requests to it.
+type standby struct {
+	identity string
+	now      func() time.Time
+
+	mu    sync.RWMutex
+	lease *coordinationv1.Lease
+}
+
+func newStandby(identity string) *standby {
+	return &standby{identity: identity, now: time.Now}
+}
+
+// observe records the latest signing lease, the lease is nil if it is deleted.
+func (s *standby) observe(lease *coordinationv1.Lease) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lease = lease
+}
+
+// leader returns whether this replica holds the signing lease, and the address of the leader if
+// another replica holds it. The address is empty if the lease expires or the leader has not
+// published its address yet.
+func (s *standby) leader() (bool, string) {
+	s.mu.RLock()
+	lease := s.lease
+	s.mu.RUnlock()
+	if lease == nil || lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil ||
+		lease.Spec.LeaseDurationSeconds == nil {
+		return false, ""
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	if !s.now().Before(expiry) {
+		return false, ""
+	}
+	holder := *lease.Spec.HolderIdentity
+	if holder == s.identity {
+		return true, ""
+	}
+	if lease.Annotations[LeaderIdentityAnnotation] != holder {
+		return false, ""
+	}
+	return false, lease.Annotations[LeaderAddressAnnotation]
+}
+
+// filter redirects the signing requests to the leader if this replica is a standby,
+// all the requests are served if the standby mode is disabled.
+func (s *standby) filter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if s == nil {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	isLeader, address := s.leader()
+	if isLeader {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	if address == "" {
+		resp.Header().Set("Retry-After", standbyRetryAfterSeconds)
+		resps.ErrorMessage(resp, http.StatusServiceUnavailable, "the leader of the signing is unknown, please retry later")
+		return
+	}
+	// 307 keeps the method and the body of the request
+	target := url.URL{
+		Scheme:   "https",
+		Host:     address,
+		Path:     req.Request.URL.Path,
+		RawQuery: req.Request.URL.RawQuery,
+	}
+	http.Redirect(resp.ResponseWriter, req.Request, target.String(), http.StatusTemporaryRedirect)
+}
+
+// startStandby starts the leader election of the signing lease, and watches the lease
+// to track the leader. It returns nil if the standby mode is disabled.
+func startStandby(ctx context.Context, kubeClient kubernetes.Interface) (*standby, error) {
+	c := hubconfig.Config.Standby
+	if c == nil || !c.Enable {
+		return nil, nil
+	}
+	if len(hubconfig.Config.AdvertiseAddress) == 0 {
+		return nil, fmt.Errorf("advertiseAddress is required by the standby mode")
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the hostname, err: %v", err)
+	}
+	s := newStandby(hostname + "_" + string(uuid.NewUUID()))
+	address := net.JoinHostPort(hubconfig.Config.AdvertiseAddress[0], strconv.Itoa(int(hubconfig.Config.HTTPS.Port)))
+
+	elector, err := newSigningElector(kubeClient, c, s.identity, address)
+	if err != nil {
+		return nil, err
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+		informers.WithNamespace(c.LeaseNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", c.LeaseName).String()
+		}))
+	informer := factory.Coordination().V1().Leases().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { s.observe(obj.(*coordinationv1.Lease)) },
+		UpdateFunc: func(_, obj interface{}) { s.observe(obj.(*coordinationv1.Lease)) },
+		DeleteFunc: func(interface{}) { s.observe(nil) },
+	}); err != nil {
+		return nil, fmt.Errorf("failed to watch the signing lease, err: %v", err)
+	}
+	factory.Start(ctx.Done())
+	// the replica becomes a candidate again after losing the leadership
+	go wait.UntilWithContext(ctx, elector.Run, 0)
+	return s, nil
+}
+
+func newSigningElector(kubeClient kubernetes.Interface, c *v1alpha1.CloudHubStandby,
+	identity, address string) (*leaderelection.LeaderElector, error) {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: c.LeaseNamespace,
+			Name:      c.LeaseName,
+		},
+		Client:     kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   time.Duration(c.LeaseDuration) * time.Second,
+		RenewDeadline:   time.Duration(c.RenewDeadline) * time.Second,
+		RetryPeriod:     time.Duration(c.RetryPeriod) * time.Second,
+		ReleaseOnCancel: true,
+		Name:            c.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				klog.Infof("became the leader of the signing, advertised address: %s", address)
+				if err := publishLeaderAddress(ctx, kubeClient, c.LeaseNamespace, c.LeaseName, identity, address); err != nil {
+					klog.Errorf("failed to publish the address of the leader, err: %v", err)
+				}
+			},
+			OnStoppedLeading: func() {
+				klog.Info("stopped leading the signing")
+			},
+			OnNewLeader: func(leader string) {
+				klog.Infof("the leader of the signing is %s", leader)
+			},
+		},
+	})
+}
+
+// publishLeaderAddress publishes the identity and the address of the leader on the signing lease.
+func publishLeaderAddress(ctx context.Context, kubeClient kubernetes.Interface, namespace, name, identity, address string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				LeaderIdentityAnnotation: identity,
+				LeaderAddressAnnotation:  address,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = kubeClient.CoordinationV1().Leases(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
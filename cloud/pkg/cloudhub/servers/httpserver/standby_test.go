@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/constants"
+)
+
+func newSigningLease(holder, address string, renewTime time.Time) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kubeedge",
+			Name:      "cloudhub-signer",
+			Annotations: map[string]string{
+				LeaderIdentityAnnotation: holder,
+				LeaderAddressAnnotation:  address,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(holder),
+			LeaseDurationSeconds: ptr.To[int32](15),
+			RenewTime:            &metav1.MicroTime{Time: renewTime},
+		},
+	}
+}
+
+func TestStandbyRoutes(t *testing.T) {
+	hubconfig.Config.Ca = []byte("ca")
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	newServer := func(identity string) (*standby, *httptest.Server) {
+		sb := newStandby(identity)
+		sb.now = clock
+		container := restful.NewContainer()
+		container.Add(routes(nil, &drainer{}, sb, nil, nil))
+		srv := httptest.NewTLSServer(container)
+		t.Cleanup(srv.Close)
+		return sb, srv
+	}
+	sbA, srvA := newServer("a")
+	sbB, srvB := newServer("b")
+	addrA, addrB := srvA.Listener.Addr().String(), srvB.Listener.Addr().String()
+	observe := func(lease *coordinationv1.Lease) {
+		sbA.observe(lease)
+		sbB.observe(lease)
+	}
+
+	client := srvA.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	get := func(srv *httptest.Server, path string) *http.Response {
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+	requireRedirect := func(srv *httptest.Server, leaderAddr string) {
+		resp := get(srv, constants.DefaultCertURL)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		location, err := url.Parse(resp.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, leaderAddr, location.Host)
+		require.Equal(t, constants.DefaultCertURL, location.Path)
+	}
+	requireSigning := func(srv *httptest.Server) {
+		// the request carries no credential, it is rejected by the signing handler
+		resp := get(srv, constants.DefaultCertURL)
+		require.NotEqual(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		require.NotEqual(t, http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	requireUnavailable := func(srv *httptest.Server) {
+		resp := get(srv, constants.DefaultCertURL)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Equal(t, standbyRetryAfterSeconds, resp.Header.Get("Retry-After"))
+	}
+
+	observe(newSigningLease("a", addrA, now))
+	for _, srv := range []*httptest.Server{srvA, srvB} {
+		require.Equal(t, http.StatusOK, get(srv, constants.DefaultCAURL).StatusCode)
+	}
+	requireSigning(srvA)
+	requireRedirect(srvB, addrA)
+
+	// the redirect is followed to the leader
+	following := srvB.Client()
+	resp, err := following.Get(srvB.URL + constants.DefaultCertURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, addrA, resp.Request.URL.Host)
+
+	// the leader stops renewing the lease, no replica signs after the lease expires
+	now = now.Add(14 * time.Second)
+	requireRedirect(srvB, addrA)
+	now = now.Add(time.Second)
+	requireUnavailable(srvA)
+	requireUnavailable(srvB)
+	require.Equal(t, http.StatusOK, get(srvB, constants.DefaultCAURL).StatusCode)
+
+	// b takes over the lease, the address of a is not trusted before b publishes its own
+	stale := newSigningLease("b", addrA, now)
+	stale.Annotations[LeaderIdentityAnnotation] = "a"
+	observe(stale)
+	requireUnavailable(srvA)
+	requireSigning(srvB)
+
+	observe(newSigningLease("b", addrB, now))
+	requireRedirect(srvA, addrB)
+	requireSigning(srvB)
+
+	observe(nil)
+	requireUnavailable(srvA)
+	requireUnavailable(srvB)
+}
+
+func TestStartStandbyFailover(t *testing.T) {
+	hubconfig.Config.AdvertiseAddress = []string{"10.0.0.1"}
+	hubconfig.Config.HTTPS = &v1alpha1.CloudHubHTTPS{Port: 10002}
+	hubconfig.Config.Standby = &v1alpha1.CloudHubStandby{
+		Enable:         true,
+		LeaseNamespace: "kubeedge",
+		LeaseName:      "cloudhub-signer",
+		LeaseDuration:  3,
+		RenewDeadline:  2,
+		RetryPeriod:    1,
+	}
+	defer func() { hubconfig.Config.Standby = nil }()
+	kubeClient := fake.NewSimpleClientset()
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	defer cancelA()
+	sbA, err := startStandby(ctxA, kubeClient)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		isLeader, _ := sbA.leader()
+		return isLeader
+	}, 10*time.Second, 50*time.Millisecond)
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+	sbB, err := startStandby(ctxB, kubeClient)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		isLeader, address := sbB.leader()
+		return !isLeader && address == "10.0.0.1:10002"
+	}, 10*time.Second, 50*time.Millisecond)
+
+	lease, err := kubeClient.CoordinationV1().Leases("kubeedge").Get(context.TODO(), "cloudhub-signer", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, sbA.identity, lease.Annotations[LeaderIdentityAnnotation])
+
+	// a releases the lease on shutting down, b takes over within the lease duration
+	cancelA()
+	require.Eventually(t, func() bool {
+		isLeader, _ := sbB.leader()
+		return isLeader
+	}, 3*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testutil provides a throwaway CA and the credentials of edge nodes for the tests
+// of the CloudHub HTTP handlers, it must only be imported by tests.
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// CA is a throwaway CA installed as the CA of CloudHub.
+type CA struct {
+	Cert *x509.Certificate
+	Key  certs.PrivateKeyWrap
+}
+
+// Node is the credentials of an edge node requesting its certificate.
+type Node struct {
+	Name string
+	// Key is the private key of the CSR.
+	Key certs.PrivateKeyWrap
+	// CSR is the DER encoded CSR of the node.
+	CSR []byte
+	// Token is a bootstrap token signed by the CA, which is valid for a minute.
+	Token string
+}
+
+// NewCA generates a CA and installs it as the CA of CloudHub with the signing duration of a day,
+// the previous config is restored when the test finishes.
+func NewCA(t testing.TB) *CA {
+	t.Helper()
+	key, err := certs.GetCAHandler(certs.CAHandlerTypeX509).GenPrivateKey()
+	require.NoError(t, err)
+	return NewCAWithKey(t, key)
+}
+
+// NewCAWithKey is like NewCA, but the CA is created with the key, e.g. one generated by
+// certs.GenPrivateKey of another key type.
+func NewCAWithKey(t testing.TB, key certs.PrivateKeyWrap) *CA {
+	t.Helper()
+	block, err := certs.GetCAHandler(certs.CAHandlerTypeX509).NewSelfSigned(key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	install(t, block.Bytes, key)
+	return &CA{Cert: cert, Key: key}
+}
+
+// install installs the CA as the CA of CloudHub with the signing duration of a day.
+func install(t testing.TB, caDER []byte, key certs.PrivateKeyWrap) {
+	config := &hubconfig.Config
+	ca, caKey, duration := config.Ca, config.CaKey, config.CloudHub.EdgeCertSigningDuration
+	t.Cleanup(func() {
+		config.Ca, config.CaKey, config.CloudHub.EdgeCertSigningDuration = ca, caKey, duration
+	})
+	config.Ca = caDER
+	config.CaKey = key.DER()
+	config.CloudHub.EdgeCertSigningDuration = 1
+}
+
+// NewIntermediateCA is like NewCA, but the CA installed is an intermediate CA signed by a root CA,
+// which is returned too. The root CA is not installed.
+func NewIntermediateCA(t testing.TB) (*CA, *CA) {
+	t.Helper()
+	handler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	rootKey, err := handler.GenPrivateKey()
+	require.NoError(t, err)
+	rootBlock, err := handler.NewSelfSigned(rootKey)
+	require.NoError(t, err)
+	rootCert, err := x509.ParseCertificate(rootBlock.Bytes)
+	require.NoError(t, err)
+	rootSigner, err := rootKey.Signer()
+	require.NoError(t, err)
+
+	key, err := handler.GenPrivateKey()
+	require.NoError(t, err)
+	signer, err := key.Signer()
+	require.NoError(t, err)
+	now := time.Now()
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:          big.NewInt(now.UnixNano()),
+		Subject:               pkix.Name{CommonName: "KubeEdge Intermediate"},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, rootCert, signer.Public(), rootSigner)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	install(t, der, key)
+	return &CA{Cert: cert, Key: key}, &CA{Cert: rootCert, Key: rootKey}
+}
+
+// Token mints a bootstrap token signed by the CA, which expires after d.
+func (ca *CA) Token(t testing.TB, d time.Duration) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(d)),
+	}).SignedString(ca.Key.DER())
+	require.NoError(t, err)
+	return token
+}
+
+// NewNode generates a key and a CSR of the node, along with a bootstrap token.
+func (ca *CA) NewNode(t testing.TB, name string) *Node {
+	t.Helper()
+	key, err := certs.GetHandler(certs.HandlerTypeX509).GenPrivateKey()
+	require.NoError(t, err)
+	return ca.NewNodeWithKey(t, name, key)
+}
+
+// NewNodeWithKey is like NewNode, but the CSR of the node is created with the key.
+func (ca *CA) NewNodeWithKey(t testing.TB, name string, key certs.PrivateKeyWrap) *Node {
+	t.Helper()
+	return &Node{
+		Name:  name,
+		Key:   key,
+		CSR:   NewCSR(t, name, key),
+		Token: ca.Token(t, time.Minute),
+	}
+}
+
+// NewCSR creates the DER encoded CSR of the node with the key.
+func NewCSR(t testing.TB, name string, key certs.PrivateKeyWrap) []byte {
+	t.Helper()
+	csr, err := certs.GetHandler(certs.HandlerTypeX509).CreateCSR(pkix.Name{
+		Organization: []string{certs.EdgeNodeOrganization},
+		CommonName:   certs.EdgeNodeCommonNamePrefix + name,
+	}, key, nil)
+	require.NoError(t, err)
+	return csr.Bytes
+}
+
+// Issue signs a client certificate of the node by the CA directly, e.g. to present in a renewal.
+func (ca *CA) Issue(t testing.TB, n *Node) *x509.Certificate {
+	t.Helper()
+	block, err := certs.GetHandler(certs.HandlerTypeX509).SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+		n.CSR, ca.Cert.Raw, ca.Key.DER(), []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour))
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+// Request builds an edge certificate request of the node authenticated by its token.
+// The request is served over TLS without a client certificate, so it reaches the handler
+// as the requests from CloudHub's HTTPS server do.
+func (n *Node) Request() *http.Request {
+	req := httptest.NewRequest(http.MethodGet, constants.DefaultCertURL, bytes.NewReader(n.CSR))
+	req.TLS = &tls.ConnectionState{}
+	req.Header.Set(types.HeaderNodeName, n.Name)
+	req.Header.Set(types.HeaderAuthorization, "Bearer "+n.Token)
+	return req
+}
+
+// RenewalRequest builds an edge certificate request of the node authenticated by the certificate.
+func (n *Node) RenewalRequest(cert *x509.Certificate) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, constants.DefaultCertURL, bytes.NewReader(n.CSR))
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	req.Header.Set(types.HeaderNodeName, n.Name)
+	return req
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/require"
+
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/certificate"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+)
+
+// TestCA shows a node bootstrapping its certificate with a token and renewing it with the certificate.
+func TestCA(t *testing.T) {
+	ca := testutil.NewCA(t)
+	require.Equal(t, ca.Cert.Raw, hubconfig.Config.Ca)
+	node := ca.NewNode(t, "testnode")
+
+	recorder := httptest.NewRecorder()
+	certificate.EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	certificate.EdgeCoreClientCert(restful.NewRequest(node.RenewalRequest(ca.Issue(t, node))),
+		restful.NewResponse(recorder))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	// the expired token is rejected
+	node.Token = ca.Token(t, -time.Minute)
+	recorder = httptest.NewRecorder()
+	certificate.EdgeCoreClientCert(restful.NewRequest(node.Request()), restful.NewResponse(recorder))
+	require.Equal(t, http.StatusUnauthorized, recorder.Code, recorder.Body.String())
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// NewClientset returns a fake clientset and a function that waits until n watches are started on it.
+// The fake clientset drops the events between the list and the watch of an informer, so the tests
+// wait for the watches of the informers before changing the watched objects.
+func NewClientset(t testing.TB) (*fake.Clientset, func(n int)) {
+	client := fake.NewSimpleClientset()
+	started := make(chan struct{}, 64)
+	client.PrependWatchReactor("*", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		w, err := client.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return false, nil, err
+		}
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		return true, w, nil
+	})
+	waitForWatches := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("only %d of %d watches are started", i, n)
+			}
+		}
+	}
+	return client, waitForWatches
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/clientip"
+)
+
+// staleSocketDialTimeout is the timeout of probing whether an existing socket file is served
+const staleSocketDialTimeout = time.Second
+
+// serveUnixSocket serves the handler in plain HTTP on the listener of the unix domain socket of
+// the path until the ctx is done, the socket file is removed after the server shuts down.
+func serveUnixSocket(ctx context.Context, listener net.Listener, path string, handler http.Handler,
+	d *drainer, drainTimeout time.Duration) {
+	defer removeSocketFile(path)
+
+	server := &http.Server{Handler: unixSocketHandler(handler)}
+	klog.Infof("serving the https server on the unix socket %s", path)
+	if err := serve(ctx, server, d, drainTimeout, func() error {
+		return server.Serve(listener)
+	}); err != nil {
+		klog.Errorf("failed to serve the https server on the unix socket %s, err: %v", path, err)
+	}
+}
+
+// unixSocketHandler marks the requests arriving via the unix domain socket, so that they are trusted
+// as the ones from the trusted proxies. The requests carry an empty TLS connection state, as the
+// handlers expect the peer certificates of the TLS connections, which are forwarded by the proxy.
+func unixSocketHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(clientip.WithUnixSocket(r.Context()))
+		if r.TLS == nil {
+			r.TLS = &tls.ConnectionState{}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// listenUnixSocket listens on the unix domain socket of the path with the file permissions of
+// the mode. The socket file left behind by a previous run is removed, but the socket served by
+// another process is never taken over.
+func listenUnixSocket(path string, mode os.FileMode) (net.Listener, error) {
+	if err := removeStaleSocketFile(path); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create the directory of the unix socket %s, err: %v", path, err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on the unix socket %s, err: %v", path, err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to set the permissions %#o of the unix socket %s, err: %v", mode, path, err)
+	}
+	return listener, nil
+}
+
+// removeStaleSocketFile removes the socket file of the path if no process serves it
+func removeStaleSocketFile(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat the unix socket %s, err: %v", path, err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("the unix socket path %s exists and is not a socket", path)
+	}
+	if conn, err := net.DialTimeout("unix", path, staleSocketDialTimeout); err == nil {
+		conn.Close()
+		return fmt.Errorf("the unix socket %s is served by another process", path)
+	}
+	klog.Infof("remove the stale unix socket %s", path)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove the stale unix socket %s, err: %v", path, err)
+	}
+	return nil
+}
+
+func removeSocketFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		klog.Warningf("failed to remove the unix socket %s, err: %v", path, err)
+	}
+}
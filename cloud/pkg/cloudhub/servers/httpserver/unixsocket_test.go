@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/clientip"
+)
+
+func unixSocketClient(path string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+}
+
+func TestServeUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "https.sock")
+	// the socket file left behind by a previous run
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	listener, err := listenUnixSocket(path, 0600)
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	var hasTLS, trusted bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hasTLS, trusted = r.TLS != nil, clientip.TrustedProxies{}.IsTrustedRequest(r)
+		_, _ = io.WriteString(w, clientip.TrustedProxies{}.Resolve(r))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serveUnixSocket(ctx, listener, path, handler, &drainer{}, time.Second)
+	}()
+
+	req, err := http.NewRequest(http.MethodGet, "http://cloudhub/ca.crt", nil)
+	require.NoError(t, err)
+	req.Header.Set(clientip.HeaderXForwardedFor, "1.2.3.4")
+	resp, err := unixSocketClient(path).Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3.4", string(body))
+	require.True(t, hasTLS, "expected the TLS connection state set")
+	require.True(t, trusted, "expected the request via the unix socket trusted")
+
+	// the socket served by this server is not taken over
+	_, err = listenUnixSocket(path, 0600)
+	require.Error(t, err)
+
+	cancel()
+	<-done
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err), "expected the socket file removed, err: %v", err)
+}
+
+func TestListenUnixSocketNotSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "https.sock")
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0600))
+
+	_, err := listenUnixSocket(path, 0600)
+	require.Error(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "data", string(data))
+}
@@ -3,14 +3,14 @@ package servers
 import (
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 
-	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
 	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/handler"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/certreload"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/revocation"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/server"
 )
@@ -27,56 +27,81 @@ func StartCloudHub(messageHandler handler.Handler) {
 	}
 }
 
-func createTLSConfig(ca, cert, key []byte) tls.Config {
-	// init certificate
-	pool := x509.NewCertPool()
-	ok := pool.AppendCertsFromPEM(pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: ca}))
-	if !ok {
-		panic(fmt.Errorf("fail to load ca content"))
-	}
-
-	certificate, err := tls.X509KeyPair(pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: cert}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}))
-	if err != nil {
-		panic(err)
-	}
-	return tls.Config{
-		ClientCAs:    pool,
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		Certificates: []tls.Certificate{certificate},
-		MinVersion:   tls.VersionTLS12,
-		// has to match cipher used by NewPrivateKey method, currently is ECDSA
+// createTLSConfig creates the TLS config of the servers, the client certificates are verified by
+// the pool of all the CAs, so that the certificates issued by the previous CA are accepted during a CA rotation.
+// The server certificate and the client CAs are reloaded by the new handshakes after their files change.
+func createTLSConfig(cert *certreload.KeyPair, cas *certreload.CAPool) *tls.Config {
+	config := &tls.Config{
+		GetCertificate: cert.GetCertificate,
+		ClientCAs:      cas.Pool(),
+		ClientAuth:     tls.RequireAndVerifyClientCert,
+		// rejects the edge certificates revoked by the duplicate enrollment fencing
+		VerifyPeerCertificate: func(_ [][]byte, chains [][]*x509.Certificate) error {
+			for _, chain := range chains {
+				if len(chain) > 0 && revocation.DefaultList.IsRevoked(chain[0]) {
+					return fmt.Errorf("the edge certificate %s is revoked", chain[0].SerialNumber.String())
+				}
+			}
+			return nil
+		},
+		// has to match cipher used by NewPrivateKey method, currently is ECDSA,
+		// unless the cipher suites are configured
 		CipherSuites: []uint16{
 			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
 			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256,
 		},
 	}
+	hubconfig.Config.ApplyTLSOptions(config)
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := config.Clone()
+		c.ClientCAs = cas.Pool()
+		return c, nil
+	}
+	return config
 }
 
 func startWebsocketServer(messageHandler handler.Handler) {
-	tlsConfig := createTLSConfig(hubconfig.Config.Ca, hubconfig.Config.Cert, hubconfig.Config.Key)
+	tlsConfig := createTLSConfig(certreload.ServerCert, certreload.ClientCAs)
 	svc := server.Server{
 		Type:               api.ProtocolTypeWS,
-		TLSConfig:          &tlsConfig,
+		TLSConfig:          tlsConfig,
 		AutoRoute:          true,
 		ConnNotify:         messageHandler.HandleConnection,
 		OnReadTransportErr: messageHandler.OnReadTransportErr,
 		Addr:               fmt.Sprintf("%s:%d", hubconfig.Config.WebSocket.Address, hubconfig.Config.WebSocket.Port),
-		ExOpts:             api.WSServerOption{Path: "/"},
+		ExOpts: api.WSServerOption{
+			Path:              "/",
+			EnableCompression: hubconfig.Config.WebSocket.EnableCompression,
+			Limits: api.MessageLimits{
+				MaxReadMessageSize:  hubconfig.Config.WebSocket.MaxReadMessageSize,
+				MaxWriteMessageSize: hubconfig.Config.WebSocket.MaxWriteMessageSize,
+				ReadBufferSize:      int(hubconfig.Config.WebSocket.ReadBufferSize),
+				WriteBufferSize:     int(hubconfig.Config.WebSocket.WriteBufferSize),
+			},
+		},
 	}
 	klog.Infof("Starting cloudhub %s server", api.ProtocolTypeWS)
 	klog.Exit(svc.ListenAndServeTLS("", ""))
 }
 
 func startQuicServer(messageHandler handler.Handler) {
-	tlsConfig := createTLSConfig(hubconfig.Config.Ca, hubconfig.Config.Cert, hubconfig.Config.Key)
+	tlsConfig := createTLSConfig(certreload.ServerCert, certreload.ClientCAs)
 	svc := server.Server{
 		Type:               api.ProtocolTypeQuic,
-		TLSConfig:          &tlsConfig,
+		TLSConfig:          tlsConfig,
 		AutoRoute:          true,
 		ConnNotify:         messageHandler.HandleConnection,
 		OnReadTransportErr: messageHandler.OnReadTransportErr,
 		Addr:               fmt.Sprintf("%s:%d", hubconfig.Config.Quic.Address, hubconfig.Config.Quic.Port),
-		ExOpts:             api.QuicServerOption{MaxIncomingStreams: int(hubconfig.Config.Quic.MaxIncomingStreams)},
+		ExOpts: api.QuicServerOption{
+			MaxIncomingStreams: int(hubconfig.Config.Quic.MaxIncomingStreams),
+			Limits: api.MessageLimits{
+				MaxReadMessageSize:  hubconfig.Config.Quic.MaxReadMessageSize,
+				MaxWriteMessageSize: hubconfig.Config.Quic.MaxWriteMessageSize,
+				ReadBufferSize:      int(hubconfig.Config.Quic.ReadBufferSize),
+				WriteBufferSize:     int(hubconfig.Config.Quic.WriteBufferSize),
+			},
+		},
 	}
 
 	klog.Infof("Starting cloudhub %s server", api.ProtocolTypeQuic)
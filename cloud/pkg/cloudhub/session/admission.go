@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+// ReasonNodeNotAdmitted is the reason of the Warning Event of the node whose
+// connection is refused by the node admission
+const ReasonNodeNotAdmitted = "NodeNotAdmitted"
+
+// the reasons of the refused connections in the node admission metrics
+const (
+	admissionLabelSelector   = "label_selector"
+	admissionTaint           = "taint"
+	admissionNodeUnavailable = "node_unavailable"
+)
+
+// nodeAdmission decides which edge nodes may establish the sessions by their Node objects
+type nodeAdmission struct {
+	lister     corev1listers.NodeLister
+	selector   labels.Selector
+	denyTaints sets.Set[string]
+	failOpen   bool
+}
+
+// EnableNodeAdmission checks the Node objects of the connecting edge nodes in the lister
+// against the config, it must be enabled before the connections are served.
+func (sm *Manager) EnableNodeAdmission(config *v1alpha1.CloudHubNodeAdmission, lister corev1listers.NodeLister) error {
+	selector, err := labels.Parse(config.LabelSelector)
+	if err != nil {
+		return fmt.Errorf("invalid label selector %q of the node admission, err: %v", config.LabelSelector, err)
+	}
+	sm.nodeAdmission = &nodeAdmission{
+		lister:     lister,
+		selector:   selector,
+		denyTaints: sets.New(config.DenyTaints...),
+		failOpen:   config.FailOpen,
+	}
+	return nil
+}
+
+// AdmitNode checks the Node object of the connecting node against the label selector and the
+// deny taints of the node admission. It returns false with the reason if the connection must be
+// rejected, which is sent to the edge hub as the close message and is kept short enough for the
+// websocket close frame.
+func (sm *Manager) AdmitNode(nodeID string) (string, bool) {
+	a := sm.nodeAdmission
+	if a == nil {
+		return "", true
+	}
+
+	var reason, message, metricReason string
+	node, err := a.lister.Get(nodeID)
+	switch {
+	case err != nil:
+		if a.failOpen {
+			klog.Warningf("admit node %s whose Node object can not be fetched, err: %v", nodeID, err)
+			return "", true
+		}
+		reason = "the Node object of the node can not be fetched"
+		message = fmt.Sprintf("failed to get the Node object of node %s, err: %v", nodeID, err)
+		metricReason = admissionNodeUnavailable
+	case !a.selector.Matches(labels.Set(node.Labels)):
+		reason = "the node does not match the label selector of cloudhub"
+		message = fmt.Sprintf("node %s does not match the label selector %q", nodeID, a.selector.String())
+		metricReason = admissionLabelSelector
+	default:
+		taint, denied := a.deniedTaint(node)
+		if !denied {
+			return "", true
+		}
+		reason = "the node has a taint denied by cloudhub"
+		message = fmt.Sprintf("node %s has the denied taint %s", nodeID, taint)
+		metricReason = admissionTaint
+	}
+
+	message = fmt.Sprintf("the connection is refused by the node admission, %s", message)
+	klog.Warning(message)
+	monitor.NodeAdmissionDeniedTotal.WithLabelValues(metricReason).Inc()
+	sm.events.Warning(nodeID, ReasonNodeNotAdmitted, message)
+	return reason, false
+}
+
+// deniedTaint returns the key of the first taint of the node which is denied
+func (a *nodeAdmission) deniedTaint(node *corev1.Node) (string, bool) {
+	for _, taint := range node.Spec.Taints {
+		if a.denyTaints.Has(taint.Key) {
+			return taint.Key, true
+		}
+	}
+	return "", false
+}
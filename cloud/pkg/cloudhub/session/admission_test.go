@@ -0,0 +1,121 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+const quarantinedTaint = "node.kubeedge.io/quarantined"
+
+func TestAdmitNode(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "stage1-node", Labels: map[string]string{"rollout": "stage1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "stage2-node", Labels: map[string]string{"rollout": "stage2"}}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "quarantined-node", Labels: map[string]string{"rollout": "stage1"}},
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+				{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule},
+				{Key: quarantinedTaint, Effect: corev1.TaintEffectNoExecute},
+			}},
+		},
+	}
+	for _, node := range nodes {
+		if err := indexer.Add(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name         string
+		nodeID       string
+		failOpen     bool
+		admitted     bool
+		metricReason string
+	}{
+		{name: "allow", nodeID: "stage1-node", admitted: true},
+		{name: "deny by label selector", nodeID: "stage2-node", metricReason: admissionLabelSelector},
+		{name: "deny by taint", nodeID: "quarantined-node", metricReason: admissionTaint},
+		{name: "node not found fail closed", nodeID: "unknown-node", metricReason: admissionNodeUnavailable},
+		{name: "node not found fail open", nodeID: "unknown-node", failOpen: true, admitted: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			events := make(chan string, 1)
+			manager := NewSessionManager(10)
+			manager.events.record = func(nodeID, _, reason, _ string) { events <- nodeID + ": " + reason }
+			err := manager.EnableNodeAdmission(&v1alpha1.CloudHubNodeAdmission{
+				Enable:        true,
+				LabelSelector: "rollout=stage1",
+				DenyTaints:    []string{quarantinedTaint},
+				FailOpen:      c.failOpen,
+			}, corev1listers.NewNodeLister(indexer))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var before float64
+			if c.metricReason != "" {
+				before = testutil.ToFloat64(monitor.NodeAdmissionDeniedTotal.WithLabelValues(c.metricReason))
+			}
+			reason, admitted := manager.AdmitNode(c.nodeID)
+			if admitted != c.admitted {
+				t.Fatalf("expected admitted %v, got %v with reason %q", c.admitted, admitted, reason)
+			}
+			if admitted {
+				return
+			}
+			if reason == "" || len(reason) > 123 {
+				t.Errorf("expected a short reason for the close frame, got %q", reason)
+			}
+			if strings.Contains(reason, c.nodeID) {
+				t.Errorf("expected the reason without the node name, got %q", reason)
+			}
+			if got := testutil.ToFloat64(monitor.NodeAdmissionDeniedTotal.WithLabelValues(c.metricReason)) - before; got != 1 {
+				t.Errorf("expected 1 refused connection with reason %s, got %v", c.metricReason, got)
+			}
+			select {
+			case event := <-events:
+				if event != c.nodeID+": "+ReasonNodeNotAdmitted {
+					t.Errorf("unexpected event: %s", event)
+				}
+			case <-time.After(5 * time.Second):
+				t.Errorf("expected the node not admitted event recorded")
+			}
+		})
+	}
+}
+
+func TestAdmitNodeDisabled(t *testing.T) {
+	manager := NewSessionManager(10)
+	if reason, admitted := manager.AdmitNode("unknown-node"); !admitted {
+		t.Errorf("expected the node admitted without the node admission, got %q", reason)
+	}
+}
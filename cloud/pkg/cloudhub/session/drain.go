@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+)
+
+// reconnectDelay returns the random reconnect delay of a node within the window
+var reconnectDelay = func(window time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(window)))
+}
+
+// NewReconnectMessage returns the control message which asks the edge hub of the node
+// to reconnect after the delay, the edge hubs not knowing it ignore it.
+func NewReconnectMessage(nodeID string, delay time.Duration) *beehivemodel.Message {
+	return beehivemodel.NewMessage("").
+		BuildRouter(modules.CloudHubModuleName, model.GpHub, model.ResNode+"/"+nodeID, model.OpReconnect).
+		FillBody(delay.String())
+}
+
+// Draining reports whether the sessions are being drained, the new connections are rejected
+func (sm *Manager) Draining() bool {
+	return sm.draining.Load()
+}
+
+// Drain asks the connected edge nodes to reconnect after random delays within the window,
+// except the ones whose edge hubs do not know the reconnect messages,
+// then closes their sessions one by one spread over the timeout, in the order of their delays.
+// The new connections are rejected once it starts, and it returns within the timeout.
+func (sm *Manager) Drain(timeout, window time.Duration) {
+	sm.draining.Store(true)
+
+	type drainItem struct {
+		session *NodeSession
+		delay   time.Duration
+	}
+	var items []drainItem
+	sm.NodeSessions.Range(func(_, value any) bool {
+		items = append(items, drainItem{session: value.(*NodeSession), delay: reconnectDelay(window)})
+		return true
+	})
+	if len(items) == 0 {
+		return
+	}
+	klog.Infof("draining the sessions of %d edge nodes in %v", len(items), timeout)
+
+	sort.Slice(items, func(i, j int) bool { return items[i].delay < items[j].delay })
+	for _, item := range items {
+		// the edge hubs of the older versions do not know the reconnect messages
+		if item.session.schemaVersion < model.SchemaVersionReconnect {
+			continue
+		}
+		if err := item.session.writeMessage(NewReconnectMessage(item.session.nodeID, item.delay)); err != nil {
+			klog.Warningf("failed to send the reconnect message to node %s, err: %v", item.session.nodeID, err)
+		}
+	}
+
+	interval := timeout / time.Duration(len(items)+1)
+	for _, item := range items {
+		time.Sleep(interval)
+		item.session.SetTerminateErr(DrainedErr)
+		item.session.Terminating()
+	}
+	klog.Infof("drained the sessions of %d edge nodes", len(items))
+}
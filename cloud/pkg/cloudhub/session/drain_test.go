@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/kubeedge/api/client/clientset/versioned/fake"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
+)
+
+func TestDrain(t *testing.T) {
+	nodes := []string{"node-a", "node-b", "node-c"}
+	var drawn []time.Duration
+	origin := reconnectDelay
+	reconnectDelay = func(window time.Duration) time.Duration {
+		if window != 5*time.Second {
+			t.Errorf("unexpected reconnect window %v", window)
+		}
+		d := time.Duration(len(drawn)+1) * time.Second
+		drawn = append(drawn, d)
+		return d
+	}
+	t.Cleanup(func() { reconnectDelay = origin })
+
+	client := &fake.Clientset{}
+	mockController := gomock.NewController(t)
+	manager := NewSessionManager(10)
+	sent := map[string]string{}
+	var closed []string
+	for _, nodeID := range nodes {
+		nodeID := nodeID
+		mockConn := mockcon.NewMockConnection(mockController)
+		mockConn.EXPECT().WriteMessageAsync(gomock.Any()).DoAndReturn(func(msg *beehivemodel.Message) error {
+			if msg.GetGroup() != model.GpHub || msg.GetOperation() != model.OpReconnect ||
+				msg.GetResource() != model.ResNode+"/"+nodeID {
+				t.Errorf("unexpected reconnect message %+v", msg.Router)
+			}
+			sent[nodeID] = msg.GetContent().(string)
+			return nil
+		})
+		mockConn.EXPECT().Close().DoAndReturn(func() error {
+			closed = append(closed, nodeID)
+			return nil
+		})
+		session := NewNodeSession(nodeID, "", mockConn, time.Minute, common.InitNodeMessagePool(nodeID), client)
+		session.SetSchemaVersion(model.SchemaVersionCurrent)
+		manager.AddSession(session)
+	}
+
+	start := time.Now()
+	manager.Drain(300*time.Millisecond, 5*time.Second)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the drain to finish within the timeout, took %v", elapsed)
+	}
+	if !manager.Draining() {
+		t.Errorf("expected the manager draining")
+	}
+	if len(sent) != 3 || len(closed) != 3 {
+		t.Fatalf("expected all the nodes notified and closed, got %v and %v", sent, closed)
+	}
+	// the sessions are closed in the order of their reconnect delays
+	for i := 1; i < len(closed); i++ {
+		prev, _ := time.ParseDuration(sent[closed[i-1]])
+		cur, _ := time.ParseDuration(sent[closed[i]])
+		if prev > cur {
+			t.Errorf("expected the sessions closed in the order of the delays, got %v with %v", closed, sent)
+		}
+	}
+	for _, nodeID := range nodes {
+		session, ok := manager.GetSession(nodeID)
+		if !ok {
+			t.Fatalf("expected the session of %s kept until it stops", nodeID)
+		}
+		if session.GetTerminateErr() != DrainedErr {
+			t.Errorf("expected the session of %s drained, got %d", nodeID, session.GetTerminateErr())
+		}
+	}
+}
+
+func TestDrainLegacySessions(t *testing.T) {
+	mockController := gomock.NewController(t)
+	manager := NewSessionManager(10)
+	// the edge hub not knowing the reconnect messages is not sent one
+	mockConn := mockcon.NewMockConnection(mockController)
+	mockConn.EXPECT().Close().Return(nil)
+	manager.AddSession(NewNodeSession("legacy-node", "", mockConn, time.Minute,
+		common.InitNodeMessagePool("legacy-node"), &fake.Clientset{}))
+
+	manager.Drain(100*time.Millisecond, time.Second)
+	session, ok := manager.GetSession("legacy-node")
+	if !ok {
+		t.Fatalf("expected the session of legacy-node kept until it stops")
+	}
+	if session.GetTerminateErr() != DrainedErr {
+		t.Errorf("expected the session of legacy-node drained, got %d", session.GetTerminateErr())
+	}
+}
+
+func TestDrainWithoutSessions(t *testing.T) {
+	manager := NewSessionManager(10)
+	start := time.Now()
+	manager.Drain(time.Minute, time.Minute)
+	if time.Since(start) > time.Second {
+		t.Errorf("expected the drain without sessions to return at once")
+	}
+	if !manager.Draining() {
+		t.Errorf("expected the manager draining")
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+// ReasonDuplicateSession is the reason of the Warning Event of the node which
+// connects again while its session is active
+const ReasonDuplicateSession = "DuplicateSession"
+
+// AdmitSession checks the new connection of the node against the duplicate session policy.
+// It returns false if the node has an active session and the new connection must be rejected,
+// with the takeover policy the active session is closed when the new session is added.
+func (sm *Manager) AdmitSession(nodeID, remoteAddr string) bool {
+	if _, exists := sm.GetSession(nodeID); !exists {
+		return true
+	}
+
+	action := v1alpha1.DuplicateSessionTakeover
+	message := fmt.Sprintf("node %s connected again from %s, its active session is taken over", nodeID, remoteAddr)
+	if sm.duplicatePolicy == v1alpha1.DuplicateSessionReject {
+		action = v1alpha1.DuplicateSessionReject
+		message = fmt.Sprintf("node %s connected again from %s while its session is active, the connection is rejected",
+			nodeID, remoteAddr)
+	}
+	klog.Warning(message)
+	monitor.DuplicateSessionsTotal.WithLabelValues(monitor.NodeLabel(nodeID), string(action)).Inc()
+	sm.events.Warning(nodeID, ReasonDuplicateSession, message)
+
+	return action == v1alpha1.DuplicateSessionTakeover
+}
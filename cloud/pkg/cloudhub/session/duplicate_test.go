@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/api/client/clientset/versioned/fake"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
+	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
+)
+
+func TestAdmitSession(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   v1alpha1.DuplicateSessionPolicy
+		action   string
+		admitted bool
+	}{
+		{name: "default policy", policy: "", action: "takeover", admitted: true},
+		{name: "takeover policy", policy: v1alpha1.DuplicateSessionTakeover, action: "takeover", admitted: true},
+		{name: "reject policy", policy: v1alpha1.DuplicateSessionReject, action: "reject", admitted: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			events := make(chan string, 1)
+			manager := NewSessionManager(10)
+			manager.duplicatePolicy = c.policy
+			manager.events.record = func(nodeID, _, _, message string) { events <- nodeID + ": " + message }
+
+			if !manager.AdmitSession(tf.TestNodeID, "10.0.0.1:5000") {
+				t.Fatalf("expected the first connection of the node admitted")
+			}
+
+			mockController := gomock.NewController(t)
+			manager.AddSession(NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockcon.NewMockConnection(mockController),
+				tf.KeepaliveInterval, common.InitNodeMessagePool(tf.TestNodeID), &fake.Clientset{}))
+
+			counter := monitor.DuplicateSessionsTotal.WithLabelValues(tf.TestNodeID, c.action)
+			before := testutil.ToFloat64(counter)
+			if got := manager.AdmitSession(tf.TestNodeID, "10.0.0.2:5000"); got != c.admitted {
+				t.Errorf("expected admitted %v, got %v", c.admitted, got)
+			}
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("expected 1 duplicate session with action %s, got %v", c.action, got)
+			}
+			select {
+			case event := <-events:
+				if !strings.HasPrefix(event, tf.TestNodeID+": ") || !strings.Contains(event, "10.0.0.2:5000") {
+					t.Errorf("unexpected event: %s", event)
+				}
+			case <-time.After(5 * time.Second):
+				t.Errorf("expected the duplicate session event recorded")
+			}
+		})
+	}
+}
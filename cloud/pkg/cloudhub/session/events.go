@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/nodes"
+)
+
+const (
+	// ReasonNodeConnected is the reason of the Event of the node whose session is established
+	ReasonNodeConnected = "NodeConnected"
+	// ReasonNodeDisconnected is the reason of the Event of the node whose session is closed
+	ReasonNodeDisconnected = "NodeDisconnected"
+
+	// nodeEventInterval is the min interval between the NodeConnected Events of a node
+	nodeEventInterval = time.Minute
+)
+
+// EventRecorder records the Events of the session lifecycle on the Node objects. At most one
+// pair of NodeConnected and NodeDisconnected Events of a node is recorded within the interval,
+// so that a flapping node does not flood the Events, the Events of the sessions in between are
+// suppressed and counted in the next NodeConnected Event of the node. The Warning Events of a
+// node are limited to one of each reason within the interval in the same way.
+type EventRecorder struct {
+	// instance identifies the cloudHub instance in the Events
+	instance string
+	interval time.Duration
+	now      func() time.Time
+	// record records the Event of the node
+	record func(nodeName, eventType, reason, message string)
+
+	mu sync.Mutex
+	// nodes records the time of the last NodeConnected Event of each node
+	nodes map[string]time.Time
+	// suppressed counts the suppressed Events of each node since its last NodeConnected Event
+	suppressed map[string]int
+	// warnings records the time of the last Warning Event of each node and reason
+	warnings map[warningKey]time.Time
+	// suppressedWarnings counts the suppressed Warning Events of each node and reason since
+	// the last one recorded
+	suppressedWarnings map[warningKey]int
+}
+
+type warningKey struct {
+	nodeID, reason string
+}
+
+// NewEventRecorder returns the EventRecorder recording the Events with the kube client
+func NewEventRecorder() *EventRecorder {
+	instance, err := os.Hostname()
+	if err != nil {
+		klog.Warningf("failed to get the hostname, err: %v", err)
+		instance = "unknown"
+	}
+	return &EventRecorder{
+		instance:   instance,
+		interval:   nodeEventInterval,
+		now:        time.Now,
+		record:     recordNodeEvent,
+		nodes:      make(map[string]time.Time),
+		suppressed: make(map[string]int),
+
+		warnings:           make(map[warningKey]time.Time),
+		suppressedWarnings: make(map[warningKey]int),
+	}
+}
+
+// Connected records the NodeConnected Event of the node unless one was recorded within the
+// interval, it returns whether the Event is recorded, and the NodeDisconnected Event of the
+// session is recorded only if so.
+func (r *EventRecorder) Connected(nodeID, protocol string) bool {
+	now := r.now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if last, ok := r.nodes[nodeID]; ok && now.Sub(last) < r.interval {
+		r.suppress(nodeID, ReasonNodeConnected)
+		return false
+	}
+	r.nodes[nodeID] = now
+
+	message := fmt.Sprintf("node %s connected to cloudhub %s over %s", nodeID, r.instance, protocol)
+	if n := r.suppressed[nodeID]; n > 0 {
+		message += fmt.Sprintf(", %d connect and disconnect events suppressed since the last one", n)
+		delete(r.suppressed, nodeID)
+	}
+	go r.record(nodeID, corev1.EventTypeNormal, ReasonNodeConnected, message)
+	return true
+}
+
+// Disconnected records the NodeDisconnected Event of the session of the node if its
+// NodeConnected Event was recorded.
+func (r *EventRecorder) Disconnected(nodeID string, connectRecorded bool, reason string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !connectRecorded {
+		r.suppress(nodeID, ReasonNodeDisconnected)
+		return
+	}
+
+	message := fmt.Sprintf("node %s disconnected from cloudhub %s: %s, after being connected for %v",
+		nodeID, r.instance, reason, duration.Round(time.Second))
+	go r.record(nodeID, corev1.EventTypeWarning, ReasonNodeDisconnected, message)
+}
+
+// Warning records the Warning Event of the node unless one of the reason was recorded within
+// the interval, the suppressed ones are counted in the next Warning Event of the reason.
+func (r *EventRecorder) Warning(nodeID, reason, message string) {
+	now := r.now()
+	key := warningKey{nodeID: nodeID, reason: reason}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if last, ok := r.warnings[key]; ok && now.Sub(last) < r.interval {
+		r.suppressedWarnings[key]++
+		countSuppressed(nodeID, reason)
+		return
+	}
+	r.warnings[key] = now
+
+	if n := r.suppressedWarnings[key]; n > 0 {
+		message += fmt.Sprintf(", %d %s events suppressed since the last one", n, reason)
+		delete(r.suppressedWarnings, key)
+	}
+	go r.record(nodeID, corev1.EventTypeWarning, reason, message)
+}
+
+// suppress counts the suppressed Event of the node in its next NodeConnected Event.
+func (r *EventRecorder) suppress(nodeID, reason string) {
+	r.suppressed[nodeID]++
+	countSuppressed(nodeID, reason)
+}
+
+func countSuppressed(nodeID, reason string) {
+	klog.V(4).Infof("suppress the %s event of node %s", reason, nodeID)
+	monitor.SuppressedNodeEventsTotal.WithLabelValues(reason).Inc()
+}
+
+// recordNodeEvent records the Event of the node
+func recordNodeEvent(nodeName, eventType, reason, message string) {
+	nodes.RecordEvent(context.Background(), client.GetKubeClient(), nodeName, eventType, reason, message)
+}
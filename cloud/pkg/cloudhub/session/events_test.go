@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kubeedge/api/client/clientset/versioned/fake"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
+	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
+	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
+)
+
+type recordedEvent struct {
+	node, eventType, reason, message string
+}
+
+func newTestEventRecorder(now *time.Time) (*EventRecorder, chan recordedEvent) {
+	events := make(chan recordedEvent, 10)
+	r := NewEventRecorder()
+	r.instance = "cloudhub-0"
+	r.now = func() time.Time { return *now }
+	r.record = func(node, eventType, reason, message string) {
+		events <- recordedEvent{node: node, eventType: eventType, reason: reason, message: message}
+	}
+	return r, events
+}
+
+func expectEvent(t *testing.T, events chan recordedEvent, reason string, contains ...string) {
+	t.Helper()
+	select {
+	case e := <-events:
+		if e.node != tf.TestNodeID || e.reason != reason {
+			t.Fatalf("expected the %s event of node %s, got %+v", reason, tf.TestNodeID, e)
+		}
+		for _, s := range contains {
+			if !strings.Contains(e.message, s) {
+				t.Errorf("expected %q in the event message %q", s, e.message)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the %s event recorded", reason)
+	}
+}
+
+func TestEventRecorderRateLimit(t *testing.T) {
+	now := time.Now()
+	r, events := newTestEventRecorder(&now)
+	suppressed := monitor.SuppressedNodeEventsTotal.WithLabelValues(ReasonNodeConnected)
+	before := testutil.ToFloat64(suppressed)
+
+	recorded := r.Connected(tf.TestNodeID, api.ProtocolTypeWS)
+	expectEvent(t, events, ReasonNodeConnected, "cloudhub-0", api.ProtocolTypeWS)
+	now = now.Add(10 * time.Second)
+	r.Disconnected(tf.TestNodeID, recorded, "transport_error", 10*time.Second)
+	expectEvent(t, events, ReasonNodeDisconnected, "transport_error", "10s")
+
+	// the node flaps within the interval
+	for i := 0; i < 3; i++ {
+		now = now.Add(5 * time.Second)
+		recorded = r.Connected(tf.TestNodeID, api.ProtocolTypeWS)
+		if recorded {
+			t.Fatalf("expected the connect event within the interval suppressed")
+		}
+		r.Disconnected(tf.TestNodeID, recorded, "transport_error", time.Second)
+	}
+	select {
+	case e := <-events:
+		t.Fatalf("expected no events recorded within the interval, got %+v", e)
+	default:
+	}
+	if got := testutil.ToFloat64(suppressed) - before; got != 3 {
+		t.Errorf("expected 3 suppressed connect events, got %v", got)
+	}
+
+	now = now.Add(time.Minute)
+	if !r.Connected(tf.TestNodeID, api.ProtocolTypeQuic) {
+		t.Fatalf("expected the connect event after the interval recorded")
+	}
+	expectEvent(t, events, ReasonNodeConnected, "6 connect and disconnect events suppressed")
+}
+
+func TestEventRecorderWarningRateLimit(t *testing.T) {
+	now := time.Now()
+	r, events := newTestEventRecorder(&now)
+	suppressed := monitor.SuppressedNodeEventsTotal.WithLabelValues(ReasonDuplicateSession)
+	before := testutil.ToFloat64(suppressed)
+
+	r.Warning(tf.TestNodeID, ReasonDuplicateSession, "duplicate session from 10.0.0.2:5000")
+	expectEvent(t, events, ReasonDuplicateSession, "10.0.0.2:5000")
+
+	// the Warning Events of the reason within the interval are suppressed
+	for i := 0; i < 3; i++ {
+		now = now.Add(5 * time.Second)
+		r.Warning(tf.TestNodeID, ReasonDuplicateSession, "duplicate session from 10.0.0.3:5000")
+	}
+	// the other reasons are limited separately
+	r.Warning(tf.TestNodeID, ReasonNodeNotAdmitted, "node is not admitted")
+	expectEvent(t, events, ReasonNodeNotAdmitted, "not admitted")
+	select {
+	case e := <-events:
+		t.Fatalf("expected no events recorded within the interval, got %+v", e)
+	default:
+	}
+	if got := testutil.ToFloat64(suppressed) - before; got != 3 {
+		t.Errorf("expected 3 suppressed duplicate session events, got %v", got)
+	}
+
+	now = now.Add(time.Minute)
+	r.Warning(tf.TestNodeID, ReasonDuplicateSession, "duplicate session from 10.0.0.4:5000")
+	expectEvent(t, events, ReasonDuplicateSession, "10.0.0.4:5000", "3 DuplicateSession events suppressed")
+}
+
+func TestSessionLifecycleEvents(t *testing.T) {
+	now := time.Now()
+	manager := NewSessionManager(10)
+	var events chan recordedEvent
+	manager.events, events = newTestEventRecorder(&now)
+
+	mockController := gomock.NewController(t)
+	mockConn := mockcon.NewMockConnection(mockController)
+	session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockConn, time.Minute,
+		common.InitNodeMessagePool(tf.TestNodeID), &fake.Clientset{})
+	manager.AddSession(session)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		session.Start()
+	}()
+	expectEvent(t, events, ReasonNodeConnected, api.ProtocolTypeWS)
+
+	mockConn.EXPECT().Close().Return(nil)
+	session.SetTerminateErr(ClosedErr)
+	session.Terminating()
+	<-done
+	expectEvent(t, events, ReasonNodeDisconnected, "closed")
+}
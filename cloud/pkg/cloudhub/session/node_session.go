@@ -34,11 +34,14 @@ import (
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 	deviceconst "github.com/kubeedge/kubeedge/cloud/pkg/devicecontroller/constants"
 	edgeconst "github.com/kubeedge/kubeedge/cloud/pkg/edgecontroller/constants"
 	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
 	v2 "github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao/v2"
 	"github.com/kubeedge/kubeedge/pkg/metaserver/util"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 )
 
@@ -50,8 +53,25 @@ const (
 	TransportErr
 	NodeStopErr
 	QueueShutdownErr
+	// ReplacedErr means the session is replaced by a new session of the node
+	ReplacedErr
+	// ClosedErr means the session is closed by CloudHub, e.g. for the revoked certificate
+	ClosedErr
+	// DrainedErr means the session is closed by the drain when CloudHub stops
+	DrainedErr
 )
 
+// terminateReasons are the reasons of the session termination errors in the disconnect metrics
+var terminateReasons = map[int32]string{
+	NoErr:            "unknown",
+	TransportErr:     "transport_error",
+	NodeStopErr:      "node_stopped",
+	QueueShutdownErr: "queue_shutdown",
+	ReplacedErr:      "replaced",
+	ClosedErr:        "closed",
+	DrainedErr:       "drained",
+}
+
 // ErrWaitTimeout is returned when the condition exited without success.
 var ErrWaitTimeout = errors.New("timed out waiting for the condition")
 
@@ -88,6 +108,13 @@ type NodeSession struct {
 	// stopOnce is used to mark that session Terminating can only be executed once
 	stopOnce sync.Once
 
+	// events records the Events of the session lifecycle, nil if the
+	// session is not added to the session manager
+	events *EventRecorder
+
+	// schemaVersion is the message schema version negotiated with the edge hub
+	schemaVersion int
+
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 }
@@ -111,9 +138,21 @@ func NewNodeSession(
 		nodeMessagePool:   nodeMessagePool,
 		reliableClient:    reliableClient,
 		terminateErr:      NoErr,
+		schemaVersion:     model.SchemaVersionLegacy,
 	}
 }
 
+// SetSchemaVersion sets the message schema version negotiated with the edge hub,
+// it must be set before the session is added to the session manager
+func (ns *NodeSession) SetSchemaVersion(version int) {
+	ns.schemaVersion = version
+}
+
+// SchemaVersion returns the message schema version negotiated with the edge hub
+func (ns *NodeSession) SchemaVersion() int {
+	return ns.schemaVersion
+}
+
 // KeepAliveMessage receive keepalive message from edge node
 func (ns *NodeSession) KeepAliveMessage() {
 	select {
@@ -136,6 +175,23 @@ func (ns *NodeSession) ReceiveMessageAck(parentID string) {
 func (ns *NodeSession) Start() {
 	klog.Infof("Start session for edge node %s", ns.nodeID)
 
+	protocol := ns.protocol()
+	start := time.Now()
+	monitor.ConnectedSessions.WithLabelValues(protocol).Inc()
+	var connectRecorded bool
+	if ns.events != nil {
+		connectRecorded = ns.events.Connected(ns.nodeID, protocol)
+	}
+	defer func() {
+		reason := terminateReasons[ns.GetTerminateErr()]
+		monitor.ConnectedSessions.WithLabelValues(protocol).Dec()
+		monitor.ConnectionDurationSeconds.WithLabelValues(protocol).Observe(time.Since(start).Seconds())
+		monitor.DisconnectsTotal.WithLabelValues(reason).Inc()
+		if ns.events != nil {
+			ns.events.Disconnected(ns.nodeID, connectRecorded, reason, time.Since(start))
+		}
+	}()
+
 	go ns.KeepAliveCheck()
 	go ns.SendAckMessage()
 	go ns.SendNoAckMessage()
@@ -143,6 +199,23 @@ func (ns *NodeSession) Start() {
 	<-ns.ctx.Done()
 }
 
+// protocol returns the protocol of the connection of the session
+func (ns *NodeSession) protocol() string {
+	if _, ok := ns.connection.(*conn.QuicConnection); ok {
+		return api.ProtocolTypeQuic
+	}
+	return api.ProtocolTypeWS
+}
+
+// writeMessage sends the message to the edge node and records it in the message metrics
+func (ns *NodeSession) writeMessage(msg *beehivemodel.Message) error {
+	if err := ns.connection.WriteMessageAsync(msg); err != nil {
+		return err
+	}
+	monitor.ObserveMessageSent(ns.nodeID, msg)
+	return nil
+}
+
 // KeepAliveCheck
 // A goroutine running KeepAliveCheck is started for each connection.
 func (ns *NodeSession) KeepAliveCheck() {
@@ -290,7 +363,11 @@ func (ns *NodeSession) syncNoAckMessage() (bool, error) {
 
 	common.TrimMessage(msg)
 
-	if err := ns.connection.WriteMessageAsync(msg); err != nil {
+	if err := ns.writeMessage(msg); err != nil {
+		if comm.IsMessageSizeError(err) {
+			// the oversized message is dropped before it is written, the connection is still usable
+			return false, fmt.Errorf("drop the message to edge node %s, err: %v, message: %s", ns.nodeID, err, msg.String())
+		}
 		ns.SetTerminateErr(TransportErr)
 		return true, fmt.Errorf("send message to edge node %s err: %v", ns.nodeID, err)
 	}
@@ -328,6 +405,12 @@ func (ns *NodeSession) syncAckMessage() (bool, error) {
 		ns.nodeMessagePool.AckMessageQueue.AddRateLimited(key)
 		return false, fmt.Errorf("send message to node %s err: %v, message: %s", ns.nodeID, err, msg.String())
 
+	case comm.IsMessageSizeError(err):
+		// the oversized message never fits the connection, so it is dropped instead of being retried
+		ns.ackMessageCache.Delete(copyMsg.GetID())
+		ns.nodeMessagePool.AckMessageQueue.Forget(key)
+		return false, fmt.Errorf("drop the message to node %s, err: %v, message: %s", ns.nodeID, err, msg.String())
+
 	default:
 		ns.SetTerminateErr(TransportErr)
 		// if err is Transport Error, we will terminating node session
@@ -343,7 +426,7 @@ func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message)
 	retryCount := 0
 	ticker := time.NewTimer(sendRetryInterval)
 
-	err := ns.connection.WriteMessageAsync(copyMsg)
+	err := ns.writeMessage(copyMsg)
 	if err != nil {
 		return err
 	}
@@ -359,7 +442,7 @@ func (ns *NodeSession) sendMessageWithRetry(copyMsg, msg *beehivemodel.Message)
 				return ErrWaitTimeout
 			}
 
-			err := ns.connection.WriteMessageAsync(copyMsg)
+			err := ns.writeMessage(copyMsg)
 			if err != nil {
 				return err
 			}
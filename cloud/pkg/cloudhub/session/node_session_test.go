@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
@@ -31,6 +32,9 @@ import (
 	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
 	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
 )
 
@@ -340,6 +344,77 @@ func TestNodeSessionSendAckMessage(t *testing.T) {
 	}
 }
 
+func TestNodeSessionMetrics(t *testing.T) {
+	const nodeID = "metrics-node"
+	mockController := gomock.NewController(t)
+	mockConn := mockcon.NewMockConnection(mockController)
+	session := NewNodeSession(nodeID, tf.TestProjectID, mockConn, time.Minute,
+		common.InitNodeMessagePool(nodeID), &fake.Clientset{})
+
+	connected := monitor.ConnectedSessions.WithLabelValues(api.ProtocolTypeWS)
+	closed := monitor.DisconnectsTotal.WithLabelValues("closed")
+	connectedBefore, closedBefore := testutil.ToFloat64(connected), testutil.ToFloat64(closed)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		session.Start()
+	}()
+	for testutil.ToFloat64(connected) != connectedBefore+1 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	msg := beehivemodel.NewMessage("").FillBody("content")
+	mockConn.EXPECT().WriteMessageAsync(msg).Return(nil)
+	sentBefore := testutil.ToFloat64(monitor.NodeMessagesSentTotal.WithLabelValues(nodeID))
+	bytesBefore := testutil.ToFloat64(monitor.NodeBytesSentTotal.WithLabelValues(nodeID))
+	if err := session.writeMessage(msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := testutil.ToFloat64(monitor.NodeMessagesSentTotal.WithLabelValues(nodeID)) - sentBefore; got != 1 {
+		t.Errorf("expected 1 message sent, got %v", got)
+	}
+	if got := testutil.ToFloat64(monitor.NodeBytesSentTotal.WithLabelValues(nodeID)) - bytesBefore; got != float64(len("content")) {
+		t.Errorf("expected %d bytes sent, got %v", len("content"), got)
+	}
+
+	mockConn.EXPECT().Close().Return(nil)
+	session.SetTerminateErr(ClosedErr)
+	session.Terminating()
+	<-done
+
+	if got := testutil.ToFloat64(connected); got != connectedBefore {
+		t.Errorf("expected %v connected sessions, got %v", connectedBefore, got)
+	}
+	if got := testutil.ToFloat64(closed) - closedBefore; got != 1 {
+		t.Errorf("expected 1 closed disconnect, got %v", got)
+	}
+}
+
+func TestNodeMetricsLabelDropped(t *testing.T) {
+	drop := hubconfig.Config.DropNodeMetricsLabel
+	defer func() { hubconfig.Config.DropNodeMetricsLabel = drop }()
+	hubconfig.Config.DropNodeMetricsLabel = true
+
+	mockController := gomock.NewController(t)
+	mockConn := mockcon.NewMockConnection(mockController)
+	session := NewNodeSession("metrics-dropped-node", tf.TestProjectID, mockConn, time.Minute,
+		common.InitNodeMessagePool("metrics-dropped-node"), &fake.Clientset{})
+
+	msg := beehivemodel.NewMessage("")
+	mockConn.EXPECT().WriteMessageAsync(msg).Return(nil)
+	before := testutil.ToFloat64(monitor.NodeMessagesSentTotal.WithLabelValues(""))
+	if err := session.writeMessage(msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := testutil.ToFloat64(monitor.NodeMessagesSentTotal.WithLabelValues("")) - before; got != 1 {
+		t.Errorf("expected 1 message sent without the node label, got %v", got)
+	}
+	if got := testutil.ToFloat64(monitor.NodeMessagesSentTotal.WithLabelValues("metrics-dropped-node")); got != 0 {
+		t.Errorf("expected no message sent with the node label, got %v", got)
+	}
+}
+
 func normalSimulateMessageFunc(pool *common.NodeMessagePool, messages []*beehivemodel.Message) {
 	for _, message := range messages {
 		enqueueAckMessage(pool, message)
@@ -0,0 +1,44 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+)
+
+// ReasonUnsupportedSchemaVersion is the reason of the Warning Event of the node whose
+// edge hub is older than the minimum message schema version
+const ReasonUnsupportedSchemaVersion = "UnsupportedSchemaVersion"
+
+// AdmitSchemaVersion checks the message schema version negotiated with the edge hub of the
+// node against the minimum version. It returns false with the reason if the connection must
+// be rejected, which is sent to the edge hub as the close message and is kept short enough
+// for the websocket close frame.
+func (sm *Manager) AdmitSchemaVersion(nodeID string, version int) (string, bool) {
+	if version >= sm.minSchemaVersion {
+		return "", true
+	}
+
+	reason := fmt.Sprintf("edge schema version %d is older than the minimum version %d, please upgrade edgecore",
+		version, sm.minSchemaVersion)
+	message := fmt.Sprintf("the connection of node %s is rejected, %s", nodeID, reason)
+	klog.Warning(message)
+	sm.events.Warning(nodeID, ReasonUnsupportedSchemaVersion, message)
+	return reason, false
+}
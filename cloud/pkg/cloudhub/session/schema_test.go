@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+)
+
+func TestAdmitSchemaVersion(t *testing.T) {
+	cases := []struct {
+		name       string
+		minVersion int
+		version    int
+		admitted   bool
+	}{
+		{name: "no minimum version", minVersion: 0, version: 1, admitted: true},
+		{name: "minimum version", minVersion: 2, version: 2, admitted: true},
+		{name: "newer than minimum version", minVersion: 1, version: 2, admitted: true},
+		{name: "older than minimum version", minVersion: 2, version: 1, admitted: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			events := make(chan string, 1)
+			manager := NewSessionManager(10)
+			manager.minSchemaVersion = c.minVersion
+			manager.events.record = func(nodeID, _, reason, _ string) { events <- nodeID + ": " + reason }
+
+			reason, admitted := manager.AdmitSchemaVersion(tf.TestNodeID, c.version)
+			if admitted != c.admitted {
+				t.Fatalf("expected admitted %v, got %v", c.admitted, admitted)
+			}
+			if admitted {
+				return
+			}
+			// the payload of the websocket close frame is at most 125 bytes with the 2 bytes close code
+			if !strings.Contains(reason, "upgrade edgecore") || len(reason) > 123 {
+				t.Errorf("expected the short reason asking to upgrade edgecore, got %q", reason)
+			}
+			select {
+			case event := <-events:
+				if event != tf.TestNodeID+": "+ReasonUnsupportedSchemaVersion {
+					t.Errorf("unexpected event: %s", event)
+				}
+			case <-time.After(5 * time.Second):
+				t.Errorf("expected the unsupported schema version event recorded")
+			}
+		})
+	}
+}
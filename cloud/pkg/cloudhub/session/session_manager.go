@@ -17,12 +17,17 @@ limitations under the License.
 package session
 
 import (
+	"crypto/x509"
 	"fmt"
 	"sync"
 	"sync/atomic"
 
 	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/registry"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
 )
 
@@ -35,13 +40,32 @@ type Manager struct {
 	NodeLimit int32
 	// NodeSessions maps a node ID to NodeSession
 	NodeSessions sync.Map
+	// registry records the nodes connected to this cloudHub instance,
+	// nil if the session registry is disabled
+	registry registry.Registry
+	// draining is set when the sessions are drained as cloudHub stops
+	draining atomic.Bool
+	// duplicatePolicy decides whether the new connection of a node with
+	// an active session takes over the session or is rejected
+	duplicatePolicy v1alpha1.DuplicateSessionPolicy
+	// events records the Events of the session lifecycle of the nodes
+	events *EventRecorder
+	// minSchemaVersion is the minimum message schema version of the edge hubs
+	// whose connections are accepted
+	minSchemaVersion int
+	// nodeAdmission checks the Node objects of the connecting nodes,
+	// nil if the node admission is disabled
+	nodeAdmission *nodeAdmission
 }
 
 // NewSessionManager initializes a new SessionManager
 func NewSessionManager(nodeLimit int32) *Manager {
 	return &Manager{
-		NodeLimit:    nodeLimit,
-		NodeSessions: sync.Map{},
+		NodeLimit:        nodeLimit,
+		NodeSessions:     sync.Map{},
+		duplicatePolicy:  hubconfig.Config.DuplicateSessionPolicy,
+		events:           NewEventRecorder(),
+		minSchemaVersion: int(hubconfig.Config.MinEdgeSchemaVersion),
 	}
 }
 
@@ -53,13 +77,21 @@ func (sm *Manager) AddSession(session *NodeSession) {
 	if exists {
 		if oldSession, ok := ons.(*NodeSession); ok {
 			klog.Warningf("session exists for %s, close old session", nodeID)
+			oldSession.SetTerminateErr(ReplacedErr)
 			oldSession.Terminating()
 			atomic.AddInt32(&sm.NodeNumber, -1)
 		}
 	}
 
+	session.events = sm.events
 	sm.NodeSessions.Store(nodeID, session)
 	monitor.ConnectedNodes.Set(float64(atomic.AddInt32(&sm.NodeNumber, 1)))
+	if node := monitor.NodeLabel(nodeID); node != "" {
+		monitor.SessionSchemaVersion.WithLabelValues(node).Set(float64(session.schemaVersion))
+	}
+	if sm.registry != nil {
+		sm.registry.Register(nodeID)
+	}
 }
 
 // DeleteSession delete the node session from session manager
@@ -78,6 +110,23 @@ func (sm *Manager) DeleteSession(session *NodeSession) {
 
 	sm.NodeSessions.Delete(session.nodeID)
 	monitor.ConnectedNodes.Set(float64(atomic.AddInt32(&sm.NodeNumber, -1)))
+	if node := monitor.NodeLabel(session.nodeID); node != "" {
+		monitor.SessionSchemaVersion.DeleteLabelValues(node)
+	}
+	if sm.registry != nil {
+		sm.registry.Unregister(session.nodeID)
+	}
+}
+
+// SetRegistry sets the registry recording the nodes connected to this cloudHub instance,
+// it must be set before the sessions are added.
+func (sm *Manager) SetRegistry(r registry.Registry) {
+	sm.registry = r
+}
+
+// Registry returns the session registry, nil if the session registry is disabled
+func (sm *Manager) Registry() registry.Registry {
+	return sm.registry
 }
 
 // GetSession get the node session for the node
@@ -90,6 +139,67 @@ func (sm *Manager) GetSession(nodeID string) (*NodeSession, bool) {
 	return nil, false
 }
 
+// PeerCertificate returns the client certificate of the active session of the node
+func (sm *Manager) PeerCertificate(nodeID string) (*x509.Certificate, bool) {
+	session, exists := sm.GetSession(nodeID)
+	if !exists {
+		return nil, false
+	}
+	certs := session.connection.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, true
+	}
+	return certs[0], true
+}
+
+// AcksReliableMessages reports whether the node has an active session, and whether the
+// edge hub of the session acknowledges the reliable messages
+func (sm *Manager) AcksReliableMessages(nodeID string) (bool, bool) {
+	session, exists := sm.GetSession(nodeID)
+	if !exists {
+		return false, false
+	}
+	return true, session.connection.ConnectionState().Headers.Get(model.ReliableAck) == "true"
+}
+
+// SchemaVersion reports whether the node has an active session, and the message
+// schema version negotiated with the edge hub of the session
+func (sm *Manager) SchemaVersion(nodeID string) (int, bool) {
+	session, exists := sm.GetSession(nodeID)
+	if !exists {
+		return 0, false
+	}
+	return session.schemaVersion, true
+}
+
+// CloseSession terminates the active session of the node, the session is
+// removed from the session manager when it stops
+func (sm *Manager) CloseSession(nodeID string) {
+	if session, exists := sm.GetSession(nodeID); exists {
+		klog.Warningf("close the session of node %s", nodeID)
+		session.SetTerminateErr(ClosedErr)
+		session.Terminating()
+	}
+}
+
+// CloseRevokedSessions terminates the active sessions whose client certificates are revoked,
+// it returns the IDs of the nodes whose sessions are closed.
+func (sm *Manager) CloseRevokedSessions(isRevoked func(cert *x509.Certificate) bool) []string {
+	var closed []string
+	sm.NodeSessions.Range(func(key, value any) bool {
+		session := value.(*NodeSession)
+		certs := session.connection.ConnectionState().PeerCertificates
+		if len(certs) > 0 && isRevoked(certs[0]) {
+			klog.Warningf("close the session of node %s, its certificate %s is revoked", key, certs[0].SerialNumber)
+			session.SetTerminateErr(ClosedErr)
+			session.Terminating()
+			closed = append(closed, key.(string))
+		}
+		return true
+	})
+	return closed
+}
+
 // ReachLimit checks whether the connected nodes exceeds the node limit number
 func (sm *Manager) ReachLimit() bool {
 	return atomic.LoadInt32(&sm.NodeNumber) >= sm.NodeLimit
@@ -17,13 +17,21 @@ limitations under the License.
 package session
 
 import (
+	"crypto/x509"
+	"math/big"
+	"net/http"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/kubeedge/api/client/clientset/versioned/fake"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/model"
 	tf "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/common/testing"
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/registry"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
 	mockcon "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn/testing"
 )
 
@@ -104,3 +112,188 @@ func TestManager_ReceiveMessageAck(t *testing.T) {
 		t.Errorf("expected err but got nil")
 	}
 }
+
+func TestPeerCertificateAndCloseSession(t *testing.T) {
+	client := &fake.Clientset{}
+	nmp := common.InitNodeMessagePool(tf.TestNodeID)
+	mockController := gomock.NewController(t)
+	mockConn := mockcon.NewMockConnection(mockController)
+	session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockConn, tf.KeepaliveInterval, nmp, client)
+
+	manager := NewSessionManager(10)
+	if _, exist := manager.PeerCertificate(tf.TestNodeID); exist {
+		t.Errorf("expected session not exist but got it")
+	}
+	manager.AddSession(session)
+
+	cert := &x509.Certificate{SerialNumber: big.NewInt(1)}
+	mockConn.EXPECT().ConnectionState().Return(conn.ConnectionState{PeerCertificates: []*x509.Certificate{cert}})
+	actualCert, exist := manager.PeerCertificate(tf.TestNodeID)
+	if !exist || actualCert != cert {
+		t.Errorf("expected: %#v, got: %#v", cert, actualCert)
+	}
+
+	mockConn.EXPECT().Close().Return(nil)
+	manager.CloseSession(tf.TestNodeID)
+	select {
+	case <-session.ctx.Done():
+	default:
+		t.Errorf("expected session terminated")
+	}
+	if got := session.GetTerminateErr(); got != ClosedErr {
+		t.Errorf("expected terminate err %d, got %d", ClosedErr, got)
+	}
+}
+
+func TestAddSessionReplacesOldSession(t *testing.T) {
+	client := &fake.Clientset{}
+	mockController := gomock.NewController(t)
+	oldConn := mockcon.NewMockConnection(mockController)
+	oldSession := NewNodeSession(tf.TestNodeID, tf.TestProjectID, oldConn, tf.KeepaliveInterval,
+		common.InitNodeMessagePool(tf.TestNodeID), client)
+	newSession := NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockcon.NewMockConnection(mockController),
+		tf.KeepaliveInterval, common.InitNodeMessagePool(tf.TestNodeID), client)
+
+	manager := NewSessionManager(10)
+	manager.AddSession(oldSession)
+	oldConn.EXPECT().Close().Return(nil)
+	manager.AddSession(newSession)
+
+	if got := oldSession.GetTerminateErr(); got != ReplacedErr {
+		t.Errorf("expected terminate err %d, got %d", ReplacedErr, got)
+	}
+	if actual, _ := manager.GetSession(tf.TestNodeID); actual != newSession {
+		t.Errorf("expected the new session kept, got: %#v", actual)
+	}
+}
+
+func TestCloseRevokedSessions(t *testing.T) {
+	client := &fake.Clientset{}
+	mockController := gomock.NewController(t)
+	manager := NewSessionManager(10)
+
+	newSession := func(nodeID string, serial int64) (*NodeSession, *mockcon.MockConnection) {
+		mockConn := mockcon.NewMockConnection(mockController)
+		cert := &x509.Certificate{SerialNumber: big.NewInt(serial)}
+		mockConn.EXPECT().ConnectionState().Return(conn.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}).AnyTimes()
+		session := NewNodeSession(nodeID, tf.TestProjectID, mockConn, tf.KeepaliveInterval, common.InitNodeMessagePool(nodeID), client)
+		manager.AddSession(session)
+		return session, mockConn
+	}
+	revoked, revokedConn := newSession("node1", 1)
+	valid, _ := newSession("node2", 2)
+
+	revokedConn.EXPECT().Close().Return(nil)
+	closed := manager.CloseRevokedSessions(func(cert *x509.Certificate) bool {
+		return cert.SerialNumber.Int64() == 1
+	})
+	if len(closed) != 1 || closed[0] != "node1" {
+		t.Errorf("expected node1 closed, got: %v", closed)
+	}
+	select {
+	case <-revoked.ctx.Done():
+	default:
+		t.Errorf("expected the session of node1 terminated")
+	}
+	select {
+	case <-valid.ctx.Done():
+		t.Errorf("expected the session of node2 not terminated")
+	default:
+	}
+}
+
+func TestAcksReliableMessages(t *testing.T) {
+	client := &fake.Clientset{}
+	mockController := gomock.NewController(t)
+	manager := NewSessionManager(10)
+
+	if connected, _ := manager.AcksReliableMessages(tf.TestNodeID); connected {
+		t.Errorf("expected the node not connected")
+	}
+
+	headers := http.Header{}
+	headers.Set(model.ReliableAck, "true")
+	mockConn := mockcon.NewMockConnection(mockController)
+	mockConn.EXPECT().ConnectionState().Return(conn.ConnectionState{Headers: headers})
+	manager.AddSession(NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockConn, tf.KeepaliveInterval,
+		common.InitNodeMessagePool(tf.TestNodeID), client))
+	if connected, acks := manager.AcksReliableMessages(tf.TestNodeID); !connected || !acks {
+		t.Errorf("expected the node connected and acknowledging, got %v, %v", connected, acks)
+	}
+
+	legacyConn := mockcon.NewMockConnection(mockController)
+	legacyConn.EXPECT().ConnectionState().Return(conn.ConnectionState{Headers: http.Header{}})
+	manager.AddSession(NewNodeSession("legacy-node", tf.TestProjectID, legacyConn, tf.KeepaliveInterval,
+		common.InitNodeMessagePool("legacy-node"), client))
+	if connected, acks := manager.AcksReliableMessages("legacy-node"); !connected || acks {
+		t.Errorf("expected the legacy node connected and not acknowledging, got %v, %v", connected, acks)
+	}
+}
+
+func TestSchemaVersion(t *testing.T) {
+	mockController := gomock.NewController(t)
+	manager := NewSessionManager(10)
+
+	if _, connected := manager.SchemaVersion(tf.TestNodeID); connected {
+		t.Errorf("expected the node not connected")
+	}
+
+	session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockcon.NewMockConnection(mockController),
+		tf.KeepaliveInterval, common.InitNodeMessagePool(tf.TestNodeID), &fake.Clientset{})
+	if version := session.SchemaVersion(); version != model.SchemaVersionLegacy {
+		t.Errorf("expected the legacy schema version by default, got %d", version)
+	}
+	session.SetSchemaVersion(model.SchemaVersionCurrent)
+	manager.AddSession(session)
+	if version, connected := manager.SchemaVersion(tf.TestNodeID); !connected || version != model.SchemaVersionCurrent {
+		t.Errorf("expected the node connected with version %d, got %v, %d", model.SchemaVersionCurrent, connected, version)
+	}
+	if got := testutil.ToFloat64(monitor.SessionSchemaVersion.WithLabelValues(tf.TestNodeID)); got != model.SchemaVersionCurrent {
+		t.Errorf("expected the schema version metric %d, got %v", model.SchemaVersionCurrent, got)
+	}
+
+	manager.DeleteSession(session)
+	if monitor.SessionSchemaVersion.DeleteLabelValues(tf.TestNodeID) {
+		t.Errorf("expected the schema version metric of the node deleted")
+	}
+}
+
+type recordingRegistry struct {
+	registry.Registry
+	nodes map[string]bool
+}
+
+func (r *recordingRegistry) Register(nodeID string) { r.nodes[nodeID] = true }
+
+func (r *recordingRegistry) Unregister(nodeID string) { delete(r.nodes, nodeID) }
+
+func TestSessionRegistry(t *testing.T) {
+	client := &fake.Clientset{}
+	mockController := gomock.NewController(t)
+	reg := &recordingRegistry{nodes: map[string]bool{}}
+	manager := NewSessionManager(10)
+	manager.SetRegistry(reg)
+
+	oldConn := mockcon.NewMockConnection(mockController)
+	oldConn.EXPECT().Close().Return(nil).AnyTimes()
+	oldSession := NewNodeSession(tf.TestNodeID, tf.TestProjectID, oldConn,
+		tf.KeepaliveInterval, common.InitNodeMessagePool(tf.TestNodeID), client)
+	manager.AddSession(oldSession)
+	if !reg.nodes[tf.TestNodeID] {
+		t.Fatalf("expected the node registered")
+	}
+
+	// the node reconnects, deleting the replaced session keeps the node registered
+	session := NewNodeSession(tf.TestNodeID, tf.TestProjectID, mockcon.NewMockConnection(mockController),
+		tf.KeepaliveInterval, common.InitNodeMessagePool(tf.TestNodeID), client)
+	manager.AddSession(session)
+	manager.DeleteSession(oldSession)
+	if !reg.nodes[tf.TestNodeID] {
+		t.Errorf("expected the reconnected node registered")
+	}
+
+	manager.DeleteSession(session)
+	if reg.nodes[tf.TestNodeID] {
+		t.Errorf("expected the node unregistered")
+	}
+}
@@ -61,6 +61,10 @@ func (s *cloudStream) Start() {
 		go ts.Start()
 
 		server := newStreamServer(ts)
+		if sm, err := cloudhub.GetSessionManager(); err == nil {
+			// route the requests of the nodes connected to the other instances
+			server.registry = sm.Registry()
+		}
 		// start stream server to accept kube-apiserver connection
 		go server.Start()
 	}
@@ -21,9 +21,13 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/httputil"
+	"net/url"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/emicklei/go-restful"
@@ -31,18 +35,30 @@ import (
 	"k8s.io/apimachinery/pkg/util/httpstream"
 	"k8s.io/klog/v2"
 
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/registry"
 	"github.com/kubeedge/kubeedge/cloud/pkg/cloudstream/config"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/client"
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/pkg/stream/flushwriter"
 )
 
+// ForwardedHeader marks the requests forwarded from the stream server of another CloudHub
+// instance, which are not forwarded again
+const ForwardedHeader = "X-KubeEdge-Stream-Forwarded"
+
 type StreamServer struct {
 	// nextMessageID indicates the next message id
 	// it starts from 0 , when receive a new apiserver connection and then add 1
 	nextMessageID uint64
 	container     *restful.Container
 	tunnel        *TunnelServer
+
+	// registry is the session registry of CloudHub, nil if it is disabled
+	registry registry.Registry
+	// streamPort is the port of the stream servers of the other instances
+	streamPort int
+	// forwardTransport is the transport of the requests forwarded to the other instances
+	forwardTransport http.RoundTripper
 }
 
 func newStreamServer(t *TunnelServer) *StreamServer {
@@ -129,6 +145,9 @@ func (s *StreamServer) getContainerLogs(r *restful.Request, w *restful.Response)
 
 	session, ok := s.tunnel.getSession(sessionKey)
 	if !ok {
+		if s.forward(sessionKey, w.ResponseWriter, r.Request) {
+			return
+		}
 		err = fmt.Errorf("can not find %v session ", sessionKey)
 		return
 	}
@@ -189,6 +208,9 @@ func (s *StreamServer) getMetrics(r *restful.Request, w *restful.Response) {
 	}
 	session, ok := s.tunnel.getSession(sessionKey)
 	if !ok {
+		if s.forward(sessionKey, w.ResponseWriter, r.Request) {
+			return
+		}
 		err = fmt.Errorf("can not find %v session ", sessionKey)
 		return
 	}
@@ -238,6 +260,9 @@ func (s *StreamServer) getExec(request *restful.Request, response *restful.Respo
 	}
 	session, ok := s.tunnel.getSession(sessionKey)
 	if !ok {
+		if s.forward(sessionKey, response.ResponseWriter, request.Request) {
+			return
+		}
 		err = fmt.Errorf("exec: can not find %v session ", sessionKey)
 		return
 	}
@@ -308,6 +333,9 @@ func (s *StreamServer) getAttach(request *restful.Request, response *restful.Res
 	}
 	session, ok := s.tunnel.getSession(sessionKey)
 	if !ok {
+		if s.forward(sessionKey, response.ResponseWriter, request.Request) {
+			return
+		}
 		err = fmt.Errorf("attach: can not find %v session ", sessionKey)
 		return
 	}
@@ -362,6 +390,39 @@ func (s *StreamServer) getAttach(request *restful.Request, response *restful.Res
 	}
 }
 
+// forward proxies the request to the stream server of the CloudHub instance which the node is
+// connected to, if the session registry is enabled and the node is connected to another instance.
+// The edge nodes are expected to connect their tunnels to the same instance as their sessions.
+// It returns false if the request is not forwarded.
+func (s *StreamServer) forward(nodeID string, w http.ResponseWriter, r *http.Request) bool {
+	if s.registry == nil || r.Header.Get(ForwardedHeader) != "" {
+		return false
+	}
+	owner, ok := s.registry.Owner(nodeID)
+	if !ok || owner.Identity == s.registry.Self().Identity {
+		return false
+	}
+	host, _, err := net.SplitHostPort(owner.Address)
+	if err != nil {
+		klog.Warningf("invalid address %s of instance %s, err: %v", owner.Address, owner.Identity, err)
+		return false
+	}
+	target := &url.URL{Scheme: "https", Host: net.JoinHostPort(host, strconv.Itoa(s.streamPort))}
+	klog.V(4).Infof("forward the stream request %s of node %s to instance %s", r.URL.Path, nodeID, owner.Identity)
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		req.Header.Set(ForwardedHeader, s.registry.Self().Identity)
+	}
+	proxy.Transport = s.forwardTransport
+	// the logs are streamed to the apiserver
+	proxy.FlushInterval = -1
+	proxy.ServeHTTP(w, r)
+	return true
+}
+
 func (s *StreamServer) getSessionKey(urlPath string) (string, error) {
 	// extract pod namespace and pod name from request
 	meta := strings.Split(urlPath, "/")
@@ -394,6 +455,23 @@ func (s *StreamServer) Start() {
 	}
 	pool.AppendCertsFromPEM(data)
 
+	if s.registry != nil {
+		// the stream servers of the instances are served with the same certificate
+		cert, err := tls.LoadX509KeyPair(config.Config.TLSStreamCertFile, config.Config.TLSStreamPrivateKeyFile)
+		if err != nil {
+			klog.Exitf("Load tls stream cert error %v", err)
+			return
+		}
+		s.streamPort = int(config.Config.StreamPort)
+		s.forwardTransport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:      pool,
+				Certificates: []tls.Certificate{cert},
+				MinVersion:   tls.VersionTLS12,
+			},
+		}
+	}
+
 	streamServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", config.Config.StreamPort),
 		Handler: s.container,
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudstream
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/registry"
+)
+
+// fakeRegistry is the registry shared by the instances in the test
+type fakeRegistry struct {
+	self   registry.Instance
+	owners map[string]registry.Instance
+}
+
+func (r *fakeRegistry) Self() registry.Instance { return r.self }
+
+func (r *fakeRegistry) Register(nodeID string) { r.owners[nodeID] = r.self }
+
+func (r *fakeRegistry) Unregister(nodeID string) { delete(r.owners, nodeID) }
+
+func (r *fakeRegistry) Owner(nodeID string) (registry.Instance, bool) {
+	owner, ok := r.owners[nodeID]
+	return owner, ok
+}
+
+func TestStreamServerForward(t *testing.T) {
+	// the stream server of instance B, which the edge node is connected to
+	forwarded := make(chan *http.Request, 1)
+	srvB := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded <- r
+		_, _ = w.Write([]byte("metrics of edge-node"))
+	}))
+	defer srvB.Close()
+	_, port, err := net.SplitHostPort(srvB.Listener.Addr().String())
+	assert.NoError(t, err)
+	streamPort, err := strconv.Atoi(port)
+	assert.NoError(t, err)
+
+	instanceA := registry.Instance{Identity: "instance-a", Address: "127.0.0.1:10000"}
+	instanceB := registry.Instance{Identity: "instance-b", Address: "127.0.0.1:10002"}
+	owners := map[string]registry.Instance{
+		"edge-node":  instanceB,
+		"local-node": instanceA,
+	}
+
+	cases := []struct {
+		name          string
+		registry      registry.Registry
+		node          string
+		forwardedBy   string
+		wantForwarded bool
+	}{
+		{
+			name:          "connected to another instance",
+			registry:      &fakeRegistry{self: instanceA, owners: owners},
+			node:          "edge-node",
+			wantForwarded: true,
+		},
+		{
+			name:     "connected to the instance itself",
+			registry: &fakeRegistry{self: instanceA, owners: owners},
+			node:     "local-node",
+		},
+		{
+			name:     "not connected",
+			registry: &fakeRegistry{self: instanceA, owners: owners},
+			node:     "unknown-node",
+		},
+		{
+			name:        "already forwarded",
+			registry:    &fakeRegistry{self: instanceA, owners: owners},
+			node:        "edge-node",
+			forwardedBy: "instance-c",
+		},
+		{
+			name: "registry disabled",
+			node: "edge-node",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := newStreamServer(newTunnelServer(testTunnelPort))
+			s.registry = c.registry
+			s.streamPort = streamPort
+			s.forwardTransport = srvB.Client().Transport
+			s.installDebugHandler()
+
+			req := httptest.NewRequest(http.MethodGet, "/stats/summary", nil)
+			req.Header.Set("X-Forwarded-Uri", "/api/v1/nodes/"+c.node+"/proxy/stats/summary")
+			if c.forwardedBy != "" {
+				req.Header.Set(ForwardedHeader, c.forwardedBy)
+			}
+			w := httptest.NewRecorder()
+			s.container.ServeHTTP(w, req)
+
+			if !c.wantForwarded {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+				assert.Len(t, forwarded, 0)
+				return
+			}
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "metrics of edge-node", w.Body.String())
+			r := <-forwarded
+			assert.Equal(t, "/stats/summary", r.URL.Path)
+			assert.Equal(t, "/api/v1/nodes/edge-node/proxy/stats/summary", r.Header.Get("X-Forwarded-Uri"))
+			assert.Equal(t, "instance-a", r.Header.Get(ForwardedHeader))
+		})
+	}
+}
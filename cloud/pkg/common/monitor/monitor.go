@@ -18,6 +18,8 @@ package monitor
 
 import (
 	"context"
+	"crypto/x509"
+	"math"
 	"net/http"
 	"net/http/pprof"
 	"sync"
@@ -29,6 +31,10 @@ import (
 
 	config "github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
 	beehivecontext "github.com/kubeedge/beehive/pkg/core/context"
+	beehivemodel "github.com/kubeedge/beehive/pkg/core/model"
+	hubconfig "github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/config"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/lane"
 )
 
 const (
@@ -36,6 +42,9 @@ const (
 
 	// CloudHubSubsystem - subsystem name used by CloudHub
 	CloudHubSubsystem = "CloudHub"
+
+	// EdgeControllerSubsystem - subsystem name used by EdgeController
+	EdgeControllerSubsystem = "EdgeController"
 )
 
 var (
@@ -47,6 +56,382 @@ var (
 			Help:      "Number of nodes that connected to the cloudHub instance",
 		},
 	)
+
+	SigningQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "signing_queue_depth",
+			Help:      "Number of edge certificate signing requests waiting in the queue",
+		},
+	)
+
+	SigningQueueWaitSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "signing_queue_wait_seconds",
+			Help:      "Time that edge certificate signing requests wait in the queue",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+		},
+	)
+
+	SigningValidationSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "signing_validation_seconds",
+			Help:      "Time spent parsing and validating the edge certificate signing requests",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 15),
+		},
+	)
+
+	SigningKeyOperationSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "signing_key_operation_seconds",
+			Help:      "Time spent signing the edge certificates with the CA private key",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 15),
+		},
+	)
+
+	CertsSignedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "certs_signed_total",
+			Help:      "Number of edge certificates signed",
+		},
+	)
+
+	CertSignFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "cert_sign_failures_total",
+			Help:      "Number of edge certificate requests that failed, by the reason of the failure",
+		},
+		[]string{"reason"},
+	)
+
+	CSRDedupHitsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "csr_dedup_hits_total",
+			Help:      "Number of repeated edge certificate requests responded with the certificate signed before",
+		},
+	)
+
+	SigningWebhookRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "signing_webhook_requests_total",
+			Help:      "Number of edge certificate requests reviewed by the signing webhook, by the result of the review",
+		},
+		[]string{"result"},
+	)
+
+	DuplicateSessionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "duplicate_sessions_total",
+			Help:      "Number of connections of the edge nodes which already have an active session, by the action taken",
+		},
+		[]string{"node", "action"},
+	)
+
+	NodeAdmissionDeniedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "node_admission_denied_total",
+			Help:      "Number of connections of the edge nodes refused by the node admission, by the reason",
+		},
+		[]string{"reason"},
+	)
+
+	NodeQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "node_queue_depth",
+			Help:      "Number of downstream messages waiting to be sent to the edge node, by the priority lane",
+		},
+		[]string{"node", "lane"},
+	)
+
+	OfflineMessages = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "offline_messages",
+			Help:      "Number of downstream messages stored for the disconnected edge node",
+		},
+		[]string{"node"},
+	)
+
+	OfflineBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "offline_bytes",
+			Help:      "Total size of the contents of the downstream messages stored for the disconnected edge node",
+		},
+		[]string{"node"},
+	)
+
+	SuppressedNodeEventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "suppressed_node_events_total",
+			Help:      "Number of the Events of the edge nodes suppressed by the rate limit, by the reason",
+		},
+		[]string{"reason"},
+	)
+
+	OfflineEvictionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "offline_evictions_total",
+			Help:      "Number of downstream messages evicted from the store of the disconnected edge node, by the reason",
+		},
+		[]string{"node", "reason"},
+	)
+
+	UpstreamMessagesCoalescedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: EdgeControllerSubsystem,
+			Name:      "upstream_messages_coalesced_total",
+			Help:      "Number of the upstream messages superseded by a later message of the same object before being written to the apiserver, by the resource type",
+		},
+		[]string{"resource"},
+	)
+
+	TokenVerifyFailuresTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "token_verify_failures_total",
+			Help:      "Number of edge certificate requests whose token failed to be verified",
+		},
+	)
+
+	SigningSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "signing_seconds",
+			Help:      "Time spent signing the edge certificates, including the validation and the queue wait",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+		},
+	)
+
+	CertReloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "cert_reloads_total",
+			Help:      "Number of reloads of the server certificate and the client CAs of CloudHub, by the kind and the result",
+		},
+		[]string{"kind", "result"},
+	)
+
+	NodeCertExpirySeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kubeedge",
+			Name:      "node_cert_expiry_seconds",
+			Help:      "Expiration of the certificate that the edge node presented most recently, in seconds since the epoch",
+		},
+		[]string{"node"},
+	)
+
+	NodeMessagesDroppedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "node_messages_dropped_total",
+			Help:      "Number of upstream messages of the edge node dropped by the upstream rate limit",
+		},
+		[]string{"node"},
+	)
+
+	SessionSchemaVersion = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "session_schema_version",
+			Help:      "Message schema version negotiated with the edge hub of the connected edge node",
+		},
+		[]string{"node"},
+	)
+
+	ConnectedSessions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "connected_sessions",
+			Help:      "Number of the sessions of the edge nodes served by the cloudHub instance, by the protocol",
+		},
+		[]string{"protocol"},
+	)
+
+	NodeMessagesSentTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "node_messages_sent_total",
+			Help:      "Number of messages sent to the edge node",
+		},
+		[]string{"node"},
+	)
+
+	NodeMessagesReceivedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "node_messages_received_total",
+			Help:      "Number of messages received from the edge node",
+		},
+		[]string{"node"},
+	)
+
+	NodeBytesSentTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "node_bytes_sent_total",
+			Help:      "Bytes of the contents of the messages sent to the edge node",
+		},
+		[]string{"node"},
+	)
+
+	NodeBytesReceivedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "node_bytes_received_total",
+			Help:      "Bytes of the contents of the messages received from the edge node",
+		},
+		[]string{"node"},
+	)
+
+	ConnectionDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "connection_duration_seconds",
+			Help:      "Duration of the sessions of the edge nodes, by the protocol",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 12),
+		},
+		[]string{"protocol"},
+	)
+
+	DisconnectsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "disconnects_total",
+			Help:      "Number of the sessions of the edge nodes ended, by the reason",
+		},
+		[]string{"reason"},
+	)
+
+	ReliableMessagesDroppedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "reliable_messages_dropped_total",
+			Help:      "Number of reliable messages to the edge node dropped unacknowledged, by the reason",
+		},
+		[]string{"node", "reason"},
+	)
+
+	ForwardedMessagesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "kubeedge",
+			Subsystem: "cloudhub",
+			Name:      "forwarded_messages_total",
+			Help:      "Number of downstream messages forwarded to the CloudHub instances which the edge nodes are connected to, by the result",
+		},
+		[]string{"result"},
+	)
+
+	NodesCertExpiringTotal = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "kubeedge",
+			Name:      "nodes_cert_expiring_total",
+			Help:      "Number of edge nodes whose certificates expire within the threshold of the cert expiry scan",
+		},
+	)
+
+	CARemainingValiditySeconds = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "ca_remaining_validity_seconds",
+			Help:      "Seconds until the CA certificate of CloudHub expires, negative if it has expired",
+		},
+		caRemainingValidity,
+	)
+
+	WebSocketPayloadBytesTotal = prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "websocket_payload_bytes_total",
+			Help:      "Bytes of the messages sent to the edge nodes over websocket before they are compressed",
+		},
+		func() float64 { return float64(lane.GetWSStats().PayloadBytes) },
+	)
+
+	WebSocketWireBytesTotal = prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: CloudHubSubsystem,
+			Name:      "websocket_wire_bytes_total",
+			Help:      "Bytes written to the network by the websocket server, including the frame headers and the TLS records",
+		},
+		func() float64 { return float64(lane.GetWSStats().WireBytes) },
+	)
+
+	OversizedReadMessagesTotal = prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace:   metricNamespace,
+			Subsystem:   CloudHubSubsystem,
+			Name:        "oversized_messages_total",
+			Help:        "Number of messages dropped as they exceed the max message size of the protocol",
+			ConstLabels: prometheus.Labels{"direction": "read"},
+		},
+		func() float64 { return float64(lane.GetOversizedStats().ReadMessages) },
+	)
+
+	OversizedWriteMessagesTotal = prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace:   metricNamespace,
+			Subsystem:   CloudHubSubsystem,
+			Name:        "oversized_messages_total",
+			Help:        "Number of messages dropped as they exceed the max message size of the protocol",
+			ConstLabels: prometheus.Labels{"direction": "write"},
+		},
+		func() float64 { return float64(lane.GetOversizedStats().WriteMessages) },
+	)
+
+	TracedMessagesTotal = prometheus.NewCounterFunc(
+		prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: EdgeControllerSubsystem,
+			Name:      "traced_messages_total",
+			Help:      "Number of messages sent to the edge nodes which are sampled and carry a trace id",
+		},
+		func() float64 { return float64(msgtrace.Traced()) },
+	)
 )
 
 var registerOnce sync.Once
@@ -56,10 +441,95 @@ func registerMetrics() {
 	registerOnce.Do(func() {
 		prometheus.MustRegister(
 			ConnectedNodes,
+			SigningQueueDepth,
+			SigningQueueWaitSeconds,
+			SigningValidationSeconds,
+			SigningKeyOperationSeconds,
+			CertsSignedTotal,
+			CertSignFailuresTotal,
+			CSRDedupHitsTotal,
+			SigningWebhookRequestsTotal,
+			TokenVerifyFailuresTotal,
+			SigningSeconds,
+			CertReloadsTotal,
+			CARemainingValiditySeconds,
+			NodeCertExpirySeconds,
+			NodesCertExpiringTotal,
+			WebSocketPayloadBytesTotal,
+			WebSocketWireBytesTotal,
+			OversizedReadMessagesTotal,
+			OversizedWriteMessagesTotal,
+			TracedMessagesTotal,
+			NodeMessagesDroppedTotal,
+			ConnectedSessions,
+			SessionSchemaVersion,
+			NodeMessagesSentTotal,
+			NodeMessagesReceivedTotal,
+			NodeBytesSentTotal,
+			NodeBytesReceivedTotal,
+			ConnectionDurationSeconds,
+			DisconnectsTotal,
+			ReliableMessagesDroppedTotal,
+			ForwardedMessagesTotal,
+			DuplicateSessionsTotal,
+			NodeAdmissionDeniedTotal,
+			NodeQueueDepth,
+			OfflineMessages,
+			OfflineBytes,
+			OfflineEvictionsTotal,
+			SuppressedNodeEventsTotal,
+			UpstreamMessagesCoalescedTotal,
 		)
 	})
 }
 
+// caRemainingValidity returns the seconds until the CA certificate of CloudHub expires,
+// it returns NaN if the CA is not loaded so that no expiry alert fires.
+func caRemainingValidity() float64 {
+	if len(hubconfig.Config.Ca) == 0 {
+		return math.NaN()
+	}
+	ca, err := x509.ParseCertificate(hubconfig.Config.Ca)
+	if err != nil {
+		klog.Warningf("failed to parse the CA certificate, err: %v", err)
+		return math.NaN()
+	}
+	return time.Until(ca.NotAfter).Seconds()
+}
+
+// NodeLabel returns the value of the node label of the message metrics, it is empty if the node
+// label is dropped, so that the metrics of all the nodes are aggregated.
+func NodeLabel(nodeID string) string {
+	if hubconfig.Config.DropNodeMetricsLabel {
+		return ""
+	}
+	return nodeID
+}
+
+// ObserveMessageSent records a message sent to the edge node.
+func ObserveMessageSent(nodeID string, msg *beehivemodel.Message) {
+	node := NodeLabel(nodeID)
+	NodeMessagesSentTotal.WithLabelValues(node).Inc()
+	NodeBytesSentTotal.WithLabelValues(node).Add(contentSize(msg))
+}
+
+// ObserveMessageReceived records a message received from the edge node.
+func ObserveMessageReceived(nodeID string, msg *beehivemodel.Message) {
+	node := NodeLabel(nodeID)
+	NodeMessagesReceivedTotal.WithLabelValues(node).Inc()
+	NodeBytesReceivedTotal.WithLabelValues(node).Add(contentSize(msg))
+}
+
+// contentSize returns the size of the content of the message, the content which is not raw data
+// is measured by its JSON encoding.
+func contentSize(msg *beehivemodel.Message) float64 {
+	data, err := msg.GetContentData()
+	if err != nil {
+		return 0
+	}
+	return float64(len(data))
+}
+
 func InstallHandlerForPProf(mux *http.ServeMux) {
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRecordEvent(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	RecordEvent(context.TODO(), kubeClient, "node1", corev1.EventTypeWarning, "TestReason", "test message")
+	RecordEvent(context.TODO(), kubeClient, "node2", corev1.EventTypeNormal, "TestReason", "another message")
+
+	events, err := kubeClient.CoreV1().Events(metav1.NamespaceDefault).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, events.Items, 2)
+	byNode := make(map[string]corev1.Event)
+	for _, e := range events.Items {
+		byNode[e.InvolvedObject.Name] = e
+	}
+	e := byNode["node1"]
+	assert.Equal(t, "Node", e.InvolvedObject.Kind)
+	assert.Equal(t, corev1.EventTypeWarning, e.Type)
+	assert.Equal(t, "TestReason", e.Reason)
+	assert.Equal(t, "test message", e.Message)
+	assert.Equal(t, EventSourceCloudHub, e.Source.Component)
+	assert.Equal(t, int32(1), e.Count)
+	assert.Equal(t, e.FirstTimestamp, e.LastTimestamp)
+	assert.Equal(t, corev1.EventTypeNormal, byNode["node2"].Type)
+}
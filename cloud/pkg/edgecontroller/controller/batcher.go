@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+// statusBatcher coalesces the update messages of the same object from the edge arriving
+// within the window, only the latest of them is passed to the workers writing the apiserver.
+// The messages of the other operations are not delayed, the pending updates are flushed
+// ahead of them so that the messages of an object keep their order.
+type statusBatcher struct {
+	resourceType string
+	window       time.Duration
+	maxBatch     int
+	out          chan model.Message
+	// immediate reports whether the update must be written without waiting for the window,
+	// such as the status of a terminated pod which may be deleted then
+	immediate func(msg model.Message) bool
+	// supersede is called with the message replaced by a later message of the same object,
+	// the edge may be waiting for its response
+	supersede func(msg model.Message)
+
+	// flushMu serializes the flushes so that a batch is passed to the workers after the
+	// batches taken before it
+	flushMu sync.Mutex
+
+	mu sync.Mutex
+	// keys records the objects of the pending messages in the order of their first arrival
+	keys    []string
+	pending map[string]model.Message
+	timer   *time.Timer
+}
+
+func newStatusBatcher(resourceType string, window time.Duration, maxBatch int, out chan model.Message) *statusBatcher {
+	return &statusBatcher{
+		resourceType: resourceType,
+		window:       window,
+		maxBatch:     maxBatch,
+		out:          out,
+		pending:      make(map[string]model.Message),
+	}
+}
+
+// Add passes the message to the workers, the update message is held until the window ends
+func (b *statusBatcher) Add(msg model.Message) {
+	if b.window <= 0 {
+		b.out <- msg
+		return
+	}
+	// the message of several objects, such as the status of the pods in a namespace, is not coalesced
+	if name, _ := messagelayer.GetResourceName(msg); msg.GetOperation() != model.UpdateOperation || name == "" {
+		b.flushMu.Lock()
+		defer b.flushMu.Unlock()
+		b.send(b.take())
+		b.out <- msg
+		return
+	}
+
+	key := msg.GetResource()
+	b.mu.Lock()
+	old, ok := b.pending[key]
+	b.pending[key] = msg
+	if !ok {
+		b.keys = append(b.keys, key)
+	}
+	full := len(b.keys) >= b.maxBatch
+	if b.timer == nil && !full {
+		b.timer = time.AfterFunc(b.window, b.Flush)
+	}
+	b.mu.Unlock()
+
+	if ok {
+		klog.V(4).Infof("message: %s of %s is superseded by message: %s", old.GetID(), key, msg.GetID())
+		monitor.UpstreamMessagesCoalescedTotal.WithLabelValues(b.resourceType).Inc()
+		if b.supersede != nil {
+			b.supersede(old)
+		}
+	}
+	if full || (b.immediate != nil && b.immediate(msg)) {
+		b.Flush()
+	}
+}
+
+// Flush passes the pending messages to the workers
+func (b *statusBatcher) Flush() {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	b.send(b.take())
+}
+
+// take removes the pending messages in the order of their first arrival
+func (b *statusBatcher) take() []model.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.keys) == 0 {
+		return nil
+	}
+	batch := make([]model.Message, 0, len(b.keys))
+	for _, key := range b.keys {
+		batch = append(batch, b.pending[key])
+		delete(b.pending, key)
+	}
+	b.keys = b.keys[:0]
+	return batch
+}
+
+func (b *statusBatcher) send(batch []model.Message) {
+	for _, msg := range batch {
+		b.out <- msg
+	}
+}
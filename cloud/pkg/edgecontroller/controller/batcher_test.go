@@ -0,0 +1,175 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/monitor"
+)
+
+func newStatusMessage(id, name, operation string) model.Message {
+	return model.Message{
+		Header: model.MessageHeader{ID: id},
+		Router: model.MessageRoute{
+			Resource:  fmt.Sprintf("node/%s/%s/%s/%s", defaultNodeID, defaultNamespace, model.ResourceTypePodStatus, name),
+			Operation: operation,
+		},
+	}
+}
+
+// receiveIDs receives n messages from the channel and returns their IDs
+func receiveIDs(t *testing.T, out chan model.Message, n int) []string {
+	t.Helper()
+	var ids []string
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-out:
+			ids = append(ids, msg.GetID())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %d messages, got %v", n, ids)
+		}
+	}
+	return ids
+}
+
+func expectNoMessage(t *testing.T, out chan model.Message) {
+	t.Helper()
+	select {
+	case msg := <-out:
+		t.Fatalf("expected no message passed yet, got %s", msg.GetID())
+	default:
+	}
+}
+
+func TestStatusBatcherCoalesce(t *testing.T) {
+	out := make(chan model.Message, 10)
+	b := newStatusBatcher(model.ResourceTypePodStatus, time.Hour, 10, out)
+	var superseded []string
+	b.supersede = func(msg model.Message) { superseded = append(superseded, msg.GetID()) }
+	coalesced := monitor.UpstreamMessagesCoalescedTotal.WithLabelValues(model.ResourceTypePodStatus)
+	before := testutil.ToFloat64(coalesced)
+
+	b.Add(newStatusMessage("a1", "pod-a", model.UpdateOperation))
+	b.Add(newStatusMessage("b1", "pod-b", model.UpdateOperation))
+	b.Add(newStatusMessage("a2", "pod-a", model.UpdateOperation))
+	b.Add(newStatusMessage("a3", "pod-a", model.UpdateOperation))
+	expectNoMessage(t, out)
+
+	b.Flush()
+	if ids := receiveIDs(t, out, 2); fmt.Sprint(ids) != "[a3 b1]" {
+		t.Errorf("expected the latest message of each pod in the order of first arrival, got %v", ids)
+	}
+	expectNoMessage(t, out)
+	if fmt.Sprint(superseded) != "[a1 a2]" {
+		t.Errorf("expected the superseded messages responded, got %v", superseded)
+	}
+	if got := testutil.ToFloat64(coalesced) - before; got != 2 {
+		t.Errorf("expected 2 coalesced messages, got %v", got)
+	}
+}
+
+func TestStatusBatcherFlush(t *testing.T) {
+	cases := []struct {
+		name     string
+		maxBatch int
+		add      []model.Message
+		expected []string
+	}{
+		{
+			name:     "window ends",
+			maxBatch: 10,
+			add:      []model.Message{newStatusMessage("a1", "pod-a", model.UpdateOperation)},
+			expected: []string{"a1"},
+		},
+		{
+			name:     "batch full",
+			maxBatch: 2,
+			add: []model.Message{
+				newStatusMessage("a1", "pod-a", model.UpdateOperation),
+				newStatusMessage("b1", "pod-b", model.UpdateOperation),
+			},
+			expected: []string{"a1", "b1"},
+		},
+		{
+			name:     "deletion not delayed",
+			maxBatch: 10,
+			add: []model.Message{
+				newStatusMessage("a1", "pod-a", model.UpdateOperation),
+				newStatusMessage("a2", "pod-a", model.DeleteOperation),
+			},
+			expected: []string{"a1", "a2"},
+		},
+		{
+			name:     "message of several pods not coalesced",
+			maxBatch: 10,
+			add: []model.Message{
+				newStatusMessage("a1", "pod-a", model.UpdateOperation),
+				newStatusMessage("n1", "", model.UpdateOperation),
+			},
+			expected: []string{"a1", "n1"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := make(chan model.Message, 10)
+			b := newStatusBatcher(model.ResourceTypePodStatus, 100*time.Millisecond, c.maxBatch, out)
+			for _, msg := range c.add {
+				b.Add(msg)
+			}
+			if ids := receiveIDs(t, out, len(c.expected)); fmt.Sprint(ids) != fmt.Sprint(c.expected) {
+				t.Errorf("expected messages %v, got %v", c.expected, ids)
+			}
+		})
+	}
+}
+
+func TestStatusBatcherImmediate(t *testing.T) {
+	setupTest(t)
+	out := make(chan model.Message, 10)
+	b := newStatusBatcher(model.ResourceTypePodStatus, time.Hour, 10, out)
+	b.immediate = UC.isPodTerminated
+
+	b.Add(createPodStatusMessage("running", "pod-a", defaultNamespace, "uid",
+		corev1.PodStatus{Phase: corev1.PodRunning}, model.UpdateOperation))
+	expectNoMessage(t, out)
+
+	b.Add(createPodStatusMessage("succeeded", "pod-a", defaultNamespace, "uid",
+		corev1.PodStatus{Phase: corev1.PodSucceeded}, model.UpdateOperation))
+	if ids := receiveIDs(t, out, 1); ids[0] != "succeeded" {
+		t.Errorf("expected the status of the terminated pod written immediately, got %v", ids)
+	}
+	expectNoMessage(t, out)
+}
+
+func TestStatusBatcherDisabled(t *testing.T) {
+	out := make(chan model.Message, 10)
+	b := newStatusBatcher(model.ResourceTypePodStatus, 0, 10, out)
+
+	b.Add(newStatusMessage("a1", "pod-a", model.UpdateOperation))
+	b.Add(newStatusMessage("a2", "pod-a", model.UpdateOperation))
+	if ids := receiveIDs(t, out, 2); fmt.Sprint(ids) != "[a1 a2]" {
+		t.Errorf("expected the messages passed through, got %v", ids)
+	}
+}
@@ -126,6 +126,11 @@ type UpstreamController struct {
 	createPodChan                  chan model.Message
 	certificasesSigningRequestChan chan model.Message
 
+	// batchers coalesce the status updates of the same object before they are written
+	podStatusBatcher  *statusBatcher
+	nodeStatusBatcher *statusBatcher
+	leaseBatcher      *statusBatcher
+
 	// lister
 	podLister       corelisters.PodLister
 	configMapLister corelisters.ConfigMapLister
@@ -139,6 +144,7 @@ func (uc *UpstreamController) Start() error {
 	klog.Info("start upstream controller")
 
 	go uc.dispatchMessage()
+	go uc.flushBatchersOnStop()
 
 	for i := 0; i < int(uc.config.Load.UpdateNodeStatusWorkers); i++ {
 		go uc.updateNodeStatus()
@@ -230,9 +236,9 @@ func (uc *UpstreamController) dispatchMessage() {
 
 		switch resourceType {
 		case model.ResourceTypeNodeStatus:
-			uc.nodeStatusChan <- msg
+			uc.nodeStatusBatcher.Add(msg)
 		case model.ResourceTypePodStatus:
-			uc.podStatusChan <- msg
+			uc.podStatusBatcher.Add(msg)
 		case model.ResourceTypeEvent:
 			uc.eventChan <- msg
 		case model.ResourceTypeConfigmap:
@@ -265,6 +271,8 @@ func (uc *UpstreamController) dispatchMessage() {
 		case model.ResourceTypePod:
 			switch msg.GetOperation() {
 			case model.DeleteOperation:
+				// the pending status of the pods is written ahead of the deletion
+				uc.podStatusBatcher.Flush()
 				uc.podDeleteChan <- msg
 			case model.InsertOperation:
 				uc.createPodChan <- msg
@@ -276,7 +284,7 @@ func (uc *UpstreamController) dispatchMessage() {
 		case model.ResourceTypeLease:
 			switch msg.GetOperation() {
 			case model.InsertOperation, model.UpdateOperation:
-				uc.createLeaseChan <- msg
+				uc.leaseBatcher.Add(msg)
 			case model.QueryOperation:
 				uc.queryLeaseChan <- msg
 			}
@@ -288,6 +296,63 @@ func (uc *UpstreamController) dispatchMessage() {
 	}
 }
 
+// flushBatchersOnStop passes the pending status updates to the workers when cloudcore stops
+func (uc *UpstreamController) flushBatchersOnStop() {
+	<-beehiveContext.Done()
+	klog.Info("flush the pending status updates")
+	uc.podStatusBatcher.Flush()
+	uc.nodeStatusBatcher.Flush()
+	uc.leaseBatcher.Flush()
+}
+
+// newStatusBatchers creates the batchers of the pod status, node status and lease updates
+func (uc *UpstreamController) newStatusBatchers() {
+	window := time.Duration(uc.config.Buffer.StatusBatchWindow) * time.Millisecond
+	size := int(uc.config.Buffer.StatusBatchSize)
+
+	uc.podStatusBatcher = newStatusBatcher(model.ResourceTypePodStatus, window, size, uc.podStatusChan)
+	uc.podStatusBatcher.immediate = uc.isPodTerminated
+	uc.podStatusBatcher.supersede = func(msg model.Message) {
+		uc.podStatusResponse(msg, common.MessageSuccessfulContent)
+	}
+
+	uc.nodeStatusBatcher = newStatusBatcher(model.ResourceTypeNodeStatus, window, size, uc.nodeStatusChan)
+	uc.nodeStatusBatcher.supersede = func(msg model.Message) {
+		namespace, _ := messagelayer.GetNamespace(msg)
+		name, _ := messagelayer.GetResourceName(msg)
+		uc.nodeMsgResponse(name, namespace, common.MessageSuccessfulContent, msg)
+	}
+
+	uc.leaseBatcher = newStatusBatcher(model.ResourceTypeLease, window, size, uc.createLeaseChan)
+	uc.leaseBatcher.supersede = uc.leaseSupersededResponse
+}
+
+// isPodTerminated reports whether the pod status message reports a terminated pod,
+// which is deleted once its status is written
+func (uc *UpstreamController) isPodTerminated(msg model.Message) bool {
+	_, podStatuses := uc.unmarshalPodStatusMessage(msg)
+	for _, podStatus := range podStatuses {
+		if podStatus.Status.Phase == v1.PodSucceeded || podStatus.Status.Phase == v1.PodFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// leaseSupersededResponse responds the conflict to the lease update superseded by a later
+// one, as the lease is updated by the later one with a newer renew time
+func (uc *UpstreamController) leaseSupersededResponse(msg model.Message) {
+	name, _ := messagelayer.GetResourceName(msg)
+	err := errors.NewConflict(coordinationv1.Resource("leases"), name,
+		fmt.Errorf("superseded by a later update of the lease"))
+	resMsg := model.NewMessage(msg.GetID()).
+		FillBody(&edgeapi.ObjectResp{Err: err}).
+		BuildRouter(modules.EdgeControllerModuleName, constants.GroupResource, msg.GetResource(), model.ResponseOperation)
+	if err := uc.messageLayer.Response(*resMsg); err != nil {
+		klog.Warningf("Response message: %s failed, response failed with error: %v", msg.GetID(), err)
+	}
+}
+
 func (uc *UpstreamController) processEvent() {
 	for {
 		select {
@@ -1580,7 +1645,8 @@ func (uc *UpstreamController) nodeMsgResponse(nodeName, namespace, content strin
 	}
 }
 
-func UpdateAnnotation(ctx context.Context, nodeName string) error {
+// UpdateAnnotation records the cloudcore serving the node and the other annotations on the node
+func UpdateAnnotation(ctx context.Context, nodeName string, annotations map[string]string) error {
 	node, err := client.GetKubeClient().CoreV1().Nodes().Get(ctx, nodeName, metaV1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get node:%s,err:%v", nodeName, err)
@@ -1590,15 +1656,26 @@ func UpdateAnnotation(ctx context.Context, nodeName string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get cloudcore localIP with err:%v", err)
 	}
-	if value, ok := node.Annotations[comconstants.EdgeMappingCloudKey]; ok {
-		if value == localIP {
-			return nil
+	desired := map[string]string{comconstants.EdgeMappingCloudKey: localIP}
+	for key, value := range annotations {
+		desired[key] = value
+	}
+	changed := false
+	for key, value := range desired {
+		if current, ok := node.Annotations[key]; !ok || current != value {
+			changed = true
+			break
 		}
 	}
+	if !changed {
+		return nil
+	}
 	if node.Annotations == nil {
 		node.Annotations = make(map[string]string)
 	}
-	node.Annotations[comconstants.EdgeMappingCloudKey] = localIP
+	for key, value := range desired {
+		node.Annotations[key] = value
+	}
 	_, err = client.GetKubeClient().CoreV1().Nodes().Update(ctx, node, metaV1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to update node:%s with err:%v", nodeName, err)
@@ -1640,5 +1717,6 @@ func NewUpstreamController(config *v1alpha1.EdgeController, factory k8sinformer.
 	uc.queryLeaseChan = make(chan model.Message, config.Buffer.QueryLease)
 	uc.ruleStatusChan = make(chan model.Message, config.Buffer.UpdateNodeStatus)
 	uc.certificasesSigningRequestChan = make(chan model.Message, config.Buffer.CertificateSigningRequest)
+	uc.newStatusBatchers()
 	return uc, nil
 }
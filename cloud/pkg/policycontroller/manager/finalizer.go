@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	policyv1alpha1 "github.com/kubeedge/api/apis/policy/v1alpha1"
+	reliablesyncsv1alpha1 "github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
+)
+
+const (
+	// EdgeCleanupFinalizer blocks the deletion of ServiceAccountAccess until the edge nodes
+	// acknowledge the removal.
+	EdgeCleanupFinalizer = "policy.kubeedge.io/edge-cleanup"
+
+	DefaultDeletionAckTimeout = 300 * time.Second
+
+	deletionAckRequeueInterval = 5 * time.Second
+)
+
+// ensureFinalizer adds the EdgeCleanupFinalizer to the ServiceAccountAccess if the finalizer is enabled.
+func (c *Controller) ensureFinalizer(ctx context.Context, acc *policyv1alpha1.ServiceAccountAccess) error {
+	if !c.DeletionFinalizer || controllerutil.ContainsFinalizer(acc, EdgeCleanupFinalizer) {
+		return nil
+	}
+	controllerutil.AddFinalizer(acc, EdgeCleanupFinalizer)
+	return c.Client.Update(ctx, acc)
+}
+
+// finalize removes the ServiceAccountAccess from the edge nodes, and removes the EdgeCleanupFinalizer
+// once all the edge nodes acknowledge the removal or the acknowledgment times out.
+func (c *Controller) finalize(ctx context.Context, acc *policyv1alpha1.ServiceAccountAccess) (controllerruntime.Result, error) {
+	if !controllerutil.ContainsFinalizer(acc, EdgeCleanupFinalizer) {
+		return controllerruntime.Result{}, nil
+	}
+	if _, sent := c.deletionSent.LoadOrStore(acc.UID, struct{}{}); !sent {
+		klog.V(4).Infof("delete serviceaccountaccess %s/%s from edge nodes %v", acc.Namespace, acc.Name, acc.Status.NodeList)
+		c.send2Edge(acc, acc.Status.NodeList, model.DeleteOperation)
+	}
+
+	pending, err := c.pendingEdgeAcks(ctx, acc)
+	if err != nil {
+		klog.Errorf("failed to check the acknowledgment of serviceaccountaccess %s/%s, %v", acc.Namespace, acc.Name, err)
+		return controllerruntime.Result{Requeue: true}, err
+	}
+	if len(pending) != 0 {
+		timeout := c.DeletionAckTimeout
+		if timeout <= 0 {
+			timeout = DefaultDeletionAckTimeout
+		}
+		if time.Since(acc.GetDeletionTimestamp().Time) < timeout {
+			klog.V(4).Infof("waiting for edge nodes %v to acknowledge the removal of serviceaccountaccess %s/%s",
+				pending, acc.Namespace, acc.Name)
+			return controllerruntime.Result{RequeueAfter: deletionAckRequeueInterval}, nil
+		}
+		klog.Warningf("edge nodes %v did not acknowledge the removal of serviceaccountaccess %s/%s in %v",
+			pending, acc.Namespace, acc.Name, timeout)
+	}
+
+	controllerutil.RemoveFinalizer(acc, EdgeCleanupFinalizer)
+	if err := c.Client.Update(ctx, acc); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("failed to remove finalizer of serviceaccountaccess %s/%s, %v", acc.Namespace, acc.Name, err)
+		return controllerruntime.Result{Requeue: true}, err
+	}
+	c.deletionSent.Delete(acc.UID)
+	return controllerruntime.Result{}, nil
+}
+
+// pendingEdgeAcks returns the edge nodes which have not acknowledged the removal. The ObjectSync
+// of an edge node is deleted by cloudhub when the edge node acknowledges the delete message.
+func (c *Controller) pendingEdgeAcks(ctx context.Context, acc *policyv1alpha1.ServiceAccountAccess) ([]string, error) {
+	var pending []string
+	for _, node := range acc.Status.NodeList {
+		objectSync := &reliablesyncsv1alpha1.ObjectSync{}
+		key := types.NamespacedName{
+			Namespace: acc.Namespace,
+			Name:      synccontroller.BuildObjectSyncName(node, string(acc.UID)),
+		}
+		err := c.Client.Get(ctx, key, objectSync)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		pending = append(pending, node)
+	}
+	return pending, nil
+}
+
+// accessDeleted propagates the deletion of ServiceAccountAccess to the edge nodes when the finalizer
+// is disabled. The removal is idempotent on the edge nodes.
+func (c *Controller) accessDeleted(e event.DeleteEvent) bool {
+	acc, ok := e.Object.(*policyv1alpha1.ServiceAccountAccess)
+	if !ok || c.DeletionFinalizer {
+		return false
+	}
+	if len(acc.Status.NodeList) != 0 {
+		klog.V(4).Infof("serviceaccountaccess %s/%s is deleted, delete it from edge nodes %v",
+			acc.Namespace, acc.Name, acc.Status.NodeList)
+		c.send2Edge(acc, acc.Status.NodeList, model.DeleteOperation)
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	policyv1alpha1 "github.com/kubeedge/api/apis/policy/v1alpha1"
+	reliablesyncsv1alpha1 "github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
+	"github.com/kubeedge/beehive/pkg/common"
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
+	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/synccontroller"
+)
+
+func initFinalizerTest(t *testing.T, objs ...client.Object) client.WithWatch {
+	beehiveContext.InitContext([]string{common.MsgCtxTypeChannel})
+	beehiveContext.AddModule(&common.ModuleInfo{
+		ModuleName: modules.CloudHubModuleName,
+		ModuleType: common.MsgCtxTypeChannel,
+	})
+	scheme := runtime.NewScheme()
+	require.NoError(t, policyv1alpha1.AddToScheme(scheme))
+	require.NoError(t, reliablesyncsv1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func receiveOperations(t *testing.T, n int) []string {
+	var oprs []string
+	for i := 0; i < n; i++ {
+		msg, err := beehiveContext.Receive(modules.CloudHubModuleName)
+		require.NoError(t, err)
+		oprs = append(oprs, msg.GetOperation())
+	}
+	return oprs
+}
+
+func TestAccessDeleted(t *testing.T) {
+	initFinalizerTest(t)
+	acc := &policyv1alpha1.ServiceAccountAccess{
+		ObjectMeta: metav1.ObjectMeta{Name: "saa1", Namespace: "ns1"},
+		Status:     policyv1alpha1.AccessStatus{NodeList: []string{"node1", "node2"}},
+	}
+	c := &Controller{MessageLayer: messagelayer.PolicyControllerMessageLayer()}
+	require.False(t, c.accessDeleted(event.DeleteEvent{Object: acc}))
+	require.Equal(t, []string{model.DeleteOperation, model.DeleteOperation}, receiveOperations(t, 2))
+}
+
+func TestFinalizerBlocksUntilAck(t *testing.T) {
+	now := metav1.Now()
+	acc := &policyv1alpha1.ServiceAccountAccess{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "saa1",
+			Namespace:         "ns1",
+			UID:               "uid1",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{EdgeCleanupFinalizer},
+		},
+		Status: policyv1alpha1.AccessStatus{NodeList: []string{"node1"}},
+	}
+	objectSync := &reliablesyncsv1alpha1.ObjectSync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      synccontroller.BuildObjectSyncName("node1", "uid1"),
+			Namespace: "ns1",
+		},
+	}
+	cli := initFinalizerTest(t, acc, objectSync)
+	c := &Controller{
+		Client:            cli,
+		MessageLayer:      messagelayer.PolicyControllerMessageLayer(),
+		DeletionFinalizer: true,
+	}
+	request := controllerruntime.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "saa1"}}
+
+	rst, err := c.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, deletionAckRequeueInterval, rst.RequeueAfter)
+	require.Equal(t, []string{model.DeleteOperation}, receiveOperations(t, 1))
+	got := &policyv1alpha1.ServiceAccountAccess{}
+	require.NoError(t, cli.Get(context.Background(), request.NamespacedName, got))
+	require.True(t, controllerutil.ContainsFinalizer(got, EdgeCleanupFinalizer))
+
+	// still waiting, the delete message is not sent again
+	rst, err = c.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, deletionAckRequeueInterval, rst.RequeueAfter)
+
+	// the edge node acknowledges the removal
+	require.NoError(t, cli.Delete(context.Background(), objectSync))
+	rst, err = c.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, controllerruntime.Result{}, rst)
+	err = cli.Get(context.Background(), request.NamespacedName, got)
+	require.True(t, apierror.IsNotFound(err))
+}
+
+func TestFinalizerAckTimeout(t *testing.T) {
+	deletedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	acc := &policyv1alpha1.ServiceAccountAccess{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "saa1",
+			Namespace:         "ns1",
+			UID:               "uid1",
+			DeletionTimestamp: &deletedAt,
+			Finalizers:        []string{EdgeCleanupFinalizer},
+		},
+		Status: policyv1alpha1.AccessStatus{NodeList: []string{"node1"}},
+	}
+	objectSync := &reliablesyncsv1alpha1.ObjectSync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      synccontroller.BuildObjectSyncName("node1", "uid1"),
+			Namespace: "ns1",
+		},
+	}
+	cli := initFinalizerTest(t, acc, objectSync)
+	c := &Controller{
+		Client:             cli,
+		MessageLayer:       messagelayer.PolicyControllerMessageLayer(),
+		DeletionFinalizer:  true,
+		DeletionAckTimeout: time.Second,
+	}
+	request := controllerruntime.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "saa1"}}
+
+	rst, err := c.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, controllerruntime.Result{}, rst)
+	receiveOperations(t, 1)
+	err = cli.Get(context.Background(), request.NamespacedName, &policyv1alpha1.ServiceAccountAccess{})
+	require.True(t, apierror.IsNotFound(err))
+}
+
+func TestEnsureFinalizer(t *testing.T) {
+	acc := &policyv1alpha1.ServiceAccountAccess{
+		ObjectMeta: metav1.ObjectMeta{Name: "saa1", Namespace: "ns1"},
+	}
+	cli := initFinalizerTest(t, acc)
+
+	c := &Controller{Client: cli}
+	require.NoError(t, c.ensureFinalizer(context.Background(), acc))
+	require.False(t, controllerutil.ContainsFinalizer(acc, EdgeCleanupFinalizer))
+
+	c.DeletionFinalizer = true
+	require.NoError(t, c.ensureFinalizer(context.Background(), acc))
+	got := &policyv1alpha1.ServiceAccountAccess{}
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Namespace: "ns1", Name: "saa1"}, got))
+	require.True(t, controllerutil.ContainsFinalizer(got, EdgeCleanupFinalizer))
+}
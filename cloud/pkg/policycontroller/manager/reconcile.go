@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -34,6 +36,17 @@ import (
 type Controller struct {
 	client.Client
 	MessageLayer messagelayer.MessageLayer
+	// AllowedAPIGroups restricts the API groups of the rules projected to the edge,
+	// all API groups are projected if it is empty.
+	AllowedAPIGroups []string
+	// DeletionFinalizer indicates whether the deletion of ServiceAccountAccess waits for
+	// the edge nodes to acknowledge the removal.
+	DeletionFinalizer bool
+	// DeletionAckTimeout is the time to wait for the edge nodes to acknowledge the removal.
+	DeletionAckTimeout time.Duration
+
+	// deletionSent records the UIDs of the ServiceAccountAccess whose removal has been sent to edge.
+	deletionSent sync.Map
 }
 
 func (c *Controller) Reconcile(ctx context.Context, request controllerruntime.Request) (controllerruntime.Result, error) {
@@ -46,7 +59,11 @@ func (c *Controller) Reconcile(ctx context.Context, request controllerruntime.Re
 		return controllerruntime.Result{Requeue: true}, err
 	}
 	if !acc.GetDeletionTimestamp().IsZero() {
-		return controllerruntime.Result{}, nil
+		return c.finalize(ctx, acc)
+	}
+	if err := c.ensureFinalizer(ctx, acc); err != nil {
+		klog.Errorf("failed to add finalizer to serviceaccountaccess %s/%s, %v", acc.Namespace, acc.Name, err)
+		return controllerruntime.Result{Requeue: true}, err
 	}
 	return c.syncRules(ctx, acc)
 }
@@ -252,7 +269,9 @@ func (c *Controller) SetupWithManager(ctx context.Context, mgr controllerruntime
 		return fmt.Errorf("failed to set ServiceAccountName field selector for manager, %v", err)
 	}
 	return controllerruntime.NewControllerManagedBy(mgr).
-		For(&policyv1alpha1.ServiceAccountAccess{}).
+		For(&policyv1alpha1.ServiceAccountAccess{}, builder.WithPredicates(predicate.Funcs{
+			DeleteFunc: c.accessDeleted,
+		})).
 		Watches(&rbacv1.ClusterRoleBinding{}, handler.EnqueueRequestsFromMapFunc(c.mapRolesFunc), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
 			return c.filterResource(ctx, object)
 		}))).
@@ -588,7 +607,7 @@ func (c *Controller) VisitRulesFor(ctx context.Context, user user.Info, namespac
 		}
 		var accessClusterRoleBinding = policyv1alpha1.AccessClusterRoleBinding{
 			ClusterRoleBinding: crb,
-			Rules:              rules,
+			Rules:              projectRules(rules, c.AllowedAPIGroups),
 		}
 		acc.Spec.AccessClusterRoleBinding = append(acc.Spec.AccessClusterRoleBinding, accessClusterRoleBinding)
 	}
@@ -611,7 +630,7 @@ func (c *Controller) VisitRulesFor(ctx context.Context, user user.Info, namespac
 			}
 			var accessRoleBinding = policyv1alpha1.AccessRoleBinding{
 				RoleBinding: roleBinding,
-				Rules:       rules,
+				Rules:       projectRules(rules, c.AllowedAPIGroups),
 			}
 			acc.Spec.AccessRoleBinding = append(acc.Spec.AccessRoleBinding, accessRoleBinding)
 		}
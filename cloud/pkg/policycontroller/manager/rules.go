@@ -0,0 +1,42 @@
+package controller
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// projectRules returns the rules that are projected to the edge. Rules are copied verbatim
+// (including verbs, resources, resourceNames and nonResourceURLs) for any API group,
+// built-in or custom. If allowedGroups is not empty, only the API groups in it are kept,
+// and a wildcard group is narrowed down to the allowed groups.
+func projectRules(rules []rbacv1.PolicyRule, allowedGroups []string) []rbacv1.PolicyRule {
+	if len(rules) == 0 {
+		return rules
+	}
+	var projected = make([]rbacv1.PolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		rule = *rule.DeepCopy()
+		if len(allowedGroups) == 0 || len(rule.NonResourceURLs) > 0 {
+			// non-resource rules are not bound to any API group
+			projected = append(projected, rule)
+			continue
+		}
+		groups := allowedAPIGroups(rule.APIGroups, allowedGroups)
+		if len(groups) == 0 {
+			continue
+		}
+		rule.APIGroups = groups
+		projected = append(projected, rule)
+	}
+	return projected
+}
+
+// allowedAPIGroups returns the API groups of a rule which are in the allowed groups.
+func allowedAPIGroups(groups, allowedGroups []string) []string {
+	if has(allowedGroups, rbacv1.APIGroupAll) {
+		return groups
+	}
+	if has(groups, rbacv1.APIGroupAll) {
+		return append([]string{}, allowedGroups...)
+	}
+	return intersectSlice(allowedGroups, groups)
+}
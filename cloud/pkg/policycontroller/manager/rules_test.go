@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	policyv1alpha1 "github.com/kubeedge/api/apis/policy/v1alpha1"
+)
+
+var (
+	deviceRule = rbacv1.PolicyRule{
+		Verbs:     []string{"get", "list", "watch", "patch"},
+		APIGroups: []string{"devices.kubeedge.io"},
+		Resources: []string{"devices", "devices/status"},
+	}
+	wildcardRule = rbacv1.PolicyRule{
+		Verbs:     []string{"*"},
+		APIGroups: []string{"*"},
+		Resources: []string{"*"},
+	}
+	resourceNamesRule = rbacv1.PolicyRule{
+		Verbs:         []string{"get", "update"},
+		APIGroups:     []string{""},
+		Resources:     []string{"configmaps"},
+		ResourceNames: []string{"mapper-config"},
+	}
+	nonResourceRule = rbacv1.PolicyRule{
+		Verbs:           []string{"get"},
+		NonResourceURLs: []string{"/healthz", "/version"},
+	}
+)
+
+func TestProjectRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         []rbacv1.PolicyRule
+		allowedGroups []string
+		want          []rbacv1.PolicyRule
+	}{
+		{
+			name:  "no restriction keeps all rules verbatim",
+			rules: []rbacv1.PolicyRule{deviceRule, wildcardRule, resourceNamesRule, nonResourceRule},
+			want:  []rbacv1.PolicyRule{deviceRule, wildcardRule, resourceNamesRule, nonResourceRule},
+		},
+		{
+			name:          "custom resource group allowed",
+			rules:         []rbacv1.PolicyRule{deviceRule, resourceNamesRule},
+			allowedGroups: []string{"devices.kubeedge.io"},
+			want:          []rbacv1.PolicyRule{deviceRule},
+		},
+		{
+			name:          "wildcard group is narrowed to the allowed groups",
+			rules:         []rbacv1.PolicyRule{wildcardRule},
+			allowedGroups: []string{"devices.kubeedge.io", ""},
+			want: []rbacv1.PolicyRule{{
+				Verbs:     []string{"*"},
+				APIGroups: []string{"devices.kubeedge.io", ""},
+				Resources: []string{"*"},
+			}},
+		},
+		{
+			name:          "wildcard allowed groups keeps all rules",
+			rules:         []rbacv1.PolicyRule{wildcardRule, resourceNamesRule},
+			allowedGroups: []string{"*"},
+			want:          []rbacv1.PolicyRule{wildcardRule, resourceNamesRule},
+		},
+		{
+			name:          "resourceNames and non-resource URLs are preserved",
+			rules:         []rbacv1.PolicyRule{resourceNamesRule, nonResourceRule},
+			allowedGroups: []string{""},
+			want:          []rbacv1.PolicyRule{resourceNamesRule, nonResourceRule},
+		},
+		{
+			name: "rule with multiple groups keeps only allowed groups",
+			rules: []rbacv1.PolicyRule{{
+				Verbs:     []string{"get"},
+				APIGroups: []string{"apps", "devices.kubeedge.io"},
+				Resources: []string{"*"},
+			}},
+			allowedGroups: []string{"devices.kubeedge.io"},
+			want: []rbacv1.PolicyRule{{
+				Verbs:     []string{"get"},
+				APIGroups: []string{"devices.kubeedge.io"},
+				Resources: []string{"*"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := projectRules(tt.rules, tt.allowedGroups)
+			if !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("projectRules() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisitRulesForCustomResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := rbacv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add rbacv1 scheme: %v", err)
+	}
+	if err := policyv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add policyv1alpha1 scheme: %v", err)
+	}
+	subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "mapper", Namespace: "default"}}
+	cr := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "device-reader"},
+		Rules:      []rbacv1.PolicyRule{deviceRule, wildcardRule},
+	}
+	crb := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "device-reader"},
+		Subjects:   subjects,
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: cr.Name},
+	}
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "mapper-config", Namespace: "default"},
+		Rules:      []rbacv1.PolicyRule{resourceNamesRule},
+	}
+	rb := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "mapper-config", Namespace: "default"},
+		Subjects:   subjects,
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name},
+	}
+	userInfo := serviceaccount.UserInfo("default", "mapper", "")
+
+	tests := []struct {
+		name          string
+		allowedGroups []string
+		wantCRBRules  []rbacv1.PolicyRule
+		wantRBRules   []rbacv1.PolicyRule
+	}{
+		{
+			name:         "all groups projected",
+			wantCRBRules: []rbacv1.PolicyRule{deviceRule, wildcardRule},
+			wantRBRules:  []rbacv1.PolicyRule{resourceNamesRule},
+		},
+		{
+			name:          "restricted to the device group",
+			allowedGroups: []string{"devices.kubeedge.io"},
+			wantCRBRules: []rbacv1.PolicyRule{deviceRule, {
+				Verbs:     []string{"*"},
+				APIGroups: []string{"devices.kubeedge.io"},
+				Resources: []string{"*"},
+			}},
+			wantRBRules: []rbacv1.PolicyRule{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr, crb, role, rb).Build()
+			ctr := &Controller{
+				Client:           fakeClient,
+				AllowedAPIGroups: tt.allowedGroups,
+			}
+			acc := &policyv1alpha1.ServiceAccountAccess{}
+			ctr.VisitRulesFor(context.Background(), userInfo, "default", acc)
+			if len(acc.Spec.AccessClusterRoleBinding) != 1 {
+				t.Fatalf("expected 1 cluster role binding, got %d", len(acc.Spec.AccessClusterRoleBinding))
+			}
+			if got := acc.Spec.AccessClusterRoleBinding[0].Rules; !equality.Semantic.DeepEqual(got, tt.wantCRBRules) {
+				t.Errorf("cluster role binding rules = %+v, want %+v", got, tt.wantCRBRules)
+			}
+			if len(acc.Spec.AccessRoleBinding) != 1 {
+				t.Fatalf("expected 1 role binding, got %d", len(acc.Spec.AccessRoleBinding))
+			}
+			if got := acc.Spec.AccessRoleBinding[0].Rules; !equality.Semantic.DeepEqual(got, tt.wantRBRules) {
+				t.Errorf("role binding rules = %+v, want %+v", got, tt.wantRBRules)
+			}
+		})
+	}
+}
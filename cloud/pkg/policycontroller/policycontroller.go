@@ -3,6 +3,7 @@ package policycontroller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -14,10 +15,12 @@ import (
 	controllerruntimemetrics "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	policyv1alpha1 "github.com/kubeedge/api/apis/policy/v1alpha1"
+	reliablesyncsv1alpha1 "github.com/kubeedge/api/apis/reliablesyncs/v1alpha1"
 	"github.com/kubeedge/beehive/pkg/core"
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/messagelayer"
 	"github.com/kubeedge/kubeedge/cloud/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/cloud/pkg/policycontroller/config"
 	pm "github.com/kubeedge/kubeedge/cloud/pkg/policycontroller/manager"
 	kefeatures "github.com/kubeedge/kubeedge/pkg/features"
 )
@@ -35,6 +38,7 @@ var accessScheme = runtime.NewScheme()
 func init() {
 	utilruntime.Must(scheme.AddToScheme(accessScheme))
 	utilruntime.Must(policyv1alpha1.AddToScheme(accessScheme))
+	utilruntime.Must(reliablesyncsv1alpha1.AddToScheme(accessScheme))
 }
 
 func NewAccessRoleControllerManager(ctx context.Context, kubeCfg *rest.Config) (manager.Manager, error) {
@@ -62,8 +66,11 @@ func setupControllers(ctx context.Context, mgr manager.Manager) error {
 	// have not be registered in the accessScheme.
 	cli := mgr.GetClient()
 	pc := &pm.Controller{
-		Client:       cli,
-		MessageLayer: messagelayer.PolicyControllerMessageLayer(),
+		Client:             cli,
+		MessageLayer:       messagelayer.PolicyControllerMessageLayer(),
+		AllowedAPIGroups:   config.Config.AllowedAPIGroups,
+		DeletionFinalizer:  config.Config.DeletionFinalizer,
+		DeletionAckTimeout: time.Duration(config.Config.DeletionAckTimeout) * time.Second,
 	}
 
 	klog.Info("setup policy controller")
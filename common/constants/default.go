@@ -25,9 +25,25 @@ const (
 const (
 	DefaultCAURL              = "/ca.crt"
 	DefaultCertURL            = "/edge.crt"
+	DefaultCertResultURL      = "/certificate/result/{id}"
 	DefaultCheckNodeURL       = "/node/{nodename}"
+	DefaultNodeCertInfoURL    = "/nodes/{nodename}/certinfo"
 	DefaultNodeUpgradeURL     = "/nodeupgrade"
 	DefaultTaskStateReportURL = "/task/{taskType}/name/{taskID}/node/{nodeID}/status"
+	DefaultIssuanceLogURL     = "/admin/issuance-log"
+	DefaultIssuanceLogNodeURL = "/admin/issuance-log/nodes/{nodename}"
+	DefaultNodeTokenURL       = "/admin/nodes/{nodename}/token"
+	DefaultPreRegistrationURL = "/admin/preregistrations"
+	DefaultCertImportURL      = "/admin/certs/import"
+	DefaultCertPruneURL       = "/certificate/prune"
+	DefaultCertFreezeURL      = "/certificate/freeze"
+	DefaultCertUnfreezeURL    = "/certificate/unfreeze"
+	DefaultRevocationURL      = "/admin/revocations"
+	DefaultRevocationItemURL  = "/admin/revocations/{serial}"
+	DefaultDebugCertInfoURL   = "/debug/certinfo"
+	DefaultOCSPURL            = "/ocsp"
+	DefaultOCSPRequestURL     = "/ocsp/{request:*}"
+	DefaultSessionForwardURL  = "/session/forward/{nodename}"
 
 	// update PodSandboxImage version when bumping k8s vendor version, consistent with vendor/k8s.io/kubernetes/cmd/kubelet/app/options/container_runtime.go defaultPodSandboxImageVersion
 	// When this value are updated, also update comments in pkg/apis/componentconfig/edgecore/v1alpha1/types.go
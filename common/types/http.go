@@ -1,6 +1,12 @@
 package types
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // HTTPRequest is used structure used to unmarshal message content from cloud
 type HTTPRequest struct {
@@ -18,7 +24,192 @@ type HTTPResponse struct {
 }
 
 const (
-	HeaderAuthorization = "Authorization"
-	HeaderNodeName      = "NodeName"
-	HeaderExtKeyUsages  = "ExtKeyUsages"
+	HeaderAuthorization          = "Authorization"
+	HeaderNodeName               = "NodeName"
+	HeaderExtKeyUsages           = "ExtKeyUsages"
+	HeaderCertSerial             = "X-Cert-Serial"
+	HeaderCertNotAfter           = "X-Cert-Not-After"
+	HeaderCertSignatureAlgorithm = "X-Cert-Signature-Algorithm"
+	HeaderCertIssuer             = "X-Cert-Issuer"
+	// HeaderCertDuration carries the desired validity period of the edge certificate in hours,
+	// which CloudHub clamps to its MaxEdgeCertSigningDuration.
+	HeaderCertDuration = "X-KubeEdge-Cert-Duration"
+	HeaderPrefer       = "Prefer"
+	HeaderWarning      = "Warning"
+	// HeaderAPIVersion carries the version of the response body requested by the client,
+	// which CloudHub echoes in the response if the version is supported.
+	HeaderAPIVersion = "X-KubeEdge-API-Version"
+)
+
+// The reason codes prefixing the error messages of the edge certificate requests,
+// so that the clients can tell the errors apart.
+const (
+	ReasonQuotaExceeded       = "QuotaExceeded"
+	ReasonDuplicateEnrollment = "DuplicateEnrollment"
+	ReasonSigningTimeout      = "SigningTimeout"
+	ReasonSigningFrozen       = "SigningFrozen"
+	ReasonRateLimited         = "RateLimited"
+	ReasonNodeNotApproved     = "NodeNotApproved"
+	ReasonCSRPending          = "CSRPending"
+	ReasonCSRDenied           = "CSRDenied"
+	ReasonSigningDenied       = "SigningDenied"
+	// The token of the request has expired, is malformed or has an invalid signature.
+	ReasonTokenExpired          = "TokenExpired"
+	ReasonTokenMalformed        = "TokenMalformed"
+	ReasonTokenInvalidSignature = "TokenInvalidSignature"
+	// The single-use token of the request has enrolled the node already.
+	ReasonTokenConsumed = "TokenConsumed"
+	// The CSR of the request cannot be parsed or breaks the signing policies.
+	ReasonCSRInvalid = "CSRInvalid"
+	// The subject of the CSR or the certificate of the request does not belong to the node.
+	ReasonSubjectMismatch = "SubjectMismatch"
+	// The certificate authenticating the request fails the verification, e.g. it is revoked.
+	ReasonCertInvalid = "CertInvalid"
+	// The CA of CloudHub cannot sign the certificate.
+	ReasonSignerUnavailable = "SignerUnavailable"
 )
+
+// MIMECertManifest is the media type of CertResponse, an edge certificate request accepting it
+// is responded with the certificate and its provisioning manifest instead of the certificate only.
+const MIMECertManifest = "application/vnd.kubeedge.cert-manifest+json"
+
+// MIMEPEMCertChain is the media type of the PEM certificate chain, an edge certificate request
+// accepting it is responded with the certificate followed by the intermediate CA certificates
+// instead of the certificate only in DER.
+const MIMEPEMCertChain = "application/pem-certificate-chain"
+
+// MIMEPEMFile is the media type of the PEM file, a CA request accepting it is responded with
+// the CA followed by the additional trusted CAs in PEM instead of the CA only in DER, and an edge
+// certificate request accepting it is responded with the certificate in PEM.
+const MIMEPEMFile = "application/x-pem-file"
+
+// MIMEPKIXCert is the media type of a single certificate in DER, a CA or an edge certificate
+// request accepting it is responded with the certificate in DER with the media type set.
+const MIMEPKIXCert = "application/pkix-cert"
+
+// CertResponse is the response of an edge certificate request in the manifest mode.
+type CertResponse struct {
+	// Certificate is the issued certificate in DER.
+	Certificate []byte `json:"certificate"`
+	// Chain is the intermediate CA certificates in DER which chain the certificate to the root CA,
+	// starting with the CA that issued it. It is empty if the CA is trusted as a root.
+	Chain [][]byte `json:"chain,omitempty"`
+	// Manifest is the provisioning manifest of the edge node.
+	Manifest CertManifest `json:"manifest"`
+}
+
+// CertManifest is the provisioning manifest returned along with an edge certificate,
+// so that an edge node can be configured without out-of-band information.
+type CertManifest struct {
+	// WebSocketServer is the host:port of the CloudHub WebSocket server, it is empty if disabled.
+	WebSocketServer string `json:"webSocketServer,omitempty"`
+	// QuicServer is the host:port of the CloudHub QUIC server, it is empty if disabled.
+	QuicServer string `json:"quicServer,omitempty"`
+	// HTTPServer is the URL of the CloudHub HTTPS server that serves the CA and certificates.
+	HTTPServer string `json:"httpServer,omitempty"`
+	// CAFingerprint is the hex encoded SHA-256 digest of the CA that issued the certificate in DER.
+	CAFingerprint string `json:"caFingerprint"`
+	// RotationInterval is the recommended interval after which the certificate is renewed.
+	RotationInterval metaV1.Duration `json:"rotationInterval"`
+}
+
+// PreferRespondAsync is the Prefer header value of an edge certificate request which asks CloudHub
+// to sign the certificate asynchronously, see RFC 7240.
+const PreferRespondAsync = "respond-async"
+
+// SigningJob is the response of an asynchronous edge certificate request, the result of the job
+// is polled from the Location of the response until the certificate is issued or the job fails.
+type SigningJob struct {
+	// ID is the ID of the signing job.
+	ID string `json:"id"`
+	// ExpiresAt is the time after which the result of the job can no longer be polled.
+	ExpiresAt metaV1.Time `json:"expiresAt"`
+}
+
+// SigningReview is POSTed by CloudHub to the signing webhook with the Request of an authenticated
+// edge certificate request, and the webhook responds the SigningReview with the Response.
+type SigningReview struct {
+	Request  *SigningReviewRequest  `json:"request,omitempty"`
+	Response *SigningReviewResponse `json:"response,omitempty"`
+}
+
+// SigningReviewRequest is the edge certificate request reviewed by the signing webhook.
+type SigningReviewRequest struct {
+	// NodeName is the name of the node requesting the certificate.
+	NodeName string `json:"nodeName"`
+	// CSR is the PEM encoded CSR of the request.
+	CSR string `json:"csr"`
+	// AuthMethod is how the request is authenticated, one of token, certificate and preregistration.
+	AuthMethod string `json:"authMethod"`
+	// SourceIP is the IP of the client of the request.
+	SourceIP string `json:"sourceIP"`
+}
+
+// SigningReviewResponse is the decision of the signing webhook.
+type SigningReviewResponse struct {
+	// Allowed indicates whether the certificate is signed.
+	Allowed bool `json:"allowed"`
+	// Message is the reason of the decision, which is responded to the node if it is denied.
+	Message string `json:"message,omitempty"`
+}
+
+// WarningCodeMiscPersistent is the warn-code of the warnings of the edge certificate requests,
+// which is the same as the Kubernetes API warnings, see RFC 7234.
+const WarningCodeMiscPersistent = 299
+
+// FormatWarning formats the warning message as the value of a Warning header, which is
+// `299 - "message"` with the quotes and backslashes in the message escaped.
+func FormatWarning(message string) string {
+	return fmt.Sprintf(`%d - "%s"`, WarningCodeMiscPersistent, warningEscaper.Replace(message))
+}
+
+// ParseWarning parses the message of a Warning header formatted by FormatWarning,
+// it returns false if the header is not a warning of the warn-code 299.
+func ParseWarning(header string) (string, bool) {
+	quoted, ok := strings.CutPrefix(header, fmt.Sprintf("%d - ", WarningCodeMiscPersistent))
+	if !ok || len(quoted) < 2 || quoted[0] != '"' {
+		return "", false
+	}
+	var b strings.Builder
+	for i := 1; i < len(quoted); i++ {
+		switch c := quoted[i]; c {
+		case '\\':
+			if i+1 == len(quoted) {
+				return "", false
+			}
+			i++
+			b.WriteByte(quoted[i])
+		case '"':
+			// the warn-date may follow the message
+			return b.String(), true
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false
+}
+
+var warningEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// CAResponse is the response of a CA request of the API version v2.
+type CAResponse struct {
+	// Certificate is the CA of CloudHub in DER, which the hash of the bootstrap tokens is of.
+	Certificate []byte `json:"certificate"`
+	// Bundle is the CA followed by the additional trusted CAs in DER.
+	Bundle [][]byte `json:"bundle"`
+}
+
+// EdgeCertResponse is the response of an edge certificate request of the API version v2, which
+// carries the metadata of the certificate returned in the headers of the raw response.
+type EdgeCertResponse struct {
+	// Certificate is the issued certificate in DER.
+	Certificate []byte `json:"certificate"`
+	// SerialNumber is the decimal serial number of the certificate.
+	SerialNumber string `json:"serialNumber,omitempty"`
+	// NotAfter is the time after which the certificate expires.
+	NotAfter *metaV1.Time `json:"notAfter,omitempty"`
+	// SignatureAlgorithm is the algorithm the certificate is signed with.
+	SignatureAlgorithm string `json:"signatureAlgorithm,omitempty"`
+	// Warnings are the warnings of the request, such as the clamped certificate duration.
+	Warnings []string `json:"warnings,omitempty"`
+}
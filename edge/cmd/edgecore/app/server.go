@@ -34,6 +34,7 @@ import (
 	"github.com/kubeedge/kubeedge/pkg/features"
 	"github.com/kubeedge/kubeedge/pkg/util"
 	"github.com/kubeedge/kubeedge/pkg/util/flag"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
 	utilvalidation "github.com/kubeedge/kubeedge/pkg/util/validation"
 	"github.com/kubeedge/kubeedge/pkg/version"
 )
@@ -103,6 +104,10 @@ offering HTTP client capabilities to components of cloud to reach HTTP servers r
 			// To help debugging, immediately log version
 			klog.Infof("Version: %+v", version.Get())
 
+			if trace := config.MessageTrace; trace != nil && trace.Enable {
+				msgtrace.SetSampleRate(trace.SampleRate)
+			}
+
 			if config.EdgeCoreVersion != version.Get().String() {
 				config.EdgeCoreVersion = version.Get().String()
 				if err := config.WriteTo(opts.ConfigFile); err != nil {
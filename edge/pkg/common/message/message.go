@@ -21,10 +21,25 @@ const (
 	OperationKeepalive         = "keepalive"
 	OperationStart             = "start"
 	OperationStop              = "stop"
+	OperationReliableAck       = "reliable_ack"
+	// OperationReconnect asks the edge hub to reconnect after the delay in the content
+	OperationReconnect = "reconnect"
+
+	// HeaderReliableAck is the connection header which tells the cloud that the
+	// reliable messages are acknowledged
+	HeaderReliableAck = "reliable_ack"
+	// HeaderSchemaVersion is the connection header which tells the cloud the
+	// message schema version supported by the edge hub
+	HeaderSchemaVersion = "schema_version"
+	// SchemaVersion is the message schema version supported by the edge hub,
+	// version 2 handles the reconnect messages
+	SchemaVersion = "2"
 
 	ResourceGroupName = "resource"
 	FuncGroupName     = "func"
 	UserGroupName     = "user"
+	// HubGroupName is the group of the control messages between CloudHub and EdgeHub
+	HubGroupName = "hub"
 )
 
 // BuildMsg returns message object with router and content details
@@ -62,6 +62,7 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager"
 	metaclient "github.com/kubeedge/kubeedge/edge/pkg/metamanager/client"
 	kefeatures "github.com/kubeedge/kubeedge/pkg/features"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
 	"github.com/kubeedge/kubeedge/pkg/version"
 )
 
@@ -313,6 +314,7 @@ func (e *edged) syncPod(podCfg *config.PodConfig) {
 			continue
 		}
 
+		msgtrace.Infof(4, &result, "edged receive message, operation: %s, resource: %s", result.GetOperation(), result.GetResource())
 		_, resType, resID, err := commonmsg.ParseResourceEdge(result.GetResource(), result.GetOperation())
 		if err != nil {
 			klog.Errorf("failed to parse the Resource: %v", err)
@@ -2,26 +2,27 @@ package certificate
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
-	"io"
-	nethttp "net/http"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/cert"
 	certutil "k8s.io/client-go/util/cert"
-	"k8s.io/client-go/util/keyutil"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/api/apis/componentconfig/edgecore/v1alpha2"
-	"github.com/kubeedge/kubeedge/common/constants"
-	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/certificate/http"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certclient"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
 	"github.com/kubeedge/kubeedge/pkg/security/token"
 )
@@ -38,8 +39,22 @@ var jitteryDuration = func(totalDuration float64) time.Duration {
 	return wait.Jitter(time.Duration(totalDuration), 0.2) - time.Duration(totalDuration*0.3)
 }
 
+// certBackoff is the backoff of retrying the certificate requests which fail transiently.
+//
+// This is represented as a variable to allow replacement during testing.
+var certBackoff = wait.Backoff{
+	Duration: 2 * time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
 var CleanupTokenChan = make(chan struct{}, 1)
 
+// ErrTokenExpired is returned when the token to join the cluster has expired, and no fresh
+// token is available in the secondary token file.
+var ErrTokenExpired = errors.New("the token to join the cluster has expired")
+
 type CertManager struct {
 	RotateCertificates bool
 	NodeName           string
@@ -49,12 +64,20 @@ type CertManager struct {
 	keyFile  string
 
 	token string
+	// secondaryTokenFile contains the token to retry with if the token has expired
+	secondaryTokenFile string
+	// duration is the desired validity period of the certificate, cloudcore decides it if it is 0
+	duration time.Duration
+	// keyAlgorithm is the type of the private key generated for the certificate, see certs.GenPrivateKey
+	keyAlgorithm string
+	// nodeNameHeader is the header carrying the node name of the certificate requests
+	nodeNameHeader string
 	// Set to time.Now but can be stubbed out for testing
 	now func() time.Time
 
-	caURL   string
-	certURL string
-	Done    chan struct{}
+	// server is the address of the CloudHub HTTPS server
+	server string
+	Done   chan struct{}
 }
 
 // NewCertManager creates a CertManager for edge certificate management according to EdgeHub config
@@ -63,12 +86,15 @@ func NewCertManager(edgehub v1alpha2.EdgeHub, nodename string) CertManager {
 		RotateCertificates: edgehub.RotateCertificates,
 		NodeName:           nodename,
 		token:              edgehub.Token,
+		secondaryTokenFile: edgehub.SecondaryTokenFile,
+		duration:           time.Duration(edgehub.CertDuration) * time.Hour,
+		keyAlgorithm:       edgehub.KeyAlgorithm,
+		nodeNameHeader:     edgehub.NodeNameHeader,
 		caFile:             edgehub.TLSCAFile,
 		certFile:           edgehub.TLSCertFile,
 		keyFile:            edgehub.TLSPrivateKeyFile,
 		now:                time.Now,
-		caURL:              edgehub.HTTPServer + constants.DefaultCAURL,
-		certURL:            edgehub.HTTPServer + constants.DefaultCertURL,
+		server:             edgehub.HTTPServer,
 		Done:               make(chan struct{}),
 	}
 }
@@ -78,7 +104,7 @@ func (cm *CertManager) Start() {
 	if _, err := cm.getCurrent(); err != nil {
 		klog.Warningf("unable to get the current edge certs, reason: %v", err)
 		klog.Info("Reuse the token to obtain the certificate")
-		if err = cm.applyCerts(); err != nil {
+		if err = cm.applyCertsWithRetry(); err != nil {
 			panic(fmt.Errorf("failed to apply the edge certs, err: %v", err))
 		}
 		// inform to cleanup token in configuration edgecore.yaml
@@ -108,7 +134,7 @@ func (cm *CertManager) getCurrent() (*tls.Certificate, error) {
 
 // applyCerts realizes the certificate application by token
 func (cm *CertManager) applyCerts() error {
-	cacert, err := GetCACert(cm.caURL)
+	cacert, err := GetCACert(cm.server)
 	if err != nil {
 		return fmt.Errorf("failed to get CA certificate, err: %v", err)
 	}
@@ -119,31 +145,109 @@ func (cm *CertManager) applyCerts() error {
 		return err
 	}
 
-	// save the ca.crt to file
-	caPem, err := certs.WriteDERToPEMFile(cm.caFile, cert.CertificateBlockType, cacert)
+	// save the CA bundle to the ca.crt, it is the CA only if CloudHub doesn't serve the bundle
+	bundle, err := getCABundle(cm.server, cacert)
 	if err != nil {
+		klog.Warningf("failed to get the CA bundle, only the CA pinned by the token is trusted, err: %v", err)
+		bundle = [][]byte{cacert}
+	}
+	if err := certs.WriteDERsToPEMFile(cm.caFile, cert.CertificateBlockType, bundle); err != nil {
 		return fmt.Errorf("failed to save the CA certificate to file: %s, error: %v", cm.caFile, err)
 	}
-	certDER, keyDER, err := cm.GetEdgeCert(cm.certURL, pem.EncodeToMemory(caPem), tls.Certificate{}, realToken)
+	var caPem []byte
+	for _, der := range bundle {
+		caPem = append(caPem, pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: der})...)
+	}
+	issued, keyDER, err := cm.GetEdgeCert(caPem, tls.Certificate{}, realToken)
+	if certclient.IsTokenExpired(err) {
+		issued, keyDER, err = cm.retryWithSecondaryToken(caPem, cacert, err)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get edge certificate from the cloudcore, error: %v", err)
+		return fmt.Errorf("failed to get edge certificate from the cloudcore, error: %w", err)
 	}
-	// save the edge.crt to the file
-	if _, err := certs.WriteDERToPEMFile(cm.certFile,
-		certutil.CertificateBlockType, certDER); err != nil {
+	// save the edge.crt along with the intermediate CAs to the file
+	if err := certs.WriteDERsToPEMFile(cm.certFile,
+		certutil.CertificateBlockType, certChain(issued)); err != nil {
 		return fmt.Errorf("failed to save the certificate file %s, err: %v", cm.certFile, err)
 	}
 	if _, err := certs.WriteDERToPEMFile(cm.keyFile,
-		keyutil.ECPrivateKeyBlockType, keyDER); err != nil {
+		certs.PrivateKeyBlockType(keyDER), keyDER); err != nil {
 		return fmt.Errorf("failed to save the certificate key file %s, err: %v", cm.keyFile, err)
 	}
 	return nil
 }
 
+// applyCertsWithRetry applies for the certificate by token, the transient errors are retried
+// with backoff, while the errors which need the intervention of the operator, e.g. an expired
+// token, are returned at once.
+func (cm *CertManager) applyCertsWithRetry() error {
+	var lastErr error
+	err := wait.ExponentialBackoff(certBackoff, func() (bool, error) {
+		if lastErr = cm.applyCerts(); lastErr == nil {
+			return true, nil
+		}
+		if !retryable(lastErr) {
+			return false, lastErr
+		}
+		klog.Warningf("failed to apply the edge certs, retry with backoff, err: %v", lastErr)
+		return false, nil
+	})
+	if wait.Interrupted(err) {
+		return lastErr
+	}
+	return err
+}
+
+// retryable reports whether the certificate request which failed with the err may succeed if it
+// is retried, e.g. the network is down or CloudHub is busy. The token, the CSR or the certificate
+// rejected by CloudHub is not retried, which must be surfaced to the operator.
+func retryable(err error) bool {
+	if errors.Is(err, ErrTokenExpired) {
+		return false
+	}
+	// the CSR is too large for CloudHub, sending it again does not help
+	var e *certclient.Error
+	if errors.As(err, &e) && e.StatusCode == http.StatusRequestEntityTooLarge {
+		return false
+	}
+	switch certclient.ReasonOf(err) {
+	case types.ReasonTokenExpired, types.ReasonTokenMalformed, types.ReasonTokenInvalidSignature,
+		types.ReasonTokenConsumed, types.ReasonCSRInvalid, types.ReasonSubjectMismatch, types.ReasonCertInvalid:
+		return false
+	}
+	return true
+}
+
+// retryWithSecondaryToken applies for the certificate with the token in the secondary token file
+// after the token has expired with the error cause. It returns ErrTokenExpired if there is no
+// secondary token file, or the token in it has expired too.
+func (cm *CertManager) retryWithSecondaryToken(capem, cacert []byte, cause error,
+) (*certclient.IssuedCert, []byte, error) {
+	if cm.secondaryTokenFile == "" {
+		return nil, nil, fmt.Errorf("%w, mint a fresh token of edgenode %s and set it to secondaryTokenFile, err: %v",
+			ErrTokenExpired, cm.NodeName, cause)
+	}
+	klog.Warningf("the token of edgenode %s has expired, retry with the token in %s", cm.NodeName, cm.secondaryTokenFile)
+	data, err := os.ReadFile(cm.secondaryTokenFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the secondary token file %s, err: %v", cm.secondaryTokenFile, err)
+	}
+	realToken, err := token.VerifyCAAndGetRealToken(strings.TrimSpace(string(data)), cacert)
+	if err != nil {
+		return nil, nil, err
+	}
+	issued, keyDER, err := cm.GetEdgeCert(capem, tls.Certificate{}, realToken)
+	if certclient.IsTokenExpired(err) {
+		return nil, nil, fmt.Errorf("%w, the token in %s has expired too, err: %v", ErrTokenExpired, cm.secondaryTokenFile, err)
+	}
+	return issued, keyDER, err
+}
+
 // rotate starts edge certificate rotation process
 func (cm *CertManager) rotate() {
 	klog.Infof("Certificate rotation is enabled.")
-	go wait.Forever(func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go wait.UntilWithContext(ctx, func(context.Context) {
 		deadline, err := cm.nextRotationDeadline()
 		if err != nil {
 			klog.Errorf("failed to get next rotation deadline:%v", err)
@@ -157,18 +261,15 @@ func (cm *CertManager) rotate() {
 			<-timer.C // unblock when deadline expires
 		}
 
-		backoff := wait.Backoff{
-			Duration: 2 * time.Second,
-			Factor:   2,
-			Jitter:   0.1,
-			Steps:    5,
-		}
-		if err := wait.ExponentialBackoff(backoff, cm.rotateCert); err != nil {
+		err = wait.ExponentialBackoff(certBackoff, cm.rotateCert)
+		if wait.Interrupted(err) {
 			utilruntime.HandleError(fmt.Errorf("reached backoff limit, still unable to rotate certs: %v", err))
-			if err := wait.PollInfinite(32*time.Second, cm.rotateCert); err != nil {
-				// TODO: handle error
-				klog.Error(err)
-			}
+			err = wait.PollInfinite(32*time.Second, cm.rotateCert)
+		}
+		if err != nil {
+			klog.Errorf("stop rotating the certificate of edgenode %s, which needs the intervention of the operator, err: %v",
+				cm.NodeName, err)
+			cancel()
 		}
 	}, time.Second)
 }
@@ -201,18 +302,21 @@ func (cm *CertManager) rotateCert() (bool, error) {
 		klog.Errorf("failed to get CA certificate locally:%v", err)
 		return false, nil
 	}
-	certDER, keyDER, err := cm.GetEdgeCert(cm.certURL, caPem, *tlsCert, "")
+	issued, keyDER, err := cm.GetEdgeCert(caPem, *tlsCert, "")
 	if err != nil {
+		if !retryable(err) {
+			return false, fmt.Errorf("failed to get edge certificate from CloudCore: %w", err)
+		}
 		klog.Errorf("failed to get edge certificate from CloudCore:%v", err)
 		return false, nil
 	}
-	if _, err := certs.WriteDERToPEMFile(cm.certFile,
-		certutil.CertificateBlockType, certDER); err != nil {
+	if err := certs.WriteDERsToPEMFile(cm.certFile,
+		certutil.CertificateBlockType, certChain(issued)); err != nil {
 		klog.Errorf("failed to save the certificate file %s, err: %v", cm.certFile, err)
 		return false, nil
 	}
 	if _, err := certs.WriteDERToPEMFile(cm.keyFile,
-		keyutil.ECPrivateKeyBlockType, keyDER); err != nil {
+		certs.PrivateKeyBlockType(keyDER), keyDER); err != nil {
 		klog.Errorf("failed to save the certificate key file %s, err: %v", cm.keyFile, err)
 		return false, nil
 	}
@@ -229,30 +333,40 @@ func (cm *CertManager) getCA() ([]byte, error) {
 	return os.ReadFile(cm.caFile)
 }
 
-// GetCACert gets the cloudcore CA certificate
-func GetCACert(url string) ([]byte, error) {
-	client := http.NewHTTPClient()
-	req, err := http.BuildRequest(nethttp.MethodGet, url, nil, "", "")
+// GetCACert gets the cloudcore CA certificate from the CloudHub HTTPS server
+func GetCACert(server string) ([]byte, error) {
+	cli, err := certclient.New(server, "")
 	if err != nil {
 		return nil, err
 	}
-	res, err := http.SendRequest(req, client)
+	return cli.GetCA(context.Background())
+}
+
+// getCABundle gets the CA bundle from the CloudHub server over the TLS verified by the CA cacert,
+// which is pinned by the hash of the token. The other CAs of the bundle, e.g. the offline root CA
+// and the previous CA of a rotation, are trusted because they are served by the pinned CloudHub.
+func getCABundle(server string, cacert []byte) ([][]byte, error) {
+	cli, err := certclient.New(server, "", certclient.WithCA(
+		pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: cacert})))
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
-	caCert, err := io.ReadAll(io.LimitReader(res.Body, constants.MaxRespBodyLength))
+	bundle, err := cli.GetCABundle(context.Background())
 	if err != nil {
 		return nil, err
 	}
-
-	return caCert, nil
+	if !bytes.Equal(bundle[0], cacert) {
+		return nil, errors.New("the CA bundle doesn't start with the CA pinned by the token")
+	}
+	return bundle, nil
 }
 
-// GetEdgeCert applies for the certificate from cloudcore
-func (cm *CertManager) GetEdgeCert(url string, capem []byte, tlscert tls.Certificate, token string,
-) ([]byte, []byte, error) {
-	h := certs.GetHandler(certs.HandlerTypeX509)
+// GetEdgeCert applies for the certificate from cloudcore, the certificate is renewed with the
+// current certificate tlscert if the token is empty. It returns the issued certificate along
+// with its intermediate CAs, and the private key in DER.
+func (cm *CertManager) GetEdgeCert(capem []byte, tlscert tls.Certificate, token string,
+) (*certclient.IssuedCert, []byte, error) {
+	h := certs.GetHandlerWithKeyType(certs.HandlerTypeX509, cm.keyAlgorithm)
 	pkw, err := h.GenPrivateKey()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate a private key of edge cert, err: %v", err)
@@ -268,28 +382,36 @@ func (cm *CertManager) GetEdgeCert(url string, capem []byte, tlscert tls.Certifi
 		return nil, nil, fmt.Errorf("failed to create a csr of edge cert, err %v", err)
 	}
 
-	client, err := http.NewHTTPClientWithCA(capem, tlscert)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create a http client, err: %v", err)
+	opts := []certclient.Option{certclient.WithCA(capem), certclient.WithChain(),
+		certclient.WithNodeNameHeader(cm.nodeNameHeader)}
+	if token != "" {
+		opts = append(opts, certclient.WithToken(token))
+	} else {
+		opts = append(opts, certclient.WithClientCert(tlscert))
 	}
-
-	req, err := http.BuildRequest(nethttp.MethodGet, url, bytes.NewReader(csrPem.Bytes), token, cm.NodeName)
+	cli, err := certclient.New(cm.server, cm.NodeName, opts...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate a http request, err: %v", err)
+		return nil, nil, fmt.Errorf("failed to create a http client, err: %v", err)
 	}
 
-	res, err := http.SendRequest(req, client)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to request the cloudcore server, err: %v", err)
+	csr := certclient.CSRRequest{CSR: csrPem.Bytes, Duration: cm.duration}
+	var issued *certclient.IssuedCert
+	if token != "" {
+		issued, err = cli.SignCert(context.Background(), csr)
+	} else {
+		issued, err = cli.Renew(context.Background(), csr)
 	}
-	defer res.Body.Close()
-
-	content, err := io.ReadAll(io.LimitReader(res.Body, constants.MaxRespBodyLength))
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read response body, err: %v", err)
+		return nil, nil, err
 	}
-	if res.StatusCode != nethttp.StatusOK {
-		return nil, nil, fmt.Errorf("failed to call http, code: %d, message: %s", res.StatusCode, string(content))
+	for _, warning := range issued.Warnings {
+		klog.Warningf("cloudcore warns about the certificate of edgenode %s: %s", cm.NodeName, warning)
 	}
-	return content, pkw.DER(), nil
+	return issued, pkw.DER(), nil
+}
+
+// certChain returns the issued certificate followed by its intermediate CAs, which is saved
+// to the certificate file so that the peers trusting the root CA can verify it.
+func certChain(issued *certclient.IssuedCert) [][]byte {
+	return append([][]byte{issued.Certificate}, issued.Chain...)
 }
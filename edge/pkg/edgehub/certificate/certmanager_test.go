@@ -17,23 +17,30 @@ limitations under the License.
 package certificate
 
 import (
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/agiledragon/gomonkey/v2"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/kubeedge/kubeedge/common/constants"
-	commhttp "github.com/kubeedge/kubeedge/edge/pkg/edgehub/certificate/http"
-	httpfake "github.com/kubeedge/kubeedge/edge/pkg/edgehub/certificate/http/fake"
+	"github.com/kubeedge/kubeedge/common/types"
+	"github.com/kubeedge/kubeedge/pkg/security/certclient"
 	"github.com/kubeedge/kubeedge/pkg/security/certs"
 )
 
@@ -72,67 +79,255 @@ func TestGetCurrent(t *testing.T) {
 }
 
 func TestGetCACert(t *testing.T) {
-	const fakehost = "http://localhost"
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, constants.DefaultCAURL, r.URL.Path)
+		_, _ = w.Write([]byte("test ca"))
+	}))
+	defer srv.Close()
 
-	patches := gomonkey.NewPatches()
-	defer patches.Reset()
-
-	patches.ApplyFunc(commhttp.SendRequest,
-		func(_ *http.Request, _ *http.Client) (*http.Response, error) {
-			return &http.Response{Body: httpfake.NewFakeBodyReader([]byte{})}, nil
-		})
-
-	_, err := GetCACert(fakehost + "/ca.crt")
+	ca, err := GetCACert(srv.URL)
 	require.NoError(t, err)
+	require.Equal(t, []byte("test ca"), ca)
 }
 
 func TestGetEdgeCert(t *testing.T) {
-	const (
-		fakehost = "http://localhost"
-	)
+	newServer := func(code int, body string) (*httptest.Server, []byte) {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, constants.DefaultCertURL, r.URL.Path)
+			require.Equal(t, "testnode", r.Header.Get(types.HeaderNodeName))
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(srv.Close)
+		return srv, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	}
 
 	t.Run("request failed", func(t *testing.T) {
-		patches := gomonkey.NewPatches()
-		defer patches.Reset()
-
-		patches.ApplyFunc(commhttp.NewHTTPClientWithCA,
-			func(capem []byte, certificate tls.Certificate) (*http.Client, error) {
-				return &http.Client{}, nil
-			})
-		patches.ApplyFunc(commhttp.SendRequest,
-			func(_ *http.Request, _ *http.Client) (*http.Response, error) {
-				return &http.Response{
-					StatusCode: http.StatusInternalServerError,
-					Body:       httpfake.NewFakeBodyReader([]byte("test error")),
-				}, nil
-			})
-
-		cm := &CertManager{}
-		_, _, err := cm.GetEdgeCert(fakehost+constants.DefaultCAURL, []byte{}, tls.Certificate{}, "")
+		srv, capem := newServer(http.StatusInternalServerError, "test error")
+		cm := &CertManager{NodeName: "testnode", server: srv.URL}
+		_, _, err := cm.GetEdgeCert(capem, tls.Certificate{}, "token")
 		require.Error(t, err)
 		require.ErrorContains(t, err, "failed to call http, code: 500, message: test error")
 	})
 
 	t.Run("request successful", func(t *testing.T) {
-		patches := gomonkey.NewPatches()
-		defer patches.Reset()
-
-		patches.ApplyFunc(commhttp.NewHTTPClientWithCA,
-			func(capem []byte, certificate tls.Certificate) (*http.Client, error) {
-				return &http.Client{}, nil
-			})
-		patches.ApplyFunc(commhttp.SendRequest,
-			func(_ *http.Request, _ *http.Client) (*http.Response, error) {
-				return &http.Response{
-					StatusCode: http.StatusOK,
-					Body:       httpfake.NewFakeBodyReader([]byte("test cert...")),
-				}, nil
-			})
-
-		cm := &CertManager{}
-		_, _, err := cm.GetEdgeCert(fakehost+constants.DefaultCAURL, []byte{}, tls.Certificate{}, "")
+		srv, capem := newServer(http.StatusOK, "test cert...")
+		cm := &CertManager{NodeName: "testnode", server: srv.URL}
+		issued, keyDER, err := cm.GetEdgeCert(capem, tls.Certificate{}, "token")
+		require.NoError(t, err)
+		require.Equal(t, []byte("test cert..."), issued.Certificate)
+		require.Empty(t, issued.Chain)
+		require.NotEmpty(t, keyDER)
+	})
+
+	t.Run("certificate chain", func(t *testing.T) {
+		cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+		caKey, err := cahandler.GenPrivateKey()
+		require.NoError(t, err)
+		caBlock, err := cahandler.NewSelfSigned(caKey)
+		require.NoError(t, err)
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, types.MIMEPEMCertChain, r.Header.Get("Accept"))
+			csr, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			certBlock, err := certs.GetHandler(certs.HandlerTypeX509).SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+				csr, caBlock.Bytes, caKey.DER(), []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour))
+			require.NoError(t, err)
+			w.Header().Set("Content-Type", types.MIMEPEMCertChain)
+			_, _ = w.Write(append(pem.EncodeToMemory(certBlock), pem.EncodeToMemory(caBlock)...))
+		}))
+		t.Cleanup(srv.Close)
+		capem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+		cm := &CertManager{
+			NodeName: "testnode",
+			server:   srv.URL,
+			certFile: filepath.Join(t.TempDir(), "server.crt"),
+			keyFile:  filepath.Join(t.TempDir(), "server.key"),
+		}
+		issued, keyDER, err := cm.GetEdgeCert(capem, tls.Certificate{}, "token")
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{caBlock.Bytes}, issued.Chain)
+		require.NoError(t, certs.WriteDERsToPEMFile(cm.certFile, "CERTIFICATE", certChain(issued)))
+		_, err = certs.WriteDERToPEMFile(cm.keyFile, certs.PrivateKeyBlockType(keyDER), keyDER)
+		require.NoError(t, err)
+
+		tlsCert, err := cm.getCurrent()
 		require.NoError(t, err)
+		require.Equal(t, [][]byte{issued.Certificate, caBlock.Bytes}, tlsCert.Certificate)
 	})
+
+	t.Run("ed25519 key", func(t *testing.T) {
+		cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+		caKey, err := cahandler.GenPrivateKey()
+		require.NoError(t, err)
+		caBlock, err := cahandler.NewSelfSigned(caKey)
+		require.NoError(t, err)
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			csr, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			certBlock, err := certs.GetHandler(certs.HandlerTypeX509).SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+				csr, caBlock.Bytes, caKey.DER(), []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour))
+			require.NoError(t, err)
+			w.Header().Set("Content-Type", types.MIMEPEMCertChain)
+			_, _ = w.Write(pem.EncodeToMemory(certBlock))
+		}))
+		t.Cleanup(srv.Close)
+		capem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+		cm := &CertManager{
+			NodeName:     "testnode",
+			server:       srv.URL,
+			keyAlgorithm: certs.KeyTypeEd25519,
+			certFile:     filepath.Join(t.TempDir(), "server.crt"),
+			keyFile:      filepath.Join(t.TempDir(), "server.key"),
+		}
+		issued, keyDER, err := cm.GetEdgeCert(capem, tls.Certificate{}, "token")
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(issued.Certificate)
+		require.NoError(t, err)
+		require.Equal(t, x509.Ed25519, cert.PublicKeyAlgorithm)
+
+		// the PKCS #8 key is saved as a "PRIVATE KEY" block, which edgehub loads along with the cert
+		require.Equal(t, "PRIVATE KEY", certs.PrivateKeyBlockType(keyDER))
+		require.NoError(t, certs.WriteDERsToPEMFile(cm.certFile, "CERTIFICATE", certChain(issued)))
+		_, err = certs.WriteDERToPEMFile(cm.keyFile, certs.PrivateKeyBlockType(keyDER), keyDER)
+		require.NoError(t, err)
+		_, err = tls.LoadX509KeyPair(cm.certFile, cm.keyFile)
+		require.NoError(t, err)
+	})
+
+	t.Run("untrusted server", func(t *testing.T) {
+		srv, _ := newServer(http.StatusOK, "test cert...")
+		cahandler := certs.GetCAHandler(certs.CAHandlerTypeX509)
+		pk, err := cahandler.GenPrivateKey()
+		require.NoError(t, err)
+		otherCA, err := cahandler.NewSelfSigned(pk)
+		require.NoError(t, err)
+		cm := &CertManager{NodeName: "testnode", server: srv.URL}
+		_, _, err = cm.GetEdgeCert(pem.EncodeToMemory(otherCA), tls.Certificate{}, "token")
+		require.ErrorContains(t, err, "failed to request the cloudcore server")
+	})
+}
+
+func TestApplyCertsWithSecondaryToken(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == constants.DefaultCAURL {
+			_, _ = w.Write(srv.Certificate().Raw)
+			return
+		}
+		if r.Header.Get(types.HeaderAuthorization) != "Bearer fresh.jwt.token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(types.ReasonTokenExpired + ": token validation failure, err: token is expired"))
+			return
+		}
+		_, _ = w.Write([]byte("test cert..."))
+	}))
+	defer srv.Close()
+	digest := sha256.Sum256(srv.Certificate().Raw)
+	caHash := hex.EncodeToString(digest[:])
+
+	dir := t.TempDir()
+	newCertManager := func(secondaryTokenFile string) *CertManager {
+		return &CertManager{
+			NodeName:           "testnode",
+			server:             srv.URL,
+			token:              caHash + ".expired.jwt.token",
+			secondaryTokenFile: secondaryTokenFile,
+			caFile:             filepath.Join(dir, "ca.crt"),
+			certFile:           filepath.Join(dir, "server.crt"),
+			keyFile:            filepath.Join(dir, "server.key"),
+		}
+	}
+
+	err := newCertManager("").applyCerts()
+	require.ErrorIs(t, err, ErrTokenExpired)
+
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(caHash+".expired.jwt.token\n"), 0600))
+	err = newCertManager(tokenFile).applyCerts()
+	require.ErrorIs(t, err, ErrTokenExpired)
+	require.ErrorContains(t, err, "has expired too")
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte(caHash+".fresh.jwt.token\n"), 0600))
+	require.NoError(t, newCertManager(tokenFile).applyCerts())
+	certPEM, err := os.ReadFile(filepath.Join(dir, "server.crt"))
+	require.NoError(t, err)
+	block, _ := pem.Decode(certPEM)
+	require.Equal(t, []byte("test cert..."), block.Bytes)
+}
+
+func TestApplyCertsWithCABundle(t *testing.T) {
+	other := httptest.NewTLSServer(http.NotFoundHandler())
+	other.Close()
+	otherCA := other.Certificate().Raw
+
+	cases := []struct {
+		name string
+		// bundle returns the CA bundle served to the clients accepting the PEM bundle, the
+		// older CloudHub serves the CA only if it is nil
+		bundle func(ca []byte) [][]byte
+		want   func(ca []byte) [][]byte
+	}{
+		{
+			name:   "bundle",
+			bundle: func(ca []byte) [][]byte { return [][]byte{ca, otherCA} },
+			want:   func(ca []byte) [][]byte { return [][]byte{ca, otherCA} },
+		},
+		{
+			name: "older CloudHub",
+			want: func(ca []byte) [][]byte { return [][]byte{ca} },
+		},
+		{
+			name:   "bundle without the pinned CA",
+			bundle: func([]byte) [][]byte { return [][]byte{otherCA} },
+			want:   func(ca []byte) [][]byte { return [][]byte{ca} },
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var srv *httptest.Server
+			srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != constants.DefaultCAURL {
+					_, _ = w.Write([]byte("test cert..."))
+					return
+				}
+				ca := srv.Certificate().Raw
+				if c.bundle == nil || r.Header.Get("Accept") != types.MIMEPEMFile {
+					_, _ = w.Write(ca)
+					return
+				}
+				// the bundle is only served over the TLS verified by the pinned CA
+				require.NotNil(t, r.TLS)
+				w.Header().Set("Content-Type", types.MIMEPEMFile)
+				for _, der := range c.bundle(ca) {
+					_, _ = w.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+				}
+			}))
+			defer srv.Close()
+			digest := sha256.Sum256(srv.Certificate().Raw)
+
+			dir := t.TempDir()
+			cm := &CertManager{
+				NodeName: "testnode",
+				server:   srv.URL,
+				token:    hex.EncodeToString(digest[:]) + ".fresh.jwt.token",
+				caFile:   filepath.Join(dir, "ca.crt"),
+				certFile: filepath.Join(dir, "server.crt"),
+				keyFile:  filepath.Join(dir, "server.key"),
+			}
+			require.NoError(t, cm.applyCerts())
+
+			caPEM, err := os.ReadFile(cm.caFile)
+			require.NoError(t, err)
+			var bundle [][]byte
+			for block, rest := pem.Decode(caPEM); block != nil; block, rest = pem.Decode(rest) {
+				bundle = append(bundle, block.Bytes)
+			}
+			require.Equal(t, c.want(srv.Certificate().Raw), bundle)
+		})
+	}
 }
 
 const (
@@ -161,7 +356,7 @@ func genFakeCerts() error {
 	if err != nil {
 		return fmt.Errorf("failed to create a csr of edge cert, err %v", err)
 	}
-	certBlock, err := certshandler.SignCerts(certs.SignCertsOptionsWithCSR(
+	certBlock, err := certshandler.SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
 		csrPem.Bytes,
 		caPem.Bytes,
 		pk.DER(),
@@ -186,3 +381,111 @@ func genFakeCerts() error {
 	}
 	return nil
 }
+
+func TestApplyCertsWithRetry(t *testing.T) {
+	origin := certBackoff
+	certBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+	defer func() { certBackoff = origin }()
+
+	cases := []struct {
+		name         string
+		failures     int
+		code         int
+		message      string
+		wantRequests int
+		wantError    string
+	}{
+		{
+			name:         "transient errors are retried",
+			failures:     2,
+			code:         http.StatusServiceUnavailable,
+			message:      "failed to sign certs for edgenode testnode, err: the signing queue is full",
+			wantRequests: 3,
+		},
+		{
+			name:         "transient errors until the backoff limit",
+			failures:     3,
+			code:         http.StatusServiceUnavailable,
+			message:      "failed to sign certs for edgenode testnode, err: the signing queue is full",
+			wantRequests: 3,
+			wantError:    "the signing queue is full",
+		},
+		{
+			name:         "expired token is not retried",
+			failures:     3,
+			code:         http.StatusUnauthorized,
+			message:      types.ReasonTokenExpired + ": token validation failure, err: token is expired",
+			wantRequests: 1,
+			wantError:    ErrTokenExpired.Error(),
+		},
+		{
+			name:         "consumed token is not retried",
+			failures:     3,
+			code:         http.StatusUnauthorized,
+			message:      types.ReasonTokenConsumed + ": token validation failure, the token abc of edgenode testnode was consumed at 2025-01-01T00:00:00Z",
+			wantRequests: 1,
+			wantError:    "was consumed at",
+		},
+		{
+			name:         "invalid CSR is not retried",
+			failures:     3,
+			code:         http.StatusBadRequest,
+			message:      types.ReasonCSRInvalid + ": invalid CSR",
+			wantRequests: 1,
+			wantError:    "invalid CSR",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var requests int
+			var srv *httptest.Server
+			srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == constants.DefaultCAURL {
+					_, _ = w.Write(srv.Certificate().Raw)
+					return
+				}
+				if requests++; requests <= c.failures {
+					w.WriteHeader(c.code)
+					_, _ = w.Write([]byte(c.message))
+					return
+				}
+				_, _ = w.Write([]byte("test cert..."))
+			}))
+			defer srv.Close()
+			digest := sha256.Sum256(srv.Certificate().Raw)
+
+			dir := t.TempDir()
+			cm := &CertManager{
+				NodeName: "testnode",
+				server:   srv.URL,
+				token:    hex.EncodeToString(digest[:]) + ".test.jwt.token",
+				caFile:   filepath.Join(dir, "ca.crt"),
+				certFile: filepath.Join(dir, "server.crt"),
+				keyFile:  filepath.Join(dir, "server.key"),
+			}
+			err := cm.applyCertsWithRetry()
+			require.Equal(t, c.wantRequests, requests)
+			if c.wantError != "" {
+				require.ErrorContains(t, err, c.wantError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	newError := func(message string) error {
+		return &certclient.Error{StatusCode: http.StatusBadRequest, Message: message, Reason: strings.SplitN(message, ":", 2)[0]}
+	}
+	require.True(t, retryable(errors.New("failed to request the cloudcore server")))
+	require.True(t, retryable(&certclient.Error{StatusCode: http.StatusServiceUnavailable}))
+	require.True(t, retryable(newError(types.ReasonNodeNotApproved+": the node is not approved yet")))
+	require.True(t, retryable(newError(types.ReasonSignerUnavailable+": fail to signCerts")))
+	require.False(t, retryable(fmt.Errorf("%w, the token has expired too", ErrTokenExpired)))
+	require.False(t, retryable(fmt.Errorf("failed: %w", newError(types.ReasonTokenExpired+": expired"))))
+	require.False(t, retryable(newError(types.ReasonTokenConsumed+": consumed")))
+	require.False(t, retryable(newError(types.ReasonSubjectMismatch+": mismatch")))
+	require.False(t, retryable(newError(types.ReasonCertInvalid+": revoked")))
+	require.False(t, retryable(&certclient.Error{StatusCode: http.StatusRequestEntityTooLarge}))
+}
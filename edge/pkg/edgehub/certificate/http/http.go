@@ -1,108 +0,0 @@
-package http
-
-import (
-	"crypto/tls"
-	"crypto/x509"
-	"fmt"
-	"io"
-	"net"
-	"net/http"
-	"time"
-
-	"k8s.io/klog/v2"
-
-	"github.com/kubeedge/kubeedge/common/types"
-)
-
-const (
-	defaultConnectTimeout            = 30 * time.Second
-	defaultKeepAliveTimeout          = 30 * time.Second
-	defaultResponseReadTimeout       = 300 * time.Second
-	defaultMaxIdleConnectionsPerHost = 3
-)
-
-var (
-	connectTimeout            = defaultConnectTimeout
-	keepaliveTimeout          = defaultKeepAliveTimeout
-	responseReadTimeout       = defaultResponseReadTimeout
-	maxIdleConnectionsPerHost = defaultMaxIdleConnectionsPerHost
-)
-
-// NewHTTPClient create new client
-func NewHTTPClient() *http.Client {
-	transport := &http.Transport{
-		DialContext: (&net.Dialer{
-			Timeout:   connectTimeout,
-			KeepAlive: keepaliveTimeout,
-		}).DialContext,
-		MaxIdleConnsPerHost:   maxIdleConnectionsPerHost,
-		ResponseHeaderTimeout: responseReadTimeout,
-		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-	}
-	klog.Infof("tlsConfig InsecureSkipVerify true")
-	return &http.Client{Transport: transport}
-}
-
-// NewHTTPSClient create https client
-func NewHTTPSClient(certFile, keyFile string) (*http.Client, error) {
-	pool := x509.NewCertPool()
-	cliCrt, err := tls.LoadX509KeyPair(certFile, keyFile)
-	if err != nil {
-		klog.Errorf("Cannot create https client , Load x509 key pair err: %v", err)
-		return nil, err
-	}
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs:      pool,
-			Certificates: []tls.Certificate{cliCrt},
-			MinVersion:   tls.VersionTLS12,
-			CipherSuites: []uint16{
-				tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-			},
-			InsecureSkipVerify: true}, /*Now we need set it true*/
-	}
-	client := &http.Client{Transport: tr, Timeout: connectTimeout}
-	return client, nil
-}
-
-// NewHTTPClientWithCA create client without certificate
-func NewHTTPClientWithCA(capem []byte, certificate tls.Certificate) (*http.Client, error) {
-	pool := x509.NewCertPool()
-	if ok := pool.AppendCertsFromPEM(capem); !ok {
-		return nil, fmt.Errorf("cannot parse the certificates")
-	}
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs:            pool,
-			InsecureSkipVerify: false,
-			Certificates:       []tls.Certificate{certificate},
-		},
-	}
-	client := &http.Client{Transport: tr, Timeout: connectTimeout}
-	return client, nil
-}
-
-// SendRequest sends a http request and return the resp info
-func SendRequest(req *http.Request, client *http.Client) (*http.Response, error) {
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
-}
-
-// BuildRequest Creates a HTTP request.
-func BuildRequest(method string, urlStr string, body io.Reader, token string, nodeName string) (*http.Request, error) {
-	req, err := http.NewRequest(method, urlStr, body)
-	if err != nil {
-		return nil, err
-	}
-	if token != "" {
-		bearerToken := "Bearer " + token
-		req.Header.Add(types.HeaderAuthorization, bearerToken)
-	}
-	if nodeName != "" {
-		req.Header.Add(types.HeaderNodeName, nodeName)
-	}
-	return req, nil
-}
@@ -15,3 +15,10 @@ type Adapter interface {
 	// Notify auth info
 	Notify(authInfo map[string]string)
 }
+
+// PeerCloser is implemented by the clients which know the close code of
+// the connection closed by the cloud
+type PeerCloser interface {
+	// PeerCloseCode returns 0 if the cloud did not close the connection with a close code
+	PeerCloseCode() int
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients/quicclient"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients/wsclient"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 )
 
 // GetClient returns an Adapter object with new web socket
@@ -15,14 +16,21 @@ func GetClient() (Adapter, error) {
 	switch {
 	case config.WebSocket.Enable:
 		websocketConf := wsclient.WebSocketConfig{
-			URL:              config.WebSocketURL,
-			CertFilePath:     config.TLSCertFile,
-			KeyFilePath:      config.TLSPrivateKeyFile,
-			HandshakeTimeout: time.Duration(config.WebSocket.HandshakeTimeout) * time.Second,
-			ReadDeadline:     time.Duration(config.WebSocket.ReadDeadline) * time.Second,
-			WriteDeadline:    time.Duration(config.WebSocket.WriteDeadline) * time.Second,
-			ProjectID:        config.ProjectID,
-			NodeID:           config.NodeName,
+			URL:               config.WebSocketURL,
+			CertFilePath:      config.TLSCertFile,
+			KeyFilePath:       config.TLSPrivateKeyFile,
+			HandshakeTimeout:  time.Duration(config.WebSocket.HandshakeTimeout) * time.Second,
+			ReadDeadline:      time.Duration(config.WebSocket.ReadDeadline) * time.Second,
+			WriteDeadline:     time.Duration(config.WebSocket.WriteDeadline) * time.Second,
+			ProjectID:         config.ProjectID,
+			NodeID:            config.NodeName,
+			EnableCompression: config.WebSocket.EnableCompression,
+			Limits: api.MessageLimits{
+				MaxReadMessageSize:  config.WebSocket.MaxReadMessageSize,
+				MaxWriteMessageSize: config.WebSocket.MaxWriteMessageSize,
+				ReadBufferSize:      int(config.WebSocket.ReadBufferSize),
+				WriteBufferSize:     int(config.WebSocket.WriteBufferSize),
+			},
 		}
 		return wsclient.NewWebSocketClient(&websocketConf), nil
 	case config.Quic.Enable:
@@ -36,6 +44,12 @@ func GetClient() (Adapter, error) {
 			WriteDeadline:    time.Duration(config.Quic.WriteDeadline) * time.Second,
 			ProjectID:        config.ProjectID,
 			NodeID:           config.NodeName,
+			Limits: api.MessageLimits{
+				MaxReadMessageSize:  config.Quic.MaxReadMessageSize,
+				MaxWriteMessageSize: config.Quic.MaxWriteMessageSize,
+				ReadBufferSize:      int(config.Quic.ReadBufferSize),
+				WriteBufferSize:     int(config.Quic.WriteBufferSize),
+			},
 		}
 		return quicclient.NewQuicClient(&quicConfig), nil
 	}
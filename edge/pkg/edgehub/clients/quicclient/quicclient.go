@@ -11,6 +11,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
+	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 	qclient "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/client"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
@@ -33,6 +34,8 @@ type QuicConfig struct {
 	WriteDeadline    time.Duration
 	NodeID           string
 	ProjectID        string
+	// Limits indicates the max message sizes and the buffer sizes, which should agree with CloudHub
+	Limits api.MessageLimits
 }
 
 // NewQuicClient initializes a new quic client instance
@@ -68,9 +71,11 @@ func (qcc *QuicClient) Init() error {
 		Type:             api.ProtocolTypeQuic,
 		Addr:             qcc.config.Addr,
 	}
-	exOpts := api.QuicClientOption{Header: make(http.Header)}
+	exOpts := api.QuicClientOption{Header: make(http.Header), Limits: qcc.config.Limits}
 	exOpts.Header.Set("node_id", qcc.config.NodeID)
 	exOpts.Header.Set("project_id", qcc.config.ProjectID)
+	exOpts.Header.Set(messagepkg.HeaderReliableAck, "true")
+	exOpts.Header.Set(messagepkg.HeaderSchemaVersion, messagepkg.SchemaVersion)
 	client := qclient.NewQuicClient(option, exOpts)
 	connection, err := client.Connect()
 	if err != nil {
@@ -103,6 +108,14 @@ func (qcc *QuicClient) Receive() (model.Message, error) {
 	return message, err
 }
 
+// PeerCloseCode returns the close code of the connection closed by the cloud
+func (qcc *QuicClient) PeerCloseCode() int {
+	if qcc.client == nil {
+		return 0
+	}
+	return conn.PeerCloseCode(qcc.client)
+}
+
 // Notify logs info
 func (qcc *QuicClient) Notify(map[string]string) {
 	klog.Infof("Do not care")
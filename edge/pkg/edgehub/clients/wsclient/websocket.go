@@ -12,10 +12,12 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
+	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 	wsclient "github.com/kubeedge/kubeedge/pkg/viaduct/pkg/client"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/lane"
 )
 
 const (
@@ -39,6 +41,10 @@ type WebSocketConfig struct {
 	WriteDeadline    time.Duration
 	NodeID           string
 	ProjectID        string
+	// EnableCompression indicates whether to negotiate the permessage-deflate compression
+	EnableCompression bool
+	// Limits indicates the max message sizes and the buffer sizes, which should agree with CloudHub
+	Limits api.MessageLimits
 }
 
 // NewWebSocketClient initializes a new websocket client instance
@@ -79,9 +85,15 @@ func (wsc *WebSocketClient) Init() error {
 		AutoRoute:        false,
 		ConnUse:          api.UseTypeMessage,
 	}
-	exOpts := api.WSClientOption{Header: make(http.Header)}
+	exOpts := api.WSClientOption{
+		Header:            make(http.Header),
+		EnableCompression: wsc.config.EnableCompression,
+		Limits:            wsc.config.Limits,
+	}
 	exOpts.Header.Set("node_id", wsc.config.NodeID)
 	exOpts.Header.Set("project_id", wsc.config.ProjectID)
+	exOpts.Header.Set(messagepkg.HeaderReliableAck, "true")
+	exOpts.Header.Set(messagepkg.HeaderSchemaVersion, messagepkg.SchemaVersion)
 	client := &wsclient.Client{Options: option, ExOpts: exOpts}
 
 	for i := 0; i < retryCount; i++ {
@@ -101,6 +113,9 @@ func (wsc *WebSocketClient) Init() error {
 // UnInit closes the websocket connection
 func (wsc *WebSocketClient) UnInit() {
 	wsc.connection.Close()
+	stats := lane.GetWSStats()
+	klog.Infof("Websocket sent %d bytes of messages in %d bytes on the wire, compression enabled: %t",
+		stats.PayloadBytes, stats.WireBytes, wsc.config.EnableCompression)
 }
 
 // Send sends the message as JSON object through the connection
@@ -122,6 +137,14 @@ func (wsc *WebSocketClient) Receive() (model.Message, error) {
 	return message, err
 }
 
+// PeerCloseCode returns the close code of the connection closed by the cloud
+func (wsc *WebSocketClient) PeerCloseCode() int {
+	if wsc.connection == nil {
+		return 0
+	}
+	return conn.PeerCloseCode(wsc.connection)
+}
+
 // Notify logs info
 func (wsc *WebSocketClient) Notify(map[string]string) {
 	klog.Infof("no op")
@@ -2,6 +2,7 @@ package edgehub
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"k8s.io/client-go/util/flowcontrol"
@@ -25,6 +26,11 @@ type EdgeHub struct {
 	rateLimiter   flowcontrol.RateLimiter
 	keeperLock    sync.RWMutex
 	enable        bool
+	// delivered keeps the IDs of the delivered reliable messages across the reconnections
+	delivered *deliveredIDs
+	// reconnectAt is the time in unix nanoseconds to reconnect to the cloud asked by
+	// the CloudHub which stops, 0 if not asked
+	reconnectAt atomic.Int64
 }
 
 var _ core.Module = (*EdgeHub)(nil)
@@ -46,6 +52,7 @@ func newEdgeHub(enable bool) *EdgeHub {
 	return &EdgeHub{
 		enable:        enable,
 		reconnectChan: make(chan struct{}),
+		delivered:     newDeliveredIDs(deliveredCapacity),
 		rateLimiter: flowcontrol.NewTokenBucketRateLimiter(
 			float32(config.Config.EdgeHub.MessageQPS),
 			int(config.Config.EdgeHub.MessageBurst)),
@@ -123,7 +130,9 @@ func (eh *EdgeHub) Start() {
 		// execute hook fun after disconnect
 		eh.pubConnectInfo(false)
 
-		// sleep one period of heartbeat, then try to connect cloud hub again
+		// sleep one period of heartbeat, or the delay asked by the stopping cloud hub,
+		// then try to connect cloud hub again
+		waitTime = eh.reconnectWait(waitTime)
 		klog.Warningf("connection is broken, will reconnect after %s", waitTime.String())
 		time.Sleep(waitTime)
 
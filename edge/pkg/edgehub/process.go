@@ -15,6 +15,8 @@ import (
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
 	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/config"
 	msghandler "github.com/kubeedge/kubeedge/edge/pkg/edgehub/messagehandler"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
 )
 
 var (
@@ -53,7 +55,15 @@ func (eh *EdgeHub) routeToEdge() {
 			eh.reconnectChan <- struct{}{}
 			return
 		}
-		klog.V(4).Infof("[edgehub/routeToEdge] receive msg from cloud, msg: %+v", message)
+		msgtrace.Infof(4, &message, "[edgehub/routeToEdge] receive msg from cloud, msg: %+v", message)
+		if message.GetGroup() == messagepkg.HubGroupName && message.GetOperation() == messagepkg.OperationReconnect {
+			eh.scheduleReconnect(message)
+			continue
+		}
+		if message.Header.Reliable {
+			eh.dispatchReliable(message)
+			continue
+		}
 		if err = eh.dispatch(message); err != nil {
 			klog.Error(err)
 		}
@@ -62,11 +72,11 @@ func (eh *EdgeHub) routeToEdge() {
 
 func (eh *EdgeHub) sendToCloud(message model.Message) error {
 	eh.keeperLock.Lock()
-	klog.V(4).Infof("[edgehub/sendToCloud] send msg to cloud, msg: %+v", message)
+	msgtrace.Infof(4, &message, "[edgehub/sendToCloud] send msg to cloud, msg: %+v", message)
 	err := eh.chClient.Send(message)
 	eh.keeperLock.Unlock()
 	if err != nil {
-		return fmt.Errorf("failed to send message, error: %v", err)
+		return fmt.Errorf("failed to send message, error: %w", err)
 	}
 
 	return nil
@@ -95,6 +105,11 @@ func (eh *EdgeHub) routeToCloud() {
 
 		// post message to cloud hub
 		err = eh.sendToCloud(message)
+		if comm.IsMessageSizeError(err) {
+			// the oversized message is dropped before it is written, the connection is still usable
+			klog.Errorf("drop the message %s to cloud: %v", message.GetID(), err)
+			continue
+		}
 		if err != nil {
 			klog.Errorf("failed to send message to cloud: %v", err)
 			eh.reconnectChan <- struct{}{}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgehub
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
+)
+
+// maxReconnectDelay bounds the reconnect delay asked by the cloud
+const maxReconnectDelay = 10 * time.Minute
+
+// notAdmittedReconnectDelay is the delay before reconnecting to the cloud which refused
+// the connection as the node is not admitted, e.g. the node is quarantined
+const notAdmittedReconnectDelay = 5 * time.Minute
+
+// scheduleReconnect records when to reconnect to the cloud, which is asked by the CloudHub
+// draining its sessions as it stops, so that the edge nodes don't reconnect all at once.
+func (eh *EdgeHub) scheduleReconnect(message model.Message) {
+	content, ok := message.GetContent().(string)
+	if !ok {
+		klog.Warningf("invalid reconnect message from cloud, content: %v", message.GetContent())
+		return
+	}
+	delay, err := time.ParseDuration(content)
+	if err != nil || delay < 0 {
+		klog.Warningf("invalid reconnect delay %q from cloud", content)
+		return
+	}
+	if delay > maxReconnectDelay {
+		delay = maxReconnectDelay
+	}
+	klog.Infof("cloud hub is stopping, will reconnect after %s once disconnected", delay)
+	eh.reconnectAt.Store(time.Now().Add(delay).UnixNano())
+}
+
+// reconnectWait returns the time to wait before reconnecting to the cloud, which is the longer
+// delay if the cloud refused the node, the rest of the delay asked by the cloud if any,
+// otherwise the given default wait time.
+func (eh *EdgeHub) reconnectWait(waitTime time.Duration) time.Duration {
+	at := eh.reconnectAt.Swap(0)
+	if pc, ok := eh.chClient.(clients.PeerCloser); ok && pc.PeerCloseCode() == conn.CloseCodeNotAdmitted {
+		klog.Warningf("the node is not admitted by the cloud, will reconnect after %s", notAdmittedReconnectDelay)
+		return notAdmittedReconnectDelay
+	}
+	if at == 0 {
+		return waitTime
+	}
+	if rest := time.Until(time.Unix(0, at)); rest > 0 {
+		return rest
+	}
+	return 0
+}
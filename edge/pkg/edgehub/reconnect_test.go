@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgehub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/edgehub/clients"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/conn"
+)
+
+func newReconnectMessage(content interface{}) model.Message {
+	return *model.NewMessage("").BuildRouter("cloudhub", message.HubGroupName, "node/edge-node", message.OperationReconnect).
+		FillBody(content)
+}
+
+func TestReconnectWait(t *testing.T) {
+	waitTime := 30 * time.Second
+	tests := []struct {
+		name    string
+		content interface{}
+		min     time.Duration
+		max     time.Duration
+	}{
+		{
+			name:    "not asked by the cloud",
+			content: nil,
+			min:     waitTime,
+			max:     waitTime,
+		},
+		{
+			name:    "delay asked by the cloud",
+			content: "5s",
+			min:     4 * time.Second,
+			max:     5 * time.Second,
+		},
+		{
+			name:    "delay elapsed",
+			content: "0s",
+			min:     0,
+			max:     0,
+		},
+		{
+			name:    "delay bounded",
+			content: "24h",
+			min:     maxReconnectDelay - time.Second,
+			max:     maxReconnectDelay,
+		},
+		{
+			name:    "invalid delay",
+			content: "soon",
+			min:     waitTime,
+			max:     waitTime,
+		},
+		{
+			name:    "invalid content",
+			content: 5,
+			min:     waitTime,
+			max:     waitTime,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eh := &EdgeHub{}
+			if tt.content != nil {
+				eh.scheduleReconnect(newReconnectMessage(tt.content))
+			}
+			got := eh.reconnectWait(waitTime)
+			if got < tt.min || got > tt.max {
+				t.Errorf("expected the wait in [%v, %v], got %v", tt.min, tt.max, got)
+			}
+			// the delay asked by the cloud only applies to the next reconnection
+			if again := eh.reconnectWait(waitTime); again != waitTime {
+				t.Errorf("expected the default wait for the later reconnection, got %v", again)
+			}
+		})
+	}
+}
+
+type closedClient struct {
+	clients.Adapter
+	code int
+}
+
+func (c *closedClient) PeerCloseCode() int { return c.code }
+
+func TestReconnectWaitNotAdmitted(t *testing.T) {
+	waitTime := 30 * time.Second
+	eh := &EdgeHub{chClient: &closedClient{code: conn.CloseCodeNotAdmitted}}
+	eh.scheduleReconnect(newReconnectMessage("5s"))
+	if got := eh.reconnectWait(waitTime); got != notAdmittedReconnectDelay {
+		t.Errorf("expected the longer wait of the node not admitted, got %v", got)
+	}
+
+	eh = &EdgeHub{chClient: &closedClient{code: 1008}}
+	if got := eh.reconnectWait(waitTime); got != waitTime {
+		t.Errorf("expected the default wait for the other close codes, got %v", got)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgehub
+
+import (
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+)
+
+// deliveredCapacity is the number of the IDs of the latest delivered reliable messages which
+// are kept to deduplicate the messages sent again by the cloud
+const deliveredCapacity = 4096
+
+// deliveredIDs keeps the IDs of the latest delivered reliable messages, the oldest ID is
+// forgotten once the capacity is reached.
+type deliveredIDs struct {
+	mu   sync.Mutex
+	ids  map[string]struct{}
+	ring []string
+	next int
+}
+
+func newDeliveredIDs(capacity int) *deliveredIDs {
+	return &deliveredIDs{
+		ids:  make(map[string]struct{}, capacity),
+		ring: make([]string, capacity),
+	}
+}
+
+func (d *deliveredIDs) contains(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.ids[id]
+	return ok
+}
+
+func (d *deliveredIDs) add(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.ids[id]; ok {
+		return
+	}
+	if oldest := d.ring[d.next]; oldest != "" {
+		delete(d.ids, oldest)
+	}
+	d.ring[d.next] = id
+	d.ids[id] = struct{}{}
+	d.next = (d.next + 1) % len(d.ring)
+}
+
+// dispatchReliable dispatches the reliable message unless it has been delivered, and acknowledges
+// it to the cloud. The message which fails to be dispatched is not acknowledged, so that the cloud
+// sends it again.
+func (eh *EdgeHub) dispatchReliable(message model.Message) {
+	if eh.delivered.contains(message.GetID()) {
+		klog.V(4).Infof("[edgehub/routeToEdge] reliable message %s has been delivered", message.GetID())
+	} else {
+		if err := eh.dispatch(message); err != nil {
+			klog.Error(err)
+			return
+		}
+		eh.delivered.add(message.GetID())
+	}
+
+	ack := model.NewMessage(message.GetID()).
+		BuildRouter(modules.EdgeHubModuleName, messagepkg.ResourceGroupName, message.GetResource(), messagepkg.OperationReliableAck)
+	if err := eh.sendToCloud(*ack); err != nil {
+		klog.Errorf("failed to acknowledge reliable message %s: %v", message.GetID(), err)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package edgehub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/golang/mock/gomock"
+
+	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/edge/mocks/edgehub"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/message"
+	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	msghandler "github.com/kubeedge/kubeedge/edge/pkg/edgehub/messagehandler"
+)
+
+func TestDeliveredIDs(t *testing.T) {
+	d := newDeliveredIDs(2)
+	d.add("a")
+	d.add("b")
+	d.add("b")
+	if !d.contains("a") || !d.contains("b") {
+		t.Fatalf("expected a and b delivered")
+	}
+	d.add("c")
+	if d.contains("a") {
+		t.Errorf("expected the oldest ID forgotten")
+	}
+	if !d.contains("b") || !d.contains("c") {
+		t.Errorf("expected b and c delivered")
+	}
+}
+
+func TestDispatchReliable(t *testing.T) {
+	var dispatched int
+	patches := gomonkey.NewPatches()
+	defer patches.Reset()
+	patches.ApplyFunc(beehiveContext.SendToGroup, func(string, model.Message) {
+		dispatched++
+	})
+	msghandler.RegisterHandlers()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAdapter := edgehub.NewMockAdapter(mockCtrl)
+	hub := newEdgeHub(true)
+	hub.chClient = mockAdapter
+
+	msg := model.NewMessage("").BuildRouter(modules.EdgeHubModuleName, modules.TwinGroup, "membership/detail", "get")
+	msg.Header.Reliable = true
+
+	var acks []model.Message
+	mockAdapter.EXPECT().Send(gomock.Any()).DoAndReturn(func(ack model.Message) error {
+		acks = append(acks, ack)
+		return nil
+	}).Times(2)
+
+	// the message sent again is acknowledged without being dispatched again
+	hub.dispatchReliable(*msg)
+	hub.dispatchReliable(*msg)
+	if dispatched != 1 {
+		t.Errorf("expected the message dispatched once, got %d", dispatched)
+	}
+	for _, ack := range acks {
+		if ack.GetOperation() != message.OperationReliableAck || ack.GetParentID() != msg.GetID() {
+			t.Errorf("unexpected ack %+v", ack)
+		}
+	}
+
+	// the message failing to be dispatched is not acknowledged
+	failed := model.NewMessage("").BuildRouter(modules.EdgeHubModuleName, modules.EdgedGroup, "", "")
+	failed.Header.Reliable = true
+	hub.dispatchReliable(*failed)
+	if hub.delivered.contains(failed.GetID()) {
+		t.Errorf("expected the failed message not delivered")
+	}
+
+	mockAdapter.EXPECT().Send(gomock.Any()).Return(errors.New("connection closed"))
+	another := model.NewMessage("").BuildRouter(modules.EdgeHubModuleName, modules.TwinGroup, "membership/detail", "get")
+	another.Header.Reliable = true
+	hub.dispatchReliable(*another)
+	if !hub.delivered.contains(another.GetID()) {
+		t.Errorf("expected the message delivered even if the ack fails")
+	}
+}
@@ -2,14 +2,12 @@ package mqtt
 
 import (
 	"encoding/base64"
-	"fmt"
-
-	"k8s.io/klog/v2"
 
 	beehiveContext "github.com/kubeedge/beehive/pkg/core/context"
 	beehiveModel "github.com/kubeedge/beehive/pkg/core/model"
 	messagepkg "github.com/kubeedge/kubeedge/edge/pkg/common/message"
 	"github.com/kubeedge/kubeedge/edge/pkg/common/modules"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
 )
 
 // handleDevice for topic "$hw/events/device/+/twin/+", "$hw/events/node/+/membership/get"
@@ -19,7 +17,8 @@ func handleDeviceTwin(topic string, payload []byte) {
 	// routing key will be $hw.<project_id>.events.user.bus.response.cluster.<cluster_id>.node.<node_id>.<base64_topic>
 	message := beehiveModel.NewMessage("").BuildRouter(modules.BusGroup, modules.UserGroup,
 		resource, messagepkg.OperationResponse).FillBody(string(payload))
-	klog.Info(fmt.Sprintf("Received msg from mqttserver, deliver to %s with resource %s", target, message.GetResource()))
+	msgtrace.Start(message)
+	msgtrace.Infof(0, message, "Received msg from mqttserver, deliver to %s with resource %s", target, message.GetResource())
 	beehiveContext.SendToGroup(target, *message)
 }
 
@@ -28,6 +27,7 @@ func handleUploadTopic(topic string, payload []byte) {
 	target := modules.HubGroup
 	message := beehiveModel.NewMessage("").BuildRouter(modules.BusGroup, modules.UserGroup,
 		topic, beehiveModel.UploadOperation).FillBody(string(payload))
-	klog.Info(fmt.Sprintf("Received msg from mqttserver, deliver to %s with resource %s", target, message.GetResource()))
+	msgtrace.Start(message)
+	msgtrace.Infof(0, message, "Received msg from mqttserver, deliver to %s with resource %s", target, message.GetResource())
 	beehiveContext.SendToGroup(target, *message)
 }
@@ -36,6 +36,7 @@ import (
 	edgemodule "github.com/kubeedge/kubeedge/edge/pkg/common/modules"
 	metaserverconfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/config"
 	"github.com/kubeedge/kubeedge/pkg/metaserver"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
 )
 
 var DefaultAgent = NewApplicationAgent()
@@ -126,6 +127,8 @@ func (a *Agent) doApply(app *metaserver.Application) {
 	app.Status = metaserver.InApplying
 	msg := model.NewMessage("").SetRoute(metaserver.MetaServerSource, modules.DynamicControllerModuleGroup).FillBody(app)
 	msg.SetResourceOperation("null", "null")
+	msgtrace.Start(msg)
+	msgtrace.Infof(4, msg, "apply application %s to the cloud", app.ID)
 	resp, err := beehiveContext.SendSync(edgemodule.EdgeHubModuleName, *msg, 10*time.Second)
 	if err != nil {
 		app.Status = metaserver.Failed
@@ -20,6 +20,7 @@ import (
 	metaManagerConfig "github.com/kubeedge/kubeedge/edge/pkg/metamanager/config"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/dao"
 	"github.com/kubeedge/kubeedge/edge/pkg/metamanager/metaserver/kubernetes/storage/sqlite/imitator"
+	"github.com/kubeedge/kubeedge/pkg/util/msgtrace"
 )
 
 // Constants to check metamanager processes
@@ -122,6 +123,9 @@ func requireRemoteQuery(resType string) bool {
 }
 
 func msgDebugInfo(message *model.Message) string {
+	if traceID := message.GetTraceID(); traceID != "" {
+		return fmt.Sprintf("msgID[%s] resource[%s] traceID[%s]", message.GetID(), message.GetResource(), traceID)
+	}
 	return fmt.Sprintf("msgID[%s] resource[%s]", message.GetID(), message.GetResource())
 }
 
@@ -431,6 +435,7 @@ func (m *metaManager) processVolume(message model.Message) {
 
 func (m *metaManager) process(message model.Message) {
 	operation := message.GetOperation()
+	msgtrace.Infof(4, &message, "metamanager process message, operation: %s, resource: %s", operation, message.GetResource())
 
 	switch operation {
 	case model.InsertOperation:
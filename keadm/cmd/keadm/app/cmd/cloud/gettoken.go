@@ -18,14 +18,20 @@ package cloud
 
 import (
 	"context"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+	secutoken "github.com/kubeedge/kubeedge/pkg/security/token"
 )
 
 var (
@@ -38,6 +44,12 @@ certificate request.
 keadm gettoken --kube-config /root/.kube/config
 - kube-config is the absolute path of kubeconfig which used to build secure connectivity between keadm and kube-apiserver
 to get the token.
+
+keadm gettoken --node-name edge-node-1 --ttl 1h
+- mints a token that can only enroll the edge node edge-node-1 once, within an hour.
+
+keadm gettoken --node-name edge-node-1 --ttl 1h --stateless
+- mints a token carrying the node name edge-node-1 that is not recorded, which can be used within an hour.
 `
 )
 
@@ -51,7 +63,13 @@ func NewGettoken() *cobra.Command {
 		Long:    gettokenLongDescription,
 		Example: gettokenExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := queryToken(constants.SystemNamespace, common.TokenSecretName, init.Kubeconfig)
+			var token []byte
+			var err error
+			if init.NodeName != "" {
+				token, err = mintNodeToken(constants.SystemNamespace, init.NodeName, init.TTL, init.Stateless, init.Kubeconfig)
+			} else {
+				token, err = queryToken(constants.SystemNamespace, common.TokenSecretName, init.Kubeconfig)
+			}
 			if err != nil {
 				fmt.Printf("failed to get token, err is %s\n", err)
 				return err
@@ -66,12 +84,19 @@ func NewGettoken() *cobra.Command {
 func addGettokenFlags(cmd *cobra.Command, gettokenOptions *common.GettokenOptions) {
 	cmd.Flags().StringVar(&gettokenOptions.Kubeconfig, common.FlagNameKubeConfig, gettokenOptions.Kubeconfig,
 		"Use this key to set kube-config path, eg: $HOME/.kube/config")
+	cmd.Flags().StringVar(&gettokenOptions.NodeName, common.FlagNameNodeName, gettokenOptions.NodeName,
+		"Mint a token which can only enroll the edge node of this name once, instead of getting the global token")
+	cmd.Flags().DurationVar(&gettokenOptions.TTL, common.FlagNameTTL, gettokenOptions.TTL,
+		"The time to live of the token minted for the edge node of --node-name")
+	cmd.Flags().BoolVar(&gettokenOptions.Stateless, common.FlagNameStateless, gettokenOptions.Stateless,
+		"Mint a token of the edge node of --node-name which is not recorded, so it is not single-use")
 }
 
 // newGettokenOptions return common options
 func newGettokenOptions() *common.GettokenOptions {
 	opts := &common.GettokenOptions{}
 	opts.Kubeconfig = common.DefaultKubeConfig
+	opts.TTL = 24 * time.Hour
 	return opts
 }
 
@@ -88,6 +113,50 @@ func queryToken(namespace string, name string, kubeConfigPath string) ([]byte, e
 	return secret.Data[common.TokenDataName], nil
 }
 
+// mintNodeToken mints a token for the node signed by the CA key of CloudCore, and records it
+// in a Secret, which CloudCore consumes when the node enrolls. The stateless token only carries
+// the node name claim and is not recorded.
+func mintNodeToken(namespace, nodeName string, ttl time.Duration, stateless bool, kubeConfigPath string) ([]byte, error) {
+	client, err := util.KubeClient(kubeConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return createNodeToken(client, namespace, nodeName, ttl, stateless)
+}
+
+func createNodeToken(client kubernetes.Interface, namespace, nodeName string, ttl time.Duration, stateless bool) ([]byte, error) {
+	caSecret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), common.CaSecretName, metaV1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the CA of CloudCore, err: %v", err)
+	}
+	// The CA key is absent when CloudCore keeps it in a key provider, and the tokens are
+	// signed with the dedicated token key instead.
+	tokenKey := caSecret.Data[common.TokenKeyDataName]
+	if len(tokenKey) == 0 {
+		tokenKey = caSecret.Data[common.CaKeyDataName]
+		// The encrypted CA key never signs the tokens, CloudCore generates the token key on start
+		if block, _ := pem.Decode(tokenKey); block != nil && certs.IsEncryptedPrivateKey(block) {
+			return nil, errors.New("the CA key is encrypted and the token key is not generated yet, wait for CloudCore to start")
+		}
+	}
+	if stateless {
+		token, err := secutoken.CreateWithNodeName(caSecret.Data[common.CaDataName], tokenKey, nodeName, ttl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the token, err: %v", err)
+		}
+		return []byte(token), nil
+	}
+	token, claims, err := secutoken.CreateNodeToken(caSecret.Data[common.CaDataName], tokenKey, nodeName, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the token, err: %v", err)
+	}
+	if _, err := client.CoreV1().Secrets(namespace).Create(context.Background(),
+		secutoken.NodeTokenSecret(claims, namespace), metaV1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to record the token, err: %v", err)
+	}
+	return []byte(token), nil
+}
+
 // showToken prints the token
 func showToken(data []byte) error {
 	_, err := fmt.Println(string(data))
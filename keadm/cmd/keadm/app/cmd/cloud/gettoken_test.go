@@ -17,17 +17,25 @@ limitations under the License.
 package cloud
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	certutil "k8s.io/client-go/util/cert"
 
 	"github.com/kubeedge/kubeedge/common/constants"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+	"github.com/kubeedge/kubeedge/pkg/security/token"
 )
 
 func TestNewGetToken(t *testing.T) {
@@ -196,3 +204,51 @@ func TestGettokenRunE(t *testing.T) {
 	err = cmd.RunE(cmd, []string{})
 	assert.NoError(err)
 }
+
+func TestCreateNodeToken(t *testing.T) {
+	assert := assert.New(t)
+
+	client := fake.NewSimpleClientset()
+	_, err := createNodeToken(client, constants.SystemNamespace, "edge-node", time.Hour, false)
+	assert.Error(err)
+	assert.Contains(err.Error(), "failed to get the CA of CloudCore")
+
+	key, err := certs.GenPrivateKey("")
+	assert.NoError(err)
+	signer, err := key.Signer()
+	assert.NoError(err)
+	ca, err := certutil.NewSelfSignedCACert(certutil.Config{CommonName: "KubeEdge"}, signer)
+	assert.NoError(err)
+	keyDER := key.DER()
+	_, err = client.CoreV1().Secrets(constants.SystemNamespace).Create(context.TODO(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: common.CaSecretName, Namespace: constants.SystemNamespace},
+		Data:       map[string][]byte{common.CaDataName: ca.Raw, common.CaKeyDataName: keyDER},
+	}, metav1.CreateOptions{})
+	assert.NoError(err)
+
+	data, err := createNodeToken(client, constants.SystemNamespace, "edge-node", time.Hour, false)
+	assert.NoError(err)
+	realToken, err := token.VerifyCAAndGetRealToken(string(data), ca.Raw)
+	assert.NoError(err)
+	claims, err := token.ParseNodeToken(realToken, keyDER)
+	assert.NoError(err)
+	assert.Equal("edge-node", claims.NodeName)
+
+	secret, err := client.CoreV1().Secrets(constants.SystemNamespace).Get(context.TODO(),
+		token.NodeTokenSecretName(claims.ID), metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal("edge-node", string(secret.Data[token.NodeTokenDataNodeName]))
+
+	// the stateless token is not recorded
+	data, err = createNodeToken(client, constants.SystemNamespace, "stateless-node", time.Hour, true)
+	assert.NoError(err)
+	realToken, err = token.VerifyCAAndGetRealToken(string(data), ca.Raw)
+	assert.NoError(err)
+	claims, err = token.ParseNodeToken(realToken, keyDER)
+	assert.NoError(err)
+	assert.Equal("stateless-node", claims.NodeName)
+	assert.Empty(claims.ID)
+	secrets, err := client.CoreV1().Secrets(constants.SystemNamespace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(err)
+	assert.Len(secrets.Items, 2)
+}
@@ -17,15 +17,24 @@ limitations under the License.
 package cloud
 
 import (
+	"bytes"
+	"context"
+	"encoding/pem"
 	"fmt"
+	"os"
 
 	"github.com/blang/semver"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/kubeedge/kubeedge/common/constants"
 	types "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/helm"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
 )
 
 var (
@@ -41,6 +50,10 @@ keadm init
 keadm init --advertise-address=127.0.0.1 --kubeedge-version=v%s --kube-config=/root/.kube/config
   - kube-config is the absolute path of kubeconfig which used to secure connectivity between cloudcore and kube-apiserver
 	- a list of helm style set flags like "--set key=value" can be implemented, ref: https://github.com/kubeedge/kubeedge/tree/master/manifests/charts/cloudcore/README.md
+
+keadm init --ca-key-passphrase-file=/etc/kubeedge/ca/passphrase
+  - generates the CA of cloudcore with the key encrypted by the passphrase, cloudcore must be given the same passphrase
+	by cloudHub.caKeyPassphraseFile or the env CLOUDCORE_CA_KEY_PASSPHRASE
 `
 )
 
@@ -53,6 +66,15 @@ func NewCloudInit() *cobra.Command {
 		Long:    cloudInitLongDescription,
 		Example: fmt.Sprintf(cloudInitExample, types.DefaultKubeEdgeVersion),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.CAKeyPassphraseFile != "" && !opts.DryRun {
+				client, err := util.KubeClient(opts.KubeConfig)
+				if err != nil {
+					return err
+				}
+				if err := createEncryptedCASecret(client, constants.SystemNamespace, opts.CAKeyPassphraseFile); err != nil {
+					return err
+				}
+			}
 			tool := helm.NewCloudCoreHelmTool(opts.KubeConfig, opts.KubeEdgeVersion)
 			return tool.Install(opts)
 		},
@@ -96,6 +118,56 @@ func addInitOtherFlags(cmd *cobra.Command, initOpts *types.InitOptions) {
 
 	cmd.Flags().StringVar(&initOpts.ImageRepository, types.FlagNameImageRepository, initOpts.ImageRepository,
 		"Choose a container image repository to pull the image of the kubedge component.")
+
+	cmd.Flags().StringVar(&initOpts.CAKeyPassphraseFile, types.FlagNameCAKeyPassphraseFile, initOpts.CAKeyPassphraseFile,
+		"Generate the CA of cloudcore with the key encrypted by the passphrase of this file, "+
+			"cloudcore must be configured with the same passphrase by cloudHub.caKeyPassphraseFile or the env "+certs.CAKeyPassphraseEnv)
+}
+
+// createEncryptedCASecret generates the CA of CloudCore and saves it to the CA secret with the key
+// encrypted by the passphrase, so that the CA key is never stored in plaintext. CloudCore reads
+// the CA from the secret and decrypts the key in memory.
+func createEncryptedCASecret(client kubernetes.Interface, namespace, passphraseFile string) error {
+	data, err := os.ReadFile(passphraseFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the passphrase file %s, err: %v", passphraseFile, err)
+	}
+	passphrase := bytes.TrimRight(data, "\r\n")
+	if len(passphrase) == 0 {
+		return fmt.Errorf("the passphrase file %s is empty", passphraseFile)
+	}
+	if _, err := client.CoreV1().Secrets(namespace).Get(context.Background(), types.CaSecretName, metav1.GetOptions{}); err == nil {
+		return fmt.Errorf("the CA secret %s already exists, it can't be replaced by the encrypted CA", types.CaSecretName)
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the CA secret %s, err: %v", types.CaSecretName, err)
+	}
+
+	h := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	pk, err := h.GenPrivateKey()
+	if err != nil {
+		return err
+	}
+	caPem, err := h.NewSelfSigned(pk)
+	if err != nil {
+		return fmt.Errorf("failed to create Certificate Authority, err: %v", err)
+	}
+	keyPem, err := certs.EncryptPrivateKey(pk.DER(), passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the CA key, err: %v", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: types.CaSecretName, Namespace: namespace},
+		Data: map[string][]byte{
+			types.CaDataName:    caPem.Bytes,
+			types.CaKeyDataName: pem.EncodeToMemory(keyPem),
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+	if _, err := client.CoreV1().Secrets(namespace).Create(context.Background(), secret, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create the CA secret %s, err: %v", types.CaSecretName, err)
+	}
+	fmt.Println("The CA of cloudcore is generated with the encrypted key")
+	return nil
 }
 
 func addHelmValueOptionsFlags(cmd *cobra.Command, initOpts *types.InitOptions) {
@@ -17,13 +17,21 @@ limitations under the License.
 package cloud
 
 import (
+	"context"
+	"encoding/pem"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
+	"github.com/kubeedge/kubeedge/common/constants"
 	types "github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
 )
 
 func TestNewCloudInit(t *testing.T) {
@@ -73,6 +81,10 @@ func TestNewCloudInit(t *testing.T) {
 			types.FlagNameImageRepository,
 			"",
 		},
+		{
+			types.FlagNameCAKeyPassphraseFile,
+			"",
+		},
 		{
 			types.FlagNameSet,
 			"[]",
@@ -143,6 +155,10 @@ func TestAddInitOtherFlags(t *testing.T) {
 			types.FlagNameImageRepository,
 			"",
 		},
+		{
+			types.FlagNameCAKeyPassphraseFile,
+			"",
+		},
 	}
 
 	for _, flag := range expectedFlags {
@@ -185,3 +201,36 @@ func TestAddInit2ToolsList(t *testing.T) {
 	assert.Nil(err)
 	assert.NotNil(toolList["helm"])
 }
+
+func TestCreateEncryptedCASecret(t *testing.T) {
+	assert := assert.New(t)
+	client := fake.NewSimpleClientset()
+
+	passphraseFile := filepath.Join(t.TempDir(), "passphrase")
+	err := createEncryptedCASecret(client, constants.SystemNamespace, passphraseFile)
+	assert.ErrorContains(err, "failed to read the passphrase file")
+	assert.NoError(os.WriteFile(passphraseFile, []byte("\n"), 0600))
+	err = createEncryptedCASecret(client, constants.SystemNamespace, passphraseFile)
+	assert.ErrorContains(err, "is empty")
+
+	assert.NoError(os.WriteFile(passphraseFile, []byte("secret\n"), 0600))
+	assert.NoError(createEncryptedCASecret(client, constants.SystemNamespace, passphraseFile))
+	secret, err := client.CoreV1().Secrets(constants.SystemNamespace).Get(context.TODO(), types.CaSecretName, metav1.GetOptions{})
+	assert.NoError(err)
+	block, _ := pem.Decode(secret.Data[types.CaKeyDataName])
+	assert.NotNil(block)
+	assert.Equal(certs.EncryptedPrivateKeyBlockType, block.Type)
+	_, err = certs.DecryptPrivateKey(block, []byte("wrong"))
+	assert.ErrorIs(err, certs.ErrIncorrectPassphrase)
+	keyDER, err := certs.DecryptPrivateKey(block, []byte("secret"))
+	assert.NoError(err)
+	_, err = certs.PrivateKeySigner(keyDER)
+	assert.NoError(err)
+
+	// the encrypted CA key can't mint the tokens before CloudCore generates the token key
+	_, err = createNodeToken(client, constants.SystemNamespace, "edge-node", 0, false)
+	assert.ErrorContains(err, "the CA key is encrypted")
+
+	err = createEncryptedCASecret(client, constants.SystemNamespace, passphraseFile)
+	assert.ErrorContains(err, "already exists")
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"crypto/x509"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+var (
+	signCSRsLongDescription = `
+"keadm sign-csrs" command signs a directory of edge node CSRs with the CloudCore CA offline,
+which is used for air-gapped sites whose edge nodes can not reach CloudCore to apply for certificates.
+The CSRs are validated by the same rules as CloudCore. A certificate named <nodeName>.crt is written
+for each valid CSR, and a manifest.json recording the serial or the error of each CSR file is written
+to the output directory.
+`
+	signCSRsExample = `
+keadm sign-csrs --csr-dir /tmp/csrs --ca-cert /etc/kubeedge/ca/rootCA.crt --ca-key /etc/kubeedge/ca/rootCA.key --output-dir /tmp/certs
+`
+)
+
+// extKeyUsages maps the usage names of the flag to the extended key usages
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"client": x509.ExtKeyUsageClientAuth,
+	"server": x509.ExtKeyUsageServerAuth,
+}
+
+// NewSignCSRs signs a directory of edge node CSRs offline
+func NewSignCSRs() *cobra.Command {
+	opts := newSignCSRsOptions()
+
+	cmd := &cobra.Command{
+		Use:     "sign-csrs",
+		Short:   "Sign a directory of edge node CSRs offline for air-gapped sites",
+		Long:    signCSRsLongDescription,
+		Example: signCSRsExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return signCSRs(opts)
+		},
+	}
+	addSignCSRsFlags(cmd, opts)
+	return cmd
+}
+
+func addSignCSRsFlags(cmd *cobra.Command, opts *common.SignCSRsOptions) {
+	cmd.Flags().StringVar(&opts.CSRDir, common.FlagNameCSRDir, opts.CSRDir,
+		"The directory of the CSR files, each file contains one PEM or DER encoded CSR")
+	cmd.Flags().StringVar(&opts.OutputDir, common.FlagNameOutputDir, opts.OutputDir,
+		"The directory that the certificates and the manifest are written to")
+	cmd.Flags().StringVar(&opts.CACert, common.FlagNameCACert, opts.CACert,
+		"The path of the PEM encoded CA certificate")
+	cmd.Flags().StringVar(&opts.CAKey, common.FlagNameCAKey, opts.CAKey,
+		"The path of the PEM encoded CA private key")
+	cmd.Flags().IntVar(&opts.Duration, common.FlagNameDuration, opts.Duration,
+		"The validity period of the certificates in days")
+	cmd.Flags().StringSliceVar(&opts.Usages, common.FlagNameUsages, opts.Usages,
+		"The extended key usages of the certificates, supports client and server")
+	cmd.Flags().StringSliceVar(&opts.SignatureAlgorithms, common.FlagNameSignatureAlgorithms, opts.SignatureAlgorithms,
+		"The allowed signature algorithms of the CSRs, eg: ECDSA-SHA256,SHA256-RSA. Defaults to the algorithms allowed by CloudCore")
+	for _, name := range []string{common.FlagNameCSRDir, common.FlagNameCACert, common.FlagNameCAKey} {
+		_ = cmd.MarkFlagRequired(name)
+	}
+}
+
+// newSignCSRsOptions return common options
+func newSignCSRsOptions() *common.SignCSRsOptions {
+	return &common.SignCSRsOptions{
+		OutputDir: ".",
+		Duration:  int(certs.DefaultEdgeCertDuration / (24 * time.Hour)),
+		Usages:    []string{"client"},
+	}
+}
+
+func signCSRs(opts *common.SignCSRsOptions) error {
+	usages := make([]x509.ExtKeyUsage, 0, len(opts.Usages))
+	for _, name := range opts.Usages {
+		u, ok := extKeyUsages[name]
+		if !ok {
+			return fmt.Errorf("unsupported usage %q, supports client and server", name)
+		}
+		usages = append(usages, u)
+	}
+	manifest, err := certs.SignCSRBatch(certs.BatchSignOptions{
+		CSRDir:              opts.CSRDir,
+		OutputDir:           opts.OutputDir,
+		CACertFile:          opts.CACert,
+		CAKeyFile:           opts.CAKey,
+		Usages:              usages,
+		Duration:            time.Duration(opts.Duration) * 24 * time.Hour,
+		SignatureAlgorithms: opts.SignatureAlgorithms,
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range manifest.Entries {
+		if e.Error != "" {
+			fmt.Printf("failed to sign %s, err: %s\n", e.CSRFile, e.Error)
+			continue
+		}
+		fmt.Printf("signed %s for node %s, serial: %s\n", e.CSRFile, e.NodeName, e.Serial)
+	}
+	fmt.Printf("%d signed, %d failed, manifest is written to %s\n", len(manifest.Entries)-manifest.Failed(),
+		manifest.Failed(), filepath.Join(opts.OutputDir, certs.BatchManifestFileName))
+	if manifest.Failed() > 0 {
+		return fmt.Errorf("failed to sign %d CSR files", manifest.Failed())
+	}
+	return nil
+}
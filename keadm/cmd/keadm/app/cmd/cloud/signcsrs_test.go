@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestNewSignCSRs(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := NewSignCSRs()
+	assert.Equal("sign-csrs", cmd.Use)
+	assert.NotNil(cmd.RunE)
+
+	flag := cmd.Flags().Lookup(common.FlagNameDuration)
+	assert.NotNil(flag)
+	assert.Equal("365", flag.DefValue)
+	flag = cmd.Flags().Lookup(common.FlagNameUsages)
+	assert.NotNil(flag)
+	assert.Equal("[client]", flag.DefValue)
+}
+
+func TestSignCSRs(t *testing.T) {
+	dir := t.TempDir()
+	csrDir := filepath.Join(dir, "csrs")
+	assert.NoError(t, os.MkdirAll(csrDir, 0755))
+
+	cah := certs.GetCAHandler(certs.CAHandlerTypeX509)
+	caKey, err := cah.GenPrivateKey()
+	assert.NoError(t, err)
+	ca, err := cah.NewSelfSigned(caKey)
+	assert.NoError(t, err)
+	opts := newSignCSRsOptions()
+	opts.CSRDir = csrDir
+	opts.OutputDir = filepath.Join(dir, "out")
+	opts.CACert, opts.CAKey = filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	_, err = certs.WriteDERToPEMFile(opts.CACert, "CERTIFICATE", ca.Bytes)
+	assert.NoError(t, err)
+	_, err = certs.WriteDERToPEMFile(opts.CAKey, keyutil.ECPrivateKeyBlockType, caKey.DER())
+	assert.NoError(t, err)
+
+	h := certs.GetHandler(certs.HandlerTypeX509)
+	pk, err := h.GenPrivateKey()
+	assert.NoError(t, err)
+	csr, err := h.CreateCSR(pkix.Name{
+		CommonName:   "system:node:node1",
+		Organization: []string{"system:nodes"},
+	}, pk, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(csrDir, "node1.csr"), pem.EncodeToMemory(csr), 0644))
+
+	opts.Usages = []string{"client", "server"}
+	assert.NoError(t, signCSRs(opts))
+	_, err = os.Stat(filepath.Join(opts.OutputDir, "node1.crt"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(csrDir, "invalid.csr"), []byte("invalid"), 0644))
+	assert.ErrorContains(t, signCSRs(opts), "failed to sign 1 CSR files")
+
+	opts.Usages = []string{"codesigning"}
+	assert.ErrorContains(t, signCSRs(opts), `unsupported usage "codesigning"`)
+}
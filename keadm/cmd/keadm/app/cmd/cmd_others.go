@@ -77,6 +77,7 @@ func NewKubeedgeCommand() *cobra.Command {
 
 	cmds.AddCommand(NewCmdVersion())
 	cmds.AddCommand(cloud.NewGettoken())
+	cmds.AddCommand(cloud.NewSignCSRs())
 	cmds.AddCommand(debug.NewEdgeDebug())
 
 	// recommended cmds
@@ -36,6 +36,15 @@ const (
 	// FlagNameKubeConfig sets the path of kubeconfig
 	FlagNameKubeConfig = "kube-config"
 
+	// FlagNameNodeName sets the name of the edge node that the token enrolls
+	FlagNameNodeName = "node-name"
+
+	// FlagNameTTL sets the time to live of the token
+	FlagNameTTL = "ttl"
+
+	// FlagNameStateless sets whether the token of the node is not recorded
+	FlagNameStateless = "stateless"
+
 	// FlagNameAdvertiseAddress ...
 	FlagNameAdvertiseAddress = "advertise-address"
 
@@ -63,6 +72,9 @@ const (
 	// FlagNameSkipCRDs skip CRDs
 	FlagNameSkipCRDs = "skip-crds"
 
+	// FlagNameCAKeyPassphraseFile sets the passphrase file which the CA key generated by keadm is encrypted with
+	FlagNameCAKeyPassphraseFile = "ca-key-passphrase-file"
+
 	// FlagNameMaster sets the address of K8s master
 	// Deprecated: only used in deprecated/init.go
 	FlagNameMaster = "master"
@@ -74,6 +86,30 @@ const (
 	FlagNameFiles = "files"
 )
 
+// Sign CSRs flag names
+const (
+	// FlagNameCSRDir sets the directory of the CSR files to sign
+	FlagNameCSRDir = "csr-dir"
+
+	// FlagNameOutputDir sets the directory that the certificates and the manifest are written to
+	FlagNameOutputDir = "output-dir"
+
+	// FlagNameCACert sets the path of the CA certificate
+	FlagNameCACert = "ca-cert"
+
+	// FlagNameCAKey sets the path of the CA private key
+	FlagNameCAKey = "ca-key"
+
+	// FlagNameDuration sets the validity period of the certificates in days
+	FlagNameDuration = "duration"
+
+	// FlagNameUsages sets the extended key usages of the certificates
+	FlagNameUsages = "usages"
+
+	// FlagNameSignatureAlgorithms sets the allowed signature algorithms of the CSRs
+	FlagNameSignatureAlgorithms = "signature-algorithms"
+)
+
 // Cloud upgrade flag names
 const (
 	// FlagNameReuseValues ...
@@ -136,6 +172,12 @@ const (
 	TokenSecretName = "tokensecret"
 	TokenDataName   = "tokendata"
 
+	// CA secret, which is used to mint the per-node tokens
+	CaSecretName     = "casecret"
+	CaDataName       = "cadata"
+	CaKeyDataName    = "cakeydata"
+	TokenKeyDataName = "tokenkeydata"
+
 	StrCheck    = "check"
 	StrDiagnose = "diagnose"
 
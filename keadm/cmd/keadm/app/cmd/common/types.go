@@ -19,6 +19,7 @@ package common
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 )
@@ -71,8 +72,9 @@ func (b CloudInitUpdateBase) HasSets(key string) bool {
 
 // InitOptions defines cloud init flags
 type InitOptions struct {
-	Manifests string
-	SkipCRDs  bool
+	Manifests           string
+	SkipCRDs            bool
+	CAKeyPassphraseFile string
 	CloudInitUpdateBase
 }
 
@@ -135,6 +137,24 @@ type ResetOptions struct {
 
 type GettokenOptions struct {
 	Kubeconfig string
+	// NodeName is the name of the edge node that the per-node token enrolls,
+	// the global token is printed if it is empty
+	NodeName string
+	TTL      time.Duration
+	// Stateless indicates that the token of the node is not recorded, so it only carries
+	// the node name claim and can be used until it expires
+	Stateless bool
+}
+
+// SignCSRsOptions has the offline CSR signing information filled by CLI
+type SignCSRsOptions struct {
+	CSRDir              string
+	OutputDir           string
+	CACert              string
+	CAKey               string
+	Duration            int
+	Usages              []string
+	SignatureAlgorithms []string
 }
 
 type DiagnoseOptions struct {
@@ -17,10 +17,9 @@ limitations under the License.
 package edge
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"net"
-	"net/http"
 	"os"
 	"strings"
 
@@ -32,6 +31,7 @@ import (
 	apiutil "github.com/kubeedge/api/apis/util"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/common"
 	"github.com/kubeedge/kubeedge/keadm/cmd/keadm/app/cmd/util"
+	"github.com/kubeedge/kubeedge/pkg/security/certclient"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 )
 
@@ -191,32 +191,26 @@ func isNodeExist(opt *common.JoinOptions) error {
 		nodeName = apiutil.GetHostname()
 	}
 
-	queryPara := fmt.Sprintf("/node/%s", nodeName)
 	host, _, err := net.SplitHostPort(opt.CloudCoreIPPort)
 	if err != nil {
 		return errors.Errorf("get current host and port failed: %v", err)
 	}
 
-	var url string
+	certPort := "10002"
 	if opt.CertPort != "" {
-		url = "https://" + net.JoinHostPort(host, opt.CertPort) + queryPara
-	} else {
-		url = "https://" + net.JoinHostPort(host, "10002") + queryPara
+		certPort = opt.CertPort
 	}
-
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	cli, err := certclient.New("https://"+net.JoinHostPort(host, certPort), nodeName)
+	if err != nil {
+		return errors.Errorf("failed to create the client of cloudcore: %v", err)
 	}
 
-	resp, err := client.Get(url)
-	if err != nil {
+	exists, err := cli.CheckNode(context.Background())
+	var respErr *certclient.Error
+	if err != nil && !errors.As(err, &respErr) {
 		return errors.Errorf("error making request: %v", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
+	if err == nil && !exists {
 		return nil
 	}
 
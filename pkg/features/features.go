@@ -39,6 +39,19 @@ const (
 	DisableNodeTaskV1alpha2 featuregate.Feature = "disableNodeTaskV1alpha2"
 	// DisableCSIVolumePlugin disables the in-tree CSI volume plugin support.
 	DisableCSIVolumePlugin featuregate.Feature = "DisableCSIVolumePlugin"
+
+	// LegacyBootstrapToken allows the edge nodes to enroll with the cluster-wide token, which can
+	// enroll any node name until it is refreshed. The per-node tokens minted by keadm gettoken
+	// --node-name are always accepted.
+	// TODO: remove the global token in the next release.
+	// deprecated: v1.22
+	LegacyBootstrapToken featuregate.Feature = "legacyBootstrapToken"
+
+	// CloudHubSessionRegistry allows running multiple CloudHub instances behind a load balancer.
+	// Each instance records the edge nodes connected to it in the session leases, and forwards
+	// the downstream messages of the nodes connected to other instances to those instances.
+	// alpha: v1.22
+	CloudHubSessionRegistry featuregate.Feature = "cloudHubSessionRegistry"
 )
 
 // defaultFeatureGates consists of all known Kubeedge-specific feature keys.
@@ -48,4 +61,6 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	RequireAuthorization:    {Default: false, PreRelease: featuregate.Alpha},
 	ModuleRestart:           {Default: false, PreRelease: featuregate.Alpha},
 	DisableNodeTaskV1alpha2: {Default: false, PreRelease: featuregate.Alpha},
+	LegacyBootstrapToken:    {Default: true, PreRelease: featuregate.Deprecated},
+	CloudHubSessionRegistry: {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -0,0 +1,354 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certclient is the client of the certificate enrollment API of CloudHub, which
+// serves the CA and signs the edge certificates over HTTPS.
+package certclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/kubeedge/common/constants"
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+const (
+	// DefaultTimeout is the timeout of a request if WithTimeout is not set, it is longer than
+	// the default signing timeout of CloudHub so that the timeout is responded by CloudHub.
+	DefaultTimeout = 60 * time.Second
+
+	connectTimeout   = 30 * time.Second
+	keepaliveTimeout = 30 * time.Second
+)
+
+// Client requests the CA and the certificates of an edge node from CloudHub.
+type Client struct {
+	server         string
+	nodeName       string
+	nodeNameHeader string
+	token          string
+	clientCert     *tls.Certificate
+	caPEM          []byte
+	timeout        time.Duration
+	manifest       bool
+	chain          bool
+	httpClient     *http.Client
+}
+
+// Option configures the Client.
+type Option func(*Client)
+
+// WithToken authenticates the signing requests with the token, which is a bootstrap token
+// without the CA hash or a ServiceAccount token of delegated signing.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithClientCert presents the certificate of the edge node in the TLS handshake,
+// it is required to renew the certificate.
+func WithClientCert(cert tls.Certificate) Option {
+	return func(c *Client) {
+		c.clientCert = &cert
+	}
+}
+
+// WithCA verifies the certificate of CloudHub by the PEM encoded CA. The certificate of
+// CloudHub is not verified without it, which is only for getting the CA before it is
+// verified by the hash of the token.
+func WithCA(caPEM []byte) Option {
+	return func(c *Client) {
+		c.caPEM = caPEM
+	}
+}
+
+// WithTimeout sets the timeout of each request, DefaultTimeout is used if it is not positive.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithManifest requests the certificates along with the provisioning manifests.
+func WithManifest() Option {
+	return func(c *Client) {
+		c.manifest = true
+	}
+}
+
+// WithChain requests the certificates along with the intermediate CAs which chain them to the root CA.
+// The older CloudHub which does not return the chain responds the certificates only.
+func WithChain() Option {
+	return func(c *Client) {
+		c.chain = true
+	}
+}
+
+// WithNodeNameHeader sends the node name in the header instead of NodeName, which must match
+// the header CloudHub is configured to read.
+func WithNodeNameHeader(header string) Option {
+	return func(c *Client) {
+		c.nodeNameHeader = header
+	}
+}
+
+// New creates a Client of the CloudHub HTTPS server, such as https://10.0.0.1:10002,
+// for the edge node.
+func New(server, nodeName string, opts ...Option) (*Client, error) {
+	c := &Client{
+		server:   strings.TrimSuffix(server, "/"),
+		nodeName: nodeName,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.timeout <= 0 {
+		c.timeout = DefaultTimeout
+	}
+	if c.nodeNameHeader == "" {
+		c.nodeNameHeader = types.HeaderNodeName
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.caPEM != nil {
+		pool := x509.NewCertPool()
+		if ok := pool.AppendCertsFromPEM(c.caPEM); !ok {
+			return nil, errors.New("cannot parse the CA certificates")
+		}
+		tlsConfig.RootCAs = pool
+	} else {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if c.clientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*c.clientCert}
+	}
+	c.httpClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   connectTimeout,
+				KeepAlive: keepaliveTimeout,
+			}).DialContext,
+			TLSClientConfig: tlsConfig,
+		},
+		Timeout: c.timeout,
+	}
+	return c, nil
+}
+
+// CSRRequest is the request of signing an edge certificate.
+type CSRRequest struct {
+	// CSR is the certificate request of the edge node in DER or PEM.
+	CSR []byte
+	// Usages are the extended key usages of the certificate, CloudHub defaults to client auth if it is empty.
+	Usages []x509.ExtKeyUsage
+	// Issuer is the name of the issuer to sign the certificate, CloudHub selects the issuer
+	// by the node group of the node if it is empty.
+	Issuer string
+	// Duration is the desired validity period of the certificate, which is rounded up to hours.
+	// CloudHub clamps it to its MaxEdgeCertSigningDuration, and uses its own duration if it is 0.
+	Duration time.Duration
+}
+
+// IssuedCert is the certificate signed by CloudHub.
+type IssuedCert struct {
+	// Certificate is the certificate in DER.
+	Certificate []byte
+	// Chain is the intermediate CA certificates in DER starting with the CA that issued the certificate,
+	// it is only set if the Client is created WithChain or WithManifest and the CA is not a root CA.
+	Chain [][]byte
+	// Manifest is the provisioning manifest, it is only set if the Client is created WithManifest.
+	Manifest *types.CertManifest
+	// Warnings are the non-fatal warnings of the request returned by CloudHub, which should be
+	// logged for the operators.
+	Warnings []string
+}
+
+// GetCA returns the CA of CloudHub in DER.
+func (c *Client) GetCA(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+constants.DefaultCAURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, _, err := c.do(req)
+	return body, err
+}
+
+// GetCABundle returns the CA bundle of CloudHub in DER, which starts with the CA returned by GetCA
+// and is followed by the other trusted CAs, e.g. the offline root CA and the previous CA of a
+// rotation. The older CloudHub which does not serve the bundle responds the CA only.
+func (c *Client) GetCABundle(ctx context.Context) ([][]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+constants.DefaultCAURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", types.MIMEPEMFile)
+	body, header, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType != types.MIMEPEMFile {
+		return [][]byte{body}, nil
+	}
+	bundle, err := parseCertChain(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the CA bundle, err: %v", err)
+	}
+	return bundle, nil
+}
+
+// SignCert requests a certificate of the edge node, the request is authenticated by the token
+// of the Client. It is authenticated by the pre-registration of the node if no token is set.
+func (c *Client) SignCert(ctx context.Context, csr CSRRequest) (*IssuedCert, error) {
+	return c.sign(ctx, csr, c.token)
+}
+
+// Renew requests a new certificate of the edge node, the request is authenticated by
+// the current certificate of the node, see WithClientCert.
+func (c *Client) Renew(ctx context.Context, csr CSRRequest) (*IssuedCert, error) {
+	if c.clientCert == nil {
+		return nil, errors.New("the client certificate is required to renew the certificate")
+	}
+	return c.sign(ctx, csr, "")
+}
+
+// CheckNode reports whether the node exists in the cluster.
+func (c *Client) CheckNode(ctx context.Context) (bool, error) {
+	url := c.server + strings.Replace(constants.DefaultCheckNodeURL, "{nodename}", c.nodeName, 1)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	if _, _, err := c.do(req); err != nil {
+		var e *Error
+		if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Client) sign(ctx context.Context, csr CSRRequest, token string) (*IssuedCert, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+constants.DefaultCertURL,
+		bytes.NewReader(csr.CSR))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(c.nodeNameHeader, c.nodeName)
+	if token != "" {
+		req.Header.Set(types.HeaderAuthorization, "Bearer "+token)
+	}
+	if len(csr.Usages) > 0 {
+		usages, err := json.Marshal(csr.Usages)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the ExtKeyUsages, err: %v", err)
+		}
+		req.Header.Set(types.HeaderExtKeyUsages, string(usages))
+	}
+	if csr.Issuer != "" {
+		req.Header.Set(types.HeaderCertIssuer, csr.Issuer)
+	}
+	if csr.Duration > 0 {
+		hours := (csr.Duration + time.Hour - 1) / time.Hour
+		req.Header.Set(types.HeaderCertDuration, strconv.FormatInt(int64(hours), 10))
+	}
+	switch {
+	case c.manifest:
+		req.Header.Set("Accept", types.MIMECertManifest)
+	case c.chain:
+		req.Header.Set("Accept", types.MIMEPEMCertChain)
+	}
+
+	body, header, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for _, h := range header.Values(types.HeaderWarning) {
+		if warning, ok := types.ParseWarning(h); ok {
+			warnings = append(warnings, warning)
+		}
+	}
+	if c.manifest {
+		var resp types.CertResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the provisioning manifest, err: %v", err)
+		}
+		return &IssuedCert{Certificate: resp.Certificate, Chain: resp.Chain, Manifest: &resp.Manifest, Warnings: warnings}, nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType != types.MIMEPEMCertChain {
+		return &IssuedCert{Certificate: body, Warnings: warnings}, nil
+	}
+	chain, err := parseCertChain(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the certificate chain, err: %v", err)
+	}
+	return &IssuedCert{Certificate: chain[0], Chain: chain[1:], Warnings: warnings}, nil
+}
+
+// parseCertChain returns the DER of the certificates in the PEM chain.
+func parseCertChain(data []byte) ([][]byte, error) {
+	var chain [][]byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		chain = append(chain, block.Bytes)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("no certificate is found")
+	}
+	return chain, nil
+}
+
+// do sends the request and returns the body and the header of the response, an *Error
+// is returned if the response is not OK.
+func (c *Client) do(req *http.Request) ([]byte, http.Header, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to request the cloudcore server, err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, constants.MaxRespBodyLength))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body, err: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, newError(resp, body)
+	}
+	return body, resp.Header, nil
+}
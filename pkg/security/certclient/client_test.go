@@ -0,0 +1,322 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certclient
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+func TestSignCertRequest(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/edge.crt", r.URL.Path)
+		require.Equal(t, "testnode", r.Header.Get(types.HeaderNodeName))
+		require.Equal(t, "Bearer token", r.Header.Get(types.HeaderAuthorization))
+		require.Equal(t, "[2,1]", r.Header.Get(types.HeaderExtKeyUsages))
+		require.Equal(t, "tenant-a", r.Header.Get(types.HeaderCertIssuer))
+		// the duration is rounded up to hours
+		require.Equal(t, "721", r.Header.Get(types.HeaderCertDuration))
+		require.Empty(t, r.Header.Get("Accept"))
+		w.Header().Add(types.HeaderWarning, types.FormatWarning(`the "server" usage is deprecated`))
+		// the warnings of other codes are ignored
+		w.Header().Add(types.HeaderWarning, `199 - "miscellaneous warning"`)
+		_, _ = w.Write([]byte("cert"))
+	}))
+	defer srv.Close()
+
+	cli, err := New(srv.URL+"/", "testnode", WithToken("token"))
+	require.NoError(t, err)
+	issued, err := cli.SignCert(context.TODO(), CSRRequest{
+		CSR:      []byte("csr"),
+		Usages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		Issuer:   "tenant-a",
+		Duration: 30*24*time.Hour + time.Minute,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte("cert"), issued.Certificate)
+	require.Nil(t, issued.Manifest)
+	require.Equal(t, []string{`the "server" usage is deprecated`}, issued.Warnings)
+}
+
+func TestSignCertWithNodeNameHeader(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "testnode", r.Header.Get("X-Edge-Node"))
+		require.Empty(t, r.Header.Get(types.HeaderNodeName))
+		_, _ = w.Write([]byte("cert"))
+	}))
+	defer srv.Close()
+
+	cli, err := New(srv.URL, "testnode", WithToken("token"), WithNodeNameHeader("X-Edge-Node"))
+	require.NoError(t, err)
+	issued, err := cli.SignCert(context.TODO(), CSRRequest{CSR: []byte("csr")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("cert"), issued.Certificate)
+}
+
+func TestSignCertChain(t *testing.T) {
+	chainPEM := "-----BEGIN CERTIFICATE-----\nbGVhZg==\n-----END CERTIFICATE-----\n" +
+		"-----BEGIN CERTIFICATE-----\nY2E=\n-----END CERTIFICATE-----\n"
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		want        *IssuedCert
+		wantErr     string
+	}{
+		{
+			name:        "chain",
+			contentType: types.MIMEPEMCertChain,
+			body:        chainPEM,
+			want:        &IssuedCert{Certificate: []byte("leaf"), Chain: [][]byte{[]byte("ca")}},
+		},
+		{
+			name: "older CloudHub",
+			body: "cert",
+			want: &IssuedCert{Certificate: []byte("cert")},
+		},
+		{
+			name:        "invalid chain",
+			contentType: types.MIMEPEMCertChain,
+			body:        "cert",
+			wantErr:     "no certificate is found",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, types.MIMEPEMCertChain, r.Header.Get("Accept"))
+				if c.contentType != "" {
+					w.Header().Set("Content-Type", c.contentType)
+				}
+				_, _ = w.Write([]byte(c.body))
+			}))
+			defer srv.Close()
+
+			cli, err := New(srv.URL, "testnode", WithToken("token"), WithChain())
+			require.NoError(t, err)
+			issued, err := cli.SignCert(context.TODO(), CSRRequest{CSR: []byte("csr")})
+			if c.wantErr != "" {
+				require.ErrorContains(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, issued)
+		})
+	}
+}
+
+func TestGetCABundle(t *testing.T) {
+	bundlePEM := "-----BEGIN CERTIFICATE-----\nY2E=\n-----END CERTIFICATE-----\n" +
+		"-----BEGIN CERTIFICATE-----\ncm9vdA==\n-----END CERTIFICATE-----\n"
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		want        [][]byte
+		wantErr     string
+	}{
+		{
+			name:        "bundle",
+			contentType: types.MIMEPEMFile,
+			body:        bundlePEM,
+			want:        [][]byte{[]byte("ca"), []byte("root")},
+		},
+		{
+			name: "older CloudHub",
+			body: "ca",
+			want: [][]byte{[]byte("ca")},
+		},
+		{
+			name:        "invalid bundle",
+			contentType: types.MIMEPEMFile,
+			body:        "ca",
+			wantErr:     "no certificate is found",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/ca.crt", r.URL.Path)
+				require.Equal(t, types.MIMEPEMFile, r.Header.Get("Accept"))
+				if c.contentType != "" {
+					w.Header().Set("Content-Type", c.contentType)
+				}
+				_, _ = w.Write([]byte(c.body))
+			}))
+			defer srv.Close()
+
+			cli, err := New(srv.URL, "")
+			require.NoError(t, err)
+			bundle, err := cli.GetCABundle(context.TODO())
+			if c.wantErr != "" {
+				require.ErrorContains(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, bundle)
+		})
+	}
+}
+
+func TestCheckNode(t *testing.T) {
+	codes := map[string]int{
+		"/node/exists":  http.StatusOK,
+		"/node/missing": http.StatusNotFound,
+		"/node/broken":  http.StatusInternalServerError,
+	}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(codes[r.URL.Path])
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		nodeName   string
+		wantExists bool
+		wantErr    bool
+	}{
+		{nodeName: "exists", wantExists: true},
+		{nodeName: "missing", wantExists: false},
+		{nodeName: "broken", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.nodeName, func(t *testing.T) {
+			cli, err := New(srv.URL, c.nodeName)
+			require.NoError(t, err)
+			exists, err := cli.CheckNode(context.TODO())
+			require.Equal(t, c.wantErr, err != nil, err)
+			require.Equal(t, c.wantExists, exists)
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	cases := []struct {
+		name           string
+		code           int
+		retryAfter     string
+		contentType    string
+		message        string
+		wantMessage    string
+		wantReason     string
+		wantRetryAfter time.Duration
+		wantTemporary  bool
+	}{
+		{
+			name:          "quota exceeded",
+			code:          http.StatusTooManyRequests,
+			message:       types.ReasonQuotaExceeded + ": the tenant tenant-a has reached its quota of 1 certificates",
+			wantReason:    types.ReasonQuotaExceeded,
+			wantTemporary: true,
+		},
+		{
+			name:       "duplicate enrollment",
+			code:       http.StatusConflict,
+			message:    types.ReasonDuplicateEnrollment + ": the node testnode is enrolled with another key",
+			wantReason: types.ReasonDuplicateEnrollment,
+		},
+		{
+			name:       "token consumed",
+			code:       http.StatusUnauthorized,
+			message:    types.ReasonTokenConsumed + ": token validation failure, the token abc of edgenode testnode is consumed",
+			wantReason: types.ReasonTokenConsumed,
+		},
+		{
+			name:           "signing queue is full",
+			code:           http.StatusServiceUnavailable,
+			retryAfter:     "5",
+			message:        "failed to sign certs for edgenode testnode, err: the signing queue is full",
+			wantRetryAfter: 5 * time.Second,
+			wantTemporary:  true,
+		},
+		{
+			name:       "token expired",
+			code:       http.StatusUnauthorized,
+			message:    types.ReasonTokenExpired + ": token validation failure, err: token has invalid claims: token is expired",
+			wantReason: types.ReasonTokenExpired,
+		},
+		{
+			name:        "problem details",
+			code:        http.StatusBadRequest,
+			contentType: "application/problem+json",
+			message: `{"type":"about:blank","title":"Bad Request","status":400,` +
+				`"detail":"CSRInvalid: invalid CSR, err: asn1: syntax error","code":"CSRInvalid"}`,
+			wantMessage: "CSRInvalid: invalid CSR, err: asn1: syntax error",
+			wantReason:  types.ReasonCSRInvalid,
+		},
+		{
+			name:        "problem details with an unknown code",
+			code:        http.StatusBadRequest,
+			contentType: "application/problem+json; charset=utf-8",
+			message:     `{"status":400,"detail":"Unknown: fake","code":"Unknown"}`,
+			wantMessage: "Unknown: fake",
+		},
+		{
+			name:       "subject mismatch",
+			code:       http.StatusBadRequest,
+			message:    types.ReasonSubjectMismatch + ": the CommonName of the CSR is for node \"a\", not \"b\"",
+			wantReason: types.ReasonSubjectMismatch,
+		},
+		{
+			name:    "reason code not at the beginning",
+			code:    http.StatusBadRequest,
+			message: "invalid CSR, err: " + types.ReasonSigningTimeout + ": fake",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if c.retryAfter != "" {
+					w.Header().Set("Retry-After", c.retryAfter)
+				}
+				if c.contentType != "" {
+					w.Header().Set("Content-Type", c.contentType)
+				}
+				w.WriteHeader(c.code)
+				_, _ = w.Write([]byte(c.message))
+			}))
+			defer srv.Close()
+
+			cli, err := New(srv.URL, "testnode")
+			require.NoError(t, err)
+			_, err = cli.SignCert(context.TODO(), CSRRequest{CSR: []byte("csr")})
+			var respErr *Error
+			require.ErrorAs(t, err, &respErr)
+			require.Equal(t, c.code, respErr.StatusCode)
+			wantMessage := c.message
+			if c.wantMessage != "" {
+				wantMessage = c.wantMessage
+			}
+			require.Equal(t, wantMessage, respErr.Message)
+			require.Equal(t, c.wantReason, ReasonOf(err))
+			require.Equal(t, c.wantRetryAfter, respErr.RetryAfter)
+			require.Equal(t, c.wantTemporary, respErr.Temporary())
+			require.Equal(t, c.wantReason == types.ReasonQuotaExceeded, IsQuotaExceeded(err))
+			require.Equal(t, c.wantReason == types.ReasonDuplicateEnrollment, IsDuplicateEnrollment(err))
+			require.Equal(t, c.wantReason == types.ReasonTokenExpired, IsTokenExpired(err))
+			require.Equal(t, c.wantReason == types.ReasonCSRInvalid, IsCSRInvalid(err))
+			require.Equal(t, c.wantReason == types.ReasonSubjectMismatch, IsSubjectMismatch(err))
+		})
+	}
+}
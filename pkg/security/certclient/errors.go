@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubeedge/kubeedge/common/types"
+)
+
+// reasons are the reason codes that CloudHub prefixes the error messages with.
+var reasons = []string{
+	types.ReasonQuotaExceeded,
+	types.ReasonDuplicateEnrollment,
+	types.ReasonSigningTimeout,
+	types.ReasonSigningFrozen,
+	types.ReasonRateLimited,
+	types.ReasonNodeNotApproved,
+	types.ReasonTokenExpired,
+	types.ReasonTokenMalformed,
+	types.ReasonTokenInvalidSignature,
+	types.ReasonTokenConsumed,
+	types.ReasonCSRInvalid,
+	types.ReasonSubjectMismatch,
+	types.ReasonCertInvalid,
+	types.ReasonSignerUnavailable,
+}
+
+// mimeProblemJSON is the media type of the problem details of the error responses, which
+// CloudHub responds to the clients accepting it.
+const mimeProblemJSON = "application/problem+json"
+
+// problem is the part of the problem details used by the client.
+type problem struct {
+	Detail string `json:"detail"`
+	Code   string `json:"code"`
+}
+
+// Error is an error response of CloudHub.
+type Error struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Reason is the reason code of the error, such as types.ReasonQuotaExceeded, which is
+	// the code of the problem details or the prefix of the message. It is empty if the
+	// reason code is unknown.
+	Reason string
+	// Message is the error message of the response.
+	Message string
+	// RetryAfter is the delay of the Retry-After header, it is 0 if the header is absent.
+	RetryAfter time.Duration
+}
+
+func newError(resp *http.Response, body []byte) *Error {
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		Message:    string(body),
+	}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && mediaType == mimeProblemJSON {
+		var p problem
+		if err := json.Unmarshal(body, &p); err == nil {
+			e.Message = p.Detail
+			if slices.Contains(reasons, p.Code) {
+				e.Reason = p.Code
+			}
+		}
+	}
+	for _, reason := range reasons {
+		if e.Reason == "" && strings.HasPrefix(e.Message, reason+":") {
+			e.Reason = reason
+		}
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		e.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("failed to call http, code: %d, message: %s", e.StatusCode, e.Message)
+}
+
+// Temporary reports whether the request may succeed if it is retried later.
+func (e *Error) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return e.RetryAfter > 0
+}
+
+// ReasonOf returns the reason code of the err if it is an *Error, otherwise it returns "".
+func ReasonOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Reason
+	}
+	return ""
+}
+
+// IsQuotaExceeded reports whether the err is caused by the issuance quota of the tenant.
+func IsQuotaExceeded(err error) bool {
+	return ReasonOf(err) == types.ReasonQuotaExceeded
+}
+
+// IsDuplicateEnrollment reports whether the err is caused by an active session of the
+// node name with a different key.
+func IsDuplicateEnrollment(err error) bool {
+	return ReasonOf(err) == types.ReasonDuplicateEnrollment
+}
+
+// IsSigningTimeout reports whether the err is caused by the signing timeout of CloudHub.
+func IsSigningTimeout(err error) bool {
+	return ReasonOf(err) == types.ReasonSigningTimeout
+}
+
+// IsSigningFrozen reports whether the err is caused by the emergency freeze of the signing.
+func IsSigningFrozen(err error) bool {
+	return ReasonOf(err) == types.ReasonSigningFrozen
+}
+
+// IsRateLimited reports whether the err is caused by the signing rate limit of the node.
+func IsRateLimited(err error) bool {
+	return ReasonOf(err) == types.ReasonRateLimited
+}
+
+// IsNodeNotApproved reports whether the err is caused by the node name not being approved.
+func IsNodeNotApproved(err error) bool {
+	return ReasonOf(err) == types.ReasonNodeNotApproved
+}
+
+// IsCSRInvalid reports whether the err is caused by a CSR which cannot be parsed or breaks
+// the signing policies of CloudHub.
+func IsCSRInvalid(err error) bool {
+	return ReasonOf(err) == types.ReasonCSRInvalid
+}
+
+// IsSubjectMismatch reports whether the err is caused by the subject of the CSR or the
+// certificate not belonging to the node.
+func IsSubjectMismatch(err error) bool {
+	return ReasonOf(err) == types.ReasonSubjectMismatch
+}
+
+// IsTokenExpired reports whether the err is caused by the expired token of the request,
+// which may be replaced by a fresh token of the node.
+func IsTokenExpired(err error) bool {
+	return ReasonOf(err) == types.ReasonTokenExpired
+}
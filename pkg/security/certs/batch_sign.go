@@ -0,0 +1,191 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// BatchManifestFileName is the name of the manifest file written to the output directory.
+const BatchManifestFileName = "manifest.json"
+
+// BatchSignOptions is the options of signing a directory of edge CSRs offline,
+// which is used for air-gapped sites that can not reach CloudHub.
+type BatchSignOptions struct {
+	// CSRDir is the directory of the CSR files, each file contains one PEM or DER encoded CSR.
+	CSRDir string
+	// OutputDir is the directory that the certificates and the manifest are written to.
+	OutputDir string
+	// CACertFile and CAKeyFile are the PEM files of the CA certificate and its EC private key.
+	CACertFile string
+	CAKeyFile  string
+	// Usages are the extended key usages of the certificates, it defaults to client auth.
+	Usages []x509.ExtKeyUsage
+	// Duration is the validity period of the certificates, it is clamped by ClampEdgeCertDuration.
+	Duration time.Duration
+	// SignatureAlgorithms are the allowed signature algorithms of the CSRs,
+	// DefaultCSRSignatureAlgorithms are used if it is empty.
+	SignatureAlgorithms []string
+}
+
+// BatchManifest records the result of signing each CSR file of a batch.
+type BatchManifest struct {
+	Entries []BatchManifestEntry `json:"entries"`
+}
+
+// BatchManifestEntry is the result of signing a CSR file, Error is set if the file failed.
+type BatchManifestEntry struct {
+	CSRFile  string     `json:"csrFile"`
+	NodeName string     `json:"nodeName,omitempty"`
+	CertFile string     `json:"certFile,omitempty"`
+	Serial   string     `json:"serial,omitempty"`
+	NotAfter *time.Time `json:"notAfter,omitempty"`
+	// SignatureAlgorithm is the signature algorithm of the certificate selected for the CA key.
+	SignatureAlgorithm string `json:"signatureAlgorithm,omitempty"`
+	Error              string `json:"error,omitempty"`
+}
+
+// Failed returns the number of CSR files that failed to be signed.
+func (m *BatchManifest) Failed() int {
+	var n int
+	for _, e := range m.Entries {
+		if e.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// SignCSRBatch signs every CSR file in the CSRDir with the CA, and writes the certificates
+// as <nodeName>.crt and the manifest to the OutputDir. The CSRs are validated by the same
+// rules as CloudHub. A CSR file that fails is recorded in the manifest and does not abort
+// the batch, an error is only returned if the batch can not be processed at all.
+func SignCSRBatch(opts BatchSignOptions) (*BatchManifest, error) {
+	caBlock, err := ReadPEMFile(opts.CACertFile)
+	if err != nil || caBlock == nil {
+		return nil, fmt.Errorf("failed to read the CA certificate %s, err: %v", opts.CACertFile, err)
+	}
+	if _, err := x509.ParseCertificate(caBlock.Bytes); err != nil {
+		return nil, fmt.Errorf("failed to parse the CA certificate %s, err: %v", opts.CACertFile, err)
+	}
+	caKeyBlock, err := ReadPEMFile(opts.CAKeyFile)
+	if err != nil || caKeyBlock == nil {
+		return nil, fmt.Errorf("failed to read the CA key %s, err: %v", opts.CAKeyFile, err)
+	}
+	if _, err := (x509PrivateKeyWrap{der: caKeyBlock.Bytes}).Signer(); err != nil {
+		return nil, fmt.Errorf("failed to parse the CA key %s, err: %v", opts.CAKeyFile, err)
+	}
+	usages := opts.Usages
+	if len(usages) == 0 {
+		usages = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+	if err := CheckEdgeCertUsages(usages); err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(opts.CSRDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CSR dir %s, err: %v", opts.CSRDir, err)
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create dir %s, err: %v", opts.OutputDir, err)
+	}
+
+	manifest := &BatchManifest{Entries: []BatchManifestEntry{}}
+	signed := make(map[string]string)
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		entry := BatchManifestEntry{CSRFile: f.Name()}
+		if err := signCSRFile(&opts, caBlock.Bytes, caKeyBlock.Bytes, usages, signed, &entry); err != nil {
+			entry.Error = err.Error()
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+	sort.Slice(manifest.Entries, func(i, j int) bool {
+		return manifest.Entries[i].CSRFile < manifest.Entries[j].CSRFile
+	})
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, fmt.Errorf("failed to marshal the manifest, err: %v", err)
+	}
+	manifestFile := filepath.Join(opts.OutputDir, BatchManifestFileName)
+	if err := os.WriteFile(manifestFile, data, 0644); err != nil {
+		return manifest, fmt.Errorf("failed to write file %s, err: %v", manifestFile, err)
+	}
+	return manifest, nil
+}
+
+// signCSRFile signs a CSR file and fills the entry, signed maps the node names
+// to the CSR files that have been signed in the batch.
+func signCSRFile(opts *BatchSignOptions, caDER, caKeyDER []byte, usages []x509.ExtKeyUsage,
+	signed map[string]string, entry *BatchManifestEntry) error {
+	payload, err := os.ReadFile(filepath.Join(opts.CSRDir, entry.CSRFile))
+	if err != nil {
+		return err
+	}
+	csrDER, err := DecodeCSR(payload)
+	if err != nil {
+		return fmt.Errorf("invalid CSR, err: %v", err)
+	}
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return fmt.Errorf("invalid CSR, err: %v", err)
+	}
+	nodeName, err := ValidateEdgeCSR(csr, "", opts.SignatureAlgorithms, 0)
+	if err != nil {
+		return err
+	}
+	entry.NodeName = nodeName
+	if other, ok := signed[nodeName]; ok {
+		return fmt.Errorf("duplicate CSR of node %s, which is signed from %s", nodeName, other)
+	}
+
+	certBlock, err := GetHandler(HandlerTypeX509).SignCerts(context.Background(), SignCertsOptionsWithCSR(
+		csrDER, caDER, caKeyDER, usages, ClampEdgeCertDuration(opts.Duration)).WithSignaturePolicy(nil))
+	if err != nil {
+		return fmt.Errorf("failed to sign the CSR, err: %v", err)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse the signed certificate, err: %v", err)
+	}
+	certFile := nodeName + ".crt"
+	if _, err := WriteDERToPEMFile(filepath.Join(opts.OutputDir, certFile),
+		certutil.CertificateBlockType, certBlock.Bytes); err != nil {
+		return err
+	}
+	signed[nodeName] = entry.CSRFile
+	notAfter := cert.NotAfter.UTC()
+	entry.CertFile = certFile
+	entry.Serial = cert.SerialNumber.String()
+	entry.NotAfter = &notAfter
+	entry.SignatureAlgorithm = cert.SignatureAlgorithm.String()
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/keyutil"
+)
+
+func TestSignCSRBatch(t *testing.T) {
+	dir := t.TempDir()
+	csrDir, outDir := filepath.Join(dir, "csrs"), filepath.Join(dir, "out")
+	assert.NoError(t, os.MkdirAll(filepath.Join(csrDir, "subdir"), 0755))
+
+	cah := GetCAHandler(CAHandlerTypeX509)
+	caKey, err := cah.GenPrivateKey()
+	assert.NoError(t, err)
+	ca, err := cah.NewSelfSigned(caKey)
+	assert.NoError(t, err)
+	caCertFile, caKeyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	_, err = WriteDERToPEMFile(caCertFile, "CERTIFICATE", ca.Bytes)
+	assert.NoError(t, err)
+	_, err = WriteDERToPEMFile(caKeyFile, keyutil.ECPrivateKeyBlockType, caKey.DER())
+	assert.NoError(t, err)
+
+	h := GetHandler(HandlerTypeX509)
+	newCSR := func(sub pkix.Name) *pem.Block {
+		pk, err := h.GenPrivateKey()
+		assert.NoError(t, err)
+		csr, err := h.CreateCSR(sub, pk, nil)
+		assert.NoError(t, err)
+		return csr
+	}
+	nodeSubject := func(name string) pkix.Name {
+		return pkix.Name{CommonName: EdgeNodeCommonNamePrefix + name, Organization: []string{EdgeNodeOrganization}}
+	}
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	weakCSR, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: nodeSubject("weak")}, weakKey)
+	assert.NoError(t, err)
+
+	files := map[string][]byte{
+		"a-node1.csr":     pem.EncodeToMemory(newCSR(nodeSubject("node1"))),
+		"b-node2.der":     newCSR(nodeSubject("node2")).Bytes,
+		"c-bad-cn.csr":    pem.EncodeToMemory(newCSR(pkix.Name{CommonName: "kubeedge.io", Organization: []string{"KubeEdge"}})),
+		"d-weak.csr":      pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: weakCSR}),
+		"e-garbage.csr":   []byte("not a csr"),
+		"f-node1-dup.csr": pem.EncodeToMemory(newCSR(nodeSubject("node1"))),
+		".hidden":         []byte("ignored"),
+	}
+	for name, data := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(csrDir, name), data, 0644))
+	}
+
+	manifest, err := SignCSRBatch(BatchSignOptions{
+		CSRDir:     csrDir,
+		OutputDir:  outDir,
+		CACertFile: caCertFile,
+		CAKeyFile:  caKeyFile,
+		Duration:   20 * 365 * 24 * time.Hour,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, manifest.Entries, 6)
+	assert.Equal(t, 4, manifest.Failed())
+
+	want := map[string]string{
+		"c-bad-cn.csr":    "the CommonName \"kubeedge.io\" of the CSR must be system:node:<nodeName>",
+		"d-weak.csr":      "the RSA key size 1024 of the CSR is less than 2048",
+		"e-garbage.csr":   "invalid CSR",
+		"f-node1-dup.csr": "duplicate CSR of node node1",
+	}
+	caCert, err := x509.ParseCertificate(ca.Bytes)
+	assert.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	for _, e := range manifest.Entries {
+		if msg, ok := want[e.CSRFile]; ok {
+			assert.Contains(t, e.Error, msg, e.CSRFile)
+			assert.Empty(t, e.CertFile)
+			continue
+		}
+		assert.Empty(t, e.Error, e.CSRFile)
+		block, err := ReadPEMFile(filepath.Join(outDir, e.CertFile))
+		assert.NoError(t, err)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		assert.NoError(t, err)
+		assert.Equal(t, EdgeNodeCommonNamePrefix+e.NodeName, cert.Subject.CommonName)
+		assert.Equal(t, cert.SerialNumber.String(), e.Serial)
+		// the duration is clamped
+		assert.True(t, cert.NotAfter.Before(time.Now().Add(MaxEdgeCertDuration+time.Minute)))
+		_, err = cert.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+		assert.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, BatchManifestFileName))
+	assert.NoError(t, err)
+	var written BatchManifest
+	assert.NoError(t, json.Unmarshal(data, &written))
+	assert.Len(t, written.Entries, 6)
+	assert.Equal(t, "node1", written.Entries[0].NodeName)
+	assert.Equal(t, "node2.crt", written.Entries[1].CertFile)
+
+	t.Run("invalid CA key aborts the batch", func(t *testing.T) {
+		_, err := SignCSRBatch(BatchSignOptions{
+			CSRDir:     csrDir,
+			OutputDir:  outDir,
+			CACertFile: caCertFile,
+			CAKeyFile:  caCertFile,
+		})
+		assert.ErrorContains(t, err, "failed to parse the CA key")
+	})
+
+	t.Run("disallowed usages abort the batch", func(t *testing.T) {
+		_, err := SignCSRBatch(BatchSignOptions{
+			CSRDir:     csrDir,
+			OutputDir:  outDir,
+			CACertFile: caCertFile,
+			CAKeyFile:  caKeyFile,
+			Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		})
+		assert.ErrorContains(t, err, "is not allowed for edge certificates")
+	})
+}
@@ -0,0 +1,354 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const (
+	// EdgeNodeCommonNamePrefix is the prefix of the CommonName of edge node certificates,
+	// which is followed by the node name.
+	EdgeNodeCommonNamePrefix = "system:node:"
+	// EdgeNodeOrganization is the Organization of edge node certificates.
+	EdgeNodeOrganization = "system:nodes"
+	// EdgeNodeSPIFFEPathPrefix is the prefix of the path of the SPIFFE IDs of edge nodes,
+	// which is followed by the node name.
+	EdgeNodeSPIFFEPathPrefix = "/node/"
+
+	// DefaultEdgeCertDuration is the validity period of edge certificates if it is not specified.
+	DefaultEdgeCertDuration = 365 * 24 * time.Hour
+	// MaxEdgeCertDuration is the longest validity period of edge certificates.
+	MaxEdgeCertDuration = 10 * 365 * 24 * time.Hour
+
+	// DefaultMinRSAKeyBits is the minimum size of the RSA keys of edge CSRs if it is not specified.
+	DefaultMinRSAKeyBits = 2048
+)
+
+// DefaultCSRSignatureAlgorithms are the signature algorithms of CSRs allowed by default.
+var DefaultCSRSignatureAlgorithms = []x509.SignatureAlgorithm{
+	x509.ECDSAWithSHA256,
+	x509.ECDSAWithSHA384,
+	x509.ECDSAWithSHA512,
+	x509.SHA256WithRSA,
+	x509.SHA384WithRSA,
+	x509.SHA512WithRSA,
+	x509.SHA256WithRSAPSS,
+	x509.SHA384WithRSAPSS,
+	x509.SHA512WithRSAPSS,
+	x509.PureEd25519,
+}
+
+// allowedEdgeCertUsages are the extended key usages that can be requested for edge certificates.
+var allowedEdgeCertUsages = []x509.ExtKeyUsage{
+	x509.ExtKeyUsageClientAuth,
+	x509.ExtKeyUsageServerAuth,
+}
+
+var pemHeader = []byte("-----BEGIN ")
+
+// DecodeCSR detects the format of the CSR payload and returns the DER bytes of the CSR.
+// The payload can be either a PEM encoded CERTIFICATE REQUEST block or raw DER bytes,
+// trailing whitespaces are ignored.
+func DecodeCSR(payload []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(payload)) == 0 {
+		return nil, errors.New("the CSR is empty")
+	}
+	if bytes.HasPrefix(bytes.TrimLeft(payload, " \t\r\n"), pemHeader) {
+		return decodePEMCSR(payload)
+	}
+	if bytes.Contains(payload, pemHeader) {
+		return nil, errors.New("unexpected data before the PEM block of the CSR")
+	}
+	return decodeDERCSR(payload)
+}
+
+func decodePEMCSR(payload []byte) ([]byte, error) {
+	block, rest := pem.Decode(payload)
+	if block == nil {
+		return nil, errors.New("failed to decode the PEM block of the CSR")
+	}
+	if block.Type != certutil.CertificateRequestBlockType {
+		return nil, fmt.Errorf("unexpected PEM block type %q, want %q",
+			block.Type, certutil.CertificateRequestBlockType)
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		if next, _ := pem.Decode(rest); next != nil {
+			return nil, errors.New("multiple PEM blocks found, only one CSR is allowed per request")
+		}
+		return nil, errors.New("unexpected data after the PEM block of the CSR")
+	}
+	return block.Bytes, nil
+}
+
+func decodeDERCSR(payload []byte) ([]byte, error) {
+	var raw asn1.RawValue
+	rest, err := asn1.Unmarshal(payload, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("the CSR is neither PEM nor DER encoded, err: %v", err)
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		if _, err := asn1.Unmarshal(rest, &asn1.RawValue{}); err == nil {
+			return nil, errors.New("multiple DER structures found, only one CSR is allowed per request")
+		}
+		return nil, errors.New("unexpected data after the DER encoded CSR")
+	}
+	return raw.FullBytes, nil
+}
+
+// VerifyCSRSignature checks the signature algorithm of the CSR against the allowed algorithms,
+// and verifies the proof-of-possession of the CSR. DefaultCSRSignatureAlgorithms are used if allowed is empty.
+func VerifyCSRSignature(csr *x509.CertificateRequest, allowed []string) error {
+	if len(allowed) == 0 {
+		for _, algo := range DefaultCSRSignatureAlgorithms {
+			allowed = append(allowed, algo.String())
+		}
+	}
+	if !slices.Contains(allowed, csr.SignatureAlgorithm.String()) {
+		return fmt.Errorf("the signature algorithm %s of the CSR is not allowed", csr.SignatureAlgorithm)
+	}
+	return VerifyCSRProofOfPossession(csr)
+}
+
+// VerifyCSRProofOfPossession verifies the self-signature of the CSR against the public key embedded
+// in it, which proves that the requester holds the private key, so that a CSR carrying the public
+// key of someone else is rejected.
+func VerifyCSRProofOfPossession(csr *x509.CertificateRequest) error {
+	if err := csr.CheckSignature(); err != nil {
+		return fmt.Errorf("invalid signature of the CSR, it fails the proof-of-possession of the private key, err: %v", err)
+	}
+	return nil
+}
+
+// ValidateEdgeCSR validates the CSR of an edge node certificate, the same rules are applied
+// by CloudHub and the offline signing. It verifies the signature of the CSR, the subject
+// template and the strength of the public key, and returns the node name in the subject.
+// The subject must belong to nodeName if it is not empty. The RSA keys must have at least
+// minRSAKeyBits, DefaultMinRSAKeyBits is used if it is 0.
+func ValidateEdgeCSR(csr *x509.CertificateRequest, nodeName string, signatureAlgorithms []string, minRSAKeyBits int) (string, error) {
+	if err := VerifyCSRSignature(csr, signatureAlgorithms); err != nil {
+		return "", err
+	}
+	name, err := edgeNodeNameFromSubject(csr)
+	if err != nil {
+		return "", err
+	}
+	if nodeName != "" && name != nodeName {
+		return "", fmt.Errorf("the CommonName of the CSR is for node %q, not %q", name, nodeName)
+	}
+	if minRSAKeyBits == 0 {
+		minRSAKeyBits = DefaultMinRSAKeyBits
+	}
+	if err := checkKeyStrength(csr.PublicKey, minRSAKeyBits); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func edgeNodeNameFromSubject(csr *x509.CertificateRequest) (string, error) {
+	cn := csr.Subject.CommonName
+	name, ok := strings.CutPrefix(cn, EdgeNodeCommonNamePrefix)
+	if !ok || name == "" {
+		return "", fmt.Errorf("the CommonName %q of the CSR must be %s<nodeName>", cn, EdgeNodeCommonNamePrefix)
+	}
+	if !slices.Equal(csr.Subject.Organization, []string{EdgeNodeOrganization}) {
+		return "", fmt.Errorf("the Organization %v of the CSR must be [%s]", csr.Subject.Organization, EdgeNodeOrganization)
+	}
+	return name, nil
+}
+
+func checkKeyStrength(publicKey any, minRSAKeyBits int) error {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if bits := key.N.BitLen(); bits < minRSAKeyBits {
+			return fmt.Errorf("the RSA key size %d of the CSR is less than %d", bits, minRSAKeyBits)
+		}
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256(), elliptic.P384(), elliptic.P521():
+		default:
+			return fmt.Errorf("the ECDSA curve %s of the CSR is not allowed, must be one of P-256, P-384 and P-521",
+				key.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+	default:
+		return fmt.Errorf("unsupported public key type %T of the CSR", publicKey)
+	}
+	return nil
+}
+
+// ParseEdgeCertUsages parses the JSON encoded extended key usages requested for an edge
+// certificate, it defaults to client auth if usagesStr is empty. Only client auth and
+// server auth are allowed.
+func ParseEdgeCertUsages(usagesStr string) ([]x509.ExtKeyUsage, error) {
+	if usagesStr == "" {
+		return []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, nil
+	}
+	var usages []x509.ExtKeyUsage
+	if err := json.Unmarshal([]byte(usagesStr), &usages); err != nil {
+		return nil, fmt.Errorf("unmarshal ExtKeyUsages fail, err: %v", err)
+	}
+	if err := CheckEdgeCertUsages(usages); err != nil {
+		return nil, err
+	}
+	return usages, nil
+}
+
+// CheckEdgeCertUsages checks the extended key usages against the allowlist of edge certificates.
+func CheckEdgeCertUsages(usages []x509.ExtKeyUsage) error {
+	return CheckEdgeCertUsagesAllowed(usages, allowedEdgeCertUsages)
+}
+
+// CheckEdgeCertUsagesAllowed checks the extended key usages against the allowed ones, which are
+// narrowed to the allowlist of edge certificates. All the disallowed usages are reported.
+func CheckEdgeCertUsagesAllowed(usages, allowed []x509.ExtKeyUsage) error {
+	if len(usages) == 0 {
+		return errors.New("must specify at least one ExtKeyUsage")
+	}
+	var disallowed []string
+	for _, u := range usages {
+		if !slices.Contains(allowed, u) || !slices.Contains(allowedEdgeCertUsages, u) {
+			disallowed = append(disallowed, describeExtKeyUsage(u))
+		}
+	}
+	switch len(disallowed) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("the ExtKeyUsage %s is not allowed for edge certificates", disallowed[0])
+	default:
+		return fmt.Errorf("the ExtKeyUsages %s are not allowed for edge certificates", strings.Join(disallowed, ", "))
+	}
+}
+
+// ParseExtKeyUsages parses the names of the extended key usages, such as ClientAuth and ServerAuth.
+func ParseExtKeyUsages(names []string) ([]x509.ExtKeyUsage, error) {
+	usages := make([]x509.ExtKeyUsage, 0, len(names))
+	for _, name := range names {
+		found := false
+		for u, n := range extKeyUsageNames {
+			if n == name {
+				usages, found = append(usages, u), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown ExtKeyUsage %q", name)
+		}
+	}
+	return usages, nil
+}
+
+// describeExtKeyUsage formats the extended key usage as its name followed by its value in the
+// X-KubeEdge-ExtKeyUsages header, e.g. CodeSigning(3).
+func describeExtKeyUsage(u x509.ExtKeyUsage) string {
+	if name, ok := extKeyUsageNames[u]; ok {
+		return fmt.Sprintf("%s(%d)", name, u)
+	}
+	return fmt.Sprintf("%d", u)
+}
+
+// keyUsageNames maps the names of the KeyUsage bits to the bits.
+var keyUsageNames = map[string]x509.KeyUsage{
+	"DigitalSignature":  x509.KeyUsageDigitalSignature,
+	"ContentCommitment": x509.KeyUsageContentCommitment,
+	"KeyEncipherment":   x509.KeyUsageKeyEncipherment,
+	"DataEncipherment":  x509.KeyUsageDataEncipherment,
+	"KeyAgreement":      x509.KeyUsageKeyAgreement,
+	"CertSign":          x509.KeyUsageCertSign,
+	"CRLSign":           x509.KeyUsageCRLSign,
+	"EncipherOnly":      x509.KeyUsageEncipherOnly,
+	"DecipherOnly":      x509.KeyUsageDecipherOnly,
+}
+
+// ParseKeyUsages parses the names of the KeyUsage bits, such as DigitalSignature and KeyEncipherment.
+func ParseKeyUsages(names []string) (x509.KeyUsage, error) {
+	var keyUsage x509.KeyUsage
+	for _, name := range names {
+		ku, ok := keyUsageNames[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown KeyUsage %q", name)
+		}
+		keyUsage |= ku
+	}
+	return keyUsage, nil
+}
+
+// CheckEdgeCertKeyUsage checks that the KeyUsage bits are sane for an edge certificate with
+// the extended key usages and the public key.
+func CheckEdgeCertKeyUsage(keyUsage x509.KeyUsage, usages []x509.ExtKeyUsage, publicKey any) error {
+	if keyUsage == 0 {
+		return errors.New("at least one KeyUsage is required")
+	}
+	if keyUsage&(x509.KeyUsageCertSign|x509.KeyUsageCRLSign) != 0 {
+		return errors.New("CertSign and CRLSign are not allowed for edge certificates")
+	}
+	// TLS peers prove the possession of the key by signatures
+	if (slices.Contains(usages, x509.ExtKeyUsageClientAuth) || slices.Contains(usages, x509.ExtKeyUsageServerAuth)) &&
+		keyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return errors.New("DigitalSignature is required by the ClientAuth and ServerAuth ExtKeyUsages")
+	}
+	if keyUsage&(x509.KeyUsageEncipherOnly|x509.KeyUsageDecipherOnly) != 0 && keyUsage&x509.KeyUsageKeyAgreement == 0 {
+		return errors.New("EncipherOnly and DecipherOnly require KeyAgreement")
+	}
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		if keyUsage&x509.KeyUsageKeyAgreement != 0 {
+			return errors.New("KeyAgreement is not applicable to RSA keys")
+		}
+	case *ecdsa.PublicKey:
+		if keyUsage&(x509.KeyUsageKeyEncipherment|x509.KeyUsageDataEncipherment) != 0 {
+			return errors.New("KeyEncipherment and DataEncipherment are not applicable to ECDSA keys")
+		}
+	case ed25519.PublicKey:
+		if keyUsage&(x509.KeyUsageKeyEncipherment|x509.KeyUsageDataEncipherment|x509.KeyUsageKeyAgreement) != 0 {
+			return errors.New("only signature KeyUsages are applicable to Ed25519 keys")
+		}
+	}
+	return nil
+}
+
+// ClampEdgeCertDuration returns DefaultEdgeCertDuration if d is not positive,
+// and limits d to MaxEdgeCertDuration.
+func ClampEdgeCertDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return DefaultEdgeCertDuration
+	}
+	return min(d, MaxEdgeCertDuration)
+}
+
+// EdgeNodeSPIFFEID returns the SPIFFE ID of the edge node in the trust domain,
+// which is spiffe://<trustDomain>/node/<nodeName>.
+func EdgeNodeSPIFFEID(trustDomain, nodeName string) *url.URL {
+	return &url.URL{Scheme: "spiffe", Host: trustDomain, Path: EdgeNodeSPIFFEPathPrefix + nodeName}
+}
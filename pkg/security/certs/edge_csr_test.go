@@ -0,0 +1,263 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEdgeCertUsages(t *testing.T) {
+	usages, err := ParseEdgeCertUsages("")
+	assert.NoError(t, err)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, usages)
+
+	usages, err = ParseEdgeCertUsages("[1,2]")
+	assert.NoError(t, err)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, usages)
+
+	_, err = ParseEdgeCertUsages("[3]")
+	assert.ErrorContains(t, err, "the ExtKeyUsage CodeSigning(3) is not allowed")
+	_, err = ParseEdgeCertUsages("[2,3,9,42]")
+	assert.ErrorContains(t, err, "the ExtKeyUsages CodeSigning(3), OCSPSigning(9), 42 are not allowed")
+	_, err = ParseEdgeCertUsages("[]")
+	assert.ErrorContains(t, err, "at least one")
+	_, err = ParseEdgeCertUsages("invalid")
+	assert.Error(t, err)
+}
+
+func TestCheckEdgeCertUsagesAllowed(t *testing.T) {
+	allowed, err := ParseExtKeyUsages([]string{"ClientAuth"})
+	assert.NoError(t, err)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, allowed)
+	_, err = ParseExtKeyUsages([]string{"Signing"})
+	assert.ErrorContains(t, err, "unknown ExtKeyUsage")
+
+	assert.NoError(t, CheckEdgeCertUsagesAllowed([]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, allowed))
+	err = CheckEdgeCertUsagesAllowed([]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth}, allowed)
+	assert.ErrorContains(t, err, "the ExtKeyUsage ServerAuth(1) is not allowed")
+	// the allowed usages never widen the allowlist of edge certificates
+	err = CheckEdgeCertUsagesAllowed([]x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning})
+	assert.ErrorContains(t, err, "CodeSigning(3)")
+}
+
+func TestClampEdgeCertDuration(t *testing.T) {
+	assert.Equal(t, DefaultEdgeCertDuration, ClampEdgeCertDuration(0))
+	assert.Equal(t, time.Hour, ClampEdgeCertDuration(time.Hour))
+	assert.Equal(t, MaxEdgeCertDuration, ClampEdgeCertDuration(MaxEdgeCertDuration*2))
+}
+
+func TestCheckEdgeCertKeyUsage(t *testing.T) {
+	ku, err := ParseKeyUsages([]string{"DigitalSignature", "KeyAgreement"})
+	assert.NoError(t, err)
+	assert.Equal(t, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyAgreement, ku)
+	_, err = ParseKeyUsages([]string{"Signing"})
+	assert.ErrorContains(t, err, `unknown KeyUsage "Signing"`)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	clientAuth := []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	cases := []struct {
+		name          string
+		keyUsage      x509.KeyUsage
+		publicKey     any
+		containsError string
+	}{
+		{
+			name:      "RSA key with KeyEncipherment",
+			keyUsage:  x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			publicKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "ECDSA key with KeyAgreement",
+			keyUsage:  x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement,
+			publicKey: &ecKey.PublicKey,
+		},
+		{
+			name:          "no KeyUsage",
+			publicKey:     &ecKey.PublicKey,
+			containsError: "at least one KeyUsage is required",
+		},
+		{
+			name:          "CertSign",
+			keyUsage:      x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+			publicKey:     &ecKey.PublicKey,
+			containsError: "CertSign and CRLSign are not allowed",
+		},
+		{
+			name:          "ClientAuth without DigitalSignature",
+			keyUsage:      x509.KeyUsageKeyEncipherment,
+			publicKey:     &rsaKey.PublicKey,
+			containsError: "DigitalSignature is required",
+		},
+		{
+			name:          "EncipherOnly without KeyAgreement",
+			keyUsage:      x509.KeyUsageDigitalSignature | x509.KeyUsageEncipherOnly,
+			publicKey:     &ecKey.PublicKey,
+			containsError: "require KeyAgreement",
+		},
+		{
+			name:          "ECDSA key with KeyEncipherment",
+			keyUsage:      x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			publicKey:     &ecKey.PublicKey,
+			containsError: "not applicable to ECDSA keys",
+		},
+		{
+			name:          "RSA key with KeyAgreement",
+			keyUsage:      x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement,
+			publicKey:     &rsaKey.PublicKey,
+			containsError: "not applicable to RSA keys",
+		},
+		{
+			name:          "Ed25519 key with KeyAgreement",
+			keyUsage:      x509.KeyUsageDigitalSignature | x509.KeyUsageKeyAgreement,
+			publicKey:     edKey,
+			containsError: "only signature KeyUsages",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := CheckEdgeCertKeyUsage(c.keyUsage, clientAuth, c.publicKey)
+			if c.containsError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, c.containsError)
+		})
+	}
+}
+
+func TestVerifyCSRProofOfPossession(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: EdgeNodeCommonNamePrefix + "testnode"},
+	}, key)
+	assert.NoError(t, err)
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyCSRProofOfPossession(csr))
+
+	// sign the CSR carrying the public key of key with another key
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	digest := sha256.Sum256(csr.RawTBSCertificateRequest)
+	signature, err := ecdsa.SignASN1(rand.Reader, otherKey, digest[:])
+	assert.NoError(t, err)
+	var raw struct {
+		TBS       asn1.RawValue
+		Algorithm pkix.AlgorithmIdentifier
+		Signature asn1.BitString
+	}
+	_, err = asn1.Unmarshal(csrDER, &raw)
+	assert.NoError(t, err)
+	raw.Signature = asn1.BitString{Bytes: signature, BitLength: len(signature) * 8}
+	stolenDER, err := asn1.Marshal(raw)
+	assert.NoError(t, err)
+	stolen, err := x509.ParseCertificateRequest(stolenDER)
+	assert.NoError(t, err)
+	assert.Equal(t, csr.PublicKey, stolen.PublicKey)
+	assert.ErrorContains(t, VerifyCSRProofOfPossession(stolen), "fails the proof-of-possession")
+}
+
+func TestValidateEdgeCSR(t *testing.T) {
+	subject := pkix.Name{
+		Organization: []string{EdgeNodeOrganization},
+		CommonName:   EdgeNodeCommonNamePrefix + "testnode",
+	}
+	newCSR := func(subject pkix.Name, key any) *x509.CertificateRequest {
+		der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: subject}, key)
+		assert.NoError(t, err)
+		csr, err := x509.ParseCertificateRequest(der)
+		assert.NoError(t, err)
+		return csr
+	}
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name          string
+		csr           *x509.CertificateRequest
+		nodeName      string
+		minRSAKeyBits int
+		containsError string
+	}{
+		{
+			name:     "valid",
+			csr:      newCSR(subject, p256Key),
+			nodeName: "testnode",
+		},
+		{
+			name:          "other node",
+			csr:           newCSR(subject, p256Key),
+			nodeName:      "othernode",
+			containsError: `the CommonName of the CSR is for node "testnode", not "othernode"`,
+		},
+		{
+			name:          "invalid organization",
+			csr:           newCSR(pkix.Name{Organization: []string{"system:masters"}, CommonName: subject.CommonName}, p256Key),
+			containsError: "the Organization [system:masters] of the CSR must be [system:nodes]",
+		},
+		{
+			name:          "weak RSA key",
+			csr:           newCSR(subject, rsaKey),
+			containsError: "the RSA key size 1024 of the CSR is less than 2048",
+		},
+		{
+			name:          "lowered RSA key size",
+			csr:           newCSR(subject, rsaKey),
+			minRSAKeyBits: 1024,
+		},
+		{
+			name:          "disallowed curve",
+			csr:           newCSR(subject, p224Key),
+			containsError: "the ECDSA curve P-224 of the CSR is not allowed",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			name, err := ValidateEdgeCSR(c.csr, c.nodeName, nil, c.minRSAKeyBits)
+			if c.containsError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, "testnode", name)
+				return
+			}
+			assert.ErrorContains(t, err, c.containsError)
+		})
+	}
+}
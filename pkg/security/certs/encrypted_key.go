@@ -0,0 +1,290 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// EncryptedPrivateKeyBlockType is the PEM block type of the PKCS #8 private keys encrypted
+	// with a passphrase, e.g. by `openssl pkcs8 -topk8 -v2 aes-256-cbc`.
+	EncryptedPrivateKeyBlockType = "ENCRYPTED PRIVATE KEY"
+	// WrappedPrivateKeyBlockType is the PEM block type of the private keys wrapped by a KEK,
+	// see WrapPrivateKey.
+	WrappedPrivateKeyBlockType = "KUBEEDGE WRAPPED PRIVATE KEY"
+
+	// CAKeyPassphraseEnv is the env of the passphrase of the encrypted CA key of CloudCore,
+	// which is read if the passphrase file is not configured.
+	CAKeyPassphraseEnv = "CLOUDCORE_CA_KEY_PASSPHRASE"
+
+	// pbkdf2Iterations is the iteration count of PBKDF2 when a private key is encrypted.
+	pbkdf2Iterations = 100000
+	// kekSize is the size of the KEKs, which are AES-256 keys.
+	kekSize = 32
+)
+
+var (
+	// ErrIncorrectPassphrase is returned when an encrypted private key fails to be decrypted.
+	ErrIncorrectPassphrase = errors.New("the passphrase is incorrect")
+	// ErrIncorrectKEK is returned when a wrapped private key fails to be unwrapped.
+	ErrIncorrectKEK = errors.New("the KEK is incorrect")
+)
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// encryptedPrivateKeyInfo is the EncryptedPrivateKeyInfo of RFC 5208.
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params is the PBES2-params of RFC 8018.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params is the PBKDF2-params of RFC 8018, PRF is hmacWithSHA1 if it is absent.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// IsEncryptedPrivateKey reports whether the PEM block is a private key encrypted with a passphrase
+// or wrapped by a KEK.
+func IsEncryptedPrivateKey(block *pem.Block) bool {
+	return block.Type == EncryptedPrivateKeyBlockType || block.Type == WrappedPrivateKeyBlockType
+}
+
+// EncryptPrivateKey encrypts the private key in DER with the passphrase into a PKCS #8
+// EncryptedPrivateKeyInfo with PBES2, PBKDF2-HMAC-SHA256 and AES-256-CBC, which OpenSSL reads too.
+// The key is converted to PKCS #8 before the encryption.
+func EncryptPrivateKey(der, passphrase []byte) (*pem.Block, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("the passphrase must not be empty")
+	}
+	signer, err := PrivateKeySigner(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key, err: %v", err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(pbkdf2.Key(passphrase, salt, pbkdf2Iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(pkcs8)%aes.BlockSize
+	data := append(pkcs8, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pbkdf2Iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	info, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pem.Block{Type: EncryptedPrivateKeyBlockType, Bytes: info}, nil
+}
+
+// DecryptPrivateKey decrypts the PKCS #8 private key encrypted with the passphrase by PBES2,
+// and returns the private key in PKCS #8 DER. ErrIncorrectPassphrase is returned if the key
+// can not be decrypted with the passphrase.
+func DecryptPrivateKey(block *pem.Block, passphrase []byte) ([]byte, error) {
+	if block.Type != EncryptedPrivateKeyBlockType {
+		return nil, fmt.Errorf("the PEM block %q is not an encrypted private key", block.Type)
+	}
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		return nil, fmt.Errorf("invalid encrypted private key, err: %v", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("the encryption algorithm %v of the private key is not supported, only PBES2 is supported",
+			info.Algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("invalid PBES2 parameters, err: %v", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("the key derivation function %v is not supported, only PBKDF2 is supported",
+			params.KeyDerivationFunc.Algorithm)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("invalid PBKDF2 parameters, err: %v", err)
+	}
+	var prf func() hash.Hash
+	switch {
+	case len(kdf.PRF.Algorithm) == 0, kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, fmt.Errorf("the PBKDF2 PRF %v is not supported", kdf.PRF.Algorithm)
+	}
+	var keyLen int
+	switch scheme := params.EncryptionScheme.Algorithm; {
+	case scheme.Equal(oidAES128CBC):
+		keyLen = 16
+	case scheme.Equal(oidAES192CBC):
+		keyLen = 24
+	case scheme.Equal(oidAES256CBC):
+		keyLen = 32
+	default:
+		return nil, fmt.Errorf("the encryption scheme %v is not supported, only AES-CBC is supported", scheme)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, errors.New("invalid IV of the encrypted private key")
+	}
+	data := info.EncryptedData
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid encrypted data of the private key")
+	}
+	c, err := aes.NewCipher(pbkdf2.Key(passphrase, kdf.Salt, kdf.IterationCount, keyLen, prf))
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(c, iv).CryptBlocks(plain, data)
+	// a wrong passphrase shows up as an invalid padding or an unparsable key
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize ||
+		!bytes.Equal(plain[len(plain)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrIncorrectPassphrase
+	}
+	der := plain[:len(plain)-padding]
+	if _, err := x509.ParsePKCS8PrivateKey(der); err != nil {
+		return nil, ErrIncorrectPassphrase
+	}
+	return der, nil
+}
+
+// ParseKEK parses the KEK, which is a 256-bit AES key encoded in base64,
+// e.g. generated by `openssl rand -base64 32`.
+func ParseKEK(data []byte) ([]byte, error) {
+	kek, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, fmt.Errorf("the KEK must be encoded in base64, err: %v", err)
+	}
+	if len(kek) != kekSize {
+		return nil, fmt.Errorf("the KEK must be %d bytes, got %d bytes", kekSize, len(kek))
+	}
+	return kek, nil
+}
+
+// WrapPrivateKey wraps the private key in DER by the KEK with AES-256-GCM, the bytes of the
+// PEM block are the nonce followed by the sealed key. The key is kept in its encoding.
+func WrapPrivateKey(der, kek []byte) (*pem.Block, error) {
+	aead, err := kekAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &pem.Block{Type: WrappedPrivateKeyBlockType, Bytes: aead.Seal(nonce, nonce, der, nil)}, nil
+}
+
+// UnwrapPrivateKey unwraps the private key wrapped by WrapPrivateKey, ErrIncorrectKEK is
+// returned if the key is not wrapped by the KEK.
+func UnwrapPrivateKey(block *pem.Block, kek []byte) ([]byte, error) {
+	if block.Type != WrappedPrivateKeyBlockType {
+		return nil, fmt.Errorf("the PEM block %q is not a wrapped private key", block.Type)
+	}
+	aead, err := kekAEAD(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(block.Bytes) < aead.NonceSize() {
+		return nil, errors.New("invalid wrapped private key")
+	}
+	nonce, sealed := block.Bytes[:aead.NonceSize()], block.Bytes[aead.NonceSize():]
+	der, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, ErrIncorrectKEK
+	}
+	return der, nil
+}
+
+func kekAEAD(kek []byte) (cipher.AEAD, error) {
+	if len(kek) != kekSize {
+		return nil, fmt.Errorf("the KEK must be %d bytes, got %d bytes", kekSize, len(kek))
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptPrivateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	block, err := EncryptPrivateKey(der, []byte("passphrase"))
+	assert.NoError(t, err)
+	assert.Equal(t, EncryptedPrivateKeyBlockType, block.Type)
+	assert.True(t, IsEncryptedPrivateKey(block))
+
+	// the block survives a PEM round trip and decrypts to the same key
+	block, _ = pem.Decode(pem.EncodeToMemory(block))
+	plain, err := DecryptPrivateKey(block, []byte("passphrase"))
+	assert.NoError(t, err)
+	signer, err := PrivateKeySigner(plain)
+	assert.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(signer.Public()))
+
+	_, err = DecryptPrivateKey(block, []byte("wrong"))
+	assert.ErrorIs(t, err, ErrIncorrectPassphrase)
+	_, err = DecryptPrivateKey(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}, []byte("passphrase"))
+	assert.Error(t, err)
+	_, err = EncryptPrivateKey(der, nil)
+	assert.Error(t, err)
+}
+
+func TestWrapPrivateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	raw := make([]byte, 32)
+	_, err = rand.Read(raw)
+	assert.NoError(t, err)
+	kek, err := ParseKEK([]byte(base64.StdEncoding.EncodeToString(raw) + "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, raw, kek)
+
+	block, err := WrapPrivateKey(der, kek)
+	assert.NoError(t, err)
+	assert.True(t, IsEncryptedPrivateKey(block))
+	plain, err := UnwrapPrivateKey(block, kek)
+	assert.NoError(t, err)
+	assert.Equal(t, der, plain)
+
+	other := make([]byte, 32)
+	_, err = UnwrapPrivateKey(block, other)
+	assert.ErrorIs(t, err, ErrIncorrectKEK)
+
+	_, err = ParseKEK([]byte("not base64"))
+	assert.Error(t, err)
+	_, err = ParseKEK([]byte(base64.StdEncoding.EncodeToString(raw[:16])))
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+)
+
+var (
+	// oidCertificateExtension is the arc of the standard certificate extensions, id-ce,
+	// such as the KeyUsage, the SANs and the BasicConstraints
+	oidCertificateExtension = asn1.ObjectIdentifier{2, 5, 29}
+	// oidPrivateExtension is the arc of the PKIX private extensions, id-pe,
+	// such as the AuthorityInfoAccess
+	oidPrivateExtension = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1}
+)
+
+// ParseExtensionOID parses the dotted OID of a custom certificate extension, e.g. 1.3.6.1.4.1.55555.2.1.
+// The OIDs of the standard extensions are refused, which are set by the handler.
+func ParseExtensionOID(s string) (asn1.ObjectIdentifier, error) {
+	oid, err := parseOID(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extension OID %q, err: %v", s, err)
+	}
+	if err := checkExtensionOID(oid); err != nil {
+		return nil, err
+	}
+	return oid, nil
+}
+
+// NewUTF8StringExtension returns a non-critical extension whose value is the UTF8String.
+func NewUTF8StringExtension(oid asn1.ObjectIdentifier, value string) (pkix.Extension, error) {
+	der, err := asn1.MarshalWithParams(value, "utf8")
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("failed to marshal the value of the extension %s, err: %v", oid, err)
+	}
+	return pkix.Extension{Id: oid, Value: der}, nil
+}
+
+// CheckExtraExtensions checks the custom extensions added to a certificate. The critical ones
+// are refused since the standard verifiers reject the certificates with critical extensions
+// they don't know, and so are the duplicated ones and the ones overriding the standard extensions.
+func CheckExtraExtensions(exts []pkix.Extension) error {
+	for i, ext := range exts {
+		if ext.Critical {
+			return fmt.Errorf("the extension %s must not be critical", ext.Id)
+		}
+		if err := checkExtensionOID(ext.Id); err != nil {
+			return err
+		}
+		for _, other := range exts[:i] {
+			if other.Id.Equal(ext.Id) {
+				return fmt.Errorf("the extension %s is duplicated", ext.Id)
+			}
+		}
+	}
+	return nil
+}
+
+func checkExtensionOID(oid asn1.ObjectIdentifier) error {
+	if hasOIDPrefix(oid, oidCertificateExtension) || hasOIDPrefix(oid, oidPrivateExtension) {
+		return fmt.Errorf("the extension %s overrides a standard extension", oid)
+	}
+	return nil
+}
+
+func hasOIDPrefix(oid, prefix asn1.ObjectIdentifier) bool {
+	return len(oid) > len(prefix) && prefix.Equal(oid[:len(prefix)])
+}
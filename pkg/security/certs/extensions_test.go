@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs_test
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+func TestSignCertsWithExtraExtensions(t *testing.T) {
+	caDER, caKeyDER := newTestCA(t, certs.KeyTypeECDSAP256)
+	csrDER := newTestCSR(t, newTestSigner(t, certs.KeyTypeECDSAP256), x509.ECDSAWithSHA256)
+	usages := []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	assetTag, err := certs.ParseExtensionOID("1.3.6.1.4.1.55555.2.1")
+	require.NoError(t, err)
+	siteCode, err := certs.ParseExtensionOID("1.3.6.1.4.1.55555.2.2")
+	require.NoError(t, err)
+	assetExt, err := certs.NewUTF8StringExtension(assetTag, "asset-0042")
+	require.NoError(t, err)
+	siteExt, err := certs.NewUTF8StringExtension(siteCode, "sha-01")
+	require.NoError(t, err)
+
+	block, err := certs.GetHandler(certs.HandlerTypeX509).SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+		csrDER, caDER, caKeyDER, usages, time.Hour).WithExtraExtensions(assetExt, siteExt))
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	values := make(map[string]string)
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(assetTag) || ext.Id.Equal(siteCode) {
+			assert.False(t, ext.Critical)
+			var value string
+			_, err := asn1.UnmarshalWithParams(ext.Value, &value, "utf8")
+			require.NoError(t, err)
+			values[ext.Id.String()] = value
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"1.3.6.1.4.1.55555.2.1": "asset-0042",
+		"1.3.6.1.4.1.55555.2.2": "sha-01",
+	}, values)
+
+	cases := []struct {
+		name    string
+		exts    []pkix.Extension
+		wantErr string
+	}{
+		{
+			name:    "critical extension",
+			exts:    []pkix.Extension{{Id: assetTag, Critical: true, Value: assetExt.Value}},
+			wantErr: "must not be critical",
+		},
+		{
+			name:    "standard extension",
+			exts:    []pkix.Extension{{Id: asn1.ObjectIdentifier{2, 5, 29, 17}, Value: assetExt.Value}},
+			wantErr: "overrides a standard extension",
+		},
+		{
+			name:    "duplicated extension",
+			exts:    []pkix.Extension{assetExt, assetExt},
+			wantErr: "is duplicated",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := certs.GetHandler(certs.HandlerTypeX509).SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+				csrDER, caDER, caKeyDER, usages, time.Hour).WithExtraExtensions(c.exts...))
+			assert.ErrorContains(t, err, c.wantErr)
+		})
+	}
+}
+
+func TestParseExtensionOID(t *testing.T) {
+	_, err := certs.ParseExtensionOID("1.3.6.1.4.1.55555.2.1")
+	assert.NoError(t, err)
+	_, err = certs.ParseExtensionOID("assetTag")
+	assert.ErrorContains(t, err, "invalid extension OID")
+	_, err = certs.ParseExtensionOID("2.5.29.19")
+	assert.ErrorContains(t, err, "overrides a standard extension")
+	_, err = certs.ParseExtensionOID("1.3.6.1.5.5.7.1.1")
+	assert.ErrorContains(t, err, "overrides a standard extension")
+}
@@ -39,3 +39,13 @@ func GetHandler(t HanndlerType) Handler {
 	}
 	return nil
 }
+
+// GetHandlerWithKeyType is like GetHandler, but the private keys generated by the handler are of
+// the key type, see GenPrivateKey.
+func GetHandlerWithKeyType(t HanndlerType, keyType string) Handler {
+	switch t {
+	case HandlerTypeX509:
+		return &x509CertsHandler{keyType: keyType}
+	}
+	return nil
+}
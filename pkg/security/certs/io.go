@@ -43,3 +43,20 @@ func WriteDERToPEMFile(file, t string, der []byte) (*pem.Block, error) {
 	}
 	return block, nil
 }
+
+// WriteDERsToPEMFile writes the DER blocks of the type to the file in PEM, e.g. a certificate
+// followed by its intermediate CA certificates.
+func WriteDERsToPEMFile(file, t string, ders [][]byte) error {
+	dir := filepath.Dir(file)
+	if err := os.MkdirAll(dir, 0722); err != nil {
+		return fmt.Errorf("failed to create dir %s, err: %v", dir, err)
+	}
+	var bff []byte
+	for _, der := range ders {
+		bff = append(bff, pem.EncodeToMemory(&pem.Block{Type: t, Bytes: der})...)
+	}
+	if err := os.WriteFile(file, bff, 0622); err != nil {
+		return fmt.Errorf("failed to write file %s, err: %v", file, err)
+	}
+	return nil
+}
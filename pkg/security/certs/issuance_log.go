@@ -0,0 +1,401 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+)
+
+// The authentication methods of the requests of the certificates recorded in the issuance log.
+const (
+	// IssuanceAuthToken indicates that the request is authenticated by a bootstrap token
+	// or a ServiceAccount token.
+	IssuanceAuthToken = "token"
+	// IssuanceAuthCertificate indicates that the request is authenticated by the current
+	// certificate of the node, which is a renewal.
+	IssuanceAuthCertificate = "certificate"
+	// IssuanceAuthPreRegistration indicates that the request is authenticated by the
+	// pre-registered public key of the node.
+	IssuanceAuthPreRegistration = "preregistration"
+)
+
+// IssuanceLogEntry is an entry of the certificate issuance log. Entries are chained by
+// hashes, the Hash of an entry covers the Hash of the previous entry and its own fields.
+type IssuanceLogEntry struct {
+	Index     uint64    `json:"index"`
+	Timestamp time.Time `json:"timestamp"`
+	NodeName  string    `json:"nodeName"`
+	// ClientIP is the effective IP of the client which requested the certificate.
+	ClientIP string `json:"clientIP,omitempty"`
+	Serial   string `json:"serial"`
+	// CertHash is the hex encoded SHA-256 digest of the issued certificate DER.
+	CertHash string `json:"certHash"`
+	// External indicates that the certificate is not signed by CloudHub but imported,
+	// e.g. signed offline for air-gapped sites.
+	External bool `json:"external,omitempty"`
+	// NotAfter is the expiration of the certificate, it is absent in the entries written
+	// before the expiration is recorded.
+	NotAfter *time.Time `json:"notAfter,omitempty"`
+	// SignatureAlgorithm is the signature algorithm of the certificate signed by CloudHub.
+	SignatureAlgorithm string `json:"signatureAlgorithm,omitempty"`
+	// NotBefore is the start of the validity of the certificate signed by CloudHub.
+	NotBefore *time.Time `json:"notBefore,omitempty"`
+	// KeyUsages are the names of the KeyUsage bits of the certificate signed by CloudHub.
+	KeyUsages []string `json:"keyUsages,omitempty"`
+	// ExtKeyUsages are the names of the extended key usages of the certificate signed by CloudHub.
+	ExtKeyUsages []string `json:"extKeyUsages,omitempty"`
+	// Authentication indicates how the request of the certificate was authenticated,
+	// one of token, certificate and preregistration.
+	Authentication string `json:"authentication,omitempty"`
+	// Hash is the hex encoded hash chain head after this entry is appended.
+	Hash string `json:"hash"`
+	// Leaf is the hex encoded digest of the fields of the entry, the Hash chains it to the Hash
+	// of the previous entry. It is absent in the entries written before, whose Hash covers the
+	// fields directly.
+	Leaf string `json:"leaf,omitempty"`
+	// Pruned indicates that the fields of the entry other than Index, Leaf and Hash are removed
+	// after the certificate expired. The chain is verified through the Leaf of a pruned entry.
+	Pruned bool `json:"pruned,omitempty"`
+}
+
+// IssuanceLogHead is the signed head of the certificate issuance log.
+type IssuanceLogHead struct {
+	// Size is the number of entries in the log.
+	Size uint64 `json:"size"`
+	// Hash is the hex encoded hash chain head of the last entry.
+	Hash      string    `json:"hash"`
+	Timestamp time.Time `json:"timestamp"`
+	// Signature is the signature of the head signed by the CA key, which is an ASN.1 ECDSA,
+	// a PKCS #1 v1.5 RSA or an Ed25519 signature depending on the type of the key.
+	Signature []byte `json:"signature"`
+}
+
+// NewIssuanceLogEntry creates an entry which is chained to the previous hash.
+// The prevHash of the first entry is empty.
+func NewIssuanceLogEntry(index uint64, ts time.Time, nodeName, clientIP, serial string, certDER []byte,
+	notAfter time.Time, signatureAlgorithm, prevHash string) IssuanceLogEntry {
+	e := newIssuanceLogEntry(index, ts, nodeName, clientIP, serial, certDER, notAfter, signatureAlgorithm)
+	return chainIssuanceLogEntry(prevHash, e)
+}
+
+// NewIssuedCertLogEntry creates an entry of the certificate signed by CloudHub, which is chained
+// to the previous hash. The authentication is how the request of the certificate was authenticated.
+func NewIssuedCertLogEntry(index uint64, ts time.Time, nodeName, clientIP, authentication string,
+	cert *x509.Certificate, prevHash string) IssuanceLogEntry {
+	e := newIssuanceLogEntry(index, ts, nodeName, clientIP, cert.SerialNumber.String(), cert.Raw,
+		cert.NotAfter, cert.SignatureAlgorithm.String())
+	notBefore := cert.NotBefore.UTC()
+	e.NotBefore = &notBefore
+	e.KeyUsages = keyUsageNamesOf(cert.KeyUsage)
+	e.ExtKeyUsages = extKeyUsageNamesOf(cert.ExtKeyUsage)
+	e.Authentication = authentication
+	return chainIssuanceLogEntry(prevHash, e)
+}
+
+func newIssuanceLogEntry(index uint64, ts time.Time, nodeName, clientIP, serial string, certDER []byte,
+	notAfter time.Time, signatureAlgorithm string) IssuanceLogEntry {
+	digest := sha256.Sum256(certDER)
+	e := IssuanceLogEntry{
+		Index:              index,
+		Timestamp:          ts.UTC(),
+		NodeName:           nodeName,
+		ClientIP:           clientIP,
+		Serial:             serial,
+		CertHash:           hex.EncodeToString(digest[:]),
+		SignatureAlgorithm: signatureAlgorithm,
+	}
+	if !notAfter.IsZero() {
+		notAfter = notAfter.UTC()
+		e.NotAfter = &notAfter
+	}
+	return e
+}
+
+// keyUsageNamesOf returns the names of the KeyUsage bits in the order of the bits.
+func keyUsageNamesOf(keyUsage x509.KeyUsage) []string {
+	var names []string
+	for bit := x509.KeyUsageDigitalSignature; bit <= x509.KeyUsageDecipherOnly; bit <<= 1 {
+		if keyUsage&bit == 0 {
+			continue
+		}
+		for name, ku := range keyUsageNames {
+			if ku == bit {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// extKeyUsageNames maps the extended key usages to the names, which are the names of
+// x509.ExtKeyUsage without the prefix. Only ClientAuth and ServerAuth are issued to edge
+// nodes, the others name the usages rejected by CheckEdgeCertUsages.
+var extKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:             "Any",
+	x509.ExtKeyUsageServerAuth:      "ServerAuth",
+	x509.ExtKeyUsageClientAuth:      "ClientAuth",
+	x509.ExtKeyUsageCodeSigning:     "CodeSigning",
+	x509.ExtKeyUsageEmailProtection: "EmailProtection",
+	x509.ExtKeyUsageTimeStamping:    "TimeStamping",
+	x509.ExtKeyUsageOCSPSigning:     "OCSPSigning",
+}
+
+// extKeyUsageNamesOf returns the names of the extended key usages, the usages without
+// a name are formatted as the numbers.
+func extKeyUsageNamesOf(usages []x509.ExtKeyUsage) []string {
+	names := make([]string, 0, len(usages))
+	for _, u := range usages {
+		if name, ok := extKeyUsageNames[u]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, fmt.Sprintf("%d", u))
+		}
+	}
+	return names
+}
+
+// PruneIssuanceLogEntry returns the pruned entry of e, which only keeps the Index, the Leaf
+// and the Hash. The entries without the Leaf can't be pruned, see CanPruneIssuanceLogEntry.
+func PruneIssuanceLogEntry(e IssuanceLogEntry) IssuanceLogEntry {
+	return IssuanceLogEntry{
+		Index:  e.Index,
+		Hash:   e.Hash,
+		Leaf:   e.Leaf,
+		Pruned: true,
+	}
+}
+
+// CanPruneIssuanceLogEntry returns whether the entry can be pruned. The entries written before
+// the Leaf is recorded can't, as their Hash can't be verified without their fields.
+func CanPruneIssuanceLogEntry(e IssuanceLogEntry) bool {
+	return e.Leaf != ""
+}
+
+// NewImportedIssuanceLogEntry creates an entry of an externally issued certificate,
+// which is chained to the previous hash. The certHash is the hex encoded SHA-256 digest
+// of the certificate DER.
+func NewImportedIssuanceLogEntry(index uint64, ts time.Time, nodeName, serial, certHash string, notAfter time.Time, prevHash string) IssuanceLogEntry {
+	notAfter = notAfter.UTC()
+	e := IssuanceLogEntry{
+		Index:     index,
+		Timestamp: ts.UTC(),
+		NodeName:  nodeName,
+		Serial:    serial,
+		CertHash:  certHash,
+		External:  true,
+		NotAfter:  &notAfter,
+	}
+	return chainIssuanceLogEntry(prevHash, e)
+}
+
+// chainIssuanceLogEntry sets the Leaf of the entry and its Hash chained to the previous hash.
+func chainIssuanceLogEntry(prevHash string, e IssuanceLogEntry) IssuanceLogEntry {
+	e.Leaf = issuanceLogLeaf(e)
+	e.Hash = chainIssuanceLogLeaf(prevHash, e.Leaf)
+	return e
+}
+
+// chainIssuanceLogLeaf computes the hash chain head of the leaf based on the previous hash.
+func chainIssuanceLogLeaf(prevHash, leaf string) string {
+	h := sha256.New()
+	writeIssuanceLogField(h, []byte(prevHash))
+	writeIssuanceLogField(h, []byte(leaf))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// issuanceLogLeaf computes the digest of the fields of the entry.
+func issuanceLogLeaf(e IssuanceLogEntry) string {
+	h := sha256.New()
+	writeIssuanceLogFields(h, e)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// legacyIssuanceLogHash computes the hash chain head of the entry written before the Leaf is
+// recorded, which covers the previous hash and the fields directly.
+func legacyIssuanceLogHash(prevHash string, e IssuanceLogEntry) string {
+	h := sha256.New()
+	writeIssuanceLogField(h, []byte(prevHash))
+	writeIssuanceLogFields(h, e)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeIssuanceLogField(h hash.Hash, b []byte) {
+	var l [8]byte
+	binary.BigEndian.PutUint64(l[:], uint64(len(b)))
+	h.Write(l[:])
+	h.Write(b)
+}
+
+func writeIssuanceLogFields(h hash.Hash, e IssuanceLogEntry) {
+	writeField := func(b []byte) {
+		writeIssuanceLogField(h, b)
+	}
+	var index [8]byte
+	binary.BigEndian.PutUint64(index[:], e.Index)
+	writeField(index[:])
+	writeField([]byte(e.Timestamp.UTC().Format(time.RFC3339Nano)))
+	writeField([]byte(e.NodeName))
+	writeField([]byte(e.ClientIP))
+	writeField([]byte(e.Serial))
+	writeField([]byte(e.CertHash))
+	// the optional fields are only covered when they are set, so the hashes of
+	// the entries written before are kept unchanged
+	if e.External {
+		writeField([]byte("external"))
+	}
+	if e.NotAfter != nil {
+		writeField([]byte(e.NotAfter.UTC().Format(time.RFC3339Nano)))
+	}
+	if e.SignatureAlgorithm != "" {
+		writeField([]byte(e.SignatureAlgorithm))
+	}
+	// the fields added later are labeled, so they can not be confused with each other
+	if e.NotBefore != nil {
+		writeField([]byte("notBefore=" + e.NotBefore.UTC().Format(time.RFC3339Nano)))
+	}
+	if len(e.KeyUsages) > 0 {
+		writeField([]byte("keyUsages=" + strings.Join(e.KeyUsages, ",")))
+	}
+	if len(e.ExtKeyUsages) > 0 {
+		writeField([]byte("extKeyUsages=" + strings.Join(e.ExtKeyUsages, ",")))
+	}
+	if e.Authentication != "" {
+		writeField([]byte("authentication=" + e.Authentication))
+	}
+}
+
+// issuanceLogHeadMessage returns the message of the head which is signed.
+func issuanceLogHeadMessage(head *IssuanceLogHead) []byte {
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], head.Size)
+	return bytes.Join([][]byte{
+		size[:],
+		[]byte(head.Hash),
+		[]byte(head.Timestamp.UTC().Format(time.RFC3339Nano)),
+	}, []byte{0})
+}
+
+func issuanceLogHeadDigest(head *IssuanceLogHead) []byte {
+	digest := sha256.Sum256(issuanceLogHeadMessage(head))
+	return digest[:]
+}
+
+// SignIssuanceLogHead signs the issuance log head with the signer of the CA private key.
+// The SHA-256 digest of the head is signed by ECDSA and RSA keys, while Ed25519 keys sign
+// the head itself, as Ed25519 hashes the message on its own.
+func SignIssuanceLogHead(size uint64, hash string, key crypto.Signer) (*IssuanceLogHead, error) {
+	head := &IssuanceLogHead{
+		Size:      size,
+		Hash:      hash,
+		Timestamp: time.Now().UTC(),
+	}
+	var signature []byte
+	var err error
+	if _, ok := key.Public().(ed25519.PublicKey); ok {
+		signature, err = key.Sign(rand.Reader, issuanceLogHeadMessage(head), crypto.Hash(0))
+	} else {
+		signature, err = key.Sign(rand.Reader, issuanceLogHeadDigest(head), crypto.SHA256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the issuance log head, err: %v", err)
+	}
+	head.Signature = signature
+	return head, nil
+}
+
+// VerifyIssuanceLog verifies an exported segment of the issuance log against the signed head.
+// The prevHash is the hash of the entry before the segment, which is empty if the segment
+// starts from the first entry. The segment must end at the head.
+func VerifyIssuanceLog(prevHash string, entries []IssuanceLogEntry, head *IssuanceLogHead, caDER []byte) error {
+	if head == nil {
+		return errors.New("the issuance log head is required")
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return fmt.Errorf("failed to parse CA, err: %v", err)
+	}
+	if err := verifyIssuanceLogHead(head, ca.PublicKey); err != nil {
+		return err
+	}
+
+	chainHash := prevHash
+	for i, e := range entries {
+		if i > 0 && e.Index != entries[i-1].Index+1 {
+			return fmt.Errorf("entry %d is not continuous with the previous entry %d", e.Index, entries[i-1].Index)
+		}
+		switch {
+		case e.Leaf == "" && e.Pruned:
+			return fmt.Errorf("the leaf digest of the pruned entry %d is missing", e.Index)
+		case e.Leaf == "":
+			if legacyIssuanceLogHash(chainHash, e) != e.Hash {
+				return fmt.Errorf("entry %d has been tampered, hash mismatch", e.Index)
+			}
+		default:
+			// the Hash of a pruned entry is recomputed from its Leaf as well, so the entries
+			// before it can't be rewritten
+			if !e.Pruned && issuanceLogLeaf(e) != e.Leaf {
+				return fmt.Errorf("entry %d has been tampered, leaf mismatch", e.Index)
+			}
+			if chainIssuanceLogLeaf(chainHash, e.Leaf) != e.Hash {
+				return fmt.Errorf("entry %d has been tampered, hash mismatch", e.Index)
+			}
+		}
+		chainHash = e.Hash
+	}
+	if len(entries) > 0 && entries[len(entries)-1].Index+1 != head.Size {
+		return fmt.Errorf("the last entry %d does not match the head size %d",
+			entries[len(entries)-1].Index, head.Size)
+	}
+	if chainHash != head.Hash {
+		return errors.New("the hash of the issuance log does not match the signed head")
+	}
+	return nil
+}
+
+// verifyIssuanceLogHead verifies the signature of the head by the public key of the CA.
+func verifyIssuanceLogHead(head *IssuanceLogHead, publicKey any) error {
+	var valid bool
+	switch pub := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(pub, issuanceLogHeadDigest(head), head.Signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(pub, crypto.SHA256, issuanceLogHeadDigest(head), head.Signature) == nil
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(pub, issuanceLogHeadMessage(head), head.Signature)
+	default:
+		return fmt.Errorf("unsupported CA public key type %T", publicKey)
+	}
+	if !valid {
+		return errors.New("invalid signature of the issuance log head")
+	}
+	return nil
+}
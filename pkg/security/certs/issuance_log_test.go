@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyIssuanceLog(t *testing.T) {
+	h := GetCAHandler(CAHandlerTypeX509)
+	caKey, err := h.GenPrivateKey()
+	assert.NoError(t, err)
+	ca, err := h.NewSelfSigned(caKey)
+	assert.NoError(t, err)
+
+	var entries []IssuanceLogEntry
+	var prev string
+	for i := 0; i < 5; i++ {
+		e := NewIssuanceLogEntry(uint64(i), time.Now(), fmt.Sprintf("node%d", i), "10.0.0.1",
+			fmt.Sprintf("%d", 1000+i), []byte(fmt.Sprintf("cert%d", i)), time.Time{}, "", prev)
+		entries = append(entries, e)
+		prev = e.Hash
+	}
+	caSigner, err := caKey.Signer()
+	assert.NoError(t, err)
+	head, err := SignIssuanceLogHead(uint64(len(entries)), prev, caSigner)
+	assert.NoError(t, err)
+
+	t.Run("verify the whole log", func(t *testing.T) {
+		assert.NoError(t, VerifyIssuanceLog("", entries, head, ca.Bytes))
+	})
+
+	t.Run("verify a segment", func(t *testing.T) {
+		assert.NoError(t, VerifyIssuanceLog(entries[1].Hash, entries[2:], head, ca.Bytes))
+	})
+
+	t.Run("tampered entry", func(t *testing.T) {
+		tampered := append([]IssuanceLogEntry{}, entries...)
+		tampered[2].NodeName = "evil"
+		err := VerifyIssuanceLog("", tampered, head, ca.Bytes)
+		assert.ErrorContains(t, err, "entry 2 has been tampered")
+	})
+
+	t.Run("removed entry", func(t *testing.T) {
+		removed := append(append([]IssuanceLogEntry{}, entries[:2]...), entries[3:]...)
+		err := VerifyIssuanceLog("", removed, head, ca.Bytes)
+		assert.Error(t, err)
+	})
+
+	t.Run("pruned entry", func(t *testing.T) {
+		pruned := append([]IssuanceLogEntry{}, entries...)
+		pruned[1] = PruneIssuanceLogEntry(pruned[1])
+		pruned[2] = PruneIssuanceLogEntry(pruned[2])
+		assert.NoError(t, VerifyIssuanceLog("", pruned, head, ca.Bytes))
+
+		// the entries after a pruned entry are still covered by the chain
+		pruned[3].NodeName = "evil"
+		err := VerifyIssuanceLog("", pruned, head, ca.Bytes)
+		assert.ErrorContains(t, err, "entry 3 has been tampered")
+	})
+
+	t.Run("rewritten entry before a pruned entry", func(t *testing.T) {
+		rewritten := append([]IssuanceLogEntry{}, entries...)
+		// the rewritten entry is consistent by itself, but the pruned entry is not
+		// chained to it
+		rewritten[1] = NewIssuanceLogEntry(1, time.Now(), "evil", "10.0.0.1", "1001", []byte("evil"),
+			time.Time{}, "", entries[0].Hash)
+		rewritten[2] = PruneIssuanceLogEntry(rewritten[2])
+		err := VerifyIssuanceLog("", rewritten, head, ca.Bytes)
+		assert.ErrorContains(t, err, "entry 2 has been tampered")
+
+		// so is a forged leaf of the pruned entry
+		forged := append([]IssuanceLogEntry{}, entries...)
+		forged[2] = PruneIssuanceLogEntry(forged[2])
+		forged[2].Leaf = entries[3].Leaf
+		err = VerifyIssuanceLog("", forged, head, ca.Bytes)
+		assert.ErrorContains(t, err, "entry 2 has been tampered")
+	})
+
+	t.Run("entries without leaf", func(t *testing.T) {
+		var legacy []IssuanceLogEntry
+		var prev string
+		for _, e := range entries {
+			e.Leaf = ""
+			e.Hash = legacyIssuanceLogHash(prev, e)
+			legacy = append(legacy, e)
+			prev = e.Hash
+		}
+		legacyHead, err := SignIssuanceLogHead(uint64(len(legacy)), prev, caSigner)
+		assert.NoError(t, err)
+		assert.NoError(t, VerifyIssuanceLog("", legacy, legacyHead, ca.Bytes))
+		assert.False(t, CanPruneIssuanceLogEntry(legacy[1]))
+
+		legacy[1].NodeName = "evil"
+		err = VerifyIssuanceLog("", legacy, legacyHead, ca.Bytes)
+		assert.ErrorContains(t, err, "entry 1 has been tampered")
+
+		legacy[1] = PruneIssuanceLogEntry(legacy[1])
+		err = VerifyIssuanceLog("", legacy, legacyHead, ca.Bytes)
+		assert.ErrorContains(t, err, "the leaf digest of the pruned entry 1 is missing")
+	})
+
+	t.Run("tampered head", func(t *testing.T) {
+		tamperedHead := *head
+		tamperedHead.Hash = entries[3].Hash
+		tamperedHead.Size = 4
+		err := VerifyIssuanceLog("", entries[:4], &tamperedHead, ca.Bytes)
+		assert.ErrorContains(t, err, "invalid signature")
+	})
+
+	t.Run("head signed by another CA", func(t *testing.T) {
+		otherKey, err := h.GenPrivateKey()
+		assert.NoError(t, err)
+		otherSigner, err := otherKey.Signer()
+		assert.NoError(t, err)
+		otherHead, err := SignIssuanceLogHead(head.Size, head.Hash, otherSigner)
+		assert.NoError(t, err)
+		err = VerifyIssuanceLog("", entries, otherHead, ca.Bytes)
+		assert.ErrorContains(t, err, "invalid signature")
+	})
+}
+
+func TestNewIssuedCertLogEntry(t *testing.T) {
+	now := time.Now()
+	cert := &x509.Certificate{
+		Raw:                []byte("cert"),
+		SerialNumber:       big.NewInt(1000),
+		NotBefore:          now.Add(-time.Minute),
+		NotAfter:           now.Add(time.Hour),
+		SignatureAlgorithm: x509.ECDSAWithSHA256,
+		KeyUsage:           x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:        []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	e := NewIssuedCertLogEntry(0, now, "node", "10.0.0.1", IssuanceAuthCertificate, cert, "")
+	assert.Equal(t, "1000", e.Serial)
+	assert.True(t, cert.NotBefore.Equal(*e.NotBefore))
+	assert.True(t, cert.NotAfter.Equal(*e.NotAfter))
+	assert.Equal(t, []string{"DigitalSignature", "KeyEncipherment"}, e.KeyUsages)
+	assert.Equal(t, []string{"ServerAuth", "ClientAuth"}, e.ExtKeyUsages)
+	assert.Equal(t, IssuanceAuthCertificate, e.Authentication)
+	assert.Equal(t, e, chainIssuanceLogEntry("", e))
+
+	// the recorded details are covered by the hash
+	tampered := e
+	tampered.Authentication = IssuanceAuthToken
+	assert.NotEqual(t, e.Hash, chainIssuanceLogEntry("", tampered).Hash)
+	tampered = e
+	tampered.ExtKeyUsages = []string{"ClientAuth"}
+	assert.NotEqual(t, e.Hash, chainIssuanceLogEntry("", tampered).Hash)
+}
+
+func TestVerifyIssuanceLogKeyTypes(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	for name, key := range map[string]crypto.Signer{"RSA": rsaKey, "Ed25519": ed25519Key} {
+		t.Run(name, func(t *testing.T) {
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "KubeEdge"},
+				NotBefore:             time.Now(),
+				NotAfter:              time.Now().Add(time.Hour),
+				KeyUsage:              x509.KeyUsageCertSign,
+				BasicConstraintsValid: true,
+				IsCA:                  true,
+			}
+			caDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+			assert.NoError(t, err)
+
+			e := NewIssuanceLogEntry(0, time.Now(), "node", "10.0.0.1", "1000", []byte("cert"), time.Time{}, "", "")
+			head, err := SignIssuanceLogHead(1, e.Hash, key)
+			assert.NoError(t, err)
+			assert.NoError(t, VerifyIssuanceLog("", []IssuanceLogEntry{e}, head, caDER))
+
+			tamperedHead := *head
+			tamperedHead.Size = 2
+			err = VerifyIssuanceLog("", []IssuanceLogEntry{e}, &tamperedHead, caDER)
+			assert.ErrorContains(t, err, "invalid signature")
+		})
+	}
+}
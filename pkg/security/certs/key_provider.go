@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// KeyProviderFile is the name of the key provider which loads the PEM encoded keys from the
+// local files, the key URI is the path of the file, optionally prefixed with file://.
+const KeyProviderFile = "file"
+
+// KeyProvider provides the signers of the private keys held in a key store, such as a KMS or an
+// HSM, so that the keys never have to be loaded into the memory of CloudCore.
+type KeyProvider interface {
+	// Signer returns the signer of the key identified by the key URI.
+	Signer(keyURI string) (crypto.Signer, error)
+}
+
+var (
+	keyProvidersMu sync.RWMutex
+	keyProviders   = map[string]KeyProvider{
+		KeyProviderFile: fileKeyProvider{},
+	}
+)
+
+// RegisterKeyProvider registers the key provider with the name, e.g. a PKCS #11 or a KMS provider
+// built into CloudCore. It panics if the name is already registered.
+func RegisterKeyProvider(name string, p KeyProvider) {
+	keyProvidersMu.Lock()
+	defer keyProvidersMu.Unlock()
+	if _, ok := keyProviders[name]; ok {
+		panic(fmt.Sprintf("the key provider %q is already registered", name))
+	}
+	keyProviders[name] = p
+}
+
+// GetKeyProvider returns the key provider registered with the name.
+func GetKeyProvider(name string) (KeyProvider, error) {
+	keyProvidersMu.RLock()
+	defer keyProvidersMu.RUnlock()
+	p, ok := keyProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown key provider %q", name)
+	}
+	return p, nil
+}
+
+type fileKeyProvider struct{}
+
+func (fileKeyProvider) Signer(keyURI string) (crypto.Signer, error) {
+	file := strings.TrimPrefix(keyURI, "file://")
+	if file == "" {
+		return nil, errors.New("the path of the key file is empty")
+	}
+	block, err := ReadPEMFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the key file %s, err: %v", file, err)
+	}
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block is found in the key file %s", file)
+	}
+	return PrivateKeySigner(block.Bytes)
+}
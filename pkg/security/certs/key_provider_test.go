@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeKeyProvider struct {
+	signer crypto.Signer
+}
+
+func (p fakeKeyProvider) Signer(string) (crypto.Signer, error) {
+	return p.signer, nil
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "ca.key")
+	assert.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+
+	p, err := GetKeyProvider(KeyProviderFile)
+	assert.NoError(t, err)
+	for _, uri := range []string{file, "file://" + file} {
+		signer, err := p.Signer(uri)
+		assert.NoError(t, err)
+		assert.True(t, key.PublicKey.Equal(signer.Public()))
+	}
+
+	_, err = p.Signer("")
+	assert.Error(t, err)
+	_, err = p.Signer(filepath.Join(t.TempDir(), "nonexistent.key"))
+	assert.Error(t, err)
+}
+
+func TestRegisterKeyProvider(t *testing.T) {
+	_, err := GetKeyProvider("fake")
+	assert.ErrorContains(t, err, `unknown key provider "fake"`)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	RegisterKeyProvider("fake", fakeKeyProvider{signer: key})
+	defer func() {
+		keyProvidersMu.Lock()
+		delete(keyProviders, "fake")
+		keyProvidersMu.Unlock()
+	}()
+
+	p, err := GetKeyProvider("fake")
+	assert.NoError(t, err)
+	signer, err := p.Signer("kms://key")
+	assert.NoError(t, err)
+	assert.Equal(t, key, signer)
+
+	assert.Panics(t, func() {
+		RegisterKeyProvider(KeyProviderFile, fakeKeyProvider{})
+	})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// CheckEdgeCSRSANs checks the SANs of the edge CSR, which are copied into the certificate. A DNS
+// or IP SAN is allowed if it is one of the addresses of the node, or a loopback address, i.e.
+// localhost, 127.0.0.0/8 or ::1, if allowLoopback is true. A DNS SAN is allowed if it is under one
+// of the allowed DNS suffixes too. The other types of SANs are not allowed. The error names the
+// first SAN which is not allowed.
+func CheckEdgeCSRSANs(csr *x509.CertificateRequest, addresses, allowedDNSSuffixes []string, allowLoopback bool) error {
+	var hostnames []string
+	var ips []net.IP
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			hostnames = append(hostnames, address)
+		}
+	}
+	for _, name := range csr.DNSNames {
+		if !allowedDNSName(name, hostnames, allowedDNSSuffixes, allowLoopback) {
+			return fmt.Errorf("the DNS SAN %q of the CSR is not an address of the node", name)
+		}
+	}
+	for _, ip := range csr.IPAddresses {
+		if !allowedIP(ip, ips, allowLoopback) {
+			return fmt.Errorf("the IP SAN %s of the CSR is not an address of the node", ip)
+		}
+	}
+	if len(csr.EmailAddresses) > 0 {
+		return fmt.Errorf("the email SAN %q of the CSR is not allowed", csr.EmailAddresses[0])
+	}
+	if len(csr.URIs) > 0 {
+		return fmt.Errorf("the URI SAN %q of the CSR is not allowed", csr.URIs[0])
+	}
+	return nil
+}
+
+func allowedDNSName(name string, hostnames, suffixes []string, allowLoopback bool) bool {
+	if allowLoopback && strings.EqualFold(name, "localhost") {
+		return true
+	}
+	for _, hostname := range hostnames {
+		if strings.EqualFold(name, hostname) {
+			return true
+		}
+	}
+	name = strings.ToLower(name)
+	for _, suffix := range suffixes {
+		suffix = strings.ToLower(strings.TrimPrefix(suffix, "."))
+		if suffix != "" && strings.HasSuffix(name, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func allowedIP(ip net.IP, ips []net.IP, allowLoopback bool) bool {
+	if allowLoopback && ip.IsLoopback() {
+		return true
+	}
+	for _, i := range ips {
+		if i.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/x509"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckEdgeCSRSANs(t *testing.T) {
+	addresses := []string{"edge-01", "edge-01.internal", "192.168.1.10", "2001:db8::10"}
+	suffixes := []string{"edge.example.com"}
+	cases := []struct {
+		name          string
+		csr           x509.CertificateRequest
+		addresses     []string
+		allowLoopback bool
+		wantErr       string
+	}{
+		{
+			name:      "no SANs",
+			addresses: addresses,
+		},
+		{
+			name: "addresses of the node",
+			csr: x509.CertificateRequest{
+				DNSNames:    []string{"Edge-01", "edge-01.internal"},
+				IPAddresses: []net.IP{net.ParseIP("192.168.1.10"), net.ParseIP("2001:0db8:0000::0010")},
+			},
+			addresses: addresses,
+		},
+		{
+			name: "under the allowed suffix",
+			csr: x509.CertificateRequest{
+				DNSNames: []string{"node1.edge.example.com", "*.edge.example.com"},
+			},
+			addresses: addresses,
+		},
+		{
+			name: "not a subdomain of the allowed suffix",
+			csr: x509.CertificateRequest{
+				DNSNames: []string{"node1.myedge.example.com"},
+			},
+			addresses: addresses,
+			wantErr:   `the DNS SAN "node1.myedge.example.com" of the CSR is not an address of the node`,
+		},
+		{
+			name: "unknown IPv6 address",
+			csr: x509.CertificateRequest{
+				IPAddresses: []net.IP{net.ParseIP("2001:db8::11")},
+			},
+			addresses: addresses,
+			wantErr:   "the IP SAN 2001:db8::11 of the CSR is not an address of the node",
+		},
+		{
+			name: "loopback addresses without the node",
+			csr: x509.CertificateRequest{
+				DNSNames:    []string{"localhost"},
+				IPAddresses: []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+			},
+			allowLoopback: true,
+		},
+		{
+			name: "localhost not allowed",
+			csr: x509.CertificateRequest{
+				DNSNames: []string{"LocalHost"},
+			},
+			addresses: addresses,
+			wantErr:   `the DNS SAN "LocalHost" of the CSR is not an address of the node`,
+		},
+		{
+			name: "loopback IP not allowed",
+			csr: x509.CertificateRequest{
+				IPAddresses: []net.IP{net.ParseIP("127.0.0.2")},
+			},
+			addresses: addresses,
+			wantErr:   "the IP SAN 127.0.0.2 of the CSR is not an address of the node",
+		},
+		{
+			name: "email SAN",
+			csr: x509.CertificateRequest{
+				EmailAddresses: []string{"ops@example.com"},
+			},
+			addresses: addresses,
+			wantErr:   `the email SAN "ops@example.com" of the CSR is not allowed`,
+		},
+		{
+			name: "URI SAN",
+			csr: x509.CertificateRequest{
+				URIs: []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/edge"}},
+			},
+			addresses: addresses,
+			wantErr:   `the URI SAN "spiffe://example.com/edge" of the CSR is not allowed`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := CheckEdgeCSRSANs(&c.csr, c.addresses, suffixes, c.allowLoopback)
+			if c.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, c.wantErr)
+			}
+		})
+	}
+}
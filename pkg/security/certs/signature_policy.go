@@ -0,0 +1,192 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"slices"
+)
+
+// The key types of the CA keys that the preferred digests are configured for.
+const (
+	KeyTypeRSA       = "RSA"
+	KeyTypeECDSAP256 = "ECDSA-P256"
+	KeyTypeECDSAP384 = "ECDSA-P384"
+	KeyTypeECDSAP521 = "ECDSA-P521"
+	KeyTypeEd25519   = "Ed25519"
+)
+
+// The digests that can be preferred for a key type.
+const (
+	DigestSHA256 = "SHA256"
+	DigestSHA384 = "SHA384"
+	DigestSHA512 = "SHA512"
+)
+
+// weakSignatureAlgorithms are never allowed, no matter what the policy is.
+var weakSignatureAlgorithms = []x509.SignatureAlgorithm{
+	x509.MD2WithRSA,
+	x509.MD5WithRSA,
+	x509.SHA1WithRSA,
+	x509.DSAWithSHA1,
+	x509.DSAWithSHA256,
+	x509.ECDSAWithSHA1,
+}
+
+// defaultDigests are the digests used for the key types if they are not configured,
+// Ed25519 keys sign the messages without a separate digest.
+var defaultDigests = map[string]string{
+	KeyTypeRSA:       DigestSHA256,
+	KeyTypeECDSAP256: DigestSHA256,
+	KeyTypeECDSAP384: DigestSHA384,
+	KeyTypeECDSAP521: DigestSHA512,
+}
+
+var ecdsaSignatureAlgorithms = map[string]x509.SignatureAlgorithm{
+	DigestSHA256: x509.ECDSAWithSHA256,
+	DigestSHA384: x509.ECDSAWithSHA384,
+	DigestSHA512: x509.ECDSAWithSHA512,
+}
+
+// signatureAlgorithms maps the key types and the digests to the signature algorithms.
+var signatureAlgorithms = map[string]map[string]x509.SignatureAlgorithm{
+	KeyTypeRSA: {
+		DigestSHA256: x509.SHA256WithRSA,
+		DigestSHA384: x509.SHA384WithRSA,
+		DigestSHA512: x509.SHA512WithRSA,
+	},
+	KeyTypeECDSAP256: ecdsaSignatureAlgorithms,
+	KeyTypeECDSAP384: ecdsaSignatureAlgorithms,
+	KeyTypeECDSAP521: ecdsaSignatureAlgorithms,
+	KeyTypeEd25519: {
+		"": x509.PureEd25519,
+	},
+}
+
+// SignaturePolicy is the policy of the signature algorithms of the issued certificates
+// and the CSRs. A nil policy allows all the algorithms except the weak ones.
+type SignaturePolicy struct {
+	// Allowed are the names of the allowed signature algorithms, such as ECDSA-SHA384 and
+	// SHA256-RSA. All the algorithms except the SHA-1 and MD5 based ones are allowed if it is empty.
+	Allowed []string
+	// Digests are the preferred digests of the signatures per CA key type, such as SHA384
+	// for ECDSA-P384. The digest of a key type is not configurable for Ed25519.
+	Digests map[string]string
+}
+
+// KeyType returns the key type of the public key in the names of the signature policy.
+func KeyType(publicKey any) (string, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return KeyTypeRSA, nil
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return KeyTypeECDSAP256, nil
+		case elliptic.P384():
+			return KeyTypeECDSAP384, nil
+		case elliptic.P521():
+			return KeyTypeECDSAP521, nil
+		}
+		return "", fmt.Errorf("unsupported ECDSA curve %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return KeyTypeEd25519, nil
+	}
+	return "", fmt.Errorf("unsupported public key type %T", publicKey)
+}
+
+// Validate checks that the allowed algorithms are known and not weak, and the preferred
+// digests select signature algorithms which are allowed by the policy.
+func (p *SignaturePolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, name := range p.Allowed {
+		algo, ok := signatureAlgorithmByName(name)
+		if !ok {
+			return fmt.Errorf("unknown signature algorithm %q", name)
+		}
+		if slices.Contains(weakSignatureAlgorithms, algo) {
+			return fmt.Errorf("the signature algorithm %s is too weak to be allowed", name)
+		}
+	}
+	for keyType := range p.Digests {
+		if _, err := p.signatureAlgorithm(keyType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SelectSignatureAlgorithm selects the signature algorithm of the certificates signed by
+// the CA key, by the preferred digest of the key type.
+func (p *SignaturePolicy) SelectSignatureAlgorithm(caPublicKey any) (x509.SignatureAlgorithm, error) {
+	keyType, err := KeyType(caPublicKey)
+	if err != nil {
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("failed to select the signature algorithm of the CA, err: %v", err)
+	}
+	return p.signatureAlgorithm(keyType)
+}
+
+func (p *SignaturePolicy) signatureAlgorithm(keyType string) (x509.SignatureAlgorithm, error) {
+	algos, ok := signatureAlgorithms[keyType]
+	if !ok {
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("unknown key type %q", keyType)
+	}
+	digest := defaultDigests[keyType]
+	if p != nil && p.Digests[keyType] != "" {
+		digest = p.Digests[keyType]
+	}
+	algo, ok := algos[digest]
+	if !ok {
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("the digest %s can not be selected for %s keys", digest, keyType)
+	}
+	if !p.allows(algo) {
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("the signature algorithm %s selected for %s keys is not allowed", algo, keyType)
+	}
+	return algo, nil
+}
+
+// CheckCSR checks that the CSR is self-signed with an allowed signature algorithm.
+func (p *SignaturePolicy) CheckCSR(csr *x509.CertificateRequest) error {
+	if !p.allows(csr.SignatureAlgorithm) {
+		return fmt.Errorf("the signature algorithm %s of the CSR is not allowed", csr.SignatureAlgorithm)
+	}
+	return nil
+}
+
+func (p *SignaturePolicy) allows(algo x509.SignatureAlgorithm) bool {
+	if algo == x509.UnknownSignatureAlgorithm || slices.Contains(weakSignatureAlgorithms, algo) {
+		return false
+	}
+	return p == nil || len(p.Allowed) == 0 || slices.Contains(p.Allowed, algo.String())
+}
+
+func signatureAlgorithmByName(name string) (x509.SignatureAlgorithm, bool) {
+	// x509.SignatureAlgorithm has no parser, the known algorithms are enumerated
+	for algo := x509.MD2WithRSA; algo <= x509.PureEd25519; algo++ {
+		if algo.String() == name {
+			return algo, true
+		}
+	}
+	return x509.UnknownSignatureAlgorithm, false
+}
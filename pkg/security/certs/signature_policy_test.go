@@ -0,0 +1,167 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/kubeedge/cloud/pkg/cloudhub/servers/httpserver/testutil"
+	"github.com/kubeedge/kubeedge/pkg/security/certs"
+)
+
+// newTestCA creates a CA with a key of the key type by testutil.NewCAWithKey, and returns the CA
+// and its key in DER.
+func newTestCA(t *testing.T, keyType string) ([]byte, []byte) {
+	key, err := certs.GenPrivateKey(keyType)
+	require.NoError(t, err)
+	ca := testutil.NewCAWithKey(t, key)
+	return ca.Cert.Raw, ca.Key.DER()
+}
+
+func newTestCSR(t *testing.T, key crypto.Signer, algo x509.SignatureAlgorithm) []byte {
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:            pkix.Name{CommonName: "node1"},
+		SignatureAlgorithm: algo,
+	}, key)
+	require.NoError(t, err)
+	return csrDER
+}
+
+func newTestSigner(t *testing.T, keyType string) crypto.Signer {
+	key, err := certs.GenPrivateKey(keyType)
+	require.NoError(t, err)
+	signer, err := key.Signer()
+	require.NoError(t, err)
+	return signer
+}
+
+func TestSignCertsWithSignaturePolicy(t *testing.T) {
+	csrDER := newTestCSR(t, newTestSigner(t, certs.KeyTypeECDSAP256), x509.ECDSAWithSHA256)
+	usages := []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	handler := certs.GetHandler(certs.HandlerTypeX509)
+
+	cases := []struct {
+		name    string
+		keyType string
+		policy  *certs.SignaturePolicy
+		want    x509.SignatureAlgorithm
+	}{
+		{name: "RSA CA", keyType: certs.KeyTypeRSA, want: x509.SHA256WithRSA},
+		{name: "ECDSA P-256 CA", keyType: certs.KeyTypeECDSAP256, want: x509.ECDSAWithSHA256},
+		{name: "ECDSA P-384 CA", keyType: certs.KeyTypeECDSAP384, want: x509.ECDSAWithSHA384},
+		{name: "ECDSA P-521 CA", keyType: certs.KeyTypeECDSAP521, want: x509.ECDSAWithSHA512},
+		{name: "Ed25519 CA", keyType: certs.KeyTypeEd25519, want: x509.PureEd25519},
+		{
+			name:    "RSA CA with preferred SHA512",
+			keyType: certs.KeyTypeRSA,
+			policy:  &certs.SignaturePolicy{Digests: map[string]string{certs.KeyTypeRSA: certs.DigestSHA512}},
+			want:    x509.SHA512WithRSA,
+		},
+		{
+			name:    "ECDSA P-256 CA with preferred SHA384",
+			keyType: certs.KeyTypeECDSAP256,
+			policy:  &certs.SignaturePolicy{Digests: map[string]string{certs.KeyTypeECDSAP256: certs.DigestSHA384}},
+			want:    x509.ECDSAWithSHA384,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			caDER, caKeyDER := newTestCA(t, c.keyType)
+			block, err := handler.SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+				csrDER, caDER, caKeyDER, usages, time.Hour).WithSignaturePolicy(c.policy))
+			require.NoError(t, err)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			require.NoError(t, err)
+			assert.Equal(t, c.want, cert.SignatureAlgorithm)
+		})
+	}
+
+	t.Run("SHA-1 signed CSR is rejected", func(t *testing.T) {
+		caDER, caKeyDER := newTestCA(t, certs.KeyTypeECDSAP256)
+		sha1CSR := newTestCSR(t, newTestSigner(t, certs.KeyTypeRSA), x509.SHA1WithRSA)
+		_, err := handler.SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+			sha1CSR, caDER, caKeyDER, usages, time.Hour).WithSignaturePolicy(nil))
+		assert.ErrorContains(t, err, "SHA1-RSA of the CSR is not allowed")
+	})
+
+	t.Run("CSR algorithm not in the allowed list is rejected", func(t *testing.T) {
+		caDER, caKeyDER := newTestCA(t, certs.KeyTypeECDSAP384)
+		policy := &certs.SignaturePolicy{Allowed: []string{"ECDSA-SHA384"}}
+		_, err := handler.SignCerts(context.TODO(), certs.SignCertsOptionsWithCSR(
+			csrDER, caDER, caKeyDER, usages, time.Hour).WithSignaturePolicy(policy))
+		assert.ErrorContains(t, err, "ECDSA-SHA256 of the CSR is not allowed")
+	})
+}
+
+func TestSignaturePolicyValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  *certs.SignaturePolicy
+		wantErr string
+	}{
+		{name: "nil policy"},
+		{
+			name: "valid policy",
+			policy: &certs.SignaturePolicy{
+				Allowed: []string{"ECDSA-SHA384", "SHA384-RSA"},
+				Digests: map[string]string{certs.KeyTypeECDSAP256: certs.DigestSHA384, certs.KeyTypeRSA: certs.DigestSHA384},
+			},
+		},
+		{
+			name:    "Ed25519 with SHA384",
+			policy:  &certs.SignaturePolicy{Digests: map[string]string{certs.KeyTypeEd25519: certs.DigestSHA384}},
+			wantErr: "the digest SHA384 can not be selected for Ed25519 keys",
+		},
+		{
+			name:    "weak algorithm",
+			policy:  &certs.SignaturePolicy{Allowed: []string{"SHA1-RSA"}},
+			wantErr: "too weak",
+		},
+		{
+			name:    "unknown algorithm",
+			policy:  &certs.SignaturePolicy{Allowed: []string{"SHA3-RSA"}},
+			wantErr: "unknown signature algorithm",
+		},
+		{
+			name: "selected algorithm not allowed",
+			policy: &certs.SignaturePolicy{
+				Allowed: []string{"ECDSA-SHA256"},
+				Digests: map[string]string{certs.KeyTypeECDSAP384: certs.DigestSHA384},
+			},
+			wantErr: "ECDSA-SHA384 selected for ECDSA-P384 keys is not allowed",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.policy.Validate()
+			if c.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, c.wantErr)
+		})
+	}
+}
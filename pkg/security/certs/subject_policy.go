@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	oidCommonName   = asn1.ObjectIdentifier{2, 5, 4, 3}
+	oidOrganization = asn1.ObjectIdentifier{2, 5, 4, 10}
+)
+
+// subjectAttributeOIDs are the OIDs of the Subject attributes by their short names.
+var subjectAttributeOIDs = map[string]asn1.ObjectIdentifier{
+	"C":            {2, 5, 4, 6},
+	"ST":           {2, 5, 4, 8},
+	"L":            {2, 5, 4, 7},
+	"STREET":       {2, 5, 4, 9},
+	"POSTALCODE":   {2, 5, 4, 17},
+	"OU":           {2, 5, 4, 11},
+	"SERIALNUMBER": {2, 5, 4, 5},
+	"emailAddress": {1, 2, 840, 113549, 1, 9, 1},
+}
+
+// ParseSubjectAttributes parses the Subject attributes in the short names, such as L and OU,
+// or in the dotted OIDs. The CommonName and the Organization are the node identity, which
+// can not be listed.
+func ParseSubjectAttributes(names []string) ([]asn1.ObjectIdentifier, error) {
+	oids := make([]asn1.ObjectIdentifier, 0, len(names))
+	for _, name := range names {
+		oid, ok := subjectAttributeOIDs[name]
+		if !ok {
+			var err error
+			if oid, err = parseOID(name); err != nil {
+				return nil, fmt.Errorf("unknown Subject attribute %q, err: %v", name, err)
+			}
+		}
+		if oid.Equal(oidCommonName) || oid.Equal(oidOrganization) {
+			return nil, fmt.Errorf("the Subject attribute %q is the node identity", name)
+		}
+		oids = append(oids, oid)
+	}
+	return oids, nil
+}
+
+func parseOID(s string) (asn1.ObjectIdentifier, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return nil, errors.New("neither a short name nor a dotted OID")
+	}
+	oid := make(asn1.ObjectIdentifier, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid OID component %q", part)
+		}
+		oid = append(oid, n)
+	}
+	return oid, nil
+}
+
+// FilterCSRSubject splits the Subject attributes of the CSR other than the CommonName and the
+// Organization into the allowed ones and the OIDs of the others.
+func FilterCSRSubject(csr *x509.CertificateRequest, allowed []asn1.ObjectIdentifier) ([]pkix.AttributeTypeAndValue, []asn1.ObjectIdentifier) {
+	var kept []pkix.AttributeTypeAndValue
+	var others []asn1.ObjectIdentifier
+	for _, name := range csr.Subject.Names {
+		if name.Type.Equal(oidCommonName) || name.Type.Equal(oidOrganization) {
+			continue
+		}
+		if containsOID(allowed, name.Type) {
+			kept = append(kept, name)
+		} else if !containsOID(others, name.Type) {
+			others = append(others, name.Type)
+		}
+	}
+	return kept, others
+}
+
+func containsOID(oids []asn1.ObjectIdentifier, oid asn1.ObjectIdentifier) bool {
+	for _, o := range oids {
+		if o.Equal(oid) {
+			return true
+		}
+	}
+	return false
+}
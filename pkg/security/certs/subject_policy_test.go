@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSubjectAttributes(t *testing.T) {
+	oids, err := ParseSubjectAttributes([]string{"L", "emailAddress", "1.3.6.1.4.1.55555.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []asn1.ObjectIdentifier{
+		{2, 5, 4, 7}, {1, 2, 840, 113549, 1, 9, 1}, {1, 3, 6, 1, 4, 1, 55555, 1},
+	}, oids)
+
+	_, err = ParseSubjectAttributes([]string{"Locality"})
+	assert.ErrorContains(t, err, `unknown Subject attribute "Locality"`)
+	_, err = ParseSubjectAttributes([]string{"1.3.x"})
+	assert.ErrorContains(t, err, `invalid OID component "x"`)
+	_, err = ParseSubjectAttributes([]string{"2.5.4.3"})
+	assert.ErrorContains(t, err, "is the node identity")
+}
+
+func TestFilterCSRSubject(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	custom := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 55555, 1}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   EdgeNodeCommonNamePrefix + "testnode",
+			Organization: []string{EdgeNodeOrganization},
+			Locality:     []string{"Berlin"},
+			ExtraNames:   []pkix.AttributeTypeAndValue{{Type: custom, Value: "custom"}},
+		},
+	}, key)
+	assert.NoError(t, err)
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	assert.NoError(t, err)
+
+	kept, others := FilterCSRSubject(csr, nil)
+	assert.Empty(t, kept)
+	assert.Equal(t, []asn1.ObjectIdentifier{{2, 5, 4, 7}, custom}, others)
+
+	kept, others = FilterCSRSubject(csr, []asn1.ObjectIdentifier{custom})
+	assert.Equal(t, []pkix.AttributeTypeAndValue{{Type: custom, Value: "custom"}}, kept)
+	assert.Equal(t, []asn1.ObjectIdentifier{{2, 5, 4, 7}}, others)
+}
@@ -16,10 +16,12 @@ limitations under the License.
 package certs
 
 import (
+	"context"
 	"crypto"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"net/url"
 	"time"
 
 	certutil "k8s.io/client-go/util/cert"
@@ -46,17 +48,33 @@ type Handler interface {
 	// CreateCSR create a certificate request, returns a pem block.
 	CreateCSR(sub pkix.Name, pkw PrivateKeyWrap, alt *certutil.AltNames) (*pem.Block, error)
 
-	// SignCerts creates a certificate, returns a pem block.
-	SignCerts(opts SignCertsOptions) (*pem.Block, error)
+	// SignCerts creates a certificate, returns a pem block. The handler should give up and
+	// return the error of the ctx when it is done, e.g. a remote signer times out.
+	SignCerts(ctx context.Context, opts SignCertsOptions) (*pem.Block, error)
 }
 
 type SignCertsOptions struct {
-	cfg        certutil.Config
-	caDER      []byte
-	caKeyDER   []byte
+	cfg      certutil.Config
+	caDER    []byte
+	caKeyDER []byte
+	// caSigner signs the certificate instead of caKeyDER if it is set
+	caSigner   crypto.Signer
 	csrDER     []byte
 	publicKey  any
 	expiration time.Duration
+	keyUsage   x509.KeyUsage
+	// signaturePolicy selects and enforces the signature algorithms if it is set
+	signaturePolicy *SignaturePolicy
+	// extraSubjectNames are the Subject attributes of the certificate besides CommonName and Organization
+	extraSubjectNames []pkix.AttributeTypeAndValue
+	// backdate moves NotBefore of the certificate into the past, see WithBackdate
+	backdate time.Duration
+	// uris are the URI SANs of the certificate besides the SANs of the CSR
+	uris []*url.URL
+	// ocspServers are the URLs of the OCSP responders in the AuthorityInfoAccess of the certificate
+	ocspServers []string
+	// extraExtensions are the custom extensions of the certificate, see WithExtraExtensions
+	extraExtensions []pkix.Extension
 }
 
 func SignCertsOptionsWithCA(cfg certutil.Config, caDER, caKeyDER []byte, publicKey any, expiration time.Duration) SignCertsOptions {
@@ -81,6 +99,68 @@ func SignCertsOptionsWithCSR(csrDER, caDER, caKeyDER []byte, usages []x509.ExtKe
 	}
 }
 
+// WithKeyUsage returns a copy of the options with the KeyUsage bits of the certificate,
+// the default bits of the handler are used if it is not set.
+func (o SignCertsOptions) WithKeyUsage(keyUsage x509.KeyUsage) SignCertsOptions {
+	o.keyUsage = keyUsage
+	return o
+}
+
+// WithCASigner returns a copy of the options which signs the certificate with the signer of the
+// CA key instead of the CA key in DER, e.g. a key held by a KMS or an HSM.
+func (o SignCertsOptions) WithCASigner(signer crypto.Signer) SignCertsOptions {
+	o.caSigner = signer
+	return o
+}
+
+// WithSignaturePolicy returns a copy of the options which selects the signature algorithm of
+// the certificate by the policy, and rejects the CSR signed with an algorithm not allowed.
+// A nil policy allows all the algorithms except the weak ones.
+func (o SignCertsOptions) WithSignaturePolicy(policy *SignaturePolicy) SignCertsOptions {
+	if policy == nil {
+		policy = &SignaturePolicy{}
+	}
+	o.signaturePolicy = policy
+	return o
+}
+
+// WithExtraSubjectNames returns a copy of the options which adds the attributes to the Subject of
+// the certificate besides the CommonName and the Organization.
+func (o SignCertsOptions) WithExtraSubjectNames(names []pkix.AttributeTypeAndValue) SignCertsOptions {
+	o.extraSubjectNames = names
+	return o
+}
+
+// WithBackdate returns a copy of the options which sets NotBefore of the certificate the duration
+// before the signing, so it is valid on the nodes whose clocks are behind. NotAfter is moved
+// back as well, the total validity of the certificate is still the expiration.
+func (o SignCertsOptions) WithBackdate(backdate time.Duration) SignCertsOptions {
+	o.backdate = backdate
+	return o
+}
+
+// WithURIs returns a copy of the options which adds the URI SANs to the certificate,
+// e.g. the SPIFFE ID of the edge node, see EdgeNodeSPIFFEID.
+func (o SignCertsOptions) WithURIs(uris ...*url.URL) SignCertsOptions {
+	o.uris = uris
+	return o
+}
+
+// WithOCSPServers returns a copy of the options which adds the URLs of the OCSP responders
+// to the AuthorityInfoAccess of the certificate.
+func (o SignCertsOptions) WithOCSPServers(servers ...string) SignCertsOptions {
+	o.ocspServers = servers
+	return o
+}
+
+// WithExtraExtensions returns a copy of the options which adds the custom extensions to the
+// certificate, e.g. the inventory tags of the edge node. The critical extensions and the ones
+// overriding the standard extensions are refused by the handler, see CheckExtraExtensions.
+func (o SignCertsOptions) WithExtraExtensions(exts ...pkix.Extension) SignCertsOptions {
+	o.extraExtensions = exts
+	return o
+}
+
 func SignCertsOptionsWithK8sCSR(csrDER []byte, usages []x509.ExtKeyUsage, expiration time.Duration) SignCertsOptions {
 	return SignCertsOptions{
 		csrDER: csrDER,
@@ -17,6 +17,7 @@ limitations under the License.
 package certs
 
 import (
+	"context"
 	"crypto"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -143,7 +144,7 @@ func TestHandlerInterface(t *testing.T) {
 		})
 
 	patches.ApplyMethod(reflect.TypeOf(mock), "SignCerts",
-		func(*mockHandler, SignCertsOptions) (*pem.Block, error) {
+		func(*mockHandler, context.Context, SignCertsOptions) (*pem.Block, error) {
 			return nil, nil
 		})
 	defer patches.Reset()
@@ -156,7 +157,7 @@ func TestHandlerInterface(t *testing.T) {
 	assert.Nil(t, csrBlock)
 	assert.Nil(t, err)
 
-	certBlock, err := mock.SignCerts(SignCertsOptions{})
+	certBlock, err := mock.SignCerts(context.TODO(), SignCertsOptions{})
 	assert.Nil(t, certBlock)
 	assert.Nil(t, err)
 }
@@ -217,7 +218,7 @@ func (m *mockHandler) CreateCSR(sub pkix.Name, pkw PrivateKeyWrap, alt *certutil
 	return nil, nil
 }
 
-func (m *mockHandler) SignCerts(opts SignCertsOptions) (*pem.Block, error) {
+func (m *mockHandler) SignCerts(_ context.Context, opts SignCertsOptions) (*pem.Block, error) {
 	return nil, nil
 }
 
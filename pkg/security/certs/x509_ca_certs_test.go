@@ -1,6 +1,7 @@
 package certs
 
 import (
+	"context"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"testing"
@@ -34,7 +35,7 @@ func TestSignX509Certs(t *testing.T) {
 
 	opts := SignCertsOptionsWithCSR(csrblock.Bytes, cablock.Bytes, capkw.DER(),
 		[]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, 24*time.Hour)
-	certblock, err := certh.SignCerts(opts)
+	certblock, err := certh.SignCerts(context.TODO(), opts)
 	if err != nil {
 		t.Fatal(err)
 	}
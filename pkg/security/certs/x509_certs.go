@@ -16,9 +16,9 @@ limitations under the License.
 package certs
 
 import (
-	"crypto/ecdsa"
-	"crypto/elliptic"
+	"context"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
@@ -31,21 +31,16 @@ import (
 	certutil "k8s.io/client-go/util/cert"
 )
 
-type x509CertsHandler struct{}
+type x509CertsHandler struct {
+	// keyType is the type of the private keys generated by the handler, which is KeyTypeECDSAP256 if it is empty
+	keyType string
+}
 
 // check implements Handler
 var _ Handler = (*x509CertsHandler)(nil)
 
 func (h x509CertsHandler) GenPrivateKey() (PrivateKeyWrap, error) {
-	pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate certificate private key, err: %v", err)
-	}
-	keyDER, err := x509.MarshalECPrivateKey(pk)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert an EC private key to SEC 1, ASN.1 DER form, err: %v", err)
-	}
-	return &x509PrivateKeyWrap{der: keyDER}, nil
+	return GenPrivateKey(h.keyType)
 }
 
 func (h x509CertsHandler) CreateCSR(sub pkix.Name, pkw PrivateKeyWrap, alt *certutil.AltNames) (*pem.Block, error) {
@@ -67,13 +62,21 @@ func (h x509CertsHandler) CreateCSR(sub pkix.Name, pkw PrivateKeyWrap, alt *cert
 	return &pem.Block{Type: certutil.CertificateRequestBlockType, Bytes: csrDER}, nil
 }
 
-func (h x509CertsHandler) SignCerts(opts SignCertsOptions) (*pem.Block, error) {
+func (h x509CertsHandler) SignCerts(ctx context.Context, opts SignCertsOptions) (*pem.Block, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	pubkey := opts.publicKey
 	if opts.csrDER != nil {
 		csr, err := x509.ParseCertificateRequest(opts.csrDER)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse csr, err: %v", err)
 		}
+		if opts.signaturePolicy != nil {
+			if err := opts.signaturePolicy.CheckCSR(csr); err != nil {
+				return nil, err
+			}
+		}
 		opts.cfg.CommonName = csr.Subject.CommonName
 		opts.cfg.Organization = csr.Subject.Organization
 		opts.cfg.AltNames.DNSNames = csr.DNSNames
@@ -92,9 +95,11 @@ func (h x509CertsHandler) SignCerts(opts SignCertsOptions) (*pem.Block, error) {
 		return nil, fmt.Errorf("failed to generate serial number, err: %v", err)
 	}
 
-	caKey, err := x509PrivateKeyWrap{der: opts.caKeyDER}.Signer()
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse CA private key, err: %v", err)
+	caKey := opts.caSigner
+	if caKey == nil {
+		if caKey, err = (x509PrivateKeyWrap{der: opts.caKeyDER}).Signer(); err != nil {
+			return nil, fmt.Errorf("failed to parse CA private key, err: %v", err)
+		}
 	}
 
 	ca, err := x509.ParseCertificate(opts.caDER)
@@ -102,18 +107,52 @@ func (h x509CertsHandler) SignCerts(opts SignCertsOptions) (*pem.Block, error) {
 		return nil, fmt.Errorf("failed to parse CA, err: %v", err)
 	}
 
+	if opts.backdate < 0 {
+		return nil, fmt.Errorf("the backdate %v must not be negative", opts.backdate)
+	}
+	if opts.backdate > 0 && opts.backdate >= opts.expiration {
+		return nil, fmt.Errorf("the backdate %v must be shorter than the validity period %v", opts.backdate, opts.expiration)
+	}
+	notBefore := time.Now().Add(-opts.backdate)
+
+	if err := CheckExtraExtensions(opts.extraExtensions); err != nil {
+		return nil, err
+	}
+
+	if _, err := KeyType(pubkey); err != nil {
+		return nil, err
+	}
+	keyUsage := defaultKeyUsage(pubkey)
+	if opts.keyUsage != 0 {
+		keyUsage = opts.keyUsage
+	}
 	certTmpl := x509.Certificate{
 		Subject: pkix.Name{
 			CommonName:   opts.cfg.CommonName,
 			Organization: opts.cfg.Organization,
+			ExtraNames:   opts.extraSubjectNames,
 		},
 		DNSNames:     opts.cfg.AltNames.DNSNames,
 		IPAddresses:  opts.cfg.AltNames.IPs,
+		URIs:         opts.uris,
+		OCSPServer:   opts.ocspServers,
 		SerialNumber: serial,
-		NotBefore:    time.Now().UTC(),
-		NotAfter:     time.Now().Add(opts.expiration),
-		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		NotBefore:    notBefore.UTC(),
+		NotAfter:     notBefore.Add(opts.expiration),
+		KeyUsage:     keyUsage,
 		ExtKeyUsage:  opts.cfg.Usages,
+		// ExtraExtensions are checked not to override the extensions above
+		ExtraExtensions: opts.extraExtensions,
+	}
+	if opts.signaturePolicy != nil {
+		certTmpl.SignatureAlgorithm, err = opts.signaturePolicy.SelectSignatureAlgorithm(caKey.Public())
+		if err != nil {
+			return nil, err
+		}
+	}
+	// the key operation can not be interrupted, skip it if the ctx is done meanwhile
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 	certDER, err := x509.CreateCertificate(rand.Reader, &certTmpl, ca, pubkey, caKey)
 	if err != nil {
@@ -122,3 +161,12 @@ func (h x509CertsHandler) SignCerts(opts SignCertsOptions) (*pem.Block, error) {
 
 	return &pem.Block{Type: certutil.CertificateBlockType, Bytes: certDER}, nil
 }
+
+// defaultKeyUsage returns the KeyUsage bits of the certificate of the public key if they are not
+// specified. The keys other than RSA ones only sign, KeyEncipherment is not applicable to them.
+func defaultKeyUsage(publicKey any) x509.KeyUsage {
+	if _, ok := publicKey.(*rsa.PublicKey); ok {
+		return x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature
+	}
+	return x509.KeyUsageDigitalSignature
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package certs
 
 import (
+	"context"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -32,6 +33,7 @@ import (
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/stretchr/testify/assert"
 	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
 )
 
 type MockPrivateKeyWrap struct {
@@ -130,6 +132,59 @@ func TestGenPrivateKey(t *testing.T) {
 	})
 }
 
+func TestGenPrivateKeyByType(t *testing.T) {
+	for keyType, want := range map[string]string{
+		"":               KeyTypeECDSAP256,
+		KeyTypeRSA:       KeyTypeRSA,
+		KeyTypeECDSAP256: KeyTypeECDSAP256,
+		KeyTypeECDSAP384: KeyTypeECDSAP384,
+		KeyTypeECDSAP521: KeyTypeECDSAP521,
+		KeyTypeEd25519:   KeyTypeEd25519,
+	} {
+		t.Run(want, func(t *testing.T) {
+			key, err := GenPrivateKey(keyType)
+			assert.NoError(t, err)
+			signer, err := key.Signer()
+			assert.NoError(t, err)
+			got, err := KeyType(signer.Public())
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			// the PEM block is parsed back to the same key
+			parsed, err := keyutil.ParsePrivateKeyPEM(key.PEM())
+			assert.NoError(t, err)
+			assert.True(t, signer.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(parsed.(crypto.Signer).Public()))
+		})
+	}
+
+	_, err := GenPrivateKey("DSA")
+	assert.ErrorContains(t, err, `unsupported key type "DSA"`)
+}
+
+func TestHandlerWithKeyType(t *testing.T) {
+	for keyType, want := range map[string]string{
+		"":             KeyTypeECDSAP256,
+		KeyTypeEd25519: KeyTypeEd25519,
+	} {
+		t.Run(want, func(t *testing.T) {
+			h := GetHandlerWithKeyType(HandlerTypeX509, keyType)
+			key, err := h.GenPrivateKey()
+			assert.NoError(t, err)
+			csrBlock, err := h.CreateCSR(pkix.Name{CommonName: "system:node:testnode"}, key, nil)
+			assert.NoError(t, err)
+			csr, err := x509.ParseCertificateRequest(csrBlock.Bytes)
+			assert.NoError(t, err)
+			got, err := KeyType(csr.PublicKey)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	h := GetHandlerWithKeyType(HandlerTypeX509, "DSA")
+	_, err := h.GenPrivateKey()
+	assert.ErrorContains(t, err, `unsupported key type "DSA"`)
+}
+
 func TestCreateCSR(t *testing.T) {
 	handler := x509CertsHandler{}
 
@@ -243,7 +298,7 @@ func TestSignCerts(t *testing.T) {
 			time.Hour*24,
 		)
 
-		certPEM, err := handler.SignCerts(opts)
+		certPEM, err := handler.SignCerts(context.TODO(), opts)
 		assert.Error(t, err)
 		assert.Nil(t, certPEM)
 		assert.Contains(t, err.Error(), "failed to parse csr")
@@ -269,7 +324,7 @@ func TestSignCerts(t *testing.T) {
 			time.Hour*24,
 		)
 
-		certPEM, err := handler.SignCerts(opts)
+		certPEM, err := handler.SignCerts(context.TODO(), opts)
 		assert.Error(t, err)
 		assert.Nil(t, certPEM)
 		assert.Contains(t, err.Error(), "must specify a CommonName")
@@ -295,7 +350,7 @@ func TestSignCerts(t *testing.T) {
 			time.Hour*24,
 		)
 
-		certPEM, err := handler.SignCerts(opts)
+		certPEM, err := handler.SignCerts(context.TODO(), opts)
 		assert.Error(t, err)
 		assert.Nil(t, certPEM)
 		assert.Contains(t, err.Error(), "must specify at least one ExtKeyUsage")
@@ -330,7 +385,7 @@ func TestSignCerts(t *testing.T) {
 			})
 		defer patch.Reset()
 
-		certPEM, err := handler.SignCerts(opts)
+		certPEM, err := handler.SignCerts(context.TODO(), opts)
 		assert.Error(t, err)
 		assert.Nil(t, certPEM)
 		assert.Contains(t, err.Error(), expectedErr.Error())
@@ -359,12 +414,43 @@ func TestSignCerts(t *testing.T) {
 			time.Hour*24,
 		)
 
-		certPEM, err := handler.SignCerts(opts)
+		certPEM, err := handler.SignCerts(context.TODO(), opts)
 		assert.Error(t, err)
 		assert.Nil(t, certPEM)
 		assert.Contains(t, err.Error(), "failed to parse CA private key")
 	})
 
+	t.Run("Sign with CA signer", func(t *testing.T) {
+		caDER, _, caKey, err := setupCA()
+		assert.NoError(t, err)
+
+		clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+
+		cfg := certutil.Config{
+			CommonName:   "Test Client",
+			Organization: []string{"Test Org"},
+			Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+
+		// The CA key in DER is absent when the key is held by a key provider.
+		opts := SignCertsOptionsWithCA(
+			cfg,
+			caDER,
+			nil,
+			&clientKey.PublicKey,
+			time.Hour*24,
+		).WithCASigner(caKey)
+
+		certPEM, err := handler.SignCerts(context.TODO(), opts)
+		assert.NoError(t, err)
+		cert, err := x509.ParseCertificate(certPEM.Bytes)
+		assert.NoError(t, err)
+		ca, err := x509.ParseCertificate(caDER)
+		assert.NoError(t, err)
+		assert.NoError(t, cert.CheckSignatureFrom(ca))
+	})
+
 	t.Run("Error parsing CA certificate", func(t *testing.T) {
 		_, caKeyDER, _, err := setupCA()
 		assert.NoError(t, err)
@@ -388,7 +474,7 @@ func TestSignCerts(t *testing.T) {
 			time.Hour*24,
 		)
 
-		certPEM, err := handler.SignCerts(opts)
+		certPEM, err := handler.SignCerts(context.TODO(), opts)
 		assert.Error(t, err)
 		assert.Nil(t, certPEM)
 		assert.Contains(t, err.Error(), "failed to parse CA")
@@ -423,9 +509,142 @@ func TestSignCerts(t *testing.T) {
 			})
 		defer patch.Reset()
 
-		certPEM, err := handler.SignCerts(opts)
+		certPEM, err := handler.SignCerts(context.TODO(), opts)
 		assert.Error(t, err)
 		assert.Nil(t, certPEM)
 		assert.Contains(t, err.Error(), expectedErr.Error())
 	})
 }
+
+func TestSignCertsBackdate(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caKeyDER, err := x509.MarshalECPrivateKey(caKey)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	assert.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	clientKey, err := GenPrivateKey(KeyTypeECDSAP256)
+	assert.NoError(t, err)
+	csr, err := x509CertsHandler{}.CreateCSR(pkix.Name{CommonName: "system:node:testnode"}, clientKey, nil)
+	assert.NoError(t, err)
+	sign := func(backdate, expiration time.Duration) (*x509.Certificate, error) {
+		block, err := x509CertsHandler{}.SignCerts(context.TODO(), SignCertsOptionsWithCSR(csr.Bytes, caDER, caKeyDER,
+			[]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, expiration).WithBackdate(backdate))
+		if err != nil {
+			return nil, err
+		}
+		return x509.ParseCertificate(block.Bytes)
+	}
+	// verifyOnSkewedClock verifies the certificate on a node whose clock is behind by skew
+	verifyOnSkewedClock := func(cert *x509.Certificate, skew time.Duration) error {
+		_, err := cert.Verify(x509.VerifyOptions{
+			Roots:       roots,
+			CurrentTime: time.Now().Add(-skew),
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		return err
+	}
+
+	t.Run("no backdate", func(t *testing.T) {
+		cert, err := sign(0, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Hour, cert.NotAfter.Sub(cert.NotBefore))
+		assert.Error(t, verifyOnSkewedClock(cert, 4*time.Minute))
+	})
+
+	t.Run("backdate", func(t *testing.T) {
+		start := time.Now().Truncate(time.Second)
+		cert, err := sign(5*time.Minute, time.Hour)
+		assert.NoError(t, err)
+		assert.False(t, cert.NotBefore.After(start.Add(-5*time.Minute)))
+		// the validity period is not extended by the backdate
+		assert.Equal(t, time.Hour, cert.NotAfter.Sub(cert.NotBefore))
+		assert.NoError(t, verifyOnSkewedClock(cert, 4*time.Minute))
+		assert.Error(t, verifyOnSkewedClock(cert, 10*time.Minute))
+	})
+
+	t.Run("backdate larger than the validity period", func(t *testing.T) {
+		_, err := sign(time.Hour, time.Hour)
+		assert.ErrorContains(t, err, "the backdate 1h0m0s must be shorter than the validity period 1h0m0s")
+	})
+
+	t.Run("negative backdate", func(t *testing.T) {
+		_, err := sign(-time.Minute, time.Hour)
+		assert.ErrorContains(t, err, "the backdate -1m0s must not be negative")
+	})
+}
+
+func TestSignCertsURIs(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caKeyDER, err := x509.MarshalECPrivateKey(caKey)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+
+	clientKey, err := GenPrivateKey(KeyTypeECDSAP256)
+	assert.NoError(t, err)
+	csr, err := x509CertsHandler{}.CreateCSR(pkix.Name{CommonName: "system:node:testnode"}, clientKey, nil)
+	assert.NoError(t, err)
+	block, err := x509CertsHandler{}.SignCerts(context.TODO(), SignCertsOptionsWithCSR(csr.Bytes, caDER, caKeyDER,
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour).WithURIs(EdgeNodeSPIFFEID("example.org", "testnode")))
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	if assert.Len(t, cert.URIs, 1) {
+		assert.Equal(t, "spiffe://example.org/node/testnode", cert.URIs[0].String())
+	}
+	assert.Equal(t, "system:node:testnode", cert.Subject.CommonName)
+}
+
+func TestSignCertsWithOCSPServers(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caKeyDER, err := x509.MarshalECPrivateKey(caKey)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+
+	clientKey, err := GenPrivateKey(KeyTypeECDSAP256)
+	assert.NoError(t, err)
+	csr, err := x509CertsHandler{}.CreateCSR(pkix.Name{CommonName: "system:node:testnode"}, clientKey, nil)
+	assert.NoError(t, err)
+	block, err := x509CertsHandler{}.SignCerts(context.TODO(), SignCertsOptionsWithCSR(csr.Bytes, caDER, caKeyDER,
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, time.Hour).WithOCSPServers("https://10.0.0.1:10002/ocsp"))
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://10.0.0.1:10002/ocsp"}, cert.OCSPServer)
+}
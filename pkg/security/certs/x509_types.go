@@ -17,28 +17,112 @@ package certs
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"fmt"
 
 	"k8s.io/client-go/util/keyutil"
 )
 
+// GenPrivateKey generates a private key of the key type, which is one of KeyTypeRSA, KeyTypeECDSAP256,
+// KeyTypeECDSAP384, KeyTypeECDSAP521 and KeyTypeEd25519, it defaults to KeyTypeECDSAP256 if keyType
+// is empty. The ECDSA keys are SEC 1 encoded as the keys generated by the handlers, the others are
+// PKCS #8 encoded.
+func GenPrivateKey(keyType string) (PrivateKeyWrap, error) {
+	var curve elliptic.Curve
+	switch keyType {
+	case "", KeyTypeECDSAP256:
+		curve = elliptic.P256()
+	case KeyTypeECDSAP384:
+		curve = elliptic.P384()
+	case KeyTypeECDSAP521:
+		curve = elliptic.P521()
+	case KeyTypeRSA, KeyTypeEd25519:
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+	if curve != nil {
+		pk, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate the %s private key, err: %v", keyType, err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(pk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert an EC private key to SEC 1, ASN.1 DER form, err: %v", err)
+		}
+		return &x509PrivateKeyWrap{der: keyDER}, nil
+	}
+
+	var pk crypto.Signer
+	var err error
+	if keyType == KeyTypeRSA {
+		pk, err = rsa.GenerateKey(rand.Reader, DefaultMinRSAKeyBits)
+	} else {
+		_, pk, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the %s private key, err: %v", keyType, err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert a %s private key to PKCS #8, ASN.1 DER form, err: %v", keyType, err)
+	}
+	return &x509PrivateKeyWrap{der: keyDER}, nil
+}
+
+// PrivateKeySigner parses the private key in DER, which is SEC 1, PKCS #8 or PKCS #1 encoded.
+func PrivateKeySigner(der []byte) (crypto.Signer, error) {
+	return x509PrivateKeyWrap{der: der}.Signer()
+}
+
 type x509PrivateKeyWrap struct {
 	der []byte
 }
 
+// Signer parses the private key, which is generated as an EC private key. The CA keys
+// provided by users may also be PKCS #8 or PKCS #1 encoded.
 func (k x509PrivateKeyWrap) Signer() (crypto.Signer, error) {
-	return x509.ParseECPrivateKey(k.der)
+	key, err := x509.ParseECPrivateKey(k.der)
+	if err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(k.der); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(k.der); err == nil {
+		return key, nil
+	}
+	return nil, err
 }
 
 func (k x509PrivateKeyWrap) DER() []byte {
 	return k.der
 }
 
+// PEM encodes the private key in the PEM block of its encoding.
 func (k x509PrivateKeyWrap) PEM() []byte {
 	privateKeyPemBlock := &pem.Block{
-		Type:  keyutil.ECPrivateKeyBlockType,
+		Type:  PrivateKeyBlockType(k.der),
 		Bytes: k.der,
 	}
 	return pem.EncodeToMemory(privateKeyPemBlock)
 }
+
+// PrivateKeyBlockType returns the PEM block type of the DER encoded private key, e.g.
+// "EC PRIVATE KEY" for SEC 1 and "PRIVATE KEY" for PKCS #8, which Ed25519 keys use.
+func PrivateKeyBlockType(der []byte) string {
+	if _, err := x509.ParseECPrivateKey(der); err == nil {
+		return keyutil.ECPrivateKeyBlockType
+	}
+	if _, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return keyutil.RSAPrivateKeyBlockType
+	}
+	return keyutil.PrivateKeyBlockType
+}
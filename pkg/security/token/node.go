@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package token
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The Secret recording a per-node bootstrap token, which is named NodeTokenSecretPrefix + the ID of
+// the token. The token is valid until it expires or the Secret is deleted or annotated consumed.
+const (
+	NodeTokenSecretPrefix       = "edge-bootstrap-token-"
+	NodeTokenConsumedAnnotation = "kubeedge.io/bootstrap-token-consumed-at"
+	NodeTokenDataNodeName       = "nodeName"
+	NodeTokenDataExpiration     = "expiration"
+)
+
+// NodeClaims are the claims of a per-node bootstrap token, which can only enroll the node once.
+type NodeClaims struct {
+	jwt.RegisteredClaims
+	// NodeName is the name of the edge node that the token enrolls.
+	NodeName string `json:"nodeName"`
+}
+
+// CreateNodeToken creates a token consisting of caHash and a jwt token for the node, which expires
+// after ttl. The claims are returned to record the token by NodeTokenSecret.
+func CreateNodeToken(ca, caKey []byte, nodeName string, ttl time.Duration) (string, *NodeClaims, error) {
+	if nodeName == "" {
+		return "", nil, errors.New("the node name of the token is empty")
+	}
+	if ttl <= 0 {
+		return "", nil, fmt.Errorf("the ttl %s of the token must be positive", ttl)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, fmt.Errorf("failed to generate the token ID, err: %v", err)
+	}
+	now := time.Now()
+	claims := &NodeClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(id),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		NodeName: nodeName,
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(caKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return strings.Join([]string{hashCA(ca), tokenString}, "."), claims, nil
+}
+
+// ParseNodeToken verifies the token like Verify and returns its claims, the NodeName of the
+// claims is empty if the token is the global token created by Create, and the ID is empty if
+// the token is created by CreateWithNodeName, which is not recorded.
+func ParseNodeToken(token string, caKey []byte) (*NodeClaims, error) {
+	claims := &NodeClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("invalid token method type, want *jwt.SigningMethodHMAC, but is %T", token.Method)
+		}
+		return caKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// NodeTokenSecretName returns the name of the Secret recording the token with the ID.
+func NodeTokenSecretName(id string) string {
+	return NodeTokenSecretPrefix + id
+}
+
+// NodeTokenSecret returns the Secret recording the unconsumed token of the claims.
+func NodeTokenSecret(claims *NodeClaims, namespace string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NodeTokenSecretName(claims.ID),
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			NodeTokenDataNodeName:   []byte(claims.NodeName),
+			NodeTokenDataExpiration: []byte(claims.ExpiresAt.UTC().Format(time.RFC3339)),
+		},
+	}
+}
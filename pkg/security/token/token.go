@@ -18,6 +18,7 @@ package token
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -44,6 +45,30 @@ func Create(ca, caKey []byte, intervalTime time.Duration) (string, error) {
 	return strings.Join([]string{hashCA(ca), tokenString}, "."), nil
 }
 
+// CreateWithNodeName creates a token like Create, which carries the node name claim and expires
+// after ttl. Unlike the per-node token created by CreateNodeToken, the token is not recorded, so it
+// can enroll the node until it expires, and CloudHub only warns about a mismatched node name unless
+// it is strict.
+func CreateWithNodeName(ca, caKey []byte, nodeName string, ttl time.Duration) (string, error) {
+	if nodeName == "" {
+		return "", errors.New("the node name of the token is empty")
+	}
+	if ttl <= 0 {
+		return "", fmt.Errorf("the ttl %s of the token must be positive", ttl)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &NodeClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+		NodeName: nodeName,
+	})
+	tokenString, err := token.SignedString(caKey)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{hashCA(ca), tokenString}, "."), nil
+}
+
 func hashCA(ca []byte) string {
 	digest := sha256.Sum256(ca)
 	return hex.EncodeToString(digest[:])
@@ -18,6 +18,7 @@ package token
 import (
 	"encoding/pem"
 	"testing"
+	"time"
 )
 
 const (
@@ -108,3 +109,85 @@ func TestToken(t *testing.T) {
 		}
 	})
 }
+
+func TestNodeToken(t *testing.T) {
+	_, caDer := pem.Decode([]byte(testCA))
+	_, cakeyDer := pem.Decode([]byte(testCAKey))
+
+	tokenWithHash, claims, err := CreateNodeToken(caDer, cakeyDer, "edge-node-1", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	realToken, err := VerifyCAAndGetRealToken(tokenWithHash, caDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseNodeToken(realToken, cakeyDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.NodeName != "edge-node-1" || parsed.ID == "" || parsed.ID != claims.ID {
+		t.Fatalf("unexpected claims %+v, want %+v", parsed, claims)
+	}
+
+	secret := NodeTokenSecret(claims, "kubeedge")
+	if secret.Name != NodeTokenSecretName(claims.ID) || string(secret.Data[NodeTokenDataNodeName]) != "edge-node-1" {
+		t.Fatalf("unexpected secret %+v", secret)
+	}
+
+	// the global token has no node name
+	globalToken, err := Create(caDer, cakeyDer, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalToken, err = VerifyCAAndGetRealToken(globalToken, caDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err = ParseNodeToken(globalToken, cakeyDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.NodeName != "" {
+		t.Fatalf("unexpected node name %s of the global token", parsed.NodeName)
+	}
+
+	// the token with the node name claim is not recorded
+	claimedToken, err := CreateWithNodeName(caDer, cakeyDer, "edge-node-1", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimedToken, err = VerifyCAAndGetRealToken(claimedToken, caDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err = ParseNodeToken(claimedToken, cakeyDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.NodeName != "edge-node-1" || parsed.ID != "" {
+		t.Fatalf("unexpected claims %+v of the token with the node name claim", parsed)
+	}
+	if _, err := CreateWithNodeName(caDer, cakeyDer, "", time.Hour); err == nil {
+		t.Fatal("expected error for the empty node name")
+	}
+
+	if _, _, err := CreateNodeToken(caDer, cakeyDer, "", time.Hour); err == nil {
+		t.Fatal("expected error for the empty node name")
+	}
+	if _, _, err := CreateNodeToken(caDer, cakeyDer, "edge-node-1", 0); err == nil {
+		t.Fatal("expected error for the non-positive ttl")
+	}
+	expired, _, err := CreateNodeToken(caDer, cakeyDer, "edge-node-1", time.Nanosecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	expired, err = VerifyCAAndGetRealToken(expired, caDer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseNodeToken(expired, cakeyDer); err == nil {
+		t.Fatal("expected error for the expired token")
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package msgtrace samples the beehive messages at the entry points where they are created,
+// and logs the sampled messages with their trace ids at every hop, so that the logs of a
+// request can be correlated across cloudcore and edgecore.
+package msgtrace
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync/atomic"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+var (
+	// sampleRate holds the bits of the fraction of the messages traced, no message is traced if it is 0
+	sampleRate atomic.Uint64
+	traced     atomic.Uint64
+
+	// random returns a pseudo-random number in [0.0,1.0), it is replaced in the tests
+	random = rand.Float64
+)
+
+// SetSampleRate sets the fraction of the messages traced at the entry points, the tracing is
+// disabled if the rate is 0.
+func SetSampleRate(rate float64) {
+	sampleRate.Store(math.Float64bits(rate))
+}
+
+// Traced returns the number of the messages traced at the entry points of the process.
+func Traced() uint64 {
+	return traced.Load()
+}
+
+// Start sets a new trace id on the message created at an entry point if it is sampled,
+// the message already carrying a valid trace id keeps it.
+func Start(msg *model.Message) *model.Message {
+	if model.IsValidTraceID(msg.GetTraceID()) {
+		return msg
+	}
+	msg.SetTraceID("")
+	rate := math.Float64frombits(sampleRate.Load())
+	if rate <= 0 || random() >= rate {
+		return msg
+	}
+	traced.Add(1)
+	return msg.SetTraceID(model.NewTraceID())
+}
+
+// Infof logs the traced message at the default verbosity with its trace id, so that the hops of
+// a sampled message are logged without raising the verbosity. The other messages are logged
+// at the verbosity of the level.
+func Infof(level klog.Level, msg *model.Message, format string, args ...interface{}) {
+	if traceID := msg.GetTraceID(); traceID != "" {
+		klog.InfofDepth(1, "%s, trace_id: %s, msg_id: %s", fmt.Sprintf(format, args...), traceID, msg.GetID())
+		return
+	}
+	klog.V(level).InfofDepth(1, format, args...)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The KubeEdge Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package msgtrace
+
+import (
+	"testing"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+)
+
+func TestStart(t *testing.T) {
+	const existing = "4bf92f3577b34da6a3ce929d0e0e4736"
+	cases := []struct {
+		name    string
+		rate    float64
+		random  float64
+		traceID string
+		traced  bool
+		keep    bool
+	}{
+		{name: "disabled", rate: 0, random: 0},
+		{name: "sampled", rate: 0.01, random: 0.005, traced: true},
+		{name: "not sampled", rate: 0.01, random: 0.5},
+		{name: "trace all", rate: 1, random: 0.999, traced: true},
+		{name: "existing trace id kept", rate: 0.01, random: 0.5, traceID: existing, keep: true},
+		{name: "invalid trace id dropped", rate: 0.01, random: 0.5, traceID: "invalid"},
+	}
+
+	defer func(r func() float64) { random = r }(random)
+	defer SetSampleRate(0)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			SetSampleRate(c.rate)
+			random = func() float64 { return c.random }
+			before := Traced()
+
+			msg := Start(model.NewMessage("").SetTraceID(c.traceID))
+			switch {
+			case c.keep:
+				if msg.GetTraceID() != c.traceID {
+					t.Errorf("expected the trace id %s kept, got %q", c.traceID, msg.GetTraceID())
+				}
+			case c.traced:
+				if !model.IsValidTraceID(msg.GetTraceID()) {
+					t.Errorf("expected a valid trace id, got %q", msg.GetTraceID())
+				}
+			default:
+				if msg.GetTraceID() != "" {
+					t.Errorf("expected no trace id, got %q", msg.GetTraceID())
+				}
+			}
+			var expected uint64
+			if c.traced {
+				expected = 1
+			}
+			if got := Traced() - before; got != expected {
+				t.Errorf("expected %d traced messages, got %d", expected, got)
+			}
+		})
+	}
+}
@@ -14,6 +14,8 @@ type QuicClientOption struct {
 	Header http.Header
 	// the max incoming stream
 	MaxIncomingStreams int
+	// the max sizes of the messages and the sizes of the buffers
+	Limits MessageLimits
 }
 
 // you can do some additional processes after successful dialing
@@ -26,4 +28,9 @@ type WSClientOption struct {
 	Header http.Header
 	// called after dialing
 	Callback WSClientCallback
+	// whether to negotiate the permessage-deflate compression with the server,
+	// the messages are sent uncompressed if the server does not support it
+	EnableCompression bool
+	// the max sizes of the messages and the sizes of the buffers
+	Limits MessageLimits
 }
@@ -8,6 +8,8 @@ import "net/http"
 type QuicServerOption struct {
 	// the max incoming stream
 	MaxIncomingStreams int
+	// the max sizes of the messages and the sizes of the buffers
+	Limits MessageLimits
 }
 
 // the filter function before upgrading the http to websocket
@@ -20,4 +22,9 @@ type WSServerOption struct {
 	Path string
 	// the necessary processing before upgrading
 	Filter WSFilterFunc
+	// whether to negotiate the permessage-deflate compression with the clients,
+	// the clients not supporting it are served uncompressed
+	EnableCompression bool
+	// the max sizes of the messages and the sizes of the buffers
+	Limits MessageLimits
 }
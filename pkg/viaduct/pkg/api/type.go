@@ -22,3 +22,20 @@ const (
 )
 
 type UseType string
+
+// the max sizes of the messages and the sizes of the buffers of the connections,
+// which are expected to agree between the server and the clients
+type MessageLimits struct {
+	// the max size of the messages read from the peer, the larger messages are dropped
+	// unlimited if 0
+	MaxReadMessageSize int64
+	// the max size of the messages written to the peer, the larger messages are not sent
+	// unlimited if 0
+	MaxWriteMessageSize int64
+	// the size of the read buffer of websocket, or the receive window of the quic streams,
+	// the default of the protocol library is used if 0
+	ReadBufferSize int
+	// the size of the write buffer of the connections,
+	// the default of the protocol library is used if 0
+	WriteBufferSize int
+}
@@ -38,11 +38,17 @@ func NewQuicClient(opts Options, exOpts interface{}) *QuicClient {
 // get quic config
 // TODO: add additional options
 func (c *QuicClient) getQuicConfig() *quic.Config {
-	return &quic.Config{
+	config := &quic.Config{
 		HandshakeTimeout: c.options.HandshakeTimeout,
 		// keep the session by default
 		KeepAlive: true,
 	}
+	if window := c.exOpts.Limits.ReadBufferSize; window > 0 {
+		config.MaxReceiveStreamFlowControlWindow = uint64(window)
+		// the connection window is larger so that a stream does not block the others
+		config.MaxReceiveConnectionFlowControlWindow = uint64(window) * 3 / 2
+	}
+	return config
 }
 
 // the basic lan for connection control
@@ -61,7 +67,7 @@ func (c *QuicClient) getControlLane(s quic.Session) error {
 		return fmt.Errorf("open control stream")
 	}
 
-	c.ctrlLane = lane.NewLane(api.ProtocolTypeQuic, stream)
+	c.ctrlLane = lane.NewLimitedLane(api.ProtocolTypeQuic, stream, c.exOpts.Limits)
 	return nil
 }
 
@@ -126,5 +132,6 @@ func (c *QuicClient) Connect() (conn.Connection, error) {
 			PeerCertificates: session.ConnectionState().PeerCertificates,
 		},
 		AutoRoute: c.options.AutoRoute,
+		Limits:    c.exOpts.Limits,
 	}), nil
 }
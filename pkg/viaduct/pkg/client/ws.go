@@ -1,9 +1,11 @@
 package client
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
 	"io"
+	"net"
 
 	"github.com/gorilla/websocket"
 	"k8s.io/klog/v2"
@@ -32,8 +34,20 @@ func NewWSClient(options Options, exOpts interface{}) *WSClient {
 		options: options,
 		exOpts:  extendOption,
 		dialer: &websocket.Dialer{
-			TLSClientConfig:  options.TLSConfig,
-			HandshakeTimeout: options.HandshakeTimeout,
+			TLSClientConfig:   options.TLSConfig,
+			HandshakeTimeout:  options.HandshakeTimeout,
+			EnableCompression: extendOption.EnableCompression,
+			ReadBufferSize:    extendOption.Limits.ReadBufferSize,
+			WriteBufferSize:   extendOption.Limits.WriteBufferSize,
+			// count the bytes written to the network to compare with the payload of the messages
+			NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				var d net.Dialer
+				c, err := d.DialContext(ctx, network, addr)
+				if err != nil {
+					return nil, err
+				}
+				return lane.CountConnWireBytes(c), nil
+			},
 		},
 	}
 }
@@ -60,13 +74,14 @@ func (c *WSClient) Connect() (conn.Connection, error) {
 			Base:     wsConn,
 			Consumer: c.options.Consumer,
 			Handler:  c.options.Handler,
-			CtrlLane: lane.NewLane(api.ProtocolTypeWS, wsConn),
+			CtrlLane: lane.NewLimitedLane(api.ProtocolTypeWS, wsConn, c.exOpts.Limits),
 			State: &conn.ConnectionState{
 				State:            api.StatConnected,
 				Headers:          c.exOpts.Header.Clone(),
 				PeerCertificates: peerCerts,
 			},
 			AutoRoute: c.options.AutoRoute,
+			Limits:    c.exOpts.Limits,
 		}), nil
 	}
 
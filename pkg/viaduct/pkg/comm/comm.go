@@ -6,6 +6,8 @@ const (
 	ControlTypeConfig = "config"
 	ControlTypePing   = "ping"
 	ControlTypePong   = "pong"
+	// the operation of the keepalive messages of EdgeHub, which are never compressed
+	ControlTypeKeepalive = "keepalive"
 
 	// control message action
 	ControlActionHeader = "/control/header"
@@ -1,7 +1,37 @@
 package comm
 
+import (
+	"errors"
+	"fmt"
+)
+
 const (
 	StatusCodeNoError = 0
 
 	StatusCodeFreeStream = 1
 )
+
+// MessageSizeError is returned when a message is larger than the max message size of the connection
+type MessageSizeError struct {
+	// Read indicates whether the message is read from the peer, or written to it
+	Read bool
+	// Size is the size of the message in bytes
+	Size int64
+	// Limit is the max message size in bytes
+	Limit int64
+}
+
+func (e *MessageSizeError) Error() string {
+	direction := "write"
+	if e.Read {
+		direction = "read"
+	}
+	return fmt.Sprintf("the message of %d bytes exceeds the max %s message size of %d bytes", e.Size, direction, e.Limit)
+}
+
+// IsMessageSizeError reports whether the err is caused by a message larger than the max message size,
+// the message is dropped while the connection is still usable
+func IsMessageSizeError(err error) bool {
+	var sizeErr *MessageSizeError
+	return errors.As(err, &sizeErr)
+}
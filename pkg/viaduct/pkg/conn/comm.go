@@ -4,18 +4,20 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/lane"
 )
 
 type responseWriter struct {
-	Type string
-	Van  interface{}
+	Type   string
+	Van    interface{}
+	Limits api.MessageLimits
 }
 
 // write response
 func (r *responseWriter) WriteResponse(msg *model.Message, content interface{}) {
 	response := msg.NewRespByMessage(msg, content)
-	err := lane.NewLane(r.Type, r.Van).WriteMessage(response)
+	err := lane.NewLimitedLane(r.Type, r.Van, r.Limits).WriteMessage(response)
 	if err != nil {
 		klog.Errorf("failed to write response, error: %+v", err)
 	}
@@ -24,7 +26,7 @@ func (r *responseWriter) WriteResponse(msg *model.Message, content interface{})
 // write error
 func (r *responseWriter) WriteError(msg *model.Message, errMsg string) {
 	response := model.NewErrorMessage(msg, errMsg)
-	err := lane.NewLane(r.Type, r.Van).WriteMessage(response)
+	err := lane.NewLimitedLane(r.Type, r.Van, r.Limits).WriteMessage(response)
 	if err != nil {
 		klog.Errorf("failed to write error, error: %+v", err)
 	}
@@ -2,6 +2,7 @@ package conn
 
 import (
 	"crypto/x509"
+	"fmt"
 	"net"
 	"net/http"
 	"time"
@@ -67,3 +68,63 @@ type Connection interface {
 	// Any blocked Read or Write operations will be unblocked and return errors.
 	Close() error
 }
+
+// ReasonCloser is implemented by the connections which can tell the
+// peer why the connection is closed
+type ReasonCloser interface {
+	CloseWithReason(reason string) error
+}
+
+// CloseWithReason closes the connection, the reason is sent to the peer
+// if the connection supports it
+func CloseWithReason(c Connection, reason string) error {
+	if rc, ok := c.(ReasonCloser); ok {
+		return rc.CloseWithReason(reason)
+	}
+	return c.Close()
+}
+
+// CloseCodeNotAdmitted is the close code of the connections of the edge nodes which
+// are not admitted by the cloud, the edge nodes back off longer before connecting again
+const CloseCodeNotAdmitted = 4403
+
+// CodeCloser is implemented by the connections which can tell the
+// peer the close code along with the reason
+type CodeCloser interface {
+	CloseWithCode(code int, reason string) error
+}
+
+// CloseWithCode closes the connection, the close code and the reason are sent
+// to the peer if the connection supports it
+func CloseWithCode(c Connection, code int, reason string) error {
+	if cc, ok := c.(CodeCloser); ok {
+		return cc.CloseWithCode(code, reason)
+	}
+	return CloseWithReason(c, reason)
+}
+
+// PeerCloseError is the close code and the reason of the connection closed by the peer
+type PeerCloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *PeerCloseError) Error() string {
+	return fmt.Sprintf("connection closed by the peer with code %d: %s", e.Code, e.Reason)
+}
+
+// PeerCloser is implemented by the connections which record how the peer closed them
+type PeerCloser interface {
+	// PeerCloseError returns nil if the connection is not closed by the peer with a close code
+	PeerCloseError() *PeerCloseError
+}
+
+// PeerCloseCode returns the close code of the connection closed by the peer, 0 if unknown
+func PeerCloseCode(c Connection) int {
+	if pc, ok := c.(PeerCloser); ok {
+		if err := pc.PeerCloseError(); err != nil {
+			return err.Code
+		}
+	}
+	return 0
+}
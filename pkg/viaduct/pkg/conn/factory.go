@@ -30,6 +30,8 @@ type ConnectionOptions struct {
 	AutoRoute bool
 	// OnReadTransportErr
 	OnReadTransportErr func(nodeID, projectID string)
+	// the max sizes of the messages and the sizes of the buffers
+	Limits api.MessageLimits
 }
 
 // get connection interface by ConnTye
@@ -8,9 +8,11 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/lucas-clemente/quic-go"
+	"github.com/lucas-clemente/quic-go/qerr"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
@@ -28,6 +30,9 @@ var (
 	autoFree = false
 )
 
+// closeReasonCode is the application error code sent with the close reason
+const closeReasonCode quic.ErrorCode = 1
+
 // QuicConnection the connection based on quic protocol
 type QuicConnection struct {
 	writeDeadline      time.Time
@@ -44,6 +49,9 @@ type QuicConnection struct {
 	autoRoute          bool
 	OnReadTransportErr func(nodeID, projectID string)
 	locker             sync.Mutex
+	// peerCloseErr records the application error of the peer which closed the session
+	peerCloseErr atomic.Pointer[PeerCloseError]
+	limits       api.MessageLimits
 }
 
 // NewQuicConn new quic connection
@@ -61,6 +69,7 @@ func NewQuicConn(options *ConnectionOptions) *QuicConnection {
 		messageFifo:        fifo.NewMessageFifo(),
 		OnReadTransportErr: options.OnReadTransportErr,
 		streamManager:      smgr.NewStreamManager(smgr.NumStreamsMax, autoFree, quicSession),
+		limits:             options.Limits,
 	}
 }
 
@@ -110,6 +119,10 @@ func (conn *QuicConnection) serveSession() {
 			// close the session
 			klog.Warningf("accept stream error(%+v) or the session has been closed",
 				err)
+			var quicErr *qerr.QuicError
+			if errors.As(err, &quicErr) && conn.state.State != api.StatDisconnected {
+				conn.peerCloseErr.Store(&PeerCloseError{Code: int(quicErr.ErrorCode), Reason: quicErr.ErrorMessage})
+			}
 			// close local session
 			_ = conn.Close()
 
@@ -218,7 +231,11 @@ func (conn *QuicConnection) handleRawData(stream *smgr.Stream) {
 func (conn *QuicConnection) handleMessage(stream *smgr.Stream) {
 	msg := &model.Message{}
 	for {
-		err := lane.NewLane(api.ProtocolTypeQuic, stream.Stream).ReadMessage(msg)
+		err := lane.NewLimitedLane(api.ProtocolTypeQuic, stream.Stream, conn.limits).ReadMessage(msg)
+		if comm.IsMessageSizeError(err) {
+			klog.Errorf("drop the message read from %s, error: %v", conn.state.Headers.Get("node_id"), err)
+			continue
+		}
 		if err != nil {
 			if !errors.Is(err, io.EOF) {
 				klog.Errorf("failed to read message, error: %+v", err)
@@ -248,8 +265,9 @@ func (conn *QuicConnection) handleMessage(stream *smgr.Stream) {
 			PeerCertificates: conn.state.PeerCertificates,
 			Message:          msg,
 		}, &responseWriter{
-			Type: api.ProtocolTypeQuic,
-			Van:  stream.Stream,
+			Type:   api.ProtocolTypeQuic,
+			Van:    stream.Stream,
+			Limits: conn.limits,
 		})
 	}
 }
@@ -262,6 +280,23 @@ func (conn *QuicConnection) Close() error {
 	return conn.session.Close()
 }
 
+// CloseWithReason closes the connection with an application error carrying the reason
+func (conn *QuicConnection) CloseWithReason(reason string) error {
+	return conn.CloseWithCode(int(closeReasonCode), reason)
+}
+
+// CloseWithCode closes the connection with an application error of the code carrying the reason
+func (conn *QuicConnection) CloseWithCode(code int, reason string) error {
+	conn.state.State = api.StatDisconnected
+	conn.streamManager.Destroy()
+	return conn.session.Sess.CloseWithError(quic.ErrorCode(code), errors.New(reason))
+}
+
+// PeerCloseError returns the close code and the reason of the peer which closed the session
+func (conn *QuicConnection) PeerCloseError() *PeerCloseError {
+	return conn.peerCloseErr.Load()
+}
+
 // WriteMessageSync write sync message
 // please set write deadline before WriteMessageSync called
 func (conn *QuicConnection) WriteMessageSync(msg *model.Message) (*model.Message, error) {
@@ -280,7 +315,7 @@ func (conn *QuicConnection) WriteMessageSync(msg *model.Message) (*model.Message
 	}
 	defer conn.streamManager.ReleaseStream(api.UseTypeMessage, stream)
 
-	lane := lane.NewLane(api.ProtocolTypeQuic, stream)
+	lane := lane.NewLimitedLane(api.ProtocolTypeQuic, stream, conn.limits)
 	_ = lane.SetWriteDeadline(conn.writeDeadline)
 	msg.Header.Sync = true
 	err = lane.WriteMessage(msg)
@@ -310,7 +345,7 @@ func (conn *QuicConnection) WriteMessageAsync(msg *model.Message) error {
 	}
 	defer conn.streamManager.ReleaseStream(api.UseTypeMessage, stream)
 
-	lane := lane.NewLane(api.ProtocolTypeQuic, stream)
+	lane := lane.NewLimitedLane(api.ProtocolTypeQuic, stream, conn.limits)
 	_ = lane.SetWriteDeadline(conn.writeDeadline)
 	msg.Header.Sync = false
 
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -32,6 +33,9 @@ type WSConnection struct {
 	messageFifo        *fifo.MessageFifo
 	locker             sync.Mutex
 	OnReadTransportErr func(nodeID, projectID string)
+	// peerCloseErr records the close frame of the peer which closed the connection
+	peerCloseErr atomic.Pointer[PeerCloseError]
+	limits       api.MessageLimits
 }
 
 func NewWSConn(options *ConnectionOptions) *WSConnection {
@@ -44,6 +48,7 @@ func NewWSConn(options *ConnectionOptions) *WSConnection {
 		autoRoute:          options.AutoRoute,
 		messageFifo:        fifo.NewMessageFifo(),
 		OnReadTransportErr: options.OnReadTransportErr,
+		limits:             options.Limits,
 	}
 }
 
@@ -71,7 +76,7 @@ func (conn *WSConnection) filterControlMessage(msg *model.Message) bool {
 	// feedback the response
 	resp := msg.NewRespByMessage(msg, comm.RespTypeAck)
 	conn.locker.Lock()
-	err := lane.NewLane(api.ProtocolTypeWS, conn.wsConn).WriteMessage(resp)
+	err := lane.NewLimitedLane(api.ProtocolTypeWS, conn.wsConn, conn.limits).WriteMessage(resp)
 	conn.locker.Unlock()
 	if err != nil {
 		klog.Errorf("failed to send response back, error:%+v", err)
@@ -102,13 +107,23 @@ func (conn *WSConnection) handleRawData() {
 func (conn *WSConnection) handleMessage() {
 	for {
 		msg := &model.Message{}
-		err := lane.NewLane(api.ProtocolTypeWS, conn.wsConn).ReadMessage(msg)
+		err := lane.NewLimitedLane(api.ProtocolTypeWS, conn.wsConn, conn.limits).ReadMessage(msg)
+		if comm.IsMessageSizeError(err) {
+			klog.Errorf("drop the message read from %s, error: %v", conn.state.Headers.Get("node_id"), err)
+			continue
+		}
 		if err != nil {
 			if !errors.Is(err, io.EOF) {
 				klog.Errorf("failed to read message, error: %+v", err)
 			}
 			conn.state.State = api.StatDisconnected
 			_ = conn.wsConn.Close()
+			// the readers of the messages learn why the peer closed the connection at once
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				conn.peerCloseErr.Store(&PeerCloseError{Code: closeErr.Code, Reason: closeErr.Text})
+				conn.messageFifo.Close()
+			}
 
 			if conn.OnReadTransportErr != nil {
 				conn.OnReadTransportErr(conn.state.Headers.Get("node_id"),
@@ -143,8 +158,9 @@ func (conn *WSConnection) handleMessage() {
 			PeerCertificates: conn.state.PeerCertificates,
 			Message:          msg,
 		}, &responseWriter{
-			Type: api.ProtocolTypeWS,
-			Van:  conn.wsConn,
+			Type:   api.ProtocolTypeWS,
+			Van:    conn.wsConn,
+			Limits: conn.limits,
 		})
 	}
 }
@@ -168,7 +184,7 @@ func (conn *WSConnection) Write(raw []byte) (int, error) {
 }
 
 func (conn *WSConnection) WriteMessageAsync(msg *model.Message) error {
-	lane := lane.NewLane(api.ProtocolTypeWS, conn.wsConn)
+	lane := lane.NewLimitedLane(api.ProtocolTypeWS, conn.wsConn, conn.limits)
 	_ = lane.SetWriteDeadline(conn.WriteDeadline)
 	msg.Header.Sync = false
 	conn.locker.Lock()
@@ -177,7 +193,7 @@ func (conn *WSConnection) WriteMessageAsync(msg *model.Message) error {
 }
 
 func (conn *WSConnection) WriteMessageSync(msg *model.Message) (*model.Message, error) {
-	lane := lane.NewLane(api.ProtocolTypeWS, conn.wsConn)
+	lane := lane.NewLimitedLane(api.ProtocolTypeWS, conn.wsConn, conn.limits)
 	// send msg
 	_ = lane.SetWriteDeadline(conn.WriteDeadline)
 	msg.Header.Sync = true
@@ -194,7 +210,18 @@ func (conn *WSConnection) WriteMessageSync(msg *model.Message) (*model.Message,
 }
 
 func (conn *WSConnection) ReadMessage(msg *model.Message) error {
-	return conn.messageFifo.Get(msg)
+	if err := conn.messageFifo.Get(msg); err != nil {
+		if closeErr := conn.peerCloseErr.Load(); closeErr != nil {
+			return closeErr
+		}
+		return err
+	}
+	return nil
+}
+
+// PeerCloseError returns the close code and the reason of the peer which closed the connection
+func (conn *WSConnection) PeerCloseError() *PeerCloseError {
+	return conn.peerCloseErr.Load()
 }
 
 func (conn *WSConnection) RemoteAddr() net.Addr {
@@ -210,6 +237,20 @@ func (conn *WSConnection) Close() error {
 	return conn.wsConn.Close()
 }
 
+// CloseWithReason sends a close frame with the reason before closing the connection
+func (conn *WSConnection) CloseWithReason(reason string) error {
+	return conn.CloseWithCode(websocket.ClosePolicyViolation, reason)
+}
+
+// CloseWithCode sends a close frame with the code and the reason before closing the connection
+func (conn *WSConnection) CloseWithCode(code int, reason string) error {
+	msg := websocket.FormatCloseMessage(code, reason)
+	if err := conn.wsConn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second)); err != nil {
+		klog.Warningf("failed to send close message, error: %+v", err)
+	}
+	return conn.Close()
+}
+
 // get connection state
 // TODO:
 func (conn *WSConnection) ConnectionState() ConnectionState {
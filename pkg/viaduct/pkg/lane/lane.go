@@ -19,11 +19,24 @@ type Lane interface {
 }
 
 func NewLane(protoType string, van interface{}) Lane {
+	return NewLimitedLane(protoType, van, api.MessageLimits{})
+}
+
+// NewLimitedLane returns a lane reading and writing the messages within the limits
+func NewLimitedLane(protoType string, van interface{}, limits api.MessageLimits) Lane {
 	switch protoType {
 	case api.ProtocolTypeQuic:
-		return NewQuicLane(van)
+		if l := NewQuicLane(van); l != nil {
+			l.limits = limits
+			return l
+		}
+		return nil
 	case api.ProtocolTypeWS:
-		return NewWSLaneWithoutPack(van)
+		if l := NewWSLaneWithoutPack(van); l != nil {
+			l.limits = limits
+			return l
+		}
+		return nil
 	}
 	klog.Errorf("bad protocol type(%s)", protoType)
 	return nil
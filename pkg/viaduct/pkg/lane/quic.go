@@ -1,12 +1,14 @@
 package lane
 
 import (
+	"bufio"
 	"time"
 
 	"github.com/lucas-clemente/quic-go"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/packer"
 	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/translator"
 )
@@ -15,6 +17,7 @@ type QuicLane struct {
 	writeDeadline time.Time
 	readDeadline  time.Time
 	stream        quic.Stream
+	limits        api.MessageLimits
 }
 
 func NewQuicLane(van interface{}) *QuicLane {
@@ -26,9 +29,9 @@ func NewQuicLane(van interface{}) *QuicLane {
 }
 
 func (l *QuicLane) ReadMessage(msg *model.Message) error {
-	rawData, err := packer.NewReader(l.stream).Read()
+	rawData, err := packer.NewReader(l.stream).SetMaxPayloadLen(l.limits.MaxReadMessageSize).Read()
 	if err != nil {
-		return err
+		return countOversized(err)
 	}
 
 	err = translator.NewTran().Decode(rawData, msg)
@@ -47,8 +50,19 @@ func (l *QuicLane) WriteMessage(msg *model.Message) error {
 		return err
 	}
 
-	_, err = packer.NewWriter(l.stream).Write(rawData)
-	return err
+	if err := checkWriteSize(l.limits, len(rawData)); err != nil {
+		return err
+	}
+	if l.limits.WriteBufferSize <= 0 {
+		_, err = packer.NewWriter(l.stream).Write(rawData)
+		return err
+	}
+	// the package header and the payload are written to the stream together
+	w := bufio.NewWriterSize(l.stream, l.limits.WriteBufferSize)
+	if _, err = packer.NewWriter(w).Write(rawData); err != nil {
+		return err
+	}
+	return w.Flush()
 }
 
 func (l *QuicLane) Read(raw []byte) (int, error) {
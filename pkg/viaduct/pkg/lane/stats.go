@@ -0,0 +1,141 @@
+package lane
+
+import (
+	"io"
+	"net"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
+)
+
+var (
+	wsPayloadBytes atomic.Uint64
+	wsWireBytes    atomic.Uint64
+
+	oversizedReadMessages  atomic.Uint64
+	oversizedWriteMessages atomic.Uint64
+)
+
+// WSStats are the bytes sent over the websocket connections of the process, which show the
+// savings of the permessage-deflate compression.
+type WSStats struct {
+	// PayloadBytes are the bytes of the messages before they are compressed
+	PayloadBytes uint64
+	// WireBytes are the bytes written to the network, including the frame headers and the TLS records
+	WireBytes uint64
+}
+
+// GetWSStats returns the bytes sent over the websocket connections since the process started.
+func GetWSStats() WSStats {
+	return WSStats{
+		PayloadBytes: wsPayloadBytes.Load(),
+		WireBytes:    wsWireBytes.Load(),
+	}
+}
+
+// OversizedStats are the messages dropped as they are larger than the max message sizes.
+type OversizedStats struct {
+	// ReadMessages are the messages read from the peers
+	ReadMessages uint64
+	// WriteMessages are the messages not written to the peers
+	WriteMessages uint64
+}
+
+// GetOversizedStats returns the oversized messages since the process started.
+func GetOversizedStats() OversizedStats {
+	return OversizedStats{
+		ReadMessages:  oversizedReadMessages.Load(),
+		WriteMessages: oversizedWriteMessages.Load(),
+	}
+}
+
+// checkWriteSize returns the error if the message of the size is larger than the max write message size.
+func checkWriteSize(limits api.MessageLimits, size int) error {
+	if limits.MaxWriteMessageSize <= 0 || int64(size) <= limits.MaxWriteMessageSize {
+		return nil
+	}
+	oversizedWriteMessages.Add(1)
+	return &comm.MessageSizeError{Size: int64(size), Limit: limits.MaxWriteMessageSize}
+}
+
+// countOversized counts the oversized message read from the peer if err is caused by it.
+func countOversized(err error) error {
+	if comm.IsMessageSizeError(err) {
+		oversizedReadMessages.Add(1)
+	}
+	return err
+}
+
+// readLimited reads the message from r, the message larger than the limit is drained so that
+// the next message can be read, and the error naming its size is returned.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) <= limit {
+		return data, nil
+	}
+	n, err := io.Copy(io.Discard, r)
+	if err != nil {
+		return nil, err
+	}
+	return nil, &comm.MessageSizeError{Read: true, Size: int64(len(data)) + n, Limit: limit}
+}
+
+// writeWSMessage writes the message to the websocket connection, the message is compressed if the
+// compression is negotiated unless it is uncompressed is true.
+func writeWSMessage(conn *websocket.Conn, messageType int, data []byte, uncompressed bool) error {
+	conn.EnableWriteCompression(!uncompressed)
+	err := conn.WriteMessage(messageType, data)
+	if err == nil {
+		wsPayloadBytes.Add(uint64(len(data)))
+	}
+	return err
+}
+
+// isKeepalive reports whether the message keeps the connection alive, which is too small
+// to be worth compressing and is kept uncompressed as the control frames.
+func isKeepalive(msg *model.Message) bool {
+	switch msg.GetOperation() {
+	case comm.ControlTypePing, comm.ControlTypePong, comm.ControlTypeKeepalive:
+		return true
+	}
+	return false
+}
+
+// CountWireBytes returns a listener whose connections count the bytes written to the network.
+func CountWireBytes(ln net.Listener) net.Listener {
+	return &countingListener{Listener: ln}
+}
+
+// CountConnWireBytes returns a connection counting the bytes written to the network.
+func CountConnWireBytes(c net.Conn) net.Conn {
+	return &countingConn{Conn: c}
+}
+
+type countingListener struct {
+	net.Listener
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: c}, nil
+}
+
+type countingConn struct {
+	net.Conn
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	wsWireBytes.Add(uint64(n))
+	return n, err
+}
@@ -0,0 +1,139 @@
+package lane
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
+)
+
+// dialWS connects a client to a websocket server which sends back the messages it receives.
+func dialWS(t *testing.T, serverCompression, clientCompression bool) (*websocket.Conn, *http.Response) {
+	upgrader := websocket.Upgrader{EnableCompression: serverCompression}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	dialer := websocket.Dialer{
+		EnableCompression: clientCompression,
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			var d net.Dialer
+			c, err := d.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return CountConnWireBytes(c), nil
+		},
+	}
+	conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, resp
+}
+
+func TestWSCompression(t *testing.T) {
+	bulky := model.NewMessage("").BuildRouter("edgecontroller", "resource", "default/pod/test", model.UpdateOperation).
+		FillBody(strings.Repeat(`{"kind":"Pod","apiVersion":"v1","metadata":{"name":"test","namespace":"default"}}`, 100))
+	keepalive := model.NewMessage("").BuildRouter("websocket", "resource", "node", comm.ControlTypeKeepalive).FillBody("ping")
+
+	cases := []struct {
+		name              string
+		serverCompression bool
+		clientCompression bool
+		wantCompressed    bool
+	}{
+		{name: "both enabled", serverCompression: true, clientCompression: true, wantCompressed: true},
+		{name: "server disabled", clientCompression: true},
+		{name: "client disabled", serverCompression: true},
+		{name: "both disabled"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conn, resp := dialWS(t, c.serverCompression, c.clientCompression)
+			negotiated := strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+			require.Equal(t, c.wantCompressed, negotiated)
+
+			for _, msg := range []*model.Message{bulky, keepalive} {
+				before := GetWSStats()
+				require.NoError(t, NewWSLaneWithoutPack(conn).WriteMessage(msg))
+				after := GetWSStats()
+				payload, wire := after.PayloadBytes-before.PayloadBytes, after.WireBytes-before.WireBytes
+				require.NotZero(t, payload)
+				if c.wantCompressed && msg == bulky {
+					require.Less(t, wire*5, payload, "the message is not compressed")
+				} else {
+					// the frame header and the mask key of the client are added to the payload
+					require.GreaterOrEqual(t, wire, payload, "the message is compressed")
+				}
+
+				echoed := &model.Message{}
+				require.NoError(t, NewWSLaneWithoutPack(conn).ReadMessage(echoed))
+				require.Equal(t, msg.GetID(), echoed.GetID())
+				require.Equal(t, msg.GetContent(), echoed.GetContent())
+			}
+
+			// the control frames are never compressed, which are the 2 bytes of the header,
+			// the 4 bytes of the mask key and the payload
+			before := GetWSStats()
+			require.NoError(t, conn.WriteControl(websocket.PingMessage, []byte("ping"), time.Now().Add(time.Second)))
+			require.Equal(t, uint64(2+4+4), GetWSStats().WireBytes-before.WireBytes)
+		})
+	}
+}
+
+func TestWSMessageLimits(t *testing.T) {
+	bulky := model.NewMessage("").BuildRouter("edgecontroller", "resource", "default/configmap/test", model.UpdateOperation).
+		FillBody(strings.Repeat("data", 1024))
+	small := model.NewMessage("").BuildRouter("edgecontroller", "resource", "default/configmap/test", model.UpdateOperation).
+		FillBody("data")
+	limits := api.MessageLimits{MaxReadMessageSize: 1024, MaxWriteMessageSize: 1024}
+	conn, _ := dialWS(t, false, false)
+	limited := NewLimitedLane(api.ProtocolTypeWS, conn, limits)
+
+	before := GetOversizedStats()
+	err := limited.WriteMessage(bulky)
+	var sizeErr *comm.MessageSizeError
+	require.ErrorAs(t, err, &sizeErr)
+	require.False(t, sizeErr.Read)
+	require.Greater(t, sizeErr.Size, limits.MaxWriteMessageSize)
+	require.Equal(t, limits.MaxWriteMessageSize, sizeErr.Limit)
+	require.Equal(t, before.WriteMessages+1, GetOversizedStats().WriteMessages)
+
+	// the oversized message echoed by the server is dropped, and the next message is read
+	require.NoError(t, NewLane(api.ProtocolTypeWS, conn).WriteMessage(bulky))
+	require.NoError(t, limited.WriteMessage(small))
+	err = limited.ReadMessage(&model.Message{})
+	require.ErrorAs(t, err, &sizeErr)
+	require.True(t, sizeErr.Read)
+	require.Greater(t, sizeErr.Size, limits.MaxReadMessageSize)
+	require.Contains(t, err.Error(), "exceeds the max read message size of 1024 bytes")
+	require.Equal(t, before.ReadMessages+1, GetOversizedStats().ReadMessages)
+
+	echoed := &model.Message{}
+	require.NoError(t, limited.ReadMessage(echoed))
+	require.Equal(t, small.GetID(), echoed.GetID())
+}
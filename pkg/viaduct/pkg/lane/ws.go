@@ -55,7 +55,7 @@ func (l *WSLane) ReadMessage(msg *model.Message) error {
 }
 
 func (l *WSLane) Write(p []byte) (int, error) {
-	err := l.conn.WriteMessage(websocket.BinaryMessage, p)
+	err := writeWSMessage(l.conn, websocket.BinaryMessage, p, false)
 	if err != nil {
 		klog.Errorf("write websocket message error(%+v)", err)
 		return len(p), err
@@ -1,6 +1,8 @@
 package lane
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"time"
@@ -9,12 +11,14 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kubeedge/beehive/pkg/core/model"
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/api"
 )
 
 type WSLaneWithoutPack struct {
 	writeDeadline time.Time
 	readDeadline  time.Time
 	conn          *websocket.Conn
+	limits        api.MessageLimits
 }
 
 func NewWSLaneWithoutPack(van interface{}) *WSLaneWithoutPack {
@@ -38,11 +42,22 @@ func (l *WSLaneWithoutPack) Read(p []byte) (int, error) {
 }
 
 func (l *WSLaneWithoutPack) ReadMessage(msg *model.Message) error {
-	return l.conn.ReadJSON(msg)
+	if l.limits.MaxReadMessageSize <= 0 {
+		return l.conn.ReadJSON(msg)
+	}
+	_, r, err := l.conn.NextReader()
+	if err != nil {
+		return err
+	}
+	data, err := readLimited(r, l.limits.MaxReadMessageSize)
+	if err != nil {
+		return countOversized(err)
+	}
+	return json.Unmarshal(data, msg)
 }
 
 func (l *WSLaneWithoutPack) Write(p []byte) (int, error) {
-	err := l.conn.WriteMessage(websocket.BinaryMessage, p)
+	err := writeWSMessage(l.conn, websocket.BinaryMessage, p, false)
 	if err != nil {
 		klog.Errorf("write websocket message error(%+v)", err)
 		return len(p), err
@@ -51,7 +66,15 @@ func (l *WSLaneWithoutPack) Write(p []byte) (int, error) {
 }
 
 func (l *WSLaneWithoutPack) WriteMessage(msg *model.Message) error {
-	return l.conn.WriteJSON(msg)
+	// encode the message as WriteJSON does, so that its size is known
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(msg); err != nil {
+		return err
+	}
+	if err := checkWriteSize(l.limits, buf.Len()); err != nil {
+		return err
+	}
+	return writeWSMessage(l.conn, websocket.TextMessage, buf.Bytes(), isKeepalive(msg))
 }
 
 func (l *WSLaneWithoutPack) SetReadDeadline(t time.Time) error {
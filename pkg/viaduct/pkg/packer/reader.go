@@ -6,16 +6,26 @@ import (
 	"io"
 
 	"k8s.io/klog/v2"
+
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
 )
 
 type Reader struct {
 	reader io.Reader
+	// the max payload length, unlimited if 0
+	maxPayloadLen int64
 }
 
 func NewReader(r io.Reader) *Reader {
 	return &Reader{reader: r}
 }
 
+// SetMaxPayloadLen sets the max payload length, the larger payloads are skipped
+func (r *Reader) SetMaxPayloadLen(maxPayloadLen int64) *Reader {
+	r.maxPayloadLen = maxPayloadLen
+	return r
+}
+
 // Read message raw data from reader
 // steps:
 // 1)read the package header
@@ -39,6 +49,15 @@ func (r *Reader) Read() ([]byte, error) {
 	header := PackageHeader{}
 	header.Unpack(headerBuffer)
 
+	if r.maxPayloadLen > 0 && int64(header.PayloadLen) > r.maxPayloadLen {
+		// skip the payload so that the next package can be read
+		if _, err := io.CopyN(io.Discard, r.reader, int64(header.PayloadLen)); err != nil {
+			klog.Error("failed to skip payload from buffer")
+			return nil, err
+		}
+		return nil, &comm.MessageSizeError{Read: true, Size: int64(header.PayloadLen), Limit: r.maxPayloadLen}
+	}
+
 	payloadBuffer := make([]byte, header.PayloadLen)
 	_, err = io.ReadFull(r.reader, payloadBuffer)
 	if err != nil {
@@ -22,6 +22,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/kubeedge/kubeedge/pkg/viaduct/pkg/comm"
 )
 
 func TestNewReader(t *testing.T) {
@@ -118,3 +120,22 @@ func TestReader_Read_PayloadReadError(t *testing.T) {
 	assert.Equal(t, io.EOF, err)
 	assert.Nil(t, data)
 }
+
+func TestReader_Read_PayloadTooLarge(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	_, err := NewWriter(buffer).Write([]byte("oversized payload"))
+	assert.NoError(t, err)
+	_, err = NewWriter(buffer).Write([]byte("payload"))
+	assert.NoError(t, err)
+
+	reader := NewReader(buffer).SetMaxPayloadLen(10)
+	data, err := reader.Read()
+	assert.Nil(t, data)
+	assert.Equal(t, &comm.MessageSizeError{Read: true, Size: 17, Limit: 10}, err)
+	assert.EqualError(t, err, "the message of 17 bytes exceeds the max read message size of 10 bytes")
+
+	// the oversized payload is skipped
+	data, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), data)
+}
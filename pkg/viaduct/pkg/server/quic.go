@@ -110,7 +110,7 @@ func (srv *QuicServer) handleSession(session quic.Session) {
 		return
 	}
 
-	ctrlLane := lane.NewLane(api.ProtocolTypeQuic, ctrlStream)
+	ctrlLane := lane.NewLimitedLane(api.ProtocolTypeQuic, ctrlStream, srv.exOpts.Limits)
 	header, err := srv.receiveHeader(ctrlLane)
 	if err != nil {
 		klog.Errorf("failed to complete get header, error: %+v", err)
@@ -122,7 +122,7 @@ func (srv *QuicServer) handleSession(session quic.Session) {
 		ConnUse:  api.UseTypeShare,
 		Consumer: srv.options.Consumer,
 		Base:     session,
-		CtrlLane: lane.NewLane(api.ProtocolTypeQuic, ctrlStream),
+		CtrlLane: lane.NewLimitedLane(api.ProtocolTypeQuic, ctrlStream, srv.exOpts.Limits),
 		Handler:  srv.options.Handler,
 		State: &conn.ConnectionState{
 			State:            api.StatConnected,
@@ -131,6 +131,7 @@ func (srv *QuicServer) handleSession(session quic.Session) {
 		},
 		AutoRoute:          srv.options.AutoRoute,
 		OnReadTransportErr: srv.options.OnReadTransportErr,
+		Limits:             srv.exOpts.Limits,
 	})
 
 	// connection callback
@@ -148,11 +149,17 @@ func (srv *QuicServer) handleSession(session quic.Session) {
 }
 
 func (srv *QuicServer) getQuicConfig() *quic.Config {
-	return &quic.Config{
+	config := &quic.Config{
 		HandshakeTimeout:   srv.options.HandshakeTimeout,
 		KeepAlive:          true,
 		MaxIncomingStreams: srv.exOpts.MaxIncomingStreams,
 	}
+	if window := srv.exOpts.Limits.ReadBufferSize; window > 0 {
+		config.MaxReceiveStreamFlowControlWindow = uint64(window)
+		// the connection window is larger so that a stream does not block the others
+		config.MaxReceiveConnectionFlowControlWindow = uint64(window) * 3 / 2
+	}
+	return config
 }
 
 func (srv *QuicServer) ListenAndServeTLS() error {
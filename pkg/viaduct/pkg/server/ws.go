@@ -2,6 +2,7 @@ package server
 
 import (
 	glog "log"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -56,7 +57,10 @@ func NewWSServer(opts Options, exOpts interface{}) *WSServer {
 
 func (srv *WSServer) upgrade(w http.ResponseWriter, r *http.Request) *websocket.Conn {
 	upgrader := websocket.Upgrader{
-		HandshakeTimeout: srv.options.HandshakeTimeout,
+		HandshakeTimeout:  srv.options.HandshakeTimeout,
+		EnableCompression: srv.exOpts.EnableCompression,
+		ReadBufferSize:    srv.exOpts.Limits.ReadBufferSize,
+		WriteBufferSize:   srv.exOpts.Limits.WriteBufferSize,
 	}
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -85,7 +89,7 @@ func (srv *WSServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		ConnUse:  api.UseType(req.Header.Get("ConnectionUse")),
 		Consumer: srv.options.Consumer,
 		Handler:  srv.options.Handler,
-		CtrlLane: lane.NewLane(api.ProtocolTypeWS, wsConn),
+		CtrlLane: lane.NewLimitedLane(api.ProtocolTypeWS, wsConn, srv.exOpts.Limits),
 		State: &conn.ConnectionState{
 			State:            api.StatConnected,
 			Headers:          req.Header.Clone(),
@@ -93,6 +97,7 @@ func (srv *WSServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		},
 		AutoRoute:          srv.options.AutoRoute,
 		OnReadTransportErr: srv.options.OnReadTransportErr,
+		Limits:             srv.exOpts.Limits,
 	})
 
 	// connection callback
@@ -110,7 +115,12 @@ func (srv *WSServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 }
 
 func (srv *WSServer) ListenAndServeTLS() error {
-	return srv.server.ListenAndServeTLS("", "")
+	ln, err := net.Listen("tcp", srv.server.Addr)
+	if err != nil {
+		return err
+	}
+	// count the bytes written to the network to compare with the payload of the messages
+	return srv.server.ServeTLS(lane.CountWireBytes(ln), "", "")
 }
 
 func (srv *WSServer) Close() error {
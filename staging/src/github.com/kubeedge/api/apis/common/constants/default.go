@@ -75,6 +75,7 @@ const (
 	DefaultServiceAccountTokenBuffer        = 1024
 	DefaultCreatePodBuffer                  = 1024
 	DefaultCertificateSigningRequestBuffer  = 1024
+	DefaultStatusBatchSize                  = 256
 
 	DefaultPodEventBuffer           = 1
 	DefaultConfigMapEventBuffer     = 1
@@ -18,7 +18,8 @@ type HTTPResponse struct {
 }
 
 const (
-	HeaderAuthorization = "Authorization"
-	HeaderNodeName      = "NodeName"
-	HeaderExtKeyUsages  = "ExtKeyUsages"
+	HeaderAuthorization       = "Authorization"
+	HeaderNodeName            = "NodeName"
+	HeaderExtKeyUsages        = "ExtKeyUsages"
+	HeaderForwardedClientCert = "X-Forwarded-Client-Cert"
 )
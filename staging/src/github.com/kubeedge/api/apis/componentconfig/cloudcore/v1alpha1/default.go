@@ -23,6 +23,7 @@ import (
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 
 	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/common/types"
 )
 
 // NewDefaultCloudCoreConfig returns a full CloudCoreConfig object
@@ -40,6 +41,10 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 				BindAddress:     "127.0.0.1:9091",
 				EnableProfiling: false,
 			},
+			MessageTrace: &MessageTrace{
+				Enable:     false,
+				SampleRate: 0.01,
+			},
 		},
 		KubeAPIConfig: &KubeAPIConfig{
 			ContentType: constants.DefaultKubeContentType,
@@ -48,37 +53,77 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 		},
 		Modules: &Modules{
 			CloudHub: &CloudHub{
-				Enable:                  true,
-				KeepaliveInterval:       30,
-				NodeLimit:               constants.DefaultNodeLimit,
-				TLSCAFile:               constants.DefaultCAFile,
-				TLSCAKeyFile:            constants.DefaultCAKeyFile,
-				TLSCertFile:             constants.DefaultCertFile,
-				TLSPrivateKeyFile:       constants.DefaultKeyFile,
-				WriteTimeout:            30,
-				AdvertiseAddress:        []string{advertiseAddress.String()},
-				DNSNames:                []string{""},
-				EdgeCertSigningDuration: 365,
-				TokenRefreshDuration:    12,
+				Enable:                     true,
+				KeepaliveInterval:          30,
+				NodeLimit:                  constants.DefaultNodeLimit,
+				TLSCAFile:                  constants.DefaultCAFile,
+				TLSCAKeyFile:               constants.DefaultCAKeyFile,
+				TLSCertFile:                constants.DefaultCertFile,
+				TLSPrivateKeyFile:          constants.DefaultKeyFile,
+				TLSMinVersion:              "VersionTLS12",
+				WriteTimeout:               30,
+				AdvertiseAddress:           []string{advertiseAddress.String()},
+				DNSNames:                   []string{""},
+				EdgeCertSigningDuration:    365,
+				MaxEdgeCertSigningDuration: 365,
+				CertRenewalOverlap:         10,
+				EdgeCertBackdate:           5,
+				CertClockSkewTolerance:     1,
+				AcceptLegacyCertSubject:    true,
+				RequireFreshCSRKey:         false,
+				DropNodeMetricsLabel:       false,
+				StrictTokenNodeName:        true,
+				TokenRefreshDuration:       12,
+				ServerKeyAlgorithm:         "ECDSA-P256",
+				CSRMinRSAKeySize:           2048,
 				Quic: &CloudHubQUIC{
-					Enable:             false,
-					Address:            "0.0.0.0",
-					Port:               10001,
-					MaxIncomingStreams: 10000,
+					Enable:              false,
+					Address:             "0.0.0.0",
+					Port:                10001,
+					MaxIncomingStreams:  10000,
+					MaxReadMessageSize:  64 << 20,
+					MaxWriteMessageSize: 64 << 20,
+					ReadBufferSize:      1 << 20,
+					WriteBufferSize:     4096,
 				},
 				UnixSocket: &CloudHubUnixSocket{
 					Enable:  true,
 					Address: "unix:///var/lib/kubeedge/kubeedge.sock",
 				},
 				WebSocket: &CloudHubWebSocket{
-					Enable:  true,
-					Port:    10000,
-					Address: "0.0.0.0",
+					Enable:              true,
+					Port:                10000,
+					Address:             "0.0.0.0",
+					EnableCompression:   false,
+					MaxReadMessageSize:  64 << 20,
+					MaxWriteMessageSize: 64 << 20,
+					ReadBufferSize:      4096,
+					WriteBufferSize:     4096,
 				},
 				HTTPS: &CloudHubHTTPS{
-					Enable:  true,
-					Port:    10002,
-					Address: "0.0.0.0",
+					Enable:              true,
+					Port:                10002,
+					Address:             "0.0.0.0",
+					DrainTimeout:        30,
+					DebugCertInfo:       false,
+					NodeNameHeader:      types.HeaderNodeName,
+					ForwardedCertHeader: types.HeaderForwardedClientCert,
+					MaxCSRSize:          1 << 20,
+					ForwardedClientCert: &CloudHubForwardedClientCert{
+						Enable:     false,
+						Precedence: ClientCertPreferDirect,
+						Format:     ForwardedCertFormatAuto,
+					},
+					RequestRateLimit: &CloudHubRequestRateLimit{
+						Enable: false,
+						QPS:    1,
+						Burst:  10,
+					},
+					UnixSocket: &CloudHubHTTPSUnixSocket{
+						Enable: false,
+						Path:   "/var/lib/kubeedge/cloudhub-https.sock",
+						Mode:   0660,
+					},
 				},
 				Authorization: &CloudHubAuthorization{
 					Enable: false,
@@ -91,6 +136,133 @@ func NewDefaultCloudCoreConfig() *CloudCoreConfig {
 						},
 					},
 				},
+				IssuanceLog: &CloudHubIssuanceLog{
+					Enable:            false,
+					Path:              "/var/lib/kubeedge/issuance.log",
+					PruneRetention:    90,
+					PruneInterval:     24,
+					MaxRecordsPerNode: 100,
+				},
+				DelegatedSigning: &CloudHubDelegatedSigning{
+					Enable: false,
+					Retry: &TokenReviewRetry{
+						Steps:           4,
+						InitialInterval: 100,
+						Timeout:         2000,
+					},
+				},
+				SigningQueue: &CloudHubSigningQueue{
+					Workers:    4,
+					QueueDepth: 100,
+				},
+				SigningTimeout: 30,
+				CSRDedupWindow: 60,
+				AsyncSigning: &CloudHubAsyncSigning{
+					Enable: false,
+					JobTTL: 300,
+				},
+				SigningWebhook: &CloudHubSigningWebhook{
+					FailurePolicy: SigningWebhookDeny,
+					Retry: &SigningWebhookRetry{
+						Steps:           3,
+						InitialInterval: 200,
+						Timeout:         5000,
+					},
+				},
+				DuplicateEnrollment: &CloudHubDuplicateEnrollment{
+					Policy: DuplicateEnrollmentAllow,
+				},
+				CSRSubjectPolicy: &CloudHubCSRSubjectPolicy{
+					Action: CSRSubjectStrip,
+				},
+				SPIFFE: &CloudHubSPIFFE{
+					Enable: false,
+				},
+				ExtKeyUsagePolicy: &CloudHubExtKeyUsagePolicy{
+					AllowedUsages: []string{"ClientAuth", "ServerAuth"},
+				},
+				EdgeCertSigner: EdgeCertSignerLocal,
+				CSRAPISigner: &CloudHubCSRAPISigner{
+					SignerName:  "kubeedge.io/edge-node",
+					WaitTimeout: 25,
+				},
+				Revocation: &CloudHubRevocation{
+					Persist:              true,
+					ConfigMapName:        "cloudcore-revoked-certs",
+					SessionCheckInterval: 30,
+				},
+				CertExpiryScan: &CloudHubCertExpiryScan{
+					Enable:      false,
+					Interval:    3600,
+					Threshold:   14,
+					GracePeriod: 24,
+				},
+				OCSP: &CloudHubOCSP{
+					Enable:         false,
+					ValidityPeriod: 3600,
+				},
+				ReliableDelivery: &CloudHubReliableDelivery{
+					Enable:         false,
+					QueueDepth:     1000,
+					TTL:            600,
+					InitialBackoff: 5,
+					MaxBackoff:     120,
+				},
+				UpstreamRateLimit: &CloudHubUpstreamRateLimit{
+					Enable:          false,
+					QPS:             50,
+					Burst:           100,
+					OverflowPolicy:  UpstreamOverflowDrop,
+					MaxViolations:   100,
+					WarningInterval: 60,
+				},
+				SigningRateLimit: &CloudHubSigningRateLimit{
+					Enable:         false,
+					Requests:       10,
+					Window:         3600,
+					Backend:        RateLimitBackendMemory,
+					LeaseNamespace: "kubeedge",
+				},
+				NodeApproval: &CloudHubNodeApproval{
+					Enable:   false,
+					Key:      "node.kubeedge.io/approved",
+					Value:    "true",
+					CacheTTL: 30,
+				},
+				Standby: &CloudHubStandby{
+					Enable:         false,
+					LeaseNamespace: "kubeedge",
+					LeaseName:      "cloudhub-signer",
+					LeaseDuration:  15,
+					RenewDeadline:  10,
+					RetryPeriod:    2,
+				},
+				SessionRegistry: &CloudHubSessionRegistry{
+					LeaseNamespace: "kubeedge",
+					LeaseDuration:  40,
+					RenewInterval:  10,
+				},
+				SessionDrain: &CloudHubSessionDrain{
+					Enable:          true,
+					Timeout:         30,
+					ReconnectWindow: 60,
+				},
+				DuplicateSessionPolicy: DuplicateSessionTakeover,
+				MinEdgeSchemaVersion:   1,
+				NodeAdmission: &CloudHubNodeAdmission{
+					Enable:   false,
+					FailOpen: false,
+				},
+				MessageLanes: &CloudHubMessageLanes{
+					Enable:        true,
+					CriticalBurst: 10,
+				},
+				OfflineStore: &CloudHubOfflineStore{
+					Enable:      false,
+					MaxMessages: 1000,
+					MaxBytes:    10 * 1024 * 1024,
+					TTL:         3600,
+				},
 			},
 			EdgeController: &EdgeController{
 				Enable:              true,
@@ -208,6 +380,7 @@ func getDefaultEdgeControllerBuffer(nodeLimit int32) *EdgeControllerBuffer {
 		ServiceAccountToken:        constants.DefaultServiceAccountTokenBuffer,
 		CreatePod:                  constants.DefaultCreatePodBuffer,
 		CertificateSigningRequest:  constants.DefaultCertificateSigningRequestBuffer,
+		StatusBatchSize:            constants.DefaultStatusBatchSize,
 	}
 }
 
@@ -32,6 +32,71 @@ const (
 	ExternalMode IptablesMgrMode = "external"
 )
 
+type DuplicateEnrollmentPolicy string
+
+const (
+	DuplicateEnrollmentReject DuplicateEnrollmentPolicy = "reject"
+	DuplicateEnrollmentAllow  DuplicateEnrollmentPolicy = "allow"
+	DuplicateEnrollmentFence  DuplicateEnrollmentPolicy = "fence"
+)
+
+type ClientCertPrecedence string
+
+const (
+	ClientCertPreferDirect    ClientCertPrecedence = "prefer-direct"
+	ClientCertPreferForwarded ClientCertPrecedence = "prefer-forwarded"
+)
+
+type ForwardedCertFormat string
+
+const (
+	ForwardedCertFormatAuto  ForwardedCertFormat = "auto"
+	ForwardedCertFormatXFCC  ForwardedCertFormat = "xfcc"
+	ForwardedCertFormatCaddy ForwardedCertFormat = "caddy"
+)
+
+type CSRSubjectAction string
+
+const (
+	CSRSubjectStrip  CSRSubjectAction = "strip"
+	CSRSubjectReject CSRSubjectAction = "reject"
+)
+
+type EdgeCertSignerMode string
+
+const (
+	EdgeCertSignerLocal  EdgeCertSignerMode = "local"
+	EdgeCertSignerCSRAPI EdgeCertSignerMode = "csr-api"
+)
+
+type DuplicateSessionPolicy string
+
+const (
+	DuplicateSessionTakeover DuplicateSessionPolicy = "takeover"
+	DuplicateSessionReject   DuplicateSessionPolicy = "reject"
+)
+
+type SigningWebhookFailurePolicy string
+
+const (
+	SigningWebhookDeny  SigningWebhookFailurePolicy = "deny"
+	SigningWebhookAllow SigningWebhookFailurePolicy = "allow"
+)
+
+type RateLimitBackend string
+
+const (
+	RateLimitBackendMemory RateLimitBackend = "memory"
+	RateLimitBackendLease  RateLimitBackend = "lease"
+)
+
+type UpstreamOverflowPolicy string
+
+const (
+	UpstreamOverflowDrop       UpstreamOverflowPolicy = "drop"
+	UpstreamOverflowDisconnect UpstreamOverflowPolicy = "disconnect"
+)
+
 // Parse reads config file and converts YAML to CloudCoreConfig
 func (c *CloudCoreConfig) Parse(filename string) error {
 	data, err := os.ReadFile(filename)
@@ -45,6 +45,20 @@ type CommonConfig struct {
 
 	// MonitorServer holds config that exposes prometheus metrics and pprof
 	MonitorServer MonitorServer `json:"monitorServer,omitempty"`
+
+	// MessageTrace indicates the config of tracing the messages sent to the edge nodes
+	MessageTrace *MessageTrace `json:"messageTrace,omitempty"`
+}
+
+// MessageTrace indicates the config of tracing the messages, the sampled messages carry
+// a trace id and are logged with it by every component they pass through
+type MessageTrace struct {
+	// Enable indicates whether the messages are traced
+	// default false
+	Enable bool `json:"enable"`
+	// SampleRate indicates the fraction of the messages traced at the entry points, in (0, 1]
+	// default 0.01
+	SampleRate float64 `json:"sampleRate,omitempty"`
 }
 
 // MonitorServer indicates MonitorServer config
@@ -100,6 +114,8 @@ type Modules struct {
 	Router *Router `json:"router,omitempty"`
 	// IptablesManager indicates iptables module config
 	IptablesManager *IptablesManager `json:"iptablesManager,omitempty"`
+	// PolicyController indicates policycontroller module config
+	PolicyController *PolicyController `json:"policyController,omitempty"`
 }
 
 // CloudHub indicates the config of CloudHub module.
@@ -122,12 +138,52 @@ type CloudHub struct {
 	// TLSCAKeyFile indicates caKey file path
 	// default "/etc/kubeedge/ca/rootCA.key"
 	TLSCAKeyFile string `json:"tlsCAKeyFile,omitempty"`
+	// CAKeyPassphraseFile indicates the file of the passphrase which the CA key of TLSCAKeyFile or
+	// of the CA secret is encrypted with, as a PKCS #8 "ENCRYPTED PRIVATE KEY" PEM block. The env
+	// CLOUDCORE_CA_KEY_PASSPHRASE is read instead if it is not set. The key is only decrypted in
+	// memory, and the tokens are signed by a dedicated token key
+	// default ""
+	CAKeyPassphraseFile string `json:"caKeyPassphraseFile,omitempty"`
+	// CAKeyKEKFile indicates the file of the base64 encoded 256-bit KEK which the CA key is wrapped
+	// by, as a "KUBEEDGE WRAPPED PRIVATE KEY" PEM block
+	// default ""
+	CAKeyKEKFile string `json:"caKeyKEKFile,omitempty"`
+	// CAKeyProvider indicates the provider of the CA key, which keeps the key in a key store such
+	// as a KMS or an HSM instead of TLSCAKeyFile. TLSCAFile is required when it is set, and the
+	// tokens are signed by a dedicated token key since the CA key can not be read
+	CAKeyProvider *CloudHubKeyProvider `json:"caKeyProvider,omitempty"`
+	// TLSIntermediateCAFile indicates the PEM file of the intermediate CA certificates which chain
+	// the edge certificates to the root CA trusted by the edge nodes, starting with the CA of
+	// TLSCAFile and excluding the root. The edge certificates requested in the PEM chain format
+	// are returned along with them
+	// default ""
+	TLSIntermediateCAFile string `json:"tlsIntermediateCAFile,omitempty"`
+	// TLSRootCAFile indicates the PEM file of the root CA certificate which the intermediate CAs of
+	// TLSIntermediateCAFile chain to, when the root CA is kept offline and CloudHub only knows its
+	// certificate. It is trusted to verify the edge certificates, and served in the CA bundle
+	// along with the CA of TLSCAFile
+	// default ""
+	TLSRootCAFile string `json:"tlsRootCAFile,omitempty"`
+	// TLSAdditionalCAFile indicates the PEM file of the CA certificates trusted besides the CA of
+	// TLSCAFile, e.g. the previous CA during a CA rotation, so that the edge certificates issued by
+	// them are still accepted. They never sign the edge certificates
+	// default ""
+	TLSAdditionalCAFile string `json:"tlsAdditionalCAFile,omitempty"`
 	// TLSPrivateKeyFile indicates key file path
 	// default "/etc/kubeedge/certs/server.crt"
 	TLSCertFile string `json:"tlsCertFile,omitempty"`
 	// TLSPrivateKeyFile indicates key file path
 	// default "/etc/kubeedge/certs/server.key"
 	TLSPrivateKeyFile string `json:"tlsPrivateKeyFile,omitempty"`
+	// TLSMinVersion indicates the minimum TLS version of the websocket, quic and https servers,
+	// one of VersionTLS10, VersionTLS11, VersionTLS12 and VersionTLS13
+	// default "VersionTLS12"
+	TLSMinVersion string `json:"tlsMinVersion,omitempty"`
+	// TLSCipherSuites indicates the cipher suites of the websocket, quic and https servers for
+	// TLS 1.2 and below, which are the names in crypto/tls, e.g. TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256.
+	// The cipher suites of TLS 1.3 are not configurable
+	// default the cipher suites of each server
+	TLSCipherSuites []string `json:"tlsCipherSuites,omitempty"`
 	// WriteTimeout indicates write time (second)
 	// default 30
 	WriteTimeout int32 `json:"writeTimeout,omitempty"`
@@ -148,11 +204,169 @@ type CloudHub struct {
 	// EdgeCertSigningDuration indicates the validity period of edge certificate
 	// default 365d
 	EdgeCertSigningDuration time.Duration `json:"edgeCertSigningDuration,omitempty"`
+	// MaxEdgeCertSigningDuration indicates the longest validity period (day) of the edge certificates
+	// that the nodes may request in the X-KubeEdge-Cert-Duration header, the longer requests are clamped
+	// to it. The requested durations are ignored if it is 0
+	// default 365d
+	MaxEdgeCertSigningDuration time.Duration `json:"maxEdgeCertSigningDuration,omitempty"`
+	// CSRSignatureAlgorithms indicates the allowed signature algorithms of the CSRs submitted by
+	// edge nodes, in the names of x509.SignatureAlgorithm, such as ECDSA-SHA256 and SHA256-RSA
+	// default ECDSA-SHA256, ECDSA-SHA384, ECDSA-SHA512, SHA256-RSA, SHA384-RSA, SHA512-RSA,
+	// SHA256-RSAPSS, SHA384-RSAPSS, SHA512-RSAPSS and Ed25519
+	CSRSignatureAlgorithms []string `json:"csrSignatureAlgorithms,omitempty"`
+	// CSRMinRSAKeySize indicates the minimum size (bits) of the RSA keys of the CSRs submitted by
+	// edge nodes, it may be lowered deliberately for the nodes on constrained hardware, but not below 1024
+	// default 2048
+	CSRMinRSAKeySize int32 `json:"csrMinRSAKeySize,omitempty"`
+	// EdgeCertKeyUsages indicates the KeyUsage bits of the issued edge certificates, in the names
+	// of x509.KeyUsage without the prefix, such as DigitalSignature and KeyEncipherment. They are
+	// checked against the requested ExtKeyUsages and the key type of the CSR.
+	// default DigitalSignature, and KeyEncipherment for RSA keys
+	EdgeCertKeyUsages []string `json:"edgeCertKeyUsages,omitempty"`
+	// ServerKeyAlgorithm indicates the algorithm of the private key that CloudCore generates for
+	// its server certificate, one of RSA, ECDSA-P256, ECDSA-P384, ECDSA-P521 and Ed25519.
+	// It does not restrict the keys of the CSRs from edge nodes.
+	// default ECDSA-P256
+	ServerKeyAlgorithm string `json:"serverKeyAlgorithm,omitempty"`
+	// CertRenewalOverlap indicates the window after an edge node renews its certificate, during which
+	// the previous certificate is still accepted, and it is revoked after the window, unit is minute.
+	// The previous certificate is not revoked on renewal if it is 0
+	// default 10m
+	CertRenewalOverlap time.Duration `json:"certRenewalOverlap,omitempty"`
+	// EdgeCertBackdate indicates how long NotBefore of the edge certificates is set before the signing,
+	// so the nodes booting with their clocks behind accept the new certificates, unit is minute.
+	// NotAfter is moved back as well, the validity period of the certificates is not extended.
+	// default 5m
+	EdgeCertBackdate time.Duration `json:"edgeCertBackdate,omitempty"`
+	// CertClockSkewTolerance indicates the clock skew tolerated when verifying the certificates that
+	// edge nodes present to renew, which are accepted if they are not yet valid or have expired
+	// within the tolerance, unit is minute
+	// default 1m
+	CertClockSkewTolerance time.Duration `json:"certClockSkewTolerance,omitempty"`
+	// AcceptLegacyCertSubject indicates whether to accept the edge certificates with the legacy
+	// subject of Organization KubeEdge and CommonName kubeedge.io, which are issued by the old
+	// versions. Disable it once all the edge nodes have rotated their certificates.
+	// default true
+	AcceptLegacyCertSubject bool `json:"acceptLegacyCertSubject"`
+	// RequireFreshCSRKey indicates whether an edge node renewing its certificate must prove the
+	// possession of a newly generated key, the CSR with the public key of the certificate being
+	// renewed is rejected if it is set
+	// default false
+	RequireFreshCSRKey bool `json:"requireFreshCSRKey,omitempty"`
+	// StrictTokenNodeName indicates whether to reject the token with the node name claim, which is
+	// not recorded, if the claim mismatches the node name of the request with 401. If it is false,
+	// the mismatch is logged as a warning. The per-node tokens recorded in the Secrets can only
+	// enroll the nodes of their claims in either case.
+	// default true
+	StrictTokenNodeName bool `json:"strictTokenNodeName"`
 	// TokenRefreshDuration indicates the interval of cloudcore token refresh, unit is hour
 	// default 12h
 	TokenRefreshDuration time.Duration `json:"tokenRefreshDuration,omitempty"`
 	// Authorization authz configurations
 	Authorization *CloudHubAuthorization `json:"authorization,omitempty"`
+	// IssuanceLog indicates the config of the edge certificate issuance log
+	IssuanceLog *CloudHubIssuanceLog `json:"issuanceLog,omitempty"`
+	// DelegatedSigning indicates the config of requesting edge certificates on behalf of
+	// edge nodes with a ServiceAccount token
+	DelegatedSigning *CloudHubDelegatedSigning `json:"delegatedSigning,omitempty"`
+	// SigningQueue indicates the config of the queue of edge certificate signing requests
+	SigningQueue *CloudHubSigningQueue `json:"signingQueue,omitempty"`
+	// SigningTimeout indicates the deadline of an edge certificate request (second), including
+	// the authentication, the queueing and the signing. The request is responded with 504 when
+	// it is exceeded, 0 means no deadline
+	// default 30
+	SigningTimeout int32 `json:"signingTimeout,omitempty"`
+	// CSRDedupWindow indicates the window (second) in which an identical CSR posted again by the
+	// same edge node is responded with the certificate signed for it before instead of a new one,
+	// 0 means every CSR is signed
+	// default 60
+	CSRDedupWindow int32 `json:"csrDedupWindow,omitempty"`
+	// AsyncSigning indicates the config of signing the edge certificates asynchronously,
+	// which lets the edge nodes poll the result instead of holding the request open
+	AsyncSigning *CloudHubAsyncSigning `json:"asyncSigning,omitempty"`
+	// DuplicateEnrollment indicates the config of handling the enrollment of a node name
+	// which already has an active session with a different key
+	DuplicateEnrollment *CloudHubDuplicateEnrollment `json:"duplicateEnrollment,omitempty"`
+	// Revocation indicates the persistence of the revoked edge certificates and the enforcement
+	// of the revocations on the connected edge nodes
+	Revocation *CloudHubRevocation `json:"revocation,omitempty"`
+	// CertExpiryScan indicates the periodic scan of the certificates of the edge nodes, which
+	// reports the nodes whose certificates expire soon
+	CertExpiryScan *CloudHubCertExpiryScan `json:"certExpiryScan,omitempty"`
+	// OCSP indicates the OCSP responder of the edge certificates served by the HTTPS server
+	OCSP *CloudHubOCSP `json:"ocsp,omitempty"`
+	// DropNodeMetricsLabel indicates whether to drop the node label of the message metrics of
+	// CloudHub, which bounds their cardinality for very large fleets
+	// default false
+	DropNodeMetricsLabel bool `json:"dropNodeMetricsLabel,omitempty"`
+	// ReliableDelivery indicates the acknowledged delivery of the reliable messages to the edge nodes
+	ReliableDelivery *CloudHubReliableDelivery `json:"reliableDelivery,omitempty"`
+	// UpstreamRateLimit indicates the limit of the messages that each edge node sends to the cloud
+	UpstreamRateLimit *CloudHubUpstreamRateLimit `json:"upstreamRateLimit,omitempty"`
+	// SigningRateLimit indicates the limit of the edge certificate requests of each node
+	SigningRateLimit *CloudHubSigningRateLimit `json:"signingRateLimit,omitempty"`
+	// NodeApproval indicates the approval of the node names before the token authenticated
+	// edge certificate requests of the nodes are signed
+	NodeApproval *CloudHubNodeApproval `json:"nodeApproval,omitempty"`
+	// SigningWebhook indicates the webhook approving the edge certificate requests after they are
+	// authenticated and before they are signed
+	SigningWebhook *CloudHubSigningWebhook `json:"signingWebhook,omitempty"`
+	// IssuanceQuota indicates the quotas of the outstanding edge certificates of the tenants
+	IssuanceQuota *CloudHubIssuanceQuota `json:"issuanceQuota,omitempty"`
+	// SignaturePolicy indicates the policy of the signature algorithms of the issued edge certificates
+	SignaturePolicy *CloudHubSignaturePolicy `json:"signaturePolicy,omitempty"`
+	// CSRSubjectPolicy indicates the policy of the Subject attributes of the CSRs other than
+	// the node identity, which is the CommonName and the Organization
+	CSRSubjectPolicy *CloudHubCSRSubjectPolicy `json:"csrSubjectPolicy,omitempty"`
+	// CSRSANPolicy indicates the policy of the SANs of the CSRs, the DNS and IP SANs are kept in the
+	// issued certificate if they are the addresses of the node, and the CSRs with other SANs are rejected
+	CSRSANPolicy *CloudHubCSRSANPolicy `json:"csrSANPolicy,omitempty"`
+	// SPIFFE indicates the SPIFFE identities of the edge nodes, which are added to the issued
+	// edge certificates as URI SANs
+	SPIFFE *CloudHubSPIFFE `json:"spiffe,omitempty"`
+	// CertExtensions indicates the custom extensions of the issued edge certificates, whose values
+	// are the labels or the annotations of the nodes, e.g. the asset tags for the inventory systems
+	CertExtensions []CloudHubCertExtension `json:"certExtensions,omitempty"`
+	// ExtKeyUsagePolicy indicates the policy of the ExtKeyUsages that the edge nodes may request
+	// in the X-KubeEdge-ExtKeyUsages header, the requests with other usages are rejected
+	ExtKeyUsagePolicy *CloudHubExtKeyUsagePolicy `json:"extKeyUsagePolicy,omitempty"`
+	// Issuers indicates the named issuers which sign the edge certificates with their own CAs,
+	// the certificates of the nodes selecting no issuer are signed by the CA of CloudHub
+	Issuers []CloudHubIssuer `json:"issuers,omitempty"`
+	// Standby indicates the config of running multiple CloudHub replicas where only the leader
+	// signs the edge certificates
+	Standby *CloudHubStandby `json:"standby,omitempty"`
+	// SessionRegistry indicates the config of the registry which records the CloudHub instance
+	// that each edge node is connected to, it takes effect when the feature gate
+	// cloudHubSessionRegistry is enabled
+	SessionRegistry *CloudHubSessionRegistry `json:"sessionRegistry,omitempty"`
+	// SessionDrain indicates how the sessions of the edge nodes are drained when CloudHub stops
+	SessionDrain *CloudHubSessionDrain `json:"sessionDrain,omitempty"`
+	// DuplicateSessionPolicy indicates how a new connection of an edge node that already has an
+	// active session is handled, one of takeover and reject. takeover closes the active session
+	// and serves the new connection, reject refuses the new connection and keeps the active session
+	// default takeover
+	DuplicateSessionPolicy DuplicateSessionPolicy `json:"duplicateSessionPolicy,omitempty"`
+	// MinEdgeSchemaVersion indicates the minimum message schema version of the edge hubs, the
+	// connections of the edge hubs advertising an older version are rejected. The edge hubs not
+	// advertising their version are of version 1
+	// default 1
+	MinEdgeSchemaVersion int32 `json:"minEdgeSchemaVersion,omitempty"`
+	// NodeAdmission indicates which edge nodes may establish the sessions by the labels and
+	// the taints of their Node objects
+	NodeAdmission *CloudHubNodeAdmission `json:"nodeAdmission,omitempty"`
+	// MessageLanes indicates the priority lanes of the downstream messages to each edge node
+	MessageLanes *CloudHubMessageLanes `json:"messageLanes,omitempty"`
+	// OfflineStore indicates the store of the downstream messages to the disconnected edge nodes
+	OfflineStore *CloudHubOfflineStore `json:"offlineStore,omitempty"`
+	// EdgeCertSigner indicates how the edge certificates are signed, one of local and csr-api.
+	// local signs them with the CA of CloudHub, csr-api delegates the signing to the Kubernetes
+	// CSR API, where an external approver and signer issue the certificates
+	// default local
+	EdgeCertSigner EdgeCertSignerMode `json:"edgeCertSigner,omitempty"`
+	// CSRAPISigner indicates the config of signing the edge certificates by the Kubernetes CSR API,
+	// which is used when EdgeCertSigner is csr-api
+	CSRAPISigner *CloudHubCSRAPISigner `json:"csrAPISigner,omitempty"`
 }
 
 // CloudHubQUIC indicates the quic server config
@@ -169,6 +383,22 @@ type CloudHubQUIC struct {
 	// MaxIncomingStreams set the max incoming stream for quic server
 	// default 10000
 	MaxIncomingStreams int32 `json:"maxIncomingStreams,omitempty"`
+	// MaxReadMessageSize indicates the max size in bytes of the messages read from the edge nodes,
+	// the larger messages are dropped, set to 0 for unlimited
+	// default 67108864 (64Mi)
+	MaxReadMessageSize int64 `json:"maxReadMessageSize,omitempty"`
+	// MaxWriteMessageSize indicates the max size in bytes of the messages written to the edge nodes,
+	// the larger messages are not sent, set to 0 for unlimited
+	// default 67108864 (64Mi)
+	MaxWriteMessageSize int64 `json:"maxWriteMessageSize,omitempty"`
+	// ReadBufferSize indicates the size in bytes of the receive window of the streams,
+	// set to 0 for the default of the protocol library
+	// default 1048576 (1Mi)
+	ReadBufferSize int32 `json:"readBufferSize,omitempty"`
+	// WriteBufferSize indicates the size in bytes of the write buffer of the connections,
+	// set to 0 for the default of the protocol library
+	// default 4096
+	WriteBufferSize int32 `json:"writeBufferSize,omitempty"`
 }
 
 // CloudHubUnixSocket indicates the unix socket config
@@ -192,6 +422,26 @@ type CloudHubWebSocket struct {
 	// Port indicates the open port for websocket server
 	// default 10000
 	Port uint32 `json:"port,omitempty"`
+	// EnableCompression indicates whether to negotiate the permessage-deflate compression with
+	// the edge nodes, the nodes not enabling it are served uncompressed
+	// default false
+	EnableCompression bool `json:"enableCompression,omitempty"`
+	// MaxReadMessageSize indicates the max size in bytes of the messages read from the edge nodes,
+	// the larger messages are dropped, set to 0 for unlimited
+	// default 67108864 (64Mi)
+	MaxReadMessageSize int64 `json:"maxReadMessageSize,omitempty"`
+	// MaxWriteMessageSize indicates the max size in bytes of the messages written to the edge nodes,
+	// the larger messages are not sent, set to 0 for unlimited
+	// default 67108864 (64Mi)
+	MaxWriteMessageSize int64 `json:"maxWriteMessageSize,omitempty"`
+	// ReadBufferSize indicates the size in bytes of the read buffer of the connections,
+	// set to 0 for the default of the protocol library
+	// default 4096
+	ReadBufferSize int32 `json:"readBufferSize,omitempty"`
+	// WriteBufferSize indicates the size in bytes of the write buffer of the connections,
+	// set to 0 for the default of the protocol library
+	// default 4096
+	WriteBufferSize int32 `json:"writeBufferSize,omitempty"`
 }
 
 // CloudHubHTTPS indicates the http config of CloudHub
@@ -205,6 +455,96 @@ type CloudHubHTTPS struct {
 	// Port indicates the open port for HTTPS server
 	// default 10002
 	Port uint32 `json:"port,omitempty"`
+	// TrustedProxies indicates the IPs or CIDRs of the proxies in front of the HTTPS server,
+	// forwarded headers are only honored when the request comes from a trusted proxy, and the
+	// X-Forwarded-Client-Cert header of the other requests is stripped
+	// default empty
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
+	// DrainTimeout indicates the seconds to wait for in-flight requests to finish when the
+	// HTTPS server shuts down
+	// default 30
+	DrainTimeout int32 `json:"drainTimeout,omitempty"`
+	// ForwardedClientCert indicates the config of the client certificates forwarded by the trusted proxies
+	ForwardedClientCert *CloudHubForwardedClientCert `json:"forwardedClientCert,omitempty"`
+	// RequestRateLimit indicates the limit of the requests to the certificate endpoints of each source
+	RequestRateLimit *CloudHubRequestRateLimit `json:"requestRateLimit,omitempty"`
+	// DebugCertInfo indicates whether to serve GET /debug/certinfo, which echoes how CloudHub sees
+	// the client certificate of the calling request for debugging the edge nodes failing to join
+	// default false
+	DebugCertInfo bool `json:"debugCertInfo,omitempty"`
+	// NodeNameHeader indicates the header carrying the node name of the certificate requests,
+	// which must match the nodeNameHeader of EdgeHub
+	// default NodeName
+	NodeNameHeader string `json:"nodeNameHeader,omitempty"`
+	// ForwardedCertHeader indicates the header of the client certificates forwarded by the
+	// trusted proxies, e.g. for the gateways rewriting the X- prefixed headers
+	// default X-Forwarded-Client-Cert
+	ForwardedCertHeader string `json:"forwardedCertHeader,omitempty"`
+	// MaxCSRSize indicates the max bytes of the body of the edge certificate requests, the requests
+	// with larger bodies are rejected with 413, e.g. for the CSRs carrying many attributes
+	// default 1048576
+	MaxCSRSize int64 `json:"maxCSRSize,omitempty"`
+	// UnixSocket indicates the unix domain socket on which the HTTPS server is also served
+	UnixSocket *CloudHubHTTPSUnixSocket `json:"unixSocket,omitempty"`
+}
+
+// CloudHubHTTPSUnixSocket indicates the unix domain socket on which the routes of the HTTPS server
+// are also served in plain HTTP, e.g. for a gateway on the same host. The requests arriving via the
+// socket are trusted as the ones from the trusted proxies, and the TCP listener keeps serving the
+// other clients over TLS.
+type CloudHubHTTPSUnixSocket struct {
+	// Enable indicates whether to serve the HTTPS server on the unix domain socket
+	// default false
+	Enable bool `json:"enable"`
+	// Path indicates the path of the socket file, a stale socket file is removed on startup
+	// default /var/lib/kubeedge/cloudhub-https.sock
+	Path string `json:"path,omitempty"`
+	// Mode indicates the file permissions of the socket file, e.g. 0660
+	// default 0660
+	Mode uint32 `json:"mode,omitempty"`
+}
+
+// CloudHubRequestRateLimit indicates the token bucket limit of the requests to the edge certificate
+// and the CA endpoints, the requests over the limit are rejected with 429 before they are authenticated.
+// A bucket is kept for each node name in the nodeNameHeader, or for each client IP if the header is
+// absent, so that the nodes behind one NAT do not share a bucket.
+type CloudHubRequestRateLimit struct {
+	// Enable indicates whether to limit the requests of each source
+	// default false
+	Enable bool `json:"enable"`
+	// QPS indicates the rate of the requests that each source is allowed
+	// default 1
+	QPS float64 `json:"qps,omitempty"`
+	// Burst indicates the max number of the requests that each source is allowed at once
+	// default 10
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// CloudHubForwardedClientCert indicates the config of the client certificates forwarded by the
+// trusted proxies in the X-Forwarded-Client-Cert header, which are verified in the same way as
+// the TLS peer certificates.
+type CloudHubForwardedClientCert struct {
+	// Enable indicates whether to accept the forwarded client certificates, they are only
+	// accepted from the TrustedProxies
+	// default false
+	Enable bool `json:"enable"`
+	// Precedence indicates the certificate used when a request presents both a TLS peer
+	// certificate and a forwarded one, one of prefer-direct and prefer-forwarded. The forwarded
+	// header is ignored entirely with prefer-direct
+	// default prefer-direct
+	// +kubebuilder:validation:Enum=prefer-direct;prefer-forwarded
+	Precedence ClientCertPrecedence `json:"precedence,omitempty"`
+	// Format indicates the format of the X-Forwarded-Client-Cert header, one of auto, xfcc and caddy.
+	// xfcc is the key/value format of Envoy, caddy is the certificate encoded in base64 DER or PEM,
+	// or the URL encoded PEM, and auto detects the format of each header
+	// default auto
+	// +kubebuilder:validation:Enum=auto;xfcc;caddy
+	Format ForwardedCertFormat `json:"format,omitempty"`
+	// InsecureSkipVerify indicates whether to accept the forwarded client certificates without
+	// verifying them against the CA, the ones failing the verification are dropped otherwise.
+	// It is only for debugging
+	// default false
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 }
 
 // CloudHubAuthorization CloudHub authz configurations
@@ -220,6 +560,549 @@ type CloudHubAuthorization struct {
 	Modes []AuthorizationMode `json:"modes"`
 }
 
+// CloudHubIssuanceLog indicates the config of the tamper-evident log of issued edge certificates
+type CloudHubIssuanceLog struct {
+	// Enable indicates whether to record the issued edge certificates
+	// default false
+	Enable bool `json:"enable"`
+	// Path indicates the file that the issuance log is persisted to
+	// default "/var/lib/kubeedge/issuance.log"
+	Path string `json:"path,omitempty"`
+	// PruneRetention indicates how long the records of expired certificates are kept for audit
+	// before they are pruned, unit is day
+	// default 90d
+	PruneRetention time.Duration `json:"pruneRetention,omitempty"`
+	// PruneInterval indicates the interval of pruning the records of expired certificates in
+	// the background, unit is hour. The background pruning is disabled if it is 0
+	// default 24h
+	PruneInterval time.Duration `json:"pruneInterval,omitempty"`
+	// MaxRecordsPerNode indicates the max number of the records of the certificates issued
+	// for a node, the older records beyond it are pruned even if the certificates are not
+	// expired. The records are not limited if it is 0
+	// default 100
+	MaxRecordsPerNode int32 `json:"maxRecordsPerNode,omitempty"`
+}
+
+// CloudHubKeyProvider indicates a private key held by a key provider.
+type CloudHubKeyProvider struct {
+	// Name indicates the name of the key provider registered in CloudCore, such as file
+	Name string `json:"name,omitempty"`
+	// KeyURI indicates the key in the key store of the provider, such as the path of the key
+	// file for the file provider
+	KeyURI string `json:"keyURI,omitempty"`
+}
+
+// CloudHubCSRAPISigner indicates the config of delegating the signing of the edge certificates to
+// the Kubernetes CSR API. CloudHub creates a CertificateSigningRequest for every validated edge CSR
+// and waits for it to be approved and issued, so CloudCore must be allowed to create and get
+// CertificateSigningRequests, and the edge nodes must trust the CA of the external signer.
+type CloudHubCSRAPISigner struct {
+	// SignerName indicates the signerName of the CertificateSigningRequests, which selects the
+	// external signer, such as a cert-manager issuer
+	// default "kubeedge.io/edge-node"
+	SignerName string `json:"signerName,omitempty"`
+	// WaitTimeout indicates how long to wait for a CertificateSigningRequest to be approved and
+	// issued (second), the request is responded with 504 when it is still pending. It is also
+	// bounded by SigningTimeout
+	// default 25
+	WaitTimeout int32 `json:"waitTimeout,omitempty"`
+}
+
+// CloudHubDelegatedSigning indicates the config of delegated signing. When it is enabled,
+// a ServiceAccount token is accepted to request edge certificates, and the ServiceAccount
+// must be annotated with the node names it may provision.
+type CloudHubDelegatedSigning struct {
+	// Enable indicates whether to accept ServiceAccount tokens validated by TokenReview
+	// default false
+	Enable bool `json:"enable"`
+	// Retry indicates the retry of the TokenReview on transient errors
+	Retry *TokenReviewRetry `json:"retry,omitempty"`
+}
+
+// TokenReviewRetry indicates the exponential backoff retry of the TokenReview when the API server
+// is transiently unavailable. Definitive results, such as an invalid token, are never retried.
+type TokenReviewRetry struct {
+	// Steps indicates the max number of attempts of the TokenReview, 1 disables the retry
+	// default 4
+	Steps int32 `json:"steps,omitempty"`
+	// InitialInterval indicates the interval before the first retry (millisecond),
+	// which is doubled before each further retry
+	// default 100
+	InitialInterval int32 `json:"initialInterval,omitempty"`
+	// Timeout indicates the max time of all the attempts (millisecond)
+	// default 2000
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// CloudHubSigningQueue indicates the config of the edge certificate signing queue.
+// Requests are rejected with 503 when the queue is full.
+type CloudHubSigningQueue struct {
+	// Workers indicates the number of workers signing the edge certificates concurrently
+	// default 4
+	Workers int32 `json:"workers,omitempty"`
+	// QueueDepth indicates the max number of signing requests waiting in the queue
+	// default 100
+	QueueDepth int32 `json:"queueDepth,omitempty"`
+}
+
+// CloudHubAsyncSigning indicates the config of asynchronous signing. When it is enabled, an edge
+// certificate request with the "Prefer: respond-async" header is responded with 202 and a job ID,
+// and the result of the job is polled from the certificate result endpoint.
+type CloudHubAsyncSigning struct {
+	// Enable indicates whether to sign the edge certificates asynchronously on request
+	// default false
+	Enable bool `json:"enable"`
+	// JobTTL indicates how long the result of a signing job can be polled after the job
+	// is submitted (second), the job ID is responded with 410 after that
+	// default 300
+	JobTTL int32 `json:"jobTTL,omitempty"`
+}
+
+// CloudHubDuplicateEnrollment indicates the policy applied when an edge certificate is requested
+// for a node name that already has an active session with a different key. Renewals with the
+// key of the active session are always allowed.
+type CloudHubDuplicateEnrollment struct {
+	// Policy indicates the policy of duplicate enrollment, one of reject, allow and fence.
+	// reject refuses the request with 409, allow signs the certificate as usual, fence signs
+	// the certificate, revokes the certificate of the active session and drops the session.
+	// default allow
+	// +kubebuilder:validation:Enum=reject;allow;fence
+	Policy DuplicateEnrollmentPolicy `json:"policy,omitempty"`
+}
+
+// CloudHubRevocation indicates the persistence of the revoked edge certificates and the enforcement
+// of the revocations on the connected edge nodes.
+type CloudHubRevocation struct {
+	// Persist indicates whether to persist the revoked certificates in a ConfigMap in the kubeedge
+	// namespace, so that the revocations survive the restarts of CloudCore and are shared by
+	// all the replicas of CloudCore
+	// default true
+	Persist bool `json:"persist"`
+	// ConfigMapName indicates the name of the ConfigMap of the revoked certificates
+	// default "cloudcore-revoked-certs"
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// SessionCheckInterval indicates the interval to close the sessions of the connected edge nodes
+	// whose certificates are revoked (second)
+	// default 30
+	SessionCheckInterval int32 `json:"sessionCheckInterval,omitempty"`
+}
+
+// CloudHubCertExpiryScan indicates the periodic scan of the certificates that the edge nodes
+// presented most recently. Each node is annotated with the expiration of its certificate, and a
+// Warning Event is recorded once per certificate when it expires within the Threshold.
+type CloudHubCertExpiryScan struct {
+	// Enable indicates whether to scan the certificates of the edge nodes
+	// default false
+	Enable bool `json:"enable"`
+	// Interval indicates the interval of the scans (second)
+	// default 3600
+	Interval int32 `json:"interval,omitempty"`
+	// Threshold indicates how soon the certificates expire to be reported (day)
+	// default 14
+	Threshold int32 `json:"threshold,omitempty"`
+	// GracePeriod indicates how long the certificates of the nodes which never reconnect are
+	// tracked after they have expired (hour)
+	// default 24
+	GracePeriod int32 `json:"gracePeriod,omitempty"`
+}
+
+// CloudHubOCSP indicates the OCSP responder of the edge certificates signed by the CA of CloudHub,
+// which answers from the same revocation list as the verification of the edge certificates.
+// The certificates not in the issuance log are answered unknown if the issuance log is enabled.
+type CloudHubOCSP struct {
+	// Enable indicates whether to serve the OCSP requests at /ocsp of the HTTPS server, and to add
+	// the URL of the responder to the AuthorityInfoAccess of the issued edge certificates
+	// default false
+	Enable bool `json:"enable"`
+	// URL indicates the URL of the responder added to the edge certificates, it is derived from
+	// the first AdvertiseAddress and the HTTPS port if it is empty
+	// default ""
+	URL string `json:"url,omitempty"`
+	// SignerCertFile indicates the delegated OCSP signing certificate issued by the CA with the
+	// OCSPSigning ExtKeyUsage, the responses are signed by the CA if it is empty
+	// default ""
+	SignerCertFile string `json:"signerCertFile,omitempty"`
+	// SignerKeyFile indicates the private key of SignerCertFile
+	// default ""
+	SignerKeyFile string `json:"signerKeyFile,omitempty"`
+	// ValidityPeriod indicates how long the responses are valid for, which is the interval
+	// between their thisUpdate and nextUpdate (second)
+	// default 3600
+	ValidityPeriod int32 `json:"validityPeriod,omitempty"`
+}
+
+// CloudHubSigningRateLimit indicates the limit of the edge certificate requests of each node in
+// fixed windows, the requests over the limit are rejected with 429. The counts are kept in the
+// memory of each replica by default, which a node can evade by spreading its requests across the
+// replicas, so the lease backend shares the counts across the replicas in a Lease per node.
+type CloudHubSigningRateLimit struct {
+	// Enable indicates whether to limit the edge certificate requests of each node
+	// default false
+	Enable bool `json:"enable"`
+	// Requests indicates the max number of the edge certificate requests of a node in a window
+	// default 10
+	Requests int32 `json:"requests,omitempty"`
+	// Window indicates the length of the window (second)
+	// default 3600
+	Window int32 `json:"window,omitempty"`
+	// Backend indicates where the counts are kept, one of memory and lease
+	// default memory
+	// +kubebuilder:validation:Enum=memory;lease
+	Backend RateLimitBackend `json:"backend,omitempty"`
+	// LeaseNamespace indicates the namespace of the Leases of the counts for the lease backend
+	// default "kubeedge"
+	LeaseNamespace string `json:"leaseNamespace,omitempty"`
+}
+
+// CloudHubReliableDelivery indicates the delivery of the reliable messages, which are kept in a
+// per node queue and sent again with exponential backoff until the edge node acknowledges them or
+// they expire. The messages flagged as reliable by their senders are reliable, and so are the messages
+// of ResourceTypes which are not synced by the ObjectSyncs. The reliable messages are sent only once
+// to the edge nodes which do not acknowledge them.
+type CloudHubReliableDelivery struct {
+	// Enable indicates whether to deliver the reliable messages with acknowledgement
+	// default false
+	Enable bool `json:"enable"`
+	// ResourceTypes indicates the resource types whose messages not synced by the ObjectSyncs are reliable,
+	// e.g. membership, podlist
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+	// QueueDepth indicates the max number of the unacknowledged messages of each node, the oldest
+	// message is dropped when a new message comes to a full queue
+	// default 1000
+	QueueDepth int32 `json:"queueDepth,omitempty"`
+	// TTL indicates the time (second) after which an unacknowledged message expires
+	// default 600
+	TTL int32 `json:"ttl,omitempty"`
+	// InitialBackoff indicates the interval (second) before a message is sent again for the first time
+	// default 5
+	InitialBackoff int32 `json:"initialBackoff,omitempty"`
+	// MaxBackoff indicates the max interval (second) before a message is sent again
+	// default 120
+	MaxBackoff int32 `json:"maxBackoff,omitempty"`
+}
+
+// CloudHubUpstreamRateLimit indicates the token bucket limit of the messages that each edge node sends
+// to the cloud, the messages over the limit are dropped before they are dispatched to the controllers.
+// The buckets are kept by the node names, so that a node does not reset its bucket by reconnecting.
+// The keepalive messages and the acks are never limited.
+type CloudHubUpstreamRateLimit struct {
+	// Enable indicates whether to limit the upstream messages of each node
+	// default false
+	Enable bool `json:"enable"`
+	// QPS indicates the rate of the messages that each node is allowed
+	// default 50
+	QPS float64 `json:"qps,omitempty"`
+	// Burst indicates the max number of the messages that each node is allowed at once
+	// default 100
+	Burst int32 `json:"burst,omitempty"`
+	// OverflowPolicy indicates the action on the messages over the limit, one of drop and disconnect.
+	// drop drops them, disconnect drops them and closes the session of the node after MaxViolations
+	// messages are dropped
+	// default drop
+	// +kubebuilder:validation:Enum=drop;disconnect
+	OverflowPolicy UpstreamOverflowPolicy `json:"overflowPolicy,omitempty"`
+	// MaxViolations indicates the number of the dropped messages of a node after which its session is
+	// closed by the disconnect policy, the count is reset once the bucket of the node is full again
+	// default 100
+	MaxViolations int32 `json:"maxViolations,omitempty"`
+	// WarningInterval indicates the min interval of the throttling warnings of a node (second)
+	// default 60
+	WarningInterval int32 `json:"warningInterval,omitempty"`
+}
+
+// CloudHubNodeApproval indicates the approval of the node names which the edge certificates are
+// requested for with a token. A node is approved if a Node of the name exists and carries the label
+// or the annotation Key with the Value, or if the node is pre-registered. The results are cached
+// for CacheTTL, so that the requests do not hit the apiserver every time.
+type CloudHubNodeApproval struct {
+	// Enable indicates whether to require the approval of the node names
+	// default false
+	Enable bool `json:"enable"`
+	// Key indicates the key of the label or the annotation of the Node approving the node
+	// default "node.kubeedge.io/approved"
+	Key string `json:"key,omitempty"`
+	// Value indicates the value of the label or the annotation of the Node approving the node
+	// default "true"
+	Value string `json:"value,omitempty"`
+	// CacheTTL indicates how long the approval of a node is cached (second), 0 disables the cache
+	// default 30
+	CacheTTL int32 `json:"cacheTTL,omitempty"`
+}
+
+// CloudHubSigningWebhook indicates the webhook approving the edge certificate requests. CloudHub
+// POSTs a SigningReview of the request to the webhook, and signs the certificate only if the
+// webhook responds allowed. The transient failures of the webhook are retried with exponential
+// backoff, and the request is handled by the FailurePolicy if the webhook can not be reached in time.
+type CloudHubSigningWebhook struct {
+	// URL indicates the https URL of the webhook, the webhook is not called if it is empty
+	// default ""
+	URL string `json:"url,omitempty"`
+	// CABundleFile indicates the file of the PEM encoded CAs verifying the serving certificate of the
+	// webhook, the system CAs are used if it is empty
+	// default ""
+	CABundleFile string `json:"caBundleFile,omitempty"`
+	// FailurePolicy indicates how the request is handled if the webhook fails or times out,
+	// deny rejects it with 403 and allow signs the certificate
+	// default deny
+	// +kubebuilder:validation:Enum=deny;allow
+	FailurePolicy SigningWebhookFailurePolicy `json:"failurePolicy,omitempty"`
+	// Retry indicates the retry of the webhook on transient failures, which are the network errors
+	// and the 429 and 5xx responses
+	Retry *SigningWebhookRetry `json:"retry,omitempty"`
+}
+
+// SigningWebhookRetry indicates the exponential backoff retry of the signing webhook.
+type SigningWebhookRetry struct {
+	// Steps indicates the max number of attempts of the webhook, 1 disables the retry
+	// default 3
+	Steps int32 `json:"steps,omitempty"`
+	// InitialInterval indicates the interval before the first retry (millisecond),
+	// which is doubled before each further retry
+	// default 200
+	InitialInterval int32 `json:"initialInterval,omitempty"`
+	// Timeout indicates the max time of all the attempts (millisecond)
+	// default 5000
+	Timeout int32 `json:"timeout,omitempty"`
+}
+
+// CloudHubIssuanceQuota indicates the quotas of the edge nodes holding issued and unexpired
+// certificates per tenant. A node belongs to the first tenant it matches, and the nodes
+// matching no tenant are not limited. Renewals of the nodes are not counted as new issuance.
+type CloudHubIssuanceQuota struct {
+	// Tenants indicates the tenants and their quotas
+	Tenants []IssuanceQuotaTenant `json:"tenants,omitempty"`
+}
+
+// IssuanceQuotaTenant indicates a tenant and its quota, the nodes of the tenant are matched
+// by the node name prefix or the node group.
+type IssuanceQuotaTenant struct {
+	// Name indicates the name of the tenant
+	Name string `json:"name"`
+	// NodeNamePrefix indicates the prefix of the names of the nodes of the tenant
+	NodeNamePrefix string `json:"nodeNamePrefix,omitempty"`
+	// NodeGroup indicates the node group of the nodes of the tenant, it only matches
+	// the nodes that already exist with the node group label
+	NodeGroup string `json:"nodeGroup,omitempty"`
+	// MaxCertificates indicates the max number of the nodes of the tenant holding
+	// issued and unexpired certificates
+	MaxCertificates int32 `json:"maxCertificates"`
+}
+
+// CloudHubSignaturePolicy indicates the policy of the signature algorithms of the issued edge
+// certificates and the CSRs. The SHA-1 and MD5 based algorithms are never allowed.
+type CloudHubSignaturePolicy struct {
+	// AllowedAlgorithms indicates the allowed signature algorithms of the issued edge certificates
+	// and the CSRs, in the names of x509.SignatureAlgorithm, such as ECDSA-SHA384 and SHA256-RSA.
+	// All the algorithms except the weak ones are allowed if it is empty
+	AllowedAlgorithms []string `json:"allowedAlgorithms,omitempty"`
+	// Digests indicates the preferred digests of the signatures per CA key type. The key types are
+	// RSA, ECDSA-P256, ECDSA-P384 and ECDSA-P521, and the digests are SHA256, SHA384 and SHA512.
+	// Ed25519 keys sign without a separate digest, so no digest can be preferred for them
+	// default SHA256 for RSA and ECDSA-P256, SHA384 for ECDSA-P384 and SHA512 for ECDSA-P521
+	Digests map[string]string `json:"digests,omitempty"`
+}
+
+// CloudHubCSRSANPolicy indicates the policy of the SANs embedded in the CSRs. A DNS or IP SAN is allowed
+// if it is an address in the status of the Node, or a loopback address unless RejectLoopback is set.
+// Only the loopback addresses are allowed if the Node doesn't exist yet.
+type CloudHubCSRSANPolicy struct {
+	// AllowedDNSSuffixes indicates the DNS suffixes that the DNS SANs are allowed to be under besides the
+	// addresses of the Node, e.g. edge.example.com allows node1.edge.example.com
+	// default nil
+	AllowedDNSSuffixes []string `json:"allowedDNSSuffixes,omitempty"`
+	// RejectLoopback indicates whether to reject the loopback SANs, i.e. localhost, 127.0.0.0/8 and ::1,
+	// which the edge servers only serving the local clients of the node are certified for
+	// default false
+	RejectLoopback bool `json:"rejectLoopback,omitempty"`
+}
+
+// CloudHubSPIFFE indicates the SPIFFE identities of the edge nodes, which are
+// spiffe://<trustDomain>/node/<nodeName>. The edge certificates carrying the SPIFFE ID of
+// a node identify the node as well as the ones with the CommonName system:node:<nodeName>.
+type CloudHubSPIFFE struct {
+	// Enable indicates whether to add the SPIFFE IDs of the edge nodes to their certificates
+	// default false
+	Enable bool `json:"enable"`
+	// TrustDomain indicates the SPIFFE trust domain of the edge nodes, e.g. example.org,
+	// which is required if Enable is true
+	// default ""
+	TrustDomain string `json:"trustDomain,omitempty"`
+}
+
+// CloudHubCertExtension indicates a custom extension of the edge certificates. The value of the
+// extension is the value of the label or the annotation of the node as a UTF8String, and the extension
+// is not added if the node doesn't exist or the value is empty. The extension is never critical.
+type CloudHubCertExtension struct {
+	// OID indicates the dotted OID of the extension, e.g. 1.3.6.1.4.1.55555.2.1, which must not
+	// be the OID of a standard extension
+	OID string `json:"oid"`
+	// Label indicates the label of the node whose value is the value of the extension
+	Label string `json:"label,omitempty"`
+	// Annotation indicates the annotation of the node whose value is the value of the extension,
+	// only one of Label and Annotation can be set
+	Annotation string `json:"annotation,omitempty"`
+}
+
+// CloudHubExtKeyUsagePolicy indicates the policy of the ExtKeyUsages requested for the edge certificates.
+// ServerAuth lets a node act as a server behind the CA of CloudHub, so it is only issued to the nodes
+// authenticated by their current certificates unless it is allowed with a token.
+type CloudHubExtKeyUsagePolicy struct {
+	// AllowedUsages indicates the ExtKeyUsages that the edge nodes may request, in the names of
+	// x509.ExtKeyUsage without the prefix, which are ClientAuth and ServerAuth
+	// default ClientAuth, ServerAuth
+	AllowedUsages []string `json:"allowedUsages,omitempty"`
+	// AllowServerAuthWithToken indicates whether the requests authenticated by a token or a
+	// pre-registration may request ServerAuth, otherwise they are rejected
+	// default false
+	AllowServerAuthWithToken bool `json:"allowServerAuthWithToken,omitempty"`
+}
+
+// CloudHubCSRSubjectPolicy indicates the policy of the Subject attributes embedded in the CSRs besides
+// the node identity. The allowed attributes of a CSR are kept in the issued certificate, and the other
+// attributes are stripped or rejected.
+type CloudHubCSRSubjectPolicy struct {
+	// Action indicates the action on the Subject attributes of a CSR that are not allowed, one of strip
+	// and reject. strip signs the certificate without them, reject refuses the CSR with 400
+	// default strip
+	// +kubebuilder:validation:Enum=strip;reject
+	Action CSRSubjectAction `json:"action,omitempty"`
+	// AllowedAttributes indicates the Subject attributes allowed besides the CommonName and the
+	// Organization, in the short names C, ST, L, STREET, POSTALCODE, OU, SERIALNUMBER and
+	// emailAddress, or in the dotted OIDs such as 1.3.6.1.4.1.55555.1
+	AllowedAttributes []string `json:"allowedAttributes,omitempty"`
+}
+
+// CloudHubIssuer indicates a named issuer of the edge certificates of a logical edge fleet. The issuer
+// of a request is selected by the X-Cert-Issuer header, or by the node group of the node if the
+// header is not set, and the certificates presented by the nodes are verified by its CA.
+type CloudHubIssuer struct {
+	// Name indicates the name of the issuer
+	Name string `json:"name"`
+	// CAFile indicates the CA file path of the issuer
+	CAFile string `json:"caFile"`
+	// CAKeyFile indicates the CA key file path of the issuer
+	CAKeyFile string `json:"caKeyFile"`
+	// IntermediateCAFile indicates the PEM file of the intermediate CA certificates of the issuer,
+	// starting with the CA of CAFile and excluding the root, see TLSIntermediateCAFile of CloudHub
+	IntermediateCAFile string `json:"intermediateCAFile,omitempty"`
+	// NodeGroups indicates the node groups whose nodes are signed by the issuer by default,
+	// a node group can only be mapped to one issuer
+	NodeGroups []string `json:"nodeGroups,omitempty"`
+}
+
+// CloudHubStandby indicates the config of the warm standby mode. The replicas elect the leader
+// with a lease, and the leader publishes its advertised address on the lease. The standby
+// replicas serve the CA and the verification requests, and redirect the signing requests
+// to the leader, or respond 503 if the leader is unknown.
+type CloudHubStandby struct {
+	// Enable indicates whether to run in the warm standby mode
+	// default false
+	Enable bool `json:"enable"`
+	// LeaseNamespace indicates the namespace of the lease of the leader election
+	// default "kubeedge"
+	LeaseNamespace string `json:"leaseNamespace,omitempty"`
+	// LeaseName indicates the name of the lease of the leader election
+	// default "cloudhub-signer"
+	LeaseName string `json:"leaseName,omitempty"`
+	// LeaseDuration indicates the duration that the standby replicas wait before taking over
+	// the lease of an unresponsive leader (second)
+	// default 15
+	LeaseDuration int32 `json:"leaseDuration,omitempty"`
+	// RenewDeadline indicates the duration that the leader retries renewing the lease before
+	// giving up the leadership (second), it must be less than LeaseDuration
+	// default 10
+	RenewDeadline int32 `json:"renewDeadline,omitempty"`
+	// RetryPeriod indicates the interval between the tries of acquiring or renewing the lease (second),
+	// it must be less than RenewDeadline
+	// default 2
+	RetryPeriod int32 `json:"retryPeriod,omitempty"`
+}
+
+// CloudHubSessionRegistry indicates the config of the session registry. Each CloudHub instance
+// holds a lease for every edge node connected to it, so that the downstream messages of the nodes
+// connected to other instances are forwarded to the instances holding their leases.
+type CloudHubSessionRegistry struct {
+	// LeaseNamespace indicates the namespace of the session leases
+	// default "kubeedge"
+	LeaseNamespace string `json:"leaseNamespace,omitempty"`
+	// LeaseDuration indicates the duration after which the session lease of an instance
+	// that stopped renewing it is considered expired (second)
+	// default 40
+	LeaseDuration int32 `json:"leaseDuration,omitempty"`
+	// RenewInterval indicates the interval of renewing the session leases (second),
+	// it must be less than LeaseDuration
+	// default 10
+	RenewInterval int32 `json:"renewInterval,omitempty"`
+}
+
+// CloudHubSessionDrain indicates the drain of the sessions when CloudHub stops. The edge nodes
+// are asked to reconnect after a random delay within the reconnect window, and their sessions are
+// closed one by one within the timeout, so that they don't reconnect all at once.
+type CloudHubSessionDrain struct {
+	// Enable indicates whether to drain the sessions when CloudHub stops
+	// default true
+	Enable bool `json:"enable"`
+	// Timeout indicates the duration of the drain, the remaining sessions are closed when it
+	// elapses (second), it must be no more than 300
+	// default 30
+	Timeout int32 `json:"timeout,omitempty"`
+	// ReconnectWindow indicates the window which the reconnect delays of the edge nodes are
+	// spread over (second)
+	// default 60
+	ReconnectWindow int32 `json:"reconnectWindow,omitempty"`
+}
+
+// CloudHubMessageLanes indicates the priority lanes of the downstream messages. The critical
+// messages, such as the deletes, the node changes and the certificate and token messages, are
+// sent to the edge node before the bulk resource sync.
+type CloudHubMessageLanes struct {
+	// Enable indicates whether to send the critical messages before the bulk messages
+	// default true
+	Enable bool `json:"enable"`
+	// CriticalBurst indicates the maximum number of the critical messages sent in a row while
+	// the bulk messages are waiting, after which a bulk message is sent
+	// default 10
+	CriticalBurst int32 `json:"criticalBurst,omitempty"`
+}
+
+// CloudHubOfflineStore indicates the bounded store of the downstream messages to each disconnected
+// edge node. The repeated messages of an object are coalesced into the most recent one, the oldest
+// messages are evicted when the store of the node is full, and the stored messages are sent in order
+// when the node connects, before the messages that come after.
+type CloudHubOfflineStore struct {
+	// Enable indicates whether to store the messages to the disconnected edge nodes
+	// default false
+	Enable bool `json:"enable"`
+	// MaxMessages indicates the max number of the stored messages of each node
+	// default 1000
+	MaxMessages int32 `json:"maxMessages,omitempty"`
+	// MaxBytes indicates the max total size of the contents of the stored messages of each node
+	// default 10485760
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+	// TTL indicates the time (second) after which a stored message expires
+	// default 3600
+	TTL int32 `json:"ttl,omitempty"`
+}
+
+// CloudHubNodeAdmission indicates the admission of the connections of the edge nodes by their
+// Node objects, which are checked after the TLS authentication, e.g. to stage the rollouts by the
+// labels or to refuse the quarantined nodes by the taints. The refused connections are closed with
+// a close code on which the edge nodes back off longer.
+type CloudHubNodeAdmission struct {
+	// Enable indicates whether to check the Node objects of the connecting edge nodes
+	// default false
+	Enable bool `json:"enable"`
+	// LabelSelector indicates the label selector that the nodes must match, e.g. "rollout in (stage1)",
+	// all the nodes match if it is empty
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// DenyTaints indicates the taint keys of the nodes to refuse, e.g. "node.kubeedge.io/quarantined"
+	DenyTaints []string `json:"denyTaints,omitempty"`
+	// FailOpen indicates whether to admit the nodes whose Node objects can not be fetched,
+	// they are refused if false
+	// default false
+	FailOpen bool `json:"failOpen,omitempty"`
+}
+
 // AuthorizationMode indicates an authorization mdoe
 type AuthorizationMode struct {
 	// Node node authorization
@@ -322,6 +1205,15 @@ type EdgeControllerBuffer struct {
 	// CertificateSigningRequest indicates the buffer of certificatesSigningRequest
 	// default 1024
 	CertificateSigningRequest int32 `json:"certificateSigningRequest,omitempty"`
+	// StatusBatchWindow indicates the window (millisecond) in which the pod status, node status
+	// and lease updates of the same object from the edge are coalesced into a single write to
+	// the apiserver, only the latest update of the object is written. 0 disables the batching
+	// default 0
+	StatusBatchWindow int32 `json:"statusBatchWindow,omitempty"`
+	// StatusBatchSize indicates the max number of the objects whose updates are held in a batch,
+	// the batch is flushed before the window ends once it is full
+	// default 256
+	StatusBatchSize int32 `json:"statusBatchSize,omitempty"`
 }
 
 // EdgeControllerLoad indicates the EdgeController load
@@ -550,3 +1442,19 @@ type IptablesManager struct {
 	// +kubebuilder:validation:Enum=internal;external
 	Mode IptablesMgrMode `json:"mode,omitempty"`
 }
+
+// PolicyController indicates the config of PolicyController module
+type PolicyController struct {
+	// AllowedAPIGroups restricts the API groups of the rules projected to the edge.
+	// A wildcard group in a role rule only grants the allowed groups at the edge.
+	// default empty, which projects the rules of all API groups
+	AllowedAPIGroups []string `json:"allowedAPIGroups,omitempty"`
+	// DeletionFinalizer indicates whether to add a finalizer to ServiceAccountAccess,
+	// so that its deletion waits for the edge nodes to acknowledge the removal
+	// default false
+	DeletionFinalizer bool `json:"deletionFinalizer,omitempty"`
+	// DeletionAckTimeout indicates the seconds to wait for the edge nodes to acknowledge the removal,
+	// the finalizer is removed after the timeout even if some edge nodes are offline
+	// default 300
+	DeletionAckTimeout int32 `json:"deletionAckTimeout,omitempty"`
+}
@@ -19,13 +19,20 @@ package validation
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 	netutils "k8s.io/utils/net"
 
@@ -48,7 +55,22 @@ func ValidateCloudCoreConfiguration(c *v1alpha1.CloudCoreConfig) field.ErrorList
 }
 
 func ValidateCommonConfig(c v1alpha1.CommonConfig) field.ErrorList {
-	return validateHostPort(c.MonitorServer.BindAddress, field.NewPath("monitorServer.bindAddress"))
+	allErrs := validateHostPort(c.MonitorServer.BindAddress, field.NewPath("monitorServer.bindAddress"))
+	allErrs = append(allErrs, ValidateMessageTrace(c.MessageTrace)...)
+	return allErrs
+}
+
+// ValidateMessageTrace validates `m` and returns an errorList if it is invalid
+func ValidateMessageTrace(m *v1alpha1.MessageTrace) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if m == nil || !m.Enable {
+		return allErrs
+	}
+	if m.SampleRate <= 0 || m.SampleRate > 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("messageTrace.sampleRate"), m.SampleRate,
+			"SampleRate must be greater than 0 and not greater than 1"))
+	}
+	return allErrs
 }
 
 func validateHostPort(input string, fldPath *field.Path) field.ErrorList {
@@ -127,6 +149,647 @@ func ValidateModuleCloudHub(c v1alpha1.CloudHub) field.ErrorList {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("TokenRefreshDuration"),
 			c.TokenRefreshDuration, "TokenRefreshDuration must be positive"))
 	}
+	if c.SigningTimeout < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("SigningTimeout"),
+			c.SigningTimeout, "SigningTimeout must not be negative"))
+	}
+	if c.CSRDedupWindow < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("CSRDedupWindow"),
+			c.CSRDedupWindow, "CSRDedupWindow must not be negative"))
+	}
+	if a := c.AsyncSigning; a != nil && a.Enable && a.JobTTL <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("AsyncSigning").Child("JobTTL"),
+			a.JobTTL, "JobTTL must be positive"))
+	}
+	if c.MaxEdgeCertSigningDuration < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("MaxEdgeCertSigningDuration"),
+			c.MaxEdgeCertSigningDuration, "MaxEdgeCertSigningDuration must not be negative"))
+	}
+	if c.CertRenewalOverlap < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("CertRenewalOverlap"),
+			c.CertRenewalOverlap, "CertRenewalOverlap must not be negative"))
+	}
+	if c.EdgeCertBackdate < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("EdgeCertBackdate"),
+			c.EdgeCertBackdate, "EdgeCertBackdate must not be negative"))
+	} else if c.EdgeCertSigningDuration > 0 && c.EdgeCertBackdate*time.Minute >= c.EdgeCertSigningDuration*24*time.Hour {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("EdgeCertBackdate"),
+			c.EdgeCertBackdate, "EdgeCertBackdate must be shorter than EdgeCertSigningDuration"))
+	}
+	if c.CertClockSkewTolerance < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("CertClockSkewTolerance"),
+			c.CertClockSkewTolerance, "CertClockSkewTolerance must not be negative"))
+	}
+	if c.CSRMinRSAKeySize != 0 && c.CSRMinRSAKeySize < 1024 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("CSRMinRSAKeySize"),
+			c.CSRMinRSAKeySize, "CSRMinRSAKeySize must not be less than 1024"))
+	}
+	if f := c.HTTPS.ForwardedClientCert; f != nil {
+		fldPath := field.NewPath("HTTPS").Child("ForwardedClientCert")
+		switch f.Precedence {
+		case "", v1alpha1.ClientCertPreferDirect, v1alpha1.ClientCertPreferForwarded:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Precedence"),
+				f.Precedence, "must be one of prefer-direct and prefer-forwarded"))
+		}
+		switch f.Format {
+		case "", v1alpha1.ForwardedCertFormatAuto, v1alpha1.ForwardedCertFormatXFCC, v1alpha1.ForwardedCertFormatCaddy:
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Format"),
+				f.Format, "must be one of auto, xfcc and caddy"))
+		}
+		if f.Enable && len(c.HTTPS.TrustedProxies) == 0 && (c.HTTPS.UnixSocket == nil || !c.HTTPS.UnixSocket.Enable) {
+			allErrs = append(allErrs, field.Required(field.NewPath("HTTPS").Child("TrustedProxies"),
+				"the forwarded client certificates are only accepted from the trusted proxies"))
+		}
+	}
+	if c.HTTPS.MaxCSRSize < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("HTTPS").Child("MaxCSRSize"),
+			c.HTTPS.MaxCSRSize, "MaxCSRSize must not be negative"))
+	}
+	for _, h := range []struct{ name, value string }{
+		{"NodeNameHeader", c.HTTPS.NodeNameHeader},
+		{"ForwardedCertHeader", c.HTTPS.ForwardedCertHeader},
+	} {
+		if h.value == "" {
+			continue
+		}
+		for _, m := range utilvalidation.IsValidHeaderName(h.value) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("HTTPS").Child(h.name), h.value, m))
+		}
+	}
+	if u := c.HTTPS.UnixSocket; u != nil && u.Enable {
+		fldPath := field.NewPath("HTTPS").Child("UnixSocket")
+		if !filepath.IsAbs(u.Path) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Path"),
+				u.Path, "Path must be an absolute path"))
+		}
+		if u.Mode > 0777 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Mode"),
+				fmt.Sprintf("%#o", u.Mode), "Mode must be the permission bits of a file"))
+		}
+	}
+	if l := c.HTTPS.RequestRateLimit; l != nil && l.Enable {
+		fldPath := field.NewPath("HTTPS").Child("RequestRateLimit")
+		if l.QPS <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("QPS"),
+				l.QPS, "QPS must be positive"))
+		}
+		if l.Burst <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Burst"),
+				l.Burst, "Burst must be positive"))
+		}
+	}
+	if c.DuplicateEnrollment != nil {
+		switch c.DuplicateEnrollment.Policy {
+		case "", v1alpha1.DuplicateEnrollmentReject, v1alpha1.DuplicateEnrollmentAllow, v1alpha1.DuplicateEnrollmentFence:
+		default:
+			allErrs = append(allErrs, field.Invalid(field.NewPath("DuplicateEnrollment").Child("Policy"),
+				c.DuplicateEnrollment.Policy, "must be one of reject, allow and fence"))
+		}
+	}
+	if p := c.CSRSubjectPolicy; p != nil {
+		switch p.Action {
+		case "", v1alpha1.CSRSubjectStrip, v1alpha1.CSRSubjectReject:
+		default:
+			allErrs = append(allErrs, field.Invalid(field.NewPath("CSRSubjectPolicy").Child("Action"),
+				p.Action, "must be one of strip and reject"))
+		}
+	}
+	if p := c.CSRSANPolicy; p != nil {
+		for i, suffix := range p.AllowedDNSSuffixes {
+			for _, msg := range k8svalidation.IsDNS1123Subdomain(strings.TrimPrefix(suffix, ".")) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("CSRSANPolicy").Child("AllowedDNSSuffixes").Index(i),
+					suffix, msg))
+			}
+		}
+	}
+	if p := c.SPIFFE; p != nil && p.Enable {
+		if err := validateSPIFFETrustDomain(p.TrustDomain); err != "" {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("SPIFFE").Child("TrustDomain"),
+				p.TrustDomain, err))
+		}
+	}
+	for i, ext := range c.CertExtensions {
+		fldPath := field.NewPath("CertExtensions").Index(i)
+		if ext.OID == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("OID"), "OID of the extension is required"))
+		}
+		if (ext.Label == "") == (ext.Annotation == "") {
+			allErrs = append(allErrs, field.Invalid(fldPath, ext.OID, "exactly one of Label and Annotation must be set"))
+		}
+		for _, other := range c.CertExtensions[:i] {
+			if ext.OID != "" && other.OID == ext.OID {
+				allErrs = append(allErrs, field.Duplicate(fldPath.Child("OID"), ext.OID))
+				break
+			}
+		}
+	}
+	if p := c.ExtKeyUsagePolicy; p != nil {
+		for i, usage := range p.AllowedUsages {
+			if usage != "ClientAuth" && usage != "ServerAuth" {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("ExtKeyUsagePolicy").Child("AllowedUsages").Index(i),
+					usage, "must be one of ClientAuth and ServerAuth"))
+			}
+		}
+	}
+	if c.CAKeyPassphraseFile != "" && c.CAKeyKEKFile != "" {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("CAKeyKEKFile"), c.CAKeyKEKFile,
+			"CAKeyPassphraseFile and CAKeyKEKFile are mutually exclusive"))
+	}
+	if p := c.CAKeyProvider; p != nil {
+		fldPath := field.NewPath("CAKeyProvider")
+		if p.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("Name"), "Name of the CA key provider is required"))
+		}
+		if p.KeyURI == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("KeyURI"), "KeyURI of the CA key provider is required"))
+		}
+		if c.TLSCAFile == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("TLSCAFile"), "TLSCAFile is required by the CA key provider"))
+		}
+	}
+	switch c.EdgeCertSigner {
+	case "", v1alpha1.EdgeCertSignerLocal:
+	case v1alpha1.EdgeCertSignerCSRAPI:
+		allErrs = append(allErrs, validateCSRAPISigner(c.CSRAPISigner)...)
+	default:
+		allErrs = append(allErrs, field.Invalid(field.NewPath("EdgeCertSigner"),
+			c.EdgeCertSigner, "must be one of local and csr-api"))
+	}
+	if r := c.Revocation; r != nil {
+		fldPath := field.NewPath("Revocation")
+		if r.Persist && r.ConfigMapName == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("ConfigMapName"),
+				"ConfigMapName is required to persist the revoked certificates"))
+		}
+		if r.SessionCheckInterval <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("SessionCheckInterval"),
+				r.SessionCheckInterval, "SessionCheckInterval must be positive"))
+		}
+	}
+	if s := c.CertExpiryScan; s != nil && s.Enable {
+		fldPath := field.NewPath("CertExpiryScan")
+		if s.Interval <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Interval"),
+				s.Interval, "Interval must be positive"))
+		}
+		if s.Threshold <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Threshold"),
+				s.Threshold, "Threshold must be positive"))
+		}
+		if s.GracePeriod < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("GracePeriod"),
+				s.GracePeriod, "GracePeriod must not be negative"))
+		}
+	}
+	if o := c.OCSP; o != nil && o.Enable {
+		fldPath := field.NewPath("OCSP")
+		if o.URL != "" {
+			if u, err := url.Parse(o.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("URL"),
+					o.URL, "URL must be an http or https URL"))
+			}
+		}
+		if (o.SignerCertFile == "") != (o.SignerKeyFile == "") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("SignerKeyFile"),
+				o.SignerKeyFile, "SignerCertFile and SignerKeyFile must be set together"))
+		}
+		if o.ValidityPeriod <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ValidityPeriod"),
+				o.ValidityPeriod, "ValidityPeriod must be positive"))
+		}
+	}
+	if a := c.NodeApproval; a != nil && a.Enable {
+		fldPath := field.NewPath("NodeApproval")
+		for _, msg := range k8svalidation.IsQualifiedName(a.Key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Key"), a.Key, msg))
+		}
+		if a.CacheTTL < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("CacheTTL"),
+				a.CacheTTL, "CacheTTL must not be negative"))
+		}
+	}
+	if r := c.ReliableDelivery; r != nil && r.Enable {
+		fldPath := field.NewPath("ReliableDelivery")
+		if r.QueueDepth <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("QueueDepth"),
+				r.QueueDepth, "QueueDepth must be positive"))
+		}
+		if r.TTL <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("TTL"),
+				r.TTL, "TTL must be positive"))
+		}
+		if r.InitialBackoff <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("InitialBackoff"),
+				r.InitialBackoff, "InitialBackoff must be positive"))
+		}
+		if r.MaxBackoff < r.InitialBackoff {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxBackoff"),
+				r.MaxBackoff, "MaxBackoff must not be less than InitialBackoff"))
+		}
+	}
+	if l := c.UpstreamRateLimit; l != nil && l.Enable {
+		fldPath := field.NewPath("UpstreamRateLimit")
+		if l.QPS <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("QPS"),
+				l.QPS, "QPS must be positive"))
+		}
+		if l.Burst <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Burst"),
+				l.Burst, "Burst must be positive"))
+		}
+		switch l.OverflowPolicy {
+		case "", v1alpha1.UpstreamOverflowDrop:
+		case v1alpha1.UpstreamOverflowDisconnect:
+			if l.MaxViolations <= 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxViolations"),
+					l.MaxViolations, "MaxViolations must be positive with the disconnect policy"))
+			}
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("OverflowPolicy"),
+				l.OverflowPolicy, "must be one of drop and disconnect"))
+		}
+		if l.WarningInterval < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("WarningInterval"),
+				l.WarningInterval, "WarningInterval must not be negative"))
+		}
+	}
+	if l := c.SigningRateLimit; l != nil && l.Enable {
+		fldPath := field.NewPath("SigningRateLimit")
+		if l.Requests <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Requests"),
+				l.Requests, "Requests must be positive"))
+		}
+		if l.Window <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Window"),
+				l.Window, "Window must be positive"))
+		}
+		switch l.Backend {
+		case "", v1alpha1.RateLimitBackendMemory:
+		case v1alpha1.RateLimitBackendLease:
+			if l.LeaseNamespace == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("LeaseNamespace"),
+					"LeaseNamespace is required by the lease backend"))
+			}
+		default:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Backend"),
+				l.Backend, "must be one of memory and lease"))
+		}
+	}
+	if d := c.DelegatedSigning; d != nil && d.Retry != nil {
+		fldPath := field.NewPath("DelegatedSigning").Child("Retry")
+		if d.Retry.Steps < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Steps"),
+				d.Retry.Steps, "Steps must not be negative"))
+		}
+		if d.Retry.InitialInterval < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("InitialInterval"),
+				d.Retry.InitialInterval, "InitialInterval must not be negative"))
+		}
+		if d.Retry.Timeout < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Timeout"),
+				d.Retry.Timeout, "Timeout must not be negative"))
+		}
+	}
+	if c.SigningWebhook != nil {
+		allErrs = append(allErrs, validateSigningWebhook(c.SigningWebhook)...)
+	}
+	if c.IssuanceQuota != nil {
+		allErrs = append(allErrs, validateIssuanceQuota(c.IssuanceQuota)...)
+	}
+	switch c.ServerKeyAlgorithm {
+	case "", "RSA", "ECDSA-P256", "ECDSA-P384", "ECDSA-P521", "Ed25519":
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("ServerKeyAlgorithm"), c.ServerKeyAlgorithm,
+			[]string{"RSA", "ECDSA-P256", "ECDSA-P384", "ECDSA-P521", "Ed25519"}))
+	}
+	if c.SignaturePolicy != nil {
+		allErrs = append(allErrs, validateSignaturePolicy(c.SignaturePolicy)...)
+	}
+	if len(c.Issuers) > 0 {
+		allErrs = append(allErrs, validateIssuers(c.Issuers)...)
+	}
+	if c.Standby != nil && c.Standby.Enable {
+		allErrs = append(allErrs, validateStandby(c.Standby)...)
+	}
+	if _, err := cliflag.TLSVersion(c.TLSMinVersion); err != nil {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("TLSMinVersion"),
+			c.TLSMinVersion, cliflag.TLSPossibleVersions()))
+	}
+	for _, name := range c.TLSCipherSuites {
+		if _, err := cliflag.TLSCipherSuites([]string{name}); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("TLSCipherSuites"),
+				name, "unsupported cipher suite"))
+		}
+	}
+	if r := c.SessionRegistry; r != nil {
+		fldPath := field.NewPath("SessionRegistry")
+		if r.LeaseNamespace == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("LeaseNamespace"), "lease namespace is required"))
+		}
+		if r.RenewInterval <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("RenewInterval"),
+				r.RenewInterval, "RenewInterval must be positive"))
+		}
+		if r.LeaseDuration <= r.RenewInterval {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("LeaseDuration"),
+				r.LeaseDuration, "LeaseDuration must be greater than RenewInterval"))
+		}
+	}
+	if d := c.SessionDrain; d != nil && d.Enable {
+		fldPath := field.NewPath("SessionDrain")
+		if d.Timeout <= 0 || d.Timeout > 300 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Timeout"),
+				d.Timeout, "Timeout must be in (0, 300]"))
+		}
+		if d.ReconnectWindow <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ReconnectWindow"),
+				d.ReconnectWindow, "ReconnectWindow must be positive"))
+		}
+	}
+	switch c.DuplicateSessionPolicy {
+	case "", v1alpha1.DuplicateSessionTakeover, v1alpha1.DuplicateSessionReject:
+	default:
+		allErrs = append(allErrs, field.Invalid(field.NewPath("DuplicateSessionPolicy"),
+			c.DuplicateSessionPolicy, "must be one of takeover and reject"))
+	}
+	if c.MinEdgeSchemaVersion < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("MinEdgeSchemaVersion"),
+			c.MinEdgeSchemaVersion, "MinEdgeSchemaVersion must not be negative"))
+	}
+	if a := c.NodeAdmission; a != nil && a.Enable {
+		allErrs = append(allErrs, validateNodeAdmission(a)...)
+	}
+	if ws := c.WebSocket; ws != nil {
+		allErrs = append(allErrs, validateMessageLimits(field.NewPath("WebSocket"), ws.MaxReadMessageSize,
+			ws.MaxWriteMessageSize, ws.ReadBufferSize, ws.WriteBufferSize)...)
+	}
+	if q := c.Quic; q != nil {
+		allErrs = append(allErrs, validateMessageLimits(field.NewPath("Quic"), q.MaxReadMessageSize,
+			q.MaxWriteMessageSize, q.ReadBufferSize, q.WriteBufferSize)...)
+	}
+	if l := c.MessageLanes; l != nil && l.Enable && l.CriticalBurst <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("MessageLanes").Child("CriticalBurst"),
+			l.CriticalBurst, "CriticalBurst must be positive"))
+	}
+	if o := c.OfflineStore; o != nil && o.Enable {
+		fldPath := field.NewPath("OfflineStore")
+		if o.MaxMessages <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxMessages"),
+				o.MaxMessages, "MaxMessages must be positive"))
+		}
+		if o.MaxBytes <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxBytes"),
+				o.MaxBytes, "MaxBytes must be positive"))
+		}
+		if o.TTL <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("TTL"),
+				o.TTL, "TTL must be positive"))
+		}
+	}
+	if l := c.IssuanceLog; l != nil {
+		if l.PruneRetention < 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("IssuanceLog").Child("PruneRetention"),
+				l.PruneRetention, "PruneRetention must not be negative"))
+		}
+		if l.PruneInterval < 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("IssuanceLog").Child("PruneInterval"),
+				l.PruneInterval, "PruneInterval must not be negative"))
+		}
+		if l.MaxRecordsPerNode < 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("IssuanceLog").Child("MaxRecordsPerNode"),
+				l.MaxRecordsPerNode, "MaxRecordsPerNode must not be negative"))
+		}
+	}
+	return allErrs
+}
+
+// validateNodeAdmission validates the label selector and the deny taints of the node admission
+func validateNodeAdmission(a *v1alpha1.CloudHubNodeAdmission) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("NodeAdmission")
+	if _, err := labels.Parse(a.LabelSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("LabelSelector"), a.LabelSelector, err.Error()))
+	}
+	for i, key := range a.DenyTaints {
+		for _, msg := range k8svalidation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("DenyTaints").Index(i), key, msg))
+		}
+	}
+	return allErrs
+}
+
+// validateMessageLimits validates the max message sizes and the buffer sizes of the protocol
+func validateMessageLimits(fldPath *field.Path, maxRead, maxWrite int64, readBuffer, writeBuffer int32) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, m := range utilvalidation.IsValidMessageSize(maxRead) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxReadMessageSize"), maxRead, m))
+	}
+	for _, m := range utilvalidation.IsValidMessageSize(maxWrite) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxWriteMessageSize"), maxWrite, m))
+	}
+	for _, m := range utilvalidation.IsValidBufferSize(readBuffer) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ReadBufferSize"), readBuffer, m))
+	}
+	for _, m := range utilvalidation.IsValidBufferSize(writeBuffer) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("WriteBufferSize"), writeBuffer, m))
+	}
+	return allErrs
+}
+
+func validateCSRAPISigner(s *v1alpha1.CloudHubCSRAPISigner) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("CSRAPISigner")
+	if s == nil {
+		return append(allErrs, field.Required(fldPath, "CSRAPISigner is required by the csr-api EdgeCertSigner"))
+	}
+	if domain, path, ok := strings.Cut(s.SignerName, "/"); !ok || domain == "" || path == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("SignerName"),
+			s.SignerName, "SignerName must be in the form of <domain>/<path>"))
+	}
+	if s.WaitTimeout <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("WaitTimeout"),
+			s.WaitTimeout, "WaitTimeout must be positive"))
+	}
+	return allErrs
+}
+
+func validateSigningWebhook(w *v1alpha1.CloudHubSigningWebhook) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("SigningWebhook")
+	if w.URL != "" {
+		if u, err := url.Parse(w.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("URL"),
+				w.URL, "URL must be an https URL"))
+		}
+	}
+	switch w.FailurePolicy {
+	case "", v1alpha1.SigningWebhookDeny, v1alpha1.SigningWebhookAllow:
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("FailurePolicy"),
+			w.FailurePolicy, "must be one of deny and allow"))
+	}
+	if r := w.Retry; r != nil {
+		if r.Steps < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Retry").Child("Steps"),
+				r.Steps, "Steps must not be negative"))
+		}
+		if r.InitialInterval < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Retry").Child("InitialInterval"),
+				r.InitialInterval, "InitialInterval must not be negative"))
+		}
+		if r.Timeout < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("Retry").Child("Timeout"),
+				r.Timeout, "Timeout must not be negative"))
+		}
+	}
+	return allErrs
+}
+
+func validateIssuanceQuota(q *v1alpha1.CloudHubIssuanceQuota) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := make(map[string]bool)
+	for i, t := range q.Tenants {
+		fldPath := field.NewPath("IssuanceQuota").Child("Tenants").Index(i)
+		if t.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("Name"), "tenant name is required"))
+		} else if names[t.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("Name"), t.Name))
+		}
+		names[t.Name] = true
+		if t.NodeNamePrefix == "" && t.NodeGroup == "" {
+			allErrs = append(allErrs, field.Required(fldPath, "one of NodeNamePrefix and NodeGroup is required"))
+		}
+		if t.MaxCertificates < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxCertificates"),
+				t.MaxCertificates, "MaxCertificates must not be negative"))
+		}
+	}
+	return allErrs
+}
+
+func validateIssuers(issuers []v1alpha1.CloudHubIssuer) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := make(map[string]bool)
+	groups := make(map[string]string)
+	for i, iss := range issuers {
+		fldPath := field.NewPath("Issuers").Index(i)
+		if iss.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("Name"), "issuer name is required"))
+		} else if names[iss.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("Name"), iss.Name))
+		}
+		names[iss.Name] = true
+		if iss.CAFile == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("CAFile"), "CA file of the issuer is required"))
+		}
+		if iss.CAKeyFile == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("CAKeyFile"), "CA key file of the issuer is required"))
+		}
+		for j, group := range iss.NodeGroups {
+			if other, ok := groups[group]; ok {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("NodeGroups").Index(j), group,
+					fmt.Sprintf("node group is already mapped to issuer %s", other)))
+				continue
+			}
+			groups[group] = iss.Name
+		}
+	}
+	return allErrs
+}
+
+func validateStandby(s *v1alpha1.CloudHubStandby) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("Standby")
+	if s.LeaseNamespace == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("LeaseNamespace"), "lease namespace is required"))
+	}
+	if s.LeaseName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("LeaseName"), "lease name is required"))
+	}
+	if s.RetryPeriod <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("RetryPeriod"),
+			s.RetryPeriod, "RetryPeriod must be positive"))
+	}
+	if s.RenewDeadline <= s.RetryPeriod {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("RenewDeadline"),
+			s.RenewDeadline, "RenewDeadline must be greater than RetryPeriod"))
+	}
+	if s.LeaseDuration <= s.RenewDeadline {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("LeaseDuration"),
+			s.LeaseDuration, "LeaseDuration must be greater than RenewDeadline"))
+	}
+	return allErrs
+}
+
+// validateSPIFFETrustDomain returns why the SPIFFE trust domain is invalid, or "" if it is valid.
+// A trust domain is the host part of the SPIFFE IDs, which is made of lowercase letters,
+// digits, dots, dashes and underscores, without a scheme, a port or a path.
+func validateSPIFFETrustDomain(td string) string {
+	if td == "" {
+		return "TrustDomain is required when SPIFFE is enabled"
+	}
+	if len(td) > 255 {
+		return "TrustDomain must be no more than 255 characters"
+	}
+	for _, r := range td {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+		default:
+			return fmt.Sprintf("TrustDomain must only contain lowercase letters, digits, '.', '-' and '_', "+
+				"found %q, it is the trust domain without spiffe:// or a path", r)
+		}
+	}
+	return ""
+}
+
+// weakSignatureAlgorithms are the names of the signature algorithms that can not be allowed.
+var weakSignatureAlgorithms = []string{"MD2-RSA", "MD5-RSA", "SHA1-RSA", "DSA-SHA1", "DSA-SHA256", "ECDSA-SHA1"}
+
+func validateSignaturePolicy(p *v1alpha1.CloudHubSignaturePolicy) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("SignaturePolicy")
+	for i, algo := range p.AllowedAlgorithms {
+		if slices.Contains(weakSignatureAlgorithms, algo) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("AllowedAlgorithms").Index(i),
+				algo, "the signature algorithm is too weak to be allowed"))
+		}
+	}
+	keyTypes := make([]string, 0, len(p.Digests))
+	for keyType := range p.Digests {
+		keyTypes = append(keyTypes, keyType)
+	}
+	sort.Strings(keyTypes)
+	for _, keyType := range keyTypes {
+		digest := p.Digests[keyType]
+		digestPath := fldPath.Child("Digests").Key(keyType)
+		var algo string
+		switch keyType {
+		case "RSA":
+			algo = digest + "-RSA"
+		case "ECDSA-P256", "ECDSA-P384", "ECDSA-P521":
+			algo = "ECDSA-" + digest
+		case "Ed25519":
+			allErrs = append(allErrs, field.Invalid(digestPath, digest,
+				"Ed25519 keys sign without a separate digest"))
+			continue
+		default:
+			allErrs = append(allErrs, field.NotSupported(digestPath, keyType,
+				[]string{"RSA", "ECDSA-P256", "ECDSA-P384", "ECDSA-P521"}))
+			continue
+		}
+		switch digest {
+		case "SHA256", "SHA384", "SHA512":
+		default:
+			allErrs = append(allErrs, field.NotSupported(digestPath, digest, []string{"SHA256", "SHA384", "SHA512"}))
+			continue
+		}
+		if len(p.AllowedAlgorithms) > 0 && !slices.Contains(p.AllowedAlgorithms, algo) {
+			allErrs = append(allErrs, field.Invalid(digestPath, digest,
+				fmt.Sprintf("the selected signature algorithm %s is not allowed", algo)))
+		}
+	}
 	return allErrs
 }
 
@@ -139,6 +802,16 @@ func ValidateModuleEdgeController(e v1alpha1.EdgeController) field.ErrorList {
 	if e.NodeUpdateFrequency <= 0 {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("NodeUpdateFrequency"), e.NodeUpdateFrequency, "NodeUpdateFrequency need > 0"))
 	}
+	if b := e.Buffer; b != nil {
+		if b.StatusBatchWindow < 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Buffer").Child("StatusBatchWindow"),
+				b.StatusBatchWindow, "StatusBatchWindow must not be negative"))
+		}
+		if b.StatusBatchWindow > 0 && b.StatusBatchSize <= 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Buffer").Child("StatusBatchSize"),
+				b.StatusBatchSize, "StatusBatchSize need > 0 when the batching is enabled"))
+		}
+	}
 	return allErrs
 }
 
@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/kubeedge/api/apis/componentconfig/cloudcore/v1alpha1"
@@ -225,6 +226,1482 @@ func TestValidateModuleCloudHub(t *testing.T) {
 			expected: field.ErrorList{field.Invalid(field.NewPath("TokenRefreshDuration"),
 				time.Duration(0), "TokenRefreshDuration must be positive")},
 		},
+		{
+			name: "case9 invalid DuplicateEnrollment policy",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				DuplicateEnrollment: &v1alpha1.CloudHubDuplicateEnrollment{
+					Policy: "kick",
+				},
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("DuplicateEnrollment").Child("Policy"),
+				v1alpha1.DuplicateEnrollmentPolicy("kick"), "must be one of reject, allow and fence")},
+		},
+		{
+			name: "case10 invalid IssuanceQuota tenants",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				IssuanceQuota: &v1alpha1.CloudHubIssuanceQuota{
+					Tenants: []v1alpha1.IssuanceQuotaTenant{
+						{Name: "bu1", NodeNamePrefix: "bu1-", MaxCertificates: 10},
+						{Name: "bu1", NodeGroup: "bu1", MaxCertificates: -1},
+						{MaxCertificates: 10},
+					},
+				},
+			},
+			expected: field.ErrorList{
+				field.Duplicate(field.NewPath("IssuanceQuota").Child("Tenants").Index(1).Child("Name"), "bu1"),
+				field.Invalid(field.NewPath("IssuanceQuota").Child("Tenants").Index(1).Child("MaxCertificates"),
+					int32(-1), "MaxCertificates must not be negative"),
+				field.Required(field.NewPath("IssuanceQuota").Child("Tenants").Index(2).Child("Name"), "tenant name is required"),
+				field.Required(field.NewPath("IssuanceQuota").Child("Tenants").Index(2), "one of NodeNamePrefix and NodeGroup is required"),
+			},
+		},
+		{
+			name: "case11 invalid IssuanceLog pruning",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				IssuanceLog: &v1alpha1.CloudHubIssuanceLog{
+					PruneRetention:    -1,
+					PruneInterval:     -1,
+					MaxRecordsPerNode: -1,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("IssuanceLog").Child("PruneRetention"),
+					time.Duration(-1), "PruneRetention must not be negative"),
+				field.Invalid(field.NewPath("IssuanceLog").Child("PruneInterval"),
+					time.Duration(-1), "PruneInterval must not be negative"),
+				field.Invalid(field.NewPath("IssuanceLog").Child("MaxRecordsPerNode"),
+					int32(-1), "MaxRecordsPerNode must not be negative"),
+			},
+		},
+		{
+			name: "case12 negative CertRenewalOverlap",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				CertRenewalOverlap:   -1,
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("CertRenewalOverlap"),
+				time.Duration(-1), "CertRenewalOverlap must not be negative")},
+		},
+		{
+			name: "case13 impossible SignaturePolicy",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				SignaturePolicy: &v1alpha1.CloudHubSignaturePolicy{
+					AllowedAlgorithms: []string{"ECDSA-SHA384", "SHA1-RSA", "SHA384-RSA"},
+					Digests: map[string]string{
+						"ECDSA-P384": "SHA384",
+						"ECDSA-P256": "SHA256",
+						"Ed25519":    "SHA384",
+						"RSA":        "MD5",
+						"DSA":        "SHA256",
+					},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("SignaturePolicy").Child("AllowedAlgorithms").Index(1),
+					"SHA1-RSA", "the signature algorithm is too weak to be allowed"),
+				field.NotSupported(field.NewPath("SignaturePolicy").Child("Digests").Key("DSA"),
+					"DSA", []string{"RSA", "ECDSA-P256", "ECDSA-P384", "ECDSA-P521"}),
+				field.Invalid(field.NewPath("SignaturePolicy").Child("Digests").Key("ECDSA-P256"),
+					"SHA256", "the selected signature algorithm ECDSA-SHA256 is not allowed"),
+				field.Invalid(field.NewPath("SignaturePolicy").Child("Digests").Key("Ed25519"),
+					"SHA384", "Ed25519 keys sign without a separate digest"),
+				field.NotSupported(field.NewPath("SignaturePolicy").Child("Digests").Key("RSA"),
+					"MD5", []string{"SHA256", "SHA384", "SHA512"}),
+			},
+		},
+		{
+			name: "case14 invalid Issuers",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				Issuers: []v1alpha1.CloudHubIssuer{
+					{Name: "fleet-a", CAFile: "/etc/kubeedge/fleet-a/ca.crt", CAKeyFile: "/etc/kubeedge/fleet-a/ca.key",
+						NodeGroups: []string{"group-a"}},
+					{Name: "fleet-a", CAFile: "/etc/kubeedge/fleet-b/ca.crt", NodeGroups: []string{"group-b", "group-a"}},
+				},
+			},
+			expected: field.ErrorList{
+				field.Duplicate(field.NewPath("Issuers").Index(1).Child("Name"), "fleet-a"),
+				field.Required(field.NewPath("Issuers").Index(1).Child("CAKeyFile"), "CA key file of the issuer is required"),
+				field.Invalid(field.NewPath("Issuers").Index(1).Child("NodeGroups").Index(1), "group-a",
+					"node group is already mapped to issuer fleet-a"),
+			},
+		},
+		{
+			name: "case15 negative DelegatedSigning Retry",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				DelegatedSigning: &v1alpha1.CloudHubDelegatedSigning{
+					Enable: true,
+					Retry: &v1alpha1.TokenReviewRetry{
+						Steps:           -1,
+						InitialInterval: 100,
+						Timeout:         -1,
+					},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("DelegatedSigning").Child("Retry").Child("Steps"),
+					int32(-1), "Steps must not be negative"),
+				field.Invalid(field.NewPath("DelegatedSigning").Child("Retry").Child("Timeout"),
+					int32(-1), "Timeout must not be negative"),
+			},
+		},
+		{
+			name: "case16 invalid Standby",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				Standby: &v1alpha1.CloudHubStandby{
+					Enable:         true,
+					LeaseNamespace: "kubeedge",
+					LeaseDuration:  10,
+					RenewDeadline:  10,
+					RetryPeriod:    2,
+				},
+			},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("Standby").Child("LeaseName"), "lease name is required"),
+				field.Invalid(field.NewPath("Standby").Child("LeaseDuration"),
+					int32(10), "LeaseDuration must be greater than RenewDeadline"),
+			},
+		},
+		{
+			name: "case17 negative SigningTimeout",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				SigningTimeout:       -1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("SigningTimeout"), int32(-1), "SigningTimeout must not be negative"),
+			},
+		},
+		{
+			name: "case18 invalid ForwardedClientCert",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+					ForwardedClientCert: &v1alpha1.CloudHubForwardedClientCert{
+						Enable:     true,
+						Precedence: "prefer-newer",
+					},
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("HTTPS").Child("ForwardedClientCert").Child("Precedence"),
+					v1alpha1.ClientCertPrecedence("prefer-newer"), "must be one of prefer-direct and prefer-forwarded"),
+				field.Required(field.NewPath("HTTPS").Child("TrustedProxies"),
+					"the forwarded client certificates are only accepted from the trusted proxies"),
+			},
+		},
+		{
+			name: "case19 invalid AsyncSigning JobTTL",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				AsyncSigning: &v1alpha1.CloudHubAsyncSigning{
+					Enable: true,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("AsyncSigning").Child("JobTTL"),
+					int32(0), "JobTTL must be positive"),
+			},
+		},
+		{
+			name: "case20 invalid CSRSubjectPolicy Action",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				CSRSubjectPolicy: &v1alpha1.CloudHubCSRSubjectPolicy{
+					Action: "drop",
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("CSRSubjectPolicy").Child("Action"),
+					v1alpha1.CSRSubjectAction("drop"), "must be one of strip and reject"),
+			},
+		},
+		{
+			name: "case21 invalid SigningRateLimit",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				SigningRateLimit: &v1alpha1.CloudHubSigningRateLimit{
+					Enable:  true,
+					Window:  60,
+					Backend: v1alpha1.RateLimitBackendLease,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("SigningRateLimit").Child("Requests"),
+					int32(0), "Requests must be positive"),
+				field.Required(field.NewPath("SigningRateLimit").Child("LeaseNamespace"),
+					"LeaseNamespace is required by the lease backend"),
+			},
+		},
+		{
+			name: "case22 unsupported ServerKeyAlgorithm",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				ServerKeyAlgorithm:   "DSA",
+			},
+			expected: field.ErrorList{
+				field.NotSupported(field.NewPath("ServerKeyAlgorithm"), "DSA",
+					[]string{"RSA", "ECDSA-P256", "ECDSA-P384", "ECDSA-P521", "Ed25519"}),
+			},
+		},
+		{
+			name: "case23 invalid Revocation",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				Revocation: &v1alpha1.CloudHubRevocation{
+					Persist: true,
+				},
+			},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("Revocation").Child("ConfigMapName"),
+					"ConfigMapName is required to persist the revoked certificates"),
+				field.Invalid(field.NewPath("Revocation").Child("SessionCheckInterval"),
+					int32(0), "SessionCheckInterval must be positive"),
+			},
+		},
+		{
+			name: "case24 invalid NodeApproval",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				NodeApproval: &v1alpha1.CloudHubNodeApproval{
+					Enable:   true,
+					Key:      "",
+					CacheTTL: -1,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("NodeApproval").Child("Key"), "",
+					"name part must be non-empty"),
+				field.Invalid(field.NewPath("NodeApproval").Child("Key"), "",
+					"name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')"),
+				field.Invalid(field.NewPath("NodeApproval").Child("CacheTTL"),
+					int32(-1), "CacheTTL must not be negative"),
+			},
+		},
+		{
+			name: "case25 invalid ForwardedClientCert Format",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port:           10000,
+					TrustedProxies: []string{"10.0.0.1"},
+					ForwardedClientCert: &v1alpha1.CloudHubForwardedClientCert{
+						Enable: true,
+						Format: "haproxy",
+					},
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("HTTPS").Child("ForwardedClientCert").Child("Format"),
+					v1alpha1.ForwardedCertFormat("haproxy"), "must be one of auto, xfcc and caddy"),
+			},
+		},
+		{
+			name: "case26 invalid RequestRateLimit",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+					RequestRateLimit: &v1alpha1.CloudHubRequestRateLimit{
+						Enable: true,
+						QPS:    0,
+						Burst:  -1,
+					},
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("HTTPS").Child("RequestRateLimit").Child("QPS"),
+					float64(0), "QPS must be positive"),
+				field.Invalid(field.NewPath("HTTPS").Child("RequestRateLimit").Child("Burst"),
+					int32(-1), "Burst must be positive"),
+			},
+		},
+		{
+			name: "case27 invalid CSRMinRSAKeySize",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				CSRMinRSAKeySize:     512,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("CSRMinRSAKeySize"),
+					int32(512), "CSRMinRSAKeySize must not be less than 1024"),
+			},
+		},
+		{
+			name: "case28 invalid EdgeCertSigner",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				EdgeCertSigner:       "remote",
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("EdgeCertSigner"),
+					v1alpha1.EdgeCertSignerMode("remote"), "must be one of local and csr-api"),
+			},
+		},
+		{
+			name: "case29 invalid CSRAPISigner",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				EdgeCertSigner:       v1alpha1.EdgeCertSignerCSRAPI,
+				CSRAPISigner: &v1alpha1.CloudHubCSRAPISigner{
+					SignerName: "edge",
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("CSRAPISigner").Child("SignerName"),
+					"edge", "SignerName must be in the form of <domain>/<path>"),
+				field.Invalid(field.NewPath("CSRAPISigner").Child("WaitTimeout"),
+					int32(0), "WaitTimeout must be positive"),
+			},
+		},
+		{
+			name: "case30 invalid CAKeyProvider",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				CAKeyProvider:        &v1alpha1.CloudHubKeyProvider{},
+			},
+			expected: field.ErrorList{
+				field.Required(field.NewPath("CAKeyProvider").Child("Name"),
+					"Name of the CA key provider is required"),
+				field.Required(field.NewPath("CAKeyProvider").Child("KeyURI"),
+					"KeyURI of the CA key provider is required"),
+				field.Required(field.NewPath("TLSCAFile"),
+					"TLSCAFile is required by the CA key provider"),
+			},
+		},
+		{
+			name: "case31 invalid CSRSANPolicy",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				CSRSANPolicy: &v1alpha1.CloudHubCSRSANPolicy{
+					AllowedDNSSuffixes: []string{".edge.example.com", "*.example.com"},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("CSRSANPolicy").Child("AllowedDNSSuffixes").Index(1),
+					"*.example.com", k8svalidation.IsDNS1123Subdomain("*.example.com")[0]),
+			},
+		},
+		{
+			name: "case32 invalid ExtKeyUsagePolicy",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				ExtKeyUsagePolicy: &v1alpha1.CloudHubExtKeyUsagePolicy{
+					AllowedUsages: []string{"ClientAuth", "CodeSigning"},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("ExtKeyUsagePolicy").Child("AllowedUsages").Index(1),
+					"CodeSigning", "must be one of ClientAuth and ServerAuth"),
+			},
+		},
+		{
+			name: "case33 EdgeCertBackdate not shorter than EdgeCertSigningDuration",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration:    1,
+				EdgeCertSigningDuration: 1,
+				EdgeCertBackdate:        24 * 60,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("EdgeCertBackdate"),
+					time.Duration(24*60), "EdgeCertBackdate must be shorter than EdgeCertSigningDuration"),
+			},
+		},
+		{
+			name: "case34 negative CertClockSkewTolerance",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration:   1,
+				EdgeCertBackdate:       60,
+				CertClockSkewTolerance: -1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("CertClockSkewTolerance"),
+					time.Duration(-1), "CertClockSkewTolerance must not be negative"),
+			},
+		},
+		{
+			name: "case35 negative CSRDedupWindow",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				CSRDedupWindow:       -1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("CSRDedupWindow"),
+					int32(-1), "CSRDedupWindow must not be negative"),
+			},
+		},
+		{
+			name: "case36 SPIFFE without TrustDomain",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				SPIFFE: &v1alpha1.CloudHubSPIFFE{
+					Enable:      true,
+					TrustDomain: "",
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("SPIFFE").Child("TrustDomain"),
+					"", "TrustDomain is required when SPIFFE is enabled"),
+			},
+		},
+		{
+			name: "case37 invalid SPIFFE TrustDomain",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				SPIFFE: &v1alpha1.CloudHubSPIFFE{
+					Enable:      true,
+					TrustDomain: "spiffe://Example.org",
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("SPIFFE").Child("TrustDomain"),
+					"spiffe://Example.org", "TrustDomain must only contain lowercase letters, digits, '.', '-' and '_', "+
+						"found ':', it is the trust domain without spiffe:// or a path"),
+			},
+		},
+		{
+			name: "case38 invalid SigningWebhook",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				SigningWebhook: &v1alpha1.CloudHubSigningWebhook{
+					URL:           "http://approver.example.com/review",
+					FailurePolicy: "ignore",
+					Retry: &v1alpha1.SigningWebhookRetry{
+						Steps: -1,
+					},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("SigningWebhook").Child("URL"),
+					"http://approver.example.com/review", "URL must be an https URL"),
+				field.Invalid(field.NewPath("SigningWebhook").Child("FailurePolicy"),
+					v1alpha1.SigningWebhookFailurePolicy("ignore"), "must be one of deny and allow"),
+				field.Invalid(field.NewPath("SigningWebhook").Child("Retry").Child("Steps"),
+					int32(-1), "Steps must not be negative"),
+			},
+		},
+		{
+			name: "case39 both CAKeyPassphraseFile and CAKeyKEKFile",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				CAKeyPassphraseFile:  "/etc/kubeedge/ca/passphrase",
+				CAKeyKEKFile:         "/etc/kubeedge/ca/kek",
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("CAKeyKEKFile"), "/etc/kubeedge/ca/kek",
+					"CAKeyPassphraseFile and CAKeyKEKFile are mutually exclusive"),
+			},
+		},
+		{
+			name: "case40 invalid CertExpiryScan",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				CertExpiryScan: &v1alpha1.CloudHubCertExpiryScan{
+					Enable:      true,
+					Threshold:   -1,
+					GracePeriod: -1,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("CertExpiryScan").Child("Interval"),
+					int32(0), "Interval must be positive"),
+				field.Invalid(field.NewPath("CertExpiryScan").Child("Threshold"),
+					int32(-1), "Threshold must be positive"),
+				field.Invalid(field.NewPath("CertExpiryScan").Child("GracePeriod"),
+					int32(-1), "GracePeriod must not be negative"),
+			},
+		},
+		{
+			name: "case41 invalid header names",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port:                10000,
+					NodeNameHeader:      "Node Name",
+					ForwardedCertHeader: "SSL-Client-Cert",
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("HTTPS").Child("NodeNameHeader"),
+					"Node Name", "must be a valid HTTP header name, (e.g. X-Node-Name)"),
+			},
+		},
+		{
+			name: "case42 invalid OCSP",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				OCSP: &v1alpha1.CloudHubOCSP{
+					Enable:         true,
+					URL:            "ldap://ocsp.example.com",
+					SignerCertFile: "/etc/kubeedge/ocsp.crt",
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("OCSP").Child("URL"),
+					"ldap://ocsp.example.com", "URL must be an http or https URL"),
+				field.Invalid(field.NewPath("OCSP").Child("SignerKeyFile"),
+					"", "SignerCertFile and SignerKeyFile must be set together"),
+				field.Invalid(field.NewPath("OCSP").Child("ValidityPeriod"),
+					int32(0), "ValidityPeriod must be positive"),
+			},
+		},
+		{
+			name: "case43 invalid CertExtensions",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				CertExtensions: []v1alpha1.CloudHubCertExtension{
+					{OID: "1.3.6.1.4.1.55555.2.1", Label: "example.com/asset-tag"},
+					{OID: "1.3.6.1.4.1.55555.2.1", Annotation: "example.com/site-code"},
+					{Label: "example.com/rack", Annotation: "example.com/rack"},
+				},
+			},
+			expected: field.ErrorList{
+				field.Duplicate(field.NewPath("CertExtensions").Index(1).Child("OID"), "1.3.6.1.4.1.55555.2.1"),
+				field.Required(field.NewPath("CertExtensions").Index(2).Child("OID"), "OID of the extension is required"),
+				field.Invalid(field.NewPath("CertExtensions").Index(2), "", "exactly one of Label and Annotation must be set"),
+			},
+		},
+		{
+			name: "case44 negative MaxCSRSize",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port:       10000,
+					MaxCSRSize: -1,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("HTTPS").Child("MaxCSRSize"),
+					int64(-1), "MaxCSRSize must not be negative"),
+			},
+		},
+		{
+			name: "case45 invalid UpstreamRateLimit",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				UpstreamRateLimit: &v1alpha1.CloudHubUpstreamRateLimit{
+					Enable:         true,
+					QPS:            10,
+					Burst:          20,
+					OverflowPolicy: v1alpha1.UpstreamOverflowDisconnect,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("UpstreamRateLimit").Child("MaxViolations"),
+					int32(0), "MaxViolations must be positive with the disconnect policy"),
+			},
+		},
+		{
+			name: "case46 invalid ReliableDelivery",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				ReliableDelivery: &v1alpha1.CloudHubReliableDelivery{
+					Enable:         true,
+					QueueDepth:     100,
+					TTL:            60,
+					InitialBackoff: 10,
+					MaxBackoff:     5,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("ReliableDelivery").Child("MaxBackoff"),
+					int32(5), "MaxBackoff must not be less than InitialBackoff"),
+			},
+		},
+		{
+			name: "case47 invalid SessionRegistry",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				SessionRegistry: &v1alpha1.CloudHubSessionRegistry{
+					LeaseNamespace: "kubeedge",
+					LeaseDuration:  10,
+					RenewInterval:  10,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("SessionRegistry").Child("LeaseDuration"),
+					int32(10), "LeaseDuration must be greater than RenewInterval"),
+			},
+		},
+		{
+			name: "case48 invalid SessionDrain",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				SessionDrain: &v1alpha1.CloudHubSessionDrain{
+					Enable:          true,
+					Timeout:         600,
+					ReconnectWindow: 60,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("SessionDrain").Child("Timeout"),
+					int32(600), "Timeout must be in (0, 300]"),
+			},
+		},
+		{
+			name: "case49 invalid TLS options",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				TLSMinVersion:        "VersionTLS14",
+				TLSCipherSuites: []string{
+					"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+					"TLS_RSA_WITH_NULL_SHA",
+				},
+			},
+			expected: field.ErrorList{
+				field.NotSupported(field.NewPath("TLSMinVersion"), "VersionTLS14",
+					[]string{"VersionTLS10", "VersionTLS11", "VersionTLS12", "VersionTLS13"}),
+				field.Invalid(field.NewPath("TLSCipherSuites"), "TLS_RSA_WITH_NULL_SHA", "unsupported cipher suite"),
+			},
+		},
+		{
+			name: "case50 invalid DuplicateSessionPolicy",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration:   1,
+				DuplicateSessionPolicy: "ignore",
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("DuplicateSessionPolicy"),
+					v1alpha1.DuplicateSessionPolicy("ignore"), "must be one of takeover and reject"),
+			},
+		},
+		{
+			name: "case51 invalid MessageLanes",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				MessageLanes: &v1alpha1.CloudHubMessageLanes{
+					Enable:        true,
+					CriticalBurst: 0,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("MessageLanes").Child("CriticalBurst"),
+					int32(0), "CriticalBurst must be positive"),
+			},
+		},
+		{
+			name: "case52 invalid OfflineStore",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				OfflineStore: &v1alpha1.CloudHubOfflineStore{
+					Enable:      true,
+					MaxMessages: 1000,
+					MaxBytes:    -1,
+					TTL:         0,
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("OfflineStore").Child("MaxBytes"),
+					int64(-1), "MaxBytes must be positive"),
+				field.Invalid(field.NewPath("OfflineStore").Child("TTL"),
+					int32(0), "TTL must be positive"),
+			},
+		},
+		{
+			name: "case53 invalid HTTPS UnixSocket",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+					UnixSocket: &v1alpha1.CloudHubHTTPSUnixSocket{
+						Enable: true,
+						Path:   "cloudhub-https.sock",
+						Mode:   01777,
+					},
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("HTTPS").Child("UnixSocket").Child("Path"),
+					"cloudhub-https.sock", "Path must be an absolute path"),
+				field.Invalid(field.NewPath("HTTPS").Child("UnixSocket").Child("Mode"),
+					"01777", "Mode must be the permission bits of a file"),
+			},
+		},
+		{
+			name: "case54 ForwardedClientCert via the HTTPS UnixSocket",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+					ForwardedClientCert: &v1alpha1.CloudHubForwardedClientCert{
+						Enable: true,
+					},
+					UnixSocket: &v1alpha1.CloudHubHTTPSUnixSocket{
+						Enable: true,
+						Path:   "/var/lib/kubeedge/cloudhub-https.sock",
+						Mode:   0660,
+					},
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case55 invalid MinEdgeSchemaVersion",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				MinEdgeSchemaVersion: -1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("MinEdgeSchemaVersion"),
+					int32(-1), "MinEdgeSchemaVersion must not be negative"),
+			},
+		},
+		{
+			name: "case56 invalid NodeAdmission",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:    10002,
+					Address: "127.0.0.1",
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+				NodeAdmission: &v1alpha1.CloudHubNodeAdmission{
+					Enable:        true,
+					LabelSelector: "rollout in stage1",
+					DenyTaints:    []string{"node.kubeedge.io/quarantined", "bad key"},
+				},
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("NodeAdmission").Child("LabelSelector"), "rollout in stage1",
+					"unable to parse requirement: found 'stage1' expected: '('"),
+				field.Invalid(field.NewPath("NodeAdmission").Child("DenyTaints").Index(1), "bad key",
+					"name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')"),
+			},
+		},
+		{
+			name: "case57 invalid message limits",
+			input: v1alpha1.CloudHub{
+				Enable: true,
+				HTTPS: &v1alpha1.CloudHubHTTPS{
+					Port: 10000,
+				},
+				WebSocket: &v1alpha1.CloudHubWebSocket{
+					Port:               10002,
+					Address:            "127.0.0.1",
+					MaxReadMessageSize: 1024,
+					ReadBufferSize:     4096,
+				},
+				Quic: &v1alpha1.CloudHubQUIC{
+					Port:                10002,
+					Address:             "127.0.0.1",
+					MaxWriteMessageSize: 2 << 30,
+					WriteBufferSize:     -1,
+				},
+				UnixSocket: &v1alpha1.CloudHubUnixSocket{
+					Address: unixAddr,
+				},
+				TokenRefreshDuration: 1,
+			},
+			expected: field.ErrorList{
+				field.Invalid(field.NewPath("WebSocket").Child("MaxReadMessageSize"), int64(1024),
+					"must be 0 for unlimited, or must be between 65536 and 1073741824, inclusive"),
+				field.Invalid(field.NewPath("Quic").Child("MaxWriteMessageSize"), int64(2<<30),
+					"must be 0 for unlimited, or must be between 65536 and 1073741824, inclusive"),
+				field.Invalid(field.NewPath("Quic").Child("WriteBufferSize"), int32(-1),
+					"must be 0 for the default, or must be between 1024 and 67108864, inclusive"),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -265,6 +1742,35 @@ func TestValidateModuleEdgeController(t *testing.T) {
 			},
 			expected: field.ErrorList{},
 		},
+		{
+			name: "case4 StatusBatchWindow negative",
+			input: v1alpha1.EdgeController{
+				Enable:              true,
+				NodeUpdateFrequency: 10,
+				Buffer:              &v1alpha1.EdgeControllerBuffer{StatusBatchWindow: -1, StatusBatchSize: 256},
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("Buffer").Child("StatusBatchWindow"), int32(-1),
+				"StatusBatchWindow must not be negative")},
+		},
+		{
+			name: "case5 StatusBatchSize not legal with batching enabled",
+			input: v1alpha1.EdgeController{
+				Enable:              true,
+				NodeUpdateFrequency: 10,
+				Buffer:              &v1alpha1.EdgeControllerBuffer{StatusBatchWindow: 500},
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("Buffer").Child("StatusBatchSize"), int32(0),
+				"StatusBatchSize need > 0 when the batching is enabled")},
+		},
+		{
+			name: "case6 batching ok",
+			input: v1alpha1.EdgeController{
+				Enable:              true,
+				NodeUpdateFrequency: 10,
+				Buffer:              &v1alpha1.EdgeControllerBuffer{StatusBatchWindow: 500, StatusBatchSize: 256},
+			},
+			expected: field.ErrorList{},
+		},
 	}
 
 	for _, c := range cases {
@@ -519,6 +2025,32 @@ func TestValidateCommonConfig(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+		{
+			name: "invalid message trace sample rate",
+			commonConfig: v1alpha1.CommonConfig{
+				MonitorServer: v1alpha1.MonitorServer{
+					BindAddress: "127.0.0.1:9091",
+				},
+				MessageTrace: &v1alpha1.MessageTrace{
+					Enable:     true,
+					SampleRate: 0,
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "valid message trace config",
+			commonConfig: v1alpha1.CommonConfig{
+				MonitorServer: v1alpha1.MonitorServer{
+					BindAddress: "127.0.0.1:9091",
+				},
+				MessageTrace: &v1alpha1.MessageTrace{
+					Enable:     true,
+					SampleRate: 0.01,
+				},
+			},
+			expectedErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kubeedge/api/apis/common/constants"
+	"github.com/kubeedge/api/apis/common/types"
 	metaconfig "github.com/kubeedge/api/apis/componentconfig/meta/v1alpha1"
 	"github.com/kubeedge/api/apis/util"
 	"github.com/kubeedge/kubeedge/pkg/version"
@@ -78,19 +79,30 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 				TLSCAFile:         constants.DefaultCAFile,
 				TLSCertFile:       constants.DefaultCertFile,
 				TLSPrivateKeyFile: constants.DefaultKeyFile,
+				KeyAlgorithm:      "ECDSA-P256",
+				NodeNameHeader:    types.HeaderNodeName,
 				Quic: &EdgeHubQUIC{
-					Enable:           false,
-					HandshakeTimeout: 30,
-					ReadDeadline:     15,
-					Server:           net.JoinHostPort(localIP, "10001"),
-					WriteDeadline:    15,
+					Enable:              false,
+					HandshakeTimeout:    30,
+					ReadDeadline:        15,
+					Server:              net.JoinHostPort(localIP, "10001"),
+					WriteDeadline:       15,
+					MaxReadMessageSize:  64 << 20,
+					MaxWriteMessageSize: 64 << 20,
+					ReadBufferSize:      6 << 20,
+					WriteBufferSize:     4096,
 				},
 				WebSocket: &EdgeHubWebSocket{
-					Enable:           true,
-					HandshakeTimeout: 30,
-					ReadDeadline:     15,
-					Server:           net.JoinHostPort(localIP, "10000"),
-					WriteDeadline:    15,
+					Enable:              true,
+					HandshakeTimeout:    30,
+					ReadDeadline:        15,
+					Server:              net.JoinHostPort(localIP, "10000"),
+					WriteDeadline:       15,
+					EnableCompression:   false,
+					MaxReadMessageSize:  64 << 20,
+					MaxWriteMessageSize: 64 << 20,
+					ReadBufferSize:      4096,
+					WriteBufferSize:     4096,
 				},
 				HTTPServer: (&url.URL{
 					Scheme: "https",
@@ -161,6 +173,10 @@ func NewDefaultEdgeCoreConfig() (config *EdgeCoreConfig) {
 			},
 		},
 		EdgeCoreVersion: version.Get().String(),
+		MessageTrace: &MessageTrace{
+			Enable:     false,
+			SampleRate: 0.01,
+		},
 	}
 	return
 }
@@ -54,6 +54,19 @@ type EdgeCoreConfig struct {
 	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 	// EdgeCoreVersion records the latest version of edgecore
 	EdgeCoreVersion string `json:"edgecoreVersion"`
+	// MessageTrace indicates the config of tracing the messages sent to the cloud
+	MessageTrace *MessageTrace `json:"messageTrace,omitempty"`
+}
+
+// MessageTrace indicates the config of tracing the messages, the sampled messages carry
+// a trace id and are logged with it by every component they pass through
+type MessageTrace struct {
+	// Enable indicates whether the messages are traced
+	// default false
+	Enable bool `json:"enable"`
+	// SampleRate indicates the fraction of the messages traced at the entry points, in (0, 1]
+	// default 0.01
+	SampleRate float64 `json:"sampleRate,omitempty"`
 }
 
 // DataBase indicates the database info
@@ -877,11 +890,27 @@ type EdgeHub struct {
 	// Token indicates the priority of joining the cluster for the edge
 	// Deprecated: will be removed in future release, will not be saved in configuration file
 	Token string `json:"token"`
+	// SecondaryTokenFile indicates the file containing the token to retry with if the token has expired,
+	// e.g. a fresh token provisioned after the device is imaged
+	// default ""
+	SecondaryTokenFile string `json:"secondaryTokenFile,omitempty"`
 	// HTTPServer indicates the server for edge to apply for the certificate.
 	HTTPServer string `json:"httpServer,omitempty"`
 	// RotateCertificates indicates whether edge certificate can be rotated
 	// default true
 	RotateCertificates bool `json:"rotateCertificates,omitempty"`
+	// CertDuration indicates the desired validity period (hour) of the edge certificate, which
+	// cloudcore clamps to its maxEdgeCertSigningDuration. The duration of cloudcore is used if it is 0
+	// default 0
+	CertDuration int32 `json:"certDuration,omitempty"`
+	// KeyAlgorithm indicates the algorithm of the private key that EdgeHub generates for the edge
+	// certificate, one of RSA, ECDSA-P256, ECDSA-P384, ECDSA-P521 and Ed25519
+	// default ECDSA-P256
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
+	// NodeNameHeader indicates the header carrying the node name of the certificate requests,
+	// which must match the https.nodeNameHeader of CloudHub
+	// default NodeName
+	NodeNameHeader string `json:"nodeNameHeader,omitempty"`
 }
 
 // EdgeHubQUIC indicates the quic client config
@@ -901,6 +930,22 @@ type EdgeHubQUIC struct {
 	// WriteDeadline indicates write deadline (second)
 	// default 15
 	WriteDeadline int32 `json:"writeDeadline,omitempty"`
+	// MaxReadMessageSize indicates the max size in bytes of the messages read from the cloud,
+	// the larger messages are dropped, set to 0 for unlimited
+	// default 67108864 (64Mi)
+	MaxReadMessageSize int64 `json:"maxReadMessageSize,omitempty"`
+	// MaxWriteMessageSize indicates the max size in bytes of the messages written to the cloud,
+	// the larger messages are not sent, set to 0 for unlimited
+	// default 67108864 (64Mi)
+	MaxWriteMessageSize int64 `json:"maxWriteMessageSize,omitempty"`
+	// ReadBufferSize indicates the size in bytes of the receive window of the streams,
+	// set to 0 for the default of the protocol library
+	// default 6291456 (6Mi)
+	ReadBufferSize int32 `json:"readBufferSize,omitempty"`
+	// WriteBufferSize indicates the size in bytes of the write buffer of the connections,
+	// set to 0 for the default of the protocol library
+	// default 4096
+	WriteBufferSize int32 `json:"writeBufferSize,omitempty"`
 }
 
 // EdgeHubWebSocket indicates the websocket client config
@@ -920,6 +965,27 @@ type EdgeHubWebSocket struct {
 	// WriteDeadline indicates write deadline (second)
 	// default 15
 	WriteDeadline int32 `json:"writeDeadline,omitempty"`
+	// EnableCompression indicates whether to negotiate the permessage-deflate compression with
+	// CloudHub, which saves the traffic of the metered links, the messages are sent uncompressed
+	// if CloudHub does not enable it
+	// default false
+	EnableCompression bool `json:"enableCompression,omitempty"`
+	// MaxReadMessageSize indicates the max size in bytes of the messages read from the cloud,
+	// the larger messages are dropped, set to 0 for unlimited
+	// default 67108864 (64Mi)
+	MaxReadMessageSize int64 `json:"maxReadMessageSize,omitempty"`
+	// MaxWriteMessageSize indicates the max size in bytes of the messages written to the cloud,
+	// the larger messages are not sent, set to 0 for unlimited
+	// default 67108864 (64Mi)
+	MaxWriteMessageSize int64 `json:"maxWriteMessageSize,omitempty"`
+	// ReadBufferSize indicates the size in bytes of the read buffer of the connections,
+	// set to 0 for the default of the protocol library
+	// default 4096
+	ReadBufferSize int32 `json:"readBufferSize,omitempty"`
+	// WriteBufferSize indicates the size in bytes of the write buffer of the connections,
+	// set to 0 for the default of the protocol library
+	// default 4096
+	WriteBufferSize int32 `json:"writeBufferSize,omitempty"`
 }
 
 // EventBus indicates the event bus module config
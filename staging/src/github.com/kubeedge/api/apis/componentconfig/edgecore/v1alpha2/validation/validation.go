@@ -41,6 +41,20 @@ func ValidateEdgeCoreConfiguration(c *v1alpha2.EdgeCoreConfig) field.ErrorList {
 	allErrs = append(allErrs, ValidateModuleDeviceTwin(*c.Modules.DeviceTwin)...)
 	allErrs = append(allErrs, ValidateModuleDBTest(*c.Modules.DBTest)...)
 	allErrs = append(allErrs, ValidateModuleEdgeStream(*c.Modules.EdgeStream)...)
+	allErrs = append(allErrs, ValidateMessageTrace(c.MessageTrace)...)
+	return allErrs
+}
+
+// ValidateMessageTrace validates `m` and returns an errorList if it is invalid
+func ValidateMessageTrace(m *v1alpha2.MessageTrace) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if m == nil || !m.Enable {
+		return allErrs
+	}
+	if m.SampleRate <= 0 || m.SampleRate > 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("messageTrace.sampleRate"), m.SampleRate,
+			"SampleRate must be greater than 0 and not greater than 1"))
+	}
 	return allErrs
 }
 
@@ -98,6 +112,50 @@ func ValidateModuleEdgeHub(h v1alpha2.EdgeHub) field.ErrorList {
 			"MessageBurst must not be a negative number"))
 	}
 
+	if h.CertDuration < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("certDuration"), h.CertDuration,
+			"CertDuration must not be a negative number"))
+	}
+
+	switch h.KeyAlgorithm {
+	case "", "RSA", "ECDSA-P256", "ECDSA-P384", "ECDSA-P521", "Ed25519":
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("keyAlgorithm"), h.KeyAlgorithm,
+			[]string{"RSA", "ECDSA-P256", "ECDSA-P384", "ECDSA-P521", "Ed25519"}))
+	}
+
+	if h.NodeNameHeader != "" {
+		for _, m := range utilvalidation.IsValidHeaderName(h.NodeNameHeader) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("nodeNameHeader"), h.NodeNameHeader, m))
+		}
+	}
+	if ws := h.WebSocket; ws != nil {
+		allErrs = append(allErrs, validateMessageLimits(field.NewPath("websocket"), ws.MaxReadMessageSize,
+			ws.MaxWriteMessageSize, ws.ReadBufferSize, ws.WriteBufferSize)...)
+	}
+	if q := h.Quic; q != nil {
+		allErrs = append(allErrs, validateMessageLimits(field.NewPath("quic"), q.MaxReadMessageSize,
+			q.MaxWriteMessageSize, q.ReadBufferSize, q.WriteBufferSize)...)
+	}
+
+	return allErrs
+}
+
+// validateMessageLimits validates the max message sizes and the buffer sizes of the protocol
+func validateMessageLimits(fldPath *field.Path, maxRead, maxWrite int64, readBuffer, writeBuffer int32) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, m := range utilvalidation.IsValidMessageSize(maxRead) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxReadMessageSize"), maxRead, m))
+	}
+	for _, m := range utilvalidation.IsValidMessageSize(maxWrite) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxWriteMessageSize"), maxWrite, m))
+	}
+	for _, m := range utilvalidation.IsValidBufferSize(readBuffer) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("readBufferSize"), readBuffer, m))
+	}
+	for _, m := range utilvalidation.IsValidBufferSize(writeBuffer) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("writeBufferSize"), writeBuffer, m))
+	}
 	return allErrs
 }
 
@@ -203,6 +203,87 @@ func TestValidateModuleEdgeHub(t *testing.T) {
 			result: field.ErrorList{field.Invalid(field.NewPath("messageBurst"),
 				int32(-1), "MessageBurst must not be a negative number")},
 		},
+		{
+			name: "case6 CertDuration must not be a negative number",
+			input: v1alpha2.EdgeHub{
+				Enable: true,
+				WebSocket: &v1alpha2.EdgeHubWebSocket{
+					Enable: true,
+				},
+				Quic: &v1alpha2.EdgeHubQUIC{
+					Enable: false,
+				},
+				CertDuration: -1,
+			},
+			result: field.ErrorList{field.Invalid(field.NewPath("certDuration"),
+				int32(-1), "CertDuration must not be a negative number")},
+		},
+		{
+			name: "case7 KeyAlgorithm is not supported",
+			input: v1alpha2.EdgeHub{
+				Enable: true,
+				WebSocket: &v1alpha2.EdgeHubWebSocket{
+					Enable: true,
+				},
+				Quic: &v1alpha2.EdgeHubQUIC{
+					Enable: false,
+				},
+				KeyAlgorithm: "DSA",
+			},
+			result: field.ErrorList{field.NotSupported(field.NewPath("keyAlgorithm"), "DSA",
+				[]string{"RSA", "ECDSA-P256", "ECDSA-P384", "ECDSA-P521", "Ed25519"})},
+		},
+		{
+			name: "case8 Ed25519 key",
+			input: v1alpha2.EdgeHub{
+				Enable: true,
+				WebSocket: &v1alpha2.EdgeHubWebSocket{
+					Enable: true,
+				},
+				Quic: &v1alpha2.EdgeHubQUIC{
+					Enable: false,
+				},
+				KeyAlgorithm: "Ed25519",
+			},
+			result: field.ErrorList{},
+		},
+		{
+			name: "case9 NodeNameHeader is not a valid header name",
+			input: v1alpha2.EdgeHub{
+				Enable: true,
+				WebSocket: &v1alpha2.EdgeHubWebSocket{
+					Enable: true,
+				},
+				Quic: &v1alpha2.EdgeHubQUIC{
+					Enable: false,
+				},
+				NodeNameHeader: "NodeName:",
+			},
+			result: field.ErrorList{field.Invalid(field.NewPath("nodeNameHeader"), "NodeName:",
+				"must be a valid HTTP header name, (e.g. X-Node-Name)")},
+		},
+		{
+			name: "case10 invalid message limits",
+			input: v1alpha2.EdgeHub{
+				Enable: true,
+				WebSocket: &v1alpha2.EdgeHubWebSocket{
+					Enable:              true,
+					MaxWriteMessageSize: 1024,
+					WriteBufferSize:     128 << 20,
+				},
+				Quic: &v1alpha2.EdgeHubQUIC{
+					Enable:             false,
+					MaxReadMessageSize: 64 << 20,
+					ReadBufferSize:     6 << 20,
+				},
+			},
+			result: field.ErrorList{
+				field.Invalid(field.NewPath("websocket").Child("maxWriteMessageSize"), int64(1024),
+					"must be 0 for unlimited, or must be between 65536 and 1073741824, inclusive"),
+				field.Invalid(field.NewPath("websocket").Child("writeBufferSize"), int32(128<<20),
+					"must be 0 for the default, or must be between 1024 and 67108864, inclusive"),
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -396,3 +477,48 @@ func TestValidateModuleEdgeStream(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateMessageTrace(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    *v1alpha2.MessageTrace
+		expected field.ErrorList
+	}{
+		{
+			name:     "case1 not set",
+			input:    nil,
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case2 not enabled",
+			input: &v1alpha2.MessageTrace{
+				Enable:     false,
+				SampleRate: 0,
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case3 enabled",
+			input: &v1alpha2.MessageTrace{
+				Enable:     true,
+				SampleRate: 1,
+			},
+			expected: field.ErrorList{},
+		},
+		{
+			name: "case4 invalid sample rate",
+			input: &v1alpha2.MessageTrace{
+				Enable:     true,
+				SampleRate: 1.5,
+			},
+			expected: field.ErrorList{field.Invalid(field.NewPath("messageTrace.sampleRate"), 1.5,
+				"SampleRate must be greater than 0 and not greater than 1")},
+		},
+	}
+
+	for _, c := range cases {
+		if result := ValidateMessageTrace(c.input); !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("%v: expected %v, but got %v", c.name, c.expected, result)
+		}
+	}
+}
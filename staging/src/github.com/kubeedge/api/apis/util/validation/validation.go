@@ -19,6 +19,17 @@ package validation
 import (
 	"fmt"
 	"net"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+const (
+	// MinMessageSize and MaxMessageSize bound the max size of the messages of the connections
+	MinMessageSize = 64 << 10
+	MaxMessageSize = 1 << 30
+	// MinBufferSize and MaxBufferSize bound the size of the buffers of the connections
+	MinBufferSize = 1 << 10
+	MaxBufferSize = 64 << 20
 )
 
 // IsValidIP tests that the argument is a valid IP address.
@@ -42,3 +53,27 @@ func IsValidPortNum(port int) []string {
 func InclusiveRangeError(lo, hi int) string {
 	return fmt.Sprintf(`must be between %d and %d, inclusive`, lo, hi)
 }
+
+// IsValidHeaderName tests that the argument is a valid HTTP header name, which is a token of RFC 7230.
+func IsValidHeaderName(value string) []string {
+	if !httpguts.ValidHeaderFieldName(value) {
+		return []string{"must be a valid HTTP header name, (e.g. X-Node-Name)"}
+	}
+	return nil
+}
+
+// IsValidMessageSize tests that the argument is 0 for unlimited, or a max message size within the bounds.
+func IsValidMessageSize(size int64) []string {
+	if size == 0 || MinMessageSize <= size && size <= MaxMessageSize {
+		return nil
+	}
+	return []string{"must be 0 for unlimited, or " + InclusiveRangeError(MinMessageSize, MaxMessageSize)}
+}
+
+// IsValidBufferSize tests that the argument is 0 for the default of the protocol, or a buffer size within the bounds.
+func IsValidBufferSize(size int32) []string {
+	if size == 0 || MinBufferSize <= size && size <= MaxBufferSize {
+		return nil
+	}
+	return []string{"must be 0 for the default, or " + InclusiveRangeError(MinBufferSize, MaxBufferSize)}
+}
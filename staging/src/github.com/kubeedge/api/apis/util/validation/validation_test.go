@@ -94,3 +94,123 @@ func TestInclusiveRangeError(t *testing.T) {
 		t.Errorf("Expected %v while get %v", expect, result)
 	}
 }
+
+func TestIsValidHeaderName(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Header string
+		Expect bool
+	}{
+		{
+			Name:   "valid header",
+			Header: "NodeName",
+			Expect: true,
+		},
+		{
+			Name:   "valid header with symbols",
+			Header: "ssl_client_cert",
+			Expect: true,
+		},
+		{
+			Name:   "empty",
+			Header: "",
+			Expect: false,
+		},
+		{
+			Name:   "invalid space",
+			Header: "Node Name",
+			Expect: false,
+		},
+		{
+			Name:   "invalid colon",
+			Header: "NodeName:",
+			Expect: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			v := IsValidHeaderName(c.Header)
+			get := len(v) == 0
+			if get != c.Expect {
+				t.Errorf("Input %s Expect %v while get %v", c.Header, c.Expect, v)
+			}
+		})
+	}
+}
+
+func TestIsValidMessageSize(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Size   int64
+		Expect []string
+	}{
+		{
+			Name:   "unlimited",
+			Size:   0,
+			Expect: nil,
+		},
+		{
+			Name:   "valid size",
+			Size:   64 << 20,
+			Expect: nil,
+		},
+		{
+			Name:   "too small",
+			Size:   1024,
+			Expect: []string{"must be 0 for unlimited, or must be between 65536 and 1073741824, inclusive"},
+		},
+		{
+			Name:   "too large",
+			Size:   2 << 30,
+			Expect: []string{"must be 0 for unlimited, or must be between 65536 and 1073741824, inclusive"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			v := IsValidMessageSize(c.Size)
+			if !reflect.DeepEqual(v, c.Expect) {
+				t.Errorf("Input %d Expect %v while get %v", c.Size, c.Expect, v)
+			}
+		})
+	}
+}
+
+func TestIsValidBufferSize(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Size   int32
+		Expect []string
+	}{
+		{
+			Name:   "default",
+			Size:   0,
+			Expect: nil,
+		},
+		{
+			Name:   "valid size",
+			Size:   4096,
+			Expect: nil,
+		},
+		{
+			Name:   "negative",
+			Size:   -1,
+			Expect: []string{"must be 0 for the default, or must be between 1024 and 67108864, inclusive"},
+		},
+		{
+			Name:   "too large",
+			Size:   128 << 20,
+			Expect: []string{"must be 0 for the default, or must be between 1024 and 67108864, inclusive"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			v := IsValidBufferSize(c.Size)
+			if !reflect.DeepEqual(v, c.Expect) {
+				t.Errorf("Input %d Expect %v while get %v", c.Size, c.Expect, v)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ toolchain go1.24.2
 require (
 	github.com/cilium/ebpf v0.9.1 // indirect
 	github.com/spf13/pflag v1.0.6-0.20210604193023-d5e0c0615ace
+	golang.org/x/net v0.25.0
 	google.golang.org/grpc v1.63.0
 	google.golang.org/protobuf v1.35.2
 	k8s.io/api v0.30.7
@@ -123,7 +124,6 @@ require (
 	go.uber.org/zap v1.26.0 // indirect
 	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/exp v0.0.0-20220827204233-334a2380cb91 // indirect
-	golang.org/x/net v0.25.0 // indirect
 	golang.org/x/oauth2 v0.17.0 // indirect
 	golang.org/x/sync v0.10.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
@@ -2,9 +2,11 @@ package model
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -82,6 +84,12 @@ type MessageHeader struct {
 	// message type indicates the context type that delivers the message, such as channel, unixsocket, etc.
 	// if the value is empty, the channel context type will be used.
 	MessageType string `json:"type,omitempty"`
+	// the flag will be set on the messages which the receiver must acknowledge,
+	// they are sent again until they are acknowledged or expire.
+	Reliable bool `json:"reliable,omitempty"`
+	// the trace id correlating the messages of a request across the components,
+	// it is compatible with the trace ids of the W3C trace context and OpenTelemetry
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // BuildRouter sets route and resource operation in message
@@ -200,6 +208,38 @@ func (msg *Message) GetResourceVersion() string {
 	return msg.Header.ResourceVersion
 }
 
+// GetTraceID returns message trace id
+func (msg *Message) GetTraceID() string {
+	return msg.Header.TraceID
+}
+
+// SetTraceID sets message trace id
+func (msg *Message) SetTraceID(traceID string) *Message {
+	msg.Header.TraceID = traceID
+	return msg
+}
+
+// NewTraceID returns a new random trace id, which is 32 lowercase hex
+// characters as the trace ids of the W3C trace context
+func NewTraceID() string {
+	// the random uuid is never all zeros as its version bits are set
+	id := uuid.New()
+	return hex.EncodeToString(id[:])
+}
+
+// IsValidTraceID reports whether the trace id is 32 lowercase hex characters and not all zeros
+func IsValidTraceID(traceID string) bool {
+	if len(traceID) != 32 || traceID == strings.Repeat("0", 32) {
+		return false
+	}
+	for _, c := range traceID {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 // UpdateID returns message object updating its ID
 func (msg *Message) UpdateID() *Message {
 	msg.Header.ID = uuid.New().String()
@@ -237,11 +277,12 @@ func NewMessage(parentID string) *Message {
 }
 
 // Clone a message
-// only update message id
+// only update message id, the trace id is kept
 func (msg *Message) Clone(message *Message) *Message {
 	msgID := uuid.New().String()
 	return NewRawMessage().BuildHeader(msgID, message.GetParentID(), message.GetTimestamp()).
 		BuildRouter(message.GetSource(), message.GetGroup(), message.GetResource(), message.GetOperation()).
+		SetTraceID(message.GetTraceID()).
 		FillBody(message.GetContent())
 }
 
@@ -250,6 +291,7 @@ func (msg *Message) NewRespByMessage(message *Message, content interface{}) *Mes
 	return NewMessage(message.GetID()).SetRoute(message.GetSource(), message.GetGroup()).
 		SetResourceOperation(message.GetResource(), ResponseOperation).
 		SetType(message.GetType()).
+		SetTraceID(message.GetTraceID()).
 		FillBody(content)
 }
 
@@ -257,6 +299,7 @@ func (msg *Message) NewRespByMessage(message *Message, content interface{}) *Mes
 func NewErrorMessage(message *Message, errContent string) *Message {
 	return NewMessage(message.GetID()).
 		SetResourceOperation(message.Router.Resource, ResponseErrorOperation).
+		SetTraceID(message.GetTraceID()).
 		FillBody(errContent)
 }
 